@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/proc"
+)
+
+var onCrash = flag.String("on-crash", "", "A shell command to run when the sub process crashes, given the crash as json on stdin and in RECOVER_* environment variables")
+var onCrashWebhook = flag.String("on-crash-webhook", "", "An http url to POST the crash as json to when the sub process crashes")
+var onCrashTail = flag.Int("on-crash-tail", 20, "A number of lines of recent sub process output to include in crash notifications")
+var onCrashTimeout = flag.Int("on-crash-timeout", 5000, "A time in milliseconds to wait for each crash notification")
+
+// crashTail is the recent sub process output, reset each time the sub process starts.
+var crashTail = &tailWriter{}
+
+// Crash is a crash notification payload.
+type Crash struct {
+	Command   []string  `json:"command"`
+	PID       int       `json:"pid"`
+	ExitCode  int       `json:"exitCode"`
+	Reason    string    `json:"reason"`
+	Restarts  int       `json:"restarts"`
+	Elapsed   float64   `json:"elapsed"`
+	Timestamp time.Time `json:"timestamp"`
+	Output    []string  `json:"output"`
+}
+
+// newCrash returns a crash notification payload for a sub process exit.
+func newCrash(command []string, exit proc.Exit) Crash {
+	return Crash{
+		Command:   command,
+		PID:       exit.PID,
+		ExitCode:  exit.ExitCode,
+		Reason:    exit.Reason,
+		Restarts:  exit.Restarts,
+		Elapsed:   logger.Milliseconds(exit.Elapsed),
+		Timestamp: time.Now().UTC(),
+		Output:    crashTail.Lines(),
+	}
+}
+
+// notifyCrash runs the `-on-crash` command and posts to the `-on-crash-webhook` url, if set.
+// Failures are logged and otherwise ignored.
+func notifyCrash(crash Crash) {
+	if *onCrash == "" && *onCrashWebhook == "" {
+		return
+	}
+	body, err := json.Marshal(crash)
+	if err != nil {
+		log.SyncError(err)
+		return
+	}
+	if *onCrash != "" {
+		if err := runCrashCommand(crash, body); err != nil {
+			log.SyncErrorf("on crash command failed: %v", err)
+		}
+	}
+	if *onCrashWebhook != "" {
+		if err := postCrashWebhook(body); err != nil {
+			log.SyncErrorf("on crash webhook failed: %v", err)
+		}
+	}
+}
+
+func runCrashCommand(crash Crash, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*onCrashTimeout)*time.Millisecond)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", *onCrash)
+	cmd.Env = append(os.Environ(),
+		"RECOVER_PID="+strconv.Itoa(crash.PID),
+		"RECOVER_EXIT_CODE="+strconv.Itoa(crash.ExitCode),
+		"RECOVER_REASON="+crash.Reason,
+		"RECOVER_RESTARTS="+strconv.Itoa(crash.Restarts),
+	)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func postCrashWebhook(body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(*onCrashTimeout)*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, *onCrashWebhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// tailWriter keeps the last `-on-crash-tail` lines written to it.
+type tailWriter struct {
+	sync.Mutex
+	lines   []string
+	partial string
+}
+
+// Write implements io.Writer.
+func (tw *tailWriter) Write(contents []byte) (int, error) {
+	tw.Lock()
+	defer tw.Unlock()
+
+	pieces := strings.Split(tw.partial+string(contents), "\n")
+	tw.partial = pieces[len(pieces)-1]
+	tw.lines = append(tw.lines, pieces[:len(pieces)-1]...)
+	tw.trim()
+	return len(contents), nil
+}
+
+// Lines returns the recent lines, including any partial line.
+func (tw *tailWriter) Lines() []string {
+	tw.Lock()
+	defer tw.Unlock()
+
+	lines := append([]string{}, tw.lines...)
+	if tw.partial != "" {
+		lines = append(lines, tw.partial)
+	}
+	if max := *onCrashTail; max >= 0 && len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	return lines
+}
+
+// Reset clears the recent lines.
+func (tw *tailWriter) Reset() {
+	tw.Lock()
+	defer tw.Unlock()
+	tw.lines = nil
+	tw.partial = ""
+}
+
+func (tw *tailWriter) trim() {
+	if max := *onCrashTail; max >= 0 && len(tw.lines) > max {
+		tw.lines = append([]string{}, tw.lines[len(tw.lines)-max:]...)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/proc"
+)
+
+func TestTailWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := *onCrashTail
+	*onCrashTail = 2
+	defer func() { *onCrashTail = previous }()
+
+	tw := &tailWriter{}
+	tw.Write([]byte("one\ntw"))
+	assert.Equal([]string{"one", "tw"}, tw.Lines())
+
+	tw.Write([]byte("o\nthree\nfour"))
+	assert.Equal([]string{"three", "four"}, tw.Lines())
+	assert.Len(tw.lines, 2)
+
+	tw.Reset()
+	assert.Empty(tw.Lines())
+}
+
+func TestNewCrash(t *testing.T) {
+	assert := assert.New(t)
+
+	crashTail.Reset()
+	defer crashTail.Reset()
+	crashTail.Write([]byte("panic: oh no\n"))
+
+	crash := newCrash([]string{"app", "-v"}, proc.Exit{
+		PID:      123,
+		ExitCode: 2,
+		Reason:   proc.ReasonExited,
+		Restarts: 1,
+		Elapsed:  1500 * time.Millisecond,
+	})
+	assert.Equal([]string{"app", "-v"}, crash.Command)
+	assert.Equal(123, crash.PID)
+	assert.Equal(2, crash.ExitCode)
+	assert.Equal(proc.ReasonExited, crash.Reason)
+	assert.Equal(1, crash.Restarts)
+	assert.Equal(1500.0, crash.Elapsed)
+	assert.False(crash.Timestamp.IsZero())
+	assert.Equal([]string{"panic: oh no"}, crash.Output)
+}
+
+func TestNotifyCrash(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-crash")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "crash")
+
+	var posted Crash
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		contentType = req.Header.Get("Content-Type")
+		json.NewDecoder(req.Body).Decode(&posted)
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	previousCommand, previousWebhook := *onCrash, *onCrashWebhook
+	*onCrash = `printf "$RECOVER_PID $RECOVER_EXIT_CODE $RECOVER_REASON $RECOVER_RESTARTS " > ` + output + ` && cat >> ` + output
+	*onCrashWebhook = server.URL
+	defer func() { *onCrash, *onCrashWebhook = previousCommand, previousWebhook }()
+
+	crash := Crash{Command: []string{"app"}, PID: 123, ExitCode: 2, Reason: proc.ReasonExited, Restarts: 1}
+	notifyCrash(crash)
+
+	contents, err := ioutil.ReadFile(output)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(contents), "123 2 "+proc.ReasonExited+" 1 {"))
+	var fromStdin Crash
+	assert.Nil(json.Unmarshal(contents[strings.Index(string(contents), "{"):], &fromStdin))
+	assert.Equal(crash.PID, fromStdin.PID)
+
+	assert.Equal("application/json", contentType)
+	assert.Equal(crash.PID, posted.PID)
+	assert.Equal(crash.Command, posted.Command)
+}
+
+func TestPostCrashWebhookStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	previous := *onCrashWebhook
+	*onCrashWebhook = server.URL
+	defer func() { *onCrashWebhook = previous }()
+
+	err := postCrashWebhook([]byte("{}"))
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "500")
+}
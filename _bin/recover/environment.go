@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/env"
+)
+
+var envFiles stringsFlag
+var envOverrides stringsFlag
+
+func init() {
+	flag.Var(&envFiles, "env-file", "A path to a file of KEY=VALUE lines to add to the sub process environment; may be repeated, later files win")
+	flag.Var(&envOverrides, "env", "A KEY=VALUE to set in the sub process environment, overriding env files; may be repeated")
+}
+
+// stringsFlag is a repeatable string flag.
+type stringsFlag []string
+
+// String implements flag.Value.
+func (sf *stringsFlag) String() string {
+	return strings.Join(*sf, ",")
+}
+
+// Set implements flag.Value.
+func (sf *stringsFlag) Set(value string) error {
+	*sf = append(*sf, value)
+	return nil
+}
+
+// subEnvironment returns the sub process environment: the supervisor environment,
+// then the `-env-file` files in order, then the `-env` overrides.
+func subEnvironment() ([]string, error) {
+	vars := env.NewVarsFromEnvironment()
+	for _, path := range envFiles {
+		fileVars, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		vars = vars.Union(fileVars)
+	}
+	for _, override := range envOverrides {
+		key, value, err := parseEnvLine(override)
+		if err != nil {
+			return nil, err
+		}
+		if key == "" {
+			return nil, fmt.Errorf("invalid -env %q, expected KEY=VALUE", override)
+		}
+		vars.Set(key, value)
+	}
+	return vars.Raw(), nil
+}
+
+// readEnvFile reads a file of KEY=VALUE lines. Blank lines and lines starting with `#` are skipped,
+// a leading `export ` is ignored, and values may be single or double quoted.
+func readEnvFile(path string) (env.Vars, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	vars := env.NewVars()
+	scanner := bufio.NewScanner(file)
+	var lineNumber int
+	for scanner.Scan() {
+		lineNumber++
+		key, value, err := parseEnvLine(scanner.Text())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, lineNumber, err)
+		}
+		if key != "" {
+			vars.Set(key, value)
+		}
+	}
+	return vars, scanner.Err()
+}
+
+// parseEnvLine parses a KEY=VALUE line, returning an empty key for blank and comment lines.
+func parseEnvLine(line string) (key, value string, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", "", nil
+	}
+	line = strings.TrimPrefix(line, "export ")
+	parts := strings.SplitN(line, "=", 2)
+	if len(parts) < 2 || strings.TrimSpace(parts[0]) == "" {
+		return "", "", fmt.Errorf("invalid line %q, expected KEY=VALUE", line)
+	}
+	key = strings.TrimSpace(parts[0])
+	value = strings.TrimSpace(parts[1])
+	if len(value) > 1 {
+		switch value[0] {
+		case '"':
+			if unquoted, unquoteErr := strconv.Unquote(value); unquoteErr == nil {
+				value = unquoted
+			}
+		case '\'':
+			if value[len(value)-1] == '\'' {
+				value = value[1 : len(value)-1]
+			}
+		}
+	}
+	return key, value, nil
+}
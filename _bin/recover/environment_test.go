@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type parseEnvLineTestCase struct {
+	Line  string
+	Key   string
+	Value string
+	Err   bool
+}
+
+func TestParseEnvLine(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []parseEnvLineTestCase{
+		{Line: "", Key: ""},
+		{Line: "   ", Key: ""},
+		{Line: "# a comment", Key: ""},
+		{Line: "  # an indented comment", Key: ""},
+		{Line: "FOO=bar", Key: "FOO", Value: "bar"},
+		{Line: " FOO = bar ", Key: "FOO", Value: "bar"},
+		{Line: "export FOO=bar", Key: "FOO", Value: "bar"},
+		{Line: "FOO=", Key: "FOO", Value: ""},
+		{Line: "FOO=bar=baz", Key: "FOO", Value: "bar=baz"},
+		{Line: `FOO="bar baz"`, Key: "FOO", Value: "bar baz"},
+		{Line: `FOO="bar\nbaz"`, Key: "FOO", Value: "bar\nbaz"},
+		{Line: `FOO="bar \"baz\""`, Key: "FOO", Value: `bar "baz"`},
+		{Line: `FOO="bar`, Key: "FOO", Value: `"bar`},
+		{Line: `FOO='bar baz'`, Key: "FOO", Value: "bar baz"},
+		{Line: `FOO='bar\nbaz'`, Key: "FOO", Value: `bar\nbaz`},
+		{Line: `FOO='bar`, Key: "FOO", Value: `'bar`},
+		{Line: `FOO='`, Key: "FOO", Value: `'`},
+		{Line: "FOO", Err: true},
+		{Line: "=bar", Err: true},
+		{Line: " =bar", Err: true},
+	}
+
+	for _, testCase := range testCases {
+		key, value, err := parseEnvLine(testCase.Line)
+		if testCase.Err {
+			assert.NotNil(err, testCase.Line)
+			continue
+		}
+		assert.Nil(err, testCase.Line)
+		assert.Equal(testCase.Key, key, testCase.Line)
+		assert.Equal(testCase.Value, value, testCase.Line)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-env")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, ".env")
+	assert.Nil(ioutil.WriteFile(path, []byte("# comment\n\nexport FOO=bar\nBUZZ='fuzz'\n"), 0644))
+	vars, err := readEnvFile(path)
+	assert.Nil(err)
+	assert.Equal("bar", vars.String("FOO"))
+	assert.Equal("fuzz", vars.String("BUZZ"))
+
+	assert.Nil(ioutil.WriteFile(path, []byte("FOO=bar\nBUZZ\n"), 0644))
+	_, err = readEnvFile(path)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), path+":2:")
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/blend/go-sdk/proc"
+)
+
+var healthURL = flag.String("health-url", "", "An http url to probe periodically, restarting the sub process if it does not return a 2xx status")
+var healthCmd = flag.String("health-cmd", "", "A shell command to run periodically, restarting the sub process if it exits nonzero")
+var healthInterval = flag.Int("health-interval", 5000, "A time in milliseconds between health checks")
+var healthTimeout = flag.Int("health-timeout", 1000, "A time in milliseconds to wait for a health check")
+var healthThreshold = flag.Int("health-threshold", 3, "A number of consecutive failed health checks before restarting the sub process")
+var healthDelay = flag.Int("health-delay", 0, "A time in milliseconds to wait after starting the sub process before health checking")
+
+// healthCheck returns the health check for `-health-cmd` or `-health-url`, if either is set.
+func healthCheck() proc.HealthCheck {
+	if *healthCmd != "" {
+		return proc.CommandHealthCheck(*healthCmd)
+	}
+	if *healthURL != "" {
+		return proc.HTTPHealthCheck(*healthURL)
+	}
+	return nil
+}
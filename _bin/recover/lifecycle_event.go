@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Lifecycle event flags.
+const (
+	// FlagStart is the flag for sub process start events.
+	FlagStart logger.Flag = "recover.start"
+	// FlagExit is the flag for sub process exit events.
+	FlagExit logger.Flag = "recover.exit"
+	// FlagRestart is the flag for sub process restart events.
+	FlagRestart logger.Flag = "recover.restart"
+	// FlagBackoff is the flag for events when the supervisor waits before a restart.
+	FlagBackoff logger.Flag = "recover.backoff"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event        = &LifecycleEvent{}
+	_ logger.TextWritable = &LifecycleEvent{}
+	_ logger.JSONWritable = &LifecycleEvent{}
+)
+
+// NewLifecycleEvent returns a new lifecycle event.
+func NewLifecycleEvent(flag logger.Flag) *LifecycleEvent {
+	return &LifecycleEvent{
+		EventMeta: logger.NewEventMeta(flag),
+	}
+}
+
+// LifecycleEvent is an event for a change in the sub process lifecycle.
+type LifecycleEvent struct {
+	*logger.EventMeta
+
+	pid      int
+	exitCode int
+	elapsed  time.Duration
+	restarts int
+	backoff  time.Duration
+	reason   string
+}
+
+// WithPID sets the sub process pid.
+func (e *LifecycleEvent) WithPID(pid int) *LifecycleEvent {
+	e.pid = pid
+	return e
+}
+
+// PID returns the sub process pid.
+func (e LifecycleEvent) PID() int {
+	return e.pid
+}
+
+// WithExitCode sets the sub process exit code.
+func (e *LifecycleEvent) WithExitCode(exitCode int) *LifecycleEvent {
+	e.exitCode = exitCode
+	return e
+}
+
+// ExitCode returns the sub process exit code; it is only set for exit events.
+func (e LifecycleEvent) ExitCode() int {
+	return e.exitCode
+}
+
+// WithElapsed sets how long the sub process ran.
+func (e *LifecycleEvent) WithElapsed(elapsed time.Duration) *LifecycleEvent {
+	e.elapsed = elapsed
+	return e
+}
+
+// Elapsed returns how long the sub process ran; it is only set for exit events.
+func (e LifecycleEvent) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+// WithRestarts sets the consecutive restart count.
+func (e *LifecycleEvent) WithRestarts(restarts int) *LifecycleEvent {
+	e.restarts = restarts
+	return e
+}
+
+// Restarts returns the consecutive restart count.
+func (e LifecycleEvent) Restarts() int {
+	return e.restarts
+}
+
+// WithBackoff sets the time waited before a restart.
+func (e *LifecycleEvent) WithBackoff(backoff time.Duration) *LifecycleEvent {
+	e.backoff = backoff
+	return e
+}
+
+// Backoff returns the time waited before a restart; it is only set for backoff events.
+func (e LifecycleEvent) Backoff() time.Duration {
+	return e.backoff
+}
+
+// WithReason sets the reason for an exit or restart, e.g. `proc.ReasonUnhealthy`.
+func (e *LifecycleEvent) WithReason(reason string) *LifecycleEvent {
+	e.reason = reason
+	return e
+}
+
+// Reason returns the reason for an exit or restart.
+func (e LifecycleEvent) Reason() string {
+	return e.reason
+}
+
+// WriteText implements logger.TextWritable.
+func (e LifecycleEvent) WriteText(tf logger.TextFormatter, buf *bytes.Buffer) {
+	switch e.Flag() {
+	case FlagStart:
+		buf.WriteString(fmt.Sprintf("pid: %d restarts: %d", e.pid, e.restarts))
+	case FlagExit:
+		buf.WriteString(fmt.Sprintf("pid: %d code: %d elapsed: %v reason: %s", e.pid, e.exitCode, e.elapsed, e.reason))
+	case FlagRestart:
+		buf.WriteString(fmt.Sprintf("restarts: %d reason: %s", e.restarts, e.reason))
+	case FlagBackoff:
+		buf.WriteString(fmt.Sprintf("waiting: %v restarts: %d", e.backoff, e.restarts))
+	}
+}
+
+// WriteJSON implements logger.JSONWritable.
+func (e LifecycleEvent) WriteJSON() logger.JSONObj {
+	obj := logger.JSONObj{
+		"restarts": e.restarts,
+	}
+	switch e.Flag() {
+	case FlagStart:
+		obj["pid"] = e.pid
+	case FlagExit:
+		obj["pid"] = e.pid
+		obj["exitCode"] = e.exitCode
+		obj[logger.JSONFieldElapsed] = logger.Milliseconds(e.elapsed)
+		obj["reason"] = e.reason
+	case FlagRestart:
+		obj["reason"] = e.reason
+	case FlagBackoff:
+		obj["backoff"] = logger.Milliseconds(e.backoff)
+	}
+	return obj
+}
@@ -4,17 +4,34 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/proc"
 )
 
 var verbose = flag.Bool("verbose", false, "Print verbose output")
+var jsonOutput = flag.Bool("json", false, "Print lifecycle events and verbose output as json")
 var delay = flag.Int("delay", 0, "A time in milliseconds to wait before starting the sub process")
 var wait = flag.Int("wait", 0, "A time in milliseconds to wait between restarting the sub process on exit")
+var backoff = flag.Int("backoff", 0, "An initial time in milliseconds to wait before restarting the sub process, doubled on each consecutive restart (overrides -wait)")
+var backoffMax = flag.Int("backoff-max", 0, "A maximum time in milliseconds to wait between restarts when backing off; a sub process that runs longer than this resets the backoff")
+var restartOnCodes = flag.String("restart-on-codes", "", "A csv of sub process exit codes to restart on, exiting on any other code (-1 is killed by a signal)")
+var exitOnCodes = flag.String("exit-on-codes", "", "A csv of sub process exit codes to exit on, restarting on any other code; by default recover exits on 0 and restarts otherwise")
+var grace = flag.Int("grace", 10000, "A time in milliseconds to wait for the sub process to exit after forwarding SIGINT or SIGTERM before killing it")
+var killChildren = flag.Bool("kill-children", true, "Start the sub process in its own process group and signal the whole group when stopping it, so processes it spawned don't outlive it")
+var maxRestarts = flag.Int("max-restarts", 0, "A maximum number of restarts before exiting with a failure, 0 restarts forever; the count resets when the sub process runs longer than -backoff-max")
+
+// log is the supervisor logger.
+var log = logger.None()
 
 func main() {
 	flag.Parse()
+	log = newLogger()
 
 	subCommand := flag.Args()
 	if len(subCommand) == 0 {
@@ -26,113 +43,196 @@ func main() {
 		fatal(err)
 	}
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1, syscall.SIGUSR2)
 
-	if err := runLoop(quit, pwd, subCommand...); err != nil {
+	restartSchedule, err := parseRestartSchedule()
+	if err != nil {
 		fatal(err)
 	}
 
-	os.Exit(0)
-}
+	lock, err := lockPidfile()
+	if err != nil {
+		fatal(err)
+	}
 
-func createSub(pwd string, subCommand ...string) (*exec.Cmd, error) {
-	bin := subCommand[0]
+	supervisor, err := newSupervisor(pwd, subCommand...)
+	if err != nil {
+		lock.Release()
+		fatal(err)
+	}
+	if err := supervisor.Start(); err != nil {
+		lock.Release()
+		fatal(err)
+	}
+	if err := serveStatus(supervisor); err != nil {
+		supervisor.Stop()
+		lock.Release()
+		fatal(err)
+	}
 
-	binPath, err := exec.LookPath(bin)
+	done := make(chan struct{})
+	watchChanges(done, supervisor.Reload)
+	scheduleRestarts(done, restartSchedule, func() {
+		supervisor.ReloadWithReason(proc.ReasonScheduled)
+	})
+	go forwardSignals(signals, supervisor)
+
+	exitCode, err := supervisor.ExitCode()
+	close(done)
+	subOutputs.Close()
+	closeCollector()
+	removeChildPidfile()
+	lock.Release()
 	if err != nil {
-		return nil, err
+		fatal(err)
 	}
+	os.Exit(exitCode)
+}
 
-	sub := exec.Command(binPath, subCommand[1:]...)
-	sub.Env = os.Environ()
-	sub.Dir = pwd
-	sub.Stdout = os.Stdout
-	sub.Stderr = os.Stderr
+// newSupervisor returns the sub process supervisor configured from the flags.
+func newSupervisor(pwd string, subCommand ...string) (*proc.Supervisor, error) {
+	restartPolicy, err := newRestartPolicy(*restartOnCodes, *exitOnCodes)
+	if err != nil {
+		return nil, err
+	}
+	environment, err := subEnvironment()
+	if err != nil {
+		return nil, err
+	}
+	if err := compileRestartPattern(); err != nil {
+		return nil, err
+	}
+	var supervisor *proc.Supervisor
+	subOutputs, err = newOutputs(func(line string) {
+		log.SyncDebugf("sub process output matched -restart-on-pattern: %s", line)
+		supervisor.Fail(proc.ReasonMatched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	collector, err = newCollector()
+	if err != nil {
+		return nil, err
+	}
 
-	return sub, nil
+	supervisor = proc.NewSupervisor(subCommand...).
+		WithDir(pwd).
+		WithEnv(environment).
+		WithStdout(subOutputs.stdout).
+		WithStderr(subOutputs.stderr).
+		WithLogger(log).
+		WithRestartPolicy(restartPolicy).
+		WithDelay(milliseconds(*delay)).
+		WithRestartWait(milliseconds(*wait)).
+		WithBackoff(milliseconds(*backoff)).
+		WithBackoffMax(milliseconds(*backoffMax)).
+		WithMaxRestarts(*maxRestarts).
+		WithGracePeriod(milliseconds(*grace)).
+		WithKillChildren(*killChildren).
+		WithHealthCheck(healthCheck()).
+		WithHealthInterval(milliseconds(*healthInterval)).
+		WithHealthTimeout(milliseconds(*healthTimeout)).
+		WithHealthThreshold(*healthThreshold).
+		WithHealthDelay(milliseconds(*healthDelay)).
+		WithOnRestart(func(restarts int, reason string) {
+			crashTail.Reset()
+			collector.Increment(MetricRestarts, "reason:"+reason)
+			log.SyncTrigger(NewLifecycleEvent(FlagRestart).WithRestarts(restarts).WithReason(reason))
+		}).
+		WithOnStart(func(pid, restarts int) {
+			writeChildPidfile(pid)
+			collector.Increment(MetricStarts)
+			log.SyncTrigger(NewLifecycleEvent(FlagStart).WithPID(pid).WithRestarts(restarts))
+		}).
+		WithOnExit(func(exit proc.Exit) {
+			subOutputs.Flush()
+			collector.Increment(MetricExits, exitTags(exit)...)
+			log.SyncTrigger(NewLifecycleEvent(FlagExit).
+				WithPID(exit.PID).
+				WithExitCode(exit.ExitCode).
+				WithElapsed(exit.Elapsed).
+				WithRestarts(exit.Restarts).
+				WithReason(exit.Reason))
+		}).
+		WithOnCrash(func(exit proc.Exit) {
+			collector.Increment(MetricCrashes, exitTags(exit)...)
+			notifyCrash(newCrash(subCommand, exit))
+		}).
+		WithOnBackoff(func(backoff time.Duration, restarts int) {
+			log.SyncTrigger(NewLifecycleEvent(FlagBackoff).WithBackoff(backoff).WithRestarts(restarts))
+		})
+	return supervisor, nil
 }
 
-func runLoop(quit chan os.Signal, pwd string, subCommand ...string) error {
-	if delay != nil && *delay > 0 {
-		delayMillis := time.Duration(*delay) * time.Millisecond
-		verbosef("delaying %v before starting", delayMillis)
-		alarm := time.After(delayMillis)
-		select {
-		case <-alarm:
-			break
-		case <-quit:
-			verbosef("received SIGINT during delay, exiting")
-			return nil
+// newRestartPolicy returns a restart policy from csvs of exit codes.
+func newRestartPolicy(restartOn, exitOn string) (proc.RestartPolicy, error) {
+	if restartOn != "" && exitOn != "" {
+		return nil, fmt.Errorf("only one of -restart-on-codes and -exit-on-codes may be set")
+	}
+	if restartOn != "" {
+		codes, err := parseCodes(restartOn)
+		if err != nil {
+			return nil, err
 		}
+		return proc.RestartOnCodes(codes...), nil
 	}
-
-	var sub *exec.Cmd
-	var err error
-	var didQuit bool
-	var abort chan struct{}
-	var aborted chan struct{}
-
-	for {
-		abort = make(chan struct{})
-		aborted = make(chan struct{})
-
-		sub, err = createSub(pwd, subCommand...)
+	if exitOn != "" {
+		codes, err := parseCodes(exitOn)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		return proc.ExitOnCodes(codes...), nil
+	}
+	return proc.RestartOnFailure, nil
+}
 
-		if err := sub.Start(); err != nil {
-			return err
+func parseCodes(codes string) ([]int, error) {
+	var output []int
+	for _, code := range strings.Split(codes, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid exit code %q", code)
 		}
+		output = append(output, value)
+	}
+	return output, nil
+}
 
-		// kick off monitor
-		go func() {
-			select {
-			case <-quit:
-				verbosef("received SIGINT while sub process is running, killing sub process")
-				didQuit = true
-				sub.Process.Kill()
-				return
-			case <-abort:
-				close(aborted)
-				return
-			}
-		}()
-
-		// wait for the sub process to exit
-		if err := sub.Wait(); err != nil {
-			verbosef("sub process exit: %v", err)
-		}
+// newLogger returns the supervisor logger, which writes lifecycle events, and debug messages if `-verbose` is set.
+func newLogger() *logger.Logger {
+	flags := []logger.Flag{FlagStart, FlagExit, FlagRestart, FlagBackoff, logger.Error, logger.Fatal}
+	if *verbose {
+		flags = append(flags, logger.Debug)
+	}
+	if *jsonOutput {
+		return logger.New(flags...).WithWriter(logger.NewJSONWriterStdout())
+	}
+	return logger.New(flags...).WithHeading("recover").WithWriter(logger.NewTextWriterStdout())
+}
 
-		if didQuit {
-			return nil
+// forwardSignals stops the supervisor on termination signals, forwarding other signals to the sub process.
+func forwardSignals(signals chan os.Signal, supervisor *proc.Supervisor) {
+	for sig := range signals {
+		if isTermination(sig) {
+			log.SyncDebugf("received %v, stopping sub process", sig)
+			supervisor.StopWithSignal(sig)
+			return
 		}
-
-		// abort the monitor
-		close(abort)
-		// wait for monitor to return
-		<-aborted
-
-		if wait != nil && *wait > 0 {
-			waitMillis := time.Duration(*wait) * time.Millisecond
-			verbosef("waiting %v before restart", waitMillis)
-			alarm := time.After(waitMillis)
-			select {
-			case <-alarm:
-				continue
-			case <-quit:
-				verbosef("received SIGINT during wait, exiting")
-				return nil
-			}
+		log.SyncDebugf("forwarding %v to sub process", sig)
+		if err := supervisor.Signal(sig); err != nil {
+			log.SyncDebugf("signaling sub process: %v", err)
 		}
 	}
 }
 
-func verbosef(format string, args ...interface{}) {
-	if verbose != nil && *verbose {
-		fmt.Fprintf(os.Stdout, "recover: "+format+"\n", args...)
-	}
+// isTermination returns if a signal should stop the supervisor, rather than just be forwarded to the sub process.
+func isTermination(sig os.Signal) bool {
+	return sig == os.Interrupt || sig == syscall.SIGTERM
+}
+
+func milliseconds(value int) time.Duration {
+	return time.Duration(value) * time.Millisecond
 }
 
 func fatalf(format string, args ...interface{}) {
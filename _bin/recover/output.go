@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var outputFile = flag.String("output-file", "", "A path to also write the sub process stdout and stderr to, rotated by size")
+var outputMaxSize = flag.Int64("output-max-size", 10<<20, "A size in bytes after which the output file is rotated to a timestamped file")
+var outputMaxFiles = flag.Int("output-max-files", 5, "A number of rotated output files to keep, 0 keeps all of them")
+var outputPrefix = flag.Bool("output-prefix", false, "Prefix each line written to the output file with a timestamp and the stream name")
+
+// rotatedTimeFormat is the timestamp format of rotated output file suffixes.
+const rotatedTimeFormat = "20060102T150405.000000000Z"
+
+// maxPartialLine is the size partial lines are buffered up to before they're handled as a complete line,
+// so output without newlines doesn't grow the buffers without bound.
+const maxPartialLine = 64 << 10
+
+// subOutputs are the writers for the sub process stdout and stderr, kept across restarts.
+var subOutputs = outputs{stdout: io.MultiWriter(os.Stdout, crashTail), stderr: io.MultiWriter(os.Stderr, crashTail)}
+
+// outputs are the sub process output writers, and the writers to flush when the sub process exits.
+type outputs struct {
+	stdout  io.Writer
+	stderr  io.Writer
+	flushes []func() error
+	closer  io.Closer
+}
+
+// Flush flushes any partial lines.
+func (o outputs) Flush() {
+	for _, flush := range o.flushes {
+		flush()
+	}
+}
+
+// Close flushes and closes the output file, if any.
+func (o outputs) Close() error {
+	o.Flush()
+	if o.closer != nil {
+		return o.closer.Close()
+	}
+	return nil
+}
+
+// newOutputs returns the sub process output writers, which pass output through, keep the
+// recent output for crash notifications, call onMatch for lines matching the `-restart-on-pattern`,
+// and, if `-output-file` is set, tee it to a rotating file.
+func newOutputs(onMatch func(line string)) (outputs, error) {
+	var o outputs
+	stdout := []io.Writer{os.Stdout, crashTail}
+	stderr := []io.Writer{os.Stderr, crashTail}
+
+	if restartPattern != nil {
+		stdoutMatches := &patternWriter{pattern: restartPattern, onMatch: onMatch}
+		stderrMatches := &patternWriter{pattern: restartPattern, onMatch: onMatch}
+		o.flushes = append(o.flushes, stdoutMatches.Flush, stderrMatches.Flush)
+		stdout = append(stdout, stdoutMatches)
+		stderr = append(stderr, stderrMatches)
+	}
+
+	if *outputFile != "" {
+		file, err := newRotatingFile(*outputFile, *outputMaxSize, *outputMaxFiles)
+		if err != nil {
+			return outputs{}, err
+		}
+		o.closer = file
+		if *outputPrefix {
+			stdoutLines := &prefixWriter{output: file, stream: "stdout"}
+			stderrLines := &prefixWriter{output: file, stream: "stderr"}
+			o.flushes = append(o.flushes, stdoutLines.Flush, stderrLines.Flush)
+			stdout = append(stdout, stdoutLines)
+			stderr = append(stderr, stderrLines)
+		} else {
+			stdout = append(stdout, file)
+			stderr = append(stderr, file)
+		}
+	}
+
+	o.stdout = io.MultiWriter(stdout...)
+	o.stderr = io.MultiWriter(stderr...)
+	return o, nil
+}
+
+// newRotatingFile opens a rotating file, appending to it if it exists.
+func newRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// rotatingFile is a file that is renamed with a timestamp suffix once it reaches a max size.
+type rotatingFile struct {
+	sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+
+	file *os.File
+	size int64
+}
+
+// Write implements io.Writer.
+func (rf *rotatingFile) Write(contents []byte) (int, error) {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.maxSize > 0 && rf.size > 0 && rf.size+int64(len(contents)) > rf.maxSize {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	written, err := rf.file.Write(contents)
+	rf.size += int64(written)
+	return written, err
+}
+
+// Close closes the file.
+func (rf *rotatingFile) Close() error {
+	rf.Lock()
+	defer rf.Unlock()
+	return rf.file.Close()
+}
+
+func (rf *rotatingFile) open() error {
+	file, err := os.OpenFile(rf.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	rf.file = file
+	rf.size = info.Size()
+	return nil
+}
+
+// rotate renames the current file with a timestamp suffix, opens a new file and removes old rotated files.
+func (rf *rotatingFile) rotate() error {
+	if err := rf.file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(rf.path, rf.path+"."+time.Now().UTC().Format(rotatedTimeFormat)); err != nil {
+		return err
+	}
+	if err := rf.open(); err != nil {
+		return err
+	}
+	if rf.maxFiles <= 0 {
+		return nil
+	}
+	rotated, err := rf.rotated()
+	if err != nil {
+		return err
+	}
+	for len(rotated) > rf.maxFiles {
+		os.Remove(rotated[0])
+		rotated = rotated[1:]
+	}
+	return nil
+}
+
+// rotated returns the rotated files, oldest first.
+// Other files that share the path as a prefix are not rotated files, and are left alone.
+func (rf *rotatingFile) rotated() ([]string, error) {
+	candidates, err := filepath.Glob(rf.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var rotated []string
+	for _, candidate := range candidates {
+		suffix := strings.TrimPrefix(candidate, rf.path+".")
+		if len(suffix) != len(rotatedTimeFormat) {
+			continue
+		}
+		if _, err := time.Parse(rotatedTimeFormat, suffix); err == nil {
+			rotated = append(rotated, candidate)
+		}
+	}
+	// the timestamp suffixes sort chronologically.
+	sort.Strings(rotated)
+	return rotated, nil
+}
+
+// prefixWriter writes complete lines prefixed with a timestamp and stream name, buffering partial lines.
+// Partial lines longer than `maxPartialLine` are written as a line of their own.
+type prefixWriter struct {
+	sync.Mutex
+	output  io.Writer
+	stream  string
+	partial bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (pw *prefixWriter) Write(contents []byte) (int, error) {
+	pw.Lock()
+	defer pw.Unlock()
+
+	pw.partial.Write(contents)
+	for {
+		line, err := pw.partial.ReadString('\n')
+		if err != nil {
+			// put back the partial line.
+			pw.partial.WriteString(line)
+			break
+		}
+		if _, err := io.WriteString(pw.output, pw.prefix()+line); err != nil {
+			return 0, err
+		}
+	}
+	if pw.partial.Len() >= maxPartialLine {
+		if err := pw.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return len(contents), nil
+}
+
+// Flush writes any partial line.
+func (pw *prefixWriter) Flush() error {
+	pw.Lock()
+	defer pw.Unlock()
+	return pw.flush()
+}
+
+func (pw *prefixWriter) flush() error {
+	if pw.partial.Len() == 0 {
+		return nil
+	}
+	line := pw.partial.String()
+	pw.partial.Reset()
+	if !strings.HasSuffix(line, "\n") {
+		line = line + "\n"
+	}
+	_, err := io.WriteString(pw.output, pw.prefix()+line)
+	return err
+}
+
+func (pw *prefixWriter) prefix() string {
+	return time.Now().UTC().Format(time.RFC3339Nano) + " " + pw.stream + " "
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type rotatingFileTestCase struct {
+	Name     string
+	Existing string
+	MaxSize  int64
+	MaxFiles int
+	Writes   []string
+	Current  string
+	Rotated  []string
+}
+
+func TestRotatingFile(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []rotatingFileTestCase{
+		{
+			Name:     "under max size",
+			MaxSize:  10,
+			MaxFiles: 2,
+			Writes:   []string{"foo\n", "bar\n"},
+			Current:  "foo\nbar\n",
+		},
+		{
+			Name:     "rotates before exceeding max size",
+			MaxSize:  10,
+			MaxFiles: 2,
+			Writes:   []string{"foo\n", "bar\n", "baz\n"},
+			Current:  "baz\n",
+			Rotated:  []string{"foo\nbar\n"},
+		},
+		{
+			Name:     "keeps max files",
+			MaxSize:  4,
+			MaxFiles: 2,
+			Writes:   []string{"foo\n", "bar\n", "baz\n", "buz\n"},
+			Current:  "buz\n",
+			Rotated:  []string{"bar\n", "baz\n"},
+		},
+		{
+			Name:     "keeps all files",
+			MaxSize:  4,
+			MaxFiles: 0,
+			Writes:   []string{"foo\n", "bar\n", "baz\n"},
+			Current:  "baz\n",
+			Rotated:  []string{"foo\n", "bar\n"},
+		},
+		{
+			Name:     "writes larger than max size",
+			MaxSize:  2,
+			MaxFiles: 2,
+			Writes:   []string{"foo\n", "bar\n"},
+			Current:  "bar\n",
+			Rotated:  []string{"foo\n"},
+		},
+		{
+			Name:     "appends to an existing file",
+			Existing: "foo\n",
+			MaxSize:  10,
+			MaxFiles: 2,
+			Writes:   []string{"bar\n", "baz\n"},
+			Current:  "baz\n",
+			Rotated:  []string{"foo\nbar\n"},
+		},
+		{
+			Name:     "no max size",
+			MaxSize:  0,
+			MaxFiles: 2,
+			Writes:   []string{"foo\n", "bar\n", "baz\n"},
+			Current:  "foo\nbar\nbaz\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		dir, err := ioutil.TempDir("", "recover-output")
+		assert.Nil(err)
+
+		path := filepath.Join(dir, "output.log")
+		if testCase.Existing != "" {
+			assert.Nil(ioutil.WriteFile(path, []byte(testCase.Existing), 0644))
+		}
+		file, err := newRotatingFile(path, testCase.MaxSize, testCase.MaxFiles)
+		assert.Nil(err, testCase.Name)
+		for _, write := range testCase.Writes {
+			written, err := file.Write([]byte(write))
+			assert.Nil(err, testCase.Name)
+			assert.Equal(len(write), written, testCase.Name)
+		}
+		assert.Nil(file.Close(), testCase.Name)
+
+		current, err := ioutil.ReadFile(path)
+		assert.Nil(err, testCase.Name)
+		assert.Equal(testCase.Current, string(current), testCase.Name)
+
+		rotatedPaths, err := filepath.Glob(path + ".*")
+		assert.Nil(err)
+		sort.Strings(rotatedPaths)
+		var rotated []string
+		for _, rotatedPath := range rotatedPaths {
+			contents, err := ioutil.ReadFile(rotatedPath)
+			assert.Nil(err, testCase.Name)
+			rotated = append(rotated, string(contents))
+		}
+		assert.Equal(testCase.Rotated, rotated, testCase.Name)
+
+		os.RemoveAll(dir)
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	pw := &prefixWriter{output: buffer, stream: "stderr"}
+	written, err := pw.Write([]byte("foo\nba"))
+	assert.Nil(err)
+	assert.Equal(6, written)
+	_, err = pw.Write([]byte("r\nbaz"))
+	assert.Nil(err)
+	assert.Nil(pw.Flush())
+	assert.Nil(pw.Flush())
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(lines, 3)
+	for index, expected := range []string{"foo", "bar", "baz"} {
+		assert.True(strings.HasSuffix(lines[index], " stderr "+expected), lines[index])
+	}
+}
+
+func TestRotatingFileIgnoresOtherFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-output")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "output.log")
+	others := []string{path + ".bak", path + ".1", path + ".20060102T150405Z", path + ".gz"}
+	for _, other := range others {
+		assert.Nil(ioutil.WriteFile(other, []byte("other\n"), 0644))
+	}
+
+	file, err := newRotatingFile(path, 4, 1)
+	assert.Nil(err)
+	for _, write := range []string{"foo\n", "bar\n", "baz\n"} {
+		_, err = file.Write([]byte(write))
+		assert.Nil(err)
+	}
+	assert.Nil(file.Close())
+
+	rotated, err := file.rotated()
+	assert.Nil(err)
+	assert.Len(rotated, 1)
+	contents, err := ioutil.ReadFile(rotated[0])
+	assert.Nil(err)
+	assert.Equal("bar\n", string(contents))
+
+	for _, other := range others {
+		_, err = os.Stat(other)
+		assert.Nil(err, other)
+	}
+}
+
+func TestPrefixWriterLongLine(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	pw := &prefixWriter{output: buffer, stream: "stdout"}
+	long := strings.Repeat("x", maxPartialLine)
+	_, err := pw.Write([]byte(long[:maxPartialLine/2]))
+	assert.Nil(err)
+	assert.Zero(buffer.Len())
+
+	_, err = pw.Write([]byte(long[maxPartialLine/2:] + "yy"))
+	assert.Nil(err)
+	assert.Zero(pw.partial.Len())
+
+	_, err = pw.Write([]byte("z\n"))
+	assert.Nil(err)
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.Len(lines, 2)
+	assert.True(strings.HasSuffix(lines[0], " stdout "+long+"yy"))
+	assert.True(strings.HasSuffix(lines[1], " stdout z"))
+}
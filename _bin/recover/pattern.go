@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var restartOnPattern = flag.String("restart-on-pattern", "", "A regular expression matched against each line of sub process output, restarting the sub process as a crash when a line matches")
+
+// restartPattern is the compiled `-restart-on-pattern`, if set.
+var restartPattern *regexp.Regexp
+
+// compileRestartPattern compiles the `-restart-on-pattern`, if set.
+func compileRestartPattern() (err error) {
+	if *restartOnPattern == "" {
+		return nil
+	}
+	restartPattern, err = regexp.Compile(*restartOnPattern)
+	return
+}
+
+// patternWriter calls onMatch for lines matching a pattern, buffering partial lines.
+// Partial lines longer than `maxPartialLine` are matched as a line of their own.
+type patternWriter struct {
+	sync.Mutex
+	pattern *regexp.Regexp
+	onMatch func(line string)
+	partial string
+}
+
+// Write implements io.Writer.
+func (pw *patternWriter) Write(contents []byte) (int, error) {
+	pw.Lock()
+	defer pw.Unlock()
+
+	lines := strings.Split(pw.partial+string(contents), "\n")
+	pw.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		pw.match(line)
+	}
+	if len(pw.partial) >= maxPartialLine {
+		pw.match(pw.partial)
+		pw.partial = ""
+	}
+	return len(contents), nil
+}
+
+// Flush matches and clears any partial line.
+func (pw *patternWriter) Flush() error {
+	pw.Lock()
+	defer pw.Unlock()
+	if pw.partial != "" {
+		pw.match(pw.partial)
+		pw.partial = ""
+	}
+	return nil
+}
+
+func (pw *patternWriter) match(line string) {
+	if pw.pattern.MatchString(line) {
+		pw.onMatch(line)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestPatternWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	var matched []string
+	pw := &patternWriter{pattern: regexp.MustCompile("panic"), onMatch: func(line string) {
+		matched = append(matched, line)
+	}}
+
+	written, err := pw.Write([]byte("foo\npan"))
+	assert.Nil(err)
+	assert.Equal(7, written)
+	assert.Empty(matched)
+
+	_, err = pw.Write([]byte("ic: oh no\nbar\nfatal panic"))
+	assert.Nil(err)
+	assert.Equal([]string{"panic: oh no"}, matched)
+
+	assert.Nil(pw.Flush())
+	assert.Nil(pw.Flush())
+	assert.Equal([]string{"panic: oh no", "fatal panic"}, matched)
+}
+
+func TestPatternWriterLongLine(t *testing.T) {
+	assert := assert.New(t)
+
+	var matched []string
+	pw := &patternWriter{pattern: regexp.MustCompile("panic"), onMatch: func(line string) {
+		matched = append(matched, line)
+	}}
+
+	long := strings.Repeat("x", maxPartialLine) + "panic"
+	_, err := pw.Write([]byte(long))
+	assert.Nil(err)
+	assert.Empty(pw.partial)
+	assert.Equal([]string{long}, matched)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+var pidfile = flag.String("pidfile", "", "A path to write the supervisor pid to; recover refuses to start if another running instance holds a lock on it")
+var childPidfile = flag.String("child-pidfile", "", "A path to write the current sub process pid to, updated on each restart")
+
+// lockedPidfile is a pidfile held open with an exclusive lock for the life of the supervisor.
+type lockedPidfile struct {
+	file *os.File
+}
+
+// lockPidfile creates the `-pidfile`, if set, locks it and writes the supervisor pid to it.
+// It returns an error if another instance holds the lock.
+func lockPidfile() (*lockedPidfile, error) {
+	if *pidfile == "" {
+		return &lockedPidfile{}, nil
+	}
+	for {
+		file, err := os.OpenFile(*pidfile, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return nil, err
+		}
+		if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			file.Close()
+			if err == syscall.EWOULDBLOCK {
+				return nil, fmt.Errorf("another instance is running with pid %s (%s is locked)", readPid(*pidfile), *pidfile)
+			}
+			return nil, err
+		}
+		// the instance we opened the file from may have removed it before releasing the lock,
+		// in which case we hold the lock on a file no one else can see; try again with the current one.
+		if current, err := isCurrentFile(file); err != nil {
+			file.Close()
+			return nil, err
+		} else if !current {
+			file.Close()
+			continue
+		}
+		if err := file.Truncate(0); err != nil {
+			file.Close()
+			return nil, err
+		}
+		if _, err := file.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0); err != nil {
+			file.Close()
+			return nil, err
+		}
+		return &lockedPidfile{file: file}, nil
+	}
+}
+
+// isCurrentFile returns if an open file is still the file at its path.
+func isCurrentFile(file *os.File) (bool, error) {
+	opened, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	current, err := os.Stat(file.Name())
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(opened, current), nil
+}
+
+// Release removes the pidfile and releases the lock.
+func (lp *lockedPidfile) Release() {
+	if lp == nil || lp.file == nil {
+		return
+	}
+	// instances waiting on the lock check the file is still current once they hold it (see `lockPidfile`),
+	// so removing it before unlocking doesn't let two instances run at once.
+	os.Remove(lp.file.Name())
+	lp.file.Close()
+	lp.file = nil
+}
+
+// writeChildPidfile writes the sub process pid to the `-child-pidfile`, if set.
+func writeChildPidfile(pid int) {
+	if *childPidfile == "" {
+		return
+	}
+	if err := ioutil.WriteFile(*childPidfile, []byte(strconv.Itoa(pid)+"\n"), 0644); err != nil {
+		log.SyncErrorf("writing child pidfile: %v", err)
+	}
+}
+
+// removeChildPidfile removes the `-child-pidfile`, if set.
+func removeChildPidfile() {
+	if *childPidfile != "" {
+		os.Remove(*childPidfile)
+	}
+}
+
+func readPid(path string) string {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil || strings.TrimSpace(string(contents)) == "" {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(contents))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLockPidfile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-pidfile")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recover.pid")
+	previous := *pidfile
+	*pidfile = path
+	defer func() { *pidfile = previous }()
+
+	locked, err := lockPidfile()
+	assert.Nil(err)
+	assert.Equal(strconv.Itoa(os.Getpid()), readPid(path))
+
+	// flock locks are per open file, so a second lock in this process is refused like another instance's.
+	_, err = lockPidfile()
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "another instance is running with pid "+strconv.Itoa(os.Getpid()))
+
+	locked.Release()
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+	locked.Release()
+
+	relocked, err := lockPidfile()
+	assert.Nil(err)
+	relocked.Release()
+}
+
+func TestLockPidfileStale(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-pidfile")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recover.pid")
+	previous := *pidfile
+	*pidfile = path
+	defer func() { *pidfile = previous }()
+
+	// a pidfile left behind by an instance that didn't release it is taken over.
+	assert.Nil(ioutil.WriteFile(path, []byte("123456789\n"), 0644))
+	locked, err := lockPidfile()
+	assert.Nil(err)
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(strconv.Itoa(os.Getpid()), strings.TrimSpace(string(contents)))
+	locked.Release()
+}
+
+func TestIsCurrentFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-pidfile")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recover.pid")
+	file, err := os.Create(path)
+	assert.Nil(err)
+	defer file.Close()
+
+	current, err := isCurrentFile(file)
+	assert.Nil(err)
+	assert.True(current)
+
+	// the file an instance waiting on the lock opened was removed, and another instance created a new one.
+	assert.Nil(os.Remove(path))
+	current, err = isCurrentFile(file)
+	assert.Nil(err)
+	assert.False(current)
+
+	assert.Nil(ioutil.WriteFile(path, nil, 0644))
+	current, err = isCurrentFile(file)
+	assert.Nil(err)
+	assert.False(current)
+}
+
+func TestLockedPidfileReleaseUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := *pidfile
+	*pidfile = ""
+	defer func() { *pidfile = previous }()
+
+	locked, err := lockPidfile()
+	assert.Nil(err)
+	locked.Release()
+
+	var nilLocked *lockedPidfile
+	nilLocked.Release()
+}
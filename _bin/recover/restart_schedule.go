@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+)
+
+var restartCron = flag.String("restart-cron", "", "A cron schedule in UTC, e.g. \"0 4 * * *\", to gracefully restart the sub process on")
+
+// parseRestartSchedule returns the `-restart-cron` schedule, or nil if it is not set.
+func parseRestartSchedule() (cron.Schedule, error) {
+	if *restartCron == "" {
+		return nil, nil
+	}
+	return cron.ParseString(*restartCron)
+}
+
+// scheduleRestarts calls restart each time the schedule fires, until done is closed.
+func scheduleRestarts(done chan struct{}, schedule cron.Schedule, restart func()) {
+	if schedule == nil {
+		return
+	}
+	go func() {
+		var last *time.Time
+		for {
+			next := schedule.GetNextRunTime(last)
+			if next == nil {
+				return
+			}
+			log.SyncDebugf("next scheduled restart at %v", next.Format(time.RFC3339))
+			timer := time.NewTimer(time.Until(*next))
+			select {
+			case <-done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			log.SyncDebugf("scheduled restart")
+			restart()
+			last = next
+		}
+	}()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/proc"
+	"github.com/blend/go-sdk/stats"
+	"github.com/blend/go-sdk/stats/dogstatsd"
+)
+
+var statusAddr = flag.String("status-addr", "", "An address, e.g. 127.0.0.1:8089, to serve the supervisor status as json on")
+var statsdAddr = flag.String("statsd-addr", "", "A statsd address to send start, exit and restart counters to; defaults to STATS_ADDR")
+var statsdNamespace = flag.String("statsd-namespace", "recover", "A prefix for statsd metric names")
+var statsdTags = flag.String("statsd-tags", "", "A csv of key:value tags to add to statsd metrics; defaults to STATS_TAGS")
+
+// Metric names.
+const (
+	MetricStarts   = "starts"
+	MetricExits    = "exits"
+	MetricRestarts = "restarts"
+	MetricCrashes  = "crashes"
+)
+
+// supervisorStarted is when the supervisor started.
+var supervisorStarted = time.Now().UTC()
+
+// collector is the statsd collector, a no-op collector unless statsd is configured.
+var collector stats.Collector = stats.NopCollector{}
+
+// newCollector returns a statsd collector from the `-statsd-*` flags and the common stats environment,
+// or a no-op collector if no address is configured.
+func newCollector() (stats.Collector, error) {
+	cfg, err := stats.NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if *statsdAddr != "" {
+		cfg.Addr = *statsdAddr
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = *statsdNamespace
+	}
+	if tags := splitCSV(*statsdTags); len(tags) > 0 {
+		cfg.DefaultTags = tags
+	}
+	return dogstatsd.NewCollectorFromConfig(cfg)
+}
+
+// closeCollector flushes and closes the collector, if it is closable.
+func closeCollector() {
+	if closer, ok := collector.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// serveStatus serves the supervisor status as json on the `-status-addr`, if set.
+func serveStatus(supervisor *proc.Supervisor) error {
+	if *statusAddr == "" {
+		return nil
+	}
+	listener, err := net.Listen("tcp", *statusAddr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(rw).Encode(newStatusResponse(supervisor.Status()))
+	})
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			log.SyncError(err)
+		}
+	}()
+	log.SyncDebugf("serving status on %s", listener.Addr())
+	return nil
+}
+
+// newStatusResponse returns the status endpoint response for a supervisor status.
+func newStatusResponse(status proc.Status) map[string]interface{} {
+	response := map[string]interface{}{
+		"state":            status.State,
+		"restarts":         status.Restarts,
+		"totalRestarts":    status.TotalRestarts,
+		"supervisorUptime": logger.Milliseconds(time.Since(supervisorStarted)),
+	}
+	if status.PID != 0 {
+		response["pid"] = status.PID
+		response["uptime"] = logger.Milliseconds(time.Since(status.Started))
+	}
+	if status.LastExit != nil {
+		response["lastExitCode"] = status.LastExit.ExitCode
+		response["lastExitReason"] = status.LastExit.Reason
+	}
+	return response
+}
+
+// exitTags returns the statsd tags for a sub process exit.
+func exitTags(exit proc.Exit) []string {
+	return []string{"reason:" + exit.Reason, "exit_code:" + strconv.Itoa(exit.ExitCode)}
+}
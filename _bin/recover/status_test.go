@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/proc"
+	"github.com/blend/go-sdk/stats"
+)
+
+func TestNewStatusResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	response := newStatusResponse(proc.Status{State: proc.StateBackoff, Restarts: 2, TotalRestarts: 5})
+	assert.Equal(proc.StateBackoff, response["state"])
+	assert.Equal(2, response["restarts"])
+	assert.Equal(5, response["totalRestarts"])
+	assert.NotNil(response["supervisorUptime"])
+	assert.Nil(response["pid"])
+	assert.Nil(response["uptime"])
+	assert.Nil(response["lastExitCode"])
+
+	response = newStatusResponse(proc.Status{
+		State:    proc.StateRunning,
+		PID:      123,
+		Started:  time.Now().UTC().Add(-time.Second),
+		LastExit: &proc.Exit{ExitCode: 1, Reason: proc.ReasonExited},
+	})
+	assert.Equal(123, response["pid"])
+	assert.True(response["uptime"].(float64) >= 1000)
+	assert.Equal(1, response["lastExitCode"])
+	assert.Equal(proc.ReasonExited, response["lastExitReason"])
+}
+
+func TestExitTags(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"reason:unhealthy", "exit_code:137"}, exitTags(proc.Exit{Reason: "unhealthy", ExitCode: 137}))
+}
+
+func TestNewCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	env.Env().Set("STATS_ADDR", "")
+	defer env.Env().Restore("STATS_ADDR")
+
+	unset, err := newCollector()
+	assert.Nil(err)
+	assert.Equal(stats.NopCollector{}, unset)
+
+	previous := *statsdAddr
+	*statsdAddr = "127.0.0.1:8125"
+	defer func() { *statsdAddr = previous }()
+
+	set, err := newCollector()
+	assert.Nil(err)
+	assert.NotEqual(stats.NopCollector{}, set)
+	if closer, ok := set.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+func TestServeStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(serveStatus(nil), "serving status is skipped without an address")
+
+	// reserve a free port for the status server.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	previous := *statusAddr
+	*statusAddr = addr
+	defer func() { *statusAddr = previous }()
+
+	assert.Nil(serveStatus(proc.NewSupervisor("true")))
+
+	res, err := http.Get("http://" + addr + "/")
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("application/json; charset=utf-8", res.Header.Get("Content-Type"))
+
+	var response map[string]interface{}
+	assert.Nil(json.NewDecoder(res.Body).Decode(&response))
+	assert.Equal(proc.StateStopped, response["state"])
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var watch = flag.String("watch", "", "A csv of paths to watch, restarting the sub process immediately when files under them change")
+var watchIgnore = flag.String("watch-ignore", ".git,*.swp,*~,.#*", "A csv of globs for files and directories to ignore when watching, matched against both the base name and the path")
+var watchInterval = flag.Int("watch-interval", 500, "A time in milliseconds between polling the watched paths for changes")
+var watchDebounce = flag.Int("watch-debounce", 250, "A time in milliseconds the watched paths must be unchanged for before restarting")
+
+// fileState is the state of a watched file used to detect changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchChanges starts watching the `-watch` paths by polling, calling onChange each time files
+// have changed and then stayed unchanged for the debounce time. Watching stops when done is closed.
+func watchChanges(done chan struct{}, onChange func()) {
+	if *watch == "" {
+		return
+	}
+
+	roots := splitCSV(*watch)
+	ignores := splitCSV(*watchIgnore)
+	go func() {
+		ticker := time.NewTicker(time.Duration(*watchInterval) * time.Millisecond)
+		defer ticker.Stop()
+
+		last := snapshot(roots, ignores)
+		var lastChange time.Time
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			current := snapshot(roots, ignores)
+			if !sameSnapshot(last, current) {
+				last = current
+				lastChange = time.Now()
+				log.SyncDebugf("watched files changed")
+				continue
+			}
+			if !lastChange.IsZero() && time.Since(lastChange) >= time.Duration(*watchDebounce)*time.Millisecond {
+				lastChange = time.Time{}
+				onChange()
+			}
+		}
+	}()
+}
+
+// snapshot returns the state of the files under the roots.
+func snapshot(roots, ignores []string) map[string]fileState {
+	files := map[string]fileState{}
+	for _, root := range roots {
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if path != root && isIgnored(path, ignores) {
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !info.IsDir() {
+				files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+func sameSnapshot(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, state := range a {
+		if other, ok := b[path]; !ok || !other.modTime.Equal(state.modTime) || other.size != state.size {
+			return false
+		}
+	}
+	return true
+}
+
+// isIgnored returns if a path matches any of the ignore globs, by base name or by path.
+func isIgnored(path string, ignores []string) bool {
+	base := filepath.Base(path)
+	for _, glob := range ignores {
+		if matched, _ := filepath.Match(glob, base); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(glob, path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func splitCSV(value string) []string {
+	var output []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			output = append(output, part)
+		}
+	}
+	return output
+}
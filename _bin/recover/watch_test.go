@@ -0,0 +1,91 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSplitCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(splitCSV(""))
+	assert.Equal([]string{"foo", "bar"}, splitCSV(" foo, ,bar,"))
+}
+
+func TestIsIgnored(t *testing.T) {
+	assert := assert.New(t)
+
+	ignores := []string{".git", "*.swp", "build/*"}
+	assert.True(isIgnored("src/.git", ignores))
+	assert.True(isIgnored("src/main.go.swp", ignores))
+	assert.True(isIgnored("build/output", ignores))
+	assert.False(isIgnored("src/main.go", ignores))
+	assert.False(isIgnored("src/build/output", ignores))
+}
+
+func TestSnapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-watch")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0644))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "main.go.swp"), []byte("swap"), 0644))
+
+	ignores := []string{".git", "*.swp"}
+	first := snapshot([]string{dir, filepath.Join(dir, "missing")}, ignores)
+	assert.Len(first, 1)
+	assert.True(sameSnapshot(first, snapshot([]string{dir}, ignores)))
+
+	// changes to ignored files don't change the snapshot.
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("other ref"), 0644))
+	assert.True(sameSnapshot(first, snapshot([]string{dir}, ignores)))
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	assert.False(sameSnapshot(first, snapshot([]string{dir}, ignores)))
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "util.go"), []byte("package main"), 0644))
+	assert.False(sameSnapshot(first, snapshot([]string{dir}, ignores)))
+}
+
+func TestWatchChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "recover-watch")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	previousWatch, previousInterval, previousDebounce := *watch, *watchInterval, *watchDebounce
+	*watch, *watchInterval, *watchDebounce = dir, 10, 50
+	defer func() { *watch, *watchInterval, *watchDebounce = previousWatch, previousInterval, previousDebounce }()
+
+	done := make(chan struct{})
+	defer close(done)
+	changed := make(chan struct{}, 10)
+	watchChanges(done, func() { changed <- struct{}{} })
+
+	// wait for the initial snapshot before changing anything.
+	time.Sleep(50 * time.Millisecond)
+	for x := 0; x < 3; x++ {
+		assert.Nil(ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"+string(make([]byte, x))), 0644))
+		time.Sleep(15 * time.Millisecond)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("watched changes should call on change")
+	}
+
+	// a burst of changes is debounced to a single call.
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(changed)
+}
@@ -164,6 +164,15 @@ func (a *Assertions) Equal(expected interface{}, actual interface{}, userMessage
 	}
 }
 
+// EqualDiff asserts that two objects are deeply equal, and shows a diff between them if they aren't.
+// `Equal` also shows a diff for structs, maps, slices and multi-line strings; use EqualDiff to always show one.
+func (a *Assertions) EqualDiff(expected interface{}, actual interface{}, userMessageComponents ...interface{}) {
+	a.assertion()
+	if didFail, message := shouldBeEqualDiff(expected, actual); didFail {
+		failNow(a.output, a.t, message, userMessageComponents...)
+	}
+}
+
 // ReferenceEqual asserts that two objects are the same reference in memory.
 func (a *Assertions) ReferenceEqual(expected interface{}, actual interface{}, userMessageComponents ...interface{}) {
 	a.assertion()
@@ -241,6 +250,23 @@ func (a *Assertions) InTimeDelta(t1, t2 time.Time, delta time.Duration, userMess
 	}
 }
 
+// Eventually asserts that a condition becomes true within a timeout, checking it every interval.
+// Use it in place of sleeping for a fixed time before asserting on something asynchronous.
+func (a *Assertions) Eventually(condition func() bool, timeout, interval time.Duration, userMessageComponents ...interface{}) {
+	a.assertion()
+	if didFail, message := shouldEventually(condition, timeout, interval); didFail {
+		failNow(a.output, a.t, message, userMessageComponents...)
+	}
+}
+
+// Never asserts that a condition stays false for a timeout, checking it every interval.
+func (a *Assertions) Never(condition func() bool, timeout, interval time.Duration, userMessageComponents ...interface{}) {
+	a.assertion()
+	if didFail, message := shouldNever(condition, timeout, interval); didFail {
+		failNow(a.output, a.t, message, userMessageComponents...)
+	}
+}
+
 // FileExists asserts that a file exists at a given filepath on disk.
 func (a *Assertions) FileExists(filepath string, userMessageComponents ...interface{}) {
 	a.assertion()
@@ -468,6 +494,16 @@ func (o *Optional) Equal(expected interface{}, actual interface{}, userMessageCo
 	return true
 }
 
+// EqualDiff asserts that two objects are equal, and shows a diff between them if they aren't.
+func (o *Optional) EqualDiff(expected interface{}, actual interface{}, userMessageComponents ...interface{}) bool {
+	o.assertion()
+	if didFail, message := shouldBeEqualDiff(expected, actual); didFail {
+		fail(o.output, o.t, prefixOptional(message), userMessageComponents...)
+		return false
+	}
+	return true
+}
+
 // ReferenceEqual asserts that two objects are the same underlying reference in memory.
 func (o *Optional) ReferenceEqual(expected interface{}, actual interface{}, userMessageComponents ...interface{}) bool {
 	o.assertion()
@@ -558,6 +594,26 @@ func (o *Optional) InTimeDelta(a, b time.Time, delta time.Duration, userMessageC
 	return true
 }
 
+// Eventually asserts that a condition becomes true within a timeout, checking it every interval.
+func (o *Optional) Eventually(condition func() bool, timeout, interval time.Duration, userMessageComponents ...interface{}) bool {
+	o.assertion()
+	if didFail, message := shouldEventually(condition, timeout, interval); didFail {
+		fail(o.output, o.t, prefixOptional(message), userMessageComponents...)
+		return false
+	}
+	return true
+}
+
+// Never asserts that a condition stays false for a timeout, checking it every interval.
+func (o *Optional) Never(condition func() bool, timeout, interval time.Duration, userMessageComponents ...interface{}) bool {
+	o.assertion()
+	if didFail, message := shouldNever(condition, timeout, interval); didFail {
+		fail(o.output, o.t, prefixOptional(message), userMessageComponents...)
+		return false
+	}
+	return true
+}
+
 // FileExists asserts that a file exists on disk at a given filepath.
 func (o *Optional) FileExists(filepath string, userMessageComponents ...interface{}) bool {
 	o.assertion()
@@ -814,6 +870,13 @@ func shouldBeEqual(expected, actual interface{}) (bool, string) {
 	return false, EMPTY
 }
 
+func shouldBeEqualDiff(expected, actual interface{}) (bool, string) {
+	if !areEqual(expected, actual) {
+		return true, diffMessage(expected, actual, "Objects should be equal")
+	}
+	return false, EMPTY
+}
+
 func shouldBeReferenceEqual(expected, actual interface{}) (bool, string) {
 	if !areReferenceEqual(expected, actual) {
 		return true, referenceEqualMessage(expected, actual)
@@ -920,6 +983,20 @@ func shouldBeInTimeDelta(from, to time.Time, delta time.Duration) (bool, string)
 	return false, EMPTY
 }
 
+func shouldEventually(condition func() bool, timeout, interval time.Duration) (bool, string) {
+	if _, ok := poll(condition, timeout, interval); !ok {
+		return true, fmt.Sprintf("Condition should have become true within %v", timeout)
+	}
+	return false, EMPTY
+}
+
+func shouldNever(condition func() bool, timeout, interval time.Duration) (bool, string) {
+	if elapsed, ok := poll(condition, timeout, interval); ok {
+		return true, fmt.Sprintf("Condition should have stayed false for %v, but became true after %v", timeout, elapsed.Round(time.Millisecond))
+	}
+	return false, EMPTY
+}
+
 func shouldContain(corpus, subString string) (bool, string) {
 	if !strings.Contains(corpus, subString) {
 		message := fmt.Sprintf("`%s` should contain `%s`", corpus, subString)
@@ -1144,6 +1221,9 @@ func notEqualMessage(expected, actual interface{}) string {
 }
 
 func equalMessage(expected, actual interface{}) string {
+	if shouldDiff(expected, actual) {
+		return diffMessage(expected, actual, "Objects should be equal")
+	}
 	return shouldBeMultipleMessage(expected, actual, "Objects should be equal")
 }
 
@@ -1226,6 +1306,33 @@ func areEqual(expected, actual interface{}) bool {
 	return reflect.DeepEqual(expected, actual)
 }
 
+// poll checks a condition immediately, every interval, and once more at the timeout, returning
+// how long it took to become true, or false if it didn't.
+func poll(condition func() bool, timeout, interval time.Duration) (time.Duration, bool) {
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	started := time.Now()
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if condition() {
+			return time.Since(started), true
+		}
+		select {
+		case <-deadline.C:
+			if condition() {
+				return time.Since(started), true
+			}
+			return time.Since(started), false
+		case <-ticker.C:
+		}
+	}
+}
+
 func callerInfo() []string {
 	pc := uintptr(0)
 	file := ""
@@ -3,6 +3,7 @@ package assert
 import (
 	"bytes"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -781,6 +782,58 @@ func TestAssertInTimeDelta(t *testing.T) {
 	}
 }
 
+func TestAssertEventually(t *testing.T) {
+	var calls int32
+	err := safeExec(func() {
+		New(nil).Eventually(func() bool { return atomic.AddInt32(&calls, 1) > 2 }, time.Second, time.Millisecond) // should be ok
+	})
+	if err != nil {
+		t.Errorf("should not have produced a panic")
+		t.FailNow()
+	}
+	if atomic.LoadInt32(&calls) != 3 {
+		t.Errorf("should have stopped checking once the condition was true, checked %d times", calls)
+		t.FailNow()
+	}
+
+	output := bytes.NewBuffer(nil)
+	err = safeExec(func() {
+		New(nil).WithOutput(output).Eventually(func() bool { return false }, 5*time.Millisecond, time.Millisecond)
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "Condition should have become true within 5ms") {
+		t.Errorf("Should have written output on failure, got: %s", output.String())
+		t.FailNow()
+	}
+}
+
+func TestAssertNever(t *testing.T) {
+	err := safeExec(func() {
+		New(nil).Never(func() bool { return false }, 5*time.Millisecond, time.Millisecond) // should be ok
+	})
+	if err != nil {
+		t.Errorf("should not have produced a panic")
+		t.FailNow()
+	}
+
+	var calls int32
+	output := bytes.NewBuffer(nil)
+	err = safeExec(func() {
+		New(nil).WithOutput(output).Never(func() bool { return atomic.AddInt32(&calls, 1) > 2 }, time.Second, time.Millisecond)
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "Condition should have stayed false for 1s") {
+		t.Errorf("Should have written output on failure, got: %s", output.String())
+		t.FailNow()
+	}
+}
+
 func TestAssertContains(t *testing.T) {
 	err := safeExec(func() {
 		New(nil).Contains("foo bar", "foo") // should be ok
@@ -1460,3 +1513,37 @@ func TestAssertNonFatalPanicEqual(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestAssertNonFatalEventually(t *testing.T) {
+	if !New(nil).NonFatal().Eventually(func() bool { return true }, time.Millisecond, time.Millisecond) {
+		t.Errorf("should not have failed")
+		t.FailNow()
+	}
+
+	output := bytes.NewBuffer(nil)
+	if New(nil).WithOutput(output).NonFatal().Eventually(func() bool { return false }, time.Millisecond, time.Millisecond) {
+		t.Errorf("should have failed")
+		t.FailNow()
+	}
+	if len(output.String()) == 0 {
+		t.Errorf("should have produced output")
+		t.FailNow()
+	}
+}
+
+func TestAssertNonFatalNever(t *testing.T) {
+	if !New(nil).NonFatal().Never(func() bool { return false }, time.Millisecond, time.Millisecond) {
+		t.Errorf("should not have failed")
+		t.FailNow()
+	}
+
+	output := bytes.NewBuffer(nil)
+	if New(nil).WithOutput(output).NonFatal().Never(func() bool { return true }, time.Millisecond, time.Millisecond) {
+		t.Errorf("should have failed")
+		t.FailNow()
+	}
+	if len(output.String()) == 0 {
+		t.Errorf("should have produced output")
+		t.FailNow()
+	}
+}
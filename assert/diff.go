@@ -0,0 +1,306 @@
+package assert
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// diffContextLines is the number of unchanged lines shown around changes in a diff.
+	diffContextLines = 3
+	// diffMaxCells bounds the work done comparing lines; larger inputs are diffed as a whole.
+	diffMaxCells = 4 << 20
+)
+
+// Diff returns a unified diff between the printed forms of two objects, or an empty string if they print
+// the same. Structs, maps and slices are printed with a field, key or element per line, and maps are
+// printed with their keys sorted, so only what differs is marked; strings are compared line by line.
+func Diff(expected, actual interface{}) string {
+	expectedLines, actualLines := diffLines(expected), diffLines(actual)
+	if strings.Join(expectedLines, "\n") == strings.Join(actualLines, "\n") {
+		return ""
+	}
+	return unifiedDiff(expectedLines, actualLines, false)
+}
+
+// shouldDiff returns if a diff is more readable than printing two values, i.e. either is a structured
+// value or a multi-line string.
+func shouldDiff(expected, actual interface{}) bool {
+	return len(diffLines(expected)) > 1 || len(diffLines(actual)) > 1
+}
+
+// diffMessage returns a failure message with a colored diff, falling back to printing both values
+// if they print the same, e.g. because they have different types.
+func diffMessage(expected, actual interface{}, message string) string {
+	expectedLines, actualLines := diffLines(expected), diffLines(actual)
+	if strings.Join(expectedLines, "\n") == strings.Join(actualLines, "\n") {
+		return shouldBeMultipleMessage(expected, actual, message)
+	}
+	diff := unifiedDiff(expectedLines, actualLines, true)
+	return fmt.Sprintf("%s\n\t%s:\n\t%s", message, color("Diff", WHITE), strings.Replace(diff, "\n", "\n\t", -1))
+}
+
+func diffLines(object interface{}) []string {
+	if typed, isString := object.(string); isString {
+		return strings.Split(typed, "\n")
+	}
+	printer := &prettyPrinter{visited: map[uintptr]bool{}}
+	printer.print(reflect.ValueOf(object), 0)
+	return strings.Split(printer.output.String(), "\n")
+}
+
+// prettyPrinter prints values like `%#v`, but with a field, key or element per line.
+type prettyPrinter struct {
+	output  strings.Builder
+	visited map[uintptr]bool
+}
+
+func (pp *prettyPrinter) indent(depth int) {
+	pp.output.WriteString(strings.Repeat("\t", depth))
+}
+
+func (pp *prettyPrinter) print(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		pp.output.WriteString("nil")
+		return
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) && v.CanInterface() {
+		fmt.Fprintf(&pp.output, "time.Time(%s)", v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			fmt.Fprintf(&pp.output, "(%s)(nil)", v.Type())
+			return
+		}
+		if pp.visited[v.Pointer()] {
+			fmt.Fprintf(&pp.output, "(%s)(<cycle>)", v.Type())
+			return
+		}
+		pp.visited[v.Pointer()] = true
+		defer delete(pp.visited, v.Pointer())
+		pp.output.WriteString("&")
+		pp.print(v.Elem(), depth)
+	case reflect.Interface:
+		if v.IsNil() {
+			pp.output.WriteString("nil")
+			return
+		}
+		pp.print(v.Elem(), depth)
+	case reflect.Struct:
+		if v.NumField() == 0 {
+			fmt.Fprintf(&pp.output, "%s{}", v.Type())
+			return
+		}
+		fmt.Fprintf(&pp.output, "%s{\n", v.Type())
+		for i := 0; i < v.NumField(); i++ {
+			pp.indent(depth + 1)
+			pp.output.WriteString(v.Type().Field(i).Name + ": ")
+			pp.print(v.Field(i), depth+1)
+			pp.output.WriteString(",\n")
+		}
+		pp.indent(depth)
+		pp.output.WriteString("}")
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(&pp.output, "%s(nil)", v.Type())
+			return
+		}
+		if v.Len() == 0 {
+			fmt.Fprintf(&pp.output, "%s{}", v.Type())
+			return
+		}
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keyPrinter := &prettyPrinter{visited: pp.visited}
+			keyPrinter.print(key, depth+1)
+			entries = append(entries, entry{key: keyPrinter.output.String(), value: v.MapIndex(key)})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		fmt.Fprintf(&pp.output, "%s{\n", v.Type())
+		for _, entry := range entries {
+			pp.indent(depth + 1)
+			pp.output.WriteString(entry.key + ": ")
+			pp.print(entry.value, depth+1)
+			pp.output.WriteString(",\n")
+		}
+		pp.indent(depth)
+		pp.output.WriteString("}")
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			fmt.Fprintf(&pp.output, "%s(nil)", v.Type())
+			return
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 && v.Kind() == reflect.Slice {
+			fmt.Fprintf(&pp.output, "%s(%s)", v.Type(), strconv.Quote(string(v.Bytes())))
+			return
+		}
+		if v.Len() == 0 {
+			fmt.Fprintf(&pp.output, "%s{}", v.Type())
+			return
+		}
+		fmt.Fprintf(&pp.output, "%s{\n", v.Type())
+		for i := 0; i < v.Len(); i++ {
+			pp.indent(depth + 1)
+			pp.print(v.Index(i), depth+1)
+			pp.output.WriteString(",\n")
+		}
+		pp.indent(depth)
+		pp.output.WriteString("}")
+	case reflect.String:
+		pp.printScalar(v, strconv.Quote(v.String()))
+	case reflect.Bool:
+		pp.printScalar(v, strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		pp.printScalar(v, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		pp.printScalar(v, strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		pp.printScalar(v, strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		pp.printScalar(v, fmt.Sprint(v.Complex()))
+	default:
+		// chans, funcs and unsafe pointers are only equal if they're nil, so print their address.
+		if v.IsNil() {
+			fmt.Fprintf(&pp.output, "(%s)(nil)", v.Type())
+			return
+		}
+		fmt.Fprintf(&pp.output, "(%s)(%#x)", v.Type(), v.Pointer())
+	}
+}
+
+// printScalar prints a scalar, with its type if it isn't a builtin type.
+func (pp *prettyPrinter) printScalar(v reflect.Value, value string) {
+	if v.Type().PkgPath() == "" {
+		pp.output.WriteString(value)
+		return
+	}
+	fmt.Fprintf(&pp.output, "%s(%s)", v.Type(), value)
+}
+
+// unifiedDiff returns a unified diff of two sets of lines, optionally with removed and added lines colored.
+func unifiedDiff(expected, actual []string, colored bool) string {
+	edits := diffEdits(expected, actual)
+
+	var output strings.Builder
+	output.WriteString("--- Expected\n+++ Actual\n")
+	for start := 0; start < len(edits); {
+		// find the next change, and the end of the hunk around it.
+		for start < len(edits) && edits[start].op == ' ' {
+			start++
+		}
+		if start == len(edits) {
+			break
+		}
+		hunkStart := start - diffContextLines
+		if hunkStart < 0 {
+			hunkStart = 0
+		}
+		end := start
+		for unchanged := 0; end < len(edits) && unchanged <= 2*diffContextLines; end++ {
+			if edits[end].op == ' ' {
+				unchanged++
+			} else {
+				unchanged = 0
+			}
+		}
+		// trim trailing context past the limit.
+		hunkEnd := end
+		for hunkEnd > start && edits[hunkEnd-1].op == ' ' {
+			hunkEnd--
+		}
+		hunkEnd += diffContextLines
+		if hunkEnd > len(edits) {
+			hunkEnd = len(edits)
+		}
+
+		hunk := edits[hunkStart:hunkEnd]
+		var expectedCount, actualCount int
+		for _, edit := range hunk {
+			if edit.op != '+' {
+				expectedCount++
+			}
+			if edit.op != '-' {
+				actualCount++
+			}
+		}
+		fmt.Fprintf(&output, "@@ -%d,%d +%d,%d @@\n", hunk[0].expectedLine, expectedCount, hunk[0].actualLine, actualCount)
+		for _, edit := range hunk {
+			line := string(edit.op) + edit.text
+			if colored && edit.op == '-' {
+				line = color(line, RED)
+			} else if colored && edit.op == '+' {
+				line = color(line, GREEN)
+			}
+			output.WriteString(line + "\n")
+		}
+		start = hunkEnd
+	}
+	return strings.TrimSuffix(output.String(), "\n")
+}
+
+type diffEdit struct {
+	op           byte
+	text         string
+	expectedLine int
+	actualLine   int
+}
+
+// diffEdits returns the edits that turn the expected lines into the actual lines, from their
+// longest common subsequence.
+func diffEdits(expected, actual []string) []diffEdit {
+	var edits []diffEdit
+	if len(expected)*len(actual) > diffMaxCells {
+		for index, line := range expected {
+			edits = append(edits, diffEdit{op: '-', text: line, expectedLine: index + 1, actualLine: 1})
+		}
+		for index, line := range actual {
+			edits = append(edits, diffEdit{op: '+', text: line, expectedLine: len(expected) + 1, actualLine: index + 1})
+		}
+		return edits
+	}
+
+	// lengths[i][j] is the length of the longest common subsequence of expected[i:] and actual[j:].
+	lengths := make([][]int, len(expected)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(actual)+1)
+	}
+	for i := len(expected) - 1; i >= 0; i-- {
+		for j := len(actual) - 1; j >= 0; j-- {
+			if expected[i] == actual[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var i, j int
+	for i < len(expected) || j < len(actual) {
+		switch {
+		case i < len(expected) && j < len(actual) && expected[i] == actual[j]:
+			edits = append(edits, diffEdit{op: ' ', text: expected[i], expectedLine: i + 1, actualLine: j + 1})
+			i++
+			j++
+		case j < len(actual) && (i == len(expected) || lengths[i][j+1] > lengths[i+1][j]):
+			edits = append(edits, diffEdit{op: '+', text: actual[j], expectedLine: i + 1, actualLine: j + 1})
+			j++
+		default:
+			edits = append(edits, diffEdit{op: '-', text: expected[i], expectedLine: i + 1, actualLine: j + 1})
+			i++
+		}
+	}
+	return edits
+}
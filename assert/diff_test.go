@@ -0,0 +1,136 @@
+package assert
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type diffTestUser struct {
+	ID      int
+	Name    string
+	Tags    []string
+	Labels  map[string]string
+	Created time.Time
+	manager *diffTestUser
+}
+
+func TestDiff(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	expected := diffTestUser{ID: 1, Name: "bailey", Tags: []string{"a", "b"}, Labels: map[string]string{"team": "web", "env": "prod"}, Created: created}
+	actual := diffTestUser{ID: 1, Name: "riley", Tags: []string{"a", "b"}, Labels: map[string]string{"env": "prod", "team": "web"}, Created: created}
+
+	diff := Diff(expected, actual)
+	expectedDiff := `--- Expected
++++ Actual
+@@ -1,6 +1,6 @@
+ assert.diffTestUser{
+ 	ID: 1,
+-	Name: "bailey",
++	Name: "riley",
+ 	Tags: []string{
+ 		"a",
+ 		"b",`
+	if diff != expectedDiff {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+
+	if diff := Diff(expected, expected); diff != "" {
+		t.Errorf("equal values should have an empty diff, got:\n%s", diff)
+	}
+
+	diff = Diff("line 1\nline 2\nline 3", "line 1\nline two\nline 3\nline 4")
+	expectedDiff = `--- Expected
++++ Actual
+@@ -1,3 +1,4 @@
+ line 1
+-line 2
++line two
+ line 3
++line 4`
+	if diff != expectedDiff {
+		t.Errorf("unexpected string diff:\n%s", diff)
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var expected, actual []int
+	for i := 0; i < 30; i++ {
+		expected = append(expected, i)
+		actual = append(actual, i)
+	}
+	actual[2] = 100
+	actual[25] = 200
+
+	diff := Diff(expected, actual)
+	if strings.Count(diff, "@@ -") != 2 {
+		t.Errorf("changes far apart should be in separate hunks:\n%s", diff)
+	}
+	if strings.Contains(diff, "\t14,") {
+		t.Errorf("unchanged lines far from changes should be omitted:\n%s", diff)
+	}
+	if !strings.Contains(diff, "@@ -1,7 +1,7 @@") || !strings.Contains(diff, "@@ -24,7 +24,7 @@") {
+		t.Errorf("unexpected hunk headers:\n%s", diff)
+	}
+}
+
+func TestDiffCycle(t *testing.T) {
+	expected := &diffTestUser{ID: 1}
+	expected.manager = expected
+	actual := &diffTestUser{ID: 2}
+	actual.manager = actual
+
+	if diff := Diff(expected, actual); !strings.Contains(diff, "-\tID: 1,") {
+		t.Errorf("unexpected diff:\n%s", diff)
+	}
+	if printed := strings.Join(diffLines(expected), "\n"); !strings.Contains(printed, "manager: (*assert.diffTestUser)(<cycle>),") {
+		t.Errorf("cycles should be printed once:\n%s", printed)
+	}
+}
+
+func TestAssertEqualDiff(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	err := safeExec(func() {
+		New(nil).WithOutput(output).Equal(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1, "b": 3})
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "--- Expected") || !strings.Contains(output.String(), `"b": 3,`) {
+		t.Errorf("equal failures for maps should include a diff, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	_ = safeExec(func() {
+		New(nil).WithOutput(output).Equal("foo", "bar")
+	})
+	if strings.Contains(output.String(), "--- Expected") {
+		t.Errorf("equal failures for scalars shouldn't include a diff, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	err = safeExec(func() {
+		New(nil).WithOutput(output).EqualDiff("foo", "bar")
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "--- Expected") {
+		t.Errorf("equal diff failures should always include a diff, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	_ = safeExec(func() {
+		New(nil).WithOutput(output).EqualDiff([]interface{}{1}, []interface{}{int64(1)})
+	})
+	if !strings.Contains(output.String(), "Expected") {
+		t.Errorf("values that print the same should fall back to printing both, got:\n%s", output.String())
+	}
+
+	if !New(nil).NonFatal().EqualDiff([]int{1}, []int{1}) {
+		t.Errorf("equal values should pass")
+	}
+}
@@ -0,0 +1,107 @@
+package assert
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	update = flag.Bool("update", false, "If we should rewrite golden files with the actual values")
+)
+
+const (
+	// GoldenDir is the directory golden files are read from and written to, relative to the package under test.
+	GoldenDir = "testdata"
+	// GoldenExtension is the extension of golden files.
+	GoldenExtension = ".golden"
+)
+
+// GoldenPath returns the path of the golden file with a given name, i.e. `testdata/{name}.golden`.
+func GoldenPath(name string) string {
+	return filepath.Join(GoldenDir, filepath.FromSlash(name)+GoldenExtension)
+}
+
+// MatchesGolden asserts that a value matches the golden file with a given name.
+// Run the tests with `-update` to rewrite the golden files with the actual values.
+func MatchesGolden(t *testing.T, name string, actual interface{}, userMessageComponents ...interface{}) {
+	New(t).MatchesGolden(name, actual, userMessageComponents...)
+}
+
+// MatchesGolden asserts that a value matches the golden file with a given name, i.e. `testdata/{name}.golden`.
+/*
+Strings and byte slices are compared as is; anything else is compared as indented json. Run the tests with
+`-update` to write the actual values to the golden files instead, and review the changes to them like code:
+
+	go test ./web/ -run TestOpenAPI -update
+*/
+func (a *Assertions) MatchesGolden(name string, actual interface{}, userMessageComponents ...interface{}) {
+	a.assertion()
+	if didFail, message := shouldMatchGolden(name, actual); didFail {
+		failNow(a.output, a.t, message, userMessageComponents...)
+	}
+}
+
+// MatchesGolden asserts that a value matches the golden file with a given name, i.e. `testdata/{name}.golden`.
+func (o *Optional) MatchesGolden(name string, actual interface{}, userMessageComponents ...interface{}) bool {
+	o.assertion()
+	if didFail, message := shouldMatchGolden(name, actual); didFail {
+		fail(o.output, o.t, prefixOptional(message), userMessageComponents...)
+		return false
+	}
+	return true
+}
+
+func shouldMatchGolden(name string, actual interface{}) (bool, string) {
+	contents, err := goldenContents(actual)
+	if err != nil {
+		return true, fmt.Sprintf("Should be able to serialize the value for golden file %s: %v", name, err)
+	}
+
+	path := GoldenPath(name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return true, fmt.Sprintf("Should be able to create the golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, contents, 0644); err != nil {
+			return true, fmt.Sprintf("Should be able to write golden file %s: %v", path, err)
+		}
+		return false, EMPTY
+	}
+
+	expected, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return true, fmt.Sprintf("Golden file %s should exist; run the tests with -update to create it", path)
+	}
+	if err != nil {
+		return true, fmt.Sprintf("Should be able to read golden file %s: %v", path, err)
+	}
+
+	// golden files checked out on windows may have their line endings changed.
+	expectedText := strings.Replace(string(expected), "\r\n", "\n", -1)
+	actualText := string(contents)
+	if expectedText != actualText {
+		return true, diffMessage(expectedText, actualText, fmt.Sprintf("Should match golden file %s; run the tests with -update to rewrite it", path))
+	}
+	return false, EMPTY
+}
+
+// goldenContents returns the contents of a golden file for a value.
+func goldenContents(actual interface{}) ([]byte, error) {
+	switch typed := actual.(type) {
+	case []byte:
+		return typed, nil
+	case string:
+		return []byte(typed), nil
+	}
+	contents, err := json.MarshalIndent(actual, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(contents, '\n'), nil
+}
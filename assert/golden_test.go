@@ -0,0 +1,78 @@
+package assert
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+type goldenTestObject struct {
+	ID   int      `json:"id"`
+	Tags []string `json:"tags"`
+}
+
+func TestAssertMatchesGolden(t *testing.T) {
+	err := safeExec(func() {
+		New(nil).MatchesGolden("golden_string", "line 1\nline 2\n") // should be ok
+		New(nil).MatchesGolden("golden_string", []byte("line 1\nline 2\n"))
+		New(nil).MatchesGolden("golden_object", goldenTestObject{ID: 1, Tags: []string{"a"}})
+	})
+	if err != nil {
+		t.Errorf("should not have produced a panic: %v", err)
+		t.FailNow()
+	}
+
+	output := bytes.NewBuffer(nil)
+	err = safeExec(func() {
+		New(nil).WithOutput(output).MatchesGolden("golden_object", goldenTestObject{ID: 2, Tags: []string{"a"}})
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "Should match golden file testdata/golden_object.golden") || !strings.Contains(output.String(), `"id": 2,`) {
+		t.Errorf("Should have written a diff on failure, got: %s", output.String())
+		t.FailNow()
+	}
+
+	output.Reset()
+	err = safeExec(func() {
+		New(nil).WithOutput(output).MatchesGolden("does_not_exist", "foo")
+	})
+	if err == nil {
+		t.Errorf("should have produced a panic")
+		t.FailNow()
+	}
+	if !strings.Contains(output.String(), "run the tests with -update to create it") {
+		t.Errorf("Should have written output on failure, got: %s", output.String())
+		t.FailNow()
+	}
+}
+
+func TestAssertMatchesGoldenUpdate(t *testing.T) {
+	defer func() { *update = false }()
+	defer os.RemoveAll("testdata/update_test")
+
+	*update = true
+	if !New(nil).NonFatal().MatchesGolden("update_test/value", "updated\n") {
+		t.Errorf("should not have failed when updating")
+		t.FailNow()
+	}
+	contents, err := ioutil.ReadFile(GoldenPath("update_test/value"))
+	if err != nil || string(contents) != "updated\n" {
+		t.Errorf("should have written the golden file, got: %q %v", contents, err)
+		t.FailNow()
+	}
+
+	*update = false
+	if !New(nil).NonFatal().MatchesGolden("update_test/value", "updated\n") {
+		t.Errorf("should match the updated golden file")
+		t.FailNow()
+	}
+	if New(nil).WithOutput(bytes.NewBuffer(nil)).NonFatal().MatchesGolden("update_test/value", "changed\n") {
+		t.Errorf("should have failed")
+		t.FailNow()
+	}
+}
@@ -88,11 +88,12 @@ func (i *Interval) Start() {
 			time.Sleep(i.delay)
 		}
 
-		tick := time.Tick(i.interval)
+		ticker := time.NewTicker(i.interval)
+		defer ticker.Stop()
 		var err error
 		for {
 			select {
-			case <-tick:
+			case <-ticker.C:
 				err = i.action()
 				if err != nil && i.errors != nil {
 					i.errors <- err
@@ -40,6 +40,9 @@ const (
 
 	// ErrTaskNotFound is a common error.
 	ErrTaskNotFound Error = "task not found"
+
+	// ErrStringScheduleInvalid is returned when a cron string schedule fails to parse.
+	ErrStringScheduleInvalid Error = "cron: invalid string schedule"
 )
 
 // IsJobNotLoaded returns if the error is a job not loaded error.
@@ -52,6 +55,11 @@ func IsJobAlreadyLoaded(err error) bool {
 	return exception.Is(err, ErrJobAlreadyLoaded)
 }
 
+// IsStringScheduleInvalid returns if the error is a string schedule parse error.
+func IsStringScheduleInvalid(err error) bool {
+	return exception.Is(err, ErrStringScheduleInvalid)
+}
+
 // IsTaskNotFound returns if the error is a task not found error.
 func IsTaskNotFound(err error) bool {
 	return exception.Is(err, ErrTaskNotFound)
@@ -313,6 +313,11 @@ func (jm *JobManager) Start() {
 	jm.killHangingTasksWorker.Start()
 }
 
+// IsStarted returns if the schedule runner is started.
+func (jm *JobManager) IsStarted() bool {
+	return jm.schedulerWorker.IsRunning()
+}
+
 // Stop stops the schedule runner for a JobManager.
 func (jm *JobManager) Stop() {
 	jm.schedulerWorker.Stop()
@@ -370,10 +375,6 @@ func (jm *JobManager) runTaskUnsafe(t Task) error {
 	go func() {
 		var err error
 		defer func() {
-			if r := recover(); r != nil {
-				err = exception.New(r)
-			}
-
 			jm.Lock()
 			if _, hasTask := jm.tasks[taskName]; hasTask {
 				jm.onTaskComplete(t, Since(start), err)
@@ -388,6 +389,7 @@ func (jm *JobManager) runTaskUnsafe(t Task) error {
 				defer func() { tf.Finish(ctx, t, err) }()
 			}
 		}
+		defer exception.Recover(&err)
 		jm.onTaskStart(t)
 		err = t.Execute(ctx)
 	}()
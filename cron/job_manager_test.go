@@ -174,8 +174,8 @@ func TestSerialTask(t *testing.T) {
 	})
 	jm.RunTask(task)
 	jm.RunTask(task)
-	time.Sleep(100 * time.Millisecond)
-	assert.Equal(1, runCount.Get())
+	assert.Eventually(func() bool { return runCount.Get() == 1 }, time.Second, time.Millisecond)
+	assert.Never(func() bool { return runCount.Get() > 1 }, 50*time.Millisecond, time.Millisecond)
 
 	// ensure parallel execution is still working as intended
 	task = NewTaskWithName("test1", func(ctx context.Context) error {
@@ -186,8 +186,7 @@ func TestSerialTask(t *testing.T) {
 	runCount = new(AtomicCounter)
 	jm.RunTask(task)
 	jm.RunTask(task)
-	time.Sleep(100 * time.Millisecond)
-	assert.Equal(2, runCount.Get())
+	assert.Eventually(func() bool { return runCount.Get() == 2 }, time.Second, time.Millisecond)
 }
 
 func TestRunJobByScheduleRapid(t *testing.T) {
@@ -0,0 +1,224 @@
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// StringScheduleMacros are the `@` shorthands for cron string schedules.
+var StringScheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	stringScheduleMonthNames = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	stringScheduleDayNames = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// ParseString parses a cron string schedule, e.g. `0 4 * * *` for every day at 04:00 UTC.
+// Schedules have five fields, minute, hour, day of month, month and day of week, or six fields
+// with a leading seconds field. Fields can be `*`, values, ranges (`1-5`), steps (`*/15`, `0-30/10`)
+// and lists of these (`1,15`); months and days of week can also be names (`jan`, `mon-fri`).
+// The `@yearly`, `@monthly`, `@weekly`, `@daily` and `@hourly` macros are supported,
+// as is `@every <duration>`, e.g. `@every 90m`.
+func ParseString(cronString string) (Schedule, error) {
+	cronString = strings.TrimSpace(cronString)
+	original := cronString
+	if strings.HasPrefix(cronString, "@every ") {
+		every, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(cronString, "@every ")))
+		if err != nil || every <= 0 {
+			return nil, exception.New(ErrStringScheduleInvalid).WithMessagef("invalid @every duration: %q", cronString)
+		}
+		return Every(every), nil
+	}
+	if expanded, ok := StringScheduleMacros[strings.ToLower(cronString)]; ok {
+		cronString = expanded
+	}
+
+	fields := strings.Fields(cronString)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, exception.New(ErrStringScheduleInvalid).WithMessagef("expected 5 or 6 fields: %q", cronString)
+	}
+
+	schedule := &StringSchedule{Original: original}
+	var err error
+	if schedule.Seconds, err = parseStringScheduleField(fields[0], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if schedule.Minutes, err = parseStringScheduleField(fields[1], 0, 59, nil); err != nil {
+		return nil, err
+	}
+	if schedule.Hours, err = parseStringScheduleField(fields[2], 0, 23, nil); err != nil {
+		return nil, err
+	}
+	if schedule.DaysOfMonth, err = parseStringScheduleField(fields[3], 1, 31, nil); err != nil {
+		return nil, err
+	}
+	if schedule.Months, err = parseStringScheduleField(fields[4], 1, 12, stringScheduleMonthNames); err != nil {
+		return nil, err
+	}
+	// 7 is also sunday.
+	if schedule.DaysOfWeek, err = parseStringScheduleField(fields[5], 0, 7, stringScheduleDayNames); err != nil {
+		return nil, err
+	}
+	if schedule.DaysOfWeek&(1<<7) != 0 {
+		schedule.DaysOfWeek |= 1
+	}
+	schedule.AnyDayOfMonth = fields[3] == "*" || fields[3] == "?"
+	schedule.AnyDayOfWeek = fields[5] == "*" || fields[5] == "?"
+	return schedule, nil
+}
+
+// MustParseString parses a cron string schedule and panics on error.
+func MustParseString(cronString string) Schedule {
+	schedule, err := ParseString(cronString)
+	if err != nil {
+		panic(err)
+	}
+	return schedule
+}
+
+// StringSchedule is a schedule parsed from a cron string, evaluated in UTC.
+// Each field is a bitmask of the values it matches.
+type StringSchedule struct {
+	Original string
+
+	Seconds     uint64
+	Minutes     uint64
+	Hours       uint64
+	DaysOfMonth uint64
+	Months      uint64
+	DaysOfWeek  uint64
+
+	// AnyDayOfMonth and AnyDayOfWeek are if the day fields are unrestricted; if both are
+	// restricted a day matches if it matches either, as with standard cron.
+	AnyDayOfMonth bool
+	AnyDayOfWeek  bool
+}
+
+// String returns the original cron string.
+func (ss *StringSchedule) String() string {
+	return ss.Original
+}
+
+// GetNextRunTime implements Schedule.
+func (ss *StringSchedule) GetNextRunTime(after *time.Time) *time.Time {
+	if after == nil {
+		after = Optional(Now())
+	}
+	next := after.UTC().Truncate(time.Second).Add(time.Second)
+	// give up if nothing matches within five years, e.g. february 30th.
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if !hasBit(ss.Months, int(next.Month())) {
+			next = time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+			continue
+		}
+		if !ss.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+			continue
+		}
+		if !hasBit(ss.Hours, next.Hour()) {
+			next = next.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !hasBit(ss.Minutes, next.Minute()) {
+			next = next.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		if !hasBit(ss.Seconds, next.Second()) {
+			next = next.Add(time.Second)
+			continue
+		}
+		return &next
+	}
+	return nil
+}
+
+func (ss *StringSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := hasBit(ss.DaysOfMonth, t.Day())
+	dayOfWeek := hasBit(ss.DaysOfWeek, int(t.Weekday()))
+	if ss.AnyDayOfMonth || ss.AnyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func hasBit(mask uint64, value int) bool {
+	return mask&(1<<uint(value)) != 0
+}
+
+// parseStringScheduleField parses a cron string schedule field into a bitmask of the values it matches.
+func parseStringScheduleField(field string, min, max int, names map[string]int) (uint64, error) {
+	var mask uint64
+	for _, part := range strings.Split(field, ",") {
+		rangeExpr, step := part, 1
+		if slash := strings.Index(part, "/"); slash >= 0 {
+			var err error
+			rangeExpr = part[:slash]
+			if step, err = strconv.Atoi(part[slash+1:]); err != nil || step <= 0 {
+				return 0, exception.New(ErrStringScheduleInvalid).WithMessagef("invalid step: %q", part)
+			}
+		}
+
+		start, end := min, max
+		switch {
+		case rangeExpr == "*" || rangeExpr == "?":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if start, err = parseStringScheduleValue(bounds[0], min, max, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseStringScheduleValue(bounds[1], min, max, names); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, exception.New(ErrStringScheduleInvalid).WithMessagef("invalid range: %q", part)
+			}
+		default:
+			var err error
+			if start, err = parseStringScheduleValue(rangeExpr, min, max, names); err != nil {
+				return 0, err
+			}
+			// a single value with a step, e.g. `5/15`, runs from the value to the max.
+			end = start
+			if strings.Contains(part, "/") {
+				end = max
+			}
+		}
+		for value := start; value <= end; value += step {
+			mask |= 1 << uint(value)
+		}
+	}
+	return mask, nil
+}
+
+func parseStringScheduleValue(value string, min, max int, names map[string]int) (int, error) {
+	if named, ok := names[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < min || parsed > max {
+		return 0, exception.New(ErrStringScheduleInvalid).WithMessagef("invalid value: %q, expected %d-%d", value, min, max)
+	}
+	return parsed, nil
+}
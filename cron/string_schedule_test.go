@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestParseString(t *testing.T) {
+	assert := assert.New(t)
+
+	after := time.Date(2018, 01, 15, 12, 30, 15, 0, time.UTC) // a monday
+	testCases := []struct {
+		Input    string
+		Expected time.Time
+	}{
+		{"0 4 * * *", time.Date(2018, 01, 16, 4, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2018, 01, 15, 12, 45, 0, 0, time.UTC)},
+		{"30 * * * * *", time.Date(2018, 01, 15, 12, 30, 30, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2018, 01, 16, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * sat,SUN", time.Date(2018, 01, 20, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2018, 01, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2018, 02, 01, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2020, 02, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 20 * mon", time.Date(2018, 01, 20, 0, 0, 0, 0, time.UTC)},
+		{"5/20 13 * * *", time.Date(2018, 01, 15, 13, 5, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2018, 01, 15, 13, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2019, 01, 01, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		schedule, err := ParseString(tc.Input)
+		assert.Nil(err, tc.Input)
+		next := schedule.GetNextRunTime(&after)
+		assert.NotNil(next, tc.Input)
+		assert.Equal(tc.Expected, *next, tc.Input)
+	}
+}
+
+func TestParseStringEvery(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseString("@every 90m")
+	assert.Nil(err)
+	after := time.Date(2018, 01, 15, 12, 30, 0, 0, time.UTC)
+	assert.Equal(after.Add(90*time.Minute), *schedule.GetNextRunTime(&after))
+}
+
+func TestParseStringNeverMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	schedule, err := ParseString("0 0 30 feb *")
+	assert.Nil(err)
+	assert.Nil(schedule.GetNextRunTime(nil))
+}
+
+func TestParseStringInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, input := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@every nope",
+	} {
+		_, err := ParseString(input)
+		assert.True(IsStringScheduleInvalid(err), input)
+	}
+}
@@ -2,8 +2,10 @@ package exception
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 )
 
 // New returns a new exception with a call stack.
@@ -17,6 +19,34 @@ func Wrap(class interface{}) Exception {
 	return newWithStartDepth(class, defaultNewStartDepth)
 }
 
+// Wrapf returns an exception that adds context to an error, with the error as its inner error.
+// Unlike `New`, which returns exceptions untouched, it always returns a new exception; it keeps the class
+// and stack of the error if it is an exception, so `Is` and `errors.Is` still match the error's class.
+/*
+Use it to add context as an error is returned up the stack:
+
+	if err := loadConfig(path); err != nil {
+		return exception.Wrapf(err, "loading config from %s", path)
+	}
+*/
+func Wrapf(err error, format string, args ...interface{}) Exception {
+	if err == nil {
+		return nil
+	}
+	wrapped := &Ex{
+		class:   err,
+		message: fmt.Sprintf(format, args...),
+		inner:   err,
+	}
+	if typed := As(err); typed != nil {
+		wrapped.class = typed.Class()
+		wrapped.stack = typed.Stack()
+	} else {
+		wrapped.stack = callers(defaultNewStartDepth)
+	}
+	return wrapped
+}
+
 func newWithStartDepth(class interface{}, startDepth int) Exception {
 	if class == nil {
 		return nil
@@ -92,8 +122,11 @@ type Exception interface {
 	Inner() error
 	WithStack(StackTrace) Exception
 	Stack() StackTrace
+	WithMeta(string, interface{}) Exception
+	Meta() map[string]interface{}
 
 	Decompose() map[string]interface{}
+	DecomposeStructured() map[string]interface{}
 }
 
 // Ex is an error with a stack trace.
@@ -107,11 +140,13 @@ type Ex struct {
 	inner error
 	// Stack is the call stack frames used to create the stack output.
 	stack StackTrace
+	// Meta holds key value pairs that give structured context to the error, e.g. ids.
+	meta map[string]interface{}
 }
 
 // Format allows for conditional expansion in printf statements
 // based on the token and flags used.
-// 	%+v : class + message + stack
+// 	%+v : class + message + meta + stack
 // 	%v, %c : class
 // 	%m : message
 // 	%t : stack
@@ -127,7 +162,12 @@ func (e *Ex) Format(s fmt.State, verb rune) {
 			} else if len(e.message) > 0 {
 				io.WriteString(s, e.message)
 			}
-			e.stack.Format(s, verb)
+			for _, key := range e.metaKeys() {
+				fmt.Fprintf(s, "\nmeta: %s=%v", key, e.meta[key])
+			}
+			if e.stack != nil {
+				e.stack.Format(s, verb)
+			}
 		} else if s.Flag('-') {
 			e.stack.Format(s, verb)
 		} else {
@@ -163,7 +203,36 @@ func (e *Ex) Error() string {
 	return e.class.Error()
 }
 
+// Unwrap returns the inner error, so the standard library's `errors.Is` and `errors.As`
+// walk the chain of causing errors.
+func (e *Ex) Unwrap() error {
+	return e.inner
+}
+
+// Is returns if the exception's class is a given error, for use by `errors.Is`.
+// Exceptions are equivalent to other exceptions with the same class.
+func (e *Ex) Is(target error) bool {
+	if e.class == nil {
+		return false
+	}
+	if typed, isTyped := target.(Exception); isTyped {
+		return typed.Class() != nil && errors.Is(e.class, typed.Class())
+	}
+	return errors.Is(e.class, target)
+}
+
+// As finds the first error in the exception's class that matches a target, for use by `errors.As`,
+// e.g. an `*os.PathError` an exception was created from.
+func (e *Ex) As(target interface{}) bool {
+	if e.class == nil {
+		return false
+	}
+	return errors.As(e.class, target)
+}
+
 // Decompose breaks the exception down to be marshalled into an intermediate format.
+// It is the exception's json representation, with the keys "Class", "Message", "Stack" (as strings),
+// "Inner" and, if set, "Meta"; see `DecomposeStructured` for a format suited to error reporting pipelines.
 func (e *Ex) Decompose() map[string]interface{} {
 	values := map[string]interface{}{}
 	values["Class"] = e.class
@@ -171,6 +240,9 @@ func (e *Ex) Decompose() map[string]interface{} {
 	if e.stack != nil {
 		values["Stack"] = e.Stack().Strings()
 	}
+	if len(e.meta) > 0 {
+		values["Meta"] = e.meta
+	}
 	if e.inner != nil {
 		if typed, isTyped := e.inner.(Exception); isTyped {
 			values["Inner"] = typed.Decompose()
@@ -181,6 +253,43 @@ func (e *Ex) Decompose() map[string]interface{} {
 	return values
 }
 
+// DecomposeStructured breaks the exception down into a stable format for structured error reporting.
+/*
+It has the keys:
+
+	class    the class, as a string
+	message  the message, if set
+	stack    the stack frames, as objects with the "file", "line" and "function" of each frame
+	meta     the meta key value pairs, if set
+	inner    the inner error, decomposed, if set
+
+Marshal it to json explicitly, e.g. `json.Marshal(ex.DecomposeStructured())`; the exception's own json
+is `Decompose`, which keeps its original keys.
+*/
+func (e *Ex) DecomposeStructured() map[string]interface{} {
+	values := map[string]interface{}{}
+	if e.class != nil {
+		values["class"] = e.class.Error()
+	}
+	if len(e.message) > 0 {
+		values["message"] = e.message
+	}
+	if e.stack != nil {
+		values["stack"] = e.stack.Frames()
+	}
+	if len(e.meta) > 0 {
+		values["meta"] = e.meta
+	}
+	if e.inner != nil {
+		if typed, isTyped := e.inner.(Exception); isTyped {
+			values["inner"] = typed.DecomposeStructured()
+		} else {
+			values["inner"] = map[string]interface{}{"class": e.inner.Error()}
+		}
+	}
+	return values
+}
+
 // MarshalJSON is a custom json marshaler.
 func (e *Ex) MarshalJSON() ([]byte, error) {
 	return json.Marshal(e.Decompose())
@@ -227,6 +336,30 @@ func (e *Ex) Message() string {
 	return e.message
 }
 
+// WithMeta sets a meta key value pair, giving structured context to the error, and returns the exception.
+// Values should be json serializable.
+func (e *Ex) WithMeta(key string, value interface{}) Exception {
+	if e.meta == nil {
+		e.meta = map[string]interface{}{}
+	}
+	e.meta[key] = value
+	return e
+}
+
+// Meta returns the meta key value pairs.
+func (e *Ex) Meta() map[string]interface{} {
+	return e.meta
+}
+
+func (e *Ex) metaKeys() []string {
+	keys := make([]string, 0, len(e.meta))
+	for key := range e.meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // WithStack sets the stack.
 func (e *Ex) WithStack(stack StackTrace) Exception {
 	e.stack = stack
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"testing"
 
 	"strings"
@@ -132,9 +133,73 @@ func TestMarshalJSON(t *testing.T) {
 	ex2 := &ReadableStackTrace{}
 	err = json.Unmarshal(jsonErr, ex2)
 	a.Nil(err)
+	a.Equal("new test error", ex2.Class)
 	a.Len(ex2.Stack, stackDepth)
 }
 
+func TestMarshalJSONKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	// the json keys are unchanged from earlier versions, and the message is included even when it's empty.
+	contents, err := json.Marshal(New("outer").WithInner(New("inner").WithStack(StackStrings{"inner frame"})).WithStack(StackStrings{"frame"}))
+	assert.Nil(err)
+	var values map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &values))
+	assert.Equal(map[string]interface{}{
+		"Class":   "outer",
+		"Message": "",
+		"Stack":   []interface{}{"frame"},
+		"Inner": map[string]interface{}{
+			"Class":   "inner",
+			"Message": "",
+			"Stack":   []interface{}{"inner frame"},
+		},
+	}, values)
+}
+
+func TestMarshalJSONMetaAndInner(t *testing.T) {
+	assert := assert.New(t)
+
+	ex := New("outer").
+		WithMessage("loading user").
+		WithMeta("userID", 1234).
+		WithMeta("attempt", "first").
+		WithInner(errors.New("connection refused")).
+		WithStack(StackStrings{"github.com/blend/go-sdk/db.Query\n\tdb/query.go:12", "unparsed"})
+	assert.Equal(map[string]interface{}{"userID": 1234, "attempt": "first"}, ex.Meta())
+
+	contents, err := json.Marshal(ex)
+	assert.Nil(err)
+	var values map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &values))
+	assert.Equal(map[string]interface{}{"userID": float64(1234), "attempt": "first"}, values["Meta"])
+
+	contents, err = json.Marshal(ex.DecomposeStructured())
+	assert.Nil(err)
+	values = nil
+	assert.Nil(json.Unmarshal(contents, &values))
+	assert.Equal("outer", values["class"])
+	assert.Equal("loading user", values["message"])
+	assert.Equal(map[string]interface{}{"userID": float64(1234), "attempt": "first"}, values["meta"])
+	assert.Equal([]interface{}{
+		map[string]interface{}{"file": "db/query.go", "line": float64(12), "function": "github.com/blend/go-sdk/db.Query"},
+		map[string]interface{}{"file": "", "line": float64(0), "function": "unparsed"},
+	}, values["stack"])
+	inner := values["inner"].(map[string]interface{})
+	assert.Equal("connection refused", inner["class"])
+	assert.NotEmpty(inner["stack"])
+
+	assert.Contains(fmt.Sprintf("%+v", ex), "meta: attempt=first\nmeta: userID=1234")
+
+	structured := New("new test error").DecomposeStructured()
+	assert.Equal("new test error", structured["class"])
+	assert.Nil(structured["message"], "empty messages are omitted")
+	frames := structured["stack"].([]StackFrame)
+	assert.Equal("github.com/blend/go-sdk/exception.TestMarshalJSONMetaAndInner", frames[0].Function)
+	assert.True(strings.HasSuffix(frames[0].File, "exception/exception_test.go"), frames[0].File)
+	assert.NotZero(frames[0].Line)
+}
+
 func TestNest(t *testing.T) {
 	a := assert.New(t)
 
@@ -179,3 +244,42 @@ func TestExceptionPrintsInner(t *testing.T) {
 	assert.Contains(output, "middle")
 	assert.Contains(output, "terminal")
 }
+
+func TestExceptionErrorsIsAs(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := os.Open("/this/file/does/not/exist")
+	ex := New(err).WithMessage("opening the file")
+	assert.True(errors.Is(ex, os.ErrNotExist), "errors.Is should match the class")
+
+	var pathErr *os.PathError
+	assert.True(errors.As(ex, &pathErr), "errors.As should match the class")
+	assert.Equal("/this/file/does/not/exist", pathErr.Path)
+
+	outer := New("outer").WithInner(ex)
+	assert.Equal(ex, errors.Unwrap(outer))
+	assert.True(errors.Is(outer, os.ErrNotExist), "errors.Is should match causes")
+	assert.False(errors.Is(New("outer"), os.ErrNotExist))
+}
+
+func TestWrapf(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(Wrapf(nil, "context"))
+
+	errInvalid := Class("invalid")
+	ex := New(errInvalid).WithMessage("original")
+	wrapped := Wrapf(ex, "loading %s", "config.yml")
+	assert.True(Is(wrapped, errInvalid))
+	assert.Equal(errInvalid, wrapped.Class())
+	assert.Equal("loading config.yml", wrapped.Message())
+	assert.Equal(ex, wrapped.Inner())
+	assert.Equal(ex.Stack(), wrapped.Stack(), "the original stack should be kept")
+	assert.Contains(fmt.Sprintf("%v", wrapped), "original")
+
+	err := errors.New("plain")
+	wrapped = Wrapf(err, "context")
+	assert.Equal(err, wrapped.Class())
+	assert.True(errors.Is(wrapped, err))
+	assert.NotNil(wrapped.Stack())
+}
@@ -0,0 +1,72 @@
+package exception
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrPanic is the class of exceptions recovered from panics.
+const ErrPanic Class = "panic"
+
+// Recover recovers a panic and sets the error it's passed to an exception for it, with the stack of the panic.
+// It must be deferred directly, and is meant for goroutine boundaries where a panic would otherwise crash the process.
+/*
+Exceptions recovered from panics have the class `ErrPanic` and the panic value as their message; if the panic
+value is an error it's also their inner error, so `errors.Is` and `errors.As` match it:
+
+	func (w *worker) process(item Item) (err error) {
+		defer exception.Recover(&err)
+		return w.handle(item)
+	}
+*/
+func Recover(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if err == nil {
+		panic(r)
+	}
+	*err = newPanic(r)
+}
+
+// Try calls an action, returning its error, or an exception if it panics.
+// See `Recover` for how panics are converted to exceptions.
+func Try(action func() error) (err error) {
+	defer Recover(&err)
+	return action()
+}
+
+// newPanic returns an exception for a recovered panic value.
+func newPanic(r interface{}) Exception {
+	ex := &Ex{
+		class:   ErrPanic,
+		message: fmt.Sprintf("%v", r),
+		stack:   panicCallers(),
+	}
+	if typed, isError := r.(error); isError {
+		ex.inner = typed
+	}
+	return ex
+}
+
+// panicCallers returns the stack of a panic from a deferred function recovering it, starting at the frame
+// that panicked rather than in the runtime's panic handling.
+func panicCallers() *StackPointers {
+	stack := *callers(defaultStartDepth)
+	for index, pc := range stack {
+		if fn := runtime.FuncForPC(Frame(pc).pc()); fn == nil || fn.Name() != "runtime.gopanic" {
+			continue
+		}
+		// runtime errors, e.g. nil pointer dereferences, panic from runtime frames after the one that failed.
+		for index++; index < len(stack)-1; index++ {
+			if fn := runtime.FuncForPC(Frame(stack[index]).pc()); fn == nil || !strings.HasPrefix(fn.Name(), "runtime.") {
+				break
+			}
+		}
+		trimmed := stack[index:]
+		return &trimmed
+	}
+	return &stack
+}
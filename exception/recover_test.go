@@ -0,0 +1,70 @@
+package exception
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func recoverTestPanic(value interface{}) (err error) {
+	defer Recover(&err)
+	panic(value)
+}
+
+func TestRecover(t *testing.T) {
+	assert := assert.New(t)
+
+	err := recoverTestPanic("only a test")
+	assert.True(Is(err, ErrPanic))
+	assert.Equal("only a test", As(err).Message())
+	assert.Nil(errors.Unwrap(err))
+
+	frames := As(err).Stack().Frames()
+	assert.NotEmpty(frames)
+	assert.True(strings.HasSuffix(frames[0].Function, "exception.recoverTestPanic"), frames[0].Function)
+
+	err = recoverTestPanic(&os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist})
+	assert.True(Is(err, ErrPanic))
+	assert.True(errors.Is(err, os.ErrNotExist), "error panics should match with errors.Is")
+	var pathErr *os.PathError
+	assert.True(errors.As(err, &pathErr))
+	assert.Equal("foo", pathErr.Path)
+
+	assert.Nil(func() (err error) {
+		defer Recover(&err)
+		return nil
+	}())
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	assert := assert.New(t)
+
+	err := Try(func() error {
+		var values map[string]int
+		values["foo"] = 1
+		return nil
+	})
+	assert.True(Is(err, ErrPanic))
+	assert.Contains(As(err).Message(), "nil map")
+
+	frames := As(err).Stack().Frames()
+	assert.NotEmpty(frames)
+	assert.Contains(frames[0].Function, "TestRecoverRuntimeError")
+}
+
+func TestTry(t *testing.T) {
+	assert := assert.New(t)
+
+	errTest := Class("only a test")
+	assert.Nil(Try(func() error { return nil }))
+	assert.Equal(errTest, Try(func() error { return errTest }), "errors should be returned as is")
+
+	err := Try(func() error { panic(errTest) })
+	assert.True(Is(err, ErrPanic))
+	assert.True(Is(err, errTest))
+	assert.Contains(fmt.Sprintf("%+v", err), "TestTry")
+}
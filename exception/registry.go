@@ -0,0 +1,147 @@
+package exception
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+var (
+	// DefaultRegistry is the registry used by `Register`, `RegisterFunc` and `CodeOf`.
+	DefaultRegistry = NewRegistry()
+
+	// InternalErrorCode is the code of errors whose class isn't registered.
+	InternalErrorCode = ErrorCode{Code: "internal_error", StatusCode: http.StatusInternalServerError}
+)
+
+// Register registers a machine readable code and http status code for errors with a given class
+// with the default registry.
+func Register(class error, code string, statusCode int) {
+	DefaultRegistry.Register(class, code, statusCode)
+}
+
+// RegisterFunc registers a machine readable code and http status code for errors a func matches,
+// e.g. errors of a given type, with the default registry.
+func RegisterFunc(matches func(error) bool, code string, statusCode int) {
+	DefaultRegistry.RegisterFunc(matches, code, statusCode)
+}
+
+// CodeOf returns the code registered with the default registry for an error's class,
+// or `InternalErrorCode` if there isn't one.
+func CodeOf(err error) ErrorCode {
+	if code, ok := DefaultRegistry.Lookup(err); ok {
+		return code
+	}
+	return InternalErrorCode
+}
+
+// ErrorCode is a machine readable code and http status code for a class of errors.
+type ErrorCode struct {
+	Code       string `json:"code"`
+	StatusCode int    `json:"statusCode"`
+}
+
+// NewRegistry returns a new, empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Registry maps exception classes to machine readable codes and http status codes,
+// so errors can be reported consistently without matching on their messages.
+/*
+Register classes where they're declared:
+
+	const ErrUserNotFound exception.Class = "user not found"
+
+	func init() {
+		exception.Register(ErrUserNotFound, "user_not_found", http.StatusNotFound)
+	}
+
+Errors are matched by class, including errors that wrap them or have them as an inner error; the outermost
+match is used.
+*/
+type Registry struct {
+	lock    sync.RWMutex
+	entries []registryEntry
+}
+
+type registryEntry struct {
+	Class   error
+	Matches func(error) bool
+	Code    ErrorCode
+}
+
+// Register registers a code and http status code for errors with a given class,
+// replacing any code already registered for the class.
+func (r *Registry) Register(class error, code string, statusCode int) *Registry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry := registryEntry{Class: class, Code: ErrorCode{Code: code, StatusCode: statusCode}}
+	for index := range r.entries {
+		if r.entries[index].Class != nil && r.entries[index].Class == class {
+			r.entries[index] = entry
+			return r
+		}
+	}
+	r.entries = append(r.entries, entry)
+	return r
+}
+
+// RegisterFunc registers a code and http status code for errors a func matches, e.g. errors of a given type.
+func (r *Registry) RegisterFunc(matches func(error) bool, code string, statusCode int) *Registry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.entries = append(r.entries, registryEntry{Matches: matches, Code: ErrorCode{Code: code, StatusCode: statusCode}})
+	return r
+}
+
+// Lookup returns the code registered for an error, or false if there isn't one.
+// It checks the error's class, and then the errors it wraps and its inner errors.
+func (r *Registry) Lookup(err error) (ErrorCode, bool) {
+	code, _, ok := r.Match(err)
+	return code, ok
+}
+
+// Match returns the code registered for an error and the error that matched it, i.e. the error itself or the
+// first error it wraps or has as an inner error that has a registered class, or false if there isn't one.
+func (r *Registry) Match(err error) (ErrorCode, error, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for current := err; current != nil; current = errors.Unwrap(current) {
+		class := current
+		if typed, isTyped := current.(Exception); isTyped && typed.Class() != nil {
+			class = typed.Class()
+		}
+		for _, entry := range r.entries {
+			if entry.Matches != nil {
+				if entry.Matches(current) || (class != current && entry.Matches(class)) {
+					return entry.Code, current, true
+				}
+				continue
+			}
+			if class != current && errors.Is(class, entry.Class) {
+				return entry.Code, current, true
+			}
+			if class == current && isError(current, entry.Class) {
+				return entry.Code, current, true
+			}
+		}
+	}
+	return ErrorCode{}, nil, false
+}
+
+// isError returns if an error is a target, without checking the errors it wraps; those are checked
+// separately so the error that matched can be told apart from the errors that wrap it.
+func isError(err, target error) bool {
+	if reflect.TypeOf(target).Comparable() && err == target {
+		return true
+	}
+	if typed, isTyped := err.(interface{ Is(error) bool }); isTyped {
+		return typed.Is(target)
+	}
+	return false
+}
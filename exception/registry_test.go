@@ -0,0 +1,78 @@
+package exception
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	errNotFound := Class("not found")
+	errInvalid := Class("invalid")
+
+	registry := NewRegistry().
+		Register(errNotFound, "not_found", http.StatusNotFound).
+		Register(errInvalid, "bad", http.StatusBadRequest).
+		Register(errInvalid, "invalid", http.StatusBadRequest).
+		RegisterFunc(func(err error) bool {
+			_, ok := err.(*os.PathError)
+			return ok
+		}, "file_error", http.StatusServiceUnavailable)
+
+	code, ok := registry.Lookup(errNotFound)
+	assert.True(ok)
+	assert.Equal(ErrorCode{Code: "not_found", StatusCode: http.StatusNotFound}, code)
+
+	code, ok = registry.Lookup(New(errInvalid).WithMessage("name is required"))
+	assert.True(ok)
+	assert.Equal("invalid", code.Code, "registering a class again should replace it")
+
+	code, ok = registry.Lookup(fmt.Errorf("context: %w", New(errNotFound)))
+	assert.True(ok)
+	assert.Equal("not_found", code.Code)
+
+	code, ok = registry.Lookup(New("outer").WithInner(New(errInvalid).WithInner(errNotFound)))
+	assert.True(ok)
+	assert.Equal("invalid", code.Code, "the outermost registered class should be used")
+
+	_, err := os.Open("/this/file/does/not/exist")
+	code, ok = registry.Lookup(New(err))
+	assert.True(ok)
+	assert.Equal("file_error", code.Code)
+
+	_, ok = registry.Lookup(New("unregistered"))
+	assert.False(ok)
+	_, ok = registry.Lookup(nil)
+	assert.False(ok)
+
+	// the error that matched is returned, rather than the errors wrapping it.
+	inner := New(errNotFound).WithMessage("widget 1234")
+	code, matched, ok := registry.Match(fmt.Errorf("select from widgets on 10.0.0.1: %w", inner))
+	assert.True(ok)
+	assert.Equal("not_found", code.Code)
+	assert.Equal(inner, matched)
+
+	code, matched, ok = registry.Match(New("outer").WithInner(inner))
+	assert.True(ok)
+	assert.Equal("not_found", code.Code)
+	assert.Equal(inner, matched)
+
+	_, matched, ok = registry.Match(New("unregistered"))
+	assert.False(ok)
+	assert.Nil(matched)
+}
+
+func TestCodeOf(t *testing.T) {
+	assert := assert.New(t)
+
+	errConflict := Class("conflict")
+	Register(errConflict, "conflict", http.StatusConflict)
+
+	assert.Equal(ErrorCode{Code: "conflict", StatusCode: http.StatusConflict}, CodeOf(New(errConflict)))
+	assert.Equal(InternalErrorCode, CodeOf(New("unregistered")))
+}
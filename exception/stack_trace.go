@@ -6,6 +6,7 @@ import (
 	"io"
 	"path"
 	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -33,6 +34,14 @@ type StackTrace interface {
 	fmt.Formatter
 	Strings() []string
 	String() string
+	Frames() []StackFrame
+}
+
+// StackFrame is a stack frame, as it is serialized to json.
+type StackFrame struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
 }
 
 // StackPointers is stack of uintptr stack frames from innermost (newest) to outermost (oldest).
@@ -72,6 +81,15 @@ func (st StackPointers) Strings() []string {
 	return res
 }
 
+// Frames returns the stack frames with their file, line and function.
+func (st StackPointers) Frames() []StackFrame {
+	frames := make([]StackFrame, len(st))
+	for i, frame := range st {
+		frames[i] = Frame(frame).StackFrame()
+	}
+	return frames
+}
+
 // String returns a single string representation of the stack pointers.
 func (st StackPointers) String() string {
 	return fmt.Sprintf("%+v", st)
@@ -113,6 +131,29 @@ func (ss StackStrings) Strings() []string {
 	return []string(ss)
 }
 
+// Frames returns the stack frames, parsed from the strings if they're in the format `StackPointers.Strings()`
+// returns; other strings are returned as the function.
+func (ss StackStrings) Frames() []StackFrame {
+	frames := make([]StackFrame, len(ss))
+	for i, value := range ss {
+		frames[i].Function = value
+		parts := strings.SplitN(value, "\n\t", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		colon := strings.LastIndex(parts[1], ":")
+		if colon < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(parts[1][colon+1:])
+		if err != nil {
+			continue
+		}
+		frames[i] = StackFrame{File: parts[1][:colon], Line: line, Function: parts[0]}
+	}
+	return frames
+}
+
 // String returns a single string representation of the stack pointers.
 func (ss StackStrings) String() string {
 	return fmt.Sprintf("%+v", ss)
@@ -152,6 +193,16 @@ func (f Frame) line() int {
 	return line
 }
 
+// StackFrame returns the frame's file, relative to the compile time GOPATH, line and function.
+func (f Frame) StackFrame() StackFrame {
+	fn := runtime.FuncForPC(f.pc())
+	if fn == nil {
+		return StackFrame{File: "unknown"}
+	}
+	file, line := fn.FileLine(f.pc())
+	return StackFrame{File: trimGOPATH(fn.Name(), file), Line: line, Function: fn.Name()}
+}
+
 // Format formats the frame according to the fmt.Formatter interface.
 //
 //    %s    source file
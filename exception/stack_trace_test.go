@@ -43,6 +43,7 @@ func TestExceptionWithStackStrings(t *testing.T) {
 
 	values := ex.Decompose()
 	assert.NotEmpty(values["Stack"])
+	assert.Equal([]StackFrame{{Function: "foo"}, {Function: "bar"}, {Function: "baz"}}, ex.DecomposeStructured()["stack"])
 
 	assert.NotNil(ex.Stack())
 }
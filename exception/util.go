@@ -1,5 +1,7 @@
 package exception
 
+import "errors"
+
 // ErrClass returns the exception class or the error message.
 // This depends on if the err is itself an exception or not.
 func ErrClass(err error) string {
@@ -12,12 +14,10 @@ func ErrClass(err error) string {
 	return err.Error()
 }
 
-// Is is a helper function that returns if an error is an exception.
+// Is returns if an error, or any error it wraps, is a given cause or an exception with the cause as its class.
+// It is equivalent to `errors.Is`.
 func Is(err, cause error) bool {
-	if typed, isTyped := err.(Exception); isTyped {
-		return typed.Class() == cause
-	}
-	return err == cause
+	return errors.Is(err, cause)
 }
 
 // Inner returns an inner error if the error is an exception.
@@ -28,10 +28,15 @@ func Inner(err error) error {
 	return nil
 }
 
-// As is a helper method that returns an error as an exception.
+// As is a helper method that returns an error as an exception, or the first exception it wraps, e.g. with
+// `fmt.Errorf("...: %w", err)`, or nil if there isn't one.
 func As(err error) Exception {
 	if typed, typedOk := err.(Exception); typedOk {
 		return typed
 	}
+	var typed Exception
+	if errors.As(err, &typed) {
+		return typed
+	}
 	return nil
 }
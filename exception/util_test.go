@@ -1,6 +1,7 @@
 package exception
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
@@ -16,3 +17,25 @@ func TestIs(t *testing.T) {
 	assert.True(Is(ex, errInvalidSomething))
 	assert.True(Is(errInvalidSomething, errInvalidSomething))
 }
+
+func TestIsWrapped(t *testing.T) {
+	assert := assert.New(t)
+
+	errInvalidSomething := Class("invalid something")
+	errOther := Class("other")
+
+	assert.True(Is(fmt.Errorf("context: %w", New(errInvalidSomething)), errInvalidSomething))
+	assert.True(Is(New(errOther).WithInner(errInvalidSomething), errInvalidSomething), "causes should match")
+	assert.True(Is(New(errInvalidSomething), New(errInvalidSomething)), "exceptions with the same class should match")
+	assert.False(Is(New(errOther), errInvalidSomething))
+	assert.False(Is(nil, errInvalidSomething))
+}
+
+func TestAsWrapped(t *testing.T) {
+	assert := assert.New(t)
+
+	ex := New("only a test")
+	assert.Equal(ex, As(fmt.Errorf("context: %w", ex)))
+	assert.Nil(As(fmt.Errorf("not an exception")))
+	assert.Nil(As(nil))
+}
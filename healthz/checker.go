@@ -0,0 +1,75 @@
+package healthz
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ErrNotStarted is returned by a started check if the subsystem is not started.
+	ErrNotStarted exception.Class = "healthz: not started"
+	// ErrUnhealthyStatus is returned by an http check if the response status is not successful.
+	ErrUnhealthyStatus exception.Class = "healthz: unhealthy status code"
+	// ErrCheckPanic is returned if a check panics.
+	ErrCheckPanic exception.Class = "healthz: check panicked"
+)
+
+// Checker is a health check for a subsystem; it returns an error if the subsystem is unhealthy.
+// Checks should return promptly when the context is cancelled.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc is a function that implements Checker.
+type CheckerFunc func(ctx context.Context) error
+
+// Check implements Checker.
+func (cf CheckerFunc) Check(ctx context.Context) error {
+	return cf(ctx)
+}
+
+// Pinger is a type that can be pinged, e.g. a `*db.Connection`.
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Ping returns a check that pings a connection, e.g. a `*db.Connection`.
+func Ping(pinger Pinger) Checker {
+	return CheckerFunc(pinger.PingContext)
+}
+
+// Starter is a type that reports if it is started, e.g. a `*cron.JobManager`.
+type Starter interface {
+	IsStarted() bool
+}
+
+// Started returns a check that a subsystem is started, e.g. a `*cron.JobManager`.
+func Started(starter Starter) Checker {
+	return CheckerFunc(func(_ context.Context) error {
+		if !starter.IsStarted() {
+			return exception.New(ErrNotStarted)
+		}
+		return nil
+	})
+}
+
+// HTTPGet returns a check that a url responds to a get with a 2xx or 3xx status code.
+func HTTPGet(url string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return exception.New(err)
+		}
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return exception.New(err)
+		}
+		defer res.Body.Close()
+		if res.StatusCode >= http.StatusBadRequest {
+			return exception.New(ErrUnhealthyStatus).WithMessagef("%s returned %d", url, res.StatusCode)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,210 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// DefaultTimeout is the default timeout for each check.
+	DefaultTimeout = 5 * time.Second
+
+	// StatusOK is the status of a passing check or report.
+	StatusOK = "ok"
+	// StatusFailed is the status of a failing check or report.
+	StatusFailed = "failed"
+)
+
+// New returns a new, empty set of checks.
+func New() *Checks {
+	return &Checks{
+		timeout:  DefaultTimeout,
+		checkers: map[string]Checker{},
+	}
+}
+
+// Checks is a set of named checks that are run together.
+type Checks struct {
+	sync.Mutex
+	timeout  time.Duration
+	names    []string
+	checkers map[string]Checker
+}
+
+// WithTimeout sets the timeout for each check.
+func (c *Checks) WithTimeout(timeout time.Duration) *Checks {
+	c.timeout = timeout
+	return c
+}
+
+// Timeout returns the timeout for each check.
+func (c *Checks) Timeout() time.Duration {
+	return c.timeout
+}
+
+// Register adds a check by name, replacing any check with the same name.
+func (c *Checks) Register(name string, checker Checker) *Checks {
+	c.Lock()
+	defer c.Unlock()
+	if _, ok := c.checkers[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checkers[name] = checker
+	return c
+}
+
+// Names returns the names of the checks in the order they were registered.
+func (c *Checks) Names() []string {
+	c.Lock()
+	defer c.Unlock()
+	return append([]string(nil), c.names...)
+}
+
+// Run runs the checks concurrently, each with the timeout, and returns a report in registration order.
+func (c *Checks) Run(ctx context.Context) Report {
+	c.Lock()
+	names := append([]string(nil), c.names...)
+	checkers := make([]Checker, len(names))
+	for index, name := range names {
+		checkers[index] = c.checkers[name]
+	}
+	c.Unlock()
+
+	report := Report{Results: make([]Result, len(names))}
+	wg := sync.WaitGroup{}
+	wg.Add(len(names))
+	for index := range names {
+		go func(index int) {
+			defer wg.Done()
+			report.Results[index] = c.run(ctx, names[index], checkers[index])
+		}(index)
+	}
+	wg.Wait()
+	return report
+}
+
+func (c *Checks) run(ctx context.Context, name string, checker Checker) (result Result) {
+	result.Name = name
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// run the check in its own goroutine so checks that ignore the context still time out.
+	start := time.Now()
+	errs := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errs <- exception.New(ErrCheckPanic).WithMessagef("%v", r)
+			}
+		}()
+		errs <- checker.Check(ctx)
+	}()
+	select {
+	case result.Err = <-errs:
+	case <-ctx.Done():
+		result.Err = exception.New(ctx.Err())
+	}
+	result.Elapsed = time.Since(start)
+	return
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name    string
+	Elapsed time.Duration
+	Err     error
+}
+
+// OK returns if the check passed.
+func (r Result) OK() bool {
+	return r.Err == nil
+}
+
+// Status returns the status of the check.
+func (r Result) Status() string {
+	if r.OK() {
+		return StatusOK
+	}
+	return StatusFailed
+}
+
+// Report is the outcome of running a set of checks.
+type Report struct {
+	Results []Result
+}
+
+// OK returns if every check passed.
+func (r Report) OK() bool {
+	for _, result := range r.Results {
+		if !result.OK() {
+			return false
+		}
+	}
+	return true
+}
+
+// Status returns the overall status.
+func (r Report) Status() string {
+	if r.OK() {
+		return StatusOK
+	}
+	return StatusFailed
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r Report) MarshalJSON() ([]byte, error) {
+	type result struct {
+		Name      string  `json:"name"`
+		Status    string  `json:"status"`
+		ElapsedMS float64 `json:"elapsedMs"`
+		Error     string  `json:"error,omitempty"`
+	}
+	results := make([]result, len(r.Results))
+	for index, check := range r.Results {
+		results[index] = result{
+			Name:      check.Name,
+			Status:    check.Status(),
+			ElapsedMS: float64(check.Elapsed) / float64(time.Millisecond),
+		}
+		if check.Err != nil {
+			results[index].Error = errorMessage(check.Err)
+		}
+	}
+	return json.Marshal(struct {
+		Status string   `json:"status"`
+		Checks []result `json:"checks"`
+	}{Status: r.Status(), Checks: results})
+}
+
+// WriteText writes the report as text, one check per line, prefixed with `[+]` if it passed or `[-]` if it failed.
+func (r Report) WriteText(w io.Writer) error {
+	for _, check := range r.Results {
+		var err error
+		if check.OK() {
+			_, err = fmt.Fprintf(w, "[+] %s %s %v\n", check.Name, check.Status(), check.Elapsed)
+		} else {
+			_, err = fmt.Fprintf(w, "[-] %s %s %v: %s\n", check.Name, check.Status(), check.Elapsed, errorMessage(check.Err))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorMessage returns the class and message of an exception, or the error string.
+func errorMessage(err error) string {
+	if typed, ok := err.(exception.Exception); ok && len(typed.Message()) > 0 {
+		return typed.Error() + "; " + typed.Message()
+	}
+	return err.Error()
+}
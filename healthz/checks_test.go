@@ -0,0 +1,113 @@
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+type mockStarter bool
+
+func (ms mockStarter) IsStarted() bool {
+	return bool(ms)
+}
+
+type mockPinger struct {
+	err error
+}
+
+func (mp mockPinger) PingContext(_ context.Context) error {
+	return mp.err
+}
+
+func TestChecksRun(t *testing.T) {
+	assert := assert.New(t)
+
+	checks := New().
+		Register("db", Ping(mockPinger{})).
+		Register("cron", Started(mockStarter(false))).
+		Register("search", CheckerFunc(func(_ context.Context) error {
+			return fmt.Errorf("connection refused")
+		}))
+	assert.Equal([]string{"db", "cron", "search"}, checks.Names())
+
+	// re-registering a check replaces it in place.
+	checks.Register("cron", Started(mockStarter(true)))
+	assert.Equal([]string{"db", "cron", "search"}, checks.Names())
+
+	report := checks.Run(context.Background())
+	assert.False(report.OK())
+	assert.Equal(StatusFailed, report.Status())
+	assert.Len(report.Results, 3)
+	assert.Equal("db", report.Results[0].Name)
+	assert.True(report.Results[0].OK())
+	assert.True(report.Results[1].OK())
+	assert.Equal(StatusFailed, report.Results[2].Status())
+
+	contents, err := json.Marshal(report)
+	assert.Nil(err)
+	var decoded struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+	}
+	assert.Nil(json.Unmarshal(contents, &decoded))
+	assert.Equal(StatusFailed, decoded.Status)
+	assert.Equal("search", decoded.Checks[2].Name)
+	assert.Equal("connection refused", decoded.Checks[2].Error)
+
+	text := new(strings.Builder)
+	assert.Nil(report.WriteText(text))
+	lines := strings.Split(strings.TrimSpace(text.String()), "\n")
+	assert.Len(lines, 3)
+	assert.True(strings.HasPrefix(lines[0], "[+] db ok"))
+	assert.True(strings.HasPrefix(lines[2], "[-] search failed"))
+	assert.True(strings.HasSuffix(lines[2], ": connection refused"))
+}
+
+func TestChecksRunTimeoutAndPanic(t *testing.T) {
+	assert := assert.New(t)
+
+	checks := New().WithTimeout(10*time.Millisecond).
+		Register("slow", CheckerFunc(func(_ context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})).
+		Register("panics", CheckerFunc(func(_ context.Context) error {
+			panic("at the disco")
+		}))
+
+	start := time.Now()
+	report := checks.Run(context.Background())
+	assert.True(time.Since(start) < time.Second)
+	assert.False(report.OK())
+	assert.True(exception.Is(report.Results[0].Err, context.DeadlineExceeded))
+	assert.True(exception.Is(report.Results[1].Err, ErrCheckPanic))
+}
+
+func TestHTTPGet(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	assert.Nil(HTTPGet(server.URL + "/up").Check(context.Background()))
+	assert.True(exception.Is(HTTPGet(server.URL+"/down").Check(context.Background()), ErrUnhealthyStatus))
+}
@@ -0,0 +1,4 @@
+// Package healthz runs named health checks for the subsystems of a service, e.g. its database connection,
+// job manager, or external dependencies, and reports the status and latency of each.
+// `web.Healthz` exposes them at `/healthz` and `/readyz`.
+package healthz
@@ -5,6 +5,31 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ErrAuditEventInvalid is returned when an audit event is missing required fields.
+	ErrAuditEventInvalid exception.Class = "audit event invalid; missing required fields"
+)
+
+// Audit event fields that can be required.
+const (
+	AuditFieldPrincipal  = "principal"
+	AuditFieldVerb       = "verb"
+	AuditFieldNoun       = "noun"
+	AuditFieldSubject    = "subject"
+	AuditFieldProperty   = "property"
+	AuditFieldResult     = "result"
+	AuditFieldRemoteAddr = "remoteAddr"
+	AuditFieldUserAgent  = "ua"
+)
+
+var (
+	// DefaultAuditRequiredFields are the fields an audit event must have to be valid;
+	// who (principal) did what (verb) to what (noun) and with what result.
+	DefaultAuditRequiredFields = []string{AuditFieldPrincipal, AuditFieldVerb, AuditFieldNoun, AuditFieldResult}
 )
 
 // these are compile time assertions
@@ -33,7 +58,28 @@ func NewAuditEventListener(listener func(me *AuditEvent)) Listener {
 	}
 }
 
+// NewAuditEventValidatingListener returns a new audit event listener that only
+// passes along audit events with all the required fields set.
+// Invalid events are passed to `rejected` (if it is set) along with the validation error.
+// If no required fields are given, `DefaultAuditRequiredFields` are used.
+func NewAuditEventValidatingListener(listener func(*AuditEvent), rejected func(*AuditEvent, error), requiredFields ...string) Listener {
+	if len(requiredFields) == 0 {
+		requiredFields = DefaultAuditRequiredFields
+	}
+	return NewAuditEventListener(func(e *AuditEvent) {
+		if err := e.Validate(requiredFields...); err != nil {
+			if rejected != nil {
+				rejected(e, err)
+			}
+			return
+		}
+		listener(e)
+	})
+}
+
 // AuditEvent is a common type of event detailing a business action by a subject.
+// It records the actor (principal), the action (verb), the object (noun, subject and property),
+// the result and any additional metadata (extra).
 type AuditEvent struct {
 	*EventMeta
 
@@ -42,6 +88,7 @@ type AuditEvent struct {
 	noun          string
 	subject       string
 	property      string
+	result        string
 	remoteAddress string
 	userAgent     string
 	extra         map[string]string
@@ -132,6 +179,17 @@ func (e AuditEvent) Property() string {
 	return e.property
 }
 
+// WithResult sets the result, e.g. `success` or `denied`.
+func (e *AuditEvent) WithResult(result string) *AuditEvent {
+	e.result = result
+	return e
+}
+
+// Result returns the result.
+func (e AuditEvent) Result() string {
+	return e.result
+}
+
 // WithRemoteAddress sets the remote address.
 func (e *AuditEvent) WithRemoteAddress(remoteAddr string) *AuditEvent {
 	e.remoteAddress = remoteAddr
@@ -165,6 +223,25 @@ func (e AuditEvent) Extra() map[string]string {
 	return e.extra
 }
 
+// Validate returns an error if any of the required fields are unset.
+// If no required fields are given, `DefaultAuditRequiredFields` are used.
+func (e AuditEvent) Validate(requiredFields ...string) error {
+	if len(requiredFields) == 0 {
+		requiredFields = DefaultAuditRequiredFields
+	}
+	values := e.WriteJSON()
+	var missing []string
+	for _, field := range requiredFields {
+		if value, _ := values[field].(string); len(value) == 0 {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return exception.New(ErrAuditEventInvalid).WithMessagef("missing: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // WriteText implements TextWritable.
 func (e AuditEvent) WriteText(formatter TextFormatter, buf *bytes.Buffer) {
 	if len(e.principal) > 0 {
@@ -192,6 +269,11 @@ func (e AuditEvent) WriteText(formatter TextFormatter, buf *bytes.Buffer) {
 		buf.WriteString(e.property)
 		buf.WriteRune(RuneSpace)
 	}
+	if len(e.result) > 0 {
+		buf.WriteString(formatter.Colorize("Result:", ColorGray))
+		buf.WriteString(e.result)
+		buf.WriteRune(RuneSpace)
+	}
 	if len(e.remoteAddress) > 0 {
 		buf.WriteString(formatter.Colorize("Remote Addr:", ColorGray))
 		buf.WriteString(e.remoteAddress)
@@ -214,13 +296,14 @@ func (e AuditEvent) WriteText(formatter TextFormatter, buf *bytes.Buffer) {
 // WriteJSON implements JSONWritable.
 func (e AuditEvent) WriteJSON() JSONObj {
 	return JSONObj{
-		"principal":  e.principal,
-		"verb":       e.verb,
-		"noun":       e.noun,
-		"subject":    e.subject,
-		"property":   e.property,
-		"remoteAddr": e.remoteAddress,
-		"ua":         e.userAgent,
-		"extra":      e.extra,
+		AuditFieldPrincipal:  e.principal,
+		AuditFieldVerb:       e.verb,
+		AuditFieldNoun:       e.noun,
+		AuditFieldSubject:    e.subject,
+		AuditFieldProperty:   e.property,
+		AuditFieldResult:     e.result,
+		AuditFieldRemoteAddr: e.remoteAddress,
+		AuditFieldUserAgent:  e.userAgent,
+		"extra":              e.extra,
 	}
 }
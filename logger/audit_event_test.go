@@ -7,6 +7,7 @@ import (
 	"time"
 
 	assert "github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
 )
 
 func TestAuditEventListener(t *testing.T) {
@@ -120,3 +121,40 @@ func TestAuditEventProperties(t *testing.T) {
 	assert.Empty(ae.Extra())
 	assert.Equal("buzz", ae.WithExtra(map[string]string{"wuzz": "buzz"}).Extra()["wuzz"])
 }
+
+func TestAuditEventValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	valid := NewAuditEvent("principal", "verb").WithNoun("noun").WithResult("success")
+	assert.Nil(valid.Validate())
+	assert.Equal("success", valid.Result())
+
+	invalid := NewAuditEvent("principal", "verb")
+	err := invalid.Validate()
+	assert.NotNil(err)
+	assert.True(exception.Is(err, ErrAuditEventInvalid))
+	assert.Equal("missing: noun, result", exception.As(err).Message())
+
+	assert.Nil(invalid.Validate(AuditFieldPrincipal, AuditFieldVerb))
+}
+
+func TestAuditEventValidatingListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var accepted, rejected []*AuditEvent
+	var rejectedErr error
+	listener := NewAuditEventValidatingListener(func(e *AuditEvent) {
+		accepted = append(accepted, e)
+	}, func(e *AuditEvent, err error) {
+		rejected = append(rejected, e)
+		rejectedErr = err
+	})
+
+	listener(NewAuditEvent("principal", "verb").WithNoun("noun").WithResult("success"))
+	listener(NewAuditEvent("principal", "verb"))
+	listener(Messagef(Audit, "not an audit event"))
+
+	assert.Len(accepted, 1)
+	assert.Len(rejected, 1)
+	assert.True(exception.Is(rejectedErr, ErrAuditEventInvalid))
+}
@@ -1,12 +1,16 @@
 package logger
 
 import (
+	"net/url"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/env"
 )
 
 // NewConfigFromEnv returns a new config from the environment.
+// A profile is only applied if `LOG_PROFILE` is set.
 func NewConfigFromEnv() *Config {
 	var config Config
 	if err := env.Env().ReadInto(&config); err != nil {
@@ -17,6 +21,7 @@ func NewConfigFromEnv() *Config {
 
 // Config is the logger config.
 type Config struct {
+	Profile            string   `json:"profile,omitempty" yaml:"profile,omitempty" env:"LOG_PROFILE"`
 	Heading            string   `json:"heading,omitempty" yaml:"heading,omitempty" env:"LOG_HEADING"`
 	OutputFormat       string   `json:"outputFormat,omitempty" yaml:"outputFormat,omitempty" env:"LOG_FORMAT"`
 	Flags              []string `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_EVENTS,csv"`
@@ -24,9 +29,14 @@ type Config struct {
 	RecoverPanics      *bool    `json:"recoverPanics,omitempty" yaml:"recoverPanics,omitempty" env:"LOG_RECOVER"`
 	WriteQueueDepth    int      `json:"writeQueueDepth,omitempty" yaml:"writeQueueDepth,omitempty" env:"LOG_WRITE_QUEUE_DEPTH"`
 	ListenerQueueDepth int      `json:"listenerQueueDepth,omitempty" yaml:"listenerQueueDepth,omitempty" env:"LOG_LISTENER_QUEUE_DEPTH"`
+	ListenerWorkers    int      `json:"listenerWorkers,omitempty" yaml:"listenerWorkers,omitempty" env:"LOG_LISTENER_WORKERS"`
 
-	Text TextWriterConfig `json:"text,omitempty" yaml:"text,omitempty"`
-	JSON JSONWriterConfig `json:"json,omitempty" yaml:"json,omitempty"`
+	Text   TextWriterConfig   `json:"text,omitempty" yaml:"text,omitempty"`
+	JSON   JSONWriterConfig   `json:"json,omitempty" yaml:"json,omitempty"`
+	Logfmt LogfmtWriterConfig `json:"logfmt,omitempty" yaml:"logfmt,omitempty"`
+	Splunk SplunkWriterConfig `json:"splunk,omitempty" yaml:"splunk,omitempty"`
+	GELF   GELFWriterConfig   `json:"gelf,omitempty" yaml:"gelf,omitempty"`
+	OTLP   OTLPWriterConfig   `json:"otlp,omitempty" yaml:"otlp,omitempty"`
 }
 
 // GetHeading returns the writer heading.
@@ -37,11 +47,22 @@ func (c Config) GetHeading() string {
 	return ""
 }
 
+// GetProfile returns the profile, if one is set and known.
+func (c Config) GetProfile() (Profile, bool) {
+	if len(c.Profile) > 0 {
+		return GetProfile(c.Profile)
+	}
+	return Profile{}, false
+}
+
 // GetOutputFormat returns the output format.
 func (c Config) GetOutputFormat() OutputFormat {
 	if len(c.OutputFormat) > 0 {
 		return OutputFormat(strings.ToLower(c.OutputFormat))
 	}
+	if profile, ok := c.GetProfile(); ok {
+		return profile.OutputFormat
+	}
 	return OutputFormatText
 }
 
@@ -50,6 +71,9 @@ func (c Config) GetFlags() []string {
 	if len(c.Flags) > 0 {
 		return c.Flags
 	}
+	if profile, ok := c.GetProfile(); ok {
+		return AsStrings(profile.Flags...)
+	}
 	return AsStrings(DefaultFlags...)
 }
 
@@ -94,16 +118,53 @@ func (c Config) GetListenerQueueDepth(defaults ...int) int {
 	return DefaultListenerQueueDepth
 }
 
-// GetWriters returns the configured writers
+// GetListenerWorkers returns the number of workers that call listeners.
+func (c Config) GetListenerWorkers(defaults ...int) int {
+	if c.ListenerWorkers > 0 {
+		return c.ListenerWorkers
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultListenerWorkers
+}
+
+// GetWriters returns the configured writers.
+// A splunk writer is included (and started) if the splunk config is set.
+// A gelf writer is included if the gelf config is set.
+// An otlp writer is included (and started) if the otlp config is set.
+// Started writers are stopped when the logger they're added to is closed; stop them yourself otherwise.
 func (c Config) GetWriters() []Writer {
+	var writers []Writer
 	switch c.GetOutputFormat() {
 	case OutputFormatJSON:
-		return []Writer{NewJSONWriterFromConfig(&c.JSON)}
+		writers = []Writer{NewJSONWriterFromConfig(&c.JSON)}
 	case OutputFormatText:
-		return []Writer{NewTextWriterFromConfig(&c.Text)}
+		writers = []Writer{c.getTextWriter()}
+	case OutputFormatLogfmt:
+		writers = []Writer{NewLogfmtWriterFromConfig(&c.Logfmt)}
 	default:
-		return []Writer{NewTextWriterFromConfig(&c.Text)}
+		writers = []Writer{c.getTextWriter()}
+	}
+	if !c.Splunk.IsZero() {
+		writers = append(writers, NewSplunkWriterFromConfig(&c.Splunk).Start())
+	}
+	if !c.GELF.IsZero() {
+		writers = append(writers, NewGELFWriterFromConfig(&c.GELF))
+	}
+	if !c.OTLP.IsZero() {
+		writers = append(writers, NewOTLPWriterFromConfig(&c.OTLP).Start())
 	}
+	return writers
+}
+
+// getTextWriter returns the text writer, using the profile color setting if color is not explicitly set.
+func (c Config) getTextWriter() *TextWriter {
+	writer := NewTextWriterFromConfig(&c.Text)
+	if profile, ok := c.GetProfile(); ok && c.Text.UseColor == nil {
+		writer.WithUseColor(profile.UseColor)
+	}
+	return writer
 }
 
 // NewTextWriterConfigFromEnv returns a new text writer config from the environment.
@@ -117,10 +178,11 @@ func NewTextWriterConfigFromEnv() *TextWriterConfig {
 
 // TextWriterConfig is the config for a text writer.
 type TextWriterConfig struct {
-	ShowHeadings  *bool  `json:"showHeadings,omitempty" yaml:"showHeadings,omitempty" env:"LOG_SHOW_HEADINGS"`
-	ShowTimestamp *bool  `json:"showTimestamp,omitempty" yaml:"showTimestamp,omitempty" env:"LOG_SHOW_TIMESTAMP"`
-	UseColor      *bool  `json:"useColor,omitempty" yaml:"useColor,omitempty" env:"LOG_USE_COLOR"`
-	TimeFormat    string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty" env:"LOG_TIME_FORMAT"`
+	ShowHeadings   *bool  `json:"showHeadings,omitempty" yaml:"showHeadings,omitempty" env:"LOG_SHOW_HEADINGS"`
+	ShowTimestamp  *bool  `json:"showTimestamp,omitempty" yaml:"showTimestamp,omitempty" env:"LOG_SHOW_TIMESTAMP"`
+	UseColor       *bool  `json:"useColor,omitempty" yaml:"useColor,omitempty" env:"LOG_USE_COLOR"`
+	TimeFormat     string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty" env:"LOG_TIME_FORMAT"`
+	EscapeNewlines *bool  `json:"escapeNewlines,omitempty" yaml:"escapeNewlines,omitempty" env:"LOG_ESCAPE_NEWLINES"`
 }
 
 // GetShowHeadings returns a field value or a default.
@@ -167,6 +229,17 @@ func (twc TextWriterConfig) GetTimeFormat(defaults ...string) string {
 	return DefaultTextTimeFormat
 }
 
+// GetEscapeNewlines returns a field value or a default.
+func (twc TextWriterConfig) GetEscapeNewlines(defaults ...bool) bool {
+	if twc.EscapeNewlines != nil {
+		return *twc.EscapeNewlines
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultTextWriterEscapeNewlines
+}
+
 // NewJSONWriterConfigFromEnv returns a new json writer config from the environment.
 func NewJSONWriterConfigFromEnv() *JSONWriterConfig {
 	var config JSONWriterConfig
@@ -191,3 +264,537 @@ func (jwc JSONWriterConfig) GetPretty(defaults ...bool) bool {
 	}
 	return DefaultJSONWriterPretty
 }
+
+// NewLogfmtWriterConfigFromEnv returns a new logfmt writer config from the environment.
+func NewLogfmtWriterConfigFromEnv() *LogfmtWriterConfig {
+	var config LogfmtWriterConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// LogfmtWriterConfig is the config for a logfmt writer.
+type LogfmtWriterConfig struct {
+	ShowTimestamp *bool  `json:"showTimestamp,omitempty" yaml:"showTimestamp,omitempty" env:"LOG_SHOW_TIMESTAMP"`
+	TimeFormat    string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty" env:"LOG_TIME_FORMAT"`
+}
+
+// GetShowTimestamp returns a field value or a default.
+func (lwc LogfmtWriterConfig) GetShowTimestamp(defaults ...bool) bool {
+	if lwc.ShowTimestamp != nil {
+		return *lwc.ShowTimestamp
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultTextWriterShowTimestamp
+}
+
+// GetTimeFormat returns a field value or a default.
+func (lwc LogfmtWriterConfig) GetTimeFormat(defaults ...string) string {
+	if len(lwc.TimeFormat) > 0 {
+		return lwc.TimeFormat
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultTextTimeFormat
+}
+
+// NewSplunkWriterConfigFromEnv returns a new splunk writer config from the environment.
+func NewSplunkWriterConfigFromEnv() *SplunkWriterConfig {
+	var config SplunkWriterConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// SplunkWriterConfig is the config for a splunk http event collector writer.
+type SplunkWriterConfig struct {
+	URL           string        `json:"url,omitempty" yaml:"url,omitempty" env:"LOG_SPLUNK_URL"`
+	Token         string        `json:"token,omitempty" yaml:"token,omitempty" env:"LOG_SPLUNK_TOKEN"`
+	Host          string        `json:"host,omitempty" yaml:"host,omitempty" env:"LOG_SPLUNK_HOST"`
+	Index         string        `json:"index,omitempty" yaml:"index,omitempty" env:"LOG_SPLUNK_INDEX"`
+	Source        string        `json:"source,omitempty" yaml:"source,omitempty" env:"LOG_SPLUNK_SOURCE"`
+	SourceType    string        `json:"sourceType,omitempty" yaml:"sourceType,omitempty" env:"LOG_SPLUNK_SOURCE_TYPE"`
+	BatchSize     int           `json:"batchSize,omitempty" yaml:"batchSize,omitempty" env:"LOG_SPLUNK_BATCH_SIZE"`
+	FlushInterval time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"LOG_SPLUNK_FLUSH_INTERVAL"`
+	MaxRetries    *int          `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" env:"LOG_SPLUNK_MAX_RETRIES"`
+	Gzip          *bool         `json:"gzip,omitempty" yaml:"gzip,omitempty" env:"LOG_SPLUNK_GZIP"`
+	Timeout       time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" env:"LOG_SPLUNK_TIMEOUT"`
+}
+
+// IsZero returns if the config is unset, i.e. if the url or token are missing.
+func (swc SplunkWriterConfig) IsZero() bool {
+	return len(swc.URL) == 0 || len(swc.Token) == 0
+}
+
+// GetURL returns the collector url.
+func (swc SplunkWriterConfig) GetURL() string {
+	return swc.URL
+}
+
+// GetToken returns the collector token.
+func (swc SplunkWriterConfig) GetToken() string {
+	return swc.Token
+}
+
+// GetHost returns a field value or a default.
+func (swc SplunkWriterConfig) GetHost(defaults ...string) string {
+	if len(swc.Host) > 0 {
+		return swc.Host
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+// GetIndex returns the index.
+func (swc SplunkWriterConfig) GetIndex() string {
+	return swc.Index
+}
+
+// GetSource returns the source.
+func (swc SplunkWriterConfig) GetSource() string {
+	return swc.Source
+}
+
+// GetSourceType returns the source type.
+func (swc SplunkWriterConfig) GetSourceType() string {
+	return swc.SourceType
+}
+
+// GetBatchSize returns a field value or a default.
+func (swc SplunkWriterConfig) GetBatchSize(defaults ...int) int {
+	if swc.BatchSize > 0 {
+		return swc.BatchSize
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSplunkBatchSize
+}
+
+// GetFlushInterval returns a field value or a default.
+func (swc SplunkWriterConfig) GetFlushInterval(defaults ...time.Duration) time.Duration {
+	if swc.FlushInterval > 0 {
+		return swc.FlushInterval
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSplunkFlushInterval
+}
+
+// GetMaxRetries returns a field value or a default.
+func (swc SplunkWriterConfig) GetMaxRetries(defaults ...int) int {
+	if swc.MaxRetries != nil {
+		return *swc.MaxRetries
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSplunkMaxRetries
+}
+
+// GetGzip returns a field value or a default.
+func (swc SplunkWriterConfig) GetGzip(defaults ...bool) bool {
+	if swc.Gzip != nil {
+		return *swc.Gzip
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSplunkGzip
+}
+
+// GetTimeout returns a field value or a default.
+func (swc SplunkWriterConfig) GetTimeout(defaults ...time.Duration) time.Duration {
+	if swc.Timeout > 0 {
+		return swc.Timeout
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSplunkTimeout
+}
+
+// NewGELFWriterConfigFromEnv returns a new gelf writer config from the environment.
+func NewGELFWriterConfigFromEnv() *GELFWriterConfig {
+	var config GELFWriterConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// GELFWriterConfig is the config for a gelf (graylog) writer.
+type GELFWriterConfig struct {
+	Addr                  string        `json:"addr,omitempty" yaml:"addr,omitempty" env:"LOG_GELF_ADDR"`
+	Network               string        `json:"network,omitempty" yaml:"network,omitempty" env:"LOG_GELF_NETWORK"`
+	Host                  string        `json:"host,omitempty" yaml:"host,omitempty" env:"LOG_GELF_HOST"`
+	ChunkSize             int           `json:"chunkSize,omitempty" yaml:"chunkSize,omitempty" env:"LOG_GELF_CHUNK_SIZE"`
+	Compress              *bool         `json:"compress,omitempty" yaml:"compress,omitempty" env:"LOG_GELF_COMPRESS"`
+	DialTimeout           time.Duration `json:"dialTimeout,omitempty" yaml:"dialTimeout,omitempty" env:"LOG_GELF_DIAL_TIMEOUT"`
+	TLS                   *bool         `json:"tls,omitempty" yaml:"tls,omitempty" env:"LOG_GELF_TLS"`
+	TLSInsecureSkipVerify *bool         `json:"tlsInsecureSkipVerify,omitempty" yaml:"tlsInsecureSkipVerify,omitempty" env:"LOG_GELF_TLS_INSECURE_SKIP_VERIFY"`
+}
+
+// IsZero returns if the config is unset, i.e. if the address is missing.
+func (gwc GELFWriterConfig) IsZero() bool {
+	return len(gwc.Addr) == 0
+}
+
+// GetAddr returns the address.
+func (gwc GELFWriterConfig) GetAddr() string {
+	return gwc.Addr
+}
+
+// GetNetwork returns a field value or a default.
+func (gwc GELFWriterConfig) GetNetwork(defaults ...string) string {
+	if len(gwc.Network) > 0 {
+		return gwc.Network
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultGELFNetwork
+}
+
+// GetHost returns a field value or a default.
+func (gwc GELFWriterConfig) GetHost(defaults ...string) string {
+	if len(gwc.Host) > 0 {
+		return gwc.Host
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+// GetChunkSize returns a field value or a default.
+func (gwc GELFWriterConfig) GetChunkSize(defaults ...int) int {
+	if gwc.ChunkSize > 0 {
+		return gwc.ChunkSize
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultGELFChunkSize
+}
+
+// GetCompress returns a field value or a default.
+func (gwc GELFWriterConfig) GetCompress(defaults ...bool) bool {
+	if gwc.Compress != nil {
+		return *gwc.Compress
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultGELFCompress
+}
+
+// GetDialTimeout returns a field value or a default.
+func (gwc GELFWriterConfig) GetDialTimeout(defaults ...time.Duration) time.Duration {
+	if gwc.DialTimeout > 0 {
+		return gwc.DialTimeout
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultGELFDialTimeout
+}
+
+// GetTLS returns a field value or a default.
+func (gwc GELFWriterConfig) GetTLS(defaults ...bool) bool {
+	if gwc.TLS != nil {
+		return *gwc.TLS
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return false
+}
+
+// GetTLSInsecureSkipVerify returns a field value or a default.
+func (gwc GELFWriterConfig) GetTLSInsecureSkipVerify(defaults ...bool) bool {
+	if gwc.TLSInsecureSkipVerify != nil {
+		return *gwc.TLSInsecureSkipVerify
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return false
+}
+
+// NewKafkaWriterConfigFromEnv returns a new kafka writer config from the environment.
+func NewKafkaWriterConfigFromEnv() *KafkaWriterConfig {
+	var config KafkaWriterConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// KafkaWriterConfig is the config for a kafka writer.
+// It is not part of `Config` as the writer also requires a producer.
+type KafkaWriterConfig struct {
+	Topic         string        `json:"topic,omitempty" yaml:"topic,omitempty" env:"LOG_KAFKA_TOPIC"`
+	Compression   string        `json:"compression,omitempty" yaml:"compression,omitempty" env:"LOG_KAFKA_COMPRESSION"`
+	PartitionKey  string        `json:"partitionKey,omitempty" yaml:"partitionKey,omitempty" env:"LOG_KAFKA_PARTITION_KEY"`
+	BatchSize     int           `json:"batchSize,omitempty" yaml:"batchSize,omitempty" env:"LOG_KAFKA_BATCH_SIZE"`
+	FlushInterval time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"LOG_KAFKA_FLUSH_INTERVAL"`
+	MaxRetries    *int          `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty" env:"LOG_KAFKA_MAX_RETRIES"`
+}
+
+// IsZero returns if the config is unset, i.e. if the topic is missing.
+func (kwc KafkaWriterConfig) IsZero() bool {
+	return len(kwc.Topic) == 0
+}
+
+// GetTopic returns the topic.
+func (kwc KafkaWriterConfig) GetTopic() string {
+	return kwc.Topic
+}
+
+// GetCompression returns a field value or a default.
+func (kwc KafkaWriterConfig) GetCompression(defaults ...KafkaCompression) KafkaCompression {
+	if len(kwc.Compression) > 0 {
+		return KafkaCompression(strings.ToLower(kwc.Compression))
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultKafkaCompression
+}
+
+// GetPartitionKey returns the partition key setting, one of `none`, `flag` or `label:<name>`.
+func (kwc KafkaWriterConfig) GetPartitionKey() string {
+	return kwc.PartitionKey
+}
+
+// GetBatchSize returns a field value or a default.
+func (kwc KafkaWriterConfig) GetBatchSize(defaults ...int) int {
+	if kwc.BatchSize > 0 {
+		return kwc.BatchSize
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultKafkaBatchSize
+}
+
+// GetFlushInterval returns a field value or a default.
+func (kwc KafkaWriterConfig) GetFlushInterval(defaults ...time.Duration) time.Duration {
+	if kwc.FlushInterval > 0 {
+		return kwc.FlushInterval
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultKafkaFlushInterval
+}
+
+// GetMaxRetries returns a field value or a default.
+func (kwc KafkaWriterConfig) GetMaxRetries(defaults ...int) int {
+	if kwc.MaxRetries != nil {
+		return *kwc.MaxRetries
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultKafkaMaxRetries
+}
+
+// NewSentryConfigFromEnv returns a new sentry config from the environment.
+func NewSentryConfigFromEnv() *SentryConfig {
+	var config SentryConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// SentryConfig is the config for a sentry client.
+type SentryConfig struct {
+	DSN         string   `json:"dsn,omitempty" yaml:"dsn,omitempty" env:"SENTRY_DSN"`
+	Environment string   `json:"environment,omitempty" yaml:"environment,omitempty" env:"SENTRY_ENVIRONMENT"`
+	Release     string   `json:"release,omitempty" yaml:"release,omitempty" env:"SENTRY_RELEASE"`
+	SampleRate  *float64 `json:"sampleRate,omitempty" yaml:"sampleRate,omitempty" env:"SENTRY_SAMPLE_RATE"`
+	RateLimit   *int     `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty" env:"SENTRY_RATE_LIMIT"`
+}
+
+// IsZero returns if the config is unset.
+func (sc SentryConfig) IsZero() bool {
+	return len(sc.DSN) == 0
+}
+
+// GetDSN returns the dsn.
+func (sc SentryConfig) GetDSN() string {
+	return sc.DSN
+}
+
+// GetEnvironment returns a field value or a default.
+func (sc SentryConfig) GetEnvironment(defaults ...string) string {
+	if len(sc.Environment) > 0 {
+		return sc.Environment
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return env.Env().String(env.VarServiceEnv)
+}
+
+// GetRelease returns the release.
+func (sc SentryConfig) GetRelease() string {
+	return sc.Release
+}
+
+// GetSampleRate returns a field value or a default.
+func (sc SentryConfig) GetSampleRate(defaults ...float64) float64 {
+	if sc.SampleRate != nil {
+		return *sc.SampleRate
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSentrySampleRate
+}
+
+// GetRateLimit returns a field value or a default.
+func (sc SentryConfig) GetRateLimit(defaults ...int) int {
+	if sc.RateLimit != nil {
+		return *sc.RateLimit
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultSentryRateLimit
+}
+
+// NewOTLPWriterConfigFromEnv returns a new otlp writer config from the environment.
+func NewOTLPWriterConfigFromEnv() *OTLPWriterConfig {
+	var config OTLPWriterConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		panic(err)
+	}
+	return &config
+}
+
+// OTLPWriterConfig is the config for an opentelemetry (otlp) log exporter.
+// It reads the standard opentelemetry exporter environment variables.
+type OTLPWriterConfig struct {
+	// Endpoint is the full logs endpoint; for the http protocols it is used as is.
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty" env:"OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"`
+	// BaseEndpoint is the collector base endpoint; for the http protocols `/v1/logs` is appended.
+	BaseEndpoint       string        `json:"baseEndpoint,omitempty" yaml:"baseEndpoint,omitempty" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	Protocol           string        `json:"protocol,omitempty" yaml:"protocol,omitempty" env:"OTEL_EXPORTER_OTLP_PROTOCOL"`
+	Headers            []string      `json:"headers,omitempty" yaml:"headers,omitempty" env:"OTEL_EXPORTER_OTLP_HEADERS,csv"`
+	ResourceAttributes []string      `json:"resourceAttributes,omitempty" yaml:"resourceAttributes,omitempty" env:"OTEL_RESOURCE_ATTRIBUTES,csv"`
+	ServiceName        string        `json:"serviceName,omitempty" yaml:"serviceName,omitempty" env:"OTEL_SERVICE_NAME"`
+	BatchSize          int           `json:"batchSize,omitempty" yaml:"batchSize,omitempty" env:"LOG_OTLP_BATCH_SIZE"`
+	FlushInterval      time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"LOG_OTLP_FLUSH_INTERVAL"`
+	Timeout            time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty" env:"LOG_OTLP_TIMEOUT"`
+}
+
+// IsZero returns if the config is unset, i.e. if both endpoints are missing.
+func (owc OTLPWriterConfig) IsZero() bool {
+	return len(owc.Endpoint) == 0 && len(owc.BaseEndpoint) == 0
+}
+
+// GetProtocol returns a field value or a default.
+func (owc OTLPWriterConfig) GetProtocol(defaults ...string) string {
+	if len(owc.Protocol) > 0 {
+		return strings.ToLower(owc.Protocol)
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultOTLPProtocol
+}
+
+// GetEndpoint returns the logs endpoint.
+// If only the base endpoint is set, the http protocols append the logs path to it.
+func (owc OTLPWriterConfig) GetEndpoint() string {
+	if len(owc.Endpoint) > 0 {
+		return owc.Endpoint
+	}
+	if owc.GetProtocol() == OTLPProtocolGRPC || len(owc.BaseEndpoint) == 0 {
+		return owc.BaseEndpoint
+	}
+	return strings.TrimSuffix(owc.BaseEndpoint, "/") + OTLPLogsPath
+}
+
+// GetHeaders returns the headers parsed from `key=value` pairs.
+func (owc OTLPWriterConfig) GetHeaders() map[string]string {
+	return parseOTLPKeyValues(owc.Headers)
+}
+
+// GetResourceAttributes returns the resource attributes parsed from `key=value` pairs.
+func (owc OTLPWriterConfig) GetResourceAttributes() map[string]string {
+	return parseOTLPKeyValues(owc.ResourceAttributes)
+}
+
+// GetServiceName returns the service name.
+func (owc OTLPWriterConfig) GetServiceName() string {
+	return owc.ServiceName
+}
+
+// GetBatchSize returns a field value or a default.
+func (owc OTLPWriterConfig) GetBatchSize(defaults ...int) int {
+	if owc.BatchSize > 0 {
+		return owc.BatchSize
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultOTLPBatchSize
+}
+
+// GetFlushInterval returns a field value or a default.
+func (owc OTLPWriterConfig) GetFlushInterval(defaults ...time.Duration) time.Duration {
+	if owc.FlushInterval > 0 {
+		return owc.FlushInterval
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultOTLPFlushInterval
+}
+
+// GetTimeout returns a field value or a default.
+func (owc OTLPWriterConfig) GetTimeout(defaults ...time.Duration) time.Duration {
+	if owc.Timeout > 0 {
+		return owc.Timeout
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultOTLPTimeout
+}
+
+// parseOTLPKeyValues parses `key=value` pairs, skipping malformed pairs.
+func parseOTLPKeyValues(pairs []string) map[string]string {
+	output := map[string]string{}
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.TrimSpace(parts[0])
+		if len(key) == 0 {
+			continue
+		}
+		value, err := url.QueryUnescape(strings.TrimSpace(parts[1]))
+		if err != nil {
+			value = strings.TrimSpace(parts[1])
+		}
+		output[key] = value
+	}
+	return output
+}
@@ -33,6 +33,10 @@ func TestConfigProperties(t *testing.T) {
 	assert.Equal(DefaultWriteQueueDepth, Config{}.GetWriteQueueDepth())
 	assert.Equal(DefaultWriteQueueDepth>>1, Config{}.GetWriteQueueDepth(DefaultWriteQueueDepth>>1))
 	assert.Equal(DefaultWriteQueueDepth>>2, Config{WriteQueueDepth: DefaultWriteQueueDepth >> 2}.GetWriteQueueDepth(DefaultWriteQueueDepth>>1))
+
+	assert.Equal(DefaultListenerWorkers, Config{}.GetListenerWorkers())
+	assert.Equal(2, Config{}.GetListenerWorkers(2))
+	assert.Equal(4, Config{ListenerWorkers: 4}.GetListenerWorkers(2))
 }
 
 func TestNewConfigFlags(t *testing.T) {
@@ -156,6 +160,53 @@ func TestNewConfigFromEnvWithVars(t *testing.T) {
 
 }
 
+func TestNewConfigFromEnvProfile(t *testing.T) {
+	assert := assert.New(t)
+
+	env.SetEnv(env.Vars{
+		env.VarServiceEnv: env.ServiceEnvProd,
+	})
+	defer env.Restore()
+
+	cfg := NewConfigFromEnv()
+	assert.Empty(cfg.Profile, "the profile shouldn't be picked from the service env")
+	assert.Equal(OutputFormatText, cfg.GetOutputFormat())
+	assert.Equal(AsStrings(DefaultFlags...), cfg.GetFlags())
+
+	env.Env().Set(EnvVarProfile, ProfileNameProduction)
+	cfg = NewConfigFromEnv()
+	assert.Equal(ProfileNameProduction, cfg.Profile)
+	assert.Equal(OutputFormatJSON, cfg.GetOutputFormat())
+
+	env.Env().Set(EnvVarProfile, ProfileNameDevelopment)
+	env.Env().Set(EnvVarFormat, string(OutputFormatLogfmt))
+	cfg = NewConfigFromEnv()
+	assert.Equal(OutputFormatLogfmt, cfg.GetOutputFormat(), "explicit settings take precedence")
+	assert.Any(cfg.GetFlags(), func(v Any) bool {
+		return v.(string) == "debug"
+	})
+}
+
+func TestConfigProfileWriters(t *testing.T) {
+	assert := assert.New(t)
+
+	config := &Config{Profile: ProfileNameTest}
+	writers := config.GetWriters()
+	assert.Len(writers, 1)
+	assert.False(writers[0].(*TextWriter).UseColor())
+	assert.Equal([]string{"fatal", "error", "warning"}, config.GetFlags())
+
+	config.Text.UseColor = b(true)
+	assert.True(config.GetWriters()[0].(*TextWriter).UseColor())
+
+	config = &Config{Profile: "not-a-profile"}
+	assert.Equal(OutputFormatText, config.GetOutputFormat())
+	assert.Equal(AsStrings(DefaultFlags...), config.GetFlags())
+
+	assert.Equal(ProfileNameTest, ProfileNameForServiceEnv(env.ServiceEnvCI))
+	assert.Empty(ProfileNameForServiceEnv(""))
+}
+
 func TestGetWritersWithOutputFormat(t *testing.T) {
 	assert := assert.New(t)
 
@@ -33,6 +33,70 @@ const (
 	DefaultTextWriterShowHeadings = true
 	// DefaultTextWriterShowTimestamp is a default setting for writers.
 	DefaultTextWriterShowTimestamp = true
+	// DefaultTextWriterEscapeNewlines is a default setting for writers.
+	DefaultTextWriterEscapeNewlines = false
+	// DefaultTextWriterIndent is the prefix for continuation lines of multi-line messages.
+	DefaultTextWriterIndent = "\t"
+
+	// DefaultDedupWindow is the default window repeated errors are collapsed over.
+	DefaultDedupWindow = time.Minute
+	// DefaultDedupSweepInterval is the default interval elapsed dedup windows are checked for, and their summaries written, on.
+	DefaultDedupSweepInterval = time.Second
+
+	// DefaultSplunkBatchSize is the default number of events that triggers a splunk flush.
+	DefaultSplunkBatchSize = 100
+	// DefaultSplunkFlushInterval is the default interval pending splunk events are flushed on.
+	DefaultSplunkFlushInterval = 5 * time.Second
+	// DefaultSplunkMaxRetries is the default number of times a failed splunk post is retried.
+	DefaultSplunkMaxRetries = 3
+	// DefaultSplunkRetryBackoff is the default base delay between splunk retries.
+	DefaultSplunkRetryBackoff = 500 * time.Millisecond
+	// DefaultSplunkGzip is the default setting for gzipping splunk request bodies.
+	DefaultSplunkGzip = true
+	// DefaultSplunkTimeout is the default splunk request timeout.
+	DefaultSplunkTimeout = 10 * time.Second
+
+	// DefaultKafkaCompression is the default kafka compression codec.
+	DefaultKafkaCompression = KafkaCompressionGzip
+	// DefaultKafkaBatchSize is the default number of events that triggers a kafka flush.
+	DefaultKafkaBatchSize = 100
+	// DefaultKafkaFlushInterval is the default interval pending kafka events are flushed on.
+	DefaultKafkaFlushInterval = time.Second
+	// DefaultKafkaMaxRetries is the default number of times a failed kafka produce is retried.
+	DefaultKafkaMaxRetries = 3
+	// DefaultKafkaRetryBackoff is the default base delay between kafka retries.
+	DefaultKafkaRetryBackoff = 500 * time.Millisecond
+
+	// DefaultGELFNetwork is the default gelf network.
+	DefaultGELFNetwork = GELFNetworkUDP
+	// DefaultGELFChunkSize is the default maximum gelf udp datagram size.
+	DefaultGELFChunkSize = 1420
+	// DefaultGELFCompress is the default setting for gzipping gelf udp messages.
+	DefaultGELFCompress = true
+	// DefaultGELFDialTimeout is the default gelf dial timeout.
+	DefaultGELFDialTimeout = 5 * time.Second
+
+	// DefaultOTLPProtocol is the default otlp protocol.
+	DefaultOTLPProtocol = OTLPProtocolHTTPProtobuf
+	// DefaultOTLPBatchSize is the default number of events that triggers an otlp export.
+	DefaultOTLPBatchSize = 512
+	// DefaultOTLPFlushInterval is the default interval pending otlp events are exported on.
+	DefaultOTLPFlushInterval = time.Second
+	// DefaultOTLPMaxRetries is the default number of times a failed otlp export is retried.
+	DefaultOTLPMaxRetries = 3
+	// DefaultOTLPRetryBackoff is the default base delay between otlp retries.
+	DefaultOTLPRetryBackoff = 500 * time.Millisecond
+	// DefaultOTLPTimeout is the default otlp export timeout.
+	DefaultOTLPTimeout = 10 * time.Second
+
+	// DefaultSentrySampleRate is the default fraction of error events sent to sentry.
+	DefaultSentrySampleRate = 1.0
+	// DefaultSentryRateLimit is the default maximum number of events sent to sentry per window.
+	DefaultSentryRateLimit = 60
+	// DefaultSentryRateLimitWindow is the default sentry rate limit window.
+	DefaultSentryRateLimitWindow = time.Minute
+	// DefaultSentryTimeout is the default sentry request timeout.
+	DefaultSentryTimeout = 5 * time.Second
 )
 
 var (
@@ -53,4 +117,7 @@ const (
 	// DefaultListenerQueueDepth is the default depth per listener to queue work.
 	// It's currently set to 256k entries.
 	DefaultListenerQueueDepth = 1 << 10
+
+	// DefaultFatalFlushTimeout is the default time a fatal event waits for queued events and buffered writers to be flushed.
+	DefaultFatalFlushTimeout = 5 * time.Second
 )
@@ -0,0 +1,49 @@
+package logger
+
+import "time"
+
+// newDedup returns a new set of dedup windows.
+func newDedup(window time.Duration) *dedup {
+	return &dedup{
+		window: window,
+		seen:   map[string]*dedupEntry{},
+	}
+}
+
+// dedup tracks error events by fingerprint, counting repeats within a window.
+// It isn't safe for concurrent use; callers hold their own lock.
+type dedup struct {
+	window time.Duration
+	seen   map[string]*dedupEntry
+}
+
+type dedupEntry struct {
+	first   *ErrorEvent
+	started time.Time
+	count   int
+}
+
+// observe counts an error event, returning if it's the first with its fingerprint in the current window.
+func (d *dedup) observe(e *ErrorEvent, now time.Time) bool {
+	fingerprint := e.Fingerprint()
+	if entry, hasEntry := d.seen[fingerprint]; hasEntry {
+		entry.count++
+		return false
+	}
+	d.seen[fingerprint] = &dedupEntry{first: e, started: now, count: 1}
+	return true
+}
+
+// expire closes the windows that have elapsed at a given time, or all of them if the time is zero,
+// and returns the closed windows with repeats.
+func (d *dedup) expire(now time.Time) (repeated []*dedupEntry) {
+	for fingerprint, entry := range d.seen {
+		if now.IsZero() || now.Sub(entry.started) >= d.window {
+			if entry.count > 1 {
+				repeated = append(repeated, entry)
+			}
+			delete(d.seen, fingerprint)
+		}
+	}
+	return
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+)
+
+// NewDedupListener returns a listener that collapses error events with the same fingerprint before
+// passing them to another listener, e.g. one that sends notifications.
+// The first occurrence within a window is passed through, repeats are counted and a single summary
+// error event, wrapping the first error so it keeps its class, is passed once the window elapses.
+func NewDedupListener(inner Listener) *DedupListener {
+	return &DedupListener{
+		inner:         inner,
+		dedup:         newDedup(DefaultDedupWindow),
+		sweepInterval: DefaultDedupSweepInterval,
+	}
+}
+
+// DedupListener wraps a listener and suppresses repeated error events.
+/*
+Start it so summaries are passed when their window elapses, and listen with `Listen`:
+
+	dl := logger.NewDedupListener(logger.NewErrorEventListener(notify)).Start()
+	defer dl.Stop()
+	log.Listen(logger.Error, "notify", dl.Listen)
+	log.Listen(logger.Fatal, "notify", dl.Listen)
+*/
+type DedupListener struct {
+	sync.Mutex
+	inner         Listener
+	dedup         *dedup
+	sweepInterval time.Duration
+	sweeper       *async.Interval
+}
+
+// WithWindow sets the dedup window.
+func (dl *DedupListener) WithWindow(window time.Duration) *DedupListener {
+	dl.dedup.window = window
+	return dl
+}
+
+// Window returns the dedup window.
+func (dl *DedupListener) Window() time.Duration {
+	return dl.dedup.window
+}
+
+// WithSweepInterval sets the interval elapsed windows are checked for once started.
+func (dl *DedupListener) WithSweepInterval(interval time.Duration) *DedupListener {
+	dl.sweepInterval = interval
+	return dl
+}
+
+// SweepInterval returns the interval elapsed windows are checked for once started.
+func (dl *DedupListener) SweepInterval() time.Duration {
+	return dl.sweepInterval
+}
+
+// Start starts passing summaries for elapsed windows on the sweep interval.
+func (dl *DedupListener) Start() *DedupListener {
+	dl.Lock()
+	defer dl.Unlock()
+	if dl.sweeper != nil {
+		return dl
+	}
+	dl.sweeper = async.NewInterval(dl.sweep, dl.sweepInterval)
+	dl.sweeper.Start()
+	return dl
+}
+
+// Stop stops the background sweep and passes summaries for all open windows.
+func (dl *DedupListener) Stop() {
+	dl.Lock()
+	sweeper := dl.sweeper
+	dl.sweeper = nil
+	dl.Unlock()
+
+	if sweeper != nil {
+		sweeper.Stop()
+	}
+	dl.Flush()
+}
+
+// Listen passes an event to the wrapped listener unless it's an error event that repeats
+// one already passed within the current window.
+func (dl *DedupListener) Listen(e Event) {
+	dl.Lock()
+	defer dl.Unlock()
+
+	now := time.Now().UTC()
+	dl.listenSummaries(dl.dedup.expire(now))
+	if typed, isTyped := e.(*ErrorEvent); isTyped && !dl.dedup.observe(typed, now) {
+		return
+	}
+	dl.inner(e)
+}
+
+// Flush passes summaries for all open windows and resets the listener.
+func (dl *DedupListener) Flush() {
+	dl.Lock()
+	defer dl.Unlock()
+	dl.listenSummaries(dl.dedup.expire(time.Time{}))
+}
+
+// sweep passes summaries for any windows that have elapsed.
+func (dl *DedupListener) sweep() error {
+	dl.Lock()
+	defer dl.Unlock()
+	dl.listenSummaries(dl.dedup.expire(time.Now().UTC()))
+	return nil
+}
+
+// listenSummaries passes summaries for closed windows to the wrapped listener.
+// It must be called while holding the lock.
+func (dl *DedupListener) listenSummaries(entries []*dedupEntry) {
+	for _, entry := range entries {
+		summary := NewErrorEvent(entry.first.Flag(), exception.Wrapf(entry.first.Err(), "seen %d times in the last %v", entry.count, dl.dedup.window))
+		summary.SetHeadings(entry.first.Headings()...)
+		dl.inner(summary)
+	}
+}
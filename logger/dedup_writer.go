@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+)
+
+// Asserts dedup writer is a writer.
+var (
+	_ Writer        = &DedupWriter{}
+	_ WriterStopper = &DedupWriter{}
+)
+
+// NewDedupWriter returns a writer that collapses error events with the same fingerprint.
+// The first occurrence within a window is written through, repeats are counted and
+// a single summary is written once the window elapses.
+//
+// Deduplication that suppresses output happens at the writer because listeners are processed
+// independently of output; see `DedupListener` to deduplicate the events a listener gets.
+func NewDedupWriter(inner Writer) *DedupWriter {
+	return &DedupWriter{
+		inner:         inner,
+		dedup:         newDedup(DefaultDedupWindow),
+		sweepInterval: DefaultDedupSweepInterval,
+	}
+}
+
+// DedupWriter wraps a writer and suppresses repeated error events.
+/*
+Start it so summaries are written when their window elapses; otherwise they're written with the next event:
+
+	dw := logger.NewDedupWriter(logger.NewTextWriterFromEnv()).Start()
+	log := logger.New(logger.DefaultFlags...).WithWriter(dw)
+	defer log.Close() // stops the dedup writer, writing any pending summaries.
+*/
+type DedupWriter struct {
+	sync.Mutex
+	inner         Writer
+	dedup         *dedup
+	sweepInterval time.Duration
+	errors        chan error
+	sweeper       *async.Interval
+}
+
+// WithWindow sets the dedup window.
+func (dw *DedupWriter) WithWindow(window time.Duration) *DedupWriter {
+	dw.dedup.window = window
+	return dw
+}
+
+// Window returns the dedup window.
+func (dw *DedupWriter) Window() time.Duration {
+	return dw.dedup.window
+}
+
+// WithSweepInterval sets the interval elapsed windows are checked for once started.
+func (dw *DedupWriter) WithSweepInterval(interval time.Duration) *DedupWriter {
+	dw.sweepInterval = interval
+	return dw
+}
+
+// SweepInterval returns the interval elapsed windows are checked for once started.
+func (dw *DedupWriter) SweepInterval() time.Duration {
+	return dw.sweepInterval
+}
+
+// WithErrors sets a channel that errors writing summaries in the background are sent to.
+func (dw *DedupWriter) WithErrors(errors chan error) *DedupWriter {
+	dw.errors = errors
+	return dw
+}
+
+// Errors returns the background errors channel.
+func (dw *DedupWriter) Errors() chan error {
+	return dw.errors
+}
+
+// Inner returns the wrapped writer.
+func (dw *DedupWriter) Inner() Writer {
+	return dw.inner
+}
+
+// OutputFormat returns the output format of the wrapped writer.
+func (dw *DedupWriter) OutputFormat() OutputFormat {
+	return dw.inner.OutputFormat()
+}
+
+// Output returns the output of the wrapped writer.
+func (dw *DedupWriter) Output() io.Writer {
+	return dw.inner.Output()
+}
+
+// ErrorOutput returns the error output of the wrapped writer.
+func (dw *DedupWriter) ErrorOutput() io.Writer {
+	return dw.inner.ErrorOutput()
+}
+
+// Start starts writing summaries for elapsed windows on the sweep interval.
+func (dw *DedupWriter) Start() *DedupWriter {
+	dw.Lock()
+	defer dw.Unlock()
+	if dw.sweeper != nil {
+		return dw
+	}
+	dw.sweeper = async.NewInterval(dw.sweep, dw.sweepInterval).WithErrors(dw.errors)
+	dw.sweeper.Start()
+	return dw
+}
+
+// Stop stops the background sweep and writes summaries for all open windows.
+func (dw *DedupWriter) Stop() error {
+	dw.Lock()
+	sweeper := dw.sweeper
+	dw.sweeper = nil
+	dw.Unlock()
+
+	if sweeper != nil {
+		sweeper.Stop()
+	}
+	return dw.Flush()
+}
+
+// Write writes an event to the wrapped writer.
+// It also writes summaries for any windows that have elapsed.
+func (dw *DedupWriter) Write(e Event) error {
+	dw.Lock()
+	dw.writeSummaries(dw.dedup.expire(time.Now().UTC()))
+	dw.Unlock()
+	return dw.inner.Write(e)
+}
+
+// WriteError writes an error event to the wrapped writer unless it repeats
+// an error already written within the current window.
+func (dw *DedupWriter) WriteError(e Event) error {
+	dw.Lock()
+	defer dw.Unlock()
+
+	now := time.Now().UTC()
+	dw.writeSummaries(dw.dedup.expire(now))
+
+	typed, isTyped := e.(*ErrorEvent)
+	if !isTyped {
+		return dw.inner.WriteError(e)
+	}
+	if !dw.dedup.observe(typed, now) {
+		return nil
+	}
+	return dw.inner.WriteError(e)
+}
+
+// Flush writes summaries for all open windows and resets the writer.
+// It should be called before the process exits; closing the logger does it by stopping the writer.
+func (dw *DedupWriter) Flush() error {
+	dw.Lock()
+	defer dw.Unlock()
+	return dw.writeSummaries(dw.dedup.expire(time.Time{}))
+}
+
+// sweep writes summaries for any windows that have elapsed.
+func (dw *DedupWriter) sweep() error {
+	dw.Lock()
+	defer dw.Unlock()
+	return dw.writeSummaries(dw.dedup.expire(time.Now().UTC()))
+}
+
+// writeSummaries writes summaries for closed windows.
+// It must be called while holding the lock.
+func (dw *DedupWriter) writeSummaries(entries []*dedupEntry) (err error) {
+	for _, entry := range entries {
+		summary := Messagef(entry.first.Flag(), "%s seen %d times in the last %v", exception.ErrClass(entry.first.Err()), entry.count, dw.dedup.window)
+		summary.SetHeadings(entry.first.Headings()...)
+		if writeErr := dw.inner.WriteError(summary); writeErr != nil {
+			err = writeErr
+		}
+	}
+	return
+}
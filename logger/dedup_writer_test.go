@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestFingerprint(t *testing.T) {
+	assert := assert.New(t)
+
+	newErr := func() error { return exception.New("test class") }
+	first, second := newErr(), newErr()
+	assert.NotEmpty(Fingerprint(first))
+	assert.Equal(Fingerprint(first), Fingerprint(second))
+	assert.NotEqual(Fingerprint(first), Fingerprint(exception.New("test class")))
+	assert.NotEqual(Fingerprint(first), Fingerprint(exception.New("other class")))
+	assert.Empty(Fingerprint(nil))
+}
+
+func TestDedupWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	dw := NewDedupWriter(NewTextWriter(output).WithUseColor(false).WithShowTimestamp(false))
+	assert.Equal(DefaultDedupWindow, dw.Window())
+
+	newErr := func() error { return exception.New("test class") }
+	for x := 0; x < 5; x++ {
+		assert.Nil(dw.WriteError(NewErrorEvent(Error, newErr())))
+	}
+	assert.Equal(1, strings.Count(output.String(), "test class"))
+
+	assert.Nil(dw.Flush())
+	assert.Contains(output.String(), "test class seen 5 times in the last 1m0s")
+}
+
+func TestDedupWriterWindowElapsed(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	dw := NewDedupWriter(NewTextWriter(output).WithUseColor(false).WithShowTimestamp(false)).WithWindow(time.Millisecond)
+
+	newErr := func() error { return exception.New("test class") }
+	assert.Nil(dw.WriteError(NewErrorEvent(Error, newErr())))
+	assert.Nil(dw.WriteError(NewErrorEvent(Error, newErr())))
+	time.Sleep(2 * time.Millisecond)
+	assert.Nil(dw.Write(Messagef(Info, "ping")))
+
+	assert.Contains(output.String(), "seen 2 times")
+	assert.Contains(output.String(), "ping")
+}
+
+func TestDedupWriterSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(syncBuffer)
+	dw := NewDedupWriter(NewTextWriter(output).WithUseColor(false).WithShowTimestamp(false)).
+		WithWindow(10 * time.Millisecond).
+		WithSweepInterval(time.Millisecond).
+		Start()
+	defer dw.Stop()
+
+	newErr := func() error { return exception.New("test class") }
+	assert.Nil(dw.WriteError(NewErrorEvent(Error, newErr())))
+	assert.Nil(dw.WriteError(NewErrorEvent(Error, newErr())))
+
+	// the summary is written once the window elapses, without waiting for another event.
+	assert.Eventually(func() bool {
+		return strings.Contains(output.String(), "seen 2 times")
+	}, time.Second, time.Millisecond)
+}
+
+func TestDedupWriterStoppedOnLoggerClose(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(syncBuffer)
+	dw := NewDedupWriter(NewTextWriter(output).WithUseColor(false).WithShowTimestamp(false)).Start()
+	log := New(Error).WithWriter(dw)
+
+	newErr := func() error { return exception.New("test class") }
+	log.SyncError(newErr())
+	log.SyncError(newErr())
+	log.Close()
+
+	assert.Contains(output.String(), "test class seen 2 times in the last 1m0s")
+}
+
+func TestDedupListener(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var events []Event
+	dl := NewDedupListener(func(e Event) {
+		lock.Lock()
+		events = append(events, e)
+		lock.Unlock()
+	}).WithWindow(10 * time.Millisecond).WithSweepInterval(time.Millisecond)
+	assert.Equal(10*time.Millisecond, dl.Window())
+
+	newErr := func() error { return exception.New("test class") }
+	for x := 0; x < 3; x++ {
+		dl.Listen(NewErrorEvent(Error, newErr()))
+	}
+	dl.Listen(Messagef(Info, "foo"))
+
+	lock.Lock()
+	assert.Len(events, 2)
+	lock.Unlock()
+
+	dl.Start()
+	assert.Eventually(func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(events) == 3
+	}, time.Second, time.Millisecond)
+	dl.Stop()
+
+	lock.Lock()
+	defer lock.Unlock()
+	summary, isSummary := events[2].(*ErrorEvent)
+	assert.True(isSummary)
+	assert.True(exception.Is(summary.Err(), exception.Class("test class")))
+	assert.Equal("seen 3 times in the last 10ms", exception.As(summary.Err()).Message())
+}
+
+// syncBuffer is a buffer that's safe to write to from the sweep goroutine.
+type syncBuffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(contents []byte) (int, error) {
+	sb.Lock()
+	defer sb.Unlock()
+	return sb.buffer.Write(contents)
+}
+
+func (sb *syncBuffer) String() string {
+	sb.Lock()
+	defer sb.Unlock()
+	return sb.buffer.String()
+}
@@ -11,6 +11,9 @@ const (
 	// EnvVarEvents is the env var that sets the output format.
 	EnvVarFormat = "LOG_FORMAT"
 
+	// EnvVarProfile is the env var that sets the logger defaults profile, i.e. `production`, `development` or `test`.
+	EnvVarProfile = "LOG_PROFILE"
+
 	// EnvVarUseColor is the env var that controls if we use ansi colors in output.
 	EnvVarUseColor = "LOG_USE_COLOR"
 	// EnvVarShowTimestamp is the env var that controls if we show timestamps in output.
@@ -24,6 +27,25 @@ const (
 	// EnvVarTimeFormat is the env var that sets the time format for text output.
 	EnvVarTimeFormat = "LOG_TIME_FORMAT"
 
+	// EnvVarEscapeNewlines is the env var that controls if multi-line messages are escaped onto a single line in text output.
+	EnvVarEscapeNewlines = "LOG_ESCAPE_NEWLINES"
+
 	// EnvVarJSONPretty returns if we should indent json output.
 	EnvVarJSONPretty = "LOG_JSON_PRETTY"
+
+	// EnvVarSplunkURL is the env var that sets the splunk http event collector url.
+	EnvVarSplunkURL = "LOG_SPLUNK_URL"
+	// EnvVarSplunkToken is the env var that sets the splunk http event collector token.
+	EnvVarSplunkToken = "LOG_SPLUNK_TOKEN"
+
+	// EnvVarGELFAddr is the env var that sets the gelf (graylog) address.
+	EnvVarGELFAddr = "LOG_GELF_ADDR"
+
+	// EnvVarKafkaTopic is the env var that sets the kafka writer topic.
+	EnvVarKafkaTopic = "LOG_KAFKA_TOPIC"
+
+	// EnvVarOTLPEndpoint is the standard opentelemetry env var that sets the collector base endpoint.
+	EnvVarOTLPEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	// EnvVarOTLPLogsEndpoint is the standard opentelemetry env var that sets the collector logs endpoint.
+	EnvVarOTLPLogsEndpoint = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
 )
@@ -109,6 +109,11 @@ func (e *ErrorEvent) Err() error {
 	return e.err
 }
 
+// Fingerprint returns an identifier for the error, used to group identical errors.
+func (e *ErrorEvent) Fingerprint() string {
+	return Fingerprint(e.err)
+}
+
 // WithState sets the state.
 func (e *ErrorEvent) WithState(state Any) *ErrorEvent {
 	e.state = state
@@ -8,6 +8,7 @@ var (
 	_ EventHeadings    = &EventMeta{}
 	_ EventLabels      = &EventMeta{}
 	_ EventAnnotations = &EventMeta{}
+	_ EventTrace       = &EventMeta{}
 )
 
 // NewEventMeta returns a new event meta.
@@ -29,6 +30,11 @@ type EventMeta struct {
 	entity        string
 	labels        Labels
 	annotations   Annotations
+	traceID       string
+	spanID        string
+
+	// pooled marks the event as leased from a pool; see `event_pool.go`.
+	pooled bool
 }
 
 // Headings returns the event meta headings.
@@ -56,7 +62,12 @@ func (em *EventMeta) Timestamp() time.Time { return em.ts }
 func (em *EventMeta) SetTimestamp(ts time.Time) { em.ts = ts }
 
 // AddLabelValue adds a label value
-func (em *EventMeta) AddLabelValue(key, value string) { em.labels[key] = value }
+func (em *EventMeta) AddLabelValue(key, value string) {
+	if em.labels == nil {
+		em.labels = make(Labels)
+	}
+	em.labels[key] = value
+}
 
 // SetLabels sets the labels collection.
 func (em *EventMeta) SetLabels(labels Labels) { em.labels = labels }
@@ -65,7 +76,12 @@ func (em *EventMeta) SetLabels(labels Labels) { em.labels = labels }
 func (em *EventMeta) Labels() Labels { return em.labels }
 
 // AddAnnotationValue adds an annotation value
-func (em *EventMeta) AddAnnotationValue(key, value string) { em.annotations[key] = value }
+func (em *EventMeta) AddAnnotationValue(key, value string) {
+	if em.annotations == nil {
+		em.annotations = make(Annotations)
+	}
+	em.annotations[key] = value
+}
 
 // SetAnnotations sets the annotations collection.
 func (em *EventMeta) SetAnnotations(annotations Annotations) { em.annotations = annotations }
@@ -78,3 +94,12 @@ func (em *EventMeta) SetEntity(value string) { em.entity = value }
 
 // Entity returns an entity value.
 func (em *EventMeta) Entity() string { return em.entity }
+
+// SetTrace sets the trace correlation ids.
+func (em *EventMeta) SetTrace(traceID, spanID string) { em.traceID, em.spanID = traceID, spanID }
+
+// TraceID returns the trace id the event was emitted under.
+func (em *EventMeta) TraceID() string { return em.traceID }
+
+// SpanID returns the span id the event was emitted under.
+func (em *EventMeta) SpanID() string { return em.spanID }
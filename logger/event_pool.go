@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// messageEventPool holds message events for reuse by the builtin message helpers (`Infof`, `Debugf` etc.)
+// of loggers that pool events; see `Logger.WithPoolEvents`.
+var messageEventPool = sync.Pool{
+	New: func() Any {
+		return &MessageEvent{EventMeta: &EventMeta{}}
+	},
+}
+
+// leaseMessagef returns a message event from the pool.
+// Leased events are returned to the pool by `releaseEvent` once they have been written,
+// unless they were handed to listeners (see `disownEvent`), which may retain them.
+func leaseMessagef(flag Flag, format string, args ...Any) *MessageEvent {
+	e := messageEventPool.Get().(*MessageEvent)
+	e.EventMeta.reset(flag)
+	e.EventMeta.pooled = true
+	e.message = fmt.Sprintf(format, args...)
+	return e
+}
+
+// Retain marks an event as retained so it is not reused once it has been written.
+// Writers that keep events after `Write` or `WriteError` return must call it if the logger pools events.
+func Retain(e Event) Event {
+	disownEvent(e)
+	return e
+}
+
+// disownEvent marks a leased event as no longer safe to return to the pool.
+func disownEvent(e Event) {
+	if typed, isTyped := e.(*MessageEvent); isTyped && typed.EventMeta != nil {
+		typed.pooled = false
+	}
+}
+
+// releaseEvent returns a leased event to the pool.
+// It is a no-op for events that were not leased or have been disowned.
+func releaseEvent(e Event) {
+	if typed, isTyped := e.(*MessageEvent); isTyped && typed.EventMeta != nil && typed.pooled {
+		typed.pooled = false
+		typed.message = ""
+		messageEventPool.Put(typed)
+	}
+}
+
+// reset clears the event meta for reuse.
+func (em *EventMeta) reset(flag Flag) {
+	em.flag = flag
+	em.flagTextColor = ""
+	em.ts = time.Now().UTC()
+	em.headings = nil
+	em.entity = ""
+	// empty collections are reused; populated ones may be shared by the caller.
+	if em.labels == nil || len(em.labels) > 0 {
+		em.labels = make(Labels)
+	}
+	if em.annotations == nil || len(em.annotations) > 0 {
+		em.annotations = make(Annotations)
+	}
+	em.traceID = ""
+	em.spanID = ""
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLeaseMessagef(t *testing.T) {
+	assert := assert.New(t)
+
+	e := leaseMessagef(Info, "foo %s", "bar")
+	assert.True(e.pooled)
+	assert.Equal(Info, e.Flag())
+	assert.Equal("foo bar", e.Message())
+	assert.False(e.Timestamp().IsZero())
+
+	e.SetHeadings("heading")
+	e.AddLabelValue("foo", "bar")
+	e.SetTrace("trace", "span")
+	releaseEvent(e)
+	assert.False(e.pooled)
+	assert.Empty(e.Message())
+
+	reused := leaseMessagef(Debug, "buzz")
+	assert.Equal(Debug, reused.Flag())
+	assert.Equal("buzz", reused.Message())
+	assert.Empty(reused.Headings())
+	assert.Empty(reused.Labels())
+	assert.Empty(reused.TraceID())
+}
+
+func TestReleaseEventUnpooled(t *testing.T) {
+	assert := assert.New(t)
+
+	e := Messagef(Info, "foo")
+	releaseEvent(e)
+	assert.Equal("foo", e.Message())
+
+	leased := leaseMessagef(Info, "foo")
+	disownEvent(leased)
+	releaseEvent(leased)
+	assert.Equal("foo", leased.Message())
+}
+
+func TestLoggerPooledEventsListened(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := New().WithFlags(AllFlags()).WithPoolEvents(true).WithWriter(NewTextWriter(buffer).WithUseColor(false).WithShowTimestamp(false))
+	defer log.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	var received []*MessageEvent
+	log.Listen(Info, "test", NewMessageEventListener(func(me *MessageEvent) {
+		defer wg.Done()
+		received = append(received, me)
+	}))
+
+	log.SyncInfof("foo %d", 1)
+	log.SyncInfof("foo %d", 2)
+	wg.Wait()
+
+	assert.Len(received, 2)
+	assert.Equal("foo 1", received[0].Message())
+	assert.Equal("foo 2", received[1].Message())
+	assert.Contains(buffer.String(), "foo 1")
+	assert.Contains(buffer.String(), "foo 2")
+}
+
+type retainingWriter struct {
+	TextWriter
+	events []Event
+}
+
+func (rw *retainingWriter) Write(e Event) error {
+	rw.events = append(rw.events, e)
+	return nil
+}
+
+func TestLoggerEventsNotPooledByDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	writer := new(retainingWriter)
+	log := New().WithFlags(AllFlags()).WithWriter(writer)
+	defer log.Close()
+	assert.False(log.PoolsEvents())
+
+	log.SyncInfof("foo %d", 1)
+	log.SyncInfof("foo %d", 2)
+
+	assert.Len(writer.events, 2)
+	assert.Equal("foo 1", writer.events[0].(*MessageEvent).Message(), "writers that keep events shouldn't see them reused")
+	assert.Equal("foo 2", writer.events[1].(*MessageEvent).Message())
+	assert.False(writer.events[0].(*MessageEvent).pooled)
+}
+
+func BenchmarkLoggerSyncInfofText(b *testing.B) {
+	log := New().WithFlags(AllFlags()).WithPoolEvents(true).WithWriter(NewTextWriter(ioutil.Discard))
+	defer log.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		log.SyncInfof("foo %d", x)
+	}
+}
+
+func BenchmarkLoggerSyncInfofJSON(b *testing.B) {
+	log := New().WithFlags(AllFlags()).WithPoolEvents(true).WithWriter(NewJSONWriter(ioutil.Discard))
+	defer log.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for x := 0; x < b.N; x++ {
+		log.SyncInfof("foo %d", x)
+	}
+}
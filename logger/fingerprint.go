@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"hash/fnv"
+	"io"
+	"strconv"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// Fingerprint returns a stable identifier for an error.
+// It is derived from the exception class and the top stack frame if the error is an exception,
+// otherwise from the error message alone.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+	hash := fnv.New64a()
+	io.WriteString(hash, exception.ErrClass(err))
+	if ex := exception.As(err); ex != nil && ex.Stack() != nil {
+		if frames := ex.Stack().Strings(); len(frames) > 0 {
+			io.WriteString(hash, frames[0])
+		}
+	}
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
@@ -29,6 +29,9 @@ const (
 
 	// Query is a logging flag.
 	Query Flag = "db.query"
+
+	// Timing fires for timed operations, i.e. from `Logger.Timer(...)`.
+	Timing Flag = "timing"
 )
 
 // Flag represents an event type that can be enabled or disabled.
@@ -0,0 +1,423 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// GELFVersion is the gelf payload version.
+	GELFVersion = "1.1"
+
+	// GELFNetworkUDP is a gelf network.
+	GELFNetworkUDP = "udp"
+	// GELFNetworkTCP is a gelf network.
+	GELFNetworkTCP = "tcp"
+
+	// GELFMaxChunks is the maximum number of chunks a udp message can be split into.
+	GELFMaxChunks = 128
+	// GELFChunkHeaderSize is the size of the header on each udp chunk.
+	GELFChunkHeaderSize = 12
+
+	// ErrGELFMessageTooLarge is returned when a udp message needs more than `GELFMaxChunks` chunks.
+	ErrGELFMessageTooLarge exception.Class = "gelf: message too large"
+	// ErrGELFNetworkInvalid is returned when the network is not `udp` or `tcp`.
+	ErrGELFNetworkInvalid exception.Class = "gelf: invalid network"
+)
+
+// gelfChunkMagic are the leading bytes of a chunked udp message.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// Asserts gelf writer is a writer.
+var (
+	_ Writer = &GELFWriter{}
+)
+
+// NewGELFWriter returns a new gelf writer for a given network (`udp` or `tcp`) and address.
+// The connection is established on the first write.
+func NewGELFWriter(network, addr string) *GELFWriter {
+	host, _ := os.Hostname()
+	return &GELFWriter{
+		network:     strings.ToLower(network),
+		addr:        addr,
+		host:        host,
+		chunkSize:   DefaultGELFChunkSize,
+		compress:    DefaultGELFCompress,
+		dialTimeout: DefaultGELFDialTimeout,
+	}
+}
+
+// NewGELFWriterFromEnv returns a new gelf writer from the environment.
+func NewGELFWriterFromEnv() *GELFWriter {
+	return NewGELFWriterFromConfig(NewGELFWriterConfigFromEnv())
+}
+
+// NewGELFWriterFromConfig returns a new gelf writer from a config.
+func NewGELFWriterFromConfig(cfg *GELFWriterConfig) *GELFWriter {
+	gw := NewGELFWriter(cfg.GetNetwork(), cfg.GetAddr()).
+		WithHost(cfg.GetHost()).
+		WithChunkSize(cfg.GetChunkSize()).
+		WithCompress(cfg.GetCompress()).
+		WithDialTimeout(cfg.GetDialTimeout())
+	if cfg.GetTLS() {
+		gw = gw.WithTLSConfig(&tls.Config{InsecureSkipVerify: cfg.GetTLSInsecureSkipVerify()})
+	}
+	return gw
+}
+
+// GELFWriter writes events as gelf (graylog extended log format) messages.
+// Over udp, messages are optionally gzipped and split into chunks; over tcp, messages are null delimited and can use tls.
+type GELFWriter struct {
+	sync.Mutex
+
+	network     string
+	addr        string
+	host        string
+	chunkSize   int
+	compress    bool
+	dialTimeout time.Duration
+	tlsConfig   *tls.Config
+
+	conn net.Conn
+}
+
+// OutputFormat returns the output format.
+func (gw *GELFWriter) OutputFormat() OutputFormat {
+	return OutputFormatJSON
+}
+
+// Output returns nil; the gelf writer has no local output stream.
+func (gw *GELFWriter) Output() io.Writer {
+	return nil
+}
+
+// ErrorOutput returns nil; the gelf writer has no local output stream.
+func (gw *GELFWriter) ErrorOutput() io.Writer {
+	return nil
+}
+
+// Network returns the network.
+func (gw *GELFWriter) Network() string {
+	return gw.network
+}
+
+// Addr returns the address messages are sent to.
+func (gw *GELFWriter) Addr() string {
+	return gw.addr
+}
+
+// WithHost sets the host field sent with messages.
+func (gw *GELFWriter) WithHost(host string) *GELFWriter {
+	gw.host = host
+	return gw
+}
+
+// Host returns the host field sent with messages.
+func (gw *GELFWriter) Host() string {
+	return gw.host
+}
+
+// WithChunkSize sets the maximum udp datagram size, including the chunk header.
+func (gw *GELFWriter) WithChunkSize(chunkSize int) *GELFWriter {
+	gw.chunkSize = chunkSize
+	return gw
+}
+
+// ChunkSize returns the maximum udp datagram size.
+func (gw *GELFWriter) ChunkSize() int {
+	return gw.chunkSize
+}
+
+// WithCompress sets if udp messages are gzipped; tcp messages are never compressed.
+func (gw *GELFWriter) WithCompress(compress bool) *GELFWriter {
+	gw.compress = compress
+	return gw
+}
+
+// Compress returns if udp messages are gzipped.
+func (gw *GELFWriter) Compress() bool {
+	return gw.compress
+}
+
+// WithDialTimeout sets the dial timeout.
+func (gw *GELFWriter) WithDialTimeout(timeout time.Duration) *GELFWriter {
+	gw.dialTimeout = timeout
+	return gw
+}
+
+// DialTimeout returns the dial timeout.
+func (gw *GELFWriter) DialTimeout() time.Duration {
+	return gw.dialTimeout
+}
+
+// WithTLSConfig sets the tls config used for tcp connections.
+func (gw *GELFWriter) WithTLSConfig(tlsConfig *tls.Config) *GELFWriter {
+	gw.tlsConfig = tlsConfig
+	return gw
+}
+
+// TLSConfig returns the tls config used for tcp connections.
+func (gw *GELFWriter) TLSConfig() *tls.Config {
+	return gw.tlsConfig
+}
+
+// Write sends an event.
+func (gw *GELFWriter) Write(e Event) error {
+	return gw.write(e)
+}
+
+// WriteError sends an event.
+func (gw *GELFWriter) WriteError(e Event) error {
+	return gw.write(e)
+}
+
+// Close closes the underlying connection.
+func (gw *GELFWriter) Close() error {
+	gw.Lock()
+	defer gw.Unlock()
+	return gw.closeConn()
+}
+
+func (gw *GELFWriter) write(e Event) error {
+	contents, err := json.Marshal(GELFMessage(gw.host, e))
+	if err != nil {
+		return exception.New(err)
+	}
+
+	gw.Lock()
+	defer gw.Unlock()
+
+	// retry once on a fresh connection, e.g. if the server closed a tcp connection.
+	if err = gw.send(contents); err != nil {
+		gw.closeConn()
+		err = gw.send(contents)
+	}
+	return err
+}
+
+func (gw *GELFWriter) send(contents []byte) error {
+	if gw.conn == nil {
+		if err := gw.dial(); err != nil {
+			return err
+		}
+	}
+	switch gw.network {
+	case GELFNetworkTCP:
+		_, err := gw.conn.Write(append(contents, 0))
+		return exception.New(err)
+	default:
+		return gw.sendUDP(contents)
+	}
+}
+
+func (gw *GELFWriter) sendUDP(contents []byte) error {
+	if gw.compress {
+		buffer := new(bytes.Buffer)
+		gzipWriter := gzip.NewWriter(buffer)
+		if _, err := gzipWriter.Write(contents); err != nil {
+			return exception.New(err)
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return exception.New(err)
+		}
+		contents = buffer.Bytes()
+	}
+
+	if len(contents) <= gw.chunkSize {
+		_, err := gw.conn.Write(contents)
+		return exception.New(err)
+	}
+
+	chunks, err := GELFChunks(contents, gw.chunkSize)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if _, err := gw.conn.Write(chunk); err != nil {
+			return exception.New(err)
+		}
+	}
+	return nil
+}
+
+func (gw *GELFWriter) dial() error {
+	dialer := &net.Dialer{Timeout: gw.dialTimeout}
+	var conn net.Conn
+	var err error
+	switch gw.network {
+	case GELFNetworkUDP:
+		conn, err = dialer.Dial(GELFNetworkUDP, gw.addr)
+	case GELFNetworkTCP:
+		if gw.tlsConfig != nil {
+			conn, err = tls.DialWithDialer(dialer, GELFNetworkTCP, gw.addr, gw.tlsConfig)
+		} else {
+			conn, err = dialer.Dial(GELFNetworkTCP, gw.addr)
+		}
+	default:
+		return exception.New(ErrGELFNetworkInvalid).WithMessagef("network: %s", gw.network)
+	}
+	if err != nil {
+		return exception.New(err)
+	}
+	gw.conn = conn
+	return nil
+}
+
+func (gw *GELFWriter) closeConn() error {
+	if gw.conn == nil {
+		return nil
+	}
+	err := gw.conn.Close()
+	gw.conn = nil
+	return exception.New(err)
+}
+
+// GELFChunks splits a udp message into chunks of at most `chunkSize` bytes, including the chunk header.
+func GELFChunks(contents []byte, chunkSize int) ([][]byte, error) {
+	dataSize := chunkSize - GELFChunkHeaderSize
+	if dataSize <= 0 {
+		return nil, exception.New(ErrGELFMessageTooLarge).WithMessagef("chunk size too small: %d", chunkSize)
+	}
+	count := (len(contents) + dataSize - 1) / dataSize
+	if count > GELFMaxChunks {
+		return nil, exception.New(ErrGELFMessageTooLarge).WithMessagef("chunks: %d", count)
+	}
+
+	messageID := make([]byte, 8)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, exception.New(err)
+	}
+
+	chunks := make([][]byte, 0, count)
+	for index := 0; index < count; index++ {
+		start := index * dataSize
+		end := start + dataSize
+		if end > len(contents) {
+			end = len(contents)
+		}
+		chunk := make([]byte, 0, GELFChunkHeaderSize+end-start)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, messageID...)
+		chunk = append(chunk, byte(index), byte(count))
+		chunk = append(chunk, contents[start:end]...)
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// GELFMessage returns the gelf payload for an event.
+// Event fields and labels are added as additional fields, i.e. prefixed with `_`, with nested fields joined by `.`.
+func GELFMessage(host string, e Event) map[string]Any {
+	message := map[string]Any{
+		"version":   GELFVersion,
+		"host":      host,
+		"timestamp": float64(e.Timestamp().UnixNano()) / float64(time.Second),
+		"level":     GELFLevel(e.Flag()),
+		"_flag":     string(e.Flag()),
+	}
+
+	fields, isFields := JSONFields(e)
+	if !isFields {
+		if typed, isTyped := e.(fmt.Stringer); isTyped {
+			message["short_message"] = typed.String()
+		}
+	} else {
+		delete(fields, JSONFieldFlag)
+		if short, hasShort := fields[JSONFieldMessage]; hasShort {
+			message["short_message"] = gelfValue(short)
+			delete(fields, JSONFieldMessage)
+		} else if err, hasErr := fields[JSONFieldErr]; hasErr {
+			message["short_message"] = gelfValue(err)
+			delete(fields, JSONFieldErr)
+		}
+		gelfFields(message, "", fields)
+	}
+
+	if typed, isTyped := e.(EventLabels); isTyped {
+		labels := make(map[string]Any, len(typed.Labels()))
+		for key, value := range typed.Labels() {
+			labels[key] = value
+		}
+		gelfFields(message, "", labels)
+	}
+	if typed, isTyped := e.(*ErrorEvent); isTyped && typed.Err() != nil {
+		message["full_message"] = fmt.Sprintf("%+v", typed.Err())
+	}
+	if short, hasShort := message["short_message"]; !hasShort || short == "" {
+		message["short_message"] = string(e.Flag())
+	}
+	return message
+}
+
+// GELFLevel returns the syslog severity for a flag.
+func GELFLevel(flag Flag) int {
+	switch flag {
+	case Fatal:
+		return 2
+	case Error:
+		return 3
+	case Warning:
+		return 4
+	case Debug, Silly:
+		return 7
+	default:
+		return 6
+	}
+}
+
+func gelfFields(message map[string]Any, prefix string, fields map[string]Any) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if nested, isNested := fields[key].(map[string]Any); isNested {
+			gelfFields(message, prefix+key+".", nested)
+			continue
+		}
+		name := gelfKey(prefix + key)
+		// `_id` is reserved by graylog.
+		if name == "id" {
+			name = "event_id"
+		}
+		message["_"+name] = gelfValue(fields[key])
+	}
+}
+
+// gelfKey replaces characters graylog does not allow in field names.
+func gelfKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// gelfValue returns a string or number value, as gelf additional fields must be one or the other.
+func gelfValue(value Any) Any {
+	switch typed := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return typed
+	case string:
+		return typed
+	case nil:
+		return ""
+	default:
+		return logfmtRaw(value)
+	}
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestGELFMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	me := Messagef(Info, "foo bar")
+	me.AddLabelValue("env", "test")
+	message := GELFMessage("test-host", me)
+	assert.Equal(GELFVersion, message["version"])
+	assert.Equal("test-host", message["host"])
+	assert.Equal("foo bar", message["short_message"])
+	assert.Equal(6, message["level"])
+	assert.Equal("info", message["_flag"])
+	assert.Equal("test", message["_env"])
+
+	ee := NewErrorEvent(Error, exception.New("only a test"))
+	message = GELFMessage("test-host", ee)
+	assert.Equal(3, message["level"])
+	assert.Equal("only a test", message["short_message"])
+	assert.NotEmpty(message["full_message"])
+}
+
+func TestGELFChunks(t *testing.T) {
+	assert := assert.New(t)
+
+	contents := bytes.Repeat([]byte("a"), 100)
+	chunks, err := GELFChunks(contents, 42)
+	assert.Nil(err)
+	assert.Len(chunks, 4)
+
+	var reassembled []byte
+	for index, chunk := range chunks {
+		assert.True(len(chunk) <= 42)
+		assert.Equal(gelfChunkMagic, chunk[:2])
+		assert.Equal(chunks[0][2:10], chunk[2:10])
+		assert.Equal(byte(index), chunk[10])
+		assert.Equal(byte(4), chunk[11])
+		reassembled = append(reassembled, chunk[GELFChunkHeaderSize:]...)
+	}
+	assert.Equal(contents, reassembled)
+
+	_, err = GELFChunks(bytes.Repeat([]byte("a"), GELFMaxChunks+1), GELFChunkHeaderSize+1)
+	assert.True(exception.Is(err, ErrGELFMessageTooLarge))
+}
+
+func TestGELFWriterUDP(t *testing.T) {
+	assert := assert.New(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(err)
+	defer conn.Close()
+
+	gw := NewGELFWriter("udp", conn.LocalAddr().String()).WithHost("test-host")
+	defer gw.Close()
+	assert.Nil(gw.Write(Messagef(Info, "foo bar")))
+
+	packet := make([]byte, 65536)
+	read, _, err := conn.ReadFrom(packet)
+	assert.Nil(err)
+
+	reader, err := gzip.NewReader(bytes.NewReader(packet[:read]))
+	assert.Nil(err)
+	contents, err := ioutil.ReadAll(reader)
+	assert.Nil(err)
+
+	var message map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &message))
+	assert.Equal("test-host", message["host"])
+	assert.Equal("foo bar", message["short_message"])
+}
+
+func TestGELFWriterUDPChunked(t *testing.T) {
+	assert := assert.New(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(err)
+	defer conn.Close()
+
+	gw := NewGELFWriter("udp", conn.LocalAddr().String()).WithCompress(false).WithChunkSize(64)
+	defer gw.Close()
+	assert.Nil(gw.Write(Messagef(Info, "foo bar")))
+
+	var contents []byte
+	packet := make([]byte, 65536)
+	for {
+		read, _, err := conn.ReadFrom(packet)
+		assert.Nil(err)
+		assert.True(read <= 64)
+		contents = append(contents, packet[GELFChunkHeaderSize:read]...)
+		if packet[10] == packet[11]-1 {
+			break
+		}
+	}
+
+	var message map[string]interface{}
+	assert.Nil(json.Unmarshal(contents, &message))
+	assert.Equal("foo bar", message["short_message"])
+}
+
+func TestGELFWriterTCPTLS(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server.TLS)
+	assert.Nil(err)
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		contents, _ := bufio.NewReader(conn).ReadBytes(0)
+		received <- contents
+	}()
+
+	gw := NewGELFWriter("tcp", listener.Addr().String()).
+		WithTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig)
+	defer gw.Close()
+	assert.Nil(gw.WriteError(Errorf(Error, "foo bar")))
+
+	contents := <-received
+	assert.Equal(byte(0), contents[len(contents)-1])
+
+	var message map[string]interface{}
+	assert.Nil(json.Unmarshal(contents[:len(contents)-1], &message))
+	assert.Equal("foo bar", message["short_message"])
+	assert.Equal(float64(3), message["level"])
+}
+
+func TestGELFWriterInvalidNetwork(t *testing.T) {
+	assert := assert.New(t)
+
+	err := NewGELFWriter("carrier-pigeon", "127.0.0.1:12201").Write(Messagef(Info, "foo"))
+	assert.True(exception.Is(err, ErrGELFNetworkInvalid))
+}
+
+func TestGELFWriterConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(GELFWriterConfig{}.IsZero())
+
+	env.Env().Set(EnvVarGELFAddr, "graylog:12201")
+	defer env.Env().Restore(EnvVarGELFAddr)
+	env.Env().Set("LOG_GELF_NETWORK", "tcp")
+	defer env.Env().Restore("LOG_GELF_NETWORK")
+	env.Env().Set("LOG_GELF_TLS", "true")
+	defer env.Env().Restore("LOG_GELF_TLS")
+
+	cfg := NewGELFWriterConfigFromEnv()
+	assert.False(cfg.IsZero())
+
+	gw := NewGELFWriterFromConfig(cfg)
+	assert.Equal("graylog:12201", gw.Addr())
+	assert.Equal(GELFNetworkTCP, gw.Network())
+	assert.NotNil(gw.TLSConfig())
+	assert.Equal(DefaultGELFChunkSize, gw.ChunkSize())
+	assert.True(gw.Compress())
+}
@@ -34,9 +34,10 @@ func NewHTTPRequestEventListener(listener func(*HTTPRequestEvent)) Listener {
 // HTTPRequestEvent is an event type for http responses.
 type HTTPRequestEvent struct {
 	*EventMeta
-	req   *http.Request
-	route string
-	state map[interface{}]interface{}
+	req             *http.Request
+	route           string
+	headerAllowList []string
+	state           map[interface{}]interface{}
 }
 
 // WithHeadings sets the headings.
@@ -91,6 +92,17 @@ func (e *HTTPRequestEvent) Route() string {
 	return e.route
 }
 
+// WithHeaderAllowList sets the request headers included in the output.
+func (e *HTTPRequestEvent) WithHeaderAllowList(headers ...string) *HTTPRequestEvent {
+	e.headerAllowList = headers
+	return e
+}
+
+// HeaderAllowList returns the request headers included in the output.
+func (e *HTTPRequestEvent) HeaderAllowList() []string {
+	return e.headerAllowList
+}
+
 // WithState sets the request state.
 func (e *HTTPRequestEvent) WithState(state map[interface{}]interface{}) *HTTPRequestEvent {
 	e.state = state
@@ -109,5 +121,5 @@ func (e *HTTPRequestEvent) WriteText(formatter TextFormatter, buf *bytes.Buffer)
 
 // WriteJSON implements JSONWritable.
 func (e *HTTPRequestEvent) WriteJSON() JSONObj {
-	return JSONWriteHTTPRequest(e.req)
+	return JSONWriteHTTPRequestDetails(JSONWriteHTTPRequest(e.req), e.req, e.route, e.headerAllowList)
 }
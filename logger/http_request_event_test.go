@@ -94,3 +94,30 @@ func TestWebRequestEventProperties(t *testing.T) {
 	assert.Empty(e.Route())
 	assert.Equal("Route", e.WithRoute("Route").Route())
 }
+
+func TestWebRequestEventJSONDetails(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{
+		Host: "test.com",
+		URL:  &url.URL{Path: "/users/123"},
+		Header: http.Header{
+			"User-Agent":    []string{"test-agent"},
+			"Referer":       []string{"https://test.com/"},
+			"X-Request-Id":  []string{"request-id"},
+			"Authorization": []string{"Bearer secret"},
+		},
+	}
+
+	output := NewHTTPRequestEvent(req).WithRoute("/users/:id").WithHeaderAllowList("x-request-id", "x-missing").WriteJSON()
+	assert.Equal("/users/:id", output["route"])
+	assert.Equal("test-agent", output["userAgent"])
+	assert.Equal("https://test.com/", output["referrer"])
+	assert.Equal(map[string]string{"X-Request-Id": "request-id"}, output["headers"])
+
+	output = NewHTTPResponseEvent(req).WithContentLength(512).WriteJSON()
+	assert.Equal(512, output["contentLength"])
+	assert.Equal("test-agent", output["userAgent"])
+	assert.Nil(output["route"])
+	assert.Nil(output["headers"])
+}
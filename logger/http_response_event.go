@@ -35,8 +35,9 @@ func NewHTTPResponseEventListener(listener func(*HTTPResponseEvent)) Listener {
 type HTTPResponseEvent struct {
 	*EventMeta
 
-	req   *http.Request
-	route string
+	req             *http.Request
+	route           string
+	headerAllowList []string
 
 	contentLength   int
 	contentType     string
@@ -100,6 +101,17 @@ func (e *HTTPResponseEvent) Route() string {
 	return e.route
 }
 
+// WithHeaderAllowList sets the request headers included in the output.
+func (e *HTTPResponseEvent) WithHeaderAllowList(headers ...string) *HTTPResponseEvent {
+	e.headerAllowList = headers
+	return e
+}
+
+// HeaderAllowList returns the request headers included in the output.
+func (e *HTTPResponseEvent) HeaderAllowList() []string {
+	return e.headerAllowList
+}
+
 // WithStatusCode sets the status code.
 func (e *HTTPResponseEvent) WithStatusCode(statusCode int) *HTTPResponseEvent {
 	e.statusCode = statusCode
@@ -173,5 +185,5 @@ func (e *HTTPResponseEvent) WriteText(formatter TextFormatter, buf *bytes.Buffer
 
 // WriteJSON implements JSONWritable.
 func (e *HTTPResponseEvent) WriteJSON() JSONObj {
-	return JSONWriteHTTPResponse(e.req, e.statusCode, e.contentLength, e.contentType, e.contentEncoding, e.elapsed)
+	return JSONWriteHTTPRequestDetails(JSONWriteHTTPResponse(e.req, e.statusCode, e.contentLength, e.contentType, e.contentEncoding, e.elapsed), e.req, e.route, e.headerAllowList)
 }
@@ -45,6 +45,13 @@ type EventAnnotations interface {
 	Annotations() map[string]string
 }
 
+// EventTrace is a type that carries trace correlation identifiers.
+type EventTrace interface {
+	SetTrace(traceID, spanID string)
+	TraceID() string
+	SpanID() string
+}
+
 // EventEnabled determines if we should allow an event to be triggered or not.
 type EventEnabled interface {
 	IsEnabled() bool
@@ -169,6 +176,18 @@ type Writer interface {
 	OutputFormat() OutputFormat
 }
 
+// WriterFlusher is a writer that buffers output and must be flushed.
+// Writers implementing it are flushed when the logger is drained or closed.
+type WriterFlusher interface {
+	Flush() error
+}
+
+// WriterStopper is a writer with a background process, e.g. a flush loop, that must be stopped.
+// Writers implementing it are stopped, which flushes them, when the logger is closed.
+type WriterStopper interface {
+	Stop() error
+}
+
 // --------------------------------------------------------------------------------
 // testing helpers
 // --------------------------------------------------------------------------------
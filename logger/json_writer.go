@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
 	"os"
@@ -19,6 +20,10 @@ const (
 	JSONFieldErr = "err"
 	// JSONFieldEventHeadings is a common json field.
 	JSONFieldEventHeadings = "event-headings"
+	// JSONFieldTraceID is a common json field.
+	JSONFieldTraceID = "trace_id"
+	// JSONFieldSpanID is a common json field.
+	JSONFieldSpanID = "span_id"
 
 	// DefaultJSONWriterPretty is a default.
 	DefaultJSONWriterPretty = false
@@ -44,6 +49,7 @@ type JSONWritable interface {
 func NewJSONWriter(output io.Writer) *JSONWriter {
 	return &JSONWriter{
 		output:           NewInterlockedWriter(output),
+		bufferPool:       NewBufferPool(DefaultBufferPoolSize),
 		pretty:           DefaultJSONWriterPretty,
 		includeTimestamp: DefaultJSONIncludeTimestamp,
 	}
@@ -64,6 +70,7 @@ func NewJSONWriterFromConfig(cfg *JSONWriterConfig) *JSONWriter {
 	return &JSONWriter{
 		output:      NewInterlockedWriter(os.Stdout),
 		errorOutput: NewInterlockedWriter(os.Stderr),
+		bufferPool:  NewBufferPool(DefaultBufferPoolSize),
 		pretty:      cfg.GetPretty(),
 	}
 }
@@ -74,6 +81,8 @@ type JSONWriter struct {
 	errorOutput      io.Writer
 	pretty           bool
 	includeTimestamp bool
+
+	bufferPool *BufferPool
 }
 
 // OutputFormat returns the output format.
@@ -139,22 +148,55 @@ func (jw *JSONWriter) WriteError(e Event) error {
 }
 
 func (jw *JSONWriter) write(output io.Writer, e Event) error {
-	encoder := json.NewEncoder(output)
+	var buf *bytes.Buffer
+	if jw.bufferPool != nil {
+		buf = jw.bufferPool.Get()
+		defer jw.bufferPool.Put(buf)
+	} else {
+		buf = new(bytes.Buffer)
+	}
+
+	encoder := json.NewEncoder(buf)
 	if jw.pretty {
 		encoder.SetIndent("", "\t")
 	}
 
-	if typed, isTyped := e.(JSONWritable); isTyped {
-		fields := typed.WriteJSON()
-		if typed, isTyped := e.(EventHeadings); isTyped && len(typed.Headings()) > 0 {
-			fields[JSONFieldEventHeadings] = typed.Headings()
-		}
-		fields[JSONFieldFlag] = e.Flag()
+	var err error
+	if fields, isFields := JSONFields(e); isFields {
 		if jw.includeTimestamp {
 			fields[JSONFieldTimestamp] = e.Timestamp()
 		}
-		return encoder.Encode(fields)
+		err = encoder.Encode(fields)
+	} else {
+		err = encoder.Encode(e)
+	}
+	if err != nil {
+		return err
 	}
+	_, err = buf.WriteTo(output)
+	return err
+}
 
-	return encoder.Encode(e)
+// JSONFields returns the json fields for an event, including the common meta fields.
+// It returns false if the event does not implement `JSONWritable`, in which case
+// the event should be marshaled directly.
+func JSONFields(e Event) (JSONObj, bool) {
+	typed, isTyped := e.(JSONWritable)
+	if !isTyped {
+		return nil, false
+	}
+	fields := typed.WriteJSON()
+	if typed, isTyped := e.(EventHeadings); isTyped && len(typed.Headings()) > 0 {
+		fields[JSONFieldEventHeadings] = typed.Headings()
+	}
+	if typed, isTyped := e.(EventTrace); isTyped {
+		if traceID := typed.TraceID(); len(traceID) > 0 {
+			fields[JSONFieldTraceID] = traceID
+		}
+		if spanID := typed.SpanID(); len(spanID) > 0 {
+			fields[JSONFieldSpanID] = spanID
+		}
+	}
+	fields[JSONFieldFlag] = e.Flag()
+	return fields, true
 }
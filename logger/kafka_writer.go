@@ -0,0 +1,380 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// KafkaCompressionNone is a kafka compression codec.
+	KafkaCompressionNone KafkaCompression = "none"
+	// KafkaCompressionGzip is a kafka compression codec.
+	KafkaCompressionGzip KafkaCompression = "gzip"
+	// KafkaCompressionSnappy is a kafka compression codec.
+	KafkaCompressionSnappy KafkaCompression = "snappy"
+	// KafkaCompressionLZ4 is a kafka compression codec.
+	KafkaCompressionLZ4 KafkaCompression = "lz4"
+	// KafkaCompressionZstd is a kafka compression codec.
+	KafkaCompressionZstd KafkaCompression = "zstd"
+
+	// ErrKafkaProducerUnset is returned when a kafka writer is flushed without a producer.
+	ErrKafkaProducerUnset exception.Class = "kafka: producer unset"
+)
+
+// Asserts kafka writer is a writer.
+var (
+	_ Writer        = &KafkaWriter{}
+	_ WriterFlusher = &KafkaWriter{}
+)
+
+// KafkaCompression is the compression codec a producer should use for a batch.
+type KafkaCompression string
+
+// KafkaMessage is a serialized event to publish.
+type KafkaMessage struct {
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+}
+
+// KafkaBatch is a batch of messages to publish to a topic.
+type KafkaBatch struct {
+	Topic       string
+	Compression KafkaCompression
+	Messages    []KafkaMessage
+}
+
+// KafkaProducer publishes batches of messages.
+// The logger does not ship a kafka client; implement this interface with the client of your choice,
+// mapping the batch compression to the client's codec settings.
+type KafkaProducer interface {
+	Produce(KafkaBatch) error
+}
+
+// KafkaProducerFunc is a function that implements `KafkaProducer`.
+type KafkaProducerFunc func(KafkaBatch) error
+
+// Produce implements `KafkaProducer`.
+func (kpf KafkaProducerFunc) Produce(batch KafkaBatch) error {
+	return kpf(batch)
+}
+
+// KafkaPartitionKey returns the partition key for an event.
+// A nil key lets the producer pick the partition.
+type KafkaPartitionKey func(Event) []byte
+
+// KafkaPartitionKeyFlag partitions events by flag.
+func KafkaPartitionKeyFlag(e Event) []byte {
+	return []byte(e.Flag())
+}
+
+// KafkaPartitionKeyLabel returns a partition key that partitions events by a label value.
+func KafkaPartitionKeyLabel(label string) KafkaPartitionKey {
+	return func(e Event) []byte {
+		if typed, isTyped := e.(EventLabels); isTyped {
+			if value, hasValue := typed.Labels()[label]; hasValue {
+				return []byte(value)
+			}
+		}
+		return nil
+	}
+}
+
+// ParseKafkaPartitionKey parses a partition key setting, which is one of
+// `none` (or empty), `flag` or `label:<name>`.
+func ParseKafkaPartitionKey(value string) KafkaPartitionKey {
+	switch {
+	case strings.EqualFold(value, "flag"):
+		return KafkaPartitionKeyFlag
+	case strings.HasPrefix(strings.ToLower(value), "label:"):
+		return KafkaPartitionKeyLabel(value[len("label:"):])
+	default:
+		return nil
+	}
+}
+
+// NewKafkaWriter returns a new kafka writer for a given producer and topic.
+// It must be started with `.Start()` to flush on an interval.
+func NewKafkaWriter(producer KafkaProducer, topic string) *KafkaWriter {
+	return &KafkaWriter{
+		producer:      producer,
+		topic:         topic,
+		compression:   DefaultKafkaCompression,
+		batchSize:     DefaultKafkaBatchSize,
+		flushInterval: DefaultKafkaFlushInterval,
+		maxRetries:    DefaultKafkaMaxRetries,
+		retryBackoff:  DefaultKafkaRetryBackoff,
+	}
+}
+
+// NewKafkaWriterFromEnv returns a new kafka writer for a given producer from the environment.
+func NewKafkaWriterFromEnv(producer KafkaProducer) *KafkaWriter {
+	return NewKafkaWriterFromConfig(producer, NewKafkaWriterConfigFromEnv())
+}
+
+// NewKafkaWriterFromConfig returns a new kafka writer for a given producer from a config.
+func NewKafkaWriterFromConfig(producer KafkaProducer, cfg *KafkaWriterConfig) *KafkaWriter {
+	return NewKafkaWriter(producer, cfg.GetTopic()).
+		WithCompression(cfg.GetCompression()).
+		WithBatchSize(cfg.GetBatchSize()).
+		WithFlushInterval(cfg.GetFlushInterval()).
+		WithMaxRetries(cfg.GetMaxRetries()).
+		WithPartitionKey(ParseKafkaPartitionKey(cfg.GetPartitionKey()))
+}
+
+// KafkaWriter batches events as json and publishes them to a kafka topic.
+type KafkaWriter struct {
+	sync.Mutex
+
+	producer     KafkaProducer
+	topic        string
+	compression  KafkaCompression
+	partitionKey KafkaPartitionKey
+
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+
+	errors chan error
+
+	batch     []KafkaMessage
+	producing chan struct{}
+	flusher   *async.Interval
+}
+
+// OutputFormat returns the output format.
+func (kw *KafkaWriter) OutputFormat() OutputFormat {
+	return OutputFormatJSON
+}
+
+// Output returns nil; the kafka writer has no local output stream.
+func (kw *KafkaWriter) Output() io.Writer {
+	return nil
+}
+
+// ErrorOutput returns nil; the kafka writer has no local output stream.
+func (kw *KafkaWriter) ErrorOutput() io.Writer {
+	return nil
+}
+
+// Producer returns the producer.
+func (kw *KafkaWriter) Producer() KafkaProducer {
+	return kw.producer
+}
+
+// Topic returns the topic.
+func (kw *KafkaWriter) Topic() string {
+	return kw.topic
+}
+
+// WithCompression sets the compression codec batches are published with.
+func (kw *KafkaWriter) WithCompression(compression KafkaCompression) *KafkaWriter {
+	kw.compression = compression
+	return kw
+}
+
+// Compression returns the compression codec batches are published with.
+func (kw *KafkaWriter) Compression() KafkaCompression {
+	return kw.compression
+}
+
+// WithPartitionKey sets the partition key function.
+func (kw *KafkaWriter) WithPartitionKey(partitionKey KafkaPartitionKey) *KafkaWriter {
+	kw.partitionKey = partitionKey
+	return kw
+}
+
+// PartitionKey returns the partition key function.
+func (kw *KafkaWriter) PartitionKey() KafkaPartitionKey {
+	return kw.partitionKey
+}
+
+// WithBatchSize sets the number of events that triggers a flush.
+func (kw *KafkaWriter) WithBatchSize(batchSize int) *KafkaWriter {
+	kw.batchSize = batchSize
+	return kw
+}
+
+// BatchSize returns the number of events that triggers a flush.
+func (kw *KafkaWriter) BatchSize() int {
+	return kw.batchSize
+}
+
+// WithFlushInterval sets the interval pending events are flushed on.
+func (kw *KafkaWriter) WithFlushInterval(interval time.Duration) *KafkaWriter {
+	kw.flushInterval = interval
+	return kw
+}
+
+// FlushInterval returns the interval pending events are flushed on.
+func (kw *KafkaWriter) FlushInterval() time.Duration {
+	return kw.flushInterval
+}
+
+// WithMaxRetries sets the number of times a failed produce is retried.
+func (kw *KafkaWriter) WithMaxRetries(maxRetries int) *KafkaWriter {
+	kw.maxRetries = maxRetries
+	return kw
+}
+
+// MaxRetries returns the number of times a failed produce is retried.
+func (kw *KafkaWriter) MaxRetries() int {
+	return kw.maxRetries
+}
+
+// WithRetryBackoff sets the base delay between retries; it is multiplied by the attempt number.
+func (kw *KafkaWriter) WithRetryBackoff(backoff time.Duration) *KafkaWriter {
+	kw.retryBackoff = backoff
+	return kw
+}
+
+// RetryBackoff returns the base delay between retries.
+func (kw *KafkaWriter) RetryBackoff() time.Duration {
+	return kw.retryBackoff
+}
+
+// WithErrors sets a channel that background flush errors are sent to.
+func (kw *KafkaWriter) WithErrors(errors chan error) *KafkaWriter {
+	kw.errors = errors
+	return kw
+}
+
+// Errors returns the background flush error channel.
+func (kw *KafkaWriter) Errors() chan error {
+	return kw.errors
+}
+
+// Start starts flushing pending events on the flush interval.
+func (kw *KafkaWriter) Start() *KafkaWriter {
+	kw.Lock()
+	defer kw.Unlock()
+	if kw.flusher != nil {
+		return kw
+	}
+	kw.flusher = async.NewInterval(kw.Flush, kw.flushInterval).WithErrors(kw.errors)
+	kw.flusher.Start()
+	return kw
+}
+
+// Stop stops the background flush and flushes any pending events.
+func (kw *KafkaWriter) Stop() error {
+	kw.Lock()
+	flusher := kw.flusher
+	kw.flusher = nil
+	kw.Unlock()
+
+	if flusher != nil {
+		flusher.Stop()
+	}
+	return kw.Flush()
+}
+
+// Write queues an event to be published.
+// Full batches are produced in the background, so writes don't wait on the producer; errors producing
+// them are sent to the errors channel.
+func (kw *KafkaWriter) Write(e Event) error {
+	return kw.write(e)
+}
+
+// WriteError queues an event to be published.
+func (kw *KafkaWriter) WriteError(e Event) error {
+	return kw.write(e)
+}
+
+// Flush waits for a full batch being produced in the background, if any, and publishes any pending events.
+func (kw *KafkaWriter) Flush() error {
+	kw.Lock()
+	producing := kw.producing
+	kw.Unlock()
+	if producing != nil {
+		<-producing
+	}
+
+	kw.Lock()
+	batch := kw.batch
+	kw.batch = nil
+	kw.Unlock()
+	return kw.produce(batch)
+}
+
+func (kw *KafkaWriter) write(e Event) error {
+	message, err := kw.encode(e)
+	if err != nil {
+		return err
+	}
+
+	kw.Lock()
+	defer kw.Unlock()
+	kw.batch = append(kw.batch, message)
+	// one full batch is produced at a time; events queue behind it until it's done.
+	if len(kw.batch) < kw.batchSize || kw.producing != nil {
+		return nil
+	}
+	batch := kw.batch
+	kw.batch = nil
+	kw.producing = make(chan struct{})
+	go kw.produceBackground(batch, kw.producing)
+	return nil
+}
+
+// produceBackground produces a full batch, sending any error to the errors channel.
+func (kw *KafkaWriter) produceBackground(batch []KafkaMessage, producing chan struct{}) {
+	err := kw.produce(batch)
+	kw.Lock()
+	kw.producing = nil
+	kw.Unlock()
+	close(producing)
+
+	if err != nil && kw.errors != nil {
+		kw.errors <- err
+	}
+}
+
+func (kw *KafkaWriter) encode(e Event) (KafkaMessage, error) {
+	var value interface{} = e
+	if fields, isFields := JSONFields(e); isFields {
+		fields[JSONFieldTimestamp] = e.Timestamp()
+		value = fields
+	}
+	contents, err := json.Marshal(value)
+	if err != nil {
+		return KafkaMessage{}, exception.New(err)
+	}
+	message := KafkaMessage{
+		Value:     contents,
+		Timestamp: e.Timestamp(),
+	}
+	if kw.partitionKey != nil {
+		message.Key = kw.partitionKey(e)
+	}
+	return message, nil
+}
+
+func (kw *KafkaWriter) produce(messages []KafkaMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	if kw.producer == nil {
+		return exception.New(ErrKafkaProducerUnset)
+	}
+
+	batch := KafkaBatch{
+		Topic:       kw.topic,
+		Compression: kw.compression,
+		Messages:    messages,
+	}
+	for attempt := 0; ; attempt++ {
+		err := kw.producer.Produce(batch)
+		if err == nil || attempt >= kw.maxRetries {
+			return err
+		}
+		time.Sleep(time.Duration(attempt+1) * kw.retryBackoff)
+	}
+}
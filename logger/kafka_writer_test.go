@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestKafkaWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var batches []KafkaBatch
+	producer := KafkaProducerFunc(func(batch KafkaBatch) error {
+		lock.Lock()
+		defer lock.Unlock()
+		batches = append(batches, batch)
+		return nil
+	})
+
+	kw := NewKafkaWriter(producer, "logs").WithBatchSize(2).WithPartitionKey(KafkaPartitionKeyFlag)
+	assert.Nil(kw.Write(Messagef(Info, "foo")))
+	assert.Empty(batches)
+	assert.Nil(kw.WriteError(Errorf(Error, "bar")))
+	// full batches are produced in the background; flush waits for them.
+	assert.Nil(kw.Flush())
+
+	lock.Lock()
+	assert.Len(batches, 1)
+	assert.Equal("logs", batches[0].Topic)
+	assert.Equal(KafkaCompressionGzip, batches[0].Compression)
+	assert.Len(batches[0].Messages, 2)
+	assert.Equal("info", string(batches[0].Messages[0].Key))
+	assert.Equal("error", string(batches[0].Messages[1].Key))
+
+	var fields map[string]interface{}
+	assert.Nil(json.Unmarshal(batches[0].Messages[0].Value, &fields))
+	assert.Equal("foo", fields[JSONFieldMessage])
+	assert.NotNil(fields[JSONFieldTimestamp])
+	lock.Unlock()
+
+	assert.Nil(kw.Write(Messagef(Info, "baz")))
+	assert.Nil(kw.Stop())
+	lock.Lock()
+	assert.Len(batches, 2)
+	lock.Unlock()
+}
+
+func TestKafkaWriterBackgroundRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int32
+	producing := make(chan struct{})
+	producer := KafkaProducerFunc(func(batch KafkaBatch) error {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			<-producing
+		}
+		return fmt.Errorf("broker unavailable")
+	})
+
+	errors := make(chan error, 1)
+	kw := NewKafkaWriter(producer, "logs").WithBatchSize(1).WithMaxRetries(2).WithRetryBackoff(time.Millisecond).WithErrors(errors)
+
+	// writes don't wait on the producer.
+	assert.Nil(kw.Write(Messagef(Info, "foo")))
+	assert.Nil(kw.Write(Messagef(Info, "bar")))
+	close(producing)
+
+	err := <-errors
+	assert.Equal("broker unavailable", err.Error())
+	assert.Equal(3, atomic.LoadInt32(&attempts))
+
+	// the event queued behind the failed batch is still produced, with retries.
+	assert.NotNil(kw.Flush())
+	assert.Equal(6, atomic.LoadInt32(&attempts))
+}
+
+func TestKafkaWriterProducerUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	kw := NewKafkaWriter(nil, "logs")
+	assert.Nil(kw.Write(Messagef(Info, "foo")))
+	assert.True(exception.Is(kw.Flush(), ErrKafkaProducerUnset))
+}
+
+func TestKafkaPartitionKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	e := Messagef(Info, "foo").WithLabel("tenant", "acme")
+	assert.Nil(ParseKafkaPartitionKey(""))
+	assert.Nil(ParseKafkaPartitionKey("none"))
+	assert.Equal("info", string(ParseKafkaPartitionKey("flag")(e)))
+	assert.Equal("acme", string(ParseKafkaPartitionKey("label:tenant")(e)))
+	assert.Nil(ParseKafkaPartitionKey("label:missing")(e))
+}
+
+func TestKafkaWriterConfigFromEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(KafkaWriterConfig{}.IsZero())
+
+	env.Env().Set(EnvVarKafkaTopic, "logs")
+	defer env.Env().Restore(EnvVarKafkaTopic)
+	env.Env().Set("LOG_KAFKA_COMPRESSION", "SNAPPY")
+	defer env.Env().Restore("LOG_KAFKA_COMPRESSION")
+	env.Env().Set("LOG_KAFKA_PARTITION_KEY", "flag")
+	defer env.Env().Restore("LOG_KAFKA_PARTITION_KEY")
+
+	cfg := NewKafkaWriterConfigFromEnv()
+	assert.False(cfg.IsZero())
+
+	kw := NewKafkaWriterFromConfig(nil, cfg)
+	assert.Equal("logs", kw.Topic())
+	assert.Equal(KafkaCompressionSnappy, kw.Compression())
+	assert.NotNil(kw.PartitionKey())
+	assert.Equal(DefaultKafkaBatchSize, kw.BatchSize())
+	assert.Equal(DefaultKafkaFlushInterval, kw.FlushInterval())
+}
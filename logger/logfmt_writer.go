@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// LogfmtFieldTimestamp is a common logfmt field.
+	LogfmtFieldTimestamp = "ts"
+	// LogfmtFieldLevel is a common logfmt field.
+	LogfmtFieldLevel = "level"
+	// LogfmtFieldMessage is a common logfmt field.
+	LogfmtFieldMessage = "msg"
+)
+
+// Asserts logfmt writer is a writer.
+var (
+	_ Writer = &LogfmtWriter{}
+)
+
+// NewLogfmtWriter returns a new logfmt writer for a given output.
+func NewLogfmtWriter(output io.Writer) *LogfmtWriter {
+	return &LogfmtWriter{
+		output:        NewInterlockedWriter(output),
+		bufferPool:    NewBufferPool(DefaultBufferPoolSize),
+		showTimestamp: DefaultTextWriterShowTimestamp,
+		timeFormat:    DefaultTextTimeFormat,
+	}
+}
+
+// NewLogfmtWriterStdout returns a new logfmt writer to stdout/stderr.
+func NewLogfmtWriterStdout() *LogfmtWriter {
+	return NewLogfmtWriter(os.Stdout).WithErrorOutput(os.Stderr)
+}
+
+// NewLogfmtWriterFromEnv returns a new logfmt writer from the environment.
+func NewLogfmtWriterFromEnv() *LogfmtWriter {
+	return NewLogfmtWriterFromConfig(NewLogfmtWriterConfigFromEnv())
+}
+
+// NewLogfmtWriterFromConfig returns a new logfmt writer from a config.
+func NewLogfmtWriterFromConfig(cfg *LogfmtWriterConfig) *LogfmtWriter {
+	return &LogfmtWriter{
+		output:        NewInterlockedWriter(os.Stdout),
+		errorOutput:   NewInterlockedWriter(os.Stderr),
+		bufferPool:    NewBufferPool(DefaultBufferPoolSize),
+		showTimestamp: cfg.GetShowTimestamp(),
+		timeFormat:    cfg.GetTimeFormat(),
+	}
+}
+
+// LogfmtWriter writes events as logfmt `key=value` pairs, e.g. `ts=... level=info msg="hello world" foo=bar`.
+// Event specific fields are taken from `JSONWritable` and written in key order.
+type LogfmtWriter struct {
+	output      io.Writer
+	errorOutput io.Writer
+
+	showTimestamp bool
+	timeFormat    string
+
+	bufferPool *BufferPool
+}
+
+// OutputFormat returns the output format.
+func (lw *LogfmtWriter) OutputFormat() OutputFormat {
+	return OutputFormatLogfmt
+}
+
+// WithShowTimestamp sets a formatting option.
+func (lw *LogfmtWriter) WithShowTimestamp(showTimestamp bool) *LogfmtWriter {
+	lw.showTimestamp = showTimestamp
+	return lw
+}
+
+// ShowTimestamp is a formatting option.
+func (lw *LogfmtWriter) ShowTimestamp() bool {
+	return lw.showTimestamp
+}
+
+// WithTimeFormat sets a formatting option.
+func (lw *LogfmtWriter) WithTimeFormat(timeFormat string) *LogfmtWriter {
+	lw.timeFormat = timeFormat
+	return lw
+}
+
+// TimeFormat is a formatting option.
+func (lw *LogfmtWriter) TimeFormat() string {
+	return lw.timeFormat
+}
+
+// Output returns the output.
+func (lw *LogfmtWriter) Output() io.Writer {
+	return lw.output
+}
+
+// WithOutput sets the primary output.
+func (lw *LogfmtWriter) WithOutput(output io.Writer) *LogfmtWriter {
+	lw.output = NewInterlockedWriter(output)
+	return lw
+}
+
+// ErrorOutput returns an io.Writer for the error stream.
+func (lw *LogfmtWriter) ErrorOutput() io.Writer {
+	if lw.errorOutput != nil {
+		return lw.errorOutput
+	}
+	return lw.output
+}
+
+// WithErrorOutput sets the error output.
+func (lw *LogfmtWriter) WithErrorOutput(errorOutput io.Writer) *LogfmtWriter {
+	lw.errorOutput = NewInterlockedWriter(errorOutput)
+	return lw
+}
+
+// Write writes to stdout.
+func (lw *LogfmtWriter) Write(e Event) error {
+	return lw.write(lw.Output(), e)
+}
+
+// WriteError writes to stderr (or stdout if .errorOutput is unset).
+func (lw *LogfmtWriter) WriteError(e Event) error {
+	return lw.write(lw.ErrorOutput(), e)
+}
+
+func (lw *LogfmtWriter) write(output io.Writer, e Event) error {
+	buf := lw.bufferPool.Get()
+	defer lw.bufferPool.Put(buf)
+
+	if lw.showTimestamp {
+		timeFormat := DefaultTextTimeFormat
+		if len(lw.timeFormat) > 0 {
+			timeFormat = lw.timeFormat
+		}
+		lw.writePair(buf, LogfmtFieldTimestamp, e.Timestamp().Format(timeFormat))
+	}
+	lw.writePair(buf, LogfmtFieldLevel, string(e.Flag()))
+
+	fields, isFields := JSONFields(e)
+	if !isFields {
+		if typed, isTyped := e.(fmt.Stringer); isTyped {
+			lw.writePair(buf, LogfmtFieldMessage, typed.String())
+		}
+	} else {
+		delete(fields, JSONFieldFlag)
+		if message, hasMessage := fields[JSONFieldMessage]; hasMessage {
+			lw.writePair(buf, LogfmtFieldMessage, message)
+			delete(fields, JSONFieldMessage)
+		} else if err, hasErr := fields[JSONFieldErr]; hasErr {
+			lw.writePair(buf, LogfmtFieldMessage, err)
+			delete(fields, JSONFieldErr)
+		}
+		lw.writeFields(buf, "", fields)
+	}
+
+	buf.WriteRune(RuneNewline)
+	_, err := buf.WriteTo(output)
+	return err
+}
+
+func (lw *LogfmtWriter) writeFields(buf *bytes.Buffer, prefix string, fields map[string]Any) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if nested, isNested := fields[key].(map[string]Any); isNested {
+			lw.writeFields(buf, prefix+key+".", nested)
+			continue
+		}
+		lw.writePair(buf, prefix+key, fields[key])
+	}
+}
+
+func (lw *LogfmtWriter) writePair(buf *bytes.Buffer, key string, value Any) {
+	if buf.Len() > 0 {
+		buf.WriteRune(RuneSpace)
+	}
+	buf.WriteString(LogfmtKey(key))
+	buf.WriteRune('=')
+	buf.WriteString(LogfmtValue(value))
+}
+
+// LogfmtKey sanitizes a key for logfmt output by replacing reserved characters.
+func LogfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// LogfmtValue formats a value for logfmt output, quoting it if required.
+func LogfmtValue(value Any) string {
+	if value == nil {
+		return ""
+	}
+	raw := logfmtRaw(value)
+	if logfmtNeedsQuote(raw) {
+		return strconv.Quote(raw)
+	}
+	return raw
+}
+
+// logfmtRaw formats a value as an unquoted string.
+func logfmtRaw(value Any) (raw string) {
+	switch typed := value.(type) {
+	case string:
+		raw = typed
+	case Flag:
+		raw = string(typed)
+	case []string:
+		raw = strings.Join(typed, ",")
+	case time.Time:
+		raw = typed.Format(time.RFC3339Nano)
+	case time.Duration:
+		raw = typed.String()
+	case error:
+		raw = typed.Error()
+	case fmt.Stringer:
+		raw = typed.String()
+	case json.Marshaler:
+		contents, err := typed.MarshalJSON()
+		if err != nil {
+			raw = err.Error()
+		} else {
+			raw = string(contents)
+		}
+	default:
+		raw = fmt.Sprint(typed)
+	}
+	return
+}
+
+func logfmtNeedsQuote(value string) bool {
+	if len(value) == 0 {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' {
+			return true
+		}
+	}
+	return false
+}
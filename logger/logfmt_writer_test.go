@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLogfmtWriterWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	writer := NewLogfmtWriter(buffer).WithShowTimestamp(false)
+	assert.Equal(OutputFormatLogfmt, writer.OutputFormat())
+	assert.Nil(writer.Write(Messagef(Info, "test string").WithHeadings("unit-test")))
+	assert.Equal("level=info msg=\"test string\" event-headings=unit-test\n", buffer.String())
+}
+
+func TestLogfmtWriterWriteTimestamp(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	writer := NewLogfmtWriter(buffer).WithTimeFormat(time.RFC3339)
+	ts := time.Date(2018, 01, 02, 03, 04, 05, 0, time.UTC)
+	assert.Nil(writer.Write(Messagef(Info, "test").WithTimestamp(ts)))
+	assert.Equal("ts=2018-01-02T03:04:05Z level=info msg=test\n", buffer.String())
+}
+
+func TestLogfmtWriterWriteError(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	errorOutput := bytes.NewBuffer(nil)
+	writer := NewLogfmtWriter(output).WithErrorOutput(errorOutput).WithShowTimestamp(false)
+	assert.Nil(writer.WriteError(Errorf(Error, "bad thing")))
+	assert.Empty(output.String())
+	assert.Equal("level=error msg=\"bad thing\"\n", errorOutput.String())
+}
+
+func TestLogfmtWriterWriteFields(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	writer := NewLogfmtWriter(buffer).WithShowTimestamp(false)
+	req := &http.Request{Method: "GET", Host: "localhost", URL: &url.URL{Path: "/foo"}, RemoteAddr: "127.0.0.1:8080"}
+	assert.Nil(writer.Write(NewHTTPRequestEvent(req)))
+	assert.Equal("level=http.request host=localhost ip=127.0.0.1 path=/foo verb=GET\n", buffer.String())
+}
+
+func TestLogfmtValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("foo", LogfmtValue("foo"))
+	assert.Equal("\"\"", LogfmtValue(""))
+	assert.Equal("\"foo bar\"", LogfmtValue("foo bar"))
+	assert.Equal("\"a=b\"", LogfmtValue("a=b"))
+	assert.Equal("\"say \\\"hi\\\"\"", LogfmtValue("say \"hi\""))
+	assert.Equal("123", LogfmtValue(123))
+	assert.Equal("1.5s", LogfmtValue(1500*time.Millisecond))
+	assert.Equal("a,b", LogfmtValue([]string{"a", "b"}))
+	assert.Equal("foo_bar", LogfmtKey("foo bar"))
+}
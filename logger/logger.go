@@ -1,12 +1,17 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"hash/fnv"
 	"net/http"
 	"os"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
 )
 
 const (
@@ -19,7 +24,7 @@ const (
 )
 
 var (
-	// DefaultListenerWorkers is the default number of workers per listener.
+	// DefaultListenerWorkers is the default number of workers that call listeners.
 	DefaultListenerWorkers = runtime.NumCPU()
 )
 
@@ -34,8 +39,12 @@ func FatalExit(err error) {
 // New returns a new logger with a given set of enabled flags, without a writer provisioned.
 func New(flags ...Flag) *Logger {
 	l := &Logger{
-		recoverPanics: DefaultRecoverPanics,
-		flags:         NewFlagSet(flags...),
+		recoverPanics:            DefaultRecoverPanics,
+		flags:                    NewFlagSet(flags...),
+		writeWorkerQueueDepth:    DefaultWriteQueueDepth,
+		listenerWorkerQueueDepth: DefaultListenerQueueDepth,
+		listenerWorkers:          DefaultListenerWorkers,
+		fatalFlushTimeout:        DefaultFatalFlushTimeout,
 	}
 	l.writeWorker = NewWorker(l, l.Write, DefaultWriteQueueDepth)
 	l.writeWorker.Start()
@@ -49,6 +58,8 @@ func NewFromConfig(cfg *Config) *Logger {
 		flags:                    NewFlagSetFromValues(cfg.GetFlags()...),
 		writeWorkerQueueDepth:    cfg.GetWriteQueueDepth(),
 		listenerWorkerQueueDepth: cfg.GetListenerQueueDepth(),
+		listenerWorkers:          cfg.GetListenerWorkers(),
+		fatalFlushTimeout:        DefaultFatalFlushTimeout,
 	}
 	l.writeWorker = NewWorker(l, l.Write, l.writeWorkerQueueDepth)
 	l.writeWorker.Start()
@@ -61,7 +72,8 @@ func NewFromConfig(cfg *Config) *Logger {
 }
 
 // NewFromEnv returns a new agent with settings read from the environment,
-// including the underlying writer.
+// including the underlying writer. Settings that are not set explicitly default
+// to the profile named by `LOG_PROFILE`, if it is set.
 func NewFromEnv() *Logger {
 	return NewFromConfig(NewConfigFromEnv())
 }
@@ -94,9 +106,14 @@ func NewJSON() *Logger {
 type Logger struct {
 	writers []Writer
 
+	routesLock sync.RWMutex
+	routes     map[Flag][]Writer
+
 	heading                  string
 	writeWorkerQueueDepth    int
 	listenerWorkerQueueDepth int
+	listenerWorkers          int
+	fatalFlushTimeout        time.Duration
 
 	state int32
 
@@ -109,10 +126,16 @@ type Logger struct {
 	workersLock sync.Mutex
 	workers     map[Flag]map[string]*Worker
 
+	listenerPoolLock sync.Mutex
+	listenerPool     []*Worker
+
 	writeWorkerLock sync.Mutex
 	writeWorker     *Worker
 
+	traceCorrelator TraceCorrelator
+
 	recoverPanics bool
+	poolEvents    bool
 }
 
 // CanStart returns if the latch can start.
@@ -148,6 +171,8 @@ func (l *Logger) WriteWorkerQueueDepth() int {
 }
 
 // WithListenerWorkerQueueDepth sets the worker queue depth.
+// Each listener worker has its own queue; the depth must be set before the first listener is added.
+// Async triggers block while a listener worker's queue is full.
 func (l *Logger) WithListenerWorkerQueueDepth(queueDepth int) *Logger {
 	l.listenerWorkerQueueDepth = queueDepth
 	return l
@@ -158,6 +183,30 @@ func (l *Logger) ListenerWorkerQueueDepth() int {
 	return l.listenerWorkerQueueDepth
 }
 
+// WithListenerWorkers sets the number of workers that call listeners for async triggers.
+// Events with the same flag are always handled by the same worker, so listeners see them in order.
+// The workers are started with the first listener; the count must be set before then.
+func (l *Logger) WithListenerWorkers(workers int) *Logger {
+	l.listenerWorkers = workers
+	return l
+}
+
+// ListenerWorkers returns the number of workers that call listeners for async triggers.
+func (l *Logger) ListenerWorkers() int {
+	return l.listenerWorkers
+}
+
+// WithFatalFlushTimeout sets the time a fatal event waits for queued events and buffered writers to be flushed.
+func (l *Logger) WithFatalFlushTimeout(timeout time.Duration) *Logger {
+	l.fatalFlushTimeout = timeout
+	return l
+}
+
+// FatalFlushTimeout returns the time a fatal event waits for queued events and buffered writers to be flushed.
+func (l *Logger) FatalFlushTimeout() time.Duration {
+	return l.fatalFlushTimeout
+}
+
 // Writers returns the output writers for events.
 func (l *Logger) Writers() []Writer {
 	return l.writers
@@ -175,6 +224,56 @@ func (l *Logger) WithWriter(writer Writer) *Logger {
 	return l
 }
 
+// WithRoute directs events for a flag to a given set of writers instead of the logger writers.
+// Calling it again for the same flag replaces the route.
+func (l *Logger) WithRoute(flag Flag, writers ...Writer) *Logger {
+	l.routesLock.Lock()
+	defer l.routesLock.Unlock()
+
+	if l.routes == nil {
+		l.routes = map[Flag][]Writer{}
+	}
+	l.routes[flag] = writers
+	return l
+}
+
+// RemoveRoute removes the route for a flag, sending its events back to the logger writers.
+func (l *Logger) RemoveRoute(flag Flag) {
+	l.routesLock.Lock()
+	defer l.routesLock.Unlock()
+
+	if l.routes == nil {
+		return
+	}
+	delete(l.routes, flag)
+}
+
+// Routes returns a copy of the routing table of flags to writers.
+func (l *Logger) Routes() map[Flag][]Writer {
+	l.routesLock.RLock()
+	defer l.routesLock.RUnlock()
+
+	routes := make(map[Flag][]Writer, len(l.routes))
+	for flag, writers := range l.routes {
+		routes[flag] = append([]Writer(nil), writers...)
+	}
+	return routes
+}
+
+// WritersFor returns the writers events for a given flag are written to.
+// It returns the routed writers if the flag has a route, otherwise the logger writers.
+func (l *Logger) WritersFor(flag Flag) []Writer {
+	l.routesLock.RLock()
+	defer l.routesLock.RUnlock()
+
+	if l.routes != nil {
+		if writers, hasRoute := l.routes[flag]; hasRoute {
+			return writers
+		}
+	}
+	return l.writers
+}
+
 // RecoversPanics returns if we should recover panics in logger listeners.
 func (l *Logger) RecoversPanics() bool {
 	return l.recoverPanics
@@ -186,6 +285,31 @@ func (l *Logger) WithRecoverPanics(value bool) *Logger {
 	return l
 }
 
+// PoolsEvents returns if the builtin message helpers (`Infof`, `Debugf` etc.) reuse events from a pool.
+func (l *Logger) PoolsEvents() bool {
+	return l.poolEvents
+}
+
+// WithPoolEvents sets if the builtin message helpers (`Infof`, `Debugf` etc.) reuse events from a pool,
+// which saves an allocation per message. It is off by default; only enable it if every writer either
+// doesn't keep events after `Write` returns or calls `Retain` on the events it keeps.
+func (l *Logger) WithPoolEvents(value bool) *Logger {
+	l.poolEvents = value
+	return l
+}
+
+// WithTraceCorrelator sets the trace correlator used to attach trace and span ids
+// to events triggered with a context.
+func (l *Logger) WithTraceCorrelator(correlator TraceCorrelator) *Logger {
+	l.traceCorrelator = correlator
+	return l
+}
+
+// TraceCorrelator returns the trace correlator.
+func (l *Logger) TraceCorrelator() TraceCorrelator {
+	return l.traceCorrelator
+}
+
 // Flags returns the logger flag set.
 func (l *Logger) Flags() *FlagSet {
 	return l.flags
@@ -356,8 +480,22 @@ func (l *Logger) SubContext(heading string) *SubContext {
 	}
 }
 
+// Timer returns a started timer for a named operation.
+// Call `Stop` on the timer to trigger a timed event with the elapsed time.
+func (l *Logger) Timer(name string) *Timer {
+	return NewTimer(l, name)
+}
+
+// Time runs an action and triggers a timed event with its elapsed time.
+func (l *Logger) Time(name string, action func() error) error {
+	return TimeAction(l, name, action)
+}
+
 // Listen adds a listener for a given flag.
+// Listeners are called for async triggers by a fixed pool of listener workers (see `WithListenerWorkers`).
 func (l *Logger) Listen(flag Flag, listenerName string, listener Listener) {
+	l.startListenerPool()
+
 	l.workersLock.Lock()
 	defer l.workersLock.Unlock()
 
@@ -365,7 +503,10 @@ func (l *Logger) Listen(flag Flag, listenerName string, listener Listener) {
 		l.workers = map[Flag]map[string]*Worker{}
 	}
 
-	w := NewWorker(l, listener, l.listenerWorkerQueueDepth)
+	// a listener's own worker isn't started; the listener workers call it with `Process`.
+	w := NewWorker(l, listener, 0)
+	w.Flag = flag
+	w.Name = listenerName
 	if listeners, hasListeners := l.workers[flag]; hasListeners {
 		listeners[listenerName] = w
 	} else {
@@ -373,25 +514,84 @@ func (l *Logger) Listen(flag Flag, listenerName string, listener Listener) {
 			listenerName: w,
 		}
 	}
-	w.Start()
 }
 
-// RemoveListeners clears *all* listeners for a Flag.
-func (l *Logger) RemoveListeners(flag Flag) {
+// startListenerPool starts the listener workers if they aren't started already.
+func (l *Logger) startListenerPool() {
+	l.listenerPoolLock.Lock()
+	defer l.listenerPoolLock.Unlock()
+
+	if l.listenerPool != nil {
+		return
+	}
+	workers := l.listenerWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	l.listenerPool = make([]*Worker, workers)
+	for index := range l.listenerPool {
+		l.listenerPool[index] = NewWorker(l, l.dispatch, l.listenerWorkerQueueDepth)
+		l.listenerPool[index].Start()
+	}
+}
+
+// listenerWorker returns the listener worker for a flag, or nil if the listener workers aren't started.
+// A flag always maps to the same worker, which keeps its events in order.
+func (l *Logger) listenerWorker(flag Flag) *Worker {
+	l.listenerPoolLock.Lock()
+	defer l.listenerPoolLock.Unlock()
+
+	if len(l.listenerPool) == 0 {
+		return nil
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(flag))
+	return l.listenerPool[hash.Sum32()%uint32(len(l.listenerPool))]
+}
+
+// takeListenerPool returns the listener workers, clearing them from the logger.
+func (l *Logger) takeListenerPool() []*Worker {
+	l.listenerPoolLock.Lock()
+	defer l.listenerPoolLock.Unlock()
+
+	pool := l.listenerPool
+	l.listenerPool = nil
+	return pool
+}
+
+// listeners returns the listeners for a flag.
+func (l *Logger) listeners(flag Flag) []*Worker {
 	l.workersLock.Lock()
 	defer l.workersLock.Unlock()
 
 	if l.workers == nil {
-		return
+		return nil
+	}
+	flagWorkers, hasWorkers := l.workers[flag]
+	if !hasWorkers {
+		return nil
 	}
+	listeners := make([]*Worker, 0, len(flagWorkers))
+	for _, worker := range flagWorkers {
+		listeners = append(listeners, worker)
+	}
+	return listeners
+}
 
-	listeners, hasListeners := l.workers[flag]
-	if !hasListeners {
-		return
+// dispatch calls the listeners for an event's flag one at a time; it is the listener for the listener workers.
+func (l *Logger) dispatch(e Event) {
+	for _, worker := range l.listeners(e.Flag()) {
+		worker.Process(e)
 	}
+}
+
+// RemoveListeners clears *all* listeners for a Flag.
+func (l *Logger) RemoveListeners(flag Flag) {
+	l.workersLock.Lock()
+	defer l.workersLock.Unlock()
 
-	for _, w := range listeners {
-		w.Close()
+	if l.workers == nil {
+		return
 	}
 
 	delete(l.workers, flag)
@@ -411,12 +611,6 @@ func (l *Logger) RemoveListener(flag Flag, listenerName string) {
 		return
 	}
 
-	worker, hasWorker := listeners[listenerName]
-	if !hasWorker {
-		return
-	}
-
-	worker.Close()
 	delete(listeners, listenerName)
 
 	if len(listeners) == 0 {
@@ -425,9 +619,9 @@ func (l *Logger) RemoveListener(flag Flag, listenerName string) {
 }
 
 // Trigger fires the listeners for a given event asynchronously.
-// The invocations will be queued in a work queue and processed by a fixed worker count.
-// There are no order guarantees on when these events will be processed.
-// This call will not block on the event listeners.
+// The invocations will be queued on the listener worker for the event's flag, which processes its events in order.
+// There are no order guarantees between flags.
+// This call will not block on the event listeners unless the listener worker's queue is full.
 func (l *Logger) Trigger(e Event) {
 	l.trigger(true, e)
 }
@@ -438,7 +632,33 @@ func (l *Logger) SyncTrigger(e Event) {
 	l.trigger(false, e)
 }
 
+// TriggerContext fires the listeners for a given event asynchronously,
+// attaching trace correlation ids from the context if a trace correlator is set.
+func (l *Logger) TriggerContext(ctx context.Context, e Event) {
+	InjectTrace(ctx, l.traceCorrelator, e)
+	l.trigger(true, e)
+}
+
+// SyncTriggerContext fires the listeners for a given event synchronously,
+// attaching trace correlation ids from the context if a trace correlator is set.
+func (l *Logger) SyncTriggerContext(ctx context.Context, e Event) {
+	InjectTrace(ctx, l.traceCorrelator, e)
+	l.trigger(false, e)
+}
+
 func (l *Logger) trigger(async bool, e Event) {
+	l.triggerWithOverrides(async, e, nil)
+}
+
+// isEnabled returns if a flag is enabled, preferring a set of flag overrides (i.e. from a sub context).
+func (l *Logger) isEnabled(flag Flag, flagOverrides map[Flag]bool) bool {
+	if enabled, hasOverride := flagOverrides[flag]; hasOverride {
+		return enabled
+	}
+	return l.IsEnabled(flag)
+}
+
+func (l *Logger) triggerWithOverrides(async bool, e Event, flagOverrides map[Flag]bool) {
 	if !async && l.recoverPanics {
 		defer func() {
 			if r := recover(); r != nil {
@@ -456,7 +676,7 @@ func (l *Logger) trigger(async bool, e Event) {
 	}
 
 	flag := e.Flag()
-	if l.IsEnabled(flag) {
+	if l.isEnabled(flag, flagOverrides) {
 		if l.heading != "" {
 			if typed, isTyped := e.(EventHeadings); isTyped {
 				if len(typed.Headings()) > 0 {
@@ -467,20 +687,19 @@ func (l *Logger) trigger(async bool, e Event) {
 			}
 		}
 
-		var workers map[string]*Worker
-		l.workersLock.Lock()
-		if l.workers != nil {
-			if flagWorkers, hasWorkers := l.workers[flag]; hasWorkers {
-				workers = flagWorkers
-			}
-		}
-		l.workersLock.Unlock()
+		workers := l.listeners(flag)
 
-		for _, worker := range workers {
+		// listeners may retain the event, so it can't be reused.
+		if len(workers) > 0 {
+			disownEvent(e)
 			if async {
-				worker.Work <- e
+				if listenerWorker := l.listenerWorker(flag); listenerWorker != nil {
+					listenerWorker.Work <- e
+				}
 			} else {
-				worker.Listener(e)
+				for _, worker := range workers {
+					worker.Process(e)
+				}
 			}
 		}
 
@@ -499,6 +718,12 @@ func (l *Logger) trigger(async bool, e Event) {
 		} else {
 			l.Write(e)
 		}
+
+		// fatal events are usually the last thing a process writes before it exits,
+		// so make sure they (and anything queued before them) reach the writers.
+		if flag == Fatal {
+			l.flush()
+		}
 	}
 }
 
@@ -506,34 +731,52 @@ func (l *Logger) trigger(async bool, e Event) {
 // Builtin Flag Handlers (infof, debugf etc.)
 // --------------------------------------------------------------------------------
 
+// messagef returns a message event, leased from the pool if the logger pools events.
+func (l *Logger) messagef(flag Flag, format string, args ...interface{}) *MessageEvent {
+	if l.poolEvents {
+		return leaseMessagef(flag, format, args...)
+	}
+	return Messagef(flag, format, args...)
+}
+
 // Sillyf logs an incredibly verbose message to the output stream.
 func (l *Logger) Sillyf(format string, args ...interface{}) {
-	l.trigger(true, Messagef(Silly, format, args...))
+	l.trigger(true, l.messagef(Silly, format, args...))
 }
 
 // SyncSillyf logs an incredibly verbose message to the output stream synchronously.
 func (l *Logger) SyncSillyf(format string, args ...interface{}) {
-	l.trigger(false, Messagef(Silly, format, args...))
+	l.trigger(false, l.messagef(Silly, format, args...))
+}
+
+// InfofContext logs an informational message to the output stream with trace ids from a context.
+func (l *Logger) InfofContext(ctx context.Context, format string, args ...interface{}) {
+	l.TriggerContext(ctx, Messagef(Info, format, args...))
 }
 
 // Infof logs an informational message to the output stream.
 func (l *Logger) Infof(format string, args ...interface{}) {
-	l.trigger(true, Messagef(Info, format, args...))
+	l.trigger(true, l.messagef(Info, format, args...))
 }
 
 // SyncInfof logs an informational message to the output stream synchronously.
 func (l *Logger) SyncInfof(format string, args ...interface{}) {
-	l.trigger(false, Messagef(Info, format, args...))
+	l.trigger(false, l.messagef(Info, format, args...))
+}
+
+// DebugfContext logs a debug message to the output stream with trace ids from a context.
+func (l *Logger) DebugfContext(ctx context.Context, format string, args ...interface{}) {
+	l.TriggerContext(ctx, Messagef(Debug, format, args...))
 }
 
 // Debugf logs a debug message to the output stream.
 func (l *Logger) Debugf(format string, args ...interface{}) {
-	l.trigger(true, Messagef(Debug, format, args...))
+	l.trigger(true, l.messagef(Debug, format, args...))
 }
 
 // SyncDebugf logs an debug message to the output stream synchronously.
 func (l *Logger) SyncDebugf(format string, args ...interface{}) {
-	l.trigger(false, Messagef(Debug, format, args...))
+	l.trigger(false, l.messagef(Debug, format, args...))
 }
 
 // Warningf logs a debug message to the output stream.
@@ -558,6 +801,12 @@ func (l *Logger) SyncWarning(err error) error {
 	return err
 }
 
+// WarningContext logs a warning error to std err with trace ids from a context.
+func (l *Logger) WarningContext(ctx context.Context, err error) error {
+	l.TriggerContext(ctx, NewErrorEvent(Warning, err))
+	return err
+}
+
 // WarningWithReq logs a warning error to std err with a request.
 func (l *Logger) WarningWithReq(err error, req *http.Request) error {
 	l.trigger(true, NewErrorEventWithState(Warning, err, req))
@@ -586,6 +835,12 @@ func (l *Logger) SyncError(err error) error {
 	return err
 }
 
+// ErrorContext logs an error to std err with trace ids from a context.
+func (l *Logger) ErrorContext(ctx context.Context, err error) error {
+	l.TriggerContext(ctx, NewErrorEvent(Error, err))
+	return err
+}
+
 // ErrorWithReq logs an error to std err with a request.
 func (l *Logger) ErrorWithReq(err error, req *http.Request) error {
 	l.trigger(true, NewErrorEventWithState(Error, err, req))
@@ -603,17 +858,25 @@ func (l *Logger) SyncFatalf(format string, args ...interface{}) {
 }
 
 // Fatal logs the result of a panic to std err.
+// It flushes queued events and buffered writers before it returns, waiting at most `FatalFlushTimeout`.
 func (l *Logger) Fatal(err error) error {
 	l.trigger(true, NewErrorEvent(Fatal, err))
 	return err
 }
 
 // SyncFatal synchronously logs a fatal to std err.
+// It flushes queued events and buffered writers before it returns, waiting at most `FatalFlushTimeout`.
 func (l *Logger) SyncFatal(err error) error {
 	l.trigger(false, NewErrorEvent(Fatal, err))
 	return err
 }
 
+// FatalContext logs a fatal error to std err with trace ids from a context.
+func (l *Logger) FatalContext(ctx context.Context, err error) error {
+	l.TriggerContext(ctx, NewErrorEvent(Fatal, err))
+	return err
+}
+
 // FatalWithReq logs the result of a fatal error to std err with a request.
 func (l *Logger) FatalWithReq(err error, req *http.Request) error {
 	l.trigger(true, NewErrorEventWithState(Fatal, err, req))
@@ -627,16 +890,22 @@ func (l *Logger) SyncFatalExit(err error) {
 }
 
 // Write writes an event synchronously to the writer either as a normal even or as an error.
+// If the event flag has a route, the event is written to the routed writers instead.
+// If the logger pools events (see `WithPoolEvents`), writers must not retain the event after they
+// return unless they call `Retain` on it.
 func (l *Logger) Write(e Event) {
-	ll := len(l.writers)
+	defer releaseEvent(e)
+
+	writers := l.WritersFor(e.Flag())
+	ll := len(writers)
 	if typed, isTyped := e.(EventError); isTyped && typed.IsError() {
 		for index := 0; index < ll; index++ {
-			l.writers[index].WriteError(e)
+			writers[index].WriteError(e)
 		}
 		return
 	}
 	for index := 0; index < ll; index++ {
-		l.writers[index].Write(e)
+		writers[index].Write(e)
 	}
 }
 
@@ -655,15 +924,13 @@ func (l *Logger) Close() (err error) {
 
 	l.setStopping()
 
+	for _, worker := range l.takeListenerPool() {
+		worker.Close()
+	}
+
 	l.workersLock.Lock()
 	defer l.workersLock.Unlock()
 
-	for _, workers := range l.workers {
-		for _, worker := range workers {
-			worker.Close()
-		}
-	}
-
 	for key := range l.workers {
 		delete(l.workers, key)
 	}
@@ -675,22 +942,47 @@ func (l *Logger) Close() (err error) {
 	l.writeWorker.Close()
 	l.writeWorker = nil
 
+	l.stopWriters()
+
 	l.setStopped()
 
 	return nil
 }
 
+// RecoverAndLog recovers a panic, writes it as a fatal error event and flushes
+// any queued events and buffered writers. It must be deferred directly, i.e.
+// `defer log.RecoverAndLog()`.
+func (l *Logger) RecoverAndLog() {
+	if r := recover(); r != nil {
+		l.SyncFatal(exception.New(r))
+	}
+}
+
+// DrainContext waits for the agent to finish its queue of events, or for the context to be done.
+// If the context is done first, the drain continues in the background and the context error is returned.
+func (l *Logger) DrainContext(ctx context.Context) error {
+	drained := make(chan error, 1)
+	go func() {
+		drained <- l.Drain()
+	}()
+	select {
+	case err := <-drained:
+		return err
+	case <-ctx.Done():
+		return exception.New(ctx.Err())
+	}
+}
+
 // Drain waits for the agent to finish its queue of events before closing.
 func (l *Logger) Drain() error {
-	l.workersLock.Lock()
-	defer l.workersLock.Unlock()
-
 	l.setStopping()
 
-	for _, workers := range l.workers {
-		for _, worker := range workers {
-			worker.Drain()
-		}
+	// listeners may trigger events, so the listener workers are drained without holding the pool lock.
+	l.listenerPoolLock.Lock()
+	listenerPool := l.listenerPool
+	l.listenerPoolLock.Unlock()
+	for _, worker := range listenerPool {
+		worker.Drain()
 	}
 
 	l.writeWorkerLock.Lock()
@@ -700,11 +992,77 @@ func (l *Logger) Drain() error {
 		l.writeWorker.Drain()
 	}
 
+	l.flushWriters()
+
 	l.setStarted()
 
 	return nil
 }
 
+// flush writes any queued events and flushes buffered writers, waiting at most the fatal flush timeout.
+// Listener queues are not drained.
+// The write worker can't drain its own queue, so if a writer triggers a fatal event the flush times out,
+// and finishes in the background once the write worker gets back to its queue.
+func (l *Logger) flush() {
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		l.writeWorkerLock.Lock()
+		if l.writeWorker != nil && l.writeWorker.Abort != nil {
+			l.writeWorker.Drain()
+		}
+		l.writeWorkerLock.Unlock()
+
+		l.flushWriters()
+	}()
+
+	timeout := time.NewTimer(l.fatalFlushTimeout)
+	defer timeout.Stop()
+	select {
+	case <-flushed:
+	case <-timeout.C:
+	}
+}
+
+// stopWriters stops any writers (including routed writers) with a background process, which flushes them,
+// and flushes any other writers that buffer output.
+func (l *Logger) stopWriters() {
+	stop := func(writers []Writer) {
+		for _, writer := range writers {
+			if typed, isTyped := writer.(WriterStopper); isTyped {
+				typed.Stop()
+			} else if typed, isTyped := writer.(WriterFlusher); isTyped {
+				typed.Flush()
+			}
+		}
+	}
+	stop(l.writers)
+
+	l.routesLock.Lock()
+	defer l.routesLock.Unlock()
+	for _, writers := range l.routes {
+		stop(writers)
+	}
+}
+
+// flushWriters flushes any writers (including routed writers) that buffer output.
+func (l *Logger) flushWriters() {
+	flush := func(writers []Writer) {
+		for _, writer := range writers {
+			if typed, isTyped := writer.(WriterFlusher); isTyped {
+				typed.Flush()
+			}
+		}
+	}
+	flush(l.writers)
+
+	l.routesLock.Lock()
+	defer l.routesLock.Unlock()
+	for _, writers := range l.routes {
+		flush(writers)
+	}
+}
+
 func (l *Logger) isStarted() bool {
 	return atomic.LoadInt32(&l.state) == LoggerStarted
 }
@@ -2,10 +2,12 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -749,6 +751,40 @@ func TestLoggerCanConfigureMultipleWriters(t *testing.T) {
 	assert.NotEmpty(out2.String())
 }
 
+func TestLoggerRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	out := bytes.NewBuffer(nil)
+	errOut := bytes.NewBuffer(nil)
+	auditOut := bytes.NewBuffer(nil)
+
+	log := New().WithFlags(AllFlags()).
+		WithWriter(NewTextWriter(out)).
+		WithRoute(Error, NewTextWriter(errOut)).
+		WithRoute(Audit, NewJSONWriter(auditOut))
+	defer log.Close()
+
+	assert.Len(log.Routes(), 2)
+	assert.Len(log.WritersFor(Info), 1)
+
+	// routes are a copy of the routing table.
+	delete(log.Routes(), Error)
+	assert.Len(log.Routes(), 2)
+
+	log.SyncInfof("this is a %s", "test")
+	log.SyncErrorf("this is an %s", "error")
+	log.SyncTrigger(NewAuditEvent("bailey", "pet"))
+
+	assert.Contains(out.String(), "this is a test")
+	assert.NotContains(out.String(), "this is an error")
+	assert.Contains(errOut.String(), "this is an error")
+	assert.Contains(auditOut.String(), "bailey")
+
+	log.RemoveRoute(Error)
+	log.SyncErrorf("this is another %s", "error")
+	assert.Contains(out.String(), "this is another error")
+}
+
 type panics struct {
 	didRun bool
 }
@@ -805,3 +841,173 @@ func TestLoggerRecoverPanics(t *testing.T) {
 		all.SyncTrigger(Messagef(Info, "this is only a test"))
 	})
 }
+
+func TestLoggerListenerPanicIsolation(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	all := New().WithFlags(AllFlags()).WithWriter(NewTextWriter(buffer).WithUseColor(false))
+	defer all.Close()
+
+	var didFire bool
+	all.Listen(Info, "panics", func(e Event) {
+		panic("this is only a test")
+	})
+	all.Listen(Info, "fires", func(e Event) {
+		didFire = true
+	})
+
+	all.SyncTrigger(Messagef(Info, "this is only a test"))
+	assert.True(didFire, "other listeners should still fire")
+	assert.Contains(buffer.String(), string(ErrListenerPanic))
+	assert.Contains(buffer.String(), "listener: panics")
+	assert.Contains(buffer.String(), "this is only a test")
+}
+
+func TestLoggerListenerOrdering(t *testing.T) {
+	assert := assert.New(t)
+
+	all := New().WithFlags(AllFlags())
+	defer all.Close()
+
+	var received []string
+	all.Listen(Info, "ordered", NewMessageEventListener(func(me *MessageEvent) {
+		received = append(received, me.Message())
+	}))
+
+	var expected []string
+	for x := 0; x < 100; x++ {
+		expected = append(expected, fmt.Sprint(x))
+		all.Trigger(Messagef(Info, "%d", x))
+	}
+	all.Drain()
+	assert.Equal(expected, received)
+	assert.Equal(DefaultListenerQueueDepth, all.ListenerWorkerQueueDepth())
+}
+
+func TestLoggerListenerWorkers(t *testing.T) {
+	assert := assert.New(t)
+
+	log := New().WithFlags(AllFlags()).WithListenerWorkers(2)
+	defer log.Close()
+	assert.Equal(2, log.ListenerWorkers())
+
+	flags := []Flag{Info, Debug, Warning, Error, Silly}
+	received := map[Flag][]string{}
+	var receivedLock sync.Mutex
+	for _, flag := range flags {
+		for x := 0; x < 10; x++ {
+			listener := x
+			log.Listen(flag, fmt.Sprint(listener), func(e Event) {
+				if listener > 0 {
+					return
+				}
+				receivedLock.Lock()
+				defer receivedLock.Unlock()
+				received[e.Flag()] = append(received[e.Flag()], e.(*MessageEvent).Message())
+			})
+		}
+	}
+	// the pool size doesn't grow with the listeners.
+	assert.Len(log.listenerPool, 2)
+
+	var expected []string
+	for x := 0; x < 100; x++ {
+		expected = append(expected, fmt.Sprint(x))
+		for _, flag := range flags {
+			log.Trigger(Messagef(flag, "%d", x))
+		}
+	}
+	log.Drain()
+
+	// events are in order per flag.
+	for _, flag := range flags {
+		assert.Equal(expected, received[flag])
+	}
+}
+
+func TestLoggerFatalFlushes(t *testing.T) {
+	assert := assert.New(t)
+
+	var produced []KafkaMessage
+	buffered := NewKafkaWriter(KafkaProducerFunc(func(batch KafkaBatch) error {
+		produced = append(produced, batch.Messages...)
+		return nil
+	}), "logs")
+
+	log := New().WithFlags(AllFlags()).WithWriter(buffered)
+	defer log.Close()
+
+	log.Infof("before the crash")
+	log.Fatal(exception.New("crash"))
+	assert.Len(produced, 2)
+	assert.Contains(string(produced[0].Value), "before the crash")
+	assert.Contains(string(produced[1].Value), "crash")
+
+	produced = nil
+	func() {
+		defer log.RecoverAndLog()
+		panic("only a test")
+	}()
+	assert.Len(produced, 1)
+	assert.Contains(string(produced[0].Value), "only a test")
+}
+
+type fatalOnWriteWriter struct {
+	TextWriter
+	log    *Logger
+	fatals int32
+}
+
+func (fw *fatalOnWriteWriter) Write(e Event) error {
+	switch e.Flag() {
+	case Info:
+		fw.log.Fatal(exception.New("from a writer"))
+	case Fatal:
+		atomic.AddInt32(&fw.fatals, 1)
+	}
+	return nil
+}
+
+func (fw *fatalOnWriteWriter) WriteError(e Event) error {
+	return fw.Write(e)
+}
+
+func TestLoggerFatalFromWriteWorker(t *testing.T) {
+	assert := assert.New(t)
+
+	writer := new(fatalOnWriteWriter)
+	log := New().WithFlags(AllFlags()).WithFatalFlushTimeout(10 * time.Millisecond).WithWriter(writer)
+	writer.log = log
+	defer log.Close()
+	assert.Equal(10*time.Millisecond, log.FatalFlushTimeout())
+
+	// the fatal is triggered on the write worker, which can't wait for its own queue to drain.
+	log.Infof("foo")
+	assert.Eventually(func() bool { return atomic.LoadInt32(&writer.fatals) == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(log.DrainContext(ctx))
+}
+
+func TestLoggerDrainContext(t *testing.T) {
+	assert := assert.New(t)
+
+	log := New().WithFlags(AllFlags())
+	defer log.Close()
+
+	release := make(chan struct{})
+	log.Listen(Info, "slow", func(e Event) {
+		<-release
+	})
+	log.Infof("foo")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	err := log.DrainContext(ctx)
+	assert.True(exception.Is(err, context.DeadlineExceeded))
+	close(release)
+
+	assert.Nil(log.DrainContext(context.Background()))
+}
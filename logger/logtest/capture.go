@@ -0,0 +1,190 @@
+package logtest
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Asserts the capture is a writer.
+var (
+	_ logger.Writer = &Capture{}
+)
+
+// New returns a logger that writes only to a capture.
+// If no flags are given, all flags are enabled.
+func New(flags ...logger.Flag) (*logger.Logger, *Capture) {
+	var log *logger.Logger
+	if len(flags) > 0 {
+		log = logger.New(flags...)
+	} else {
+		log = logger.New().WithFlags(logger.AllFlags())
+	}
+	capture := NewCapture().WithLogger(log)
+	log.WithWriter(capture)
+	return log, capture
+}
+
+// NewCapture returns a new capture.
+func NewCapture() *Capture {
+	return &Capture{
+		written: make(chan struct{}, 1),
+	}
+}
+
+// Capture is a writer that records events for assertions in tests.
+// If a logger is set, its queues are drained before any events are read,
+// so events triggered asynchronously before the read are always visible.
+type Capture struct {
+	sync.Mutex
+
+	log     *logger.Logger
+	events  []logger.Event
+	written chan struct{}
+}
+
+// WithLogger sets the logger to drain before events are read.
+func (c *Capture) WithLogger(log *logger.Logger) *Capture {
+	c.log = log
+	return c
+}
+
+// Logger returns the logger drained before events are read.
+func (c *Capture) Logger() *logger.Logger {
+	return c.log
+}
+
+// OutputFormat returns the output format.
+func (c *Capture) OutputFormat() logger.OutputFormat {
+	return logger.OutputFormatText
+}
+
+// Output returns nil; the capture has no output stream.
+func (c *Capture) Output() io.Writer {
+	return nil
+}
+
+// ErrorOutput returns nil; the capture has no output stream.
+func (c *Capture) ErrorOutput() io.Writer {
+	return nil
+}
+
+// Write records an event.
+func (c *Capture) Write(e logger.Event) error {
+	c.Lock()
+	c.events = append(c.events, logger.Retain(e))
+	c.Unlock()
+
+	select {
+	case c.written <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// WriteError records an event.
+func (c *Capture) WriteError(e logger.Event) error {
+	return c.Write(e)
+}
+
+// Reset removes any recorded events.
+func (c *Capture) Reset() {
+	c.drain()
+	c.Lock()
+	c.events = nil
+	c.Unlock()
+}
+
+// Events returns the recorded events in the order they were written.
+func (c *Capture) Events() []logger.Event {
+	c.drain()
+	return c.snapshot()
+}
+
+// EventsFor returns the recorded events for a flag that match a given matcher.
+func (c *Capture) EventsFor(flag logger.Flag, matcher Matcher) []logger.Event {
+	var output []logger.Event
+	for _, e := range c.Events() {
+		if Expect(flag, matcher).Matches(e) {
+			output = append(output, e)
+		}
+	}
+	return output
+}
+
+// HasEvent returns if an event for a flag that matches a given matcher was recorded.
+// A nil matcher matches any event.
+func (c *Capture) HasEvent(flag logger.Flag, matcher Matcher) bool {
+	return c.Index(flag, matcher) >= 0
+}
+
+// Count returns the number of recorded events for a flag that match a given matcher.
+func (c *Capture) Count(flag logger.Flag, matcher Matcher) int {
+	return len(c.EventsFor(flag, matcher))
+}
+
+// Index returns the position of the first recorded event for a flag that matches a given matcher, or -1.
+func (c *Capture) Index(flag logger.Flag, matcher Matcher) int {
+	for index, e := range c.Events() {
+		if Expect(flag, matcher).Matches(e) {
+			return index
+		}
+	}
+	return -1
+}
+
+// InOrder returns if events matching each of the expectations were recorded in the given order.
+// Other events may be recorded between them.
+func (c *Capture) InOrder(expectations ...Expectation) bool {
+	events := c.Events()
+	var position int
+	for _, expectation := range expectations {
+		for ; position < len(events); position++ {
+			if expectation.Matches(events[position]) {
+				break
+			}
+		}
+		if position == len(events) {
+			return false
+		}
+		position++
+	}
+	return true
+}
+
+// WaitFor waits until an event for a flag that matches a given matcher is recorded, or the timeout elapses.
+// Use it for events triggered from other goroutines, e.g. by listeners.
+// It does not drain the logger, as draining drops events triggered concurrently.
+func (c *Capture) WaitFor(flag logger.Flag, matcher Matcher, timeout time.Duration) bool {
+	expectation := Expect(flag, matcher)
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		for _, e := range c.snapshot() {
+			if expectation.Matches(e) {
+				return true
+			}
+		}
+		select {
+		case <-c.written:
+		case <-deadline.C:
+			return false
+		}
+	}
+}
+
+func (c *Capture) drain() {
+	if c.log != nil {
+		c.log.Drain()
+	}
+}
+
+func (c *Capture) snapshot() []logger.Event {
+	c.Lock()
+	defer c.Unlock()
+	output := make([]logger.Event, len(c.events))
+	copy(output, c.events)
+	return output
+}
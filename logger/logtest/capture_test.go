@@ -0,0 +1,73 @@
+package logtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestCapture(t *testing.T) {
+	assert := assert.New(t)
+
+	log, captured := New()
+	defer log.Close()
+
+	log.Infof("foo %s", "bar")
+	log.Error(exception.New("only a test"))
+	sub := log.SubContext("sub")
+	sub.WithLabel("env", "test").Debugf("baz")
+
+	assert.Len(captured.Events(), 3)
+	assert.True(captured.HasEvent(logger.Info, MessageContains("foo bar")))
+	assert.True(captured.HasEvent(logger.Error, ErrorContains("only a test")))
+	assert.True(captured.HasEvent(logger.Debug, All(MessageContains("baz"), HasLabel("env", "test"))))
+	assert.False(captured.HasEvent(logger.Warning, nil))
+	assert.Equal(1, captured.Count(logger.Info, Any))
+	assert.Equal(1, captured.Index(logger.Error, nil))
+
+	assert.True(captured.InOrder(
+		Expect(logger.Info, MessageContains("foo")),
+		Expect(logger.Debug, nil),
+	))
+	assert.False(captured.InOrder(
+		Expect(logger.Debug, nil),
+		Expect(logger.Info, nil),
+	))
+
+	captured.Reset()
+	assert.Empty(captured.Events())
+}
+
+func TestCaptureRetainsPooledEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	log, captured := New(logger.Info)
+	defer log.Close()
+	log.WithPoolEvents(true)
+
+	for x := 0; x < 100; x++ {
+		log.Infof("message %d", x)
+	}
+	events := captured.Events()
+	assert.Len(events, 100)
+	assert.Equal("message 0", events[0].(*logger.MessageEvent).Message())
+	assert.Equal("message 99", events[99].(*logger.MessageEvent).Message())
+	assert.False(captured.HasEvent(logger.Debug, nil))
+}
+
+func TestCaptureWaitFor(t *testing.T) {
+	assert := assert.New(t)
+
+	log, captured := New()
+	defer log.Close()
+
+	go func() {
+		time.Sleep(time.Millisecond)
+		log.Warningf("eventually")
+	}()
+	assert.True(captured.WaitFor(logger.Warning, ErrorContains("eventually"), time.Second))
+	assert.False(captured.WaitFor(logger.Fatal, nil, time.Millisecond))
+}
@@ -0,0 +1,82 @@
+package logtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Matcher returns if an event matches.
+type Matcher func(logger.Event) bool
+
+// Expectation is a flag and matcher pair.
+type Expectation struct {
+	Flag    logger.Flag
+	Matcher Matcher
+}
+
+// Expect returns an expectation for a flag and matcher.
+// A nil matcher matches any event with the flag.
+func Expect(flag logger.Flag, matcher Matcher) Expectation {
+	return Expectation{Flag: flag, Matcher: matcher}
+}
+
+// Matches returns if an event matches the expectation.
+func (ex Expectation) Matches(e logger.Event) bool {
+	if e == nil || e.Flag() != ex.Flag {
+		return false
+	}
+	return ex.Matcher == nil || ex.Matcher(e)
+}
+
+// Any matches any event.
+func Any(e logger.Event) bool {
+	return true
+}
+
+// All matches events that match all of the given matchers.
+func All(matchers ...Matcher) Matcher {
+	return func(e logger.Event) bool {
+		for _, matcher := range matchers {
+			if !matcher(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MessageContains matches events whose message (or string form) contains a substring.
+func MessageContains(substring string) Matcher {
+	return func(e logger.Event) bool {
+		if typed, isTyped := e.(*logger.MessageEvent); isTyped {
+			return strings.Contains(typed.Message(), substring)
+		}
+		if typed, isTyped := e.(fmt.Stringer); isTyped {
+			return strings.Contains(typed.String(), substring)
+		}
+		return false
+	}
+}
+
+// ErrorContains matches error events whose error contains a substring.
+func ErrorContains(substring string) Matcher {
+	return func(e logger.Event) bool {
+		if typed, isTyped := e.(*logger.ErrorEvent); isTyped && typed.Err() != nil {
+			return strings.Contains(typed.Err().Error(), substring)
+		}
+		return false
+	}
+}
+
+// HasLabel matches events with a given label value.
+func HasLabel(key, value string) Matcher {
+	return func(e logger.Event) bool {
+		if typed, isTyped := e.(logger.EventLabels); isTyped {
+			labelValue, hasLabel := typed.Labels()[key]
+			return hasLabel && labelValue == value
+		}
+		return false
+	}
+}
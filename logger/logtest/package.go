@@ -0,0 +1,2 @@
+// Package logtest provides a capturing writer and matchers to assert on logger events in tests.
+package logtest
@@ -0,0 +1,397 @@
+package logger
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// otlp any value kinds.
+const (
+	otlpKindString byte = iota
+	otlpKindBool
+	otlpKindInt
+	otlpKindDouble
+	otlpKindArray
+	otlpKindKeyValueList
+)
+
+// otlpValue is an otlp `AnyValue`.
+type otlpValue struct {
+	kind    byte
+	str     string
+	boolean bool
+	integer int64
+	double  float64
+	array   []otlpValue
+	kvs     []otlpKeyValue
+}
+
+// otlpKeyValue is an otlp `KeyValue`.
+type otlpKeyValue struct {
+	key   string
+	value otlpValue
+}
+
+// otlpLogRecord is an otlp `LogRecord`.
+type otlpLogRecord struct {
+	timeUnixNano         uint64
+	observedTimeUnixNano uint64
+	severityNumber       int
+	severityText         string
+	body                 otlpValue
+	attributes           []otlpKeyValue
+	traceID              []byte
+	spanID               []byte
+}
+
+// otlpRequest is an otlp `ExportLogsServiceRequest` with a single resource and scope.
+type otlpRequest struct {
+	resource     []otlpKeyValue
+	scopeName    string
+	scopeVersion string
+	records      []otlpLogRecord
+}
+
+// OTLPSeverity returns the otlp severity number and text for a flag.
+func OTLPSeverity(flag Flag) (int, string) {
+	switch flag {
+	case Fatal:
+		return 21, "FATAL"
+	case Error:
+		return 17, "ERROR"
+	case Warning:
+		return 13, "WARN"
+	case Debug:
+		return 5, "DEBUG"
+	case Silly:
+		return 1, "TRACE"
+	default:
+		return 9, "INFO"
+	}
+}
+
+// otlpLogRecordFromEvent converts an event to a log record.
+// The message (or error) becomes the body, and the remaining event fields become attributes.
+func otlpLogRecordFromEvent(e Event) otlpLogRecord {
+	severityNumber, severityText := OTLPSeverity(e.Flag())
+	record := otlpLogRecord{
+		timeUnixNano:         uint64(e.Timestamp().UnixNano()),
+		observedTimeUnixNano: uint64(time.Now().UTC().UnixNano()),
+		severityNumber:       severityNumber,
+		severityText:         severityText,
+	}
+
+	fields, isFields := JSONFields(e)
+	if !isFields {
+		if typed, isTyped := e.(fmt.Stringer); isTyped {
+			record.body = otlpStringValue(typed.String())
+		}
+		fields = JSONObj{JSONFieldFlag: e.Flag()}
+	} else if message, hasMessage := fields[JSONFieldMessage]; hasMessage {
+		record.body = otlpValueOf(message)
+		delete(fields, JSONFieldMessage)
+	} else if err, hasErr := fields[JSONFieldErr]; hasErr {
+		record.body = otlpValueOf(err)
+		delete(fields, JSONFieldErr)
+	}
+	delete(fields, JSONFieldTraceID)
+	delete(fields, JSONFieldSpanID)
+
+	if typed, isTyped := e.(EventLabels); isTyped {
+		for key, value := range typed.Labels() {
+			if _, hasKey := fields[key]; !hasKey {
+				fields[key] = value
+			}
+		}
+	}
+	record.attributes = otlpKeyValues(fields)
+
+	if typed, isTyped := e.(EventTrace); isTyped {
+		record.traceID = otlpID(typed.TraceID(), 16)
+		record.spanID = otlpID(typed.SpanID(), 8)
+	}
+	return record
+}
+
+// otlpID parses a hex or decimal id into a fixed size byte slice.
+func otlpID(value string, size int) []byte {
+	if len(value) == 0 {
+		return nil
+	}
+	if len(value) == size*2 {
+		if decoded, err := hex.DecodeString(value); err == nil {
+			return decoded
+		}
+	}
+	if parsed, err := strconv.ParseUint(value, 10, 64); err == nil {
+		output := make([]byte, size)
+		binary.BigEndian.PutUint64(output[size-8:], parsed)
+		return output
+	}
+	return nil
+}
+
+func otlpStringValue(value string) otlpValue {
+	return otlpValue{kind: otlpKindString, str: value}
+}
+
+func otlpKeyValues(values map[string]Any) []otlpKeyValue {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	output := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		output = append(output, otlpKeyValue{key: key, value: otlpValueOf(values[key])})
+	}
+	return output
+}
+
+func otlpValueOf(value Any) otlpValue {
+	switch typed := value.(type) {
+	case nil:
+		return otlpStringValue("")
+	case string:
+		return otlpStringValue(typed)
+	case Flag:
+		return otlpStringValue(string(typed))
+	case bool:
+		return otlpValue{kind: otlpKindBool, boolean: typed}
+	case int:
+		return otlpValue{kind: otlpKindInt, integer: int64(typed)}
+	case int32:
+		return otlpValue{kind: otlpKindInt, integer: int64(typed)}
+	case int64:
+		return otlpValue{kind: otlpKindInt, integer: typed}
+	case uint:
+		return otlpValue{kind: otlpKindInt, integer: int64(typed)}
+	case uint32:
+		return otlpValue{kind: otlpKindInt, integer: int64(typed)}
+	case uint64:
+		return otlpValue{kind: otlpKindInt, integer: int64(typed)}
+	case float32:
+		return otlpValue{kind: otlpKindDouble, double: float64(typed)}
+	case float64:
+		return otlpValue{kind: otlpKindDouble, double: typed}
+	case []string:
+		array := make([]otlpValue, 0, len(typed))
+		for _, item := range typed {
+			array = append(array, otlpStringValue(item))
+		}
+		return otlpValue{kind: otlpKindArray, array: array}
+	case map[string]Any:
+		return otlpValue{kind: otlpKindKeyValueList, kvs: otlpKeyValues(typed)}
+	case map[string]string:
+		values := make(map[string]Any, len(typed))
+		for key, item := range typed {
+			values[key] = item
+		}
+		return otlpValue{kind: otlpKindKeyValueList, kvs: otlpKeyValues(values)}
+	default:
+		return otlpStringValue(logfmtRaw(typed))
+	}
+}
+
+// --------------------------------------------------------------------------------
+// protobuf encoding
+// --------------------------------------------------------------------------------
+
+const (
+	protoWireVarint  = 0
+	protoWireFixed64 = 1
+	protoWireBytes   = 2
+)
+
+func protoAppendVarint(buf []byte, value uint64) []byte {
+	for value >= 0x80 {
+		buf = append(buf, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(buf, byte(value))
+}
+
+func protoAppendTag(buf []byte, field, wireType int) []byte {
+	return protoAppendVarint(buf, uint64(field<<3|wireType))
+}
+
+func protoAppendVarintField(buf []byte, field int, value uint64) []byte {
+	return protoAppendVarint(protoAppendTag(buf, field, protoWireVarint), value)
+}
+
+func protoAppendFixed64Field(buf []byte, field int, value uint64) []byte {
+	buf = protoAppendTag(buf, field, protoWireFixed64)
+	var contents [8]byte
+	binary.LittleEndian.PutUint64(contents[:], value)
+	return append(buf, contents[:]...)
+}
+
+func protoAppendBytesField(buf []byte, field int, value []byte) []byte {
+	buf = protoAppendVarint(protoAppendTag(buf, field, protoWireBytes), uint64(len(value)))
+	return append(buf, value...)
+}
+
+func protoAppendStringField(buf []byte, field int, value string) []byte {
+	return protoAppendBytesField(buf, field, []byte(value))
+}
+
+func (v otlpValue) marshalProto() (buf []byte) {
+	switch v.kind {
+	case otlpKindBool:
+		var value uint64
+		if v.boolean {
+			value = 1
+		}
+		buf = protoAppendVarintField(buf, 2, value)
+	case otlpKindInt:
+		buf = protoAppendVarintField(buf, 3, uint64(v.integer))
+	case otlpKindDouble:
+		buf = protoAppendFixed64Field(buf, 4, math.Float64bits(v.double))
+	case otlpKindArray:
+		var array []byte
+		for _, item := range v.array {
+			array = protoAppendBytesField(array, 1, item.marshalProto())
+		}
+		buf = protoAppendBytesField(buf, 5, array)
+	case otlpKindKeyValueList:
+		var kvs []byte
+		for _, kv := range v.kvs {
+			kvs = protoAppendBytesField(kvs, 1, kv.marshalProto())
+		}
+		buf = protoAppendBytesField(buf, 6, kvs)
+	default:
+		buf = protoAppendStringField(buf, 1, v.str)
+	}
+	return
+}
+
+func (kv otlpKeyValue) marshalProto() (buf []byte) {
+	buf = protoAppendStringField(buf, 1, kv.key)
+	return protoAppendBytesField(buf, 2, kv.value.marshalProto())
+}
+
+func (r otlpLogRecord) marshalProto() (buf []byte) {
+	buf = protoAppendFixed64Field(buf, 1, r.timeUnixNano)
+	buf = protoAppendVarintField(buf, 2, uint64(r.severityNumber))
+	buf = protoAppendStringField(buf, 3, r.severityText)
+	buf = protoAppendBytesField(buf, 5, r.body.marshalProto())
+	for _, attribute := range r.attributes {
+		buf = protoAppendBytesField(buf, 6, attribute.marshalProto())
+	}
+	if len(r.traceID) > 0 {
+		buf = protoAppendBytesField(buf, 9, r.traceID)
+	}
+	if len(r.spanID) > 0 {
+		buf = protoAppendBytesField(buf, 10, r.spanID)
+	}
+	return protoAppendFixed64Field(buf, 11, r.observedTimeUnixNano)
+}
+
+// marshalProto encodes the request as an `ExportLogsServiceRequest` protobuf message.
+func (req otlpRequest) marshalProto() []byte {
+	var resource []byte
+	for _, attribute := range req.resource {
+		resource = protoAppendBytesField(resource, 1, attribute.marshalProto())
+	}
+
+	var scope []byte
+	scope = protoAppendStringField(scope, 1, req.scopeName)
+	if len(req.scopeVersion) > 0 {
+		scope = protoAppendStringField(scope, 2, req.scopeVersion)
+	}
+
+	var scopeLogs []byte
+	scopeLogs = protoAppendBytesField(scopeLogs, 1, scope)
+	for _, record := range req.records {
+		scopeLogs = protoAppendBytesField(scopeLogs, 2, record.marshalProto())
+	}
+
+	var resourceLogs []byte
+	resourceLogs = protoAppendBytesField(resourceLogs, 1, resource)
+	resourceLogs = protoAppendBytesField(resourceLogs, 2, scopeLogs)
+
+	return protoAppendBytesField(nil, 1, resourceLogs)
+}
+
+// --------------------------------------------------------------------------------
+// json encoding
+// --------------------------------------------------------------------------------
+
+func (v otlpValue) jsonValue() map[string]Any {
+	switch v.kind {
+	case otlpKindBool:
+		return map[string]Any{"boolValue": v.boolean}
+	case otlpKindInt:
+		// int64 values are encoded as strings per the protobuf json mapping.
+		return map[string]Any{"intValue": strconv.FormatInt(v.integer, 10)}
+	case otlpKindDouble:
+		return map[string]Any{"doubleValue": v.double}
+	case otlpKindArray:
+		values := make([]Any, 0, len(v.array))
+		for _, item := range v.array {
+			values = append(values, item.jsonValue())
+		}
+		return map[string]Any{"arrayValue": map[string]Any{"values": values}}
+	case otlpKindKeyValueList:
+		return map[string]Any{"kvlistValue": map[string]Any{"values": otlpJSONKeyValues(v.kvs)}}
+	default:
+		return map[string]Any{"stringValue": v.str}
+	}
+}
+
+func otlpJSONKeyValues(kvs []otlpKeyValue) []Any {
+	output := make([]Any, 0, len(kvs))
+	for _, kv := range kvs {
+		output = append(output, map[string]Any{"key": kv.key, "value": kv.value.jsonValue()})
+	}
+	return output
+}
+
+func (r otlpLogRecord) jsonValue() map[string]Any {
+	output := map[string]Any{
+		"timeUnixNano":         strconv.FormatUint(r.timeUnixNano, 10),
+		"observedTimeUnixNano": strconv.FormatUint(r.observedTimeUnixNano, 10),
+		"severityNumber":       r.severityNumber,
+		"severityText":         r.severityText,
+		"body":                 r.body.jsonValue(),
+		"attributes":           otlpJSONKeyValues(r.attributes),
+	}
+	if len(r.traceID) > 0 {
+		output["traceId"] = hex.EncodeToString(r.traceID)
+	}
+	if len(r.spanID) > 0 {
+		output["spanId"] = hex.EncodeToString(r.spanID)
+	}
+	return output
+}
+
+// marshalJSON encodes the request using the otlp json encoding.
+func (req otlpRequest) marshalJSON() ([]byte, error) {
+	records := make([]Any, 0, len(req.records))
+	for _, record := range req.records {
+		records = append(records, record.jsonValue())
+	}
+	scope := map[string]Any{"name": req.scopeName}
+	if len(req.scopeVersion) > 0 {
+		scope["version"] = req.scopeVersion
+	}
+	return json.Marshal(map[string]Any{
+		"resourceLogs": []Any{
+			map[string]Any{
+				"resource": map[string]Any{"attributes": otlpJSONKeyValues(req.resource)},
+				"scopeLogs": []Any{
+					map[string]Any{
+						"scope":      scope,
+						"logRecords": records,
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,415 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// OTLPProtocolGRPC is an otlp protocol.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTPProtobuf is an otlp protocol.
+	OTLPProtocolHTTPProtobuf = "http/protobuf"
+	// OTLPProtocolHTTPJSON is an otlp protocol.
+	OTLPProtocolHTTPJSON = "http/json"
+
+	// OTLPLogsPath is the path logs are posted to for the http protocols.
+	OTLPLogsPath = "/v1/logs"
+	// OTLPLogsGRPCMethod is the grpc method logs are exported with.
+	OTLPLogsGRPCMethod = "/opentelemetry.proto.collector.logs.v1.LogsService/Export"
+
+	// OTLPScopeName is the instrumentation scope name sent with log records.
+	OTLPScopeName = "github.com/blend/go-sdk/logger"
+
+	// ErrOTLPStatus is returned when the collector responds with a non-2xx status code.
+	ErrOTLPStatus exception.Class = "otlp: non-ok status code"
+	// ErrOTLPGRPCStatus is returned when the collector responds with a non-ok grpc status.
+	ErrOTLPGRPCStatus exception.Class = "otlp: non-ok grpc status"
+	// ErrOTLPProtocolInvalid is returned when the protocol is not supported.
+	ErrOTLPProtocolInvalid exception.Class = "otlp: invalid protocol"
+)
+
+// Asserts otlp writer is a writer.
+var (
+	_ Writer        = &OTLPWriter{}
+	_ WriterFlusher = &OTLPWriter{}
+)
+
+// NewOTLPWriter returns a new opentelemetry log exporter for a given protocol and endpoint.
+// For the http protocols the endpoint is the full logs url, e.g. `http://localhost:4318/v1/logs`;
+// for grpc it is the collector base url, e.g. `https://localhost:4317`.
+// Grpc requires an http/2 capable client; the default client negotiates http/2 over tls only,
+// so use `WithClient` to export to a plaintext (h2c) collector.
+// It must be started with `.Start()` to flush on an interval.
+func NewOTLPWriter(protocol, endpoint string) *OTLPWriter {
+	resource := map[string]string{}
+	if serviceName := env.Env().ServiceName(); len(serviceName) > 0 {
+		resource["service.name"] = serviceName
+	}
+	if serviceEnv := env.Env().ServiceEnv(); len(serviceEnv) > 0 {
+		resource["deployment.environment"] = serviceEnv
+	}
+	if host, _ := os.Hostname(); len(host) > 0 {
+		resource["host.name"] = host
+	}
+	return &OTLPWriter{
+		protocol:      strings.ToLower(protocol),
+		endpoint:      endpoint,
+		headers:       map[string]string{},
+		resource:      resource,
+		batchSize:     DefaultOTLPBatchSize,
+		flushInterval: DefaultOTLPFlushInterval,
+		maxRetries:    DefaultOTLPMaxRetries,
+		retryBackoff:  DefaultOTLPRetryBackoff,
+		client:        &http.Client{Timeout: DefaultOTLPTimeout},
+	}
+}
+
+// NewOTLPWriterFromEnv returns a new otlp writer from the environment.
+func NewOTLPWriterFromEnv() *OTLPWriter {
+	return NewOTLPWriterFromConfig(NewOTLPWriterConfigFromEnv())
+}
+
+// NewOTLPWriterFromConfig returns a new otlp writer from a config.
+func NewOTLPWriterFromConfig(cfg *OTLPWriterConfig) *OTLPWriter {
+	ow := NewOTLPWriter(cfg.GetProtocol(), cfg.GetEndpoint()).
+		WithBatchSize(cfg.GetBatchSize()).
+		WithFlushInterval(cfg.GetFlushInterval()).
+		WithClient(&http.Client{Timeout: cfg.GetTimeout()})
+	for key, value := range cfg.GetHeaders() {
+		ow.WithHeader(key, value)
+	}
+	if serviceName := cfg.GetServiceName(); len(serviceName) > 0 {
+		ow.WithResourceAttribute("service.name", serviceName)
+	}
+	for key, value := range cfg.GetResourceAttributes() {
+		ow.WithResourceAttribute(key, value)
+	}
+	return ow
+}
+
+// OTLPWriter batches events and exports them to an opentelemetry collector using the otlp logs protocol.
+type OTLPWriter struct {
+	sync.Mutex
+
+	protocol string
+	endpoint string
+	headers  map[string]string
+	resource map[string]string
+
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+
+	client *http.Client
+	errors chan error
+
+	batch   []otlpLogRecord
+	flusher *async.Interval
+}
+
+// OutputFormat returns the output format.
+func (ow *OTLPWriter) OutputFormat() OutputFormat {
+	return OutputFormatJSON
+}
+
+// Output returns nil; the otlp writer has no local output stream.
+func (ow *OTLPWriter) Output() io.Writer {
+	return nil
+}
+
+// ErrorOutput returns nil; the otlp writer has no local output stream.
+func (ow *OTLPWriter) ErrorOutput() io.Writer {
+	return nil
+}
+
+// Protocol returns the protocol.
+func (ow *OTLPWriter) Protocol() string {
+	return ow.protocol
+}
+
+// Endpoint returns the endpoint.
+func (ow *OTLPWriter) Endpoint() string {
+	return ow.endpoint
+}
+
+// WithHeader sets a header sent with each export, e.g. for authentication.
+func (ow *OTLPWriter) WithHeader(key, value string) *OTLPWriter {
+	ow.headers[key] = value
+	return ow
+}
+
+// Headers returns the headers sent with each export.
+func (ow *OTLPWriter) Headers() map[string]string {
+	return ow.headers
+}
+
+// WithResourceAttribute sets a resource attribute, e.g. `service.name`.
+func (ow *OTLPWriter) WithResourceAttribute(key, value string) *OTLPWriter {
+	ow.resource[key] = value
+	return ow
+}
+
+// ResourceAttributes returns the resource attributes.
+func (ow *OTLPWriter) ResourceAttributes() map[string]string {
+	return ow.resource
+}
+
+// WithBatchSize sets the number of events that triggers a flush.
+func (ow *OTLPWriter) WithBatchSize(batchSize int) *OTLPWriter {
+	ow.batchSize = batchSize
+	return ow
+}
+
+// BatchSize returns the number of events that triggers a flush.
+func (ow *OTLPWriter) BatchSize() int {
+	return ow.batchSize
+}
+
+// WithFlushInterval sets the interval pending events are flushed on.
+func (ow *OTLPWriter) WithFlushInterval(interval time.Duration) *OTLPWriter {
+	ow.flushInterval = interval
+	return ow
+}
+
+// FlushInterval returns the interval pending events are flushed on.
+func (ow *OTLPWriter) FlushInterval() time.Duration {
+	return ow.flushInterval
+}
+
+// WithMaxRetries sets the number of times a failed export is retried.
+func (ow *OTLPWriter) WithMaxRetries(maxRetries int) *OTLPWriter {
+	ow.maxRetries = maxRetries
+	return ow
+}
+
+// MaxRetries returns the number of times a failed export is retried.
+func (ow *OTLPWriter) MaxRetries() int {
+	return ow.maxRetries
+}
+
+// WithRetryBackoff sets the base delay between retries; it is multiplied by the attempt number.
+func (ow *OTLPWriter) WithRetryBackoff(backoff time.Duration) *OTLPWriter {
+	ow.retryBackoff = backoff
+	return ow
+}
+
+// RetryBackoff returns the base delay between retries.
+func (ow *OTLPWriter) RetryBackoff() time.Duration {
+	return ow.retryBackoff
+}
+
+// WithClient sets the http client.
+func (ow *OTLPWriter) WithClient(client *http.Client) *OTLPWriter {
+	ow.client = client
+	return ow
+}
+
+// Client returns the http client.
+func (ow *OTLPWriter) Client() *http.Client {
+	return ow.client
+}
+
+// WithErrors sets a channel that background flush errors are sent to.
+func (ow *OTLPWriter) WithErrors(errors chan error) *OTLPWriter {
+	ow.errors = errors
+	return ow
+}
+
+// Errors returns the background flush error channel.
+func (ow *OTLPWriter) Errors() chan error {
+	return ow.errors
+}
+
+// Start starts flushing pending events on the flush interval.
+func (ow *OTLPWriter) Start() *OTLPWriter {
+	ow.Lock()
+	defer ow.Unlock()
+	if ow.flusher != nil {
+		return ow
+	}
+	ow.flusher = async.NewInterval(ow.Flush, ow.flushInterval).WithErrors(ow.errors)
+	ow.flusher.Start()
+	return ow
+}
+
+// Stop stops the background flush and flushes any pending events.
+func (ow *OTLPWriter) Stop() error {
+	ow.Lock()
+	flusher := ow.flusher
+	ow.flusher = nil
+	ow.Unlock()
+
+	if flusher != nil {
+		flusher.Stop()
+	}
+	return ow.Flush()
+}
+
+// Write queues an event to be exported.
+func (ow *OTLPWriter) Write(e Event) error {
+	return ow.write(e)
+}
+
+// WriteError queues an event to be exported.
+func (ow *OTLPWriter) WriteError(e Event) error {
+	return ow.write(e)
+}
+
+// Flush exports any pending events.
+func (ow *OTLPWriter) Flush() error {
+	ow.Lock()
+	batch := ow.batch
+	ow.batch = nil
+	ow.Unlock()
+	return ow.export(batch)
+}
+
+func (ow *OTLPWriter) write(e Event) error {
+	record := otlpLogRecordFromEvent(e)
+
+	ow.Lock()
+	ow.batch = append(ow.batch, record)
+	if len(ow.batch) < ow.batchSize {
+		ow.Unlock()
+		return nil
+	}
+	batch := ow.batch
+	ow.batch = nil
+	ow.Unlock()
+
+	return ow.export(batch)
+}
+
+func (ow *OTLPWriter) request(records []otlpLogRecord) otlpRequest {
+	keys := make([]string, 0, len(ow.resource))
+	for key := range ow.resource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	resource := make([]otlpKeyValue, 0, len(keys))
+	for _, key := range keys {
+		resource = append(resource, otlpKeyValue{key: key, value: otlpStringValue(ow.resource[key])})
+	}
+	return otlpRequest{
+		resource:  resource,
+		scopeName: OTLPScopeName,
+		records:   records,
+	}
+}
+
+func (ow *OTLPWriter) export(records []otlpLogRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	req := ow.request(records)
+	var body []byte
+	var err error
+	switch ow.protocol {
+	case OTLPProtocolHTTPJSON:
+		body, err = req.marshalJSON()
+		if err != nil {
+			return exception.New(err)
+		}
+	case OTLPProtocolHTTPProtobuf:
+		body = req.marshalProto()
+	case OTLPProtocolGRPC:
+		message := req.marshalProto()
+		body = make([]byte, 5, 5+len(message))
+		binary.BigEndian.PutUint32(body[1:], uint32(len(message)))
+		body = append(body, message...)
+	default:
+		return exception.New(ErrOTLPProtocolInvalid).WithMessagef("protocol: %s", ow.protocol)
+	}
+
+	var retryable bool
+	for attempt := 0; ; attempt++ {
+		retryable, err = ow.send(body)
+		if err == nil || !retryable || attempt >= ow.maxRetries {
+			return err
+		}
+		time.Sleep(time.Duration(attempt+1) * ow.retryBackoff)
+	}
+}
+
+// send posts a body to the collector, returning if a failure is worth retrying.
+func (ow *OTLPWriter) send(body []byte) (bool, error) {
+	url := ow.endpoint
+	if ow.protocol == OTLPProtocolGRPC {
+		url = strings.TrimSuffix(ow.endpoint, "/") + OTLPLogsGRPCMethod
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, exception.New(err)
+	}
+	switch ow.protocol {
+	case OTLPProtocolHTTPJSON:
+		req.Header.Set("Content-Type", "application/json")
+	case OTLPProtocolHTTPProtobuf:
+		req.Header.Set("Content-Type", "application/x-protobuf")
+	case OTLPProtocolGRPC:
+		req.Header.Set("Content-Type", "application/grpc")
+		req.Header.Set("TE", "trailers")
+	}
+	for key, value := range ow.headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := ow.client.Do(req)
+	if err != nil {
+		return true, exception.New(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		contents, _ := ioutil.ReadAll(res.Body)
+		retryable := res.StatusCode == http.StatusTooManyRequests ||
+			res.StatusCode == http.StatusBadGateway ||
+			res.StatusCode == http.StatusServiceUnavailable ||
+			res.StatusCode == http.StatusGatewayTimeout
+		return retryable, exception.New(ErrOTLPStatus).WithMessagef("status: %d, body: %s", res.StatusCode, strings.TrimSpace(string(contents)))
+	}
+	io.Copy(ioutil.Discard, res.Body)
+
+	if ow.protocol == OTLPProtocolGRPC {
+		// the grpc status is sent as a trailer, or as a header for trailers-only responses.
+		status := res.Trailer.Get("Grpc-Status")
+		if len(status) == 0 {
+			status = res.Header.Get("Grpc-Status")
+		}
+		if len(status) > 0 && status != "0" {
+			code, _ := strconv.Atoi(status)
+			message := res.Trailer.Get("Grpc-Message")
+			if len(message) == 0 {
+				message = res.Header.Get("Grpc-Message")
+			}
+			return otlpGRPCRetryable(code), exception.New(ErrOTLPGRPCStatus).WithMessagef("status: %d, message: %s", code, message)
+		}
+	}
+	return false, nil
+}
+
+// otlpGRPCRetryable returns if a grpc status code is retryable per the otlp spec.
+func otlpGRPCRetryable(code int) bool {
+	switch code {
+	case 1, 4, 10, 11, 14, 15: // cancelled, deadline exceeded, aborted, out of range, unavailable, data loss
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestOTLPSeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	number, text := OTLPSeverity(Error)
+	assert.Equal(17, number)
+	assert.Equal("ERROR", text)
+
+	number, text = OTLPSeverity(HTTPResponse)
+	assert.Equal(9, number)
+	assert.Equal("INFO", text)
+}
+
+func TestOTLPID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(otlpID("", 8))
+	assert.Equal([]byte{0, 0, 0, 0, 0, 0, 0, 0x2a}, otlpID("42", 8))
+	assert.Equal([]byte{0xde, 0xad, 0xbe, 0xef, 0, 0, 0, 1}, otlpID("deadbeef00000001", 8))
+	assert.Nil(otlpID("not-an-id", 8))
+}
+
+func TestOTLPProtoVarint(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]byte{0x01}, protoAppendVarint(nil, 1))
+	assert.Equal([]byte{0xac, 0x02}, protoAppendVarint(nil, 300))
+	assert.Equal([]byte{0x0a, 0x03, 'f', 'o', 'o'}, protoAppendStringField(nil, 1, "foo"))
+}
+
+func TestOTLPWriterHTTPJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	var body map[string]interface{}
+	var contentType, auth string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		contentType = req.Header.Get("Content-Type")
+		auth = req.Header.Get("Authorization")
+		json.NewDecoder(req.Body).Decode(&body)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ow := NewOTLPWriter(OTLPProtocolHTTPJSON, server.URL+OTLPLogsPath).
+		WithHeader("Authorization", "Bearer test").
+		WithResourceAttribute("service.name", "test-service")
+	me := Messagef(Warning, "foo bar")
+	me.AddLabelValue("env", "test")
+	assert.Nil(ow.Write(me))
+	assert.Nil(ow.Flush())
+
+	assert.Equal("application/json", contentType)
+	assert.Equal("Bearer test", auth)
+
+	resourceLogs := body["resourceLogs"].([]interface{})[0].(map[string]interface{})
+	resource := resourceLogs["resource"].(map[string]interface{})
+	assert.Contains(mustJSON(resource), `"service.name"`)
+	assert.Contains(mustJSON(resource), `"test-service"`)
+
+	scopeLogs := resourceLogs["scopeLogs"].([]interface{})[0].(map[string]interface{})
+	record := scopeLogs["logRecords"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(float64(13), record["severityNumber"])
+	assert.Equal("WARN", record["severityText"])
+	assert.Equal("foo bar", record["body"].(map[string]interface{})["stringValue"])
+	assert.Contains(mustJSON(record["attributes"]), `"env"`)
+}
+
+func TestOTLPWriterHTTPProtobuf(t *testing.T) {
+	assert := assert.New(t)
+
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		contentType = req.Header.Get("Content-Type")
+		body, _ = ioutil.ReadAll(req.Body)
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ow := NewOTLPWriter(OTLPProtocolHTTPProtobuf, server.URL+OTLPLogsPath).WithBatchSize(1)
+	assert.Nil(ow.Write(Messagef(Info, "foo bar")))
+	assert.Equal("application/x-protobuf", contentType)
+	assert.True(bytes.Contains(body, []byte("foo bar")))
+	assert.True(bytes.Contains(body, []byte(OTLPScopeName)))
+}
+
+func TestOTLPWriterRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ow := NewOTLPWriter(OTLPProtocolHTTPProtobuf, server.URL).WithRetryBackoff(0)
+	assert.Nil(ow.Write(Messagef(Info, "foo bar")))
+	assert.Nil(ow.Flush())
+	assert.Equal(int32(3), atomic.LoadInt32(&attempts))
+
+	atomic.StoreInt32(&attempts, 0)
+	assert.Nil(ow.WithMaxRetries(0).Write(Messagef(Info, "foo bar")))
+	err := ow.Flush()
+	assert.True(exception.Is(err, ErrOTLPStatus))
+	assert.Equal(int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestOTLPWriterGRPC(t *testing.T) {
+	assert := assert.New(t)
+
+	var path, contentType string
+	var message []byte
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		contentType = req.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(req.Body)
+		if len(body) >= 5 && int(binary.BigEndian.Uint32(body[1:5])) == len(body)-5 {
+			message = body[5:]
+		}
+		rw.Header().Set("Content-Type", "application/grpc")
+		rw.Header().Set("Trailer", "Grpc-Status")
+		rw.WriteHeader(http.StatusOK)
+		rw.Header().Set("Grpc-Status", "0")
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	ow := NewOTLPWriter(OTLPProtocolGRPC, server.URL).WithClient(server.Client())
+	assert.Nil(ow.Write(Messagef(Info, "foo bar")))
+	assert.Nil(ow.Flush())
+	assert.Equal(OTLPLogsGRPCMethod, path)
+	assert.Equal("application/grpc", contentType)
+	assert.True(bytes.Contains(message, []byte("foo bar")))
+}
+
+func TestOTLPWriterGRPCStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/grpc")
+		rw.Header().Set("Grpc-Status", "3")
+		rw.Header().Set("Grpc-Message", "bad request")
+		rw.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	ow := NewOTLPWriter(OTLPProtocolGRPC, server.URL).WithClient(server.Client())
+	assert.Nil(ow.Write(Messagef(Info, "foo bar")))
+	err := ow.Flush()
+	assert.True(exception.Is(err, ErrOTLPGRPCStatus))
+}
+
+func TestOTLPWriterInvalidProtocol(t *testing.T) {
+	assert := assert.New(t)
+
+	ow := NewOTLPWriter("carrier-pigeon", "http://localhost:4318").WithBatchSize(1)
+	assert.True(exception.Is(ow.Write(Messagef(Info, "foo")), ErrOTLPProtocolInvalid))
+}
+
+func TestOTLPWriterConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(OTLPWriterConfig{}.IsZero())
+
+	env.Env().Set(EnvVarOTLPEndpoint, "http://collector:4318/")
+	defer env.Env().Restore(EnvVarOTLPEndpoint)
+	env.Env().Set("OTEL_EXPORTER_OTLP_HEADERS", "api-key=secret,x-tenant=foo%20bar")
+	defer env.Env().Restore("OTEL_EXPORTER_OTLP_HEADERS")
+	env.Env().Set("OTEL_RESOURCE_ATTRIBUTES", "service.version=1.2.3,malformed")
+	defer env.Env().Restore("OTEL_RESOURCE_ATTRIBUTES")
+	env.Env().Set("OTEL_SERVICE_NAME", "test-service")
+	defer env.Env().Restore("OTEL_SERVICE_NAME")
+
+	cfg := NewOTLPWriterConfigFromEnv()
+	assert.False(cfg.IsZero())
+	assert.Equal("http://collector:4318/v1/logs", cfg.GetEndpoint())
+
+	ow := NewOTLPWriterFromConfig(cfg)
+	assert.Equal(DefaultOTLPProtocol, ow.Protocol())
+	assert.Equal("http://collector:4318/v1/logs", ow.Endpoint())
+	assert.Equal("secret", ow.Headers()["api-key"])
+	assert.Equal("foo bar", ow.Headers()["x-tenant"])
+	assert.Equal("1.2.3", ow.ResourceAttributes()["service.version"])
+	assert.Equal("test-service", ow.ResourceAttributes()["service.name"])
+	assert.Equal(DefaultOTLPBatchSize, ow.BatchSize())
+
+	cfg.Protocol = OTLPProtocolGRPC
+	assert.Equal("http://collector:4318/", cfg.GetEndpoint())
+	cfg.Endpoint = "http://logs:4318/custom"
+	assert.Equal("http://logs:4318/custom", cfg.GetEndpoint())
+}
+
+func mustJSON(value interface{}) string {
+	contents, _ := json.Marshal(value)
+	return string(contents)
+}
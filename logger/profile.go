@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/blend/go-sdk/env"
+)
+
+// Profile names.
+const (
+	ProfileNameProduction  = "production"
+	ProfileNameDevelopment = "development"
+	ProfileNameTest        = "test"
+)
+
+var (
+	// ProfileProduction writes json without color, with the default flags.
+	ProfileProduction = Profile{
+		Name:         ProfileNameProduction,
+		OutputFormat: OutputFormatJSON,
+		Flags:        DefaultFlags,
+		UseColor:     false,
+	}
+	// ProfileDevelopment writes colorized text, with the default flags and debug messages.
+	ProfileDevelopment = Profile{
+		Name:         ProfileNameDevelopment,
+		OutputFormat: OutputFormatText,
+		Flags:        append([]Flag{Debug}, DefaultFlags...),
+		UseColor:     true,
+	}
+	// ProfileTest writes text without color, with only warnings and errors.
+	ProfileTest = Profile{
+		Name:         ProfileNameTest,
+		OutputFormat: OutputFormatText,
+		Flags:        []Flag{Fatal, Error, Warning},
+		UseColor:     false,
+	}
+
+	// Profiles are the known profiles by name.
+	Profiles = map[string]Profile{
+		ProfileNameProduction:  ProfileProduction,
+		ProfileNameDevelopment: ProfileDevelopment,
+		ProfileNameTest:        ProfileTest,
+	}
+)
+
+// Profile is a named set of logger defaults.
+// Profile values are only used for settings that are not explicitly configured.
+type Profile struct {
+	Name         string
+	OutputFormat OutputFormat
+	Flags        []Flag
+	UseColor     bool
+}
+
+// GetProfile returns a profile by name.
+func GetProfile(name string) (Profile, bool) {
+	profile, ok := Profiles[strings.ToLower(name)]
+	return profile, ok
+}
+
+// ProfileNameForServiceEnv returns the profile name for a service environment (i.e. `SERVICE_ENV`).
+// It returns an empty string if the service environment is unset or unknown.
+/*
+Profiles aren't picked from the service environment by default; apps can opt in with:
+
+	cfg := logger.NewConfigFromEnv()
+	if cfg.Profile == "" {
+		cfg.Profile = logger.ProfileNameForServiceEnv(env.Env().ServiceEnv())
+	}
+*/
+func ProfileNameForServiceEnv(serviceEnv string) string {
+	switch strings.ToLower(serviceEnv) {
+	case env.ServiceEnvProd, env.ServiceEnvPreprod, env.ServiceEnvBeta, env.ServiceEnvSandbox:
+		return ProfileNameProduction
+	case env.ServiceEnvDev:
+		return ProfileNameDevelopment
+	case env.ServiceEnvTest, env.ServiceEnvCI:
+		return ProfileNameTest
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/uuid"
+)
+
+const (
+	// ListenerNameSentry is the listener name used for sentry listeners.
+	ListenerNameSentry = "sentry"
+
+	// ErrSentryDSNInvalid is returned when a sentry dsn cannot be parsed.
+	ErrSentryDSNInvalid exception.Class = "sentry: dsn invalid"
+	// ErrSentryStatus is returned when sentry responds with a non-2xx status code.
+	ErrSentryStatus exception.Class = "sentry: non-ok status code"
+)
+
+// AddSentryListeners forwards error and fatal events to sentry.
+func AddSentryListeners(log *Logger, client *SentryClient) {
+	if log == nil || client == nil {
+		return
+	}
+	log.Listen(Error, ListenerNameSentry, client.Listener())
+	log.Listen(Fatal, ListenerNameSentry, client.Listener())
+}
+
+// NewSentryClient returns a new sentry client for a given dsn.
+func NewSentryClient(dsn string) (*SentryClient, error) {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return nil, exception.New(ErrSentryDSNInvalid).WithInner(err)
+	}
+	if parsed.User == nil || len(parsed.User.Username()) == 0 {
+		return nil, exception.New(ErrSentryDSNInvalid).WithMessage("missing public key")
+	}
+	pathSegments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	projectID := pathSegments[len(pathSegments)-1]
+	if len(projectID) == 0 {
+		return nil, exception.New(ErrSentryDSNInvalid).WithMessage("missing project id")
+	}
+	prefix := strings.Join(pathSegments[:len(pathSegments)-1], "/")
+	if len(prefix) > 0 {
+		prefix = "/" + prefix
+	}
+
+	serverName, _ := os.Hostname()
+	return &SentryClient{
+		storeURL:        fmt.Sprintf("%s://%s%s/api/%s/store/", parsed.Scheme, parsed.Host, prefix, projectID),
+		publicKey:       parsed.User.Username(),
+		serverName:      serverName,
+		sampleRate:      DefaultSentrySampleRate,
+		rateLimit:       DefaultSentryRateLimit,
+		rateLimitWindow: DefaultSentryRateLimitWindow,
+		client:          &http.Client{Timeout: DefaultSentryTimeout},
+	}, nil
+}
+
+// NewSentryClientFromEnv returns a new sentry client from the environment.
+func NewSentryClientFromEnv() (*SentryClient, error) {
+	return NewSentryClientFromConfig(NewSentryConfigFromEnv())
+}
+
+// NewSentryClientFromConfig returns a new sentry client from a config.
+func NewSentryClientFromConfig(cfg *SentryConfig) (*SentryClient, error) {
+	client, err := NewSentryClient(cfg.GetDSN())
+	if err != nil {
+		return nil, err
+	}
+	return client.
+		WithEnvironment(cfg.GetEnvironment()).
+		WithRelease(cfg.GetRelease()).
+		WithSampleRate(cfg.GetSampleRate()).
+		WithRateLimit(cfg.GetRateLimit()), nil
+}
+
+// SentryClient sends error events to sentry.
+// Events are sampled, then rate limited to a fixed number per window.
+type SentryClient struct {
+	sync.Mutex
+
+	storeURL   string
+	publicKey  string
+	serverName string
+
+	environment string
+	release     string
+
+	sampleRate      float64
+	rateLimit       int
+	rateLimitWindow time.Duration
+	windowStart     time.Time
+	windowCount     int
+
+	client *http.Client
+}
+
+// StoreURL returns the url events are posted to.
+func (sc *SentryClient) StoreURL() string {
+	return sc.storeURL
+}
+
+// WithEnvironment sets the environment tag.
+func (sc *SentryClient) WithEnvironment(environment string) *SentryClient {
+	sc.environment = environment
+	return sc
+}
+
+// Environment returns the environment tag.
+func (sc *SentryClient) Environment() string {
+	return sc.environment
+}
+
+// WithRelease sets the release tag.
+func (sc *SentryClient) WithRelease(release string) *SentryClient {
+	sc.release = release
+	return sc
+}
+
+// Release returns the release tag.
+func (sc *SentryClient) Release() string {
+	return sc.release
+}
+
+// WithServerName sets the server name.
+func (sc *SentryClient) WithServerName(serverName string) *SentryClient {
+	sc.serverName = serverName
+	return sc
+}
+
+// ServerName returns the server name.
+func (sc *SentryClient) ServerName() string {
+	return sc.serverName
+}
+
+// WithSampleRate sets the fraction of events (between 0 and 1) that are sent.
+func (sc *SentryClient) WithSampleRate(sampleRate float64) *SentryClient {
+	sc.sampleRate = sampleRate
+	return sc
+}
+
+// SampleRate returns the fraction of events that are sent.
+func (sc *SentryClient) SampleRate() float64 {
+	return sc.sampleRate
+}
+
+// WithRateLimit sets the maximum number of events sent per rate limit window.
+// A value of zero disables rate limiting.
+func (sc *SentryClient) WithRateLimit(rateLimit int) *SentryClient {
+	sc.rateLimit = rateLimit
+	return sc
+}
+
+// RateLimit returns the maximum number of events sent per rate limit window.
+func (sc *SentryClient) RateLimit() int {
+	return sc.rateLimit
+}
+
+// WithRateLimitWindow sets the rate limit window.
+func (sc *SentryClient) WithRateLimitWindow(window time.Duration) *SentryClient {
+	sc.rateLimitWindow = window
+	return sc
+}
+
+// RateLimitWindow returns the rate limit window.
+func (sc *SentryClient) RateLimitWindow() time.Duration {
+	return sc.rateLimitWindow
+}
+
+// WithClient sets the http client.
+func (sc *SentryClient) WithClient(client *http.Client) *SentryClient {
+	sc.client = client
+	return sc
+}
+
+// Listener returns a listener that captures error events.
+func (sc *SentryClient) Listener() Listener {
+	return NewErrorEventListener(func(ee *ErrorEvent) {
+		sc.Capture(ee)
+	})
+}
+
+// Capture sends an error event to sentry, unless it is sampled out or rate limited.
+// It returns if the event was sent.
+func (sc *SentryClient) Capture(ee *ErrorEvent) (bool, error) {
+	if ee == nil || ee.Err() == nil || !sc.allow() {
+		return false, nil
+	}
+	contents, err := json.Marshal(sc.packet(ee))
+	if err != nil {
+		return false, exception.New(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, sc.storeURL, bytes.NewReader(contents))
+	if err != nil {
+		return false, exception.New(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-sdk/1.0, sentry_timestamp=%d, sentry_key=%s", time.Now().UTC().Unix(), sc.publicKey))
+
+	res, err := sc.client.Do(req)
+	if err != nil {
+		return false, exception.New(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		return false, exception.New(ErrSentryStatus).WithMessagef("status: %d", res.StatusCode)
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	return true, nil
+}
+
+// allow applies sampling and rate limiting.
+func (sc *SentryClient) allow() bool {
+	if sc.sampleRate <= 0 || (sc.sampleRate < 1 && rand.Float64() >= sc.sampleRate) {
+		return false
+	}
+	if sc.rateLimit <= 0 {
+		return true
+	}
+
+	sc.Lock()
+	defer sc.Unlock()
+	now := time.Now().UTC()
+	if now.Sub(sc.windowStart) >= sc.rateLimitWindow {
+		sc.windowStart = now
+		sc.windowCount = 0
+	}
+	if sc.windowCount >= sc.rateLimit {
+		return false
+	}
+	sc.windowCount++
+	return true
+}
+
+// SentryPacket is the body of a sentry store request.
+type SentryPacket struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger"`
+	Platform    string            `json:"platform"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Exception   struct {
+		Values []SentryException `json:"values"`
+	} `json:"exception"`
+}
+
+// SentryException is an exception in a sentry packet.
+type SentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *SentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+// SentryStacktrace is a stack trace in a sentry packet.
+type SentryStacktrace struct {
+	Frames []SentryFrame `json:"frames"`
+}
+
+// SentryFrame is a stack frame in a sentry packet.
+type SentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+}
+
+func (sc *SentryClient) packet(ee *ErrorEvent) SentryPacket {
+	packet := SentryPacket{
+		EventID:     uuid.V4().ToShortString(),
+		Timestamp:   ee.Timestamp().UTC().Format("2006-01-02T15:04:05"),
+		Level:       string(ee.Flag()),
+		Logger:      "go-sdk",
+		Platform:    "go",
+		ServerName:  sc.serverName,
+		Environment: sc.environment,
+		Release:     sc.release,
+		Tags:        ee.Labels(),
+	}
+
+	exceptionValue := SentryException{
+		Type:  exception.ErrClass(ee.Err()),
+		Value: ee.Err().Error(),
+	}
+	if ex := exception.As(ee.Err()); ex != nil {
+		if len(ex.Message()) > 0 {
+			exceptionValue.Value = ex.Message()
+		}
+		if frames := sentryFrames(ex.Stack()); len(frames) > 0 {
+			exceptionValue.Stacktrace = &SentryStacktrace{Frames: frames}
+		}
+	}
+	packet.Message = exceptionValue.Type
+	packet.Exception.Values = []SentryException{exceptionValue}
+	return packet
+}
+
+// sentryFrames returns frames oldest to newest, as sentry expects.
+func sentryFrames(stack exception.StackTrace) []SentryFrame {
+	if stack == nil {
+		return nil
+	}
+	var frames []SentryFrame
+	if pointers, isPointers := stack.(*exception.StackPointers); isPointers && pointers != nil {
+		stack = *pointers
+	}
+	switch typed := stack.(type) {
+	case exception.StackPointers:
+		for index := len(typed) - 1; index >= 0; index-- {
+			pc := typed[index] - 1
+			fn := runtime.FuncForPC(pc)
+			if fn == nil {
+				continue
+			}
+			file, line := fn.FileLine(pc)
+			frames = append(frames, SentryFrame{Function: fn.Name(), Filename: file, Lineno: line})
+		}
+	default:
+		values := stack.Strings()
+		for index := len(values) - 1; index >= 0; index-- {
+			frames = append(frames, SentryFrame{Function: values[index]})
+		}
+	}
+	return frames
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestNewSentryClient(t *testing.T) {
+	assert := assert.New(t)
+
+	client, err := NewSentryClient("https://public@sentry.example.com/prefix/42")
+	assert.Nil(err)
+	assert.Equal("https://sentry.example.com/prefix/api/42/store/", client.StoreURL())
+
+	_, err = NewSentryClient("https://sentry.example.com/42")
+	assert.True(exception.Is(err, ErrSentryDSNInvalid))
+	_, err = NewSentryClient("https://public@sentry.example.com/")
+	assert.True(exception.Is(err, ErrSentryDSNInvalid))
+}
+
+func TestSentryClientCapture(t *testing.T) {
+	assert := assert.New(t)
+
+	var packet SentryPacket
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		auth = req.Header.Get("X-Sentry-Auth")
+		assert.Nil(json.NewDecoder(req.Body).Decode(&packet))
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewSentryClient(strings.Replace(server.URL, "http://", "http://public@", 1) + "/42")
+	assert.Nil(err)
+	client.WithEnvironment("test").WithRelease("v1.0.0")
+
+	sent, err := client.Capture(NewErrorEvent(Error, exception.New("test class").WithMessage("test message")))
+	assert.Nil(err)
+	assert.True(sent)
+
+	assert.Contains(auth, "sentry_key=public")
+	assert.Equal("error", packet.Level)
+	assert.Equal("test", packet.Environment)
+	assert.Equal("v1.0.0", packet.Release)
+	assert.Len(packet.Exception.Values, 1)
+	assert.Equal("test class", packet.Exception.Values[0].Type)
+	assert.Equal("test message", packet.Exception.Values[0].Value)
+	assert.NotNil(packet.Exception.Values[0].Stacktrace)
+	frames := packet.Exception.Values[0].Stacktrace.Frames
+	assert.Contains(frames[len(frames)-1].Function, "TestSentryClientCapture")
+}
+
+func TestSentryClientSamplingAndRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewSentryClient(strings.Replace(server.URL, "http://", "http://public@", 1) + "/42")
+	assert.Nil(err)
+
+	client.WithSampleRate(0)
+	sent, err := client.Capture(Errorf(Error, "sampled out"))
+	assert.Nil(err)
+	assert.False(sent)
+
+	client.WithSampleRate(1).WithRateLimit(2)
+	for x := 0; x < 5; x++ {
+		client.Capture(Errorf(Error, "rate limited"))
+	}
+	assert.Equal(2, requests)
+}
@@ -0,0 +1,414 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ErrSplunkStatus is returned when the collector responds with a non-2xx status code.
+	ErrSplunkStatus exception.Class = "splunk hec: non-ok status code"
+)
+
+// Asserts splunk writer is a writer.
+var (
+	_ Writer        = &SplunkWriter{}
+	_ WriterFlusher = &SplunkWriter{}
+)
+
+// NewSplunkWriter returns a new splunk http event collector writer for a given collector url and token.
+// It must be started with `.Start()` to flush on an interval.
+func NewSplunkWriter(url, token string) *SplunkWriter {
+	host, _ := os.Hostname()
+	return &SplunkWriter{
+		url:           url,
+		token:         token,
+		host:          host,
+		batchSize:     DefaultSplunkBatchSize,
+		flushInterval: DefaultSplunkFlushInterval,
+		maxRetries:    DefaultSplunkMaxRetries,
+		retryBackoff:  DefaultSplunkRetryBackoff,
+		gzip:          DefaultSplunkGzip,
+		client:        &http.Client{Timeout: DefaultSplunkTimeout},
+	}
+}
+
+// NewSplunkWriterFromEnv returns a new splunk writer from the environment.
+func NewSplunkWriterFromEnv() *SplunkWriter {
+	return NewSplunkWriterFromConfig(NewSplunkWriterConfigFromEnv())
+}
+
+// NewSplunkWriterFromConfig returns a new splunk writer from a config.
+func NewSplunkWriterFromConfig(cfg *SplunkWriterConfig) *SplunkWriter {
+	return NewSplunkWriter(cfg.GetURL(), cfg.GetToken()).
+		WithHost(cfg.GetHost()).
+		WithIndex(cfg.GetIndex()).
+		WithSource(cfg.GetSource()).
+		WithSourceType(cfg.GetSourceType()).
+		WithBatchSize(cfg.GetBatchSize()).
+		WithFlushInterval(cfg.GetFlushInterval()).
+		WithMaxRetries(cfg.GetMaxRetries()).
+		WithGzip(cfg.GetGzip()).
+		WithClient(&http.Client{Timeout: cfg.GetTimeout()})
+}
+
+// SplunkWriter batches events and posts them to a splunk http event collector.
+type SplunkWriter struct {
+	sync.Mutex
+
+	url        string
+	token      string
+	host       string
+	index      string
+	source     string
+	sourceType string
+
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	retryBackoff  time.Duration
+	gzip          bool
+
+	client *http.Client
+	errors chan error
+
+	batch   [][]byte
+	posting chan struct{}
+	flusher *async.Interval
+}
+
+// OutputFormat returns the output format.
+func (sw *SplunkWriter) OutputFormat() OutputFormat {
+	return OutputFormatJSON
+}
+
+// Output returns nil; the splunk writer has no local output stream.
+func (sw *SplunkWriter) Output() io.Writer {
+	return nil
+}
+
+// ErrorOutput returns nil; the splunk writer has no local output stream.
+func (sw *SplunkWriter) ErrorOutput() io.Writer {
+	return nil
+}
+
+// URL returns the collector url.
+func (sw *SplunkWriter) URL() string {
+	return sw.url
+}
+
+// Token returns the collector token.
+func (sw *SplunkWriter) Token() string {
+	return sw.token
+}
+
+// WithHost sets the host field sent with events.
+func (sw *SplunkWriter) WithHost(host string) *SplunkWriter {
+	sw.host = host
+	return sw
+}
+
+// Host returns the host field sent with events.
+func (sw *SplunkWriter) Host() string {
+	return sw.host
+}
+
+// WithIndex sets the index field sent with events.
+func (sw *SplunkWriter) WithIndex(index string) *SplunkWriter {
+	sw.index = index
+	return sw
+}
+
+// Index returns the index field sent with events.
+func (sw *SplunkWriter) Index() string {
+	return sw.index
+}
+
+// WithSource sets the source field sent with events.
+func (sw *SplunkWriter) WithSource(source string) *SplunkWriter {
+	sw.source = source
+	return sw
+}
+
+// Source returns the source field sent with events.
+func (sw *SplunkWriter) Source() string {
+	return sw.source
+}
+
+// WithSourceType sets the sourcetype field sent with events.
+func (sw *SplunkWriter) WithSourceType(sourceType string) *SplunkWriter {
+	sw.sourceType = sourceType
+	return sw
+}
+
+// SourceType returns the sourcetype field sent with events.
+func (sw *SplunkWriter) SourceType() string {
+	return sw.sourceType
+}
+
+// WithBatchSize sets the number of events that triggers a flush.
+func (sw *SplunkWriter) WithBatchSize(batchSize int) *SplunkWriter {
+	sw.batchSize = batchSize
+	return sw
+}
+
+// BatchSize returns the number of events that triggers a flush.
+func (sw *SplunkWriter) BatchSize() int {
+	return sw.batchSize
+}
+
+// WithFlushInterval sets the interval pending events are flushed on.
+func (sw *SplunkWriter) WithFlushInterval(interval time.Duration) *SplunkWriter {
+	sw.flushInterval = interval
+	return sw
+}
+
+// FlushInterval returns the interval pending events are flushed on.
+func (sw *SplunkWriter) FlushInterval() time.Duration {
+	return sw.flushInterval
+}
+
+// WithMaxRetries sets the number of times a failed post is retried.
+func (sw *SplunkWriter) WithMaxRetries(maxRetries int) *SplunkWriter {
+	sw.maxRetries = maxRetries
+	return sw
+}
+
+// MaxRetries returns the number of times a failed post is retried.
+func (sw *SplunkWriter) MaxRetries() int {
+	return sw.maxRetries
+}
+
+// WithRetryBackoff sets the base delay between retries; it is multiplied by the attempt number.
+func (sw *SplunkWriter) WithRetryBackoff(backoff time.Duration) *SplunkWriter {
+	sw.retryBackoff = backoff
+	return sw
+}
+
+// RetryBackoff returns the base delay between retries.
+func (sw *SplunkWriter) RetryBackoff() time.Duration {
+	return sw.retryBackoff
+}
+
+// WithGzip sets if request bodies should be gzipped.
+func (sw *SplunkWriter) WithGzip(gzip bool) *SplunkWriter {
+	sw.gzip = gzip
+	return sw
+}
+
+// Gzip returns if request bodies are gzipped.
+func (sw *SplunkWriter) Gzip() bool {
+	return sw.gzip
+}
+
+// WithClient sets the http client.
+func (sw *SplunkWriter) WithClient(client *http.Client) *SplunkWriter {
+	sw.client = client
+	return sw
+}
+
+// Client returns the http client.
+func (sw *SplunkWriter) Client() *http.Client {
+	return sw.client
+}
+
+// WithErrors sets a channel that background flush errors are sent to.
+func (sw *SplunkWriter) WithErrors(errors chan error) *SplunkWriter {
+	sw.errors = errors
+	return sw
+}
+
+// Errors returns the background flush error channel.
+func (sw *SplunkWriter) Errors() chan error {
+	return sw.errors
+}
+
+// Start starts flushing pending events on the flush interval.
+func (sw *SplunkWriter) Start() *SplunkWriter {
+	sw.Lock()
+	defer sw.Unlock()
+	if sw.flusher != nil {
+		return sw
+	}
+	sw.flusher = async.NewInterval(sw.Flush, sw.flushInterval).WithErrors(sw.errors)
+	sw.flusher.Start()
+	return sw
+}
+
+// Stop stops the background flush and flushes any pending events.
+func (sw *SplunkWriter) Stop() error {
+	sw.Lock()
+	flusher := sw.flusher
+	sw.flusher = nil
+	sw.Unlock()
+
+	if flusher != nil {
+		flusher.Stop()
+	}
+	return sw.Flush()
+}
+
+// Write queues an event to be sent to the collector.
+// Full batches are posted in the background, so writes don't wait on the collector; errors posting
+// them are sent to the errors channel.
+func (sw *SplunkWriter) Write(e Event) error {
+	return sw.write(e)
+}
+
+// WriteError queues an event to be sent to the collector.
+func (sw *SplunkWriter) WriteError(e Event) error {
+	return sw.write(e)
+}
+
+// Flush waits for a full batch being posted in the background, if any, and posts any pending events to the collector.
+func (sw *SplunkWriter) Flush() error {
+	sw.Lock()
+	posting := sw.posting
+	sw.Unlock()
+	if posting != nil {
+		<-posting
+	}
+
+	sw.Lock()
+	batch := sw.batch
+	sw.batch = nil
+	sw.Unlock()
+	return sw.post(batch)
+}
+
+func (sw *SplunkWriter) write(e Event) error {
+	contents, err := sw.encode(e)
+	if err != nil {
+		return err
+	}
+
+	sw.Lock()
+	defer sw.Unlock()
+	sw.batch = append(sw.batch, contents)
+	// one full batch is posted at a time; events queue behind it until it's done.
+	if len(sw.batch) < sw.batchSize || sw.posting != nil {
+		return nil
+	}
+	batch := sw.batch
+	sw.batch = nil
+	sw.posting = make(chan struct{})
+	go sw.postBackground(batch, sw.posting)
+	return nil
+}
+
+// postBackground posts a full batch, sending any error to the errors channel.
+func (sw *SplunkWriter) postBackground(batch [][]byte, posting chan struct{}) {
+	err := sw.post(batch)
+	sw.Lock()
+	sw.posting = nil
+	sw.Unlock()
+	close(posting)
+
+	if err != nil && sw.errors != nil {
+		sw.errors <- err
+	}
+}
+
+// splunkEvent is the http event collector envelope.
+type splunkEvent struct {
+	Time       float64     `json:"time"`
+	Host       string      `json:"host,omitempty"`
+	Index      string      `json:"index,omitempty"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Event      interface{} `json:"event"`
+}
+
+func (sw *SplunkWriter) encode(e Event) ([]byte, error) {
+	var event interface{} = e
+	if fields, isFields := JSONFields(e); isFields {
+		event = fields
+	}
+	return json.Marshal(splunkEvent{
+		Time:       float64(e.Timestamp().UnixNano()) / float64(time.Second),
+		Host:       sw.host,
+		Index:      sw.index,
+		Source:     sw.source,
+		SourceType: sw.sourceType,
+		Event:      event,
+	})
+}
+
+func (sw *SplunkWriter) post(batch [][]byte) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	body, err := sw.body(batch)
+	if err != nil {
+		return err
+	}
+
+	var retryable bool
+	for attempt := 0; ; attempt++ {
+		retryable, err = sw.send(body)
+		if err == nil || !retryable || attempt >= sw.maxRetries {
+			return err
+		}
+		time.Sleep(time.Duration(attempt+1) * sw.retryBackoff)
+	}
+}
+
+func (sw *SplunkWriter) body(batch [][]byte) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	var output io.Writer = buffer
+	var gzipWriter *gzip.Writer
+	if sw.gzip {
+		gzipWriter = gzip.NewWriter(buffer)
+		output = gzipWriter
+	}
+	for _, contents := range batch {
+		if _, err := output.Write(contents); err != nil {
+			return nil, err
+		}
+	}
+	if gzipWriter != nil {
+		if err := gzipWriter.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+// send posts a body to the collector, returning if a failure is worth retrying.
+func (sw *SplunkWriter) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, sw.url, bytes.NewReader(body))
+	if err != nil {
+		return false, exception.New(err)
+	}
+	req.Header.Set("Authorization", "Splunk "+sw.token)
+	req.Header.Set("Content-Type", "application/json")
+	if sw.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	res, err := sw.client.Do(req)
+	if err != nil {
+		return true, exception.New(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		contents, _ := ioutil.ReadAll(res.Body)
+		retryable := res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+		return retryable, exception.New(ErrSplunkStatus).WithMessagef("status: %d, body: %s", res.StatusCode, strings.TrimSpace(string(contents)))
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	return false, nil
+}
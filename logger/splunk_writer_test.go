@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestSplunkWriter(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal("Splunk test-token", req.Header.Get("Authorization"))
+		assert.Equal("gzip", req.Header.Get("Content-Encoding"))
+
+		body, err := gzip.NewReader(req.Body)
+		assert.Nil(err)
+		decoder := json.NewDecoder(bufio.NewReader(body))
+		lock.Lock()
+		defer lock.Unlock()
+		for decoder.More() {
+			var event map[string]interface{}
+			assert.Nil(decoder.Decode(&event))
+			events = append(events, event)
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sw := NewSplunkWriter(server.URL, "test-token").WithBatchSize(2).WithIndex("test-index")
+	assert.Nil(sw.Write(Messagef(Info, "foo")))
+	assert.Empty(events)
+	assert.Nil(sw.WriteError(Errorf(Error, "bar")))
+	assert.Nil(sw.Flush())
+
+	lock.Lock()
+	assert.Len(events, 2)
+	assert.Equal("test-index", events[0]["index"])
+	assert.Equal("foo", events[0]["event"].(map[string]interface{})[JSONFieldMessage])
+	assert.Equal("bar", events[1]["event"].(map[string]interface{})[JSONFieldErr])
+	lock.Unlock()
+
+	assert.Nil(sw.Write(Messagef(Info, "baz")))
+	assert.Nil(sw.Flush())
+	lock.Lock()
+	assert.Len(events, 3)
+	lock.Unlock()
+}
+
+func TestSplunkWriterPostsFullBatchesInBackground(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	var lock sync.Mutex
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+		lock.Lock()
+		posts++
+		lock.Unlock()
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sw := NewSplunkWriter(server.URL, "test-token").WithBatchSize(1)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		// the second full batch waits for the first to be posted.
+		assert.Nil(sw.Write(Messagef(Info, "foo")))
+		assert.Nil(sw.Write(Messagef(Info, "bar")))
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		assert.FailNow("writes should not wait on the collector")
+	}
+
+	close(release)
+	assert.Nil(sw.Flush())
+	lock.Lock()
+	assert.Equal(2, posts)
+	lock.Unlock()
+}
+
+func TestSplunkWriterStoppedOnLoggerClose(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var posts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		posts++
+		lock.Unlock()
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sw := NewSplunkWriter(server.URL, "test-token").WithFlushInterval(time.Hour).Start()
+	log := New(Info).WithWriter(sw)
+	log.SyncInfof("foo")
+	log.Close()
+
+	sw.Lock()
+	assert.Nil(sw.flusher)
+	sw.Unlock()
+	lock.Lock()
+	assert.Equal(1, posts)
+	lock.Unlock()
+}
+
+func TestSplunkWriterRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		if attempts < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sw := NewSplunkWriter(server.URL, "test-token").WithRetryBackoff(time.Millisecond).WithGzip(false)
+	assert.Nil(sw.Write(Messagef(Info, "foo")))
+	assert.Nil(sw.Flush())
+	assert.Equal(3, attempts)
+}
+
+func TestSplunkWriterNoRetryOnClientError(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		attempts++
+		rw.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	sw := NewSplunkWriter(server.URL, "test-token").WithRetryBackoff(time.Millisecond)
+	assert.Nil(sw.Write(Messagef(Info, "foo")))
+	err := sw.Flush()
+	assert.NotNil(err)
+	assert.True(exception.Is(err, ErrSplunkStatus))
+	assert.Equal(1, attempts)
+}
+
+func TestSplunkWriterConfigFromEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	env.Env().Set(EnvVarSplunkURL, "https://splunk.example.com/services/collector")
+	defer env.Env().Restore(EnvVarSplunkURL)
+	env.Env().Set(EnvVarSplunkToken, "test-token")
+	defer env.Env().Restore(EnvVarSplunkToken)
+	env.Env().Set("LOG_SPLUNK_BATCH_SIZE", "10")
+	defer env.Env().Restore("LOG_SPLUNK_BATCH_SIZE")
+
+	cfg := NewSplunkWriterConfigFromEnv()
+	assert.False(cfg.IsZero())
+	assert.Equal(10, cfg.GetBatchSize())
+	assert.Equal(DefaultSplunkFlushInterval, cfg.GetFlushInterval())
+	assert.Equal(DefaultSplunkGzip, cfg.GetGzip())
+
+	sw := NewSplunkWriterFromConfig(cfg)
+	assert.Equal("test-token", sw.Token())
+	assert.Equal(10, sw.BatchSize())
+
+	assert.True(SplunkWriterConfig{}.IsZero())
+}
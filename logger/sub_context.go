@@ -1,5 +1,7 @@
 package logger
 
+import "context"
+
 var (
 	// This is a compile time assertion `SubContext` implements `FullReceiver`.
 	_ FullReceiver = &SubContext{}
@@ -7,11 +9,13 @@ var (
 
 // SubContext is a sub-reference to a logger with a specific heading and set of default labels for messages.
 // It implements the full logger suite but forwards them up to the parent logger.
+// It can override which flags are enabled, e.g. to turn on debug output for one subsystem only.
 type SubContext struct {
-	log         *Logger
-	headings    []string
-	labels      map[string]string
-	annotations map[string]string
+	log           *Logger
+	headings      []string
+	labels        map[string]string
+	annotations   map[string]string
+	flagOverrides map[Flag]bool
 }
 
 // Logger returns the underlying logger.
@@ -21,12 +25,47 @@ func (sc *SubContext) Logger() *Logger {
 
 // SubContext returns a further sub-context with a given heading.
 func (sc *SubContext) SubContext(heading string) *SubContext {
+	var flagOverrides map[Flag]bool
+	if len(sc.flagOverrides) > 0 {
+		flagOverrides = make(map[Flag]bool, len(sc.flagOverrides))
+		for flag, enabled := range sc.flagOverrides {
+			flagOverrides[flag] = enabled
+		}
+	}
 	return &SubContext{
-		headings:    append(sc.headings, heading),
-		labels:      sc.labels,
-		annotations: sc.annotations,
-		log:         sc.log,
+		headings:      append(sc.headings, heading),
+		labels:        sc.labels,
+		annotations:   sc.annotations,
+		flagOverrides: flagOverrides,
+		log:           sc.log,
+	}
+}
+
+// WithEnabled enables flags for events triggered through the sub-context, regardless of the parent logger's flags.
+// Further sub-contexts inherit the override.
+func (sc *SubContext) WithEnabled(flags ...Flag) *SubContext {
+	return sc.withFlagOverrides(true, flags...)
+}
+
+// WithDisabled disables flags for events triggered through the sub-context, regardless of the parent logger's flags.
+// Further sub-contexts inherit the override.
+func (sc *SubContext) WithDisabled(flags ...Flag) *SubContext {
+	return sc.withFlagOverrides(false, flags...)
+}
+
+// IsEnabled returns if a flag is enabled for the sub-context, either by override or by the parent logger.
+func (sc *SubContext) IsEnabled(flag Flag) bool {
+	return sc.log.isEnabled(flag, sc.flagOverrides)
+}
+
+func (sc *SubContext) withFlagOverrides(enabled bool, flags ...Flag) *SubContext {
+	if sc.flagOverrides == nil {
+		sc.flagOverrides = map[Flag]bool{}
+	}
+	for _, flag := range flags {
+		sc.flagOverrides[flag] = enabled
 	}
+	return sc
 }
 
 // Headings returns the headings.
@@ -80,7 +119,7 @@ func (sc *SubContext) Sillyf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncSillyf synchronously writes a message.
@@ -89,7 +128,7 @@ func (sc *SubContext) SyncSillyf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // Infof writes a message.
@@ -98,7 +137,7 @@ func (sc *SubContext) Infof(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncInfof synchronously writes a message.
@@ -107,7 +146,7 @@ func (sc *SubContext) SyncInfof(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // Debugf writes a message.
@@ -116,7 +155,7 @@ func (sc *SubContext) Debugf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncDebugf synchronously writes a message.
@@ -125,7 +164,7 @@ func (sc *SubContext) SyncDebugf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // Warningf writes an error message.
@@ -134,7 +173,7 @@ func (sc *SubContext) Warningf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // Warning writes an error message.
@@ -143,7 +182,7 @@ func (sc *SubContext) Warning(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncWarningf synchronously writes an error message.
@@ -152,7 +191,7 @@ func (sc *SubContext) SyncWarningf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // SyncWarning writes a message.
@@ -161,7 +200,7 @@ func (sc *SubContext) SyncWarning(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // Errorf writes an error  message.
@@ -170,7 +209,7 @@ func (sc *SubContext) Errorf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // Error writes an error message.
@@ -179,7 +218,7 @@ func (sc *SubContext) Error(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncErrorf synchronously writes an error message.
@@ -188,7 +227,7 @@ func (sc *SubContext) SyncErrorf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // SyncError writes an error message.
@@ -197,7 +236,7 @@ func (sc *SubContext) SyncError(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // Fatalf writes an error  message.
@@ -206,7 +245,7 @@ func (sc *SubContext) Fatalf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // Fatal writes an error message.
@@ -215,7 +254,7 @@ func (sc *SubContext) Fatal(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.Trigger(msg)
+	sc.log.triggerWithOverrides(true, msg, sc.flagOverrides)
 }
 
 // SyncFatalf synchronously writes an error message.
@@ -224,7 +263,7 @@ func (sc *SubContext) SyncFatalf(format string, args ...Any) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
 }
 
 // SyncFatal writes an error message.
@@ -233,7 +272,18 @@ func (sc *SubContext) SyncFatal(err error) {
 	sc.injectHeadings(msg)
 	sc.injectLabels(msg)
 	sc.injectAnnotations(msg)
-	sc.log.SyncTrigger(msg)
+	sc.log.triggerWithOverrides(false, msg, sc.flagOverrides)
+}
+
+// Timer returns a started timer for a named operation.
+// Call `Stop` on the timer to trigger a timed event with the elapsed time.
+func (sc *SubContext) Timer(name string) *Timer {
+	return NewTimer(sc, name)
+}
+
+// Time runs an action and triggers a timed event with its elapsed time.
+func (sc *SubContext) Time(name string, action func() error) error {
+	return TimeAction(sc, name, action)
 }
 
 // Trigger triggers listeners asynchronously.
@@ -241,7 +291,7 @@ func (sc *SubContext) Trigger(e Event) {
 	sc.injectHeadings(e)
 	sc.injectLabels(e)
 	sc.injectAnnotations(e)
-	sc.log.trigger(true, e)
+	sc.log.triggerWithOverrides(true, e, sc.flagOverrides)
 }
 
 // SyncTrigger triggers event listeners synchronously.
@@ -249,7 +299,19 @@ func (sc *SubContext) SyncTrigger(e Event) {
 	sc.injectHeadings(e)
 	sc.injectLabels(e)
 	sc.injectAnnotations(e)
-	sc.log.trigger(false, e)
+	sc.log.triggerWithOverrides(false, e, sc.flagOverrides)
+}
+
+// TriggerContext triggers listeners asynchronously with trace ids from a context.
+func (sc *SubContext) TriggerContext(ctx context.Context, e Event) {
+	InjectTrace(ctx, sc.log.traceCorrelator, e)
+	sc.Trigger(e)
+}
+
+// SyncTriggerContext triggers event listeners synchronously with trace ids from a context.
+func (sc *SubContext) SyncTriggerContext(ctx context.Context, e Event) {
+	InjectTrace(ctx, sc.log.traceCorrelator, e)
+	sc.SyncTrigger(e)
 }
 
 // injectHeadings injects the sub-context's headings into an event if it supports headings.
@@ -267,7 +329,11 @@ func (sc *SubContext) injectLabels(e Event) {
 	if sc.labels == nil {
 		return
 	}
-	if typed, isTyped := e.(EventLabels); isTyped {
+	if typed, isTyped := e.(interface{ AddLabelValue(string, string) }); isTyped {
+		for key, value := range sc.labels {
+			typed.AddLabelValue(key, value)
+		}
+	} else if typed, isTyped := e.(EventLabels); isTyped && typed.Labels() != nil {
 		for key, value := range sc.labels {
 			typed.Labels()[key] = value
 		}
@@ -279,7 +345,11 @@ func (sc *SubContext) injectAnnotations(e Event) {
 	if sc.annotations == nil {
 		return
 	}
-	if typed, isTyped := e.(EventAnnotations); isTyped {
+	if typed, isTyped := e.(interface{ AddAnnotationValue(string, string) }); isTyped {
+		for key, value := range sc.annotations {
+			typed.AddAnnotationValue(key, value)
+		}
+	} else if typed, isTyped := e.(EventAnnotations); isTyped && typed.Annotations() != nil {
 		for key, value := range sc.annotations {
 			typed.Annotations()[key] = value
 		}
@@ -266,3 +266,34 @@ func TestSubContextSyncTrigger(t *testing.T) {
 	sc.SyncTrigger(Messagef(Info, "this is only a test").WithHeadings("message"))
 	assert.Equal("[sub-context > message] [info] this is only a test\n", output.String())
 }
+
+func TestSubContextFlagOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	l := New(Info).WithWriter(NewTextWriter(output).WithShowTimestamp(false).WithUseColor(false))
+	defer l.Close()
+
+	verbose := l.SubContext("verbose").WithEnabled(Debug).WithDisabled(Info)
+	assert.True(verbose.IsEnabled(Debug))
+	assert.False(verbose.IsEnabled(Info))
+	assert.False(l.IsEnabled(Debug))
+
+	verbose.SyncDebugf("debug from sub-context")
+	verbose.SyncInfof("info from sub-context")
+	l.SyncDebugf("debug from logger")
+	l.SyncInfof("info from logger")
+
+	assert.Contains(output.String(), "debug from sub-context")
+	assert.NotContains(output.String(), "info from sub-context")
+	assert.NotContains(output.String(), "debug from logger")
+	assert.Contains(output.String(), "info from logger")
+
+	output.Reset()
+	child := verbose.SubContext("child")
+	assert.True(child.IsEnabled(Debug))
+	child.WithEnabled(Info)
+	assert.False(verbose.IsEnabled(Info), "child overrides should not affect the parent")
+	child.SyncInfof("info from child")
+	assert.Contains(output.String(), "[verbose > child] [info] info from child")
+}
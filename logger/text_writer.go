@@ -41,6 +41,7 @@ func NewTextWriter(output io.Writer) *TextWriter {
 		showTimestamp: DefaultTextWriterShowTimestamp,
 		useColor:      DefaultTextWriterUseColor,
 		timeFormat:    DefaultTextTimeFormat,
+		indent:        DefaultTextWriterIndent,
 	}
 }
 
@@ -57,13 +58,15 @@ func NewTextWriterFromEnv() *TextWriter {
 // NewTextWriterFromConfig creates a new text writer from a given config.
 func NewTextWriterFromConfig(cfg *TextWriterConfig) *TextWriter {
 	return &TextWriter{
-		output:        NewInterlockedWriter(os.Stdout),
-		errorOutput:   NewInterlockedWriter(os.Stderr),
-		bufferPool:    NewBufferPool(DefaultBufferPoolSize),
-		showTimestamp: cfg.GetShowTimestamp(),
-		showHeadings:  cfg.GetShowHeadings(),
-		useColor:      cfg.GetUseColor(),
-		timeFormat:    cfg.GetTimeFormat(),
+		output:         NewInterlockedWriter(os.Stdout),
+		errorOutput:    NewInterlockedWriter(os.Stderr),
+		bufferPool:     NewBufferPool(DefaultBufferPoolSize),
+		showTimestamp:  cfg.GetShowTimestamp(),
+		showHeadings:   cfg.GetShowHeadings(),
+		useColor:       cfg.GetUseColor(),
+		timeFormat:     cfg.GetTimeFormat(),
+		escapeNewlines: cfg.GetEscapeNewlines(),
+		indent:         DefaultTextWriterIndent,
 	}
 }
 
@@ -78,6 +81,9 @@ type TextWriter struct {
 
 	timeFormat string
 
+	escapeNewlines bool
+	indent         string
+
 	bufferPool *BufferPool
 }
 
@@ -130,6 +136,45 @@ func (wr *TextWriter) TimeFormat() string {
 	return wr.timeFormat
 }
 
+// WithEscapeNewlines sets a formatting option.
+// If true, multi-line messages (and error stacks) are written on a single line with escaped newlines,
+// and backslashes are escaped so escaped newlines can't be confused with literal ones;
+// otherwise continuation lines are written as an indented block.
+func (wr *TextWriter) WithEscapeNewlines(escapeNewlines bool) *TextWriter {
+	wr.escapeNewlines = escapeNewlines
+	return wr
+}
+
+// EscapeNewlines is a formatting option.
+func (wr *TextWriter) EscapeNewlines() bool {
+	return wr.escapeNewlines
+}
+
+// WithIndent sets the prefix for continuation lines of multi-line messages.
+func (wr *TextWriter) WithIndent(indent string) *TextWriter {
+	wr.indent = indent
+	return wr
+}
+
+// Indent returns the prefix for continuation lines of multi-line messages.
+func (wr *TextWriter) Indent() string {
+	return wr.indent
+}
+
+// FormatMultiline formats a multi-line message body, either as an indented block
+// or as a single line with escaped newlines and backslashes. Trailing newlines are removed.
+func (wr *TextWriter) FormatMultiline(body string) string {
+	body = strings.TrimRight(body, "\r\n")
+	if wr.escapeNewlines {
+		return strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(body)
+	}
+	if !strings.ContainsAny(body, "\r\n") {
+		return body
+	}
+	body = strings.Replace(body, "\r\n", "\n", -1)
+	return strings.Replace(body, "\n", "\n"+wr.indent, -1)
+}
+
 // Output returns the output.
 func (wr *TextWriter) Output() io.Writer {
 	return wr.output
@@ -276,12 +321,21 @@ func (wr *TextWriter) write(output io.Writer, e Event) error {
 		}
 	}
 
+	bodyStart := buf.Len()
 	if typed, isTyped := e.(TextWritable); isTyped {
 		typed.WriteText(wr, buf)
 	} else if typed, isTyped := e.(fmt.Stringer); isTyped {
 		buf.WriteString(typed.String())
 	}
 
+	// keep multi-line bodies (e.g. stack traces) attached to their event line.
+	// escaped bodies with backslashes are formatted too, so their backslashes are escaped.
+	if body := buf.Bytes()[bodyStart:]; bytes.ContainsAny(body, "\r\n") || (wr.escapeNewlines && bytes.IndexByte(body, '\\') >= 0) {
+		formatted := wr.FormatMultiline(string(body))
+		buf.Truncate(bodyStart)
+		buf.WriteString(formatted)
+	}
+
 	buf.WriteRune(RuneNewline)
 	_, err := buf.WriteTo(output)
 	return err
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
 )
 
 func TestLogWriterWrite(t *testing.T) {
@@ -79,3 +80,45 @@ func TestWriterLabels(t *testing.T) {
 	writer.WriteError(Messagef(Error, "test %s", "string").WithLabel("foo", "bar").WithLabel("moo", "boo"))
 	assert.True(strings.HasPrefix(buffer.String(), "[error] test string"))
 }
+
+func TestTextWriterMultiline(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	writer := NewTextWriter(buffer).WithShowTimestamp(false).WithShowHeadings(false).WithUseColor(false)
+	writer.Write(Messagef(Info, "first\nsecond\r\nthird\n"))
+	assert.Equal("[info] first\n\tsecond\n\tthird\n", buffer.String())
+
+	buffer.Reset()
+	writer.WithEscapeNewlines(true).Write(Messagef(Info, "first\nsecond\n"))
+	assert.Equal("[info] first\\nsecond\n", buffer.String())
+
+	// literal backslashes are escaped, so they can't be confused with escaped newlines.
+	buffer.Reset()
+	writer.Write(Messagef(Info, `C:\new\table`+"\nsecond"))
+	assert.Equal(`[info] C:\\new\\table\nsecond`+"\n", buffer.String())
+
+	buffer.Reset()
+	writer.Write(Messagef(Info, `a literal \n`))
+	assert.Equal(`[info] a literal \\n`+"\n", buffer.String())
+}
+
+func TestTextWriterErrorStack(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	writer := NewTextWriter(buffer).WithShowTimestamp(false).WithShowHeadings(false).WithUseColor(false)
+	writer.WriteError(NewErrorEvent(Error, exception.New("only a test")))
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	assert.True(len(lines) > 1)
+	assert.True(strings.HasPrefix(lines[0], "[error] only a test"))
+	for _, line := range lines[1:] {
+		assert.True(strings.HasPrefix(line, DefaultTextWriterIndent), line)
+	}
+
+	buffer.Reset()
+	writer.WithEscapeNewlines(true).WriteError(NewErrorEvent(Error, exception.New("only a test")))
+	assert.Equal(1, strings.Count(buffer.String(), "\n"))
+	assert.Contains(buffer.String(), `\n`)
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -38,6 +39,7 @@ type TimedEvent struct {
 
 	message string
 	elapsed time.Duration
+	fields  Values
 }
 
 // WithHeadings sets the headings.
@@ -92,6 +94,20 @@ func (e TimedEvent) Elapsed() time.Duration {
 	return e.elapsed
 }
 
+// WithField adds a field to the event output.
+func (e *TimedEvent) WithField(key string, value Any) *TimedEvent {
+	if e.fields == nil {
+		e.fields = Values{}
+	}
+	e.fields[key] = value
+	return e
+}
+
+// Fields returns the additional output fields.
+func (e TimedEvent) Fields() Values {
+	return e.fields
+}
+
 // String implements fmt.Stringer
 func (e TimedEvent) String() string {
 	return fmt.Sprintf("%s (%v)", e.message, e.elapsed)
@@ -100,12 +116,32 @@ func (e TimedEvent) String() string {
 // WriteText implements TextWritable.
 func (e TimedEvent) WriteText(tf TextFormatter, buf *bytes.Buffer) {
 	buf.WriteString(e.String())
+	if len(e.fields) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(e.fields))
+	for key := range e.fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buf.WriteRune(RuneSpace)
+		buf.WriteString(key)
+		buf.WriteRune('=')
+		buf.WriteString(LogfmtValue(e.fields[key]))
+	}
 }
 
 // WriteJSON implements JSONWritable.
 func (e TimedEvent) WriteJSON() JSONObj {
-	return JSONObj{
+	output := JSONObj{
 		JSONFieldMessage: e.message,
 		JSONFieldElapsed: Milliseconds(e.elapsed),
 	}
+	for key, value := range e.fields {
+		if _, isReserved := output[key]; !isReserved {
+			output[key] = value
+		}
+	}
+	return output
 }
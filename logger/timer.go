@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// NewTimer returns a new timer for a named operation that triggers events on a given log.
+// The timer starts immediately; call `Stop` to trigger the timed event.
+// `Timing` isn't one of the default flags, so enable it (i.e. `log.Enable(logger.Timing)`) to write timed events.
+func NewTimer(log Triggerable, name string) *Timer {
+	return &Timer{
+		log:     log,
+		name:    name,
+		flag:    Timing,
+		started: time.Now().UTC(),
+	}
+}
+
+// TimeAction runs an action and triggers a timed event for it on a given log.
+// If the action returns an error, it is added to the event as the `err` field and returned.
+func TimeAction(log Triggerable, name string, action func() error) error {
+	timer := NewTimer(log, name)
+	err := action()
+	if err != nil {
+		timer.WithField(JSONFieldErr, err.Error())
+	}
+	timer.Stop()
+	return err
+}
+
+// Timer measures a named operation and triggers a timed event when stopped.
+type Timer struct {
+	sync.Mutex
+
+	log     Triggerable
+	name    string
+	flag    Flag
+	started time.Time
+	fields  Values
+	stopped bool
+	elapsed time.Duration
+}
+
+// WithFlag sets the flag of the timed event, it defaults to `Timing`.
+func (t *Timer) WithFlag(flag Flag) *Timer {
+	t.flag = flag
+	return t
+}
+
+// Flag returns the flag of the timed event.
+func (t *Timer) Flag() Flag {
+	return t.flag
+}
+
+// WithField adds a field to the timed event.
+func (t *Timer) WithField(key string, value Any) *Timer {
+	t.Lock()
+	defer t.Unlock()
+	if t.fields == nil {
+		t.fields = Values{}
+	}
+	t.fields[key] = value
+	return t
+}
+
+// Name returns the operation name.
+func (t *Timer) Name() string {
+	return t.name
+}
+
+// Started returns the time the timer started.
+func (t *Timer) Started() time.Time {
+	return t.started
+}
+
+// Elapsed returns the time elapsed since the timer started, or until it was stopped.
+func (t *Timer) Elapsed() time.Duration {
+	t.Lock()
+	defer t.Unlock()
+	if t.stopped {
+		return t.elapsed
+	}
+	return time.Now().UTC().Sub(t.started)
+}
+
+// Stop triggers the timed event and returns the elapsed time.
+// Only the first call triggers an event; it is safe to `defer timer.Stop()`.
+func (t *Timer) Stop() time.Duration {
+	elapsed := time.Now().UTC().Sub(t.started)
+
+	t.Lock()
+	if t.stopped {
+		t.Unlock()
+		return t.elapsed
+	}
+	t.stopped = true
+	t.elapsed = elapsed
+	e := Timedf(t.flag, elapsed, "%s", t.name)
+	for key, value := range t.fields {
+		e.WithField(key, value)
+	}
+	t.Unlock()
+
+	if t.log != nil {
+		t.log.Trigger(e)
+	}
+	return elapsed
+}
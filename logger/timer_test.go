@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLoggerTimer(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := New().WithFlags(AllFlags()).WithWriter(NewTextWriter(buffer).WithUseColor(false).WithShowTimestamp(false))
+	defer log.Close()
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	var received *TimedEvent
+	log.Listen(Timing, "test", NewTimedEventListener(func(te *TimedEvent) {
+		defer wg.Done()
+		received = te
+	}))
+
+	timer := log.Timer("rebuild_cache").WithField("entries", 3)
+	elapsed := timer.Stop()
+	assert.Equal(elapsed, timer.Stop(), "stop should only trigger once")
+	wg.Wait()
+	log.Drain()
+
+	assert.NotNil(received)
+	assert.Equal(Timing, received.Flag())
+	assert.Equal("rebuild_cache", received.Message())
+	assert.Equal(elapsed, received.Elapsed())
+	assert.Equal(3, received.Fields()["entries"])
+	assert.Equal(3, received.WriteJSON()["entries"])
+	assert.Contains(buffer.String(), "rebuild_cache")
+	assert.Contains(buffer.String(), "entries=3")
+	assert.Equal(1, strings.Count(buffer.String(), "rebuild_cache"))
+}
+
+func TestLoggerTime(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	log := New().WithFlags(AllFlags()).WithWriter(NewJSONWriter(buffer))
+	defer log.Close()
+
+	var ran bool
+	assert.Nil(log.SubContext("cache").Time("warm", func() error {
+		ran = true
+		return nil
+	}))
+	assert.True(ran)
+
+	err := log.Time("rebuild", func() error { return fmt.Errorf("only a test") })
+	assert.Equal("only a test", err.Error())
+	log.Drain()
+
+	assert.Contains(buffer.String(), `"message":"warm"`)
+	assert.Contains(buffer.String(), `"cache"`)
+	assert.Contains(buffer.String(), `"err":"only a test"`)
+}
+
+func TestTimerWithFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	timer := NewTimer(nil, "test").WithFlag(Debug)
+	assert.Equal(Debug, timer.Flag())
+	assert.Equal("test", timer.Name())
+	assert.False(timer.Started().IsZero())
+	assert.NotZero(timer.Stop())
+}
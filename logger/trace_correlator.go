@@ -0,0 +1,26 @@
+package logger
+
+import "context"
+
+// TraceCorrelator extracts trace correlation ids from a context.
+// It lets the logger stay free of a specific tracing implementation;
+// see `stats/logtrace` for an opentracing backed correlator.
+type TraceCorrelator interface {
+	TraceIDs(context.Context) (traceID, spanID string)
+}
+
+// InjectTrace sets the trace correlation ids found in a context on an event if it supports them.
+func InjectTrace(ctx context.Context, correlator TraceCorrelator, e Event) {
+	if ctx == nil || correlator == nil {
+		return
+	}
+	typed, isTyped := e.(EventTrace)
+	if !isTyped {
+		return
+	}
+	traceID, spanID := correlator.TraceIDs(ctx)
+	if len(traceID) == 0 && len(spanID) == 0 {
+		return
+	}
+	typed.SetTrace(traceID, spanID)
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type testTraceKey struct{}
+
+type testCorrelator struct{}
+
+func (tc testCorrelator) TraceIDs(ctx context.Context) (traceID, spanID string) {
+	if value, ok := ctx.Value(testTraceKey{}).(string); ok {
+		return value, value + "-span"
+	}
+	return
+}
+
+func TestLoggerTriggerContext(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	log := New().WithFlags(AllFlags()).WithWriter(NewJSONWriter(output)).WithTraceCorrelator(testCorrelator{})
+	defer log.Close()
+
+	ctx := context.WithValue(context.Background(), testTraceKey{}, "1234")
+	log.SyncTriggerContext(ctx, Messagef(Info, "test"))
+
+	var verify JSONObj
+	assert.Nil(json.Unmarshal(output.Bytes(), &verify))
+	assert.Equal("1234", verify[JSONFieldTraceID])
+	assert.Equal("1234-span", verify[JSONFieldSpanID])
+}
+
+func TestLoggerTriggerContextWithoutTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	output := bytes.NewBuffer(nil)
+	log := New().WithFlags(AllFlags()).WithWriter(NewJSONWriter(output)).WithTraceCorrelator(testCorrelator{})
+	defer log.Close()
+
+	log.SyncTriggerContext(context.Background(), Messagef(Info, "test"))
+
+	var verify JSONObj
+	assert.Nil(json.Unmarshal(output.Bytes(), &verify))
+	_, hasTraceID := verify[JSONFieldTraceID]
+	assert.False(hasTraceID)
+}
+
+func TestInjectTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	e := Messagef(Info, "test")
+	InjectTrace(context.WithValue(context.Background(), testTraceKey{}, "foo"), testCorrelator{}, e)
+	assert.Equal("foo", e.TraceID())
+	assert.Equal("foo-span", e.SpanID())
+
+	unset := Messagef(Info, "test")
+	InjectTrace(context.WithValue(context.Background(), testTraceKey{}, "foo"), nil, unset)
+	assert.Empty(unset.TraceID())
+}
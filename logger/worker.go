@@ -2,6 +2,13 @@ package logger
 
 import (
 	"sync"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ErrListenerPanic is the class of error events written when a listener panics.
+	ErrListenerPanic exception.Class = "logger: listener panic"
 )
 
 // NewWorker returns a new worker.
@@ -14,9 +21,12 @@ func NewWorker(parent *Logger, listener Listener, queueDepth int) *Worker {
 }
 
 // Worker is an agent that processes a listener.
+// Events are processed one at a time, in the order they were queued.
 type Worker struct {
 	sync.Mutex
 	Parent   *Logger
+	Flag     Flag
+	Name     string
 	Listener Listener
 	Abort    chan struct{}
 	Aborted  chan struct{}
@@ -51,15 +61,14 @@ func (w *Worker) ProcessLoop() {
 }
 
 // Process calls the listener for an event.
+// If the parent logger recovers panics, a panic in the listener is
+// written as an error event and does not affect other listeners.
 func (w *Worker) Process(e Event) {
 	if w.Parent != nil && w.Parent.RecoversPanics() {
-		defer func() {
-			if r := recover(); r != nil {
-				if w.Parent != nil {
-					w.Parent.Write(Errorf(Fatal, "%+v", r))
-				}
-			}
-		}()
+		if err := exception.Try(func() error { w.Listener(e); return nil }); err != nil {
+			w.Parent.Write(NewErrorEvent(Fatal, exception.New(ErrListenerPanic).WithMessagef("flag: %s, listener: %s", w.Flag, w.Name).WithInner(err)))
+		}
+		return
 	}
 	w.Listener(e)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/blend/go-sdk/webutil"
@@ -75,3 +76,37 @@ func JSONWriteHTTPResponse(req *http.Request, statusCode, contentLength int, con
 		JSONFieldElapsed:  Milliseconds(elapsed),
 	}
 }
+
+// JSONWriteHTTPRequestDetails adds the route, user agent, referrer and allow-listed request headers to a json object.
+// Empty values are omitted.
+func JSONWriteHTTPRequestDetails(obj JSONObj, req *http.Request, route string, headerAllowList []string) JSONObj {
+	if len(route) > 0 {
+		obj["route"] = route
+	}
+	if userAgent := req.UserAgent(); len(userAgent) > 0 {
+		obj["userAgent"] = userAgent
+	}
+	if referrer := req.Referer(); len(referrer) > 0 {
+		obj["referrer"] = referrer
+	}
+	if headers := HTTPHeaderValues(req, headerAllowList); len(headers) > 0 {
+		obj["headers"] = headers
+	}
+	return obj
+}
+
+// HTTPHeaderValues returns the values of the allow-listed headers present on a request.
+// Keys are canonicalized and multiple values are joined with `, `.
+func HTTPHeaderValues(req *http.Request, allowList []string) map[string]string {
+	if req == nil || len(allowList) == 0 {
+		return nil
+	}
+	output := map[string]string{}
+	for _, header := range allowList {
+		key := http.CanonicalHeaderKey(header)
+		if values, hasValues := req.Header[key]; hasValues && len(values) > 0 {
+			output[key] = strings.Join(values, ", ")
+		}
+	}
+	return output
+}
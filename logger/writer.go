@@ -14,6 +14,8 @@ const (
 	OutputFormatJSON OutputFormat = "json"
 	// OutputFormatText is an output format.
 	OutputFormatText OutputFormat = "text"
+	// OutputFormatLogfmt is an output format.
+	OutputFormatLogfmt OutputFormat = "logfmt"
 	// Sometime in the future ...
 	// OutputFormatProtobuf = "protobuf"
 )
@@ -26,6 +28,8 @@ func NewWriter(format OutputFormat) Writer {
 		return NewJSONWriterFromEnv()
 	case OutputFormatText:
 		return NewTextWriterFromEnv()
+	case OutputFormatLogfmt:
+		return NewLogfmtWriterFromEnv()
 	}
 
 	panic("invalid writer output format")
@@ -0,0 +1,180 @@
+package names
+
+import (
+	"bufio"
+	"encoding/csv"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ErrEmptyName is returned for blank lines or empty name columns.
+	ErrEmptyName exception.Class = "names: empty name"
+	// ErrColumnOutOfRange is returned for csv records without the name column.
+	ErrColumnOutOfRange exception.Class = "names: csv column out of range"
+	// ErrReadFailed is returned when the input can't be read; it is the last result.
+	ErrReadFailed exception.Class = "names: read failed"
+)
+
+const (
+	// DefaultBatchBufferSize is the default results channel buffer size.
+	DefaultBatchBufferSize = 1024
+	// DefaultBatchMaxLineSize is the default maximum line size for newline delimited input.
+	DefaultBatchMaxLineSize = 1 << 20
+)
+
+// BatchOptions are options for `ParseAll`.
+type BatchOptions struct {
+	// Parser is the parser to use; it defaults to the default parser.
+	Parser *Parser
+	// Workers is the number of parsing goroutines; it defaults to the number of cpus.
+	Workers int
+	// BufferSize is the results channel buffer size.
+	BufferSize int
+	// CSV is if the input is csv rather than newline delimited.
+	CSV bool
+	// Column is the zero based csv column of the name.
+	Column int
+	// SkipHeader is if the first line or record is a header and should be skipped.
+	SkipHeader bool
+	// MaxLineSize is the maximum line size for newline delimited input.
+	MaxLineSize int
+}
+
+// GetParser returns the parser or a default.
+func (bo BatchOptions) GetParser() *Parser {
+	if bo.Parser != nil {
+		return bo.Parser
+	}
+	return defaultParser
+}
+
+// GetWorkers returns the number of workers or a default.
+func (bo BatchOptions) GetWorkers() int {
+	if bo.Workers > 0 {
+		return bo.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// GetBufferSize returns the buffer size or a default.
+func (bo BatchOptions) GetBufferSize() int {
+	if bo.BufferSize > 0 {
+		return bo.BufferSize
+	}
+	return DefaultBatchBufferSize
+}
+
+// GetMaxLineSize returns the max line size or a default.
+func (bo BatchOptions) GetMaxLineSize() int {
+	if bo.MaxLineSize > 0 {
+		return bo.MaxLineSize
+	}
+	return DefaultBatchMaxLineSize
+}
+
+// BatchResult is the result of parsing a line.
+type BatchResult struct {
+	// Line is the one based line number, or csv record number.
+	Line int
+	// Input is the raw name.
+	Input string
+	// Name is the parsed name, if there was no error.
+	Name *Name
+	// Err is the error for the line, if any.
+	Err error
+}
+
+// ParseAll parses newline or csv delimited names concurrently, sending a result per line on the returned channel.
+// Results are not in input order; use `BatchResult.Line` to correlate them. Errors are per line,
+// except a read error, which is the last result. The channel is closed once the input is exhausted,
+// and must be drained.
+func ParseAll(r io.Reader, opts BatchOptions) <-chan BatchResult {
+	lines := make(chan BatchResult, opts.GetBufferSize())
+	results := make(chan BatchResult, opts.GetBufferSize())
+
+	parser := opts.GetParser()
+	wg := sync.WaitGroup{}
+	for x := 0; x < opts.GetWorkers(); x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for line := range lines {
+				if line.Err == nil {
+					if strings.TrimSpace(line.Input) == "" {
+						line.Err = exception.New(ErrEmptyName).WithMessagef("line: %d", line.Line)
+					} else {
+						line.Name = parser.Parse(line.Input)
+					}
+				}
+				results <- line
+			}
+		}()
+	}
+
+	go func() {
+		readErr := readBatch(r, opts, lines)
+		close(lines)
+		wg.Wait()
+		if readErr != nil {
+			results <- *readErr
+		}
+		close(results)
+	}()
+	return results
+}
+
+// readBatch sends each line or record to the lines channel, returning a result for a read error if there is one.
+func readBatch(r io.Reader, opts BatchOptions, lines chan BatchResult) *BatchResult {
+	if opts.CSV {
+		return readBatchCSV(r, opts, lines)
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), opts.GetMaxLineSize())
+	var line int
+	for scanner.Scan() {
+		line++
+		if line == 1 && opts.SkipHeader {
+			continue
+		}
+		lines <- BatchResult{Line: line, Input: scanner.Text()}
+	}
+	if err := scanner.Err(); err != nil {
+		return &BatchResult{Line: line + 1, Err: exception.New(ErrReadFailed).WithInner(err)}
+	}
+	return nil
+}
+
+func readBatchCSV(r io.Reader, opts BatchOptions, lines chan BatchResult) *BatchResult {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var line int
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		line++
+		if err != nil {
+			if _, isParseError := err.(*csv.ParseError); !isParseError {
+				return &BatchResult{Line: line, Err: exception.New(ErrReadFailed).WithInner(err)}
+			}
+			lines <- BatchResult{Line: line, Err: exception.New(err)}
+			continue
+		}
+		if line == 1 && opts.SkipHeader {
+			continue
+		}
+		if opts.Column < 0 || opts.Column >= len(record) {
+			lines <- BatchResult{Line: line, Err: exception.New(ErrColumnOutOfRange).WithMessagef("line: %d, column: %d", line, opts.Column)}
+			continue
+		}
+		lines <- BatchResult{Line: line, Input: record[opts.Column]}
+	}
+}
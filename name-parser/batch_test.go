@@ -0,0 +1,62 @@
+package names
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func collectBatch(results <-chan BatchResult) []BatchResult {
+	var output []BatchResult
+	for result := range results {
+		output = append(output, result)
+	}
+	sort.Slice(output, func(i, j int) bool { return output[i].Line < output[j].Line })
+	return output
+}
+
+func TestParseAll(t *testing.T) {
+	assert := assert.New(t)
+
+	results := collectBatch(ParseAll(strings.NewReader("John Smith\n\nSmith, Jane A.\nMr. Bob Jones Jr\n"), BatchOptions{Workers: 2}))
+	assert.Len(results, 4)
+	assert.Equal(&Name{FirstName: "John", LastName: "Smith"}, results[0].Name)
+	assert.Equal(2, results[1].Line)
+	assert.Nil(results[1].Name)
+	assert.True(exception.Is(results[1].Err, ErrEmptyName))
+	assert.Equal(&Name{FirstName: "Jane", MiddleName: "A.", LastName: "Smith"}, results[2].Name)
+	assert.Equal("Mr. Bob Jones Jr", results[3].Input)
+	assert.Equal(&Name{Salutation: "Mr.", FirstName: "Bob", LastName: "Jones", Suffix: "Jr"}, results[3].Name)
+}
+
+func TestParseAllCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	input := "id,name\n1,John Smith\n2,\"Smith, Jane\"\n3\n4,\"bad\"quote\"\n"
+	results := collectBatch(ParseAll(strings.NewReader(input), BatchOptions{CSV: true, Column: 1, SkipHeader: true}))
+	assert.Len(results, 4)
+	assert.Equal(2, results[0].Line)
+	assert.Equal(&Name{FirstName: "John", LastName: "Smith"}, results[0].Name)
+	assert.Equal(&Name{FirstName: "Jane", LastName: "Smith"}, results[1].Name)
+	assert.True(exception.Is(results[2].Err, ErrColumnOutOfRange))
+	assert.NotNil(results[3].Err)
+	assert.Nil(results[3].Name)
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("disk on fire")
+}
+
+func TestParseAllReadError(t *testing.T) {
+	assert := assert.New(t)
+
+	results := collectBatch(ParseAll(failingReader{}, BatchOptions{}))
+	assert.Len(results, 1)
+	assert.True(exception.Is(results[0].Err, ErrReadFailed))
+}
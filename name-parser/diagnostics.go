@@ -0,0 +1,86 @@
+package names
+
+// AmbiguityReason is why a word could have been parsed differently.
+type AmbiguityReason string
+
+// Ambiguity reasons.
+const (
+	AmbiguityMiddleNameOrParticle   AmbiguityReason = "word could be a middle name or a compound surname particle"
+	AmbiguityGivenNameOrParticle    AmbiguityReason = "word could be part of the given name or a compound surname particle"
+	AmbiguityFirstNameOrMiddleName  AmbiguityReason = "word could be part of the first name or a middle name"
+	AmbiguityDoubleSurname          AmbiguityReason = "word could be a paternal surname or a middle name"
+	AmbiguityFirstNameOrLastName    AmbiguityReason = "word could be a first name or a last name"
+	AmbiguitySuffixOrInitial        AmbiguityReason = "word could be a suffix or an initial"
+	AmbiguityInitialAsFirstName     AmbiguityReason = "initial is used as the first name"
+	AmbiguityUnclosedNickname       AmbiguityReason = "nickname delimiter is not closed"
+	AmbiguityUnrecognizedSegment    AmbiguityReason = "comma delimited segment could be a given name or an unrecognized suffix"
+	AmbiguityUnrecognizedSalutation AmbiguityReason = "word could be an unrecognized salutation or a first name"
+)
+
+// AmbiguityPenalties are how much each ambiguity reduces the confidence of a parse.
+var AmbiguityPenalties = map[AmbiguityReason]float64{
+	AmbiguityMiddleNameOrParticle:   0.2,
+	AmbiguityGivenNameOrParticle:    0.2,
+	AmbiguityFirstNameOrMiddleName:  0.1,
+	AmbiguityDoubleSurname:          0.2,
+	AmbiguityFirstNameOrLastName:    0.4,
+	AmbiguitySuffixOrInitial:        0.2,
+	AmbiguityInitialAsFirstName:     0.3,
+	AmbiguityUnclosedNickname:       0.3,
+	AmbiguityUnrecognizedSegment:    0.4,
+	AmbiguityUnrecognizedSalutation: 0.2,
+}
+
+// Ambiguity is a word the parser could have interpreted differently.
+type Ambiguity struct {
+	Word   string
+	Reason AmbiguityReason
+}
+
+// String returns a description of the ambiguity.
+func (a Ambiguity) String() string {
+	return "\"" + a.Word + "\": " + string(a.Reason)
+}
+
+// Result is a parsed name with diagnostics.
+type Result struct {
+	Name *Name
+	// Confidence is the confidence in the parse, from 0 (no confidence) to 1.
+	Confidence float64
+	// Ambiguities are the words the parser could have interpreted differently.
+	Ambiguities []Ambiguity
+}
+
+// IsAmbiguous returns if there are any ambiguities.
+func (r *Result) IsAmbiguous() bool {
+	return len(r.Ambiguities) > 0
+}
+
+// Analyze parses a string into a name with the default parser, returning a confidence score
+// and the ambiguities found; see `Parse`.
+func Analyze(input string) *Result {
+	return defaultParser.Analyze(input)
+}
+
+// ambiguous adds an ambiguity; it is safe to call on a nil result.
+func (r *Result) ambiguous(word string, reason AmbiguityReason) {
+	if r == nil {
+		return
+	}
+	r.Ambiguities = append(r.Ambiguities, Ambiguity{Word: word, Reason: reason})
+}
+
+// score sets the confidence from the name and ambiguities.
+func (r *Result) score() {
+	if r.Name == nil || (r.Name.FirstName == "" && r.Name.LastName == "") {
+		r.Confidence = 0
+		return
+	}
+	r.Confidence = 1
+	for _, ambiguity := range r.Ambiguities {
+		r.Confidence -= AmbiguityPenalties[ambiguity.Reason]
+	}
+	if r.Confidence < 0 {
+		r.Confidence = 0
+	}
+}
@@ -0,0 +1,95 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Entity is a parsed string that is either a person's name or an organization.
+type Entity struct {
+	// IsPerson is if the string is a person's name.
+	IsPerson bool
+	// Name is the parsed name if the string is a person's name, and nil otherwise.
+	Name *Name
+	// Organization is the trimmed string if it is an organization, and empty otherwise.
+	Organization string
+}
+
+// String returns the string representation of the entity.
+func (e Entity) String() string {
+	if e.IsPerson && e.Name != nil {
+		return e.Name.String()
+	}
+	return e.Organization
+}
+
+// ParseEntity parses a string with the default parser into either a person's name or an organization,
+// e.g. "Acme Holdings LLC" or "The Smith Family Trust" are organizations and are not parsed as names.
+func ParseEntity(input string) *Entity {
+	return defaultParser.ParseEntity(input)
+}
+
+// IsOrganization returns if a string is an organization rather than a person's name with the default parser.
+func IsOrganization(input string) bool {
+	return defaultParser.IsOrganization(input)
+}
+
+// ParseEntity parses a string into either a person's name or an organization; see the package level `ParseEntity`.
+func (p *Parser) ParseEntity(input string) *Entity {
+	if p.IsOrganization(input) {
+		return &Entity{Organization: strings.Join(strings.Fields(input), " ")}
+	}
+	return &Entity{IsPerson: true, Name: p.Parse(input)}
+}
+
+// IsOrganization returns if a string is an organization rather than a person's name.
+// A string is an organization if it ends with an organization keyword after other words, e.g. "Acme LLC" (but not "Mark Co"),
+// starts with "The" followed by other words (but not a salutation like "The Honorable"), contains an ampersand,
+// or contains a word with digits that isn't an ordinal, e.g. "7-Eleven" (but not "John Smith 3rd").
+func (p *Parser) IsOrganization(input string) bool {
+	words := strings.FieldsFunc(input, func(c rune) bool {
+		return unicode.IsSpace(c) || c == ','
+	})
+	if _, count := p.processSalutations(words); count > 0 {
+		words = words[count:]
+	} else if len(words) > 1 && cleanString(words[0]) == "the" {
+		return true
+	}
+	if len(words) > 1 && p.organizationKeywords[cleanString(words[len(words)-1])] {
+		return true
+	}
+	for _, word := range words {
+		if word == "&" || (hasDigit(word) && !isOrdinal(word)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDigit(input string) bool {
+	for _, c := range input {
+		if unicode.IsDigit(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOrdinal returns if a word is a number with an ordinal suffix, e.g. "3rd" or "4th.".
+func isOrdinal(input string) bool {
+	cleaned := cleanString(input)
+	if len(cleaned) < 3 {
+		return false
+	}
+	switch cleaned[len(cleaned)-2:] {
+	case "st", "nd", "rd", "th":
+	default:
+		return false
+	}
+	for _, c := range cleaned[:len(cleaned)-2] {
+		if !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
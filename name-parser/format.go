@@ -0,0 +1,120 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Punctuation is the punctuation used to format initials.
+type Punctuation struct {
+	// Period follows each initial, e.g. ".".
+	Period string
+	// Separator separates initials, e.g. " ".
+	Separator string
+}
+
+// Default punctuation.
+var (
+	// DefaultInitialsPunctuation is the default punctuation for `Name.Initials`, e.g. "J.A.S.".
+	DefaultInitialsPunctuation = Punctuation{Period: "."}
+	// DefaultAbbreviatedPunctuation is the default punctuation for `Name.Abbreviated`, e.g. "J. A. Smith".
+	DefaultAbbreviatedPunctuation = Punctuation{Period: ".", Separator: " "}
+)
+
+// FirstLast returns the first and last name, e.g. "John Smith".
+func (n Name) FirstLast() string {
+	return joinNonEmpty(" ", n.FirstName, n.LastName)
+}
+
+// Sorted returns the name in sorted order, e.g. "Smith, John A." or "Smith, John A., Jr".
+// The salutation and nickname are omitted.
+func (n Name) Sorted() string {
+	return joinNonEmpty(", ", n.LastName, joinNonEmpty(" ", n.FirstName, n.MiddleName), n.Suffix)
+}
+
+// Initials returns the initials of the first, middle and last names, e.g. "J.A.S.".
+// The punctuation defaults to `DefaultInitialsPunctuation`.
+// Only the first capitalized word of the last name is used, e.g. "L.B." for "Ludwig van Beethoven".
+func (n Name) Initials(punctuation ...Punctuation) string {
+	p := DefaultInitialsPunctuation
+	if len(punctuation) > 0 {
+		p = punctuation[0]
+	}
+	initials := append(wordInitials(n.FirstName), wordInitials(n.MiddleName)...)
+	if initial := lastNameInitial(n.LastName); initial != "" {
+		initials = append(initials, initial)
+	}
+	return formatInitials(initials, p)
+}
+
+// Abbreviated returns the initials of the first and middle names followed by the last name, e.g. "J. A. Smith".
+// The punctuation defaults to `DefaultAbbreviatedPunctuation`. If there is no last name, the first name is
+// returned as is.
+func (n Name) Abbreviated(punctuation ...Punctuation) string {
+	if n.LastName == "" {
+		return n.FirstName
+	}
+	p := DefaultAbbreviatedPunctuation
+	if len(punctuation) > 0 {
+		p = punctuation[0]
+	}
+	initials := formatInitials(append(wordInitials(n.FirstName), wordInitials(n.MiddleName)...), p)
+	if initials == "" {
+		return n.LastName
+	}
+	return initials + " " + n.LastName
+}
+
+// wordInitials returns the uppercase first letter of each word.
+func wordInitials(input string) []string {
+	var initials []string
+	for _, word := range strings.Fields(input) {
+		if initial, ok := firstLetter(word); ok {
+			initials = append(initials, string(unicode.ToUpper(initial)))
+		}
+	}
+	return initials
+}
+
+// lastNameInitial returns the first letter of the first capitalized word of a last name,
+// or of the first word if none are capitalized.
+func lastNameInitial(input string) string {
+	words := strings.Fields(input)
+	for _, word := range words {
+		if initial, ok := firstLetter(word); ok && unicode.IsUpper(initial) {
+			return string(initial)
+		}
+	}
+	if initials := wordInitials(input); len(initials) > 0 {
+		return initials[0]
+	}
+	return ""
+}
+
+func firstLetter(word string) (rune, bool) {
+	for _, c := range word {
+		if unicode.IsLetter(c) {
+			return c, true
+		}
+	}
+	return utf8.RuneError, false
+}
+
+func formatInitials(initials []string, punctuation Punctuation) string {
+	output := make([]string, 0, len(initials))
+	for _, initial := range initials {
+		output = append(output, initial+punctuation.Period)
+	}
+	return strings.Join(output, punctuation.Separator)
+}
+
+func joinNonEmpty(separator string, values ...string) string {
+	output := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			output = append(output, value)
+		}
+	}
+	return strings.Join(output, separator)
+}
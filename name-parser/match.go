@@ -0,0 +1,168 @@
+package names
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultMatchThreshold is the default score at or above which two names are a match.
+const DefaultMatchThreshold = 0.8
+
+// Match weights.
+const (
+	matchWeightLast  = 0.55
+	matchWeightFirst = 0.45
+
+	// matchPenaltyTransposed is applied when names only match with the first and last names transposed.
+	matchPenaltyTransposed = 0.9
+	// matchPenaltySuffix is applied when both names have different suffixes, e.g. "Jr" and "Sr".
+	matchPenaltySuffix = 0.5
+)
+
+// MatchResult is the result of comparing two names.
+type MatchResult struct {
+	// Score is the likelihood the names refer to the same person, from 0 to 1.
+	Score float64
+	// IsMatch is if the score is at or above `DefaultMatchThreshold`.
+	IsMatch bool
+	// Transposed is if the names matched with the first and last names transposed, e.g. "Smith John".
+	Transposed bool
+}
+
+// Match scores if two names likely refer to the same person with the default parser's nicknames.
+// Nicknames (e.g. "Bob" and "Robert"), initials (e.g. "J." and "John") and transposed
+// first and last names are taken into account.
+func Match(a, b *Name) MatchResult {
+	return defaultParser.Match(a, b)
+}
+
+// Match scores if two names likely refer to the same person; see the package level `Match`.
+func (p *Parser) Match(a, b *Name) MatchResult {
+	if a == nil || b == nil {
+		return MatchResult{}
+	}
+
+	a, b = initialAsFirstName(a), initialAsFirstName(b)
+	result := MatchResult{Score: p.matchScore(a, b.FirstName, b.MiddleName, b.LastName, b.Nickname)}
+	if transposed := p.matchScore(a, b.LastName, b.MiddleName, b.FirstName, b.Nickname) * matchPenaltyTransposed; transposed > result.Score {
+		result.Score = transposed
+		result.Transposed = true
+	}
+	if a.Suffix != "" && b.Suffix != "" && cleanString(a.Suffix) != cleanString(b.Suffix) {
+		result.Score = result.Score * matchPenaltySuffix
+	}
+	result.IsMatch = result.Score >= DefaultMatchThreshold
+	return result
+}
+
+// initialAsFirstName returns a name with the first middle initial as the first name if it has no first name,
+// e.g. "J. Smith", which parses with "J." as a middle name.
+func initialAsFirstName(name *Name) *Name {
+	if name.FirstName != "" || name.MiddleName == "" {
+		return name
+	}
+	copied := *name
+	middleNames := strings.Fields(name.MiddleName)
+	copied.FirstName = middleNames[0]
+	copied.MiddleName = strings.Join(middleNames[1:], " ")
+	return &copied
+}
+
+func (p *Parser) matchScore(a *Name, firstName, middleName, lastName, nickname string) float64 {
+	if a.LastName == "" && lastName == "" {
+		return p.matchFirstName(a, firstName, nickname)
+	}
+	score := matchWeightLast*p.matchLastName(a.LastName, lastName) + matchWeightFirst*p.matchFirstName(a, firstName, nickname)
+	return score * matchMiddleName(a.MiddleName, middleName)
+}
+
+// matchLastName scores last names, where a shared part of a hyphenated or double surname is a partial match;
+// compound surname particles, e.g. the "de" in "de Vries", aren't parts.
+func (p *Parser) matchLastName(a, b string) float64 {
+	a, b = matchKey(a), matchKey(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+	for _, aPart := range splitNameParts(a) {
+		if p.compoundLastNames[aPart] {
+			continue
+		}
+		for _, bPart := range splitNameParts(b) {
+			if aPart == bPart {
+				return 0.8
+			}
+		}
+	}
+	return 0
+}
+
+// matchFirstName scores first names, including nicknames and initials.
+func (p *Parser) matchFirstName(a *Name, firstName, nickname string) float64 {
+	aFirst, bFirst := matchKey(a.FirstName), matchKey(firstName)
+	if aFirst == "" || bFirst == "" {
+		return 0
+	}
+	if aFirst == bFirst {
+		return 1
+	}
+	aWord, bWord := firstWord(aFirst), firstWord(bFirst)
+	if aWord == bWord {
+		return 0.9
+	}
+	if p.AreNicknames(aWord, bWord) ||
+		(a.Nickname != "" && p.AreNicknames(a.Nickname, bWord)) ||
+		(nickname != "" && p.AreNicknames(aWord, nickname)) {
+		return 0.9
+	}
+	if isInitialOf(aWord, bWord) || isInitialOf(bWord, aWord) {
+		return 0.7
+	}
+	return 0
+}
+
+// matchMiddleName returns the factor middle names apply to a score, where a missing middle name
+// is weak evidence against a match and conflicting middle names are strong evidence against a match.
+func matchMiddleName(a, b string) float64 {
+	a, b = matchKey(a), matchKey(b)
+	if a == "" && b == "" {
+		return 1
+	}
+	if a == "" || b == "" {
+		return 0.95
+	}
+	if a == b {
+		return 1
+	}
+	if isInitialOf(a, b) || isInitialOf(b, a) {
+		return 0.98
+	}
+	return 0.5
+}
+
+// isInitialOf returns if a word is a single letter initial of another word.
+func isInitialOf(initial, word string) bool {
+	initialRunes := []rune(initial)
+	wordRunes := []rune(word)
+	return len(initialRunes) == 1 && len(wordRunes) > 0 && initialRunes[0] == wordRunes[0]
+}
+
+// matchKey returns the lowercase, period-less form of a name with collapsed whitespace.
+func matchKey(input string) string {
+	return strings.Join(strings.Fields(cleanString(input)), " ")
+}
+
+func firstWord(input string) string {
+	if words := strings.Fields(input); len(words) > 0 {
+		return words[0]
+	}
+	return ""
+}
+
+func splitNameParts(input string) []string {
+	return strings.FieldsFunc(input, func(c rune) bool {
+		return unicode.IsSpace(c) || c == '-'
+	})
+}
@@ -3,26 +3,24 @@ package names
 import (
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-var validSuffixes = []string{
-	"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII", "XIII", "XIV", "XV", "XVI", "XVII", "XVIII", "XIX", "XX",
-	"Senior", "Junior", "Jr", "Sr",
-	"PhD", "APR", "RPh", "PE", "MD", "MA", "DMD", "CME",
-}
-
-var compoundLastNames = []string{
-	"vere", "von", "van", "de", "del", "della", "di", "da", "pietro",
-	"vanden", "du", "st.", "st", "la", "lo", "ter", "bin", "ibn",
-}
-
 // Name is a structured/parsed name.
 type Name struct {
 	Salutation string
 	FirstName  string
 	MiddleName string
 	LastName   string
-	Suffix     string
+	// Suffix is the suffixes, space separated, e.g. "Jr MD".
+	Suffix   string
+	Nickname string
+
+	// Raw is the input the name was parsed from, if the parser records spans.
+	Raw string
+	// Spans are the byte offsets of each word in the raw input and the field it was parsed as,
+	// in input order, if the parser records spans.
+	Spans []Span
 }
 
 // String returns the string representation of a name.
@@ -40,6 +38,13 @@ func (n Name) String() string {
 		fullName = fullName + n.FirstName
 	}
 
+	if n.Nickname != "" {
+		if fullName != "" {
+			fullName = fullName + " "
+		}
+		fullName = fullName + "\"" + n.Nickname + "\""
+	}
+
 	if n.MiddleName != "" {
 		if fullName != "" {
 			fullName = fullName + " "
@@ -63,66 +68,301 @@ func (n Name) String() string {
 	return fullName
 }
 
-// Parse parses a string into a name.
+// Parse parses a string into a name with the default parser.
+// Both the "First Middle Last" and "Last, First Middle" conventions are supported,
+// and suffixes may be separated by commas, e.g. "Smith, John, Jr." or "John Smith, Jr.".
+// Nicknames in parentheses or double quotes, e.g. `Robert "Bob" Smith`, are set as the nickname.
 func Parse(input string) *Name {
-	fullName := strings.TrimSpace(input)
+	return defaultParser.Parse(input)
+}
+
+// Parse parses a string into a name; see the package level `Parse`.
+func (p *Parser) Parse(input string) *Name {
+	return p.Analyze(input).Name
+}
 
-	rawNameParts := strings.Split(fullName, " ")
+// Analyze parses a string into a name with diagnostics; see the package level `Analyze`.
+func (p *Parser) Analyze(input string) *Result {
+	result := new(Result)
+	fullName, nickname := p.extractNicknames(strings.TrimSpace(input), result)
+
+	var name *Name
+	var fields []Field
+	if strings.Contains(fullName, ",") {
+		name, fields = p.parseCommaDelimited(fullName, result)
+	} else {
+		name, fields = p.parseWords(fullName, result)
+	}
+	name.Nickname = nickname
+	if p.spans {
+		name.Raw = input
+		name.Spans = spans(input, fields)
+	}
+
+	result.Name = name
+	result.score()
+	return result
+}
 
+// nicknameDelimiters are the opening and closing delimiters of nicknames.
+var nicknameDelimiters = [][2]rune{
+	{'(', ')'},
+	{'"', '"'},
+	{'“', '”'},
+}
+
+// extractNicknames removes delimited nicknames from a name, returning the remaining name and the nicknames.
+func (p *Parser) extractNicknames(input string, result *Result) (string, string) {
+	var nicknames []string
+	var output strings.Builder
+	runes := []rune(input)
+	for index := 0; index < len(runes); index++ {
+		closing, isOpening := nicknameClosing(runes[index])
+		if !isOpening {
+			output.WriteRune(runes[index])
+			continue
+		}
+		end := index + 1
+		for end < len(runes) && runes[end] != closing {
+			end++
+		}
+		if end == len(runes) {
+			result.ambiguous(string(runes[index]), AmbiguityUnclosedNickname)
+		}
+		var words []string
+		for _, word := range strings.Fields(string(runes[index+1 : end])) {
+			words = append(words, p.fixCase(word))
+		}
+		if len(words) > 0 {
+			nicknames = append(nicknames, strings.Join(words, " "))
+		}
+		output.WriteRune(' ')
+		index = end
+	}
+	return strings.Join(strings.Fields(output.String()), " "), strings.Join(nicknames, " ")
+}
+
+func nicknameClosing(c rune) (rune, bool) {
+	for _, delimiters := range nicknameDelimiters {
+		if delimiters[0] == c {
+			return delimiters[1], true
+		}
+	}
+	return 0, false
+}
+
+// parseCommaDelimited parses a name with commas, either "Last, First Middle"
+// or a name followed by comma delimited suffixes.
+// It returns the field of each word in the name, in order.
+func (p *Parser) parseCommaDelimited(fullName string, result *Result) (*Name, []Field) {
+	var segments []string
+	for _, segment := range strings.Split(fullName, ",") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	// trailing segments made up only of suffixes are suffixes.
+	var suffixes []string
+	for len(segments) > 1 {
+		segmentSuffixes, ok := p.processSuffixes(segments[len(segments)-1])
+		if !ok {
+			break
+		}
+		suffixes = append(segmentSuffixes, suffixes...)
+		segments = segments[:len(segments)-1]
+	}
+
+	var name *Name
+	var fields []Field
+	switch len(segments) {
+	case 0:
+		name = new(Name)
+	case 1:
+		name, fields = p.parseWords(segments[0], result)
+	default:
+		for _, segment := range segments[2:] {
+			result.ambiguous(segment, AmbiguityUnrecognizedSegment)
+		}
+		name, fields = p.parseLastFirst(segments[0], strings.Join(segments[1:], " "), result)
+	}
+	if len(suffixes) > 0 {
+		for range suffixes {
+			fields = append(fields, FieldSuffix)
+		}
+		if name.Suffix != "" {
+			suffixes = append([]string{name.Suffix}, suffixes...)
+		}
+		name.Suffix = strings.Join(suffixes, " ")
+	}
+	return name, fields
+}
+
+// parseLastFirst parses the "Last, First Middle" convention.
+// It returns the field of each word in the name, in order.
+func (p *Parser) parseLastFirst(last, given string, result *Result) (*Name, []Field) {
 	name := new(Name)
+	var fields []Field
+
+	var lastNames []string
+	for index, word := range strings.Fields(last) {
+		lastNames = append(lastNames, p.fixParticleCase(word, index == 0))
+		fields = append(fields, FieldLastName)
+	}
+	name.LastName = strings.Join(lastNames, " ")
+
+	words := strings.Fields(given)
+	if len(words) > 0 {
+		if salutation, count := p.processSalutations(words); count > 0 {
+			name.Salutation = salutation
+			words = words[count:]
+			for x := 0; x < count; x++ {
+				fields = append(fields, FieldSalutation)
+			}
+		} else if len(words) > 1 && isAbbreviation(words[0]) {
+			result.ambiguous(words[0], AmbiguityUnrecognizedSalutation)
+		}
+	}
+	var suffixes []string
+	for len(words) > 1 {
+		suffix := p.processSuffix(words[len(words)-1])
+		if suffix == "" {
+			break
+		}
+		suffixes = append([]string{suffix}, suffixes...)
+		words = words[:len(words)-1]
+	}
+	name.Suffix = strings.Join(suffixes, " ")
+	p.checkSuffixes(suffixes, result)
+
+	var firstNames, initials []string
+	firstInitial := -1
+	for _, word := range words {
+		if isMiddleName(word) {
+			if firstInitial < 0 {
+				firstInitial = len(fields)
+			}
+			initials = append(initials, strings.ToUpper(word))
+			fields = append(fields, FieldMiddleName)
+		} else {
+			firstNames = append(firstNames, p.fixParticleCase(word, false))
+			fields = append(fields, FieldFirstName)
+		}
+	}
+	// e.g. "Smith, J. A." uses the first initial as the first name.
+	if len(firstNames) == 0 && len(initials) > 0 {
+		result.ambiguous(initials[0], AmbiguityInitialAsFirstName)
+		firstNames, initials = initials[:1], initials[1:]
+		fields[firstInitial] = FieldFirstName
+	}
+	for range suffixes {
+		fields = append(fields, FieldSuffix)
+	}
+	name.FirstName = strings.Join(firstNames, " ")
+	name.MiddleName = strings.Join(initials, " ")
+	return name, fields
+}
 
-	nameParts := []string{}
+// parseWords parses the "First Middle Last" convention.
+// It returns the field of each word in the name, in order.
+func (p *Parser) parseWords(fullName string, result *Result) (*Name, []Field) {
+	nameParts := strings.Fields(fullName)
+
+	name := new(Name)
+	if len(nameParts) == 0 {
+		return name, nil
+	}
+	fields := make([]Field, len(nameParts))
 
 	lastName := ""
 	firstName := ""
 	initials := ""
-	for _, part := range rawNameParts {
-		if !strings.Contains(part, "(") {
-			nameParts = append(nameParts, part)
-		}
-	}
 
 	numWords := len(nameParts)
-	salutation := processSalutation(nameParts[0])
-	suffix := processSuffix(nameParts[len(nameParts)-1])
-
-	start := 0
-	if salutation != "" {
-		start = 1
+	salutation, start := p.processSalutations(nameParts)
+	for x := 0; x < start; x++ {
+		fields[x] = FieldSalutation
+	}
+	if start == 0 && numWords > 1 && isAbbreviation(nameParts[0]) {
+		result.ambiguous(nameParts[0], AmbiguityUnrecognizedSalutation)
 	}
 
+	// collect trailing suffixes, e.g. "John Smith Jr. MD PhD".
 	end := numWords
-	if suffix != "" {
-		end = numWords - 1
+	var suffixes []string
+	if suffix := p.processSuffix(nameParts[end-1]); suffix != "" {
+		suffixes = append(suffixes, suffix)
+		end--
+		for end-1 > start {
+			suffix = p.processSuffix(nameParts[end-1])
+			if suffix == "" {
+				break
+			}
+			suffixes = append([]string{suffix}, suffixes...)
+			end--
+		}
+	}
+	suffix := strings.Join(suffixes, " ")
+	p.checkSuffixes(suffixes, result)
+	for x := end; x < numWords; x++ {
+		fields[x] = FieldSuffix
+	}
+
+	// the first names end before a double surname, e.g. "García y López".
+	limit := end - 1
+	if surnameStart := p.doubleSurnameStart(nameParts, start, end); surnameStart > start {
+		limit = surnameStart
+		if !isSurnameConjunction(nameParts[end-2]) {
+			result.ambiguous(nameParts[surnameStart], AmbiguityDoubleSurname)
+		}
 	}
 
 	i := 0
-	for i = start; i < (end - 1); i++ {
+	for i = start; i < limit; i++ {
 		word := nameParts[i]
-		if isCompoundLastName(word) && i != start {
+		if p.isCompoundLastName(word) && i != start {
+			// e.g. "María del Carmen García López", where the particle is part of the given name.
+			if length, ok := p.givenNameParticle(nameParts[i:end]); ok {
+				result.ambiguous(word, AmbiguityGivenNameOrParticle)
+				for x, particle := range nameParts[i : i+length] {
+					firstName = firstName + " " + p.fixParticleCase(particle, false)
+					fields[i+x] = FieldFirstName
+				}
+				i += length
+				break
+			}
+			// e.g. "Von" in "Anthony Von Fange" could be a middle name, but "von" is a particle.
+			if strings.ToLower(word) != word {
+				result.ambiguous(word, AmbiguityMiddleNameOrParticle)
+			}
 			break
 		}
 		if isMiddleName(word) {
-			if i == start {
-				if isMiddleName(nameParts[i+1]) {
-					firstName = firstName + " " + strings.ToUpper(word)
-				} else {
-					initials = initials + " " + strings.ToUpper(word)
-				}
+			if i == start && isMiddleName(nameParts[i+1]) {
+				firstName = firstName + " " + strings.ToUpper(word)
+				fields[i] = FieldFirstName
 			} else {
 				initials = initials + " " + strings.ToUpper(word)
+				fields[i] = FieldMiddleName
 			}
 		} else {
-			firstName = firstName + " " + fixCase(word)
+			if firstName != "" {
+				result.ambiguous(word, AmbiguityFirstNameOrMiddleName)
+			}
+			firstName = firstName + " " + p.fixCase(word)
+			fields[i] = FieldFirstName
 		}
 	}
 
 	if (end - start) > 1 {
 		for j := i; j < end; j++ {
-			lastName = lastName + " " + fixCase(nameParts[j])
+			lastName = lastName + " " + p.fixParticleCase(nameParts[j], false)
+			fields[j] = FieldLastName
 		}
 	} else if i < len(nameParts) {
-		firstName = fixCase(nameParts[i])
+		result.ambiguous(nameParts[i], AmbiguityFirstNameOrLastName)
+		firstName = p.fixCase(nameParts[i])
+		fields[i] = FieldFirstName
 	}
 
 	name.Salutation = salutation
@@ -131,69 +371,168 @@ func Parse(input string) *Name {
 	name.LastName = strings.TrimSpace(lastName)
 	name.Suffix = suffix
 
-	return name
+	return name, fields
 }
 
-func processSalutation(input string) string {
-	word := cleanString(input)
+// doubleSurnameStart returns the index of the first word of a double surname, i.e. a paternal
+// and a maternal surname, or -1 if there isn't one. Double surnames joined by a conjunction,
+// e.g. "García y López", are always recognized; other double surnames, e.g. "García López",
+// are only recognized if the parser has double surnames enabled.
+func (p *Parser) doubleSurnameStart(nameParts []string, start, end int) int {
+	index := p.surnameUnitStart(nameParts, start, end-1)
+	isJoined := index-1 > start && isSurnameConjunction(nameParts[index-1])
+	if isJoined {
+		index--
+	} else if !p.doubleSurnames {
+		return -1
+	}
+	if index-1 <= start || isMiddleName(nameParts[index-1]) || p.isCompoundLastName(nameParts[index-1]) {
+		return -1
+	}
+	return p.surnameUnitStart(nameParts, start, index-1)
+}
 
-	switch word {
-	case "mr", "master", "mister":
-		return "Mr."
-	case "mrs", "misses":
-		return "Mrs."
-	case "ms", "miss":
-		return "Ms."
-	case "dr":
-		return "Dr."
-	case "rev":
-		return "Rev."
-	case "fr":
-		return "Fr."
+// surnameUnitStart returns the index of the first word of a surname ending with a given word,
+// including its particles, e.g. "de la Fuente".
+func (p *Parser) surnameUnitStart(nameParts []string, start, index int) int {
+	for index-1 > start && p.isCompoundLastName(nameParts[index-1]) {
+		index--
 	}
+	return index
+}
+
+// givenNameParticle returns the number of words in a particle phrase and the word it belongs to if they
+// are part of a given name, e.g. the "del Carmen" in "María del Carmen García López", which is
+// the case when a double surname follows them.
+func (p *Parser) givenNameParticle(words []string) (int, bool) {
+	length := 0
+	for length < len(words) && p.isCompoundLastName(words[length]) {
+		length++
+	}
+	var surnames int
+	for _, word := range words[length:] {
+		if isMiddleName(word) {
+			return 0, false
+		}
+		if !p.isCompoundLastName(word) && !isSurnameConjunction(word) {
+			surnames++
+		}
+	}
+	if surnames < 3 {
+		return 0, false
+	}
+	return length + 1, true
+}
 
-	return ""
+// isSurnameConjunction returns if a word joins a paternal and maternal surname, e.g. the "y" in "García y López".
+// Conjunctions must be lowercase so they aren't confused with initials.
+func isSurnameConjunction(input string) bool {
+	switch input {
+	case "y", "e", "i":
+		return true
+	}
+	return false
 }
 
-func processSuffix(input string) string {
-	word := cleanString(input)
-	return getByLower(validSuffixes, word)
+// checkSuffixes adds ambiguities for suffixes that could be initials, e.g. the "V" in "John Smith V".
+func (p *Parser) checkSuffixes(suffixes []string, result *Result) {
+	for _, suffix := range suffixes {
+		if isMiddleName(suffix) {
+			result.ambiguous(suffix, AmbiguitySuffixOrInitial)
+		}
+	}
 }
 
-func isCompoundLastName(input string) bool {
-	word := cleanString(input)
-	exists := containsLower(compoundLastNames, word)
-	return exists
+// isAbbreviation returns if a word is an abbreviation of more than one letter, e.g. "Capt.".
+func isAbbreviation(input string) bool {
+	return strings.HasSuffix(input, ".") && utf8.RuneCountInString(input) > 2
 }
 
+// isMiddleName returns if a word is an initial, i.e. a single letter with an optional period.
 func isMiddleName(input string) bool {
-	word := cleanString(input)
-	return len(word) == 1
+	word := []rune(cleanString(input))
+	return len(word) == 1 && unicode.IsLetter(word[0])
 }
 
-func uppercaseFirstAll(input string, seperator string) string {
-	words := []string{}
-	parts := strings.Split(input, seperator)
-	for _, thisWord := range parts {
-		toAppend := ""
-		if isCamelCase(thisWord) {
-			toAppend = thisWord
-		} else {
-			toAppend = strings.ToLower(upperCaseFirst(thisWord))
+// fixCase capitalizes each part of a word, where parts are separated by hyphens, periods or apostrophes,
+// e.g. "o'brien-smith" becomes "O'Brien-Smith". Parts that are already mixed case are kept as is,
+// and "Mc" and "Mac" prefixes are capitalized, e.g. "mcdonald" becomes "McDonald".
+// Words in the case exceptions are output in their exception form.
+func (p *Parser) fixCase(input string) string {
+	if exception, ok := p.caseExceptions[strings.ToLower(input)]; ok {
+		return exception
+	}
+
+	var output, part strings.Builder
+	flush := func() {
+		output.WriteString(fixPartCase(part.String()))
+		part.Reset()
+	}
+	for _, c := range input {
+		if isCaseSeparator(c) {
+			flush()
+			output.WriteRune(c)
+			continue
+		}
+		part.WriteRune(c)
+	}
+	flush()
+	return output.String()
+}
+
+// fixParticleCase fixes the case of a word, casing compound surname particles by the parser's
+// particle case, e.g. the "bin" in "bin Omar". Conjunctions are kept lowercase.
+// Leading is if the word starts the string, i.e. a last name without a given name before it.
+func (p *Parser) fixParticleCase(input string, isLeading bool) string {
+	if isSurnameConjunction(input) {
+		return input
+	}
+	if !p.isCompoundLastName(input) {
+		return p.fixCase(input)
+	}
+	switch p.particleCase {
+	case ParticleCaseLower:
+		return strings.ToLower(input)
+	case ParticleCaseCapitalize:
+		return p.fixCase(input)
+	case ParticleCaseDutch:
+		if isLeading {
+			return p.fixCase(input)
 		}
-		words = append(words, toAppend)
+		return strings.ToLower(input)
+	default:
+		if strings.ToLower(input) == input {
+			return input
+		}
+		return p.fixCase(input)
+	}
+}
+
+func fixPartCase(part string) string {
+	if part == "" || isCamelCase(part) {
+		return part
 	}
-	return strings.Join(words, seperator)
+	lower := strings.ToLower(part)
+	switch {
+	case strings.HasPrefix(lower, "mc") && utf8.RuneCountInString(lower) > 3:
+		return "Mc" + upperCaseFirst(lower[2:])
+	case strings.HasPrefix(lower, "mac") && utf8.RuneCountInString(lower) > 5:
+		return "Mac" + upperCaseFirst(lower[3:])
+	}
+	return upperCaseFirst(lower)
 }
 
+// upperCaseFirst title cases the first rune of a string, e.g. "émile" becomes "Émile".
 func upperCaseFirst(input string) string {
-	return strings.Title(strings.ToLower(input))
+	first, size := utf8.DecodeRuneInString(input)
+	if first == utf8.RuneError {
+		return input
+	}
+	return string(unicode.ToTitle(first)) + input[size:]
 }
 
-func fixCase(input string) string {
-	word := uppercaseFirstAll(input, "-")
-	word = uppercaseFirstAll(word, ".")
-	return word
+func isCaseSeparator(c rune) bool {
+	return c == '-' || c == '.' || c == '\'' || c == '’'
 }
 
 func cleanString(input string) string {
@@ -217,23 +556,3 @@ func isCamelCase(input string) bool {
 
 	return hasLowers && hasUppers
 }
-
-// containsLower returns true if the `elem` is in the StringArray, false otherwise.
-func containsLower(values []string, elem string) bool {
-	for _, arrayElem := range values {
-		if strings.ToLower(arrayElem) == elem {
-			return true
-		}
-	}
-	return false
-}
-
-// getByLower returns an element from the array that matches the input.
-func getByLower(values []string, elem string) string {
-	for _, arrayElem := range values {
-		if strings.ToLower(arrayElem) == elem {
-			return arrayElem
-		}
-	}
-	return ""
-}
@@ -10,23 +10,440 @@ func TestNames(t *testing.T) {
 	assert := assert.New(t)
 
 	names := map[string]*Name{}
-	names["John Doe"] = &Name{"", "John", "", "Doe", ""}
-	names["Mr Anthony R Von Fange III"] = &Name{"Mr.", "Anthony", "R", "Von Fange", "III"}
-	names["Sara Ann Fraser"] = &Name{"", "Sara Ann", "", "Fraser", ""}
-	names["Adam"] = &Name{"", "Adam", "", "", ""}
-	names["Jonathan Smith"] = &Name{"", "Jonathan", "", "Smith", ""}
-	names["Anthony R Von Fange III"] = &Name{"", "Anthony", "R", "Von Fange", "III"}
-	names["Anthony Von Fange III"] = &Name{"", "Anthony", "", "Von Fange", "III"}
-	names["Mr John Doe"] = &Name{"Mr.", "John", "", "Doe", ""}
-	names["Justin White Phd"] = &Name{"", "Justin", "", "White", "PhD"}
-	names["Mark P Williams"] = &Name{"", "Mark", "P", "Williams", ""}
-	names["Aaron bin Omar"] = &Name{"", "Aaron", "", "bin Omar", ""}
-	names["Aaron ibn Omar"] = &Name{"", "Aaron", "", "ibn Omar", ""}
-	names[""] = &Name{"", "", "", "", ""}
-	names["Dr"] = &Name{"Dr.", "", "", "", ""}
+	names["John Doe"] = &Name{FirstName: "John", LastName: "Doe"}
+	names["Mr Anthony R Von Fange III"] = &Name{Salutation: "Mr.", FirstName: "Anthony", MiddleName: "R", LastName: "Von Fange", Suffix: "III"}
+	names["Sara Ann Fraser"] = &Name{FirstName: "Sara Ann", LastName: "Fraser"}
+	names["Adam"] = &Name{FirstName: "Adam"}
+	names["Jonathan Smith"] = &Name{FirstName: "Jonathan", LastName: "Smith"}
+	names["Anthony R Von Fange III"] = &Name{FirstName: "Anthony", MiddleName: "R", LastName: "Von Fange", Suffix: "III"}
+	names["Anthony Von Fange III"] = &Name{FirstName: "Anthony", LastName: "Von Fange", Suffix: "III"}
+	names["Mr John Doe"] = &Name{Salutation: "Mr.", FirstName: "John", LastName: "Doe"}
+	names["Justin White Phd"] = &Name{FirstName: "Justin", LastName: "White", Suffix: "PhD"}
+	names["Mark P Williams"] = &Name{FirstName: "Mark", MiddleName: "P", LastName: "Williams"}
+	names["Aaron bin Omar"] = &Name{FirstName: "Aaron", LastName: "bin Omar"}
+	names["Aaron ibn Omar"] = &Name{FirstName: "Aaron", LastName: "ibn Omar"}
+	names[""] = &Name{}
+	names["Dr"] = &Name{Salutation: "Dr."}
 
 	for rawName, expectedResult := range names {
 		result := Parse(rawName)
 		assert.Equal(expectedResult, result)
 	}
 }
+
+func TestParseLastFirst(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["Smith, John A."] = &Name{FirstName: "John", MiddleName: "A.", LastName: "Smith"}
+	names["Smith, John, Jr."] = &Name{FirstName: "John", LastName: "Smith", Suffix: "Jr"}
+	names["Smith, John A., Jr., PhD"] = &Name{FirstName: "John", MiddleName: "A.", LastName: "Smith", Suffix: "Jr PhD"}
+	names["Von Fange, Anthony R III"] = &Name{FirstName: "Anthony", MiddleName: "R", LastName: "Von Fange", Suffix: "III"}
+	names["Smith, Dr. John"] = &Name{Salutation: "Dr.", FirstName: "John", LastName: "Smith"}
+	names["Smith, J. A."] = &Name{FirstName: "J.", MiddleName: "A.", LastName: "Smith"}
+	names["Smith,"] = &Name{FirstName: "Smith"}
+	names["Mr. Potato McTater, III"] = &Name{Salutation: "Mr.", FirstName: "Potato", LastName: "McTater", Suffix: "III"}
+	names["John Smith, Jr."] = &Name{FirstName: "John", LastName: "Smith", Suffix: "Jr"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+}
+
+func TestParseNickname(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names[`Robert "Bob" Smith`] = &Name{FirstName: "Robert", LastName: "Smith", Nickname: "Bob"}
+	names["Robert (Bob) Smith"] = &Name{FirstName: "Robert", LastName: "Smith", Nickname: "Bob"}
+	names["Robert (Big Bob) Smith Jr"] = &Name{FirstName: "Robert", LastName: "Smith", Suffix: "Jr", Nickname: "Big Bob"}
+	names["Smith, Robert “Bob”"] = &Name{FirstName: "Robert", LastName: "Smith", Nickname: "Bob"}
+	names["Robert Smith ()"] = &Name{FirstName: "Robert", LastName: "Smith"}
+	names["Robert (Bob Smith"] = &Name{FirstName: "Robert", Nickname: "Bob Smith"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+}
+
+func TestNameString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("Mr. John A. Smith Jr", Name{Salutation: "Mr.", FirstName: "John", MiddleName: "A.", LastName: "Smith", Suffix: "Jr"}.String())
+	assert.Equal(`Robert "Bob" Smith`, Parse(`Robert (Bob) Smith`).String())
+}
+
+func TestParser(t *testing.T) {
+	assert := assert.New(t)
+
+	parser := NewParser().
+		WithSalutation("Sgt.", "sergeant").
+		WithSuffixes("Esq", "CPA").
+		WithCompoundLastNames("mac")
+
+	assert.Equal(&Name{Salutation: "Sgt.", FirstName: "John", LastName: "Smith", Suffix: "Esq"}, parser.Parse("Sergeant John Smith Esq."))
+	assert.Equal(&Name{FirstName: "Angus", LastName: "Mac Donald", Suffix: "CPA"}, parser.Parse("Angus Mac Donald CPA"))
+	assert.Equal(&Name{Salutation: "Dr.", FirstName: "Jane", LastName: "Doe"}, parser.Parse("Dr Jane Doe"))
+
+	parser = NewParser().WithoutDefaultSalutations().WithoutDefaultSuffixes().WithoutDefaultCompoundLastNames()
+	assert.Empty(parser.Salutations())
+	assert.Empty(parser.Suffixes())
+	assert.Empty(parser.CompoundLastNames())
+	assert.Equal(&Name{FirstName: "Dr Anthony Von", LastName: "Fange"}, parser.Parse("Dr Anthony Von Fange"))
+}
+
+func TestParseMultipleSuffixes(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["John Smith Jr. MD PhD"] = &Name{FirstName: "John", LastName: "Smith", Suffix: "Jr MD PhD"}
+	names["Dr. John A Smith III MD"] = &Name{Salutation: "Dr.", FirstName: "John", MiddleName: "A", LastName: "Smith", Suffix: "III MD"}
+	names["Smith, John Jr MD"] = &Name{FirstName: "John", LastName: "Smith", Suffix: "Jr MD"}
+	names["John V"] = &Name{FirstName: "John", Suffix: "V"}
+	names["John Smith, Jr., MD"] = &Name{FirstName: "John", LastName: "Smith", Suffix: "Jr MD"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+}
+
+func TestParseCase(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["ronald mcdonald"] = &Name{FirstName: "Ronald", LastName: "McDonald"}
+	names["CONAN O'BRIEN"] = &Name{FirstName: "Conan", LastName: "O'Brien"}
+	names["anthony d'angelo"] = &Name{FirstName: "Anthony", LastName: "D'Angelo"}
+	names["angus macdonald"] = &Name{FirstName: "Angus", LastName: "MacDonald"}
+	names["antonio machado"] = &Name{FirstName: "Antonio", LastName: "Machado"}
+	names["mary-kate smith-jones"] = &Name{FirstName: "Mary-Kate", LastName: "Smith-Jones"}
+	names["jack mack"] = &Name{FirstName: "Jack", LastName: "Mack"}
+	names["Ludwig van Beethoven"] = &Name{FirstName: "Ludwig", LastName: "van Beethoven"}
+	names["LUDWIG VAN BEETHOVEN"] = &Name{FirstName: "Ludwig", LastName: "Van Beethoven"}
+	names["Ronald McDonald"] = &Name{FirstName: "Ronald", LastName: "McDonald"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+
+	parser := NewParser().WithCaseExceptions("DeVito", "Macon")
+	assert.Equal(&Name{FirstName: "Danny", LastName: "DeVito"}, parser.Parse("danny devito"))
+	assert.Equal(&Name{FirstName: "Jim", LastName: "Macon"}, parser.Parse("jim macon"))
+}
+
+func TestParseUnicode(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["josé garcía-núñez"] = &Name{FirstName: "José", LastName: "García-Núñez"}
+	names["BJÖRK GUÐMUNDSDÓTTIR"] = &Name{FirstName: "Björk", LastName: "Guðmundsdóttir"}
+	names["Émile É. Zola"] = &Name{FirstName: "Émile", MiddleName: "É.", LastName: "Zola"}
+	names["Øystein  Ødegård"] = &Name{FirstName: "Øystein", LastName: "Ødegård"}
+	names["José García"] = &Name{FirstName: "José", LastName: "García"}
+	names["Núñez, José Á."] = &Name{FirstName: "José", MiddleName: "Á.", LastName: "Núñez"}
+	names["ÉLODIE O'CONNOR"] = &Name{FirstName: "Élodie", LastName: "O'Connor"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+}
+
+func TestParseDoubleSurnames(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["María del Carmen García López"] = &Name{FirstName: "María del Carmen", LastName: "García López"}
+	names["Juan García y López"] = &Name{FirstName: "Juan", LastName: "García y López"}
+	names["Maria Silva e Souza"] = &Name{FirstName: "Maria", LastName: "Silva e Souza"}
+	names["Juan Pablo de la Fuente López"] = &Name{FirstName: "Juan Pablo", LastName: "de la Fuente López"}
+	names["José García López"] = &Name{FirstName: "José García", LastName: "López"}
+	names["García López, María del Carmen"] = &Name{FirstName: "María del Carmen", LastName: "García López"}
+	names["John E Smith"] = &Name{FirstName: "John", MiddleName: "E", LastName: "Smith"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+
+	parser := NewParser().WithDoubleSurnames(true)
+	assert.True(parser.DoubleSurnames())
+	assert.Equal(&Name{FirstName: "José", LastName: "García López"}, parser.Parse("José García López"))
+	assert.Equal(&Name{FirstName: "José", MiddleName: "A.", LastName: "García López"}, parser.Parse("José A. García López"))
+	assert.Equal(&Name{FirstName: "Pedro", LastName: "de la Cruz Sánchez"}, parser.Parse("Pedro de la Cruz Sánchez"))
+	assert.Equal(&Name{FirstName: "María del Carmen", LastName: "García López"}, parser.Parse("María del Carmen García López"))
+	assert.Equal(&Name{FirstName: "José", LastName: "García"}, parser.Parse("José García"))
+}
+
+func TestAnalyze(t *testing.T) {
+	assert := assert.New(t)
+
+	result := Analyze("John Smith")
+	assert.Equal(&Name{FirstName: "John", LastName: "Smith"}, result.Name)
+	assert.Equal(1.0, result.Confidence)
+	assert.False(result.IsAmbiguous())
+
+	result = Analyze("Anthony Von Fange")
+	assert.Equal(&Name{FirstName: "Anthony", LastName: "Von Fange"}, result.Name)
+	assert.Equal([]Ambiguity{{Word: "Von", Reason: AmbiguityMiddleNameOrParticle}}, result.Ambiguities)
+	assert.InDelta(0.8, result.Confidence, 0.0001)
+	assert.Equal(`"Von": word could be a middle name or a compound surname particle`, result.Ambiguities[0].String())
+
+	assert.False(Analyze("Ludwig van Beethoven").IsAmbiguous())
+
+	result = Analyze("Sara Ann Fraser")
+	assert.Equal([]Ambiguity{{Word: "Ann", Reason: AmbiguityFirstNameOrMiddleName}}, result.Ambiguities)
+
+	result = Analyze("Adam")
+	assert.Equal([]Ambiguity{{Word: "Adam", Reason: AmbiguityFirstNameOrLastName}}, result.Ambiguities)
+	assert.InDelta(0.6, result.Confidence, 0.0001)
+
+	result = Analyze("Smith, J. A.")
+	assert.Equal([]Ambiguity{{Word: "J.", Reason: AmbiguityInitialAsFirstName}}, result.Ambiguities)
+
+	result = Analyze("Supt. John Smith")
+	assert.Equal([]Ambiguity{
+		{Word: "Supt.", Reason: AmbiguityUnrecognizedSalutation},
+		{Word: "John", Reason: AmbiguityFirstNameOrMiddleName},
+	}, result.Ambiguities)
+
+	result = Analyze("John Smith V")
+	assert.Equal([]Ambiguity{{Word: "V", Reason: AmbiguitySuffixOrInitial}}, result.Ambiguities)
+
+	result = Analyze("Robert (Bob Smith")
+	assert.Equal([]Ambiguity{
+		{Word: "(", Reason: AmbiguityUnclosedNickname},
+		{Word: "Robert", Reason: AmbiguityFirstNameOrLastName},
+	}, result.Ambiguities)
+
+	result = Analyze("Smith, John, Adam")
+	assert.Equal([]Ambiguity{
+		{Word: "Adam", Reason: AmbiguityUnrecognizedSegment},
+	}, result.Ambiguities)
+
+	result = NewParser().WithDoubleSurnames(true).Analyze("José García López")
+	assert.Equal([]Ambiguity{{Word: "García", Reason: AmbiguityDoubleSurname}}, result.Ambiguities)
+
+	result = Analyze("")
+	assert.Equal(0.0, result.Confidence)
+	result = Analyze("Dr")
+	assert.Equal(0.0, result.Confidence)
+}
+
+func TestParseEntity(t *testing.T) {
+	assert := assert.New(t)
+
+	organizations := []string{
+		"Acme Holdings LLC",
+		"Acme Holdings, L.L.C.",
+		"The Smith Family Trust",
+		"Smith & Jones",
+		"Initech Inc.",
+		"7-Eleven",
+	}
+	for _, organization := range organizations {
+		entity := ParseEntity(organization)
+		assert.False(entity.IsPerson, organization)
+		assert.Nil(entity.Name, organization)
+		assert.Equal(organization, entity.Organization)
+		assert.Equal(organization, entity.String())
+		assert.True(IsOrganization(organization), organization)
+	}
+
+	entity := ParseEntity("  Acme   Widgets  Corp ")
+	assert.Equal("Acme Widgets Corp", entity.Organization)
+
+	entity = ParseEntity("Mr. John Smith")
+	assert.True(entity.IsPerson)
+	assert.Equal(&Name{Salutation: "Mr.", FirstName: "John", LastName: "Smith"}, entity.Name)
+	assert.Empty(entity.Organization)
+	assert.Equal("Mr. John Smith", entity.String())
+	assert.False(IsOrganization("Theodore Smith"))
+	assert.False(IsOrganization("The"))
+	assert.False(IsOrganization("LLC"))
+
+	people := []string{
+		"Charlotte Church",
+		"Mary Church Terrell",
+		"John Smith 3rd",
+		"John Smith 2nd.",
+		"Mark Co",
+		"Paul Inc Jones",
+	}
+	for _, person := range people {
+		assert.False(IsOrganization(person), person)
+		assert.True(ParseEntity(person).IsPerson, person)
+	}
+
+	parser := NewParser().WithoutDefaultOrganizationKeywords().WithOrganizationKeywords("Bancorp")
+	assert.Equal([]string{"bancorp"}, parser.OrganizationKeywords())
+	assert.True(parser.IsOrganization("First Bancorp"))
+	assert.False(parser.IsOrganization("Acme LLC"))
+}
+
+func TestNameFormats(t *testing.T) {
+	assert := assert.New(t)
+
+	name := Name{Salutation: "Mr.", FirstName: "John", MiddleName: "A.", LastName: "Smith", Suffix: "Jr", Nickname: "Jack"}
+	assert.Equal("John Smith", name.FirstLast())
+	assert.Equal("Smith, John A., Jr", name.Sorted())
+	assert.Equal("J.A.S.", name.Initials())
+	assert.Equal("J A S", name.Initials(Punctuation{Separator: " "}))
+	assert.Equal("J. A. Smith", name.Abbreviated())
+	assert.Equal("JA Smith", name.Abbreviated(Punctuation{}))
+
+	name = Name{FirstName: "Sara Ann", LastName: "Fraser"}
+	assert.Equal("Fraser, Sara Ann", name.Sorted())
+	assert.Equal("S.A.F.", name.Initials())
+	assert.Equal("S. A. Fraser", name.Abbreviated())
+
+	name = *Parse("Ludwig van Beethoven")
+	assert.Equal("L.B.", name.Initials())
+	assert.Equal("L. van Beethoven", name.Abbreviated())
+
+	name = *Parse("émile zola")
+	assert.Equal("É.Z.", name.Initials())
+
+	name = Name{FirstName: "Adam"}
+	assert.Equal("Adam", name.FirstLast())
+	assert.Equal("Adam", name.Sorted())
+	assert.Equal("A.", name.Initials())
+	assert.Equal("Adam", name.Abbreviated())
+
+	name = Name{LastName: "Smith"}
+	assert.Equal("Smith", name.Abbreviated())
+	assert.Equal("Smith", name.Sorted())
+	assert.Empty(Name{}.Initials())
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	result := Match(Parse("John A. Smith"), Parse("John A. Smith"))
+	assert.Equal(1.0, result.Score)
+	assert.True(result.IsMatch)
+	assert.False(result.Transposed)
+
+	assert.True(Match(Parse("Robert Smith"), Parse("Bob Smith")).IsMatch)
+	assert.True(Match(Parse("Bob Smith"), Parse("Rob Smith")).IsMatch)
+	assert.True(Match(Parse(`Robert "Rocky" Smith`), Parse("Rocky Smith")).IsMatch)
+	assert.True(Match(Parse("John Adam Smith"), Parse("John A. Smith")).IsMatch)
+	assert.True(Match(Parse("John Smith"), Parse("John A. Smith")).IsMatch)
+	assert.True(Match(Parse("J. Smith"), Parse("John Smith")).IsMatch)
+	assert.True(Match(Parse("Mary Smith-Jones"), Parse("Mary Jones")).IsMatch)
+
+	result = Match(Parse("John Smith"), Parse("Smith, John"))
+	assert.True(result.IsMatch)
+	assert.False(result.Transposed)
+
+	result = Match(Parse("John Smith"), &Name{FirstName: "Smith", LastName: "John"})
+	assert.True(result.IsMatch)
+	assert.True(result.Transposed)
+
+	assert.False(Match(Parse("Robert Smith"), Parse("Albert Smith")).IsMatch)
+	assert.False(Match(Parse("John Smith"), Parse("Jane Smith")).IsMatch)
+	assert.False(Match(Parse("John Smith"), Parse("John Jones")).IsMatch)
+	assert.False(Match(Parse("John A. Smith"), Parse("John B. Smith")).IsMatch)
+	assert.False(Match(Parse("John Smith Jr"), Parse("John Smith Sr")).IsMatch)
+	assert.False(Match(Parse("Jan de Vries"), Parse("Jan de Jong")).IsMatch)
+	assert.Equal(0.45, Match(Parse("Jan de Vries"), Parse("Jan de Jong")).Score)
+	assert.True(Match(Parse("Jan de Vries"), Parse("Jan Vries")).IsMatch)
+	assert.False(Match(nil, Parse("John Smith")).IsMatch)
+
+	parser := NewParser().WithoutDefaultNicknames()
+	assert.False(parser.Match(Parse("Robert Smith"), Parse("Bob Smith")).IsMatch)
+	parser.WithNicknames("Robert", "Rocky")
+	assert.True(parser.AreNicknames("rocky", "Robert"))
+}
+
+func TestParseSalutations(t *testing.T) {
+	assert := assert.New(t)
+
+	names := map[string]*Name{}
+	names["Mx. Alex Smith"] = &Name{Salutation: "Mx.", FirstName: "Alex", LastName: "Smith"}
+	names["Professor Jane Doe"] = &Name{Salutation: "Prof.", FirstName: "Jane", LastName: "Doe"}
+	names["Hon. John Smith"] = &Name{Salutation: "Hon.", FirstName: "John", LastName: "Smith"}
+	names["Captain Jack Sparrow"] = &Name{Salutation: "Capt.", FirstName: "Jack", LastName: "Sparrow"}
+	names["Rabbi David Cohen"] = &Name{Salutation: "Rabbi", FirstName: "David", LastName: "Cohen"}
+	names["Imam Ali Hassan"] = &Name{Salutation: "Imam", FirstName: "Ali", LastName: "Hassan"}
+	names["The Honorable John Smith"] = &Name{Salutation: "The Hon.", FirstName: "John", LastName: "Smith"}
+	names["Lieutenant Colonel John Smith"] = &Name{Salutation: "Lt. Col.", FirstName: "John", LastName: "Smith"}
+	names["Rev. Dr. Martin King Jr."] = &Name{Salutation: "Rev. Dr.", FirstName: "Martin", LastName: "King", Suffix: "Jr"}
+	names["Smith, The Honorable John"] = &Name{Salutation: "The Hon.", FirstName: "John", LastName: "Smith"}
+
+	for rawName, expectedResult := range names {
+		assert.Equal(expectedResult, Parse(rawName), rawName)
+	}
+
+	assert.False(IsOrganization("The Honorable John Smith"))
+	assert.Equal(&Name{Salutation: "Gen.", FirstName: "John", LastName: "Smith"}, NewParser().WithSalutation("Gen.", "general").Parse("General John Smith"))
+}
+
+func TestParseParticleCase(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ParticleCasePreserve, NewParser().ParticleCase())
+	// the dutch and german particles aren't particles by default.
+	assert.False(NewParser().isCompoundLastName("der"))
+	assert.False(NewParser().WithParticleCase(ParticleCasePreserve).isCompoundLastName("der"))
+	assert.True(NewParser().WithParticleCase(ParticleCaseDutch).isCompoundLastName("der"))
+
+	preserve := NewParser().WithCompoundLastNames(DutchGermanCompoundLastNames...)
+	assert.Equal(&Name{FirstName: "Jan", LastName: "van der Berg"}, preserve.Parse("Jan van der Berg"))
+	assert.Equal(&Name{FirstName: "Karl", LastName: "von und zu Guttenberg"}, preserve.Parse("Karl von und zu Guttenberg"))
+	assert.Equal(&Name{FirstName: "Jan", LastName: "Van Der Berg"}, preserve.Parse("JAN VAN DER BERG"))
+
+	lower := NewParser().WithParticleCase(ParticleCaseLower)
+	assert.Equal(&Name{FirstName: "Karl", LastName: "von und zu Guttenberg"}, lower.Parse("KARL VON UND ZU GUTTENBERG"))
+	assert.Equal(&Name{FirstName: "Jan", LastName: "van der Berg"}, lower.Parse("Van Der Berg, Jan"))
+
+	dutch := NewParser().WithParticleCase(ParticleCaseDutch)
+	assert.Equal(&Name{FirstName: "Jan", LastName: "van der Berg"}, dutch.Parse("JAN VAN DER BERG"))
+	assert.Equal(&Name{FirstName: "Jan", LastName: "Van der Berg"}, dutch.Parse("van der berg, jan"))
+
+	capitalize := NewParser().WithParticleCase(ParticleCaseCapitalize)
+	assert.Equal(&Name{FirstName: "Jan", LastName: "Van Der Berg"}, capitalize.Parse("jan van der berg"))
+	assert.Equal("dutch", ParticleCaseDutch.String())
+}
+
+func TestParseSpans(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(Parse("John Smith").Spans)
+	assert.Empty(Parse("John Smith").Raw)
+
+	parser := NewParser().WithSpans(true)
+	assert.True(parser.Spans())
+
+	name := parser.Parse(`  Dr. Robert "Bob" A. Smith Jr.`)
+	assert.Equal(`  Dr. Robert "Bob" A. Smith Jr.`, name.Raw)
+	assert.Equal([]Span{
+		{Field: FieldSalutation, Start: 2, End: 5},
+		{Field: FieldFirstName, Start: 6, End: 12},
+		{Field: FieldNickname, Start: 14, End: 17},
+		{Field: FieldMiddleName, Start: 19, End: 21},
+		{Field: FieldLastName, Start: 22, End: 27},
+		{Field: FieldSuffix, Start: 28, End: 31},
+	}, name.Spans)
+	assert.Equal("Bob", name.SpanText(name.Spans[2]))
+	assert.Equal("Jr.", name.SpanText(name.SpansFor(FieldSuffix)[0]))
+
+	name = parser.Parse("García López, José, Jr., PhD")
+	var fields, texts []string
+	for _, span := range name.Spans {
+		fields = append(fields, string(span.Field))
+		texts = append(texts, name.SpanText(span))
+	}
+	assert.Equal([]string{"last_name", "last_name", "first_name", "suffix", "suffix"}, fields)
+	assert.Equal([]string{"García", "López", "José", "Jr.", "PhD"}, texts)
+
+	name = parser.Parse("Smith, J. A.")
+	assert.Equal([]Span{
+		{Field: FieldLastName, Start: 0, End: 5},
+		{Field: FieldFirstName, Start: 7, End: 9},
+		{Field: FieldMiddleName, Start: 10, End: 12},
+	}, name.Spans)
+
+	name = parser.Parse("The Honorable María del Carmen García López")
+	assert.Len(name.SpansFor(FieldSalutation), 2)
+	assert.Len(name.SpansFor(FieldFirstName), 3)
+	assert.Len(name.SpansFor(FieldLastName), 2)
+}
@@ -0,0 +1,371 @@
+package names
+
+import "strings"
+
+// DefaultSalutations are the default salutations, keyed by their output form,
+// with the lowercase, period-less forms they are parsed from.
+var DefaultSalutations = map[string][]string{
+	"Mr.":   {"mr", "master", "mister"},
+	"Mrs.":  {"mrs", "misses"},
+	"Ms.":   {"ms", "miss"},
+	"Mx.":   {"mx"},
+	"Dr.":   {"dr", "doctor"},
+	"Prof.": {"prof", "professor"},
+	"Rev.":  {"rev", "reverend"},
+	"Fr.":   {"fr", "father"},
+	"Hon.":  {"hon", "honorable", "honourable"},
+	"Capt.": {"capt", "cpt", "captain"},
+	"Rabbi": {"rabbi"},
+	"Imam":  {"imam"},
+
+	"The Hon.": {"the hon", "the honorable", "the honourable"},
+	"Lt. Col.": {"lt col", "lieutenant colonel"},
+}
+
+// DefaultSuffixes are the default suffixes.
+var DefaultSuffixes = []string{
+	"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII", "XIII", "XIV", "XV", "XVI", "XVII", "XVIII", "XIX", "XX",
+	"Senior", "Junior", "Jr", "Sr",
+	"PhD", "APR", "RPh", "PE", "MD", "MA", "DMD", "CME",
+}
+
+// DefaultCompoundLastNames are the default compound surname particles, e.g. the "von" in "von Fange".
+var DefaultCompoundLastNames = []string{
+	"vere", "von", "van", "de", "del", "della", "di", "da", "pietro",
+	"vanden", "du", "st.", "st", "la", "lo", "ter", "bin", "ibn",
+	"do", "dos", "das",
+}
+
+// DutchGermanCompoundLastNames are the Dutch and German compound surname particles, e.g. the "der" in "van der Berg".
+// They're added by `WithParticleCase` for particle cases other than `ParticleCasePreserve`,
+// as they're common words or given names in other languages.
+var DutchGermanCompoundLastNames = []string{
+	"der", "den", "des", "ten", "te", "op", "vom", "zu", "zum", "zur", "und",
+}
+
+// DefaultCaseExceptions are words output as given instead of by the capitalization rules,
+// e.g. surnames that start with "Mac" but are not capitalized as "MacX".
+var DefaultCaseExceptions = []string{
+	"Macaulay", "Macedo", "Macey", "Machado", "Machin", "Machlin", "Machuca", "Macias", "Maciel",
+	"Mackey", "Mackie", "Mackle", "Macklin", "Mackintosh", "Macomber", "Macri",
+}
+
+// DefaultOrganizationKeywords are the default legal forms that mark a string as an organization rather than a person's name
+// when they end it, e.g. "Acme LLC". "Co" is left out as it's also a surname; "Acme & Co" is an organization by its ampersand.
+var DefaultOrganizationKeywords = []string{
+	"LLC", "L.L.C.", "LLP", "LP", "Inc", "Incorporated", "Corp", "Corporation", "Company", "Ltd", "Limited", "PLC", "GmbH",
+}
+
+// DefaultNicknames are the default nicknames, keyed by the name they are short for.
+var DefaultNicknames = map[string][]string{
+	"Albert":      {"Al", "Bert"},
+	"Alexander":   {"Alex", "Sasha"},
+	"Andrew":      {"Andy", "Drew"},
+	"Anthony":     {"Tony"},
+	"Benjamin":    {"Ben", "Benny"},
+	"Catherine":   {"Cathy", "Kate", "Katie"},
+	"Charles":     {"Charlie", "Chuck", "Chas"},
+	"Christopher": {"Chris"},
+	"Daniel":      {"Dan", "Danny"},
+	"David":       {"Dave"},
+	"Deborah":     {"Deb", "Debbie"},
+	"Donald":      {"Don"},
+	"Edward":      {"Ed", "Eddie", "Ted", "Ned"},
+	"Elizabeth":   {"Liz", "Lizzie", "Beth", "Betty", "Eliza"},
+	"Gregory":     {"Greg"},
+	"James":       {"Jim", "Jimmy", "Jamie"},
+	"Jennifer":    {"Jen", "Jenny"},
+	"John":        {"Jack", "Johnny", "Jon"},
+	"Jonathan":    {"Jon"},
+	"Joseph":      {"Joe", "Joey"},
+	"Katherine":   {"Kathy", "Kate", "Katie", "Kat"},
+	"Kenneth":     {"Ken"},
+	"Lawrence":    {"Larry"},
+	"Margaret":    {"Maggie", "Meg", "Peggy"},
+	"Matthew":     {"Matt"},
+	"Michael":     {"Mike", "Mikey", "Mickey"},
+	"Nicholas":    {"Nick"},
+	"Patricia":    {"Pat", "Patty", "Trish"},
+	"Rebecca":     {"Becky"},
+	"Richard":     {"Rick", "Ricky", "Rich", "Dick"},
+	"Robert":      {"Bob", "Bobby", "Rob", "Robbie", "Bert"},
+	"Ronald":      {"Ron"},
+	"Samuel":      {"Sam"},
+	"Stephen":     {"Steve"},
+	"Steven":      {"Steve"},
+	"Susan":       {"Sue", "Suzy"},
+	"Theodore":    {"Ted", "Teddy", "Theo"},
+	"Thomas":      {"Tom", "Tommy"},
+	"Timothy":     {"Tim"},
+	"Victoria":    {"Vicky", "Tori"},
+	"William":     {"Bill", "Billy", "Will", "Willie", "Liam"},
+}
+
+// defaultParser is the parser used by `Parse`.
+var defaultParser = NewParser()
+
+// NewParser returns a new parser with the default dictionaries.
+func NewParser() *Parser {
+	p := &Parser{
+		salutations:       map[string]string{},
+		suffixes:          map[string]string{},
+		compoundLastNames: map[string]bool{},
+		caseExceptions:    map[string]string{},
+
+		organizationKeywords: map[string]bool{},
+		nicknames:            map[string]map[string]bool{},
+	}
+	for output, inputs := range DefaultSalutations {
+		p.WithSalutation(output, inputs...)
+	}
+	p.WithSuffixes(DefaultSuffixes...)
+	p.WithCompoundLastNames(DefaultCompoundLastNames...)
+	p.WithCaseExceptions(DefaultCaseExceptions...)
+	p.WithOrganizationKeywords(DefaultOrganizationKeywords...)
+	for name, nicknames := range DefaultNicknames {
+		p.WithNicknames(name, nicknames...)
+	}
+	return p
+}
+
+// Parser parses names with configurable dictionaries of salutations, suffixes and compound surname particles.
+// Dictionary lookups are case insensitive and ignore periods.
+// A parser should be configured before use; it is safe to use from multiple goroutines once configured.
+type Parser struct {
+	salutations       map[string]string
+	suffixes          map[string]string
+	compoundLastNames map[string]bool
+	caseExceptions    map[string]string
+	doubleSurnames    bool
+	particleCase      ParticleCase
+	spans             bool
+
+	organizationKeywords map[string]bool
+	// nicknames are the names each nickname is short for.
+	nicknames map[string]map[string]bool
+}
+
+// WithSalutation adds a salutation, parsed from any of the given inputs
+// (and its own output form) and output as given, e.g. `WithSalutation("Sgt.", "sergeant")`.
+// Salutations may have multiple words, e.g. `WithSalutation("Lt. Col.", "lieutenant colonel")`.
+func (p *Parser) WithSalutation(output string, inputs ...string) *Parser {
+	p.salutations[cleanString(output)] = output
+	for _, input := range inputs {
+		p.salutations[cleanString(input)] = output
+	}
+	return p
+}
+
+// Salutations returns the salutations, keyed by the cleaned input form.
+func (p *Parser) Salutations() map[string]string {
+	return p.salutations
+}
+
+// WithoutDefaultSalutations removes all salutations, including the defaults.
+func (p *Parser) WithoutDefaultSalutations() *Parser {
+	p.salutations = map[string]string{}
+	return p
+}
+
+// WithSuffixes adds suffixes, output as given.
+func (p *Parser) WithSuffixes(suffixes ...string) *Parser {
+	for _, suffix := range suffixes {
+		p.suffixes[cleanString(suffix)] = suffix
+	}
+	return p
+}
+
+// Suffixes returns the suffixes, keyed by the cleaned input form.
+func (p *Parser) Suffixes() map[string]string {
+	return p.suffixes
+}
+
+// WithoutDefaultSuffixes removes all suffixes, including the defaults.
+func (p *Parser) WithoutDefaultSuffixes() *Parser {
+	p.suffixes = map[string]string{}
+	return p
+}
+
+// WithCompoundLastNames adds compound surname particles.
+func (p *Parser) WithCompoundLastNames(particles ...string) *Parser {
+	for _, particle := range particles {
+		p.compoundLastNames[cleanString(particle)] = true
+	}
+	return p
+}
+
+// CompoundLastNames returns the compound surname particles, in their cleaned form.
+func (p *Parser) CompoundLastNames() []string {
+	output := make([]string, 0, len(p.compoundLastNames))
+	for particle := range p.compoundLastNames {
+		output = append(output, particle)
+	}
+	return output
+}
+
+// WithoutDefaultCompoundLastNames removes all compound surname particles, including the defaults.
+func (p *Parser) WithoutDefaultCompoundLastNames() *Parser {
+	p.compoundLastNames = map[string]bool{}
+	return p
+}
+
+// WithCaseExceptions adds words that are output as given instead of by the capitalization rules,
+// e.g. `WithCaseExceptions("Macon", "DeVito")`. Matching is case insensitive.
+func (p *Parser) WithCaseExceptions(words ...string) *Parser {
+	for _, word := range words {
+		p.caseExceptions[strings.ToLower(word)] = word
+	}
+	return p
+}
+
+// CaseExceptions returns the case exceptions, keyed by their lowercase form.
+func (p *Parser) CaseExceptions() map[string]string {
+	return p.caseExceptions
+}
+
+// WithDoubleSurnames sets if the last two words of a name are a double surname, i.e. a paternal
+// and a maternal surname as in Spanish and Portuguese names, e.g. "José García López".
+// Double surnames joined by a conjunction, e.g. "García y López", are recognized regardless.
+func (p *Parser) WithDoubleSurnames(doubleSurnames bool) *Parser {
+	p.doubleSurnames = doubleSurnames
+	return p
+}
+
+// DoubleSurnames returns if the last two words of a name are a double surname.
+func (p *Parser) DoubleSurnames() bool {
+	return p.doubleSurnames
+}
+
+// WithOrganizationKeywords adds words that mark a string as an organization when they end it, e.g. `WithOrganizationKeywords("Bancorp")`.
+func (p *Parser) WithOrganizationKeywords(keywords ...string) *Parser {
+	for _, keyword := range keywords {
+		p.organizationKeywords[cleanString(keyword)] = true
+	}
+	return p
+}
+
+// OrganizationKeywords returns the organization keywords, in their cleaned form.
+func (p *Parser) OrganizationKeywords() []string {
+	output := make([]string, 0, len(p.organizationKeywords))
+	for keyword := range p.organizationKeywords {
+		output = append(output, keyword)
+	}
+	return output
+}
+
+// WithoutDefaultOrganizationKeywords removes all organization keywords, including the defaults.
+func (p *Parser) WithoutDefaultOrganizationKeywords() *Parser {
+	p.organizationKeywords = map[string]bool{}
+	return p
+}
+
+// WithNicknames adds nicknames for a name, e.g. `WithNicknames("Robert", "Bob", "Rob")`.
+func (p *Parser) WithNicknames(name string, nicknames ...string) *Parser {
+	name = cleanString(name)
+	for _, nickname := range nicknames {
+		nickname = cleanString(nickname)
+		if p.nicknames[nickname] == nil {
+			p.nicknames[nickname] = map[string]bool{}
+		}
+		p.nicknames[nickname][name] = true
+	}
+	return p
+}
+
+// AreNicknames returns if two first names are the same or nicknames of each other, e.g. "Bob" and "Robert",
+// or nicknames of the same name, e.g. "Bob" and "Rob".
+func (p *Parser) AreNicknames(a, b string) bool {
+	a, b = cleanString(a), cleanString(b)
+	if a == b {
+		return true
+	}
+	if p.nicknames[a][b] || p.nicknames[b][a] {
+		return true
+	}
+	for name := range p.nicknames[a] {
+		if p.nicknames[b][name] {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutDefaultNicknames removes all nicknames, including the defaults.
+func (p *Parser) WithoutDefaultNicknames() *Parser {
+	p.nicknames = map[string]map[string]bool{}
+	return p
+}
+
+// WithParticleCase sets how compound surname particles are cased, e.g. `ParticleCaseDutch`.
+// Particle cases other than `ParticleCasePreserve` also add the `DutchGermanCompoundLastNames`.
+func (p *Parser) WithParticleCase(particleCase ParticleCase) *Parser {
+	p.particleCase = particleCase
+	if particleCase != ParticleCasePreserve {
+		p.WithCompoundLastNames(DutchGermanCompoundLastNames...)
+	}
+	return p
+}
+
+// ParticleCase returns how compound surname particles are cased.
+func (p *Parser) ParticleCase() ParticleCase {
+	return p.particleCase
+}
+
+// WithSpans sets if parsed names record the raw input and the byte offsets of each word
+// and the field it was parsed as, e.g. to highlight the parts of the input in a UI.
+func (p *Parser) WithSpans(spans bool) *Parser {
+	p.spans = spans
+	return p
+}
+
+// Spans returns if parsed names record the raw input and word spans.
+func (p *Parser) Spans() bool {
+	return p.spans
+}
+
+func (p *Parser) processSalutation(input string) string {
+	return p.salutations[cleanString(input)]
+}
+
+// processSalutations returns the leading salutations in a list of words and how many words they are,
+// preferring the longest multi-word salutations, e.g. "Rev. Dr." or "The Honorable".
+func (p *Parser) processSalutations(words []string) (string, int) {
+	var salutations []string
+	var count int
+	for count < len(words) {
+		length := len(words) - count
+		for ; length > 0; length-- {
+			if salutation := p.processSalutation(strings.Join(words[count:count+length], " ")); salutation != "" {
+				salutations = append(salutations, salutation)
+				break
+			}
+		}
+		if length == 0 {
+			break
+		}
+		count += length
+	}
+	return strings.Join(salutations, " "), count
+}
+
+func (p *Parser) processSuffix(input string) string {
+	return p.suffixes[cleanString(input)]
+}
+
+// processSuffixes returns the suffixes in a segment, and if every word in the segment is a suffix.
+func (p *Parser) processSuffixes(input string) ([]string, bool) {
+	words := strings.Fields(input)
+	suffixes := make([]string, 0, len(words))
+	for _, word := range words {
+		suffix := p.processSuffix(word)
+		if suffix == "" {
+			return nil, false
+		}
+		suffixes = append(suffixes, suffix)
+	}
+	return suffixes, len(suffixes) > 0
+}
+
+func (p *Parser) isCompoundLastName(input string) bool {
+	return p.compoundLastNames[cleanString(input)]
+}
@@ -0,0 +1,32 @@
+package names
+
+// ParticleCase is how compound surname particles, e.g. the Dutch tussenvoegsels "van der", are cased.
+type ParticleCase int
+
+// Particle cases.
+const (
+	// ParticleCasePreserve keeps lowercase particles lowercase and capitalizes others,
+	// e.g. "van Beethoven" and "Von Fange". It is the default.
+	ParticleCasePreserve ParticleCase = iota
+	// ParticleCaseLower lowercases particles, e.g. "von und zu Guttenberg", as in German.
+	ParticleCaseLower
+	// ParticleCaseDutch lowercases particles after a given name and capitalizes the first particle
+	// of a last name that starts the string, e.g. "Jan van der Berg" and "Van der Berg, Jan".
+	ParticleCaseDutch
+	// ParticleCaseCapitalize capitalizes particles, e.g. "Van Der Berg", as in Belgian names.
+	ParticleCaseCapitalize
+)
+
+// String returns the name of the particle case.
+func (pc ParticleCase) String() string {
+	switch pc {
+	case ParticleCaseLower:
+		return "lower"
+	case ParticleCaseDutch:
+		return "dutch"
+	case ParticleCaseCapitalize:
+		return "capitalize"
+	default:
+		return "preserve"
+	}
+}
@@ -0,0 +1,110 @@
+package names
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is a name component.
+type Field string
+
+// Fields.
+const (
+	FieldSalutation Field = "salutation"
+	FieldFirstName  Field = "first_name"
+	FieldMiddleName Field = "middle_name"
+	FieldLastName   Field = "last_name"
+	FieldSuffix     Field = "suffix"
+	FieldNickname   Field = "nickname"
+)
+
+// Span is a word in the raw input of a name and the field it was parsed as.
+type Span struct {
+	Field Field
+	// Start is the byte offset of the start of the word in the raw input.
+	Start int
+	// End is the byte offset of the end of the word in the raw input, exclusive.
+	End int
+}
+
+// SpanText returns the raw text of a span.
+func (n Name) SpanText(span Span) string {
+	if span.Start < 0 || span.End > len(n.Raw) || span.Start > span.End {
+		return ""
+	}
+	return n.Raw[span.Start:span.End]
+}
+
+// SpansFor returns the spans for a field.
+func (n Name) SpansFor(field Field) []Span {
+	var output []Span
+	for _, span := range n.Spans {
+		if span.Field == field {
+			output = append(output, span)
+		}
+	}
+	return output
+}
+
+// spans returns the spans of the words in a raw input given the field of each word outside of nicknames, in order.
+// Words are split the same way the parser splits them: on whitespace and commas, with nicknames delimited.
+// If the words don't line up with the fields, no spans are returned.
+func spans(raw string, fields []Field) []Span {
+	var output []Span
+	var wordIndex int
+	wordStart := -1
+	var closing rune
+	var inNickname bool
+
+	endWord := func(end int) bool {
+		if wordStart < 0 {
+			return true
+		}
+		if inNickname {
+			output = append(output, Span{Field: FieldNickname, Start: wordStart, End: end})
+		} else {
+			if wordIndex >= len(fields) {
+				return false
+			}
+			output = append(output, Span{Field: fields[wordIndex], Start: wordStart, End: end})
+			wordIndex++
+		}
+		wordStart = -1
+		return true
+	}
+
+	for index := 0; index < len(raw); {
+		c, size := utf8.DecodeRuneInString(raw[index:])
+		switch {
+		case inNickname && c == closing:
+			if !endWord(index) {
+				return nil
+			}
+			inNickname = false
+		case !inNickname && isNicknameOpening(c):
+			if !endWord(index) {
+				return nil
+			}
+			closing, _ = nicknameClosing(c)
+			inNickname = true
+		case unicode.IsSpace(c) || (!inNickname && c == ','):
+			if !endWord(index) {
+				return nil
+			}
+		default:
+			if wordStart < 0 {
+				wordStart = index
+			}
+		}
+		index += size
+	}
+	if !endWord(len(raw)) || wordIndex != len(fields) {
+		return nil
+	}
+	return output
+}
+
+func isNicknameOpening(c rune) bool {
+	_, ok := nicknameClosing(c)
+	return ok
+}
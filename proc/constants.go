@@ -0,0 +1,67 @@
+package proc
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// DefaultGracePeriod is the time to wait for a stopped sub process to exit before killing it.
+	DefaultGracePeriod = 10 * time.Second
+	// DefaultHealthInterval is the time between health checks.
+	DefaultHealthInterval = 5 * time.Second
+	// DefaultHealthTimeout is the time to wait for a health check.
+	DefaultHealthTimeout = time.Second
+	// DefaultHealthThreshold is the number of consecutive failed health checks before a sub process is restarted.
+	DefaultHealthThreshold = 3
+	// DefaultOutputWaitDelay is the time to wait, once a sub process has exited, for processes it started
+	// to close its stdout and stderr before they're closed.
+	DefaultOutputWaitDelay = time.Second
+)
+
+// Exit reasons.
+const (
+	// ReasonExited is the sub process exited on its own.
+	ReasonExited = "exited"
+	// ReasonQuit is the supervisor was stopped and stopped the sub process.
+	ReasonQuit = "quit"
+	// ReasonUnhealthy is the sub process failed its health checks and was stopped to restart it.
+	ReasonUnhealthy = "unhealthy"
+	// ReasonChanged is the sub process was stopped to restart it immediately, e.g. on file changes.
+	ReasonChanged = "changed"
+	// ReasonMatched is the sub process was stopped to restart it because its output matched a pattern.
+	ReasonMatched = "matched"
+	// ReasonScheduled is the sub process was stopped to restart it immediately on a schedule.
+	ReasonScheduled = "scheduled"
+)
+
+// Supervisor states.
+const (
+	// StateStopped is the supervisor is not running.
+	StateStopped = "stopped"
+	// StateStarting is the supervisor is waiting to start the sub process for the first time.
+	StateStarting = "starting"
+	// StateRunning is the sub process is running.
+	StateRunning = "running"
+	// StateBackoff is the supervisor is waiting to restart the sub process.
+	StateBackoff = "backoff"
+)
+
+const (
+	// ErrAlreadyRunning is returned when starting a supervisor that is already running.
+	ErrAlreadyRunning Error = "supervisor is already running"
+	// ErrNotRunning is returned when a supervisor or its sub process is not running.
+	ErrNotRunning Error = "supervisor is not running"
+	// ErrEmptyCommand is returned when starting a supervisor without a command.
+	ErrEmptyCommand Error = "supervisor command is empty"
+	// ErrRestartsExhausted is returned when the sub process has been restarted the max restarts times.
+	ErrRestartsExhausted Error = "sub process restarts exhausted"
+	// ErrHealthCheckStatus is returned when an http health check returns a non 2xx status.
+	ErrHealthCheckStatus Error = "health check returned a non 2xx status"
+)
+
+// IsRestartsExhausted returns if the error is a restarts exhausted error.
+func IsRestartsExhausted(err error) bool {
+	return exception.Is(err, ErrRestartsExhausted)
+}
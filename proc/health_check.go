@@ -0,0 +1,39 @@
+package proc
+
+import (
+	"context"
+	"net/http"
+	"os/exec"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// HealthCheck checks if the sub process is healthy, returning an error if it is not.
+// The context is cancelled after the supervisor health timeout.
+type HealthCheck func(ctx context.Context) error
+
+// HTTPHealthCheck returns a health check that fails if a GET to a url does not return a 2xx status.
+func HTTPHealthCheck(url string) HealthCheck {
+	return func(ctx context.Context) error {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		res, err := http.DefaultClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return exception.New(ErrHealthCheckStatus).WithMessagef("status code: %d", res.StatusCode)
+		}
+		return nil
+	}
+}
+
+// CommandHealthCheck returns a health check that fails if a shell command exits nonzero.
+func CommandHealthCheck(command string) HealthCheck {
+	return func(ctx context.Context) error {
+		return exec.CommandContext(ctx, "sh", "-c", command).Run()
+	}
+}
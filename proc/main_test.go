@@ -0,0 +1,12 @@
+package proc
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+// TestMain is the testing entrypoint.
+func TestMain(m *testing.M) {
+	assert.Main(m)
+}
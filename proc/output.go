@@ -0,0 +1,68 @@
+package proc
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// newSubOutput returns the output a sub process writes to for a writer.
+// Writers that aren't files are copied to from a pipe the supervisor owns rather than one `exec` creates,
+// so waiting for the sub process to exit doesn't also wait for any processes it started that hold the pipe open.
+func newSubOutput(output io.Writer) (*subOutput, error) {
+	if output == nil {
+		return &subOutput{}, nil
+	}
+	if file, isFile := output.(*os.File); isFile {
+		return &subOutput{file: file}, nil
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	so := &subOutput{file: writer, reader: reader, copied: make(chan struct{})}
+	go func() {
+		defer close(so.copied)
+		io.Copy(output, reader)
+	}()
+	return so, nil
+}
+
+// subOutput is a sub process output; if it's a pipe, the output is copied from the read end.
+type subOutput struct {
+	file   *os.File
+	reader *os.File
+	copied chan struct{}
+}
+
+// started closes the supervisor's copy of the pipe write end once the sub process has its own,
+// so the copy ends when the sub process (and anything it started) closes it.
+func (so *subOutput) started() {
+	if so.reader != nil {
+		so.file.Close()
+	}
+}
+
+// close closes the pipe and waits for the copy to end, e.g. if the sub process didn't start.
+func (so *subOutput) close() {
+	if so.reader == nil {
+		return
+	}
+	so.file.Close()
+	so.reader.Close()
+	<-so.copied
+}
+
+// wait waits for the output written before the sub process exited to be copied. If processes the sub
+// process started still hold the pipe open after a delay, the pipe is closed so the supervisor doesn't hang.
+func (so *subOutput) wait(delay time.Duration) {
+	if so.reader == nil {
+		return
+	}
+	select {
+	case <-so.copied:
+	case <-time.After(delay):
+		so.reader.Close()
+		<-so.copied
+	}
+}
@@ -0,0 +1,3 @@
+// Package proc is a supervisor for sub processes, restarting them with a policy when they exit or fail health checks.
+// It is the library behind the `recover` binary, for services that manage sub processes programmatically.
+package proc
@@ -0,0 +1,39 @@
+package proc
+
+// RestartPolicy returns if the sub process should be restarted after exiting with a code.
+// A code of -1 is a sub process killed by a signal.
+type RestartPolicy func(exitCode int) bool
+
+// RestartOnFailure restarts the sub process on nonzero exit codes; it is the default policy.
+func RestartOnFailure(exitCode int) bool {
+	return exitCode != 0
+}
+
+// RestartAlways restarts the sub process on any exit code.
+func RestartAlways(exitCode int) bool {
+	return true
+}
+
+// RestartOnCodes returns a policy that restarts the sub process only on the given exit codes.
+func RestartOnCodes(codes ...int) RestartPolicy {
+	restartOn := codeSet(codes)
+	return func(exitCode int) bool {
+		return restartOn[exitCode]
+	}
+}
+
+// ExitOnCodes returns a policy that restarts the sub process on any exit code but the given exit codes.
+func ExitOnCodes(codes ...int) RestartPolicy {
+	exitOn := codeSet(codes)
+	return func(exitCode int) bool {
+		return !exitOn[exitCode]
+	}
+}
+
+func codeSet(codes []int) map[int]bool {
+	output := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		output[code] = true
+	}
+	return output
+}
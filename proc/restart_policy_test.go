@@ -0,0 +1,27 @@
+package proc
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRestartPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(RestartOnFailure(0))
+	assert.True(RestartOnFailure(1))
+	assert.True(RestartOnFailure(-1))
+
+	assert.True(RestartAlways(0))
+
+	restartOn := RestartOnCodes(2, -1)
+	assert.True(restartOn(2))
+	assert.True(restartOn(-1))
+	assert.False(restartOn(1))
+
+	exitOn := ExitOnCodes(0, 3)
+	assert.False(exitOn(0))
+	assert.False(exitOn(3))
+	assert.True(exitOn(1))
+}
@@ -0,0 +1,37 @@
+package proc
+
+import "time"
+
+// Status is a snapshot of the supervisor state.
+type Status struct {
+	// State is the supervisor state, e.g. `StateRunning`.
+	State string
+	// PID is the pid of the running sub process, if any.
+	PID int
+	// Restarts is the consecutive restart count.
+	Restarts int
+	// TotalRestarts is the number of restarts since the supervisor started.
+	TotalRestarts int
+	// Started is when the running sub process started, if any.
+	Started time.Time
+	// LastExit is the most recent sub process exit, if any.
+	LastExit *Exit
+}
+
+// Exit is a sub process exit.
+type Exit struct {
+	PID      int
+	ExitCode int
+	// Reason is why the sub process exited, e.g. `ReasonUnhealthy`.
+	Reason string
+	// Failed is if the supervisor stopped the sub process because it failed, e.g. its health checks.
+	Failed   bool
+	Restarts int
+	Started  time.Time
+	Elapsed  time.Duration
+}
+
+// IsCrash returns if the sub process ended abnormally, i.e. it exited nonzero or failed.
+func (e Exit) IsCrash() bool {
+	return e.Failed || (e.Reason == ReasonExited && e.ExitCode != 0)
+}
@@ -0,0 +1,750 @@
+package proc
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/logger"
+)
+
+// NewSupervisor returns a new supervisor for a command.
+func NewSupervisor(command ...string) *Supervisor {
+	return &Supervisor{
+		command:         command,
+		restartPolicy:   RestartOnFailure,
+		gracePeriod:     DefaultGracePeriod,
+		killChildren:    true,
+		healthInterval:  DefaultHealthInterval,
+		healthTimeout:   DefaultHealthTimeout,
+		healthThreshold: DefaultHealthThreshold,
+		status:          Status{State: StateStopped},
+	}
+}
+
+// Supervisor runs a sub process, restarting it according to a restart policy when it exits,
+// and optionally when it fails health checks.
+type Supervisor struct {
+	sync.Mutex
+
+	command []string
+	dir     string
+	env     []string
+	stdout  io.Writer
+	stderr  io.Writer
+	log     *logger.Logger
+
+	restartPolicy RestartPolicy
+	delay         time.Duration
+	restartWait   time.Duration
+	backoff       time.Duration
+	backoffMax    time.Duration
+	maxRestarts   int
+	gracePeriod   time.Duration
+	killChildren  bool
+
+	healthCheck     HealthCheck
+	healthInterval  time.Duration
+	healthTimeout   time.Duration
+	healthThreshold int
+	healthDelay     time.Duration
+
+	onStart   func(pid, restarts int)
+	onExit    func(Exit)
+	onRestart func(restarts int, reason string)
+	onBackoff func(backoff time.Duration, restarts int)
+	onCrash   func(Exit)
+
+	sub      *exec.Cmd
+	status   Status
+	stop     chan os.Signal
+	reload   chan string
+	fail     chan string
+	done     chan struct{}
+	exitCode int
+	err      error
+}
+
+// WithDir sets the sub process working directory; by default it is the current working directory.
+func (s *Supervisor) WithDir(dir string) *Supervisor {
+	s.dir = dir
+	return s
+}
+
+// Dir returns the sub process working directory.
+func (s *Supervisor) Dir() string {
+	return s.dir
+}
+
+// WithEnv sets the sub process environment as KEY=VALUE strings; by default it is the current environment.
+func (s *Supervisor) WithEnv(env []string) *Supervisor {
+	s.env = env
+	return s
+}
+
+// Env returns the sub process environment.
+func (s *Supervisor) Env() []string {
+	return s.env
+}
+
+// WithStdout sets the writer for the sub process stdout; by default it is discarded.
+func (s *Supervisor) WithStdout(stdout io.Writer) *Supervisor {
+	s.stdout = stdout
+	return s
+}
+
+// Stdout returns the writer for the sub process stdout.
+func (s *Supervisor) Stdout() io.Writer {
+	return s.stdout
+}
+
+// WithStderr sets the writer for the sub process stderr; by default it is discarded.
+func (s *Supervisor) WithStderr(stderr io.Writer) *Supervisor {
+	s.stderr = stderr
+	return s
+}
+
+// Stderr returns the writer for the sub process stderr.
+func (s *Supervisor) Stderr() io.Writer {
+	return s.stderr
+}
+
+// WithLogger sets the logger for debug messages.
+func (s *Supervisor) WithLogger(log *logger.Logger) *Supervisor {
+	s.log = log
+	return s
+}
+
+// Logger returns the logger.
+func (s *Supervisor) Logger() *logger.Logger {
+	return s.log
+}
+
+// WithRestartPolicy sets the restart policy; by default it is `RestartOnFailure`.
+func (s *Supervisor) WithRestartPolicy(restartPolicy RestartPolicy) *Supervisor {
+	s.restartPolicy = restartPolicy
+	return s
+}
+
+// RestartPolicy returns the restart policy.
+func (s *Supervisor) RestartPolicy() RestartPolicy {
+	return s.restartPolicy
+}
+
+// WithDelay sets a time to wait before starting the sub process the first time.
+func (s *Supervisor) WithDelay(delay time.Duration) *Supervisor {
+	s.delay = delay
+	return s
+}
+
+// Delay returns the time to wait before starting the sub process the first time.
+func (s *Supervisor) Delay() time.Duration {
+	return s.delay
+}
+
+// WithRestartWait sets a fixed time to wait between restarts; it is ignored if a backoff is set.
+func (s *Supervisor) WithRestartWait(restartWait time.Duration) *Supervisor {
+	s.restartWait = restartWait
+	return s
+}
+
+// RestartWait returns the fixed time to wait between restarts.
+func (s *Supervisor) RestartWait() time.Duration {
+	return s.restartWait
+}
+
+// WithBackoff sets an initial time to wait before restarting, doubled on each consecutive restart.
+func (s *Supervisor) WithBackoff(backoff time.Duration) *Supervisor {
+	s.backoff = backoff
+	return s
+}
+
+// Backoff returns the initial time to wait before restarting.
+func (s *Supervisor) Backoff() time.Duration {
+	return s.backoff
+}
+
+// WithBackoffMax sets a maximum time to wait between restarts when backing off.
+// A sub process that runs longer than this resets the backoff and the restart count.
+func (s *Supervisor) WithBackoffMax(backoffMax time.Duration) *Supervisor {
+	s.backoffMax = backoffMax
+	return s
+}
+
+// BackoffMax returns the maximum time to wait between restarts when backing off.
+func (s *Supervisor) BackoffMax() time.Duration {
+	return s.backoffMax
+}
+
+// WithMaxRestarts sets a maximum number of consecutive restarts, after which the supervisor
+// stops with an `ErrRestartsExhausted` error; 0 restarts forever.
+func (s *Supervisor) WithMaxRestarts(maxRestarts int) *Supervisor {
+	s.maxRestarts = maxRestarts
+	return s
+}
+
+// MaxRestarts returns the maximum number of consecutive restarts.
+func (s *Supervisor) MaxRestarts() int {
+	return s.maxRestarts
+}
+
+// WithGracePeriod sets the time to wait for a stopped sub process to exit before killing it.
+func (s *Supervisor) WithGracePeriod(gracePeriod time.Duration) *Supervisor {
+	s.gracePeriod = gracePeriod
+	return s
+}
+
+// GracePeriod returns the time to wait for a stopped sub process to exit before killing it.
+func (s *Supervisor) GracePeriod() time.Duration {
+	return s.gracePeriod
+}
+
+// WithKillChildren sets if the sub process is started in its own process group, and the whole group
+// is signaled when stopping it, so processes it spawned don't outlive it. It is enabled by default.
+func (s *Supervisor) WithKillChildren(killChildren bool) *Supervisor {
+	s.killChildren = killChildren
+	return s
+}
+
+// KillChildren returns if the sub process group is signaled when stopping the sub process.
+func (s *Supervisor) KillChildren() bool {
+	return s.killChildren
+}
+
+// WithHealthCheck sets a health check; the sub process is restarted after it fails the health threshold consecutive times.
+func (s *Supervisor) WithHealthCheck(healthCheck HealthCheck) *Supervisor {
+	s.healthCheck = healthCheck
+	return s
+}
+
+// HealthCheck returns the health check.
+func (s *Supervisor) HealthCheck() HealthCheck {
+	return s.healthCheck
+}
+
+// WithHealthInterval sets the time between health checks.
+func (s *Supervisor) WithHealthInterval(healthInterval time.Duration) *Supervisor {
+	s.healthInterval = healthInterval
+	return s
+}
+
+// HealthInterval returns the time between health checks.
+func (s *Supervisor) HealthInterval() time.Duration {
+	return s.healthInterval
+}
+
+// WithHealthTimeout sets the time to wait for a health check.
+func (s *Supervisor) WithHealthTimeout(healthTimeout time.Duration) *Supervisor {
+	s.healthTimeout = healthTimeout
+	return s
+}
+
+// HealthTimeout returns the time to wait for a health check.
+func (s *Supervisor) HealthTimeout() time.Duration {
+	return s.healthTimeout
+}
+
+// WithHealthThreshold sets the number of consecutive failed health checks before restarting the sub process.
+func (s *Supervisor) WithHealthThreshold(healthThreshold int) *Supervisor {
+	s.healthThreshold = healthThreshold
+	return s
+}
+
+// HealthThreshold returns the number of consecutive failed health checks before restarting the sub process.
+func (s *Supervisor) HealthThreshold() int {
+	return s.healthThreshold
+}
+
+// WithHealthDelay sets a time to wait after starting the sub process before health checking.
+func (s *Supervisor) WithHealthDelay(healthDelay time.Duration) *Supervisor {
+	s.healthDelay = healthDelay
+	return s
+}
+
+// HealthDelay returns the time to wait after starting the sub process before health checking.
+func (s *Supervisor) HealthDelay() time.Duration {
+	return s.healthDelay
+}
+
+// WithOnStart sets a hook called after the sub process starts.
+func (s *Supervisor) WithOnStart(onStart func(pid, restarts int)) *Supervisor {
+	s.onStart = onStart
+	return s
+}
+
+// WithOnExit sets a hook called after the sub process exits, for any reason.
+func (s *Supervisor) WithOnExit(onExit func(Exit)) *Supervisor {
+	s.onExit = onExit
+	return s
+}
+
+// WithOnRestart sets a hook called before the sub process is restarted, with the reason it exited.
+func (s *Supervisor) WithOnRestart(onRestart func(restarts int, reason string)) *Supervisor {
+	s.onRestart = onRestart
+	return s
+}
+
+// WithOnBackoff sets a hook called before waiting to restart the sub process.
+func (s *Supervisor) WithOnBackoff(onBackoff func(backoff time.Duration, restarts int)) *Supervisor {
+	s.onBackoff = onBackoff
+	return s
+}
+
+// WithOnCrash sets a hook called after the sub process crashes, i.e. it exits nonzero or fails its health checks.
+func (s *Supervisor) WithOnCrash(onCrash func(Exit)) *Supervisor {
+	s.onCrash = onCrash
+	return s
+}
+
+// Command returns the command.
+func (s *Supervisor) Command() []string {
+	return s.command
+}
+
+// Status returns a snapshot of the supervisor state.
+func (s *Supervisor) Status() Status {
+	s.Lock()
+	defer s.Unlock()
+	status := s.status
+	if status.LastExit != nil {
+		lastExit := *status.LastExit
+		status.LastExit = &lastExit
+	}
+	return status
+}
+
+// IsRunning returns if the supervisor is running.
+func (s *Supervisor) IsRunning() bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.status.State != StateStopped
+}
+
+// Start starts supervising the sub process in the background.
+// Use `Done` or `ExitCode` to wait for the supervisor to stop on its own.
+func (s *Supervisor) Start() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.status.State != StateStopped {
+		return exception.New(ErrAlreadyRunning)
+	}
+	if len(s.command) == 0 {
+		return exception.New(ErrEmptyCommand)
+	}
+	if _, err := exec.LookPath(s.command[0]); err != nil {
+		return exception.New(err)
+	}
+
+	s.stop = make(chan os.Signal, 1)
+	s.reload = make(chan string, 1)
+	s.fail = make(chan string, 1)
+	s.done = make(chan struct{})
+	s.exitCode = 0
+	s.err = nil
+	s.status = Status{State: StateStarting}
+	go s.run()
+	return nil
+}
+
+// Stop stops the sub process with SIGTERM and waits for the supervisor to stop.
+func (s *Supervisor) Stop() {
+	s.StopWithSignal(syscall.SIGTERM)
+}
+
+// StopWithSignal stops the sub process with a signal, killing it if it hasn't exited after the
+// grace period, and waits for the supervisor to stop.
+func (s *Supervisor) StopWithSignal(sig os.Signal) {
+	s.Lock()
+	stop, done := s.stop, s.done
+	s.Unlock()
+	if done == nil {
+		return
+	}
+	select {
+	case stop <- sig:
+	default:
+	}
+	<-done
+}
+
+// Reload stops the sub process and restarts it immediately, resetting the restart count.
+// The exit reason is `ReasonChanged`.
+func (s *Supervisor) Reload() {
+	s.ReloadWithReason(ReasonChanged)
+}
+
+// ReloadWithReason stops the sub process and restarts it immediately, resetting the restart count,
+// with a given exit reason, e.g. `ReasonScheduled`.
+func (s *Supervisor) ReloadWithReason(reason string) {
+	s.Lock()
+	reload := s.reload
+	s.Unlock()
+	if reload == nil {
+		return
+	}
+	select {
+	case reload <- reason:
+	default:
+	}
+}
+
+// Fail stops the sub process and restarts it as if it had crashed, i.e. after the restart delay
+// and counting toward the max restarts, with a given exit reason, e.g. `ReasonMatched`.
+// It is ignored if the sub process is not running.
+func (s *Supervisor) Fail(reason string) {
+	s.Lock()
+	fail := s.fail
+	running := s.sub != nil
+	s.Unlock()
+	if !running {
+		return
+	}
+	select {
+	case fail <- reason:
+	default:
+	}
+}
+
+// Signal sends a signal to the running sub process.
+func (s *Supervisor) Signal(sig os.Signal) error {
+	s.Lock()
+	defer s.Unlock()
+	if s.sub == nil {
+		return exception.New(ErrNotRunning)
+	}
+	return s.sub.Process.Signal(sig)
+}
+
+// Done returns a channel that is closed when the supervisor stops.
+func (s *Supervisor) Done() <-chan struct{} {
+	s.Lock()
+	defer s.Unlock()
+	return s.done
+}
+
+// ExitCode waits for the supervisor to stop, returning the exit code of the last sub process
+// if the restart policy stopped restarting it, and an error if the supervisor failed.
+func (s *Supervisor) ExitCode() (int, error) {
+	done := s.Done()
+	if done == nil {
+		return 0, exception.New(ErrNotRunning)
+	}
+	<-done
+	s.Lock()
+	defer s.Unlock()
+	return s.exitCode, s.err
+}
+
+// run runs the sub process until it exits with a code the restart policy exits on, the restarts are
+// exhausted, or the supervisor is stopped.
+func (s *Supervisor) run() {
+	exitCode, err := s.runLoop()
+	s.Lock()
+	s.exitCode = exitCode
+	s.err = err
+	s.status.State = StateStopped
+	s.status.PID = 0
+	close(s.done)
+	s.Unlock()
+}
+
+func (s *Supervisor) runLoop() (int, error) {
+	if s.delay > 0 {
+		s.debugf("delaying %v before starting", s.delay)
+		if s.sleep(s.delay) == ReasonQuit {
+			return 0, nil
+		}
+	}
+
+	var restarts int
+	var reason string
+	for {
+		if restarts > 0 || reason != "" {
+			if s.onRestart != nil {
+				s.onRestart(restarts, reason)
+			}
+		}
+
+		// a failure reported after the previous sub process exited doesn't apply to the next one.
+		select {
+		case <-s.fail:
+		default:
+		}
+
+		started := time.Now().UTC()
+		sub, stdout, stderr, err := s.createSub()
+		if err != nil {
+			return 0, exception.New(err)
+		}
+		if err := sub.Start(); err != nil {
+			stdout.close()
+			stderr.close()
+			return 0, exception.New(err)
+		}
+		stdout.started()
+		stderr.started()
+		s.setRunning(sub, restarts, started)
+		if s.onStart != nil {
+			s.onStart(sub.Process.Pid, restarts)
+		}
+
+		var failed bool
+		reason, failed = s.monitorSub(sub)
+		s.killGroup(sub)
+		stdout.wait(DefaultOutputWaitDelay)
+		stderr.wait(DefaultOutputWaitDelay)
+		exit := Exit{
+			PID:      sub.Process.Pid,
+			ExitCode: sub.ProcessState.ExitCode(),
+			Reason:   reason,
+			Failed:   failed,
+			Restarts: restarts,
+			Started:  started,
+			Elapsed:  time.Since(started),
+		}
+		s.setExited(exit)
+		if s.onExit != nil {
+			s.onExit(exit)
+		}
+		if exit.IsCrash() && s.onCrash != nil {
+			s.onCrash(exit)
+		}
+
+		switch {
+		case reason == ReasonQuit:
+			return 0, nil
+		case reason == ReasonExited:
+			if !s.restartPolicy(exit.ExitCode) {
+				s.debugf("sub process exited with code %d, exiting", exit.ExitCode)
+				return exit.ExitCode, nil
+			}
+		case !failed:
+			// the sub process was reloaded.
+			restarts = 0
+			continue
+		}
+
+		// a sub process that stayed up longer than the max backoff is healthy again.
+		if s.backoffMax > 0 && exit.Elapsed >= s.backoffMax {
+			restarts = 0
+		}
+		if s.maxRestarts > 0 && restarts >= s.maxRestarts {
+			return 0, exception.New(ErrRestartsExhausted).WithMessagef("sub process failed %d consecutive times, giving up", restarts+1)
+		}
+
+		waitFor := s.restartDelay(restarts)
+		restarts++
+		s.setBackoff(restarts)
+		if waitFor > 0 {
+			if s.onBackoff != nil {
+				s.onBackoff(waitFor, restarts)
+			}
+			switch slept := s.sleep(waitFor); slept {
+			case "":
+			case ReasonQuit:
+				s.debugf("stopped during wait, exiting")
+				return 0, nil
+			default:
+				restarts, reason = 0, slept
+			}
+		}
+	}
+}
+
+// createSub returns the sub process command and its outputs; the outputs must be started or closed.
+func (s *Supervisor) createSub() (*exec.Cmd, *subOutput, *subOutput, error) {
+	stdout, err := newSubOutput(s.stdout)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stderr, err := newSubOutput(s.stderr)
+	if err != nil {
+		stdout.close()
+		return nil, nil, nil, err
+	}
+
+	sub := exec.Command(s.command[0], s.command[1:]...)
+	sub.Env = s.env
+	sub.Dir = s.dir
+	// files are passed to the sub process as is; an untyped nil discards the output.
+	if stdout.file != nil {
+		sub.Stdout = stdout.file
+	}
+	if stderr.file != nil {
+		sub.Stderr = stderr.file
+	}
+	if s.killChildren {
+		setProcessGroup(sub)
+	}
+	return sub, stdout, stderr, nil
+}
+
+// monitorSub waits for a started sub process to exit. If the supervisor is stopped or reloaded,
+// or the sub process fails, the sub process is stopped; it returns the exit reason and if the sub process failed.
+func (s *Supervisor) monitorSub(sub *exec.Cmd) (string, bool) {
+	exited := make(chan error, 1)
+	go func() {
+		exited <- sub.Wait()
+	}()
+
+	done := make(chan struct{})
+	defer close(done)
+	unhealthy := s.checkHealth(done)
+
+	select {
+	case err := <-exited:
+		if err != nil {
+			s.debugf("sub process exit: %v", err)
+		}
+		return ReasonExited, false
+	case <-unhealthy:
+		s.debugf("sub process is unhealthy, restarting sub process")
+		s.stopSub(sub, syscall.SIGTERM, exited)
+		return ReasonUnhealthy, true
+	case reason := <-s.fail:
+		s.debugf("sub process failed (%s), restarting sub process", reason)
+		s.stopSub(sub, syscall.SIGTERM, exited)
+		return reason, true
+	case reason := <-s.reload:
+		s.debugf("reloading (%s), restarting sub process", reason)
+		s.stopSub(sub, syscall.SIGTERM, exited)
+		return reason, false
+	case sig := <-s.stop:
+		s.debugf("stopping sub process with %v", sig)
+		s.stopSub(sub, sig, exited)
+		return ReasonQuit, false
+	}
+}
+
+// checkHealth starts health checking if a health check is set, returning a channel that is closed
+// once the sub process has failed the health threshold consecutive checks.
+// Health checking stops when done is closed.
+func (s *Supervisor) checkHealth(done chan struct{}) chan struct{} {
+	unhealthy := make(chan struct{})
+	if s.healthCheck == nil {
+		return unhealthy
+	}
+
+	go func() {
+		if s.healthDelay > 0 {
+			select {
+			case <-done:
+				return
+			case <-time.After(s.healthDelay):
+			}
+		}
+		ticker := time.NewTicker(s.healthInterval)
+		defer ticker.Stop()
+
+		var failures int
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if err := s.probe(); err != nil {
+				failures++
+				s.debugf("health check failed (%d/%d): %v", failures, s.healthThreshold, err)
+				if failures >= s.healthThreshold {
+					close(unhealthy)
+					return
+				}
+				continue
+			}
+			failures = 0
+		}
+	}()
+	return unhealthy
+}
+
+func (s *Supervisor) probe() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.healthTimeout)
+	defer cancel()
+	return s.healthCheck(ctx)
+}
+
+// stopSub signals the sub process and waits for it to exit, killing it if it hasn't exited after the grace period.
+func (s *Supervisor) stopSub(sub *exec.Cmd, sig os.Signal, exited chan error) {
+	s.signalGroup(sub, sig)
+	select {
+	case <-exited:
+	case <-time.After(s.gracePeriod):
+		s.debugf("sub process did not exit within %v, killing sub process", s.gracePeriod)
+		s.signalGroup(sub, syscall.SIGKILL)
+		<-exited
+	}
+}
+
+// sleep waits for a duration, returning `ReasonQuit` if the supervisor was stopped
+// or the reload reason if it was reloaded, which cuts the wait short.
+func (s *Supervisor) sleep(d time.Duration) string {
+	select {
+	case <-time.After(d):
+		return ""
+	case reason := <-s.reload:
+		return reason
+	case <-s.stop:
+		return ReasonQuit
+	}
+}
+
+// restartDelay returns the time to wait before a restart given the number of
+// consecutive restarts so far, backing off exponentially if a backoff is set.
+func (s *Supervisor) restartDelay(restarts int) time.Duration {
+	if s.backoff <= 0 {
+		return s.restartWait
+	}
+	delay := s.backoff
+	for x := 0; x < restarts; x++ {
+		delay = delay * 2
+		if s.backoffMax > 0 && delay >= s.backoffMax {
+			return s.backoffMax
+		}
+	}
+	if s.backoffMax > 0 && delay > s.backoffMax {
+		return s.backoffMax
+	}
+	return delay
+}
+
+func (s *Supervisor) setRunning(sub *exec.Cmd, restarts int, started time.Time) {
+	s.Lock()
+	defer s.Unlock()
+	s.sub = sub
+	if s.status.LastExit != nil {
+		s.status.TotalRestarts++
+	}
+	s.status.State = StateRunning
+	s.status.PID = sub.Process.Pid
+	s.status.Restarts = restarts
+	s.status.Started = started
+}
+
+func (s *Supervisor) setExited(exit Exit) {
+	s.Lock()
+	defer s.Unlock()
+	s.sub = nil
+	s.status.PID = 0
+	s.status.Started = time.Time{}
+	s.status.LastExit = &exit
+}
+
+func (s *Supervisor) setBackoff(restarts int) {
+	s.Lock()
+	defer s.Unlock()
+	s.status.State = StateBackoff
+	s.status.Restarts = restarts
+}
+
+func (s *Supervisor) debugf(format string, args ...interface{}) {
+	if s.log != nil {
+		s.log.SyncDebugf(format, args...)
+	}
+}
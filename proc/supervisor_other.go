@@ -0,0 +1,31 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on platforms without process groups.
+func setProcessGroup(sub *exec.Cmd) {}
+
+// signalGroup sends a signal to the sub process; there are no process groups
+// to signal on this platform, so children of the sub process are not signaled.
+func (s *Supervisor) signalGroup(sub *exec.Cmd, sig os.Signal) {
+	if err := sub.Process.Signal(sig); err != nil {
+		s.debugf("signaling sub process: %v", err)
+	}
+}
+
+// killGroup kills the sub process if it is still running; there are no process groups
+// to kill on this platform, so children of the sub process are left running.
+func (s *Supervisor) killGroup(sub *exec.Cmd) {
+	if !s.killChildren {
+		return
+	}
+	if err := sub.Process.Kill(); err == nil {
+		s.debugf("killed the sub process")
+	}
+}
@@ -0,0 +1,282 @@
+package proc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+// waitFor polls a condition until it is true or a timeout elapses.
+func waitFor(condition func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return false
+}
+
+// lockedBuffer is a buffer safe to write to from the sub process output copier.
+type lockedBuffer struct {
+	sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(contents []byte) (int, error) {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.buffer.Write(contents)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.buffer.String()
+}
+
+func TestSupervisorExitCode(t *testing.T) {
+	assert := assert.New(t)
+
+	stdout := new(lockedBuffer)
+	s := NewSupervisor("sh", "-c", "echo hello; exit 3").
+		WithStdout(stdout).
+		WithRestartPolicy(ExitOnCodes(3))
+	assert.Nil(s.Start())
+
+	exitCode, err := s.ExitCode()
+	assert.Nil(err)
+	assert.Equal(3, exitCode)
+	assert.Equal("hello\n", stdout.String())
+
+	status := s.Status()
+	assert.Equal(StateStopped, status.State)
+	assert.NotNil(status.LastExit)
+	assert.Equal(ReasonExited, status.LastExit.Reason)
+	assert.True(status.LastExit.IsCrash())
+}
+
+func TestSupervisorExitWithBackgroundedChild(t *testing.T) {
+	assert := assert.New(t)
+
+	// the backgrounded sleep holds the sub process stdout open after the sub process exits.
+	stdout := new(lockedBuffer)
+	s := NewSupervisor("sh", "-c", "echo hello; sleep 30 & exit 3").
+		WithStdout(stdout).
+		WithStderr(new(lockedBuffer)).
+		WithRestartPolicy(ExitOnCodes(3))
+	assert.Nil(s.Start())
+
+	assert.True(waitFor(func() bool { return !s.IsRunning() }), "the supervisor should not wait for the backgrounded child")
+	exitCode, err := s.ExitCode()
+	assert.Nil(err)
+	assert.Equal(3, exitCode)
+	assert.Equal("hello\n", stdout.String())
+	assert.NotNil(s.Status().LastExit)
+}
+
+func TestSupervisorExitWithBackgroundedChildNotKilled(t *testing.T) {
+	assert := assert.New(t)
+
+	stdout := new(lockedBuffer)
+	s := NewSupervisor("sh", "-c", "echo hello; sleep 3 & exit 3").
+		WithStdout(stdout).
+		WithKillChildren(false).
+		WithRestartPolicy(ExitOnCodes(3))
+	assert.Nil(s.Start())
+
+	// the output is closed after a delay if processes the sub process started still hold it open.
+	assert.True(waitFor(func() bool { return !s.IsRunning() }), "the supervisor should not wait for the backgrounded child")
+	exitCode, err := s.ExitCode()
+	assert.Nil(err)
+	assert.Equal(3, exitCode)
+	assert.Equal("hello\n", stdout.String())
+}
+
+func TestSupervisorStartErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NotNil(NewSupervisor().Start())
+	assert.NotNil(NewSupervisor("not-a-real-binary-for-sure").Start())
+
+	_, err := NewSupervisor("true").ExitCode()
+	assert.NotNil(err)
+
+	s := NewSupervisor("sleep", "10")
+	assert.Nil(s.Start())
+	defer s.Stop()
+	assert.NotNil(s.Start())
+}
+
+func TestSupervisorRestartsExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var starts, crashes int
+	var restarts []int
+	s := NewSupervisor("sh", "-c", "exit 1").
+		WithMaxRestarts(2).
+		WithOnStart(func(_, _ int) { starts++ }).
+		WithOnRestart(func(restartCount int, reason string) {
+			restarts = append(restarts, restartCount)
+			assert.Equal(ReasonExited, reason)
+		}).
+		WithOnCrash(func(exit Exit) {
+			crashes++
+			assert.Equal(1, exit.ExitCode)
+		})
+	assert.Nil(s.Start())
+
+	_, err := s.ExitCode()
+	assert.True(IsRestartsExhausted(err))
+	assert.Equal(3, starts)
+	assert.Equal(3, crashes)
+	assert.Equal([]int{1, 2}, restarts)
+	assert.Equal(2, s.Status().TotalRestarts)
+}
+
+func TestSupervisorStop(t *testing.T) {
+	assert := assert.New(t)
+
+	var exits []Exit
+	s := NewSupervisor("sleep", "10").
+		WithOnExit(func(exit Exit) { exits = append(exits, exit) })
+	assert.Nil(s.Start())
+	assert.True(waitFor(func() bool { return s.Status().State == StateRunning }))
+	assert.True(s.IsRunning())
+	assert.NotZero(s.Status().PID)
+	assert.Nil(s.Signal(syscall.Signal(0)))
+
+	s.Stop()
+	assert.False(s.IsRunning())
+	exitCode, err := s.ExitCode()
+	assert.Nil(err)
+	assert.Zero(exitCode)
+	assert.Len(exits, 1)
+	assert.Equal(ReasonQuit, exits[0].Reason)
+	assert.False(exits[0].IsCrash())
+	assert.NotNil(s.Signal(syscall.Signal(0)))
+}
+
+func TestSupervisorReload(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var pids []int
+	var reasons []string
+	s := NewSupervisor("sleep", "10").
+		WithOnStart(func(pid, _ int) {
+			lock.Lock()
+			pids = append(pids, pid)
+			lock.Unlock()
+		}).
+		WithOnRestart(func(_ int, reason string) {
+			lock.Lock()
+			reasons = append(reasons, reason)
+			lock.Unlock()
+		})
+	assert.Nil(s.Start())
+	defer s.Stop()
+
+	assert.True(waitFor(func() bool { return s.Status().State == StateRunning }))
+	s.Reload()
+	assert.True(waitFor(func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return len(pids) == 2
+	}))
+	lock.Lock()
+	defer lock.Unlock()
+	assert.NotEqual(pids[0], pids[1])
+	assert.Equal([]string{ReasonChanged}, reasons)
+}
+
+func TestSupervisorHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	var checks int
+	var lock sync.Mutex
+	var exits []Exit
+	s := NewSupervisor("sleep", "10").
+		WithMaxRestarts(1).
+		WithHealthInterval(time.Millisecond).
+		WithHealthThreshold(2).
+		WithHealthCheck(func(_ context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			checks++
+			return fmt.Errorf("unhealthy")
+		}).
+		WithOnExit(func(exit Exit) {
+			lock.Lock()
+			defer lock.Unlock()
+			exits = append(exits, exit)
+		})
+	assert.Nil(s.Start())
+
+	_, err := s.ExitCode()
+	assert.True(IsRestartsExhausted(err))
+	lock.Lock()
+	defer lock.Unlock()
+	assert.True(checks >= 4)
+	assert.Len(exits, 2)
+	assert.Equal(ReasonUnhealthy, exits[0].Reason)
+	assert.True(exits[0].IsCrash())
+}
+
+func TestSupervisorRestartDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSupervisor("true")
+	assert.Zero(s.restartDelay(3))
+
+	s.WithRestartWait(time.Second)
+	assert.Equal(time.Second, s.restartDelay(3))
+
+	s.WithBackoff(100 * time.Millisecond)
+	assert.Equal(100*time.Millisecond, s.restartDelay(0))
+	assert.Equal(800*time.Millisecond, s.restartDelay(3))
+
+	s.WithBackoffMax(time.Second)
+	assert.Equal(time.Second, s.restartDelay(10))
+}
+
+func TestSupervisorFail(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var exits []Exit
+	s := NewSupervisor("sleep", "10").
+		WithMaxRestarts(1).
+		WithOnExit(func(exit Exit) {
+			lock.Lock()
+			defer lock.Unlock()
+			exits = append(exits, exit)
+		})
+	s.Fail(ReasonMatched) // ignored, the sub process isn't running.
+	assert.Nil(s.Start())
+
+	for x := 0; x < 2; x++ {
+		assert.True(waitFor(func() bool { return s.Status().PID != 0 }))
+		s.Fail(ReasonMatched)
+		assert.True(waitFor(func() bool {
+			lock.Lock()
+			defer lock.Unlock()
+			return len(exits) == x+1
+		}))
+	}
+
+	_, err := s.ExitCode()
+	assert.True(IsRestartsExhausted(err))
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal(ReasonMatched, exits[0].Reason)
+	assert.True(exits[0].Failed)
+	assert.True(exits[0].IsCrash())
+}
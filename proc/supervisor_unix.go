@@ -0,0 +1,42 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts the sub process in its own process group, so the sub process
+// and any children it starts can be signaled together.
+func setProcessGroup(sub *exec.Cmd) {
+	sub.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalGroup sends a signal to the sub process group if kill children is set,
+// falling back to just the sub process otherwise.
+func (s *Supervisor) signalGroup(sub *exec.Cmd, sig os.Signal) {
+	sysSig, isSysSig := sig.(syscall.Signal)
+	if !s.killChildren || !isSysSig {
+		if err := sub.Process.Signal(sig); err != nil {
+			s.debugf("signaling sub process: %v", err)
+		}
+		return
+	}
+	if err := syscall.Kill(-sub.Process.Pid, sysSig); err != nil {
+		s.debugf("signaling sub process group: %v", err)
+	}
+}
+
+// killGroup kills any processes left in the sub process group after the sub process has exited,
+// so they don't survive a restart as orphans.
+func (s *Supervisor) killGroup(sub *exec.Cmd) {
+	if !s.killChildren {
+		return
+	}
+	if err := syscall.Kill(-sub.Process.Pid, syscall.SIGKILL); err == nil {
+		s.debugf("killed processes left in the sub process group")
+	}
+}
@@ -0,0 +1,7 @@
+package proc
+
+// Error is an error string.
+type Error string
+
+// Error implements error.
+func (e Error) Error() string { return string(e) }
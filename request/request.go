@@ -19,6 +19,7 @@ import (
 
 	"github.com/blend/go-sdk/exception"
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
 )
 
 // Get returns a new get request.
@@ -654,6 +655,9 @@ func (r *Request) Request() (*http.Request, error) {
 			req.Header.Set(key, value)
 		}
 	}
+	if requestID := webutil.GetRequestID(req.Context()); len(requestID) > 0 && len(req.Header.Get(webutil.HeaderXRequestID)) == 0 {
+		req.Header.Set(webutil.HeaderXRequestID, requestID)
+	}
 	return req, nil
 }
 
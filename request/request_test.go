@@ -18,6 +18,7 @@ import (
 	"github.com/blend/go-sdk/assert"
 	"github.com/blend/go-sdk/exception"
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
 )
 
 type statusObject struct {
@@ -533,3 +534,20 @@ func TestRequestTracer(t *testing.T) {
 	wg.Wait()
 	assert.True(hasValue)
 }
+
+func TestRequestForwardsRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := webutil.WithRequestID(context.Background(), "abc123")
+	req, err := New().MustWithRawURL("http://localhost/foo").WithContext(ctx).Request()
+	assert.Nil(err)
+	assert.Equal("abc123", req.Header.Get(webutil.HeaderXRequestID))
+
+	req, err = New().MustWithRawURL("http://localhost/foo").WithContext(ctx).WithHeader(webutil.HeaderXRequestID, "explicit").Request()
+	assert.Nil(err)
+	assert.Equal("explicit", req.Header.Get(webutil.HeaderXRequestID))
+
+	req, err = New().MustWithRawURL("http://localhost/foo").Request()
+	assert.Nil(err)
+	assert.Empty(req.Header.Get(webutil.HeaderXRequestID))
+}
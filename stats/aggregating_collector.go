@@ -0,0 +1,230 @@
+package stats
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// DefaultAggregatingFlushInterval is the default interval aggregated percentiles are flushed on.
+	DefaultAggregatingFlushInterval = 10 * time.Second
+	// DefaultAggregatingMaxSamples is the default maximum number of samples kept per metric between flushes.
+	DefaultAggregatingMaxSamples = 1024
+)
+
+// Assert the aggregating collector is a collector.
+var (
+	_ Collector = (*AggregatingCollector)(nil)
+)
+
+// NewAggregatingCollector returns a new aggregating collector that writes to a collector.
+// It must be started with `.Start()` to flush on the interval.
+func NewAggregatingCollector(collector Collector) *AggregatingCollector {
+	return &AggregatingCollector{
+		collector:     collector,
+		flushInterval: DefaultAggregatingFlushInterval,
+		maxSamples:    DefaultAggregatingMaxSamples,
+		samples:       map[string]*sampleSet{},
+		random:        rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// AggregatingCollector buckets histogram and timing samples in process and flushes
+// `.p50`, `.p95`, `.p99` and `.max` gauges and a `.count` count per metric and tag set on an interval,
+// for environments where emitting every sample overwhelms the local agent.
+// Counts and gauges are passed through to the underlying collector.
+// If more than the max samples are observed between flushes, a uniform random sample is kept.
+type AggregatingCollector struct {
+	sync.Mutex
+
+	collector     Collector
+	flushInterval time.Duration
+	maxSamples    int
+
+	samples map[string]*sampleSet
+	random  *rand.Rand
+
+	errors  chan error
+	flusher *async.Interval
+}
+
+// sampleSet is the samples for a metric name and tag set.
+type sampleSet struct {
+	name   string
+	tags   []string
+	values []float64
+	count  int64
+}
+
+// Collector returns the underlying collector.
+func (ac *AggregatingCollector) Collector() Collector {
+	return ac.collector
+}
+
+// WithFlushInterval sets the flush interval.
+func (ac *AggregatingCollector) WithFlushInterval(flushInterval time.Duration) *AggregatingCollector {
+	ac.flushInterval = flushInterval
+	return ac
+}
+
+// FlushInterval returns the flush interval.
+func (ac *AggregatingCollector) FlushInterval() time.Duration {
+	return ac.flushInterval
+}
+
+// WithMaxSamples sets the maximum number of samples kept per metric between flushes.
+func (ac *AggregatingCollector) WithMaxSamples(maxSamples int) *AggregatingCollector {
+	ac.maxSamples = maxSamples
+	return ac
+}
+
+// MaxSamples returns the maximum number of samples kept per metric between flushes.
+func (ac *AggregatingCollector) MaxSamples() int {
+	return ac.maxSamples
+}
+
+// WithErrors sets a channel that background flush errors are sent to.
+func (ac *AggregatingCollector) WithErrors(errors chan error) *AggregatingCollector {
+	ac.errors = errors
+	return ac
+}
+
+// Errors returns the background flush error channel.
+func (ac *AggregatingCollector) Errors() chan error {
+	return ac.errors
+}
+
+// AddDefaultTag adds a default tag to the underlying collector.
+func (ac *AggregatingCollector) AddDefaultTag(key, value string) {
+	ac.collector.AddDefaultTag(key, value)
+}
+
+// DefaultTags returns the default tags of the underlying collector.
+func (ac *AggregatingCollector) DefaultTags() []string {
+	return ac.collector.DefaultTags()
+}
+
+// Count increments a counter on the underlying collector.
+func (ac *AggregatingCollector) Count(name string, value int64, tags ...string) error {
+	return ac.collector.Count(name, value, tags...)
+}
+
+// Increment increments a counter on the underlying collector.
+func (ac *AggregatingCollector) Increment(name string, tags ...string) error {
+	return ac.collector.Increment(name, tags...)
+}
+
+// Gauge sets a gauge on the underlying collector.
+func (ac *AggregatingCollector) Gauge(name string, value float64, tags ...string) error {
+	return ac.collector.Gauge(name, value, tags...)
+}
+
+// Histogram adds a sample to be aggregated.
+func (ac *AggregatingCollector) Histogram(name string, value float64, tags ...string) error {
+	ac.observe(name, value, tags)
+	return nil
+}
+
+// TimeInMilliseconds adds a timing sample, in milliseconds, to be aggregated.
+func (ac *AggregatingCollector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	ac.observe(name, float64(value)/float64(time.Millisecond), tags)
+	return nil
+}
+
+// Start starts flushing on the flush interval.
+func (ac *AggregatingCollector) Start() *AggregatingCollector {
+	ac.Lock()
+	defer ac.Unlock()
+	if ac.flusher != nil {
+		return ac
+	}
+	ac.flusher = async.NewInterval(ac.Flush, ac.flushInterval).WithErrors(ac.errors)
+	ac.flusher.Start()
+	return ac
+}
+
+// Stop stops the background flush and flushes any pending samples.
+func (ac *AggregatingCollector) Stop() error {
+	ac.Lock()
+	flusher := ac.flusher
+	ac.flusher = nil
+	ac.Unlock()
+
+	if flusher != nil {
+		flusher.Stop()
+	}
+	return ac.Flush()
+}
+
+// Flush writes the aggregated percentiles for the samples since the last flush.
+func (ac *AggregatingCollector) Flush() error {
+	ac.Lock()
+	samples := ac.samples
+	ac.samples = map[string]*sampleSet{}
+	ac.Unlock()
+
+	keys := make([]string, 0, len(samples))
+	for key := range samples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, key := range keys {
+		set := samples[key]
+		sort.Float64s(set.values)
+		collect(ac.collector.Gauge(set.name+".p50", percentileFloat64(set.values, 0.50), set.tags...))
+		collect(ac.collector.Gauge(set.name+".p95", percentileFloat64(set.values, 0.95), set.tags...))
+		collect(ac.collector.Gauge(set.name+".p99", percentileFloat64(set.values, 0.99), set.tags...))
+		collect(ac.collector.Gauge(set.name+".max", set.values[len(set.values)-1], set.tags...))
+		collect(ac.collector.Count(set.name+".count", set.count, set.tags...))
+	}
+	if len(errs) > 0 {
+		return exception.Nest(errs...)
+	}
+	return nil
+}
+
+func (ac *AggregatingCollector) observe(name string, value float64, tags []string) {
+	key := name + "|" + strings.Join(tags, ",")
+
+	ac.Lock()
+	defer ac.Unlock()
+	set, ok := ac.samples[key]
+	if !ok {
+		set = &sampleSet{name: name, tags: append([]string(nil), tags...)}
+		ac.samples[key] = set
+	}
+	set.count++
+	if len(set.values) < ac.maxSamples {
+		set.values = append(set.values, value)
+		return
+	}
+	// reservoir sampling keeps each observed value with equal probability.
+	if index := ac.random.Int63n(set.count); index < int64(len(set.values)) {
+		set.values[index] = value
+	}
+}
+
+// percentileFloat64 returns the nearest rank percentile of sorted values.
+func percentileFloat64(sorted []float64, p float64) float64 {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestAggregatingCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &MockCollector{Events: make(chan MockMetric, 16)}
+	collector := NewAggregatingCollector(mock)
+
+	for x := 1; x <= 100; x++ {
+		assert.Nil(collector.TimeInMilliseconds("elapsed", time.Duration(x)*time.Millisecond, "route:/"))
+	}
+	assert.Nil(collector.Increment("requests"))
+	assert.Equal("requests", (<-mock.Events).Name)
+	assert.Empty(mock.Events)
+
+	assert.Nil(collector.Flush())
+	expected := []MockMetric{
+		{Name: "elapsed.p50", Gauge: 50, Tags: []string{"route:/"}},
+		{Name: "elapsed.p95", Gauge: 95, Tags: []string{"route:/"}},
+		{Name: "elapsed.p99", Gauge: 99, Tags: []string{"route:/"}},
+		{Name: "elapsed.max", Gauge: 100, Tags: []string{"route:/"}},
+		{Name: "elapsed.count", Count: 100, Tags: []string{"route:/"}},
+	}
+	for _, metric := range expected {
+		assert.Equal(metric, <-mock.Events)
+	}
+
+	assert.Nil(collector.Flush())
+	assert.Empty(mock.Events)
+}
+
+func TestAggregatingCollectorMaxSamples(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &MockCollector{Events: make(chan MockMetric, 16)}
+	collector := NewAggregatingCollector(mock).WithMaxSamples(10)
+	for x := 0; x < 1000; x++ {
+		assert.Nil(collector.Histogram("size", float64(x)))
+	}
+	assert.Len(collector.samples["size|"].values, 10)
+	assert.Equal(1000, collector.samples["size|"].count)
+}
+
+func TestAggregatingCollectorStartStop(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := &MockCollector{Events: make(chan MockMetric, 16)}
+	collector := NewAggregatingCollector(mock).WithFlushInterval(time.Millisecond).Start()
+	assert.Nil(collector.Histogram("size", 1))
+	assert.Equal("size.p50", (<-mock.Events).Name)
+	assert.Nil(collector.Stop())
+}
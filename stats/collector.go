@@ -1,5 +1,7 @@
 package stats
 
+import "time"
+
 // Collector is a stats collector.
 type Collector interface {
 	AddDefaultTag(string, string)
@@ -8,4 +10,5 @@ type Collector interface {
 	Increment(name string, tags ...string) error
 	Gauge(name string, value float64, tags ...string) error
 	Histogram(name string, value float64, tags ...string) error
+	TimeInMilliseconds(name string, value time.Duration, tags ...string) error
 }
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/util"
+)
+
+// NewConfigFromEnv returns a new config from the env.
+func NewConfigFromEnv() (*Config, error) {
+	var config Config
+	if err := env.Env().ReadInto(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// MustNewConfigFromEnv creates a new config from the environment and panics on error.
+func MustNewConfigFromEnv() (config *Config) {
+	var err error
+	if config, err = NewConfigFromEnv(); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Config is the common stats collector config.
+// Use it with a collector package's `NewCollectorFromConfig`, e.g. `dogstatsd.NewCollectorFromConfig`,
+// which returns a no-op collector when stats are disabled.
+type Config struct {
+	// Enabled indicates if stats are collected; it defaults to true if an address is set.
+	Enabled *bool `json:"enabled,omitempty" yaml:"enabled,omitempty" env:"STATS_ENABLED"`
+	// Addr is the collector address.
+	Addr string `json:"addr,omitempty" yaml:"addr,omitempty" env:"STATS_ADDR"`
+	// Namespace is an optional prefix for metric names.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty" env:"STATS_NAMESPACE"`
+	// DefaultTags are the default tags associated with any metric.
+	DefaultTags []string `json:"defaultTags,omitempty" yaml:"defaultTags,omitempty" env:"STATS_TAGS,csv"`
+	// FlushInterval is the interval buffered metrics are flushed on.
+	FlushInterval time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"STATS_FLUSH_INTERVAL"`
+}
+
+// GetEnabled returns if stats are enabled.
+func (c Config) GetEnabled() bool {
+	if c.Enabled != nil {
+		return *c.Enabled
+	}
+	return len(c.Addr) > 0
+}
+
+// GetAddr returns the collector address.
+func (c Config) GetAddr(defaults ...string) string {
+	return util.Coalesce.String(c.Addr, "", defaults...)
+}
+
+// GetNamespace returns the prefix for metric names.
+func (c Config) GetNamespace(defaults ...string) string {
+	return util.Coalesce.String(c.Namespace, "", defaults...)
+}
+
+// GetDefaultTags returns the default tags.
+func (c Config) GetDefaultTags(defaults ...[]string) []string {
+	return util.Coalesce.Strings(c.DefaultTags, nil, defaults...)
+}
+
+// GetFlushInterval returns the flush interval.
+func (c Config) GetFlushInterval(defaults ...time.Duration) time.Duration {
+	return util.Coalesce.Duration(c.FlushInterval, 0, defaults...)
+}
@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+func TestConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(Config{}.GetEnabled())
+	assert.True(Config{Addr: "127.0.0.1:8125"}.GetEnabled())
+	disabled := false
+	assert.False(Config{Addr: "127.0.0.1:8125", Enabled: &disabled}.GetEnabled())
+
+	env.Env().Set("STATS_ADDR", "127.0.0.1:8125")
+	defer env.Env().Restore("STATS_ADDR")
+	env.Env().Set("STATS_TAGS", "env:test,team:platform")
+	defer env.Env().Restore("STATS_TAGS")
+	env.Env().Set("STATS_FLUSH_INTERVAL", "5s")
+	defer env.Env().Restore("STATS_FLUSH_INTERVAL")
+
+	cfg := MustNewConfigFromEnv()
+	assert.True(cfg.GetEnabled())
+	assert.Equal("127.0.0.1:8125", cfg.GetAddr())
+	assert.Equal([]string{"env:test", "team:platform"}, cfg.GetDefaultTags())
+	assert.Equal(5*time.Second, cfg.GetFlushInterval())
+}
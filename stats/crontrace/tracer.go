@@ -10,6 +10,8 @@ import (
 )
 
 // Tracer returns a opentracing cron tracer.
+// Spans are `job` operations tagged with the job name, elapsed time, and any error.
+// Set it on a job manager with `jm.WithTracer(crontrace.Tracer(tracer))`.
 func Tracer(t opentracing.Tracer) cron.Tracer {
 	return &tracer{tracer: t}
 }
@@ -19,17 +21,20 @@ type tracer struct {
 }
 
 func (t tracer) Start(ctx context.Context, task cron.Task) (context.Context, cron.TraceFinisher) {
+	started := time.Now().UTC()
 	startOptions := []opentracing.StartSpanOption{
 		opentracing.Tag{Key: tracing.TagKeyResourceName, Value: task.Name()},
+		opentracing.Tag{Key: tracing.TagKeyJobName, Value: task.Name()},
 		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeJob},
-		opentracing.StartTime(time.Now().UTC()),
+		opentracing.StartTime(started),
 	}
 	span, spanCtx := tracing.StartSpanFromContext(ctx, t.tracer, tracing.OperationJob, startOptions...)
-	return spanCtx, &traceFinisher{span: span}
+	return spanCtx, &traceFinisher{span: span, started: started}
 }
 
 type traceFinisher struct {
-	span opentracing.Span
+	span    opentracing.Span
+	started time.Time
 }
 
 func (tf traceFinisher) Finish(ctx context.Context, t cron.Task, err error) {
@@ -37,5 +42,6 @@ func (tf traceFinisher) Finish(ctx context.Context, t cron.Task, err error) {
 		return
 	}
 	tracing.SpanError(tf.span, err)
+	tf.span.SetTag(tracing.TagKeyJobElapsed, float64(time.Since(tf.started))/float64(time.Millisecond))
 	tf.span.Finish()
 }
@@ -0,0 +1,31 @@
+package crontrace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/stats/tracing"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := mocktracer.New()
+	task := cron.NewJob("test-job")
+
+	ctx, finisher := Tracer(mock).Start(context.Background(), task)
+	assert.NotNil(ctx)
+	finisher.Finish(ctx, task, fmt.Errorf("only a test"))
+
+	spans := mock.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(tracing.OperationJob, spans[0].OperationName)
+	assert.Equal("test-job", spans[0].Tag(tracing.TagKeyJobName))
+	assert.Equal(tracing.SpanTypeJob, spans[0].Tag(tracing.TagKeySpanType))
+	assert.Equal("only a test", spans[0].Tag(tracing.TagKeyError))
+	assert.NotNil(spans[0].Tag(tracing.TagKeyJobElapsed))
+}
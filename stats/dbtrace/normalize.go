@@ -0,0 +1,93 @@
+package dbtrace
+
+import "strings"
+
+// NormalizeStatement returns a statement with string and numeric literals replaced by `?`,
+// comments removed, and whitespace collapsed, so statements that differ only
+// by their literal values are grouped together and values are not leaked into traces.
+// Placeholders (e.g. `$1`) and quoted identifiers are kept as is.
+func NormalizeStatement(statement string) string {
+	output := new(strings.Builder)
+	output.Grow(len(statement))
+
+	var pendingSpace bool
+	write := func(value string) {
+		if pendingSpace && output.Len() > 0 {
+			output.WriteByte(' ')
+		}
+		pendingSpace = false
+		output.WriteString(value)
+	}
+
+	for index := 0; index < len(statement); {
+		c := statement[index]
+		switch {
+		case isSpace(c):
+			pendingSpace = true
+			index++
+		case c == '-' && index+1 < len(statement) && statement[index+1] == '-':
+			for index < len(statement) && statement[index] != '\n' {
+				index++
+			}
+			pendingSpace = true
+		case c == '/' && index+1 < len(statement) && statement[index+1] == '*':
+			end := strings.Index(statement[index+2:], "*/")
+			if end < 0 {
+				index = len(statement)
+			} else {
+				index = index + 2 + end + 2
+			}
+			pendingSpace = true
+		case c == '\'':
+			index++
+			for index < len(statement) {
+				if statement[index] == '\'' {
+					if index+1 < len(statement) && statement[index+1] == '\'' {
+						index += 2
+						continue
+					}
+					break
+				}
+				index++
+			}
+			index++
+			write("?")
+		case c == '"':
+			end := strings.IndexByte(statement[index+1:], '"')
+			if end < 0 {
+				write(statement[index:])
+				index = len(statement)
+			} else {
+				write(statement[index : index+1+end+1])
+				index = index + 1 + end + 1
+			}
+		case isDigit(c) && (index == 0 || !isIdentifier(statement[index-1])):
+			for index < len(statement) && (isDigit(statement[index]) || statement[index] == '.') {
+				index++
+			}
+			write("?")
+		default:
+			start := index
+			for index < len(statement) && isIdentifier(statement[index]) {
+				index++
+			}
+			if index == start {
+				index++
+			}
+			write(statement[start:index])
+		}
+	}
+	return output.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentifier(c byte) bool {
+	return c == '_' || c == '$' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,27 @@
+package dbtrace
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestNormalizeStatement(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := [...]struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "select 1", Expected: "select ?"},
+		{Input: "SELECT * FROM users WHERE id = $1", Expected: "SELECT * FROM users WHERE id = $1"},
+		{Input: "select * from table1 where name = 'o''brien' and age > 21.5", Expected: "select * from table1 where name = ? and age > ?"},
+		{Input: "select\n\t\"Col 1\"\n  from  t -- trailing comment\nwhere x = 'a'", Expected: "select \"Col 1\" from t where x = ?"},
+		{Input: "/* leading */ insert into t (a, b) values (1, 'two')", Expected: "insert into t (a, b) values (?, ?)"},
+		{Input: "select a-1 from t", Expected: "select a-? from t"},
+		{Input: "", Expected: ""},
+	}
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, NormalizeStatement(tc.Input), tc.Input)
+	}
+}
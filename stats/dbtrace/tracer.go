@@ -14,6 +14,7 @@ var (
 )
 
 // Tracer returns a db tracer.
+// Statements are normalized with literals scrubbed before they are added to spans.
 func Tracer(tracer opentracing.Tracer) db.Tracer {
 	return &dbTracer{tracer: tracer}
 }
@@ -38,7 +39,7 @@ func (dbt dbTracer) Prepare(ctx context.Context, conn *db.Connection, statement
 		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeSQL},
 		opentracing.Tag{Key: tracing.TagKeyDBName, Value: conn.Config().GetDatabase()},
 		opentracing.Tag{Key: tracing.TagKeyDBUser, Value: conn.Config().GetUsername()},
-		opentracing.Tag{Key: "db.query", Value: statement},
+		opentracing.Tag{Key: tracing.TagKeyDBQuery, Value: NormalizeStatement(statement)},
 		opentracing.StartTime(time.Now().UTC()),
 	}
 	span, _ := tracing.StartSpanFromContext(ctx, dbt.tracer, tracing.OperationSQLPrepare, startOptions...)
@@ -46,12 +47,17 @@ func (dbt dbTracer) Prepare(ctx context.Context, conn *db.Connection, statement
 }
 
 func (dbt dbTracer) Query(ctx context.Context, conn *db.Connection, inv *db.Invocation, statement string) db.TraceFinisher {
+	normalized := NormalizeStatement(statement)
+	resource := inv.Label()
+	if len(resource) == 0 {
+		resource = normalized
+	}
 	startOptions := []opentracing.StartSpanOption{
-		opentracing.Tag{Key: tracing.TagKeyResourceName, Value: inv.Label()},
+		opentracing.Tag{Key: tracing.TagKeyResourceName, Value: resource},
 		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeSQL},
 		opentracing.Tag{Key: tracing.TagKeyDBName, Value: conn.Config().GetDatabase()},
 		opentracing.Tag{Key: tracing.TagKeyDBUser, Value: conn.Config().GetUsername()},
-		opentracing.Tag{Key: "db.query", Value: statement},
+		opentracing.Tag{Key: tracing.TagKeyDBQuery, Value: normalized},
 		opentracing.StartTime(inv.Start()),
 	}
 	span, _ := tracing.StartSpanFromContext(ctx, dbt.tracer, tracing.OperationSQLQuery, startOptions...)
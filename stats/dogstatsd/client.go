@@ -0,0 +1,322 @@
+package dogstatsd
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/stats"
+)
+
+// Assert that the client implements stats.Collector.
+var (
+	_ stats.Collector = (*Client)(nil)
+)
+
+// Metric types.
+const (
+	MetricTypeCount     = "c"
+	MetricTypeGauge     = "g"
+	MetricTypeHistogram = "h"
+	MetricTypeTiming    = "ms"
+)
+
+// New returns a new client for an agent address, i.e. `host:port` for udp or
+// `unix:///path/to/socket` for a unix domain socket, with default settings.
+// It must be started with `.Start()` to flush on an interval.
+func New(addr string) (*Client, error) {
+	return NewFromConfig(&Config{Addr: addr})
+}
+
+// NewFromEnv returns a new client from the environment.
+func NewFromEnv() (*Client, error) {
+	cfg, err := NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewFromConfig(cfg)
+}
+
+// NewFromConfig returns a new client from a config.
+// It must be started with `.Start()` to flush on an interval.
+func NewFromConfig(cfg *Config) (*Client, error) {
+	network, addr := "udp", cfg.GetAddr()
+	if strings.HasPrefix(addr, UnixAddrPrefix) {
+		network, addr = "unixgram", strings.TrimPrefix(addr, UnixAddrPrefix)
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, exception.New(err)
+	}
+	namespace := cfg.GetNamespace()
+	if len(namespace) > 0 && !strings.HasSuffix(namespace, ".") {
+		namespace = namespace + "."
+	}
+	return &Client{
+		conn:          conn,
+		namespace:     namespace,
+		defaultTags:   cfg.GetDefaultTags(),
+		flushInterval: cfg.GetFlushInterval(),
+		maxPacketSize: cfg.GetMaxPacketSize(),
+		aggregate:     cfg.GetAggregate(),
+		counts:        map[metricKey]int64{},
+		gauges:        map[metricKey]float64{},
+	}, nil
+}
+
+// Client is a dogstatsd client that buffers metrics into datagrams.
+// With aggregation enabled, counts are summed and gauges keep their last value
+// between flushes, so a hot counter is sent once per flush interval.
+// Histograms and timings are always sent individually so the agent can compute distributions.
+type Client struct {
+	sync.Mutex
+
+	conn          net.Conn
+	namespace     string
+	defaultTags   []string
+	flushInterval time.Duration
+	maxPacketSize int
+	aggregate     bool
+
+	counts map[metricKey]int64
+	gauges map[metricKey]float64
+	buffer []byte
+
+	errors  chan error
+	flusher *async.Interval
+}
+
+// metricKey identifies an aggregated metric.
+type metricKey struct {
+	name string
+	tags string
+}
+
+// Namespace returns the metric name prefix.
+func (c *Client) Namespace() string {
+	return c.namespace
+}
+
+// FlushInterval returns the flush interval.
+func (c *Client) FlushInterval() time.Duration {
+	return c.flushInterval
+}
+
+// MaxPacketSize returns the maximum datagram size.
+func (c *Client) MaxPacketSize() int {
+	return c.maxPacketSize
+}
+
+// Aggregate returns if counts and gauges are aggregated client side.
+func (c *Client) Aggregate() bool {
+	return c.aggregate
+}
+
+// WithErrors sets a channel that background flush errors are sent to.
+func (c *Client) WithErrors(errors chan error) *Client {
+	c.errors = errors
+	return c
+}
+
+// Errors returns the background flush error channel.
+func (c *Client) Errors() chan error {
+	return c.errors
+}
+
+// AddDefaultTag adds a new default tag.
+func (c *Client) AddDefaultTag(key, value string) {
+	c.Lock()
+	c.defaultTags = append(c.defaultTags, stats.Tag(key, value))
+	c.Unlock()
+}
+
+// DefaultTags returns the default tags.
+func (c *Client) DefaultTags() []string {
+	return c.defaultTags
+}
+
+// Count increments a counter by a value.
+func (c *Client) Count(name string, value int64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.aggregate {
+		c.counts[c.key(name, tags)] += value
+		return nil
+	}
+	return c.appendMetric(c.key(name, tags), strconv.FormatInt(value, 10), MetricTypeCount)
+}
+
+// Increment increments a counter by 1.
+func (c *Client) Increment(name string, tags ...string) error {
+	return c.Count(name, 1, tags...)
+}
+
+// Gauge sets a gauge value.
+func (c *Client) Gauge(name string, value float64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	if c.aggregate {
+		c.gauges[c.key(name, tags)] = value
+		return nil
+	}
+	return c.appendMetric(c.key(name, tags), formatFloat(value), MetricTypeGauge)
+}
+
+// Histogram adds a histogram value.
+func (c *Client) Histogram(name string, value float64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.appendMetric(c.key(name, tags), formatFloat(value), MetricTypeHistogram)
+}
+
+// TimeInMilliseconds adds a timing value.
+func (c *Client) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.appendMetric(c.key(name, tags), formatFloat(float64(value)/float64(time.Millisecond)), MetricTypeTiming)
+}
+
+// Start starts flushing metrics on the flush interval.
+func (c *Client) Start() *Client {
+	c.Lock()
+	defer c.Unlock()
+	if c.flusher != nil {
+		return c
+	}
+	c.flusher = async.NewInterval(c.Flush, c.flushInterval).WithErrors(c.errors)
+	c.flusher.Start()
+	return c
+}
+
+// Stop stops the background flush and flushes any pending metrics.
+func (c *Client) Stop() error {
+	c.Lock()
+	flusher := c.flusher
+	c.flusher = nil
+	c.Unlock()
+
+	if flusher != nil {
+		flusher.Stop()
+	}
+	return c.Flush()
+}
+
+// Close stops the client and closes the connection.
+func (c *Client) Close() error {
+	if err := c.Stop(); err != nil {
+		c.conn.Close()
+		return err
+	}
+	return exception.New(c.conn.Close())
+}
+
+// Flush sends any aggregated and buffered metrics.
+func (c *Client) Flush() error {
+	c.Lock()
+	defer c.Unlock()
+
+	countKeys := make([]metricKey, 0, len(c.counts))
+	for key := range c.counts {
+		countKeys = append(countKeys, key)
+	}
+	sortKeys(countKeys)
+	for _, key := range countKeys {
+		if err := c.appendMetric(key, strconv.FormatInt(c.counts[key], 10), MetricTypeCount); err != nil {
+			return err
+		}
+		delete(c.counts, key)
+	}
+
+	gaugeKeys := make([]metricKey, 0, len(c.gauges))
+	for key := range c.gauges {
+		gaugeKeys = append(gaugeKeys, key)
+	}
+	sortKeys(gaugeKeys)
+	for _, key := range gaugeKeys {
+		if err := c.appendMetric(key, formatFloat(c.gauges[key]), MetricTypeGauge); err != nil {
+			return err
+		}
+		delete(c.gauges, key)
+	}
+	return c.send()
+}
+
+// key returns the aggregation key for a metric; it must be called with the lock held.
+func (c *Client) key(name string, tags []string) metricKey {
+	allTags := make([]string, 0, len(c.defaultTags)+len(tags))
+	for _, tag := range c.defaultTags {
+		allTags = append(allTags, sanitizeTag(tag))
+	}
+	for _, tag := range tags {
+		allTags = append(allTags, sanitizeTag(tag))
+	}
+	return metricKey{
+		name: c.namespace + sanitizeName(name),
+		tags: strings.Join(allTags, ","),
+	}
+}
+
+// appendMetric adds a metric line to the buffer; it must be called with the lock held.
+func (c *Client) appendMetric(key metricKey, value, metricType string) error {
+	line := key.name + ":" + value + "|" + metricType
+	if len(key.tags) > 0 {
+		line = line + "|#" + key.tags
+	}
+	return c.appendLine(line)
+}
+
+// appendLine adds a line to the buffer, sending the buffer first if the line
+// would not fit in the current datagram. It must be called with the lock held.
+func (c *Client) appendLine(line string) error {
+	if len(c.buffer) > 0 && len(c.buffer)+1+len(line) > c.maxPacketSize {
+		if err := c.send(); err != nil {
+			return err
+		}
+	}
+	if len(c.buffer) > 0 {
+		c.buffer = append(c.buffer, '\n')
+	}
+	c.buffer = append(c.buffer, line...)
+	return nil
+}
+
+// send writes the buffer as a datagram; it must be called with the lock held.
+func (c *Client) send() error {
+	if len(c.buffer) == 0 {
+		return nil
+	}
+	_, err := c.conn.Write(c.buffer)
+	c.buffer = c.buffer[:0]
+	return exception.New(err)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+var (
+	nameReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "\n", "_")
+	tagReplacer  = strings.NewReplacer("|", "_", ",", "_", "\n", "_")
+)
+
+func sanitizeName(name string) string {
+	return nameReplacer.Replace(name)
+}
+
+func sanitizeTag(tag string) string {
+	return tagReplacer.Replace(tag)
+}
+
+func sortKeys(keys []metricKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].name == keys[j].name {
+			return keys[i].tags < keys[j].tags
+		}
+		return keys[i].name < keys[j].name
+	})
+}
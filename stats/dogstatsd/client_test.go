@@ -0,0 +1,198 @@
+package dogstatsd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/stats"
+)
+
+func listenUDP(assert *assert.Assertions) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(err)
+	return conn
+}
+
+func readPacket(assert *assert.Assertions, conn net.PacketConn) string {
+	assert.Nil(conn.SetReadDeadline(time.Now().Add(time.Second)))
+	packet := make([]byte, 65536)
+	read, _, err := conn.ReadFrom(packet)
+	assert.Nil(err)
+	return string(packet[:read])
+}
+
+func TestClientAggregates(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := listenUDP(assert)
+	defer conn.Close()
+
+	client, err := NewFromConfig(&Config{
+		Addr:        conn.LocalAddr().String(),
+		Namespace:   "test",
+		DefaultTags: []string{"env:test"},
+	})
+	assert.Nil(err)
+	defer client.Close()
+
+	assert.Nil(client.Increment("requests", "route:/foo"))
+	assert.Nil(client.Increment("requests", "route:/foo"))
+	assert.Nil(client.Count("requests", 3, "route:/bar"))
+	assert.Nil(client.Gauge("queue", 1))
+	assert.Nil(client.Gauge("queue", 5.5))
+	assert.Nil(client.Histogram("size", 12))
+	assert.Nil(client.TimeInMilliseconds("elapsed", 1500*time.Microsecond))
+	assert.Nil(client.Flush())
+
+	lines := strings.Split(readPacket(assert, conn), "\n")
+	assert.Equal([]string{
+		"test.size:12|h|#env:test",
+		"test.elapsed:1.5|ms|#env:test",
+		"test.requests:3|c|#env:test,route:/bar",
+		"test.requests:2|c|#env:test,route:/foo",
+		"test.queue:5.5|g|#env:test",
+	}, lines)
+}
+
+func TestClientSplitsPackets(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := listenUDP(assert)
+	defer conn.Close()
+
+	aggregate := false
+	client, err := NewFromConfig(&Config{
+		Addr:          conn.LocalAddr().String(),
+		MaxPacketSize: 32,
+		Aggregate:     &aggregate,
+	})
+	assert.Nil(err)
+	defer client.Close()
+
+	for x := 0; x < 4; x++ {
+		assert.Nil(client.Increment("a_fairly_long_name"))
+	}
+	assert.Nil(client.Flush())
+
+	var received []string
+	for len(received) < 4 {
+		packet := readPacket(assert, conn)
+		assert.True(len(packet) <= 32, packet)
+		received = append(received, strings.Split(packet, "\n")...)
+	}
+	assert.Len(received, 4)
+	assert.Equal("a_fairly_long_name:1|c", received[0])
+}
+
+func TestClientUnixSocket(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "dogstatsd")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dsd.socket")
+	conn, err := net.ListenPacket("unixgram", path)
+	assert.Nil(err)
+	defer conn.Close()
+
+	client, err := New(UnixAddrPrefix + path)
+	assert.Nil(err)
+	defer client.Close()
+	assert.Equal(DefaultMaxPacketSizeUDS, client.MaxPacketSize())
+
+	assert.Nil(client.Gauge("bad|name:here", 1, "key:a|b"))
+	assert.Nil(client.Flush())
+	assert.Equal("bad_name_here:1|g|#key:a_b", readPacket(assert, conn))
+}
+
+func TestClientStart(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := listenUDP(assert)
+	defer conn.Close()
+
+	client, err := NewFromConfig(&Config{
+		Addr:          conn.LocalAddr().String(),
+		FlushInterval: time.Millisecond,
+	})
+	assert.Nil(err)
+	client.Start()
+	defer client.Close()
+
+	assert.Nil(client.Increment("ticks"))
+	assert.Equal("ticks:1|c", readPacket(assert, conn))
+}
+
+func TestConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	env.Env().Set("DOGSTATSD_ADDR", "unix:///var/run/datadog/dsd.socket")
+	defer env.Env().Restore("DOGSTATSD_ADDR")
+	env.Env().Set("DOGSTATSD_TAGS", "env:test,team:platform")
+	defer env.Env().Restore("DOGSTATSD_TAGS")
+
+	cfg := MustNewConfigFromEnv()
+	assert.Equal(DefaultMaxPacketSizeUDS, cfg.GetMaxPacketSize())
+	assert.Equal([]string{"env:test", "team:platform"}, cfg.GetDefaultTags())
+	assert.True(cfg.GetAggregate())
+	assert.Equal(DefaultMaxPacketSizeUDP, Config{}.GetMaxPacketSize())
+	assert.Equal(DefaultFlushInterval, Config{}.GetFlushInterval())
+}
+
+func TestClientEventsAndServiceChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	conn := listenUDP(assert)
+	defer conn.Close()
+
+	client, err := NewFromConfig(&Config{
+		Addr:        conn.LocalAddr().String(),
+		Namespace:   "test",
+		DefaultTags: []string{"env:test"},
+	})
+	assert.Nil(err)
+	defer client.Close()
+
+	assert.Nil(client.Event("deploy", "version 1.2.3\nby ci", "service:api"))
+	assert.Nil(client.ServiceCheck("api.health", ServiceCheckCritical))
+	assert.Nil(client.Flush())
+
+	lines := strings.Split(readPacket(assert, conn), "\n")
+	assert.Equal([]string{
+		"_e{6,20}:deploy|version 1.2.3\\nby ci|#env:test,service:api",
+		"_sc|api.health|2|#env:test",
+	}, lines)
+}
+
+func TestNewCollectorFromConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	collector, err := NewCollectorFromConfig(&stats.Config{})
+	assert.Nil(err)
+	_, isNop := collector.(stats.NopCollector)
+	assert.True(isNop)
+
+	conn := listenUDP(assert)
+	defer conn.Close()
+
+	collector, err = NewCollectorFromConfig(&stats.Config{
+		Addr:          conn.LocalAddr().String(),
+		Namespace:     "test",
+		FlushInterval: time.Millisecond,
+	})
+	assert.Nil(err)
+	client, isClient := collector.(*Client)
+	assert.True(isClient)
+	defer client.Close()
+
+	assert.Nil(collector.Increment("ticks"))
+	assert.Equal("test.ticks:1|c", readPacket(assert, conn))
+}
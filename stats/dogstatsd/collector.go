@@ -0,0 +1,33 @@
+package dogstatsd
+
+import (
+	"github.com/blend/go-sdk/stats"
+)
+
+// NewCollectorFromConfig returns a started dogstatsd client from the common stats config,
+// or a no-op collector if stats are disabled.
+func NewCollectorFromConfig(cfg *stats.Config) (stats.Collector, error) {
+	if !cfg.GetEnabled() {
+		return stats.NopCollector{}, nil
+	}
+	client, err := NewFromConfig(&Config{
+		Addr:          cfg.GetAddr(),
+		Namespace:     cfg.GetNamespace(),
+		DefaultTags:   cfg.GetDefaultTags(),
+		FlushInterval: cfg.GetFlushInterval(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.Start(), nil
+}
+
+// NewCollectorFromEnv returns a started dogstatsd client from the common stats config in the environment,
+// or a no-op collector if stats are disabled.
+func NewCollectorFromEnv() (stats.Collector, error) {
+	cfg, err := stats.NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewCollectorFromConfig(cfg)
+}
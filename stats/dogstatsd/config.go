@@ -0,0 +1,76 @@
+package dogstatsd
+
+import (
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/util"
+)
+
+// NewConfigFromEnv returns a new config from the env.
+func NewConfigFromEnv() (*Config, error) {
+	var config Config
+	if err := env.Env().ReadInto(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// MustNewConfigFromEnv creates a new config from the environment and panics on error.
+func MustNewConfigFromEnv() (config *Config) {
+	var err error
+	if config, err = NewConfigFromEnv(); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Config is the dogstatsd client config.
+type Config struct {
+	// Addr is the agent address, either `host:port` for udp or `unix:///path/to/socket` for a unix domain socket.
+	Addr string `json:"addr,omitempty" yaml:"addr,omitempty" env:"DOGSTATSD_ADDR"`
+	// Namespace is an optional prefix for metric names.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty" env:"DOGSTATSD_NAMESPACE"`
+	// DefaultTags are the default tags associated with any metric.
+	DefaultTags []string `json:"defaultTags,omitempty" yaml:"defaultTags,omitempty" env:"DOGSTATSD_TAGS,csv"`
+	// FlushInterval is the interval buffered and aggregated metrics are flushed on.
+	FlushInterval time.Duration `json:"flushInterval,omitempty" yaml:"flushInterval,omitempty" env:"DOGSTATSD_FLUSH_INTERVAL"`
+	// MaxPacketSize is the maximum datagram size; it defaults based on the address network.
+	MaxPacketSize int `json:"maxPacketSize,omitempty" yaml:"maxPacketSize,omitempty" env:"DOGSTATSD_MAX_PACKET_SIZE"`
+	// Aggregate indicates if counts and gauges should be aggregated client side between flushes.
+	Aggregate *bool `json:"aggregate,omitempty" yaml:"aggregate,omitempty" env:"DOGSTATSD_AGGREGATE"`
+}
+
+// GetAddr returns the agent address.
+func (c Config) GetAddr(defaults ...string) string {
+	return util.Coalesce.String(c.Addr, DefaultAddr, defaults...)
+}
+
+// GetNamespace returns the default prefix for metric names.
+func (c Config) GetNamespace(defaults ...string) string {
+	return util.Coalesce.String(c.Namespace, "", defaults...)
+}
+
+// GetDefaultTags returns default tags for the client.
+func (c Config) GetDefaultTags(defaults ...[]string) []string {
+	return util.Coalesce.Strings(c.DefaultTags, nil, defaults...)
+}
+
+// GetFlushInterval returns the flush interval.
+func (c Config) GetFlushInterval(defaults ...time.Duration) time.Duration {
+	return util.Coalesce.Duration(c.FlushInterval, DefaultFlushInterval, defaults...)
+}
+
+// GetMaxPacketSize returns the maximum datagram size.
+func (c Config) GetMaxPacketSize(defaults ...int) int {
+	if strings.HasPrefix(c.GetAddr(), UnixAddrPrefix) {
+		return util.Coalesce.Int(c.MaxPacketSize, DefaultMaxPacketSizeUDS, defaults...)
+	}
+	return util.Coalesce.Int(c.MaxPacketSize, DefaultMaxPacketSizeUDP, defaults...)
+}
+
+// GetAggregate returns if counts and gauges should be aggregated client side.
+func (c Config) GetAggregate(defaults ...bool) bool {
+	return util.Coalesce.Bool(c.Aggregate, DefaultAggregate, defaults...)
+}
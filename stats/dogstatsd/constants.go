@@ -0,0 +1,20 @@
+package dogstatsd
+
+import "time"
+
+const (
+	// DefaultAddr is the default dogstatsd agent address.
+	DefaultAddr = "127.0.0.1:8125"
+	// DefaultFlushInterval is the default interval buffered and aggregated metrics are flushed on.
+	DefaultFlushInterval = time.Second
+	// DefaultMaxPacketSizeUDP is the default maximum datagram size for udp.
+	// It fits in a typical ethernet mtu without fragmentation.
+	DefaultMaxPacketSizeUDP = 1432
+	// DefaultMaxPacketSizeUDS is the default maximum datagram size for unix domain sockets.
+	DefaultMaxPacketSizeUDS = 8192
+	// DefaultAggregate is the default setting for client-side aggregation of counts and gauges.
+	DefaultAggregate = true
+
+	// UnixAddrPrefix is the address prefix that selects a unix domain socket, e.g. `unix:///var/run/datadog/dsd.socket`.
+	UnixAddrPrefix = "unix://"
+)
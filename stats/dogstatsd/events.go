@@ -0,0 +1,50 @@
+package dogstatsd
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ServiceCheckStatus is the status of a service check.
+type ServiceCheckStatus int
+
+// Service check statuses.
+const (
+	ServiceCheckOK       ServiceCheckStatus = 0
+	ServiceCheckWarning  ServiceCheckStatus = 1
+	ServiceCheckCritical ServiceCheckStatus = 2
+	ServiceCheckUnknown  ServiceCheckStatus = 3
+)
+
+// Event sends an event, e.g. a deploy marker, with the default tags.
+// Events are buffered with metrics and sent on the next flush.
+func (c *Client) Event(title, text string, tags ...string) error {
+	title, text = escapeEventText(title), escapeEventText(text)
+	line := "_e{" + strconv.Itoa(len(title)) + "," + strconv.Itoa(len(text)) + "}:" + title + "|" + text
+
+	c.Lock()
+	defer c.Unlock()
+	if key := c.key("", tags); len(key.tags) > 0 {
+		line = line + "|#" + key.tags
+	}
+	return c.appendLine(line)
+}
+
+// ServiceCheck sends a service check status, e.g. a health annotation, with the default tags.
+// Service checks are buffered with metrics and sent on the next flush.
+func (c *Client) ServiceCheck(name string, status ServiceCheckStatus, tags ...string) error {
+	line := "_sc|" + sanitizeName(name) + "|" + strconv.Itoa(int(status))
+
+	c.Lock()
+	defer c.Unlock()
+	if key := c.key("", tags); len(key.tags) > 0 {
+		line = line + "|#" + key.tags
+	}
+	return c.appendLine(line)
+}
+
+var eventTextReplacer = strings.NewReplacer("\n", "\\n", "|", "_")
+
+func escapeEventText(text string) string {
+	return eventTextReplacer.Replace(text)
+}
@@ -32,7 +32,11 @@ const (
 
 	RouteNotFound string = "not_found"
 
-	ListenerNameStats string = "stats"
+	ListenerNameStats           string = "stats"
+	ListenerNameStatsEventCount string = "stats.event_count"
+
+	MetricNameEventCountPrefix string = "log."
+	MetricNameEventCountSuffix string = ".count"
 )
 
 // Tag creates a new tag.
@@ -40,6 +44,28 @@ func Tag(key, value string) string {
 	return key + ":" + value
 }
 
+// MetricNameEventCount returns the event count metric name for a flag, e.g. `log.error.count`.
+func MetricNameEventCount(flag logger.Flag) string {
+	return MetricNameEventCountPrefix + string(flag) + MetricNameEventCountSuffix
+}
+
+// AddEventCountListeners adds listeners that increment a counter per event flag.
+// If no flags are given, `logger.DefaultFlags` are counted.
+func AddEventCountListeners(log *logger.Logger, stats Collector, flags ...logger.Flag) {
+	if log == nil || stats == nil {
+		return
+	}
+	if len(flags) == 0 {
+		flags = logger.DefaultFlags
+	}
+	for _, flag := range flags {
+		metricName := MetricNameEventCount(flag)
+		log.Listen(flag, ListenerNameStatsEventCount, func(e logger.Event) {
+			stats.Increment(metricName)
+		})
+	}
+}
+
 // AddWebListeners adds web listeners.
 func AddWebListeners(log *logger.Logger, stats Collector) {
 	if log == nil || stats == nil {
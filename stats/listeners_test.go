@@ -40,3 +40,31 @@ func TestAddErrorListeners(t *testing.T) {
 	assert.True(log.HasListener(logger.Error, ListenerNameStats))
 	assert.True(log.HasListener(logger.Fatal, ListenerNameStats))
 }
+
+func TestAddEventCountListeners(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.None()
+	AddEventCountListeners(nil, nil)
+	assert.False(log.HasListener(logger.Error, ListenerNameStatsEventCount))
+	AddEventCountListeners(log, NewMockCollector())
+	for _, flag := range logger.DefaultFlags {
+		assert.True(log.HasListener(flag, ListenerNameStatsEventCount))
+	}
+	assert.False(log.HasListener(logger.Debug, ListenerNameStatsEventCount))
+}
+
+func TestEventCountListenerIncrements(t *testing.T) {
+	assert := assert.New(t)
+
+	log := logger.New(logger.Error)
+	defer log.Close()
+
+	collector := NewMockCollector()
+	AddEventCountListeners(log, collector, logger.Error)
+
+	go log.SyncErrorf("test error")
+	metric := <-collector.Events
+	assert.Equal("log.error.count", metric.Name)
+	assert.Equal(1, metric.Count)
+}
@@ -0,0 +1,28 @@
+package logtrace
+
+import (
+	"context"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stats/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+var (
+	_ logger.TraceCorrelator = (*correlator)(nil)
+)
+
+// Correlator returns a logger trace correlator that reads ids from the opentracing span in a context.
+func Correlator() logger.TraceCorrelator {
+	return &correlator{}
+}
+
+type correlator struct{}
+
+func (c correlator) TraceIDs(ctx context.Context) (traceID, spanID string) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	return tracing.GetTraceIDs(span.Context())
+}
@@ -0,0 +1,125 @@
+package logtrace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/stats/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+var (
+	_ opentracing.Tracer = (*LoggingTracer)(nil)
+	_ opentracing.Span   = (*loggingSpan)(nil)
+)
+
+// NewLoggingTracer returns a tracer that mirrors spans started by another tracer into logger events.
+// Span starts trigger `span.start` events and span finishes trigger `span.finish` events with the
+// elapsed time and any error tagged on the span; enable the flags on the logger to write them.
+// This gives span level timing in logs for environments without a trace backend.
+func NewLoggingTracer(tracer opentracing.Tracer, log *logger.Logger) *LoggingTracer {
+	return &LoggingTracer{
+		Tracer: tracer,
+		log:    log,
+	}
+}
+
+// LoggingTracer is a tracer that triggers logger events for spans.
+type LoggingTracer struct {
+	opentracing.Tracer
+	log *logger.Logger
+}
+
+// Logger returns the logger.
+func (lt *LoggingTracer) Logger() *logger.Logger {
+	return lt.log
+}
+
+// StartSpan implements opentracing.Tracer.
+func (lt *LoggingTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var options opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+	started := options.StartTime
+	if started.IsZero() {
+		started = time.Now().UTC()
+	}
+
+	span := &loggingSpan{
+		Span:          lt.Tracer.StartSpan(operationName, opts...),
+		log:           lt.log,
+		operationName: operationName,
+		started:       started,
+	}
+	if err, ok := options.Tags[tracing.TagKeyError]; ok {
+		span.setErr(err)
+	}
+	if lt.log != nil {
+		lt.log.Trigger(span.event(FlagSpanStart).WithTimestamp(started))
+	}
+	return span
+}
+
+// loggingSpan wraps a span to trigger an event when it finishes.
+type loggingSpan struct {
+	opentracing.Span
+	log           *logger.Logger
+	operationName string
+	started       time.Time
+	err           error
+}
+
+// SetOperationName implements opentracing.Span.
+func (ls *loggingSpan) SetOperationName(operationName string) opentracing.Span {
+	ls.operationName = operationName
+	ls.Span.SetOperationName(operationName)
+	return ls
+}
+
+// SetTag implements opentracing.Span, recording error tags.
+func (ls *loggingSpan) SetTag(key string, value interface{}) opentracing.Span {
+	if key == tracing.TagKeyError {
+		ls.setErr(value)
+	}
+	ls.Span.SetTag(key, value)
+	return ls
+}
+
+// Finish implements opentracing.Span.
+func (ls *loggingSpan) Finish() {
+	ls.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions implements opentracing.Span.
+func (ls *loggingSpan) FinishWithOptions(opts opentracing.FinishOptions) {
+	finished := opts.FinishTime
+	if finished.IsZero() {
+		finished = time.Now().UTC()
+	}
+	ls.Span.FinishWithOptions(opts)
+	if ls.log != nil {
+		ls.log.Trigger(ls.event(FlagSpanFinish).WithElapsed(finished.Sub(ls.started)).WithErr(ls.err).WithTimestamp(finished))
+	}
+}
+
+func (ls *loggingSpan) event(flag logger.Flag) *SpanEvent {
+	e := NewSpanEvent(flag, ls.operationName)
+	e.SetTrace(tracing.GetTraceIDs(ls.Span.Context()))
+	return e
+}
+
+func (ls *loggingSpan) setErr(value interface{}) {
+	switch typed := value.(type) {
+	case nil:
+	case error:
+		ls.err = typed
+	case bool:
+		if typed {
+			ls.err = fmt.Errorf("error")
+		}
+	default:
+		ls.err = fmt.Errorf("%v", typed)
+	}
+}
@@ -0,0 +1,61 @@
+package logtrace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/logger/logtest"
+	"github.com/blend/go-sdk/stats/tracing"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestLoggingTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	log, capture := logtest.New(FlagSpanStart, FlagSpanFinish)
+	defer log.Close()
+
+	mock := mocktracer.New()
+	tracer := NewLoggingTracer(mock, log)
+
+	started := time.Now().UTC().Add(-time.Second)
+	span, _ := tracing.StartSpanFromContext(context.Background(), tracer, tracing.OperationJob, opentracing.StartTime(started))
+	tracing.SpanError(span, fmt.Errorf("only a test"))
+	span.Finish()
+
+	events := capture.Events()
+	assert.Len(events, 2)
+	start := events[0].(*SpanEvent)
+	assert.Equal(FlagSpanStart, start.Flag())
+	assert.Equal(tracing.OperationJob, start.OperationName())
+	assert.Equal(started, start.Timestamp())
+
+	finish := events[1].(*SpanEvent)
+	assert.Equal(FlagSpanFinish, finish.Flag())
+	assert.True(finish.Elapsed() >= time.Second)
+	assert.Equal("only a test", finish.Err().Error())
+
+	assert.Len(mock.FinishedSpans(), 1)
+}
+
+func TestSpanEventWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewSpanEvent(FlagSpanFinish, "job").WithElapsed(time.Millisecond).WithErr(fmt.Errorf("failed"))
+	buf := new(bytes.Buffer)
+	e.WriteText(logger.NewTextWriter(nil).WithUseColor(false), buf)
+	assert.Equal("job (1ms) failed", buf.String())
+
+	obj := e.WriteJSON()
+	assert.Equal("job", obj["operation"])
+	assert.NotNil(obj[logger.JSONFieldElapsed])
+
+	obj = NewSpanEvent(FlagSpanStart, "job").WriteJSON()
+	assert.Nil(obj[logger.JSONFieldElapsed])
+}
@@ -0,0 +1,141 @@
+package logtrace
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+)
+
+// Span event flags.
+const (
+	// FlagSpanStart is the flag for span start events.
+	FlagSpanStart logger.Flag = "span.start"
+	// FlagSpanFinish is the flag for span finish events.
+	FlagSpanFinish logger.Flag = "span.finish"
+)
+
+// these are compile time assertions
+var (
+	_ logger.Event            = &SpanEvent{}
+	_ logger.EventHeadings    = &SpanEvent{}
+	_ logger.EventLabels      = &SpanEvent{}
+	_ logger.EventAnnotations = &SpanEvent{}
+	_ logger.EventTrace       = &SpanEvent{}
+)
+
+// NewSpanEventListener returns a new span event listener.
+func NewSpanEventListener(listener func(e *SpanEvent)) logger.Listener {
+	return func(e logger.Event) {
+		if typed, isTyped := e.(*SpanEvent); isTyped {
+			listener(typed)
+		}
+	}
+}
+
+// NewSpanEvent returns a new span event.
+func NewSpanEvent(flag logger.Flag, operationName string) *SpanEvent {
+	return &SpanEvent{
+		EventMeta:     logger.NewEventMeta(flag),
+		operationName: operationName,
+	}
+}
+
+// SpanEvent is an event that mirrors a span starting or finishing.
+type SpanEvent struct {
+	*logger.EventMeta
+
+	operationName string
+	elapsed       time.Duration
+	err           error
+}
+
+// WithHeadings sets the headings.
+func (e *SpanEvent) WithHeadings(headings ...string) *SpanEvent {
+	e.SetHeadings(headings...)
+	return e
+}
+
+// WithLabel sets a label on the event for later filtering.
+func (e *SpanEvent) WithLabel(key, value string) *SpanEvent {
+	e.AddLabelValue(key, value)
+	return e
+}
+
+// WithAnnotation adds an annotation to the event.
+func (e *SpanEvent) WithAnnotation(key, value string) *SpanEvent {
+	e.AddAnnotationValue(key, value)
+	return e
+}
+
+// WithFlag sets the event flag.
+func (e *SpanEvent) WithFlag(f logger.Flag) *SpanEvent {
+	e.SetFlag(f)
+	return e
+}
+
+// WithTimestamp sets the event timestamp.
+func (e *SpanEvent) WithTimestamp(ts time.Time) *SpanEvent {
+	e.SetTimestamp(ts)
+	return e
+}
+
+// WithOperationName sets the operation name.
+func (e *SpanEvent) WithOperationName(operationName string) *SpanEvent {
+	e.operationName = operationName
+	return e
+}
+
+// OperationName returns the operation name.
+func (e SpanEvent) OperationName() string {
+	return e.operationName
+}
+
+// WithElapsed sets the elapsed time.
+func (e *SpanEvent) WithElapsed(elapsed time.Duration) *SpanEvent {
+	e.elapsed = elapsed
+	return e
+}
+
+// Elapsed returns the elapsed time; it is only set for finish events.
+func (e SpanEvent) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+// WithErr sets the span error.
+func (e *SpanEvent) WithErr(err error) *SpanEvent {
+	e.err = err
+	return e
+}
+
+// Err returns the span error, if any.
+func (e SpanEvent) Err() error {
+	return e.err
+}
+
+// WriteText implements logger.TextWritable.
+func (e SpanEvent) WriteText(tf logger.TextFormatter, buf *bytes.Buffer) {
+	buf.WriteString(tf.Colorize(e.operationName, logger.ColorBlue))
+	if e.Flag() == FlagSpanFinish {
+		buf.WriteString(fmt.Sprintf(" (%v)", e.elapsed))
+	}
+	if e.err != nil {
+		buf.WriteString(" ")
+		buf.WriteString(tf.Colorize(e.err.Error(), logger.ColorRed))
+	}
+}
+
+// WriteJSON implements logger.JSONWritable.
+func (e SpanEvent) WriteJSON() logger.JSONObj {
+	obj := logger.JSONObj{
+		"operation": e.operationName,
+	}
+	if e.Flag() == FlagSpanFinish {
+		obj[logger.JSONFieldElapsed] = logger.Milliseconds(e.elapsed)
+	}
+	if e.err != nil {
+		obj[logger.JSONFieldErr] = e.err
+	}
+	return obj
+}
@@ -1,6 +1,9 @@
 package stats
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Assert that the mock collector implements Collector.
 var (
@@ -56,11 +59,18 @@ func (mc *MockCollector) Histogram(name string, value float64, tags ...string) e
 	return nil
 }
 
+// TimeInMilliseconds adds a mock timing event to the event stream with a value.
+func (mc *MockCollector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	mc.Events <- MockMetric{Name: name, TimeInMilliseconds: float64(value) / float64(time.Millisecond), Tags: append(mc.defaultTags, tags...)}
+	return nil
+}
+
 // MockMetric is a mock metric.
 type MockMetric struct {
-	Name      string
-	Count     int64
-	Gauge     float64
-	Histogram float64
-	Tags      []string
+	Name               string
+	Count              int64
+	Gauge              float64
+	Histogram          float64
+	TimeInMilliseconds float64
+	Tags               []string
 }
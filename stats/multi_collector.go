@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// Assert multi collectors are collectors.
+var (
+	_ Collector = (Collectors)(nil)
+)
+
+// MultiCollector returns a collector that writes to each of the given collectors,
+// e.g. to double write to datadog and prometheus during a migration.
+// Use `NewNamespacedCollector` to give each collector its own prefix and default tags.
+func MultiCollector(collectors ...Collector) Collectors {
+	return Collectors(collectors)
+}
+
+// Collectors is a list of collectors that is itself a collector.
+// Metrics are written to every collector; errors are nested together.
+type Collectors []Collector
+
+// AddDefaultTag adds a default tag to each collector.
+func (c Collectors) AddDefaultTag(key, value string) {
+	for _, collector := range c {
+		collector.AddDefaultTag(key, value)
+	}
+}
+
+// DefaultTags returns the distinct default tags of the collectors.
+func (c Collectors) DefaultTags() []string {
+	var output []string
+	seen := map[string]bool{}
+	for _, collector := range c {
+		for _, tag := range collector.DefaultTags() {
+			if !seen[tag] {
+				seen[tag] = true
+				output = append(output, tag)
+			}
+		}
+	}
+	return output
+}
+
+// Count increments a counter by a value on each collector.
+func (c Collectors) Count(name string, value int64, tags ...string) error {
+	return c.each(func(collector Collector) error { return collector.Count(name, value, tags...) })
+}
+
+// Increment increments a counter by 1 on each collector.
+func (c Collectors) Increment(name string, tags ...string) error {
+	return c.each(func(collector Collector) error { return collector.Increment(name, tags...) })
+}
+
+// Gauge sets a gauge on each collector.
+func (c Collectors) Gauge(name string, value float64, tags ...string) error {
+	return c.each(func(collector Collector) error { return collector.Gauge(name, value, tags...) })
+}
+
+// Histogram adds a histogram value on each collector.
+func (c Collectors) Histogram(name string, value float64, tags ...string) error {
+	return c.each(func(collector Collector) error { return collector.Histogram(name, value, tags...) })
+}
+
+// TimeInMilliseconds adds a timing on each collector.
+func (c Collectors) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	return c.each(func(collector Collector) error { return collector.TimeInMilliseconds(name, value, tags...) })
+}
+
+func (c Collectors) each(action func(Collector) error) error {
+	var errs []error
+	for _, collector := range c {
+		if err := action(collector); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return exception.Nest(errs...)
+}
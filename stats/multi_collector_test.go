@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type errorCollector struct {
+	MockCollector
+}
+
+func (ec *errorCollector) Count(name string, value int64, tags ...string) error {
+	return fmt.Errorf("count failed: %s", name)
+}
+
+func TestMultiCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	first := &MockCollector{Events: make(chan MockMetric, 8)}
+	second := &MockCollector{Events: make(chan MockMetric, 8)}
+	collector := MultiCollector(
+		NewNamespacedCollector(first, "old", "source:datadog"),
+		NewNamespacedCollector(second, "", "source:prometheus"),
+	)
+
+	assert.Nil(collector.Increment("requests", "route:/"))
+	assert.Nil(collector.TimeInMilliseconds("elapsed", time.Second))
+	assert.Equal([]string{"source:datadog", "source:prometheus"}, collector.DefaultTags())
+
+	metric := <-first.Events
+	assert.Equal("old.requests", metric.Name)
+	assert.Equal([]string{"source:datadog", "route:/"}, metric.Tags)
+	metric = <-second.Events
+	assert.Equal("requests", metric.Name)
+	assert.Equal([]string{"source:prometheus", "route:/"}, metric.Tags)
+
+	metric = <-first.Events
+	assert.Equal("old.elapsed", metric.Name)
+	assert.Equal(1000, metric.TimeInMilliseconds)
+	metric = <-second.Events
+	assert.Equal("elapsed", metric.Name)
+}
+
+func TestMultiCollectorErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	ok := &MockCollector{Events: make(chan MockMetric, 8)}
+	collector := MultiCollector(&errorCollector{}, ok, &errorCollector{})
+
+	err := collector.Count("requests", 1)
+	assert.NotNil(err)
+	assert.Contains(fmt.Sprintf("%v", err), "count failed: requests")
+	assert.Equal("requests", (<-ok.Events).Name)
+
+	assert.Nil(MultiCollector().Count("requests", 1))
+}
+
+func TestNamespacedCollectorDefaultTags(t *testing.T) {
+	assert := assert.New(t)
+
+	tags := []string{"source:datadog", "env:test"}
+	mock := &MockCollector{Events: make(chan MockMetric, 64)}
+	collector := NewNamespacedCollector(mock, "app", tags[:1]...)
+
+	// the default tags are copied, so appending to the caller's slice doesn't change them.
+	_ = append(tags[:1], "env:prod")
+	assert.Equal([]string{"source:datadog"}, collector.DefaultTags())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for x := 0; x < 32; x++ {
+			collector.AddDefaultTag("tag", fmt.Sprint(x))
+		}
+	}()
+	for x := 0; x < 32; x++ {
+		assert.Nil(collector.Increment("requests"))
+		<-mock.Events
+	}
+	<-done
+	assert.Len(collector.DefaultTags(), 33)
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// Assert namespaced collectors are collectors.
+var (
+	_ Collector = (*NamespacedCollector)(nil)
+)
+
+// NewNamespacedCollector returns a collector that prefixes metric names and adds
+// default tags before writing to an underlying collector.
+// A prefix of `app` turns `http.request` into `app.http.request`.
+func NewNamespacedCollector(collector Collector, prefix string, defaultTags ...string) *NamespacedCollector {
+	return &NamespacedCollector{
+		collector:   collector,
+		prefix:      prefix,
+		defaultTags: append([]string{}, defaultTags...),
+	}
+}
+
+// NamespacedCollector is a collector with its own metric name prefix and default tags.
+type NamespacedCollector struct {
+	sync.RWMutex
+
+	collector   Collector
+	prefix      string
+	defaultTags []string
+}
+
+// Collector returns the underlying collector.
+func (nc *NamespacedCollector) Collector() Collector {
+	return nc.collector
+}
+
+// Prefix returns the metric name prefix.
+func (nc *NamespacedCollector) Prefix() string {
+	return nc.prefix
+}
+
+// AddDefaultTag adds a default tag; it is not added to the underlying collector.
+func (nc *NamespacedCollector) AddDefaultTag(key, value string) {
+	nc.Lock()
+	nc.defaultTags = append(nc.defaultTags, Tag(key, value))
+	nc.Unlock()
+}
+
+// DefaultTags returns the default tags of the underlying collector and the namespaced collector.
+func (nc *NamespacedCollector) DefaultTags() []string {
+	nc.RLock()
+	defer nc.RUnlock()
+	return append(append([]string{}, nc.collector.DefaultTags()...), nc.defaultTags...)
+}
+
+// Count increments a counter by a value.
+func (nc *NamespacedCollector) Count(name string, value int64, tags ...string) error {
+	return nc.collector.Count(nc.name(name), value, nc.tags(tags)...)
+}
+
+// Increment increments a counter by 1.
+func (nc *NamespacedCollector) Increment(name string, tags ...string) error {
+	return nc.collector.Increment(nc.name(name), nc.tags(tags)...)
+}
+
+// Gauge sets a gauge value.
+func (nc *NamespacedCollector) Gauge(name string, value float64, tags ...string) error {
+	return nc.collector.Gauge(nc.name(name), value, nc.tags(tags)...)
+}
+
+// Histogram adds a histogram value.
+func (nc *NamespacedCollector) Histogram(name string, value float64, tags ...string) error {
+	return nc.collector.Histogram(nc.name(name), value, nc.tags(tags)...)
+}
+
+// TimeInMilliseconds adds a timing value.
+func (nc *NamespacedCollector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	return nc.collector.TimeInMilliseconds(nc.name(name), value, nc.tags(tags)...)
+}
+
+func (nc *NamespacedCollector) name(name string) string {
+	if len(nc.prefix) == 0 {
+		return name
+	}
+	return nc.prefix + "." + name
+}
+
+func (nc *NamespacedCollector) tags(tags []string) []string {
+	nc.RLock()
+	defer nc.RUnlock()
+	if len(nc.defaultTags) == 0 {
+		return tags
+	}
+	return append(append([]string{}, nc.defaultTags...), tags...)
+}
@@ -0,0 +1,34 @@
+package stats
+
+import "time"
+
+// Assert the nop collector is a collector.
+var (
+	_ Collector = (*NopCollector)(nil)
+)
+
+// NopCollector is a collector that discards metrics, e.g. when stats are disabled.
+type NopCollector struct{}
+
+// AddDefaultTag does nothing.
+func (nc NopCollector) AddDefaultTag(key, value string) {}
+
+// DefaultTags returns nil.
+func (nc NopCollector) DefaultTags() []string { return nil }
+
+// Count does nothing.
+func (nc NopCollector) Count(name string, value int64, tags ...string) error { return nil }
+
+// Increment does nothing.
+func (nc NopCollector) Increment(name string, tags ...string) error { return nil }
+
+// Gauge does nothing.
+func (nc NopCollector) Gauge(name string, value float64, tags ...string) error { return nil }
+
+// Histogram does nothing.
+func (nc NopCollector) Histogram(name string, value float64, tags ...string) error { return nil }
+
+// TimeInMilliseconds does nothing.
+func (nc NopCollector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	return nil
+}
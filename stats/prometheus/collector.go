@@ -0,0 +1,340 @@
+package prometheus
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/stats"
+)
+
+// Assert that the prometheus collector implements stats.Collector.
+var (
+	_ stats.Collector = (*Collector)(nil)
+	_ http.Handler    = (*Collector)(nil)
+)
+
+// New returns a new collector with default settings.
+func New() *Collector {
+	return NewFromConfig(&Config{})
+}
+
+// NewFromEnv returns a new collector from the environment.
+func NewFromEnv() (*Collector, error) {
+	cfg, err := NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return NewFromConfig(cfg), nil
+}
+
+// NewFromConfig returns a new collector from a config.
+func NewFromConfig(cfg *Config) *Collector {
+	c := &Collector{
+		namespace:     cfg.GetNamespace(),
+		defaultTags:   cfg.GetDefaultTags(),
+		buckets:       cfg.GetBuckets(),
+		timingBuckets: cfg.GetTimingBuckets(),
+		metricBuckets: map[string][]float64{},
+		families:      map[string]*family{},
+	}
+	for name, buckets := range cfg.MetricBuckets {
+		c.WithMetricBuckets(name, buckets...)
+	}
+	return c
+}
+
+// Collector is a stats collector that keeps metrics in memory and serves them
+// in the prometheus text exposition format.
+// Tags of the form `key:value` become labels; other tags become a `tag` label.
+type Collector struct {
+	sync.Mutex
+
+	namespace     string
+	defaultTags   []string
+	buckets       []float64
+	timingBuckets []float64
+	metricBuckets map[string][]float64
+
+	families map[string]*family
+}
+
+// family is a metric name with a type and its labeled series.
+type family struct {
+	name       string
+	metricType string
+	series     map[string]*series
+}
+
+// series is a single labeled metric.
+type series struct {
+	labels string
+	value  float64
+
+	buckets      []float64
+	bucketCounts []uint64
+	count        uint64
+}
+
+// Namespace returns the metric name prefix.
+func (c *Collector) Namespace() string {
+	return c.namespace
+}
+
+// WithMetricBuckets sets the histogram buckets for a metric name.
+func (c *Collector) WithMetricBuckets(name string, buckets ...float64) *Collector {
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+	c.Lock()
+	c.metricBuckets[c.metricName(name)] = sorted
+	c.Unlock()
+	return c
+}
+
+// MetricBuckets returns the histogram buckets for a metric name.
+func (c *Collector) MetricBuckets(name string) []float64 {
+	c.Lock()
+	defer c.Unlock()
+	return c.metricBuckets[c.metricName(name)]
+}
+
+// AddDefaultTag adds a new default tag.
+func (c *Collector) AddDefaultTag(key, value string) {
+	c.Lock()
+	c.defaultTags = append(c.defaultTags, stats.Tag(key, value))
+	c.Unlock()
+}
+
+// DefaultTags returns the default tags.
+func (c *Collector) DefaultTags() []string {
+	return c.defaultTags
+}
+
+// Count increments a counter by a value.
+func (c *Collector) Count(name string, value int64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	s, err := c.series(name, MetricTypeCounter, nil, tags)
+	if err != nil {
+		return err
+	}
+	s.value += float64(value)
+	return nil
+}
+
+// Increment increments a counter by 1.
+func (c *Collector) Increment(name string, tags ...string) error {
+	return c.Count(name, 1, tags...)
+}
+
+// Gauge sets a gauge value.
+func (c *Collector) Gauge(name string, value float64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	s, err := c.series(name, MetricTypeGauge, nil, tags)
+	if err != nil {
+		return err
+	}
+	s.value = value
+	return nil
+}
+
+// Histogram observes a histogram value.
+func (c *Collector) Histogram(name string, value float64, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.observe(name, c.buckets, value, tags)
+}
+
+// TimeInMilliseconds observes a timing, in milliseconds, in a histogram.
+func (c *Collector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	c.Lock()
+	defer c.Unlock()
+	return c.observe(name, c.timingBuckets, float64(value)/float64(time.Millisecond), tags)
+}
+
+// ServeHTTP writes the metrics in the text exposition format.
+func (c *Collector) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Content-Type", ContentType)
+	rw.WriteHeader(http.StatusOK)
+	c.WriteTo(rw)
+}
+
+// WriteTo writes the metrics in the text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	buf := new(bytes.Buffer)
+
+	c.Lock()
+	names := make([]string, 0, len(c.families))
+	for name := range c.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := c.families[name]
+		buf.WriteString("# TYPE " + f.name + " " + f.metricType + "\n")
+
+		labels := make([]string, 0, len(f.series))
+		for key := range f.series {
+			labels = append(labels, key)
+		}
+		sort.Strings(labels)
+		for _, key := range labels {
+			s := f.series[key]
+			if f.metricType != MetricTypeHistogram {
+				writeSample(buf, f.name, s.labels, "", formatFloat(s.value))
+				continue
+			}
+			for index, upperBound := range s.buckets {
+				writeSample(buf, f.name+"_bucket", s.labels, "le=\""+formatFloat(upperBound)+"\"", strconv.FormatUint(s.bucketCounts[index], 10))
+			}
+			writeSample(buf, f.name+"_bucket", s.labels, "le=\"+Inf\"", strconv.FormatUint(s.count, 10))
+			writeSample(buf, f.name+"_sum", s.labels, "", formatFloat(s.value))
+			writeSample(buf, f.name+"_count", s.labels, "", strconv.FormatUint(s.count, 10))
+		}
+	}
+	c.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// observe adds a value to a histogram; it must be called with the lock held.
+func (c *Collector) observe(name string, defaultBuckets []float64, value float64, tags []string) error {
+	s, err := c.series(name, MetricTypeHistogram, defaultBuckets, tags)
+	if err != nil {
+		return err
+	}
+	for index, upperBound := range s.buckets {
+		if value <= upperBound {
+			s.bucketCounts[index]++
+		}
+	}
+	s.count++
+	s.value += value
+	return nil
+}
+
+// series returns the series for a metric name and tags, creating it if needed.
+// It must be called with the lock held.
+func (c *Collector) series(name, metricType string, defaultBuckets []float64, tags []string) (*series, error) {
+	metricName := c.metricName(name)
+	f, ok := c.families[metricName]
+	if !ok {
+		f = &family{name: metricName, metricType: metricType, series: map[string]*series{}}
+		c.families[metricName] = f
+	} else if f.metricType != metricType {
+		return nil, exception.New(ErrMetricTypeMismatch).WithMessagef("metric: %s, type: %s, requested: %s", metricName, f.metricType, metricType)
+	}
+
+	labels := Labels(append(append([]string{}, c.defaultTags...), tags...)...)
+	s, ok := f.series[labels]
+	if !ok {
+		s = &series{labels: labels}
+		if metricType == MetricTypeHistogram {
+			if buckets, hasBuckets := c.metricBuckets[metricName]; hasBuckets {
+				s.buckets = buckets
+			} else {
+				s.buckets = defaultBuckets
+			}
+			s.bucketCounts = make([]uint64, len(s.buckets))
+		}
+		f.series[labels] = s
+	}
+	return s, nil
+}
+
+// metricName returns the namespaced, sanitized metric name.
+func (c *Collector) metricName(name string) string {
+	if len(c.namespace) > 0 {
+		return SanitizeName(c.namespace + "_" + name)
+	}
+	return SanitizeName(name)
+}
+
+// Labels returns the label set for a list of tags, sorted by label name.
+// Tags of the form `key:value` map to `key="value"`; other tags map to `tag="value"`.
+// If a label name is repeated, the last value wins.
+func Labels(tags ...string) string {
+	values := map[string]string{}
+	for _, tag := range tags {
+		if index := strings.Index(tag, ":"); index > 0 {
+			values[SanitizeName(tag[:index])] = tag[index+1:]
+		} else {
+			values[LabelTag] = tag
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"=\""+escapeLabelValue(values[key])+"\"")
+	}
+	return strings.Join(pairs, ",")
+}
+
+// SanitizeName replaces characters that are not valid in prometheus metric
+// and label names with underscores, e.g. `http.request.elapsed` becomes `http_request_elapsed`.
+// Names that start with a digit are prefixed with an underscore.
+func SanitizeName(name string) string {
+	output := []byte(name)
+	for index, c := range output {
+		isValid := c == '_' || c == ':' ||
+			(c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9')
+		if !isValid {
+			output[index] = '_'
+		}
+	}
+	if len(output) > 0 && output[0] >= '0' && output[0] <= '9' {
+		return "_" + string(output)
+	}
+	return string(output)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(value string) string {
+	return labelValueReplacer.Replace(value)
+}
+
+func writeSample(buf *bytes.Buffer, name, labels, extraLabel, value string) {
+	buf.WriteString(name)
+	if len(labels) > 0 || len(extraLabel) > 0 {
+		buf.WriteByte('{')
+		buf.WriteString(labels)
+		if len(labels) > 0 && len(extraLabel) > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(extraLabel)
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,95 @@
+package prometheus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	c := NewFromConfig(&Config{
+		Namespace:   "app",
+		DefaultTags: []string{"env:test"},
+	})
+	assert.Nil(c.Increment("http.request", "route:/foo", "method:GET"))
+	assert.Nil(c.Count("http.request", 2, "route:/foo", "method:GET"))
+	assert.Nil(c.Gauge("queue.depth", 3.5, "canary"))
+
+	buf := new(bytes.Buffer)
+	_, err := c.WriteTo(buf)
+	assert.Nil(err)
+	assert.Equal(strings.Join([]string{
+		"# TYPE app_http_request counter",
+		`app_http_request{env="test",method="GET",route="/foo"} 3`,
+		"# TYPE app_queue_depth gauge",
+		`app_queue_depth{env="test",tag="canary"} 3.5`,
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestCollectorHistogram(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New().WithMetricBuckets("size", 100, 10)
+	assert.Equal([]float64{10, 100}, c.MetricBuckets("size"))
+	assert.Nil(c.Histogram("size", 5))
+	assert.Nil(c.Histogram("size", 50))
+	assert.Nil(c.Histogram("size", 500))
+	assert.Nil(c.TimeInMilliseconds("elapsed", 3*time.Millisecond))
+
+	buf := new(bytes.Buffer)
+	_, err := c.WriteTo(buf)
+	assert.Nil(err)
+	output := buf.String()
+	assert.Contains(output, "# TYPE size histogram\n")
+	assert.Contains(output, `size_bucket{le="10"} 1`)
+	assert.Contains(output, `size_bucket{le="100"} 2`)
+	assert.Contains(output, `size_bucket{le="+Inf"} 3`)
+	assert.Contains(output, "size_sum 555\n")
+	assert.Contains(output, "size_count 3\n")
+	assert.Contains(output, `elapsed_bucket{le="2.5"} 0`)
+	assert.Contains(output, `elapsed_bucket{le="5"} 1`)
+}
+
+func TestCollectorTypeMismatch(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New()
+	assert.Nil(c.Increment("foo"))
+	assert.True(exception.Is(c.Gauge("foo", 1), ErrMetricTypeMismatch))
+}
+
+func TestCollectorHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	c := New()
+	assert.Nil(c.Increment("foo"))
+
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + DefaultPath)
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(ContentType, res.Header.Get("Content-Type"))
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal("# TYPE foo counter\nfoo 1\n", string(contents))
+}
+
+func TestLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`a_b="c:d",tag="e\"f"`, Labels("a.b:c:d", `e"f`))
+	assert.Equal("", Labels())
+	assert.Equal("_9lives", SanitizeName("9lives"))
+}
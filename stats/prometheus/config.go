@@ -0,0 +1,77 @@
+package prometheus
+
+import (
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/util"
+)
+
+// NewConfigFromEnv returns a new config from the env.
+func NewConfigFromEnv() (*Config, error) {
+	var config Config
+	if err := env.Env().ReadInto(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// MustNewConfigFromEnv creates a new config from the environment and panics on error.
+func MustNewConfigFromEnv() (config *Config) {
+	var err error
+	if config, err = NewConfigFromEnv(); err != nil {
+		panic(err)
+	}
+	return
+}
+
+// Config is the prometheus collector config.
+type Config struct {
+	// Namespace is an optional prefix for metric names.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty" env:"PROMETHEUS_NAMESPACE"`
+	// Path is the path the metrics handler should be mounted on.
+	Path string `json:"path,omitempty" yaml:"path,omitempty" env:"PROMETHEUS_PATH"`
+	// DefaultTags are the default tags associated with any metric, as `key:value` pairs.
+	DefaultTags []string `json:"defaultTags,omitempty" yaml:"defaultTags,omitempty" env:"PROMETHEUS_TAGS,csv"`
+	// Buckets are the default histogram buckets.
+	Buckets []float64 `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+	// TimingBuckets are the default histogram buckets for timings, in milliseconds.
+	TimingBuckets []float64 `json:"timingBuckets,omitempty" yaml:"timingBuckets,omitempty"`
+	// MetricBuckets are histogram buckets for specific metric names.
+	MetricBuckets map[string][]float64 `json:"metricBuckets,omitempty" yaml:"metricBuckets,omitempty"`
+}
+
+// GetNamespace returns the default prefix for metric names.
+func (c Config) GetNamespace(defaults ...string) string {
+	return util.Coalesce.String(c.Namespace, "", defaults...)
+}
+
+// GetPath returns the metrics handler path.
+func (c Config) GetPath(defaults ...string) string {
+	return util.Coalesce.String(c.Path, DefaultPath, defaults...)
+}
+
+// GetDefaultTags returns default tags for the collector.
+func (c Config) GetDefaultTags(defaults ...[]string) []string {
+	return util.Coalesce.Strings(c.DefaultTags, nil, defaults...)
+}
+
+// GetBuckets returns the default histogram buckets.
+func (c Config) GetBuckets(defaults ...[]float64) []float64 {
+	if len(c.Buckets) > 0 {
+		return c.Buckets
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultBuckets
+}
+
+// GetTimingBuckets returns the default histogram buckets for timings.
+func (c Config) GetTimingBuckets(defaults ...[]float64) []float64 {
+	if len(c.TimingBuckets) > 0 {
+		return c.TimingBuckets
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return DefaultTimingBuckets
+}
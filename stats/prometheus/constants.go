@@ -0,0 +1,30 @@
+package prometheus
+
+import "github.com/blend/go-sdk/exception"
+
+const (
+	// DefaultPath is the default path the metrics handler is mounted on.
+	DefaultPath = "/metrics"
+	// ContentType is the text exposition format content type.
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// LabelTag is the label name used for tags without a `key:value` separator.
+	LabelTag = "tag"
+
+	// ErrMetricTypeMismatch is returned when a metric name is reused with a different metric type.
+	ErrMetricTypeMismatch exception.Class = "prometheus: metric type mismatch"
+)
+
+// Metric types.
+const (
+	MetricTypeCounter   = "counter"
+	MetricTypeGauge     = "gauge"
+	MetricTypeHistogram = "histogram"
+)
+
+var (
+	// DefaultBuckets are the default histogram buckets.
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefaultTimingBuckets are the default histogram buckets for timings, in milliseconds.
+	DefaultTimingBuckets = []float64{1, 2.5, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+)
@@ -1,60 +1,189 @@
 package stats
 
 import (
+	"os"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/blend/go-sdk/async"
 	"github.com/blend/go-sdk/logger"
 )
 
-// Runtime reports golang vm runtime stats.
+const (
+	// DefaultRuntimeInterval is the default interval runtime stats are reported on.
+	DefaultRuntimeInterval = 250 * time.Millisecond
+	// DefaultRuntimePrefix is the default prefix for runtime stat names.
+	DefaultRuntimePrefix = "go.runtime"
+)
+
+// Runtime reports golang vm runtime stats with the default interval and prefix.
+// It runs for the life of the process; use a `RuntimeReporter` to control the interval or stop reporting.
 func Runtime(log *logger.Logger, collector Collector) {
 	if collector == nil {
 		return
 	}
+	NewRuntimeReporter(collector).Start()
+}
+
+// NewRuntimeReporter returns a new runtime reporter for a collector.
+// It must be started with `.Start()` to report on the interval.
+func NewRuntimeReporter(collector Collector) *RuntimeReporter {
+	return &RuntimeReporter{
+		collector: collector,
+		interval:  DefaultRuntimeInterval,
+		prefix:    DefaultRuntimePrefix,
+	}
+}
+
+// RuntimeReporter periodically reports golang vm runtime stats, i.e. goroutines, heap,
+// gc pause percentiles and open file descriptors, to a collector.
+type RuntimeReporter struct {
+	sync.Mutex
+
+	collector Collector
+	interval  time.Duration
+	prefix    string
+
+	previous runtime.MemStats
+	current  runtime.MemStats
+
+	reporter *async.Interval
+}
+
+// WithInterval sets the report interval.
+func (rr *RuntimeReporter) WithInterval(interval time.Duration) *RuntimeReporter {
+	rr.interval = interval
+	return rr
+}
+
+// Interval returns the report interval.
+func (rr *RuntimeReporter) Interval() time.Duration {
+	return rr.interval
+}
+
+// WithPrefix sets the stat name prefix.
+func (rr *RuntimeReporter) WithPrefix(prefix string) *RuntimeReporter {
+	rr.prefix = prefix
+	return rr
+}
+
+// Prefix returns the stat name prefix.
+func (rr *RuntimeReporter) Prefix() string {
+	return rr.prefix
+}
+
+// Start starts reporting on the interval.
+func (rr *RuntimeReporter) Start() *RuntimeReporter {
+	rr.Lock()
+	defer rr.Unlock()
+	if rr.reporter != nil {
+		return rr
+	}
+	runtime.ReadMemStats(&rr.previous)
+	rr.reporter = async.NewInterval(func() error {
+		rr.Report()
+		return nil
+	}, rr.interval)
+	rr.reporter.Start()
+	return rr
+}
 
-	go func() {
-		var previous, current runtime.MemStats
-		runtimeCollect(log, collector, &previous, &current)
-		for {
-			select {
-			case <-time.After(250 * time.Millisecond):
-				runtimeCollect(log, collector, &previous, &current)
-			}
-		}
-	}()
+// Stop stops reporting.
+func (rr *RuntimeReporter) Stop() {
+	rr.Lock()
+	reporter := rr.reporter
+	rr.reporter = nil
+	rr.Unlock()
+
+	if reporter != nil {
+		reporter.Stop()
+	}
 }
 
-func runtimeCollect(log *logger.Logger, collector Collector, previous, current *runtime.MemStats) {
+// Report reports the runtime stats once.
+// Counters (gcs, pauses, mallocs and frees) are reported as the change since the last report.
+func (rr *RuntimeReporter) Report() {
+	rr.Lock()
+	defer rr.Unlock()
+
+	current, previous := &rr.current, &rr.previous
 	runtime.ReadMemStats(current)
 
 	// these depend on the previous values
-	collector.Gauge("go.runtime.mem.num_gc", float64(current.NumGC-previous.NumGC))
-	collector.Gauge("go.runtime.mem.num_forced_gc", float64(current.NumForcedGC-previous.NumForcedGC))
-	collector.Gauge("go.runtime.mem.pause_total_ns", float64(current.PauseTotalNs-previous.PauseTotalNs))
-	collector.Gauge("go.runtime.mem.frees", float64(current.Frees-previous.Frees))
-	collector.Gauge("go.runtime.mem.mallocs", float64(current.Mallocs-previous.Mallocs))
+	rr.gauge("mem.num_gc", float64(current.NumGC-previous.NumGC))
+	rr.gauge("mem.num_forced_gc", float64(current.NumForcedGC-previous.NumForcedGC))
+	rr.gauge("mem.pause_total_ns", float64(current.PauseTotalNs-previous.PauseTotalNs))
+	rr.gauge("mem.frees", float64(current.Frees-previous.Frees))
+	rr.gauge("mem.mallocs", float64(current.Mallocs-previous.Mallocs))
+	if pauses := gcPauses(previous, current); len(pauses) > 0 {
+		rr.gauge("mem.pause_ns.p50", percentileFloat64(pauses, 0.50))
+		rr.gauge("mem.pause_ns.p95", percentileFloat64(pauses, 0.95))
+		rr.gauge("mem.pause_ns.p99", percentileFloat64(pauses, 0.99))
+		rr.gauge("mem.pause_ns.max", pauses[len(pauses)-1])
+	}
 
 	// these are mostly points in time.
-	collector.Gauge("go.runtime.num_cpu", float64(runtime.NumCPU()))
-	collector.Gauge("go.runtime.num_goroutine", float64(runtime.NumGoroutine()))
+	rr.gauge("num_cpu", float64(runtime.NumCPU()))
+	rr.gauge("num_goroutine", float64(runtime.NumGoroutine()))
+	if openFDs, ok := openFileDescriptors(); ok {
+		rr.gauge("num_open_fds", float64(openFDs))
+	}
 
-	collector.Gauge("go.runtime.mem.alloc", float64(current.Alloc))
+	rr.gauge("mem.alloc", float64(current.Alloc))
 
-	collector.Gauge("go.runtime.mem.gc_sys", float64(current.GCSys))
-	collector.Gauge("go.runtime.mem.other_sys", float64(current.OtherSys))
+	rr.gauge("mem.gc_sys", float64(current.GCSys))
+	rr.gauge("mem.other_sys", float64(current.OtherSys))
 
-	collector.Gauge("go.runtime.mem.heap_alloc", float64(current.HeapAlloc))
-	collector.Gauge("go.runtime.mem.heap_idle", float64(current.HeapIdle))
-	collector.Gauge("go.runtime.mem.heap_inuse", float64(current.HeapInuse))
-	collector.Gauge("go.runtime.mem.heap_objects", float64(current.HeapObjects))
-	collector.Gauge("go.runtime.mem.heap_sys", float64(current.HeapSys))
+	rr.gauge("mem.heap_alloc", float64(current.HeapAlloc))
+	rr.gauge("mem.heap_idle", float64(current.HeapIdle))
+	rr.gauge("mem.heap_inuse", float64(current.HeapInuse))
+	rr.gauge("mem.heap_objects", float64(current.HeapObjects))
+	rr.gauge("mem.heap_sys", float64(current.HeapSys))
 
-	collector.Gauge("go.runtime.mem.stack_inuse", float64(current.StackInuse))
-	collector.Gauge("go.runtime.mem.stack_sys", float64(current.StackSys))
-	collector.Gauge("go.runtime.mem.sys", float64(current.Sys))
-	collector.Gauge("go.runtime.mem.total_alloc", float64(current.TotalAlloc))
+	rr.gauge("mem.stack_inuse", float64(current.StackInuse))
+	rr.gauge("mem.stack_sys", float64(current.StackSys))
+	rr.gauge("mem.sys", float64(current.Sys))
+	rr.gauge("mem.total_alloc", float64(current.TotalAlloc))
 
 	// rotate the results ...
 	*previous = *current
 }
+
+func (rr *RuntimeReporter) gauge(name string, value float64) {
+	if len(rr.prefix) > 0 {
+		name = rr.prefix + "." + name
+	}
+	rr.collector.Gauge(name, value)
+}
+
+// gcPauses returns the sorted gc pause times since the previous stats.
+// The runtime only keeps the most recent 256 pauses.
+func gcPauses(previous, current *runtime.MemStats) []float64 {
+	count := int(current.NumGC - previous.NumGC)
+	if count > len(current.PauseNs) {
+		count = len(current.PauseNs)
+	}
+	pauses := make([]float64, 0, count)
+	for index := 0; index < count; index++ {
+		pauses = append(pauses, float64(current.PauseNs[(int(current.NumGC)-1-index+len(current.PauseNs))%len(current.PauseNs)]))
+	}
+	sort.Float64s(pauses)
+	return pauses
+}
+
+// openFileDescriptors returns the number of open file descriptors, where the os exposes them.
+func openFileDescriptors() (int, bool) {
+	dir, err := os.Open("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	defer dir.Close()
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, false
+	}
+	// the directory handle itself is one of the entries.
+	return len(names) - 1, true
+}
@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRuntimeReporterReport(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := NewMockCollector()
+	reporter := NewRuntimeReporter(collector).WithPrefix("test.runtime").WithInterval(time.Second)
+	assert.Equal("test.runtime", reporter.Prefix())
+	assert.Equal(time.Second, reporter.Interval())
+
+	runtime.GC()
+	go reporter.Report()
+
+	names := map[string]float64{}
+	for {
+		metric := <-collector.Events
+		assert.True(strings.HasPrefix(metric.Name, "test.runtime."), metric.Name)
+		names[metric.Name] = metric.Gauge
+		if metric.Name == "test.runtime.mem.total_alloc" {
+			break
+		}
+	}
+	assert.NotZero(names["test.runtime.num_goroutine"])
+	assert.NotZero(names["test.runtime.mem.heap_alloc"])
+	assert.NotZero(names["test.runtime.mem.num_gc"])
+	_, hasPause := names["test.runtime.mem.pause_ns.p99"]
+	assert.True(hasPause)
+}
+
+func TestRuntimeReporterStartStop(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := NewMockCollector()
+	reporter := NewRuntimeReporter(collector).WithInterval(time.Millisecond).Start()
+
+	metric := <-collector.Events
+	assert.True(strings.HasPrefix(metric.Name, DefaultRuntimePrefix+"."))
+
+	stopped := make(chan struct{})
+	go func() {
+		reporter.Stop()
+		close(stopped)
+	}()
+	for {
+		select {
+		case <-collector.Events:
+		case <-stopped:
+			return
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(5, percentileFloat64(values, 0.5))
+	assert.Equal(10, percentileFloat64(values, 0.95))
+	assert.Equal(10, percentileFloat64(values, 0.99))
+	assert.Equal(7, percentileFloat64([]float64{7}, 0.5))
+}
@@ -0,0 +1,193 @@
+package statstest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/stats"
+)
+
+// Asserts the collector is a stats collector.
+var (
+	_ stats.Collector = (*Collector)(nil)
+)
+
+// Metric types.
+const (
+	MetricTypeCount     = "count"
+	MetricTypeGauge     = "gauge"
+	MetricTypeHistogram = "histogram"
+	MetricTypeTiming    = "timing"
+)
+
+// Metric is a recorded metric.
+// Timings are recorded in milliseconds.
+type Metric struct {
+	Type  string
+	Name  string
+	Value float64
+	Tags  []string
+}
+
+// HasTags returns if the metric has all of the given tags.
+func (m Metric) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		var found bool
+		for _, metricTag := range m.Tags {
+			if metricTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// New returns a new collector.
+func New() *Collector {
+	return &Collector{}
+}
+
+// Collector is a stats collector that records metrics in memory for assertions in tests.
+// Default tags are included in the recorded tags.
+type Collector struct {
+	sync.Mutex
+	defaultTags []string
+	metrics     []Metric
+}
+
+// AddDefaultTag adds a default tag.
+func (c *Collector) AddDefaultTag(key, value string) {
+	c.Lock()
+	c.defaultTags = append(c.defaultTags, stats.Tag(key, value))
+	c.Unlock()
+}
+
+// DefaultTags returns the default tags.
+func (c *Collector) DefaultTags() []string {
+	c.Lock()
+	defer c.Unlock()
+	return c.defaultTags
+}
+
+// Count records a count.
+func (c *Collector) Count(name string, value int64, tags ...string) error {
+	c.record(MetricTypeCount, name, float64(value), tags)
+	return nil
+}
+
+// Increment records a count of 1.
+func (c *Collector) Increment(name string, tags ...string) error {
+	c.record(MetricTypeCount, name, 1, tags)
+	return nil
+}
+
+// Gauge records a gauge.
+func (c *Collector) Gauge(name string, value float64, tags ...string) error {
+	c.record(MetricTypeGauge, name, value, tags)
+	return nil
+}
+
+// Histogram records a histogram value.
+func (c *Collector) Histogram(name string, value float64, tags ...string) error {
+	c.record(MetricTypeHistogram, name, value, tags)
+	return nil
+}
+
+// TimeInMilliseconds records a timing in milliseconds.
+func (c *Collector) TimeInMilliseconds(name string, value time.Duration, tags ...string) error {
+	c.record(MetricTypeTiming, name, float64(value)/float64(time.Millisecond), tags)
+	return nil
+}
+
+// Reset removes any recorded metrics.
+func (c *Collector) Reset() {
+	c.Lock()
+	c.metrics = nil
+	c.Unlock()
+}
+
+// Metrics returns the recorded metrics in the order they were emitted.
+func (c *Collector) Metrics() []Metric {
+	c.Lock()
+	defer c.Unlock()
+	output := make([]Metric, len(c.metrics))
+	copy(output, c.metrics)
+	return output
+}
+
+// MetricsFor returns the recorded metrics with a name and all of the given tags.
+func (c *Collector) MetricsFor(name string, tags ...string) []Metric {
+	var output []Metric
+	for _, metric := range c.Metrics() {
+		if metric.Name == name && metric.HasTags(tags...) {
+			output = append(output, metric)
+		}
+	}
+	return output
+}
+
+// Has returns if any metric with a name and all of the given tags was recorded.
+func (c *Collector) Has(name string, tags ...string) bool {
+	return len(c.MetricsFor(name, tags...)) > 0
+}
+
+// CountOf returns the sum of the counts with a name and all of the given tags.
+func (c *Collector) CountOf(name string, tags ...string) int64 {
+	var total int64
+	for _, metric := range c.metricsOfType(MetricTypeCount, name, tags) {
+		total += int64(metric.Value)
+	}
+	return total
+}
+
+// GaugeOf returns the last gauge value with a name and all of the given tags, and if one was recorded.
+func (c *Collector) GaugeOf(name string, tags ...string) (value float64, ok bool) {
+	metrics := c.metricsOfType(MetricTypeGauge, name, tags)
+	if len(metrics) == 0 {
+		return 0, false
+	}
+	return metrics[len(metrics)-1].Value, true
+}
+
+// HistogramValues returns the histogram values with a name and all of the given tags.
+func (c *Collector) HistogramValues(name string, tags ...string) []float64 {
+	return values(c.metricsOfType(MetricTypeHistogram, name, tags))
+}
+
+// Timings returns the timing values, in milliseconds, with a name and all of the given tags.
+func (c *Collector) Timings(name string, tags ...string) []float64 {
+	return values(c.metricsOfType(MetricTypeTiming, name, tags))
+}
+
+func (c *Collector) metricsOfType(metricType, name string, tags []string) []Metric {
+	var output []Metric
+	for _, metric := range c.MetricsFor(name, tags...) {
+		if metric.Type == metricType {
+			output = append(output, metric)
+		}
+	}
+	return output
+}
+
+func (c *Collector) record(metricType, name string, value float64, tags []string) {
+	c.Lock()
+	defer c.Unlock()
+	c.metrics = append(c.metrics, Metric{
+		Type:  metricType,
+		Name:  name,
+		Value: value,
+		Tags:  append(append([]string{}, c.defaultTags...), tags...),
+	})
+}
+
+func values(metrics []Metric) []float64 {
+	output := make([]float64, 0, len(metrics))
+	for _, metric := range metrics {
+		output = append(output, metric.Value)
+	}
+	return output
+}
@@ -0,0 +1,48 @@
+package statstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	collector := New()
+	collector.AddDefaultTag("env", "test")
+
+	collector.Increment("requests", "route:/foo")
+	collector.Count("requests", 2, "route:/foo", "status:500")
+	collector.Increment("requests", "route:/bar")
+	collector.Gauge("queue", 1)
+	collector.Gauge("queue", 3)
+	collector.Histogram("size", 10, "route:/foo")
+	collector.Histogram("size", 20, "route:/bar")
+	collector.TimeInMilliseconds("elapsed", 1500*time.Microsecond)
+
+	assert.Len(collector.Metrics(), 8)
+	assert.Equal([]string{"env:test", "route:/bar"}, collector.MetricsFor("requests", "route:/bar")[0].Tags)
+
+	assert.Equal(4, collector.CountOf("requests"))
+	assert.Equal(3, collector.CountOf("requests", "route:/foo"))
+	assert.Equal(2, collector.CountOf("requests", "route:/foo", "status:500"))
+	assert.Zero(collector.CountOf("requests", "route:/baz"))
+
+	value, ok := collector.GaugeOf("queue", "env:test")
+	assert.True(ok)
+	assert.Equal(3, value)
+	_, ok = collector.GaugeOf("missing")
+	assert.False(ok)
+
+	assert.Equal([]float64{10, 20}, collector.HistogramValues("size"))
+	assert.Equal([]float64{20}, collector.HistogramValues("size", "route:/bar"))
+	assert.Equal([]float64{1.5}, collector.Timings("elapsed"))
+
+	assert.True(collector.Has("elapsed"))
+	assert.False(collector.Has("elapsed", "route:/foo"))
+
+	collector.Reset()
+	assert.Empty(collector.Metrics())
+}
@@ -0,0 +1,2 @@
+// Package statstest provides an in memory stats collector with helpers to assert on emitted metrics in tests.
+package statstest
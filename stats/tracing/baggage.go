@@ -0,0 +1,158 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/blend/go-sdk/exception"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Baggage keys.
+const (
+	// BaggageKeyTenantID is the tenant id baggage key.
+	BaggageKeyTenantID = "tenant-id"
+	// BaggageKeyRequestID is the request id baggage key.
+	BaggageKeyRequestID = "request-id"
+)
+
+// Baggage defaults.
+const (
+	// DefaultMaxBaggageItemSize is the default maximum length of a baggage value.
+	DefaultMaxBaggageItemSize = 128
+	// DefaultMaxBaggageSize is the default maximum length of all baggage keys and values on a span.
+	DefaultMaxBaggageSize = 1024
+)
+
+// Baggage errors.
+const (
+	// ErrBaggageKeyNotAllowed is returned when a baggage key is not in the allow list.
+	ErrBaggageKeyNotAllowed exception.Class = "baggage key not allowed"
+	// ErrBaggageItemTooLarge is returned when a baggage value is longer than the maximum item size.
+	ErrBaggageItemTooLarge exception.Class = "baggage item too large"
+	// ErrBaggageTooLarge is returned when setting a baggage item would exceed the maximum baggage size.
+	ErrBaggageTooLarge exception.Class = "baggage too large"
+)
+
+var (
+	// DefaultBaggage is the baggage policy used by the typed helpers, e.g. `SetTenantID`.
+	DefaultBaggage = NewBaggage(BaggageKeyTenantID, BaggageKeyRequestID)
+)
+
+// NewBaggage returns a new baggage policy that allows a given set of keys.
+func NewBaggage(allowedKeys ...string) *Baggage {
+	allowed := map[string]bool{}
+	for _, key := range allowedKeys {
+		allowed[strings.ToLower(key)] = true
+	}
+	return &Baggage{
+		allowed:     allowed,
+		maxItemSize: DefaultMaxBaggageItemSize,
+		maxSize:     DefaultMaxBaggageSize,
+	}
+}
+
+// Baggage is a policy for setting and reading span baggage items.
+// Baggage is propagated to every downstream service in request headers, so only
+// allowed keys can be set or read, and values and the total baggage size are limited.
+type Baggage struct {
+	allowed     map[string]bool
+	maxItemSize int
+	maxSize     int
+}
+
+// WithMaxItemSize sets the maximum length of a baggage value.
+func (b *Baggage) WithMaxItemSize(maxItemSize int) *Baggage {
+	b.maxItemSize = maxItemSize
+	return b
+}
+
+// MaxItemSize returns the maximum length of a baggage value.
+func (b *Baggage) MaxItemSize() int {
+	return b.maxItemSize
+}
+
+// WithMaxSize sets the maximum length of all baggage keys and values on a span.
+func (b *Baggage) WithMaxSize(maxSize int) *Baggage {
+	b.maxSize = maxSize
+	return b
+}
+
+// MaxSize returns the maximum length of all baggage keys and values on a span.
+func (b *Baggage) MaxSize() int {
+	return b.maxSize
+}
+
+// IsAllowed returns if a key is in the allow list.
+func (b *Baggage) IsAllowed(key string) bool {
+	return b.allowed[strings.ToLower(key)]
+}
+
+// Set sets a baggage item on a span.
+func (b *Baggage) Set(span opentracing.Span, key, value string) error {
+	if span == nil {
+		return nil
+	}
+	if !b.IsAllowed(key) {
+		return exception.New(ErrBaggageKeyNotAllowed).WithMessagef("key: %s", key)
+	}
+	if len(value) > b.maxItemSize {
+		return exception.New(ErrBaggageItemTooLarge).WithMessagef("key: %s, size: %d, max: %d", key, len(value), b.maxItemSize)
+	}
+
+	size := len(key) + len(value)
+	span.Context().ForeachBaggageItem(func(k, v string) bool {
+		if !strings.EqualFold(k, key) {
+			size += len(k) + len(v)
+		}
+		return true
+	})
+	if size > b.maxSize {
+		return exception.New(ErrBaggageTooLarge).WithMessagef("key: %s, size: %d, max: %d", key, size, b.maxSize)
+	}
+	span.SetBaggageItem(key, value)
+	return nil
+}
+
+// Get returns a baggage item from a span.
+// Keys that are not allowed, or values that are too large, read as empty.
+func (b *Baggage) Get(span opentracing.Span, key string) string {
+	if span == nil || !b.IsAllowed(key) {
+		return ""
+	}
+	value := span.BaggageItem(key)
+	if len(value) > b.maxItemSize {
+		return ""
+	}
+	return value
+}
+
+// SetFromContext sets a baggage item on the active span in a context.
+func (b *Baggage) SetFromContext(ctx context.Context, key, value string) error {
+	return b.Set(opentracing.SpanFromContext(ctx), key, value)
+}
+
+// GetFromContext returns a baggage item from the active span in a context.
+func (b *Baggage) GetFromContext(ctx context.Context, key string) string {
+	return b.Get(opentracing.SpanFromContext(ctx), key)
+}
+
+// SetTenantID sets the tenant id baggage item on the active span in a context.
+func SetTenantID(ctx context.Context, tenantID string) error {
+	return DefaultBaggage.SetFromContext(ctx, BaggageKeyTenantID, tenantID)
+}
+
+// GetTenantID returns the tenant id baggage item from the active span in a context.
+func GetTenantID(ctx context.Context) string {
+	return DefaultBaggage.GetFromContext(ctx, BaggageKeyTenantID)
+}
+
+// SetRequestID sets the request id baggage item on the active span in a context.
+func SetRequestID(ctx context.Context, requestID string) error {
+	return DefaultBaggage.SetFromContext(ctx, BaggageKeyRequestID, requestID)
+}
+
+// GetRequestID returns the request id baggage item from the active span in a context.
+func GetRequestID(ctx context.Context) string {
+	return DefaultBaggage.GetFromContext(ctx, BaggageKeyRequestID)
+}
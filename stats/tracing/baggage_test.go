@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestBaggage(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test")
+	baggage := NewBaggage("allowed", "other").WithMaxItemSize(8).WithMaxSize(24)
+
+	assert.Nil(baggage.Set(span, "allowed", "value"))
+	assert.Equal("value", baggage.Get(span, "allowed"))
+
+	err := baggage.Set(span, "secret", "value")
+	assert.True(exception.Is(err, ErrBaggageKeyNotAllowed))
+	assert.Empty(span.BaggageItem("secret"))
+
+	err = baggage.Set(span, "other", "too long value")
+	assert.True(exception.Is(err, ErrBaggageItemTooLarge))
+
+	// "allowed"+"value" is 12, so another 13 would exceed 24.
+	err = baggage.Set(span, "other", "12345678")
+	assert.True(exception.Is(err, ErrBaggageTooLarge))
+	// replacing an existing item does not count it twice.
+	assert.Nil(baggage.Set(span, "allowed", "12345678"))
+
+	span.SetBaggageItem("secret", "leaked")
+	assert.Empty(baggage.Get(span, "secret"))
+	span.SetBaggageItem("other", strings.Repeat("x", 16))
+	assert.Empty(baggage.Get(span, "other"))
+
+	assert.Nil(baggage.Set(nil, "allowed", "value"))
+	assert.Empty(baggage.Get(nil, "allowed"))
+}
+
+func TestBaggageHelpers(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	_, ctx := StartSpanFromContext(context.Background(), tracer, "test")
+	assert.Nil(SetTenantID(ctx, "tenant-1"))
+	assert.Nil(SetRequestID(ctx, "request-1"))
+	assert.Equal("tenant-1", GetTenantID(ctx))
+	assert.Equal("request-1", GetRequestID(ctx))
+
+	_, childCtx := StartSpanFromContext(ctx, tracer, "child")
+	assert.Equal("tenant-1", GetTenantID(childCtx))
+	assert.Empty(GetTenantID(context.Background()))
+}
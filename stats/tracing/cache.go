@@ -0,0 +1,117 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Cache operations and tags.
+const (
+	// OperationRedisCommand is the redis command tracing operation.
+	OperationRedisCommand = "redis.command"
+	// OperationMemcachedCommand is the memcached command tracing operation.
+	OperationMemcachedCommand = "memcached.command"
+
+	// TagKeyRedisRawCommand is the raw redis command, optionally scrubbed and truncated.
+	TagKeyRedisRawCommand = "redis.raw_command"
+	// TagKeyMemcachedRawCommand is the raw memcached command, optionally scrubbed and truncated.
+	TagKeyMemcachedRawCommand = "memcached.raw_command"
+	// TagKeyCacheArgsLength is the number of command arguments.
+	TagKeyCacheArgsLength = "cache.args_length"
+
+	// DefaultMaxRawCommandLength is the default maximum length of the raw command tag.
+	DefaultMaxRawCommandLength = 350
+)
+
+// NewCacheTracer returns a new cache tracer with default settings.
+func NewCacheTracer(tracer opentracing.Tracer) *CacheTracer {
+	return &CacheTracer{
+		tracer:              tracer,
+		maxRawCommandLength: DefaultMaxRawCommandLength,
+	}
+}
+
+// CacheTracer starts redis and memcached spans for cache client wrappers.
+// Spans use the command as the resource, and tag the raw command, which can be
+// scrubbed so only the command and first key are recorded, and truncated.
+type CacheTracer struct {
+	tracer              opentracing.Tracer
+	maxRawCommandLength int
+	scrubArgs           bool
+}
+
+// Tracer returns the tracer.
+func (ct *CacheTracer) Tracer() opentracing.Tracer {
+	return ct.tracer
+}
+
+// WithMaxRawCommandLength sets the maximum length of the raw command tag.
+// A length of zero omits the raw command tag.
+func (ct *CacheTracer) WithMaxRawCommandLength(maxRawCommandLength int) *CacheTracer {
+	ct.maxRawCommandLength = maxRawCommandLength
+	return ct
+}
+
+// MaxRawCommandLength returns the maximum length of the raw command tag.
+func (ct *CacheTracer) MaxRawCommandLength() int {
+	return ct.maxRawCommandLength
+}
+
+// WithScrubArgs sets if arguments after the first (i.e. values) are replaced with `?` in the raw command tag.
+func (ct *CacheTracer) WithScrubArgs(scrubArgs bool) *CacheTracer {
+	ct.scrubArgs = scrubArgs
+	return ct
+}
+
+// ScrubArgs returns if arguments after the first are replaced with `?` in the raw command tag.
+func (ct *CacheTracer) ScrubArgs() bool {
+	return ct.scrubArgs
+}
+
+// StartRedis starts a redis command span, e.g. `StartRedis(ctx, "SET", "user:1", value)`.
+func (ct *CacheTracer) StartRedis(ctx context.Context, command string, args ...string) (opentracing.Span, context.Context) {
+	return ct.start(ctx, OperationRedisCommand, SpanTypeRedis, TagKeyRedisRawCommand, command, args)
+}
+
+// StartMemcached starts a memcached command span, e.g. `StartMemcached(ctx, "get", "user:1")`.
+func (ct *CacheTracer) StartMemcached(ctx context.Context, command string, args ...string) (opentracing.Span, context.Context) {
+	return ct.start(ctx, OperationMemcachedCommand, SpanTypeMemcached, TagKeyMemcachedRawCommand, command, args)
+}
+
+// RawCommand returns the raw command tag value for a command and its arguments.
+func (ct *CacheTracer) RawCommand(command string, args ...string) string {
+	parts := make([]string, 0, len(args)+1)
+	parts = append(parts, command)
+	for index, arg := range args {
+		if ct.scrubArgs && index > 0 {
+			parts = append(parts, "?")
+		} else {
+			parts = append(parts, arg)
+		}
+	}
+	raw := strings.Join(parts, " ")
+	if len(raw) > ct.maxRawCommandLength {
+		if ct.maxRawCommandLength > 3 {
+			return raw[:ct.maxRawCommandLength-3] + "..."
+		}
+		return raw[:ct.maxRawCommandLength]
+	}
+	return raw
+}
+
+func (ct *CacheTracer) start(ctx context.Context, operationName, spanType, rawCommandTagKey, command string, args []string) (opentracing.Span, context.Context) {
+	startOptions := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: TagKeyResourceName, Value: strings.ToUpper(command)},
+		opentracing.Tag{Key: TagKeySpanType, Value: spanType},
+		opentracing.Tag{Key: TagKeyCacheArgsLength, Value: strconv.Itoa(len(args))},
+		opentracing.StartTime(time.Now().UTC()),
+	}
+	if ct.maxRawCommandLength > 0 {
+		startOptions = append(startOptions, opentracing.Tag{Key: rawCommandTagKey, Value: ct.RawCommand(command, args...)})
+	}
+	return StartSpanFromContext(ctx, ct.tracer, operationName, startOptions...)
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestCacheTracerStartRedis(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span, ctx := NewCacheTracer(tracer).StartRedis(context.Background(), "set", "user:1", "secret")
+	assert.NotNil(ctx)
+	span.Finish()
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(OperationRedisCommand, spans[0].OperationName)
+	assert.Equal(SpanTypeRedis, spans[0].Tag(TagKeySpanType))
+	assert.Equal("SET", spans[0].Tag(TagKeyResourceName))
+	assert.Equal("set user:1 secret", spans[0].Tag(TagKeyRedisRawCommand))
+	assert.Equal("2", spans[0].Tag(TagKeyCacheArgsLength))
+}
+
+func TestCacheTracerStartMemcached(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span, _ := NewCacheTracer(tracer).WithMaxRawCommandLength(0).StartMemcached(context.Background(), "get", "user:1")
+	span.Finish()
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(SpanTypeMemcached, spans[0].Tag(TagKeySpanType))
+	assert.Nil(spans[0].Tag(TagKeyMemcachedRawCommand))
+}
+
+func TestCacheTracerRawCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	ct := NewCacheTracer(mocktracer.New()).WithScrubArgs(true)
+	assert.Equal("MSET a ? ? ?", ct.RawCommand("MSET", "a", "1", "b", "2"))
+	assert.Equal("GET", ct.RawCommand("GET"))
+
+	ct = NewCacheTracer(mocktracer.New()).WithMaxRawCommandLength(10)
+	assert.Equal("SET key...", ct.RawCommand("SET", "key", strings.Repeat("x", 100)))
+}
@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/util"
+)
+
+const (
+	// DefaultSampleRate is the default fraction of traces that are kept.
+	DefaultSampleRate = 1.0
+)
+
+// NewSamplerConfigFromEnv returns a new sampler config from the environment.
+func NewSamplerConfigFromEnv() (*SamplerConfig, error) {
+	var config SamplerConfig
+	if err := env.Env().ReadInto(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// SamplerConfig is the sampling config for a service.
+type SamplerConfig struct {
+	// SampleRate is the fraction of traces kept if no rule matches, from 0 to 1.
+	SampleRate *float64 `json:"sampleRate,omitempty" yaml:"sampleRate,omitempty" env:"TRACING_SAMPLE_RATE"`
+	// Rules are sampling rates for specific operations and resources; the first matching rule applies.
+	Rules []SamplingRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// GetSampleRate returns the sample rate or a default.
+func (sc SamplerConfig) GetSampleRate(defaults ...float64) float64 {
+	if sc.SampleRate != nil {
+		return *sc.SampleRate
+	}
+	return util.Coalesce.Float64(0, DefaultSampleRate, defaults...)
+}
+
+// NewSamplerFromConfig returns a new rule sampler from a config.
+func NewSamplerFromConfig(cfg *SamplerConfig) *RuleSampler {
+	return NewRuleSampler(cfg.GetSampleRate(), cfg.Rules...)
+}
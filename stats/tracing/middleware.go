@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/webutil"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	// ErrHTTPServerError is the error class for spans of requests that finish with a 5xx status.
+	ErrHTTPServerError exception.Class = "http server error"
+)
+
+// Middleware returns a middleware that traces requests with a tracer.
+func Middleware(tracer opentracing.Tracer) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return Handler(tracer, handler)
+	}
+}
+
+// Handler wraps a handler with a span per request.
+// The parent span context is extracted from the request headers, the span is
+// added to the request context, and requests that finish with a 5xx status are marked as errors.
+func Handler(tracer opentracing.Tracer, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		startOptions := []opentracing.StartSpanOption{
+			opentracing.Tag{Key: TagKeyResourceName, Value: req.URL.Path},
+			opentracing.Tag{Key: TagKeySpanType, Value: SpanTypeWeb},
+			opentracing.Tag{Key: TagKeyHTTPMethod, Value: req.Method},
+			opentracing.Tag{Key: TagKeyHTTPURL, Value: req.URL.Path},
+			opentracing.Tag{Key: "http.remote_addr", Value: webutil.GetRemoteAddr(req)},
+			opentracing.Tag{Key: "http.host", Value: webutil.GetHost(req)},
+			opentracing.Tag{Key: "http.user_agent", Value: webutil.GetUserAgent(req)},
+			opentracing.StartTime(time.Now().UTC()),
+		}
+		if spanContext, _ := ExtractSpanContext(tracer, req.Header); spanContext != nil {
+			startOptions = append(startOptions, opentracing.ChildOf(spanContext))
+		}
+		span, spanCtx := StartSpanFromContext(req.Context(), tracer, OperationHTTPRequest, startOptions...)
+		defer span.Finish()
+
+		srw := &statusResponseWriter{ResponseWriter: rw, statusCode: http.StatusOK}
+		handler.ServeHTTP(srw, req.WithContext(spanCtx))
+
+		span.SetTag(TagKeyHTTPCode, strconv.Itoa(srw.statusCode))
+		if srw.statusCode >= http.StatusInternalServerError {
+			SpanError(span, exception.New(ErrHTTPServerError).WithMessage(http.StatusText(srw.statusCode)))
+		}
+	})
+}
+
+// statusResponseWriter records the status code written to a response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+// WriteHeader records and writes the status code.
+func (srw *statusResponseWriter) WriteHeader(statusCode int) {
+	if !srw.wroteHeader {
+		srw.statusCode = statusCode
+		srw.wroteHeader = true
+	}
+	srw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write writes to the response, implicitly with a 200 status if no status was written.
+func (srw *statusResponseWriter) Write(contents []byte) (int, error) {
+	srw.wroteHeader = true
+	return srw.ResponseWriter.Write(contents)
+}
+
+// Flush flushes the response if the underlying writer supports it.
+func (srw *statusResponseWriter) Flush() {
+	if typed, ok := srw.ResponseWriter.(http.Flusher); ok {
+		typed.Flush()
+	}
+}
+
+// Hijack hijacks the connection if the underlying writer supports it.
+func (srw *statusResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if typed, ok := srw.ResponseWriter.(http.Hijacker); ok {
+		return typed.Hijack()
+	}
+	return nil, nil, exception.New(http.ErrNotSupported)
+}
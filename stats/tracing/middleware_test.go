@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	var hasSpan bool
+	handler := Middleware(tracer)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		hasSpan = opentracing.SpanFromContext(req.Context()) != nil
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+
+	req := httptest.NewRequest("POST", "/foo/bar", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(hasSpan)
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(OperationHTTPRequest, spans[0].OperationName)
+	assert.Equal("POST", spans[0].Tag(TagKeyHTTPMethod))
+	assert.Equal("/foo/bar", spans[0].Tag(TagKeyHTTPURL))
+	assert.Equal("202", spans[0].Tag(TagKeyHTTPCode))
+	assert.Nil(spans[0].Tag(TagKeyError))
+}
+
+func TestHandlerParent(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	parent := tracer.StartSpan("parent")
+	req := httptest.NewRequest("GET", "/", nil)
+	assert.Nil(tracer.Inject(parent.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)))
+
+	Handler(tracer, http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(parent.Context().(mocktracer.MockSpanContext).SpanID, spans[0].ParentID)
+	assert.Equal("404", spans[0].Tag(TagKeyHTTPCode))
+}
+
+func TestHandlerServerError(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	Handler(tracer, http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "bad", http.StatusBadGateway)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal("502", spans[0].Tag(TagKeyHTTPCode))
+	assert.Equal(ErrHTTPServerError, spans[0].Tag(TagKeyError))
+	assert.Equal(http.StatusText(http.StatusBadGateway), spans[0].Tag(TagKeyErrorMessage))
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"runtime/pprof"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Profiler label keys.
+const (
+	// ProfilerLabelOperation is the pprof label for the span operation name.
+	ProfilerLabelOperation = "span.operation"
+	// ProfilerLabelResource is the pprof label for the span resource name.
+	ProfilerLabelResource = "span.resource"
+)
+
+// ProfilerLabels sets pprof labels for an operation and resource on the current goroutine,
+// so cpu profiles can be sliced by endpoint or job. It returns a context carrying the labels,
+// which `pprof.Do` uses for child goroutines, and a function that restores the previous labels.
+// The restore function must be called on the same goroutine.
+func ProfilerLabels(ctx context.Context, operationName, resource string) (context.Context, func()) {
+	labels := []string{ProfilerLabelOperation, operationName}
+	if len(resource) > 0 {
+		labels = append(labels, ProfilerLabelResource, resource)
+	}
+	labeledCtx := pprof.WithLabels(ctx, pprof.Labels(labels...))
+	pprof.SetGoroutineLabels(labeledCtx)
+	return labeledCtx, func() {
+		pprof.SetGoroutineLabels(ctx)
+	}
+}
+
+// StartSpanWithProfilerLabels starts a span like `StartSpanFromContext`, and sets pprof labels for the
+// operation and resource on the current goroutine until the span finishes; see `ProfilerLabels`.
+// The span must be finished on the goroutine that started it.
+func StartSpanWithProfilerLabels(ctx context.Context, tracer opentracing.Tracer, operationName string, opts ...opentracing.StartSpanOption) (opentracing.Span, context.Context) {
+	var options opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+	resource, _ := options.Tags[TagKeyResourceName].(string)
+
+	span, spanCtx := StartSpanFromContext(ctx, tracer, operationName, opts...)
+	labeledCtx, restore := ProfilerLabels(spanCtx, operationName, resource)
+	span = &profiledSpan{Span: span, restore: restore}
+	return span, opentracing.ContextWithSpan(labeledCtx, span)
+}
+
+// profiledSpan restores goroutine profiler labels when it finishes.
+type profiledSpan struct {
+	opentracing.Span
+	restore func()
+}
+
+// Finish implements opentracing.Span.
+func (ps *profiledSpan) Finish() {
+	ps.FinishWithOptions(opentracing.FinishOptions{})
+}
+
+// FinishWithOptions implements opentracing.Span.
+func (ps *profiledSpan) FinishWithOptions(opts opentracing.FinishOptions) {
+	ps.Span.FinishWithOptions(opts)
+	ps.restore()
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestStartSpanWithProfilerLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span, ctx := StartSpanWithProfilerLabels(context.Background(), tracer, OperationHTTPRequest, opentracing.Tag{Key: TagKeyResourceName, Value: "/foo"})
+	assert.Equal(span, opentracing.SpanFromContext(ctx))
+
+	operation, ok := pprof.Label(ctx, ProfilerLabelOperation)
+	assert.True(ok)
+	assert.Equal(OperationHTTPRequest, operation)
+	resource, ok := pprof.Label(ctx, ProfilerLabelResource)
+	assert.True(ok)
+	assert.Equal("/foo", resource)
+
+	span.Finish()
+	assert.Len(tracer.FinishedSpans(), 1)
+}
+
+func TestProfilerLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, restore := ProfilerLabels(context.Background(), OperationJob, "")
+	defer restore()
+	_, ok := pprof.Label(ctx, ProfilerLabelResource)
+	assert.False(ok)
+	operation, _ := pprof.Label(ctx, ProfilerLabelOperation)
+	assert.Equal(OperationJob, operation)
+}
@@ -0,0 +1,255 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Propagation headers.
+const (
+	// HeaderTraceParent is the w3c trace context parent header.
+	HeaderTraceParent = "traceparent"
+	// HeaderTraceState is the w3c trace context vendor state header.
+	HeaderTraceState = "tracestate"
+
+	// HeaderB3 is the b3 single header.
+	HeaderB3 = "b3"
+	// HeaderB3TraceID is the b3 multi header trace id.
+	HeaderB3TraceID = "X-B3-TraceId"
+	// HeaderB3SpanID is the b3 multi header span id.
+	HeaderB3SpanID = "X-B3-SpanId"
+	// HeaderB3ParentSpanID is the b3 multi header parent span id.
+	HeaderB3ParentSpanID = "X-B3-ParentSpanId"
+	// HeaderB3Sampled is the b3 multi header sampling decision.
+	HeaderB3Sampled = "X-B3-Sampled"
+	// HeaderB3Flags is the b3 multi header debug flag.
+	HeaderB3Flags = "X-B3-Flags"
+
+	// HeaderDatadogTraceID is the datadog trace id header.
+	HeaderDatadogTraceID = "X-Datadog-Trace-Id"
+	// HeaderDatadogParentID is the datadog parent span id header.
+	HeaderDatadogParentID = "X-Datadog-Parent-Id"
+	// HeaderDatadogSamplingPriority is the datadog sampling priority header.
+	HeaderDatadogSamplingPriority = "X-Datadog-Sampling-Priority"
+)
+
+// TraceContext is a tracer independent span context, as propagated by the
+// w3c `traceparent` and b3 headers.
+type TraceContext struct {
+	// TraceID is the 32 character lowercase hex trace id.
+	TraceID string
+	// SpanID is the 16 character lowercase hex span id.
+	SpanID string
+	// Sampled is the sampling decision.
+	Sampled bool
+	// TraceState is the w3c vendor state, if any.
+	TraceState string
+}
+
+// IsZero returns if the trace context is unset.
+func (tc TraceContext) IsZero() bool {
+	return len(tc.TraceID) == 0 || len(tc.SpanID) == 0
+}
+
+// TraceParent returns the w3c `traceparent` header value.
+func (tc TraceContext) TraceParent() string {
+	flags := "00"
+	if tc.Sampled {
+		flags = "01"
+	}
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + flags
+}
+
+// Inject sets the w3c and b3 headers for the trace context.
+func (tc TraceContext) Inject(header http.Header) {
+	if tc.IsZero() {
+		return
+	}
+	header.Set(HeaderTraceParent, tc.TraceParent())
+	if len(tc.TraceState) > 0 {
+		header.Set(HeaderTraceState, tc.TraceState)
+	}
+	header.Set(HeaderB3TraceID, tc.TraceID)
+	header.Set(HeaderB3SpanID, tc.SpanID)
+	if tc.Sampled {
+		header.Set(HeaderB3Sampled, "1")
+	} else {
+		header.Set(HeaderB3Sampled, "0")
+	}
+}
+
+// InjectHeaders injects the span in a context into outgoing request headers.
+// The span's tracer injects its native headers, and the w3c `traceparent` and b3
+// headers are added so peers using other tracers can continue the trace.
+func InjectHeaders(ctx context.Context, header http.Header) {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return
+	}
+	span.Tracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if traceContext, ok := TraceContextFromSpanContext(span.Context()); ok {
+		traceContext.Inject(header)
+	}
+}
+
+// ExtractHeaders returns the trace context from incoming request headers.
+// The w3c `traceparent` header is preferred, then the b3 single header, then the b3 multi headers.
+func ExtractHeaders(header http.Header) (TraceContext, bool) {
+	if traceParent := header.Get(HeaderTraceParent); len(traceParent) > 0 {
+		if traceContext, ok := ParseTraceParent(traceParent); ok {
+			traceContext.TraceState = header.Get(HeaderTraceState)
+			return traceContext, true
+		}
+	}
+	if b3 := header.Get(HeaderB3); len(b3) > 0 {
+		if traceContext, ok := ParseB3(b3); ok {
+			return traceContext, true
+		}
+	}
+	traceID, traceIDOK := normalizeID(header.Get(HeaderB3TraceID), 32)
+	spanID, spanIDOK := normalizeID(header.Get(HeaderB3SpanID), 16)
+	if !traceIDOK || !spanIDOK {
+		return TraceContext{}, false
+	}
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: header.Get(HeaderB3Sampled) != "0" || header.Get(HeaderB3Flags) == "1",
+	}, true
+}
+
+// ExtractSpanContext extracts a span context from incoming request headers for a tracer.
+// The tracer's native headers are tried first; if they are missing, w3c or b3 headers
+// are translated to datadog headers for the tracer to extract.
+func ExtractSpanContext(tracer opentracing.Tracer, header http.Header) (opentracing.SpanContext, error) {
+	spanContext, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(header))
+	if err == nil && spanContext != nil {
+		return spanContext, nil
+	}
+	traceContext, ok := ExtractHeaders(header)
+	if !ok {
+		return nil, err
+	}
+
+	traceID, _ := strconv.ParseUint(traceContext.TraceID[16:], 16, 64)
+	spanID, _ := strconv.ParseUint(traceContext.SpanID, 16, 64)
+	priority := PriorityAutoReject
+	if traceContext.Sampled {
+		priority = PriorityAutoKeep
+	}
+	datadogHeader := http.Header{}
+	datadogHeader.Set(HeaderDatadogTraceID, strconv.FormatUint(traceID, 10))
+	datadogHeader.Set(HeaderDatadogParentID, strconv.FormatUint(spanID, 10))
+	datadogHeader.Set(HeaderDatadogSamplingPriority, strconv.Itoa(priority))
+	return tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(datadogHeader))
+}
+
+// TraceContextFromSpanContext returns the trace context for a span context, if the ids are available.
+// Span contexts must implement `TraceID()` and `SpanID()` returning `uint64` (e.g. datadog) or hex strings;
+// if they implement `SamplingPriority() (int, bool)` it is used as the sampling decision.
+func TraceContextFromSpanContext(spanContext opentracing.SpanContext) (TraceContext, bool) {
+	var traceContext TraceContext
+	var ok bool
+	switch typed := spanContext.(type) {
+	case interface {
+		TraceID() uint64
+		SpanID() uint64
+	}:
+		traceContext.TraceID = formatID(typed.TraceID(), 32)
+		traceContext.SpanID = formatID(typed.SpanID(), 16)
+		ok = typed.TraceID() != 0 && typed.SpanID() != 0
+	case interface {
+		TraceID() string
+		SpanID() string
+	}:
+		var traceIDOK, spanIDOK bool
+		traceContext.TraceID, traceIDOK = normalizeID(typed.TraceID(), 32)
+		traceContext.SpanID, spanIDOK = normalizeID(typed.SpanID(), 16)
+		ok = traceIDOK && spanIDOK
+	}
+	if !ok {
+		return TraceContext{}, false
+	}
+
+	traceContext.Sampled = true
+	if typed, isTyped := spanContext.(interface {
+		SamplingPriority() (int, bool)
+	}); isTyped {
+		if priority, hasPriority := typed.SamplingPriority(); hasPriority {
+			traceContext.Sampled = priority > 0
+		}
+	}
+	return traceContext, true
+}
+
+// ParseTraceParent parses a w3c `traceparent` header value.
+func ParseTraceParent(value string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isHex(parts[0]) || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return TraceContext{}, false
+	}
+	traceID, traceIDOK := normalizeID(parts[1], 32)
+	spanID, spanIDOK := normalizeID(parts[2], 16)
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if !traceIDOK || !spanIDOK || err != nil {
+		return TraceContext{}, false
+	}
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flags&0x01 == 0x01,
+	}, true
+}
+
+// ParseB3 parses a b3 single header value, i.e. `{traceId}-{spanId}-{sampled}-{parentSpanId}`
+// where the sampling decision and parent span id are optional.
+func ParseB3(value string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 2 {
+		return TraceContext{}, false
+	}
+	traceID, traceIDOK := normalizeID(parts[0], 32)
+	spanID, spanIDOK := normalizeID(parts[1], 16)
+	if !traceIDOK || !spanIDOK {
+		return TraceContext{}, false
+	}
+	traceContext := TraceContext{TraceID: traceID, SpanID: spanID, Sampled: true}
+	if len(parts) > 2 {
+		traceContext.Sampled = parts[2] == "1" || parts[2] == "d"
+	}
+	return traceContext, true
+}
+
+// normalizeID validates a hex id and left pads it with zeros to a given length.
+// All zero ids are invalid.
+func normalizeID(value string, size int) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) == 0 || len(value) > size || !isHex(value) || strings.Trim(value, "0") == "" {
+		return "", false
+	}
+	return strings.Repeat("0", size-len(value)) + value, true
+}
+
+func formatID(value uint64, size int) string {
+	return strings.Repeat("0", size-16) + hex.EncodeToString([]byte{
+		byte(value >> 56), byte(value >> 48), byte(value >> 40), byte(value >> 32),
+		byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value),
+	})
+}
+
+func isHex(value string) bool {
+	for _, c := range value {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package tracing
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type mockSpanContext struct {
+	traceID, spanID uint64
+	priority        int
+}
+
+func (msc mockSpanContext) TraceID() uint64                           { return msc.traceID }
+func (msc mockSpanContext) SpanID() uint64                            { return msc.spanID }
+func (msc mockSpanContext) SamplingPriority() (int, bool)             { return msc.priority, true }
+func (msc mockSpanContext) ForeachBaggageItem(func(k, v string) bool) {}
+
+func TestParseTraceParent(t *testing.T) {
+	assert := assert.New(t)
+
+	tc, ok := ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.True(ok)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal("00f067aa0ba902b7", tc.SpanID)
+	assert.True(tc.Sampled)
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", tc.TraceParent())
+
+	tc, ok = ParseTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	assert.True(ok)
+	assert.False(tc.Sampled)
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	} {
+		_, ok = ParseTraceParent(invalid)
+		assert.False(ok, invalid)
+	}
+}
+
+func TestExtractHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	header := http.Header{}
+	header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	header.Set(HeaderTraceState, "dd=s:1")
+	header.Set(HeaderB3, "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1")
+	tc, ok := ExtractHeaders(header)
+	assert.True(ok)
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal("dd=s:1", tc.TraceState)
+
+	header = http.Header{}
+	header.Set(HeaderB3, "64fe8b2a57d3eff7-e457b5a2e4d86bd1-0-05e3ac9a4f6e3b90")
+	tc, ok = ExtractHeaders(header)
+	assert.True(ok)
+	assert.Equal("000000000000000064fe8b2a57d3eff7", tc.TraceID)
+	assert.Equal("e457b5a2e4d86bd1", tc.SpanID)
+	assert.False(tc.Sampled)
+
+	header = http.Header{}
+	header.Set(HeaderB3TraceID, "80f198ee56343ba864fe8b2a57d3eff7")
+	header.Set(HeaderB3SpanID, "e457b5a2e4d86bd1")
+	header.Set(HeaderB3Sampled, "1")
+	tc, ok = ExtractHeaders(header)
+	assert.True(ok)
+	assert.Equal("80f198ee56343ba864fe8b2a57d3eff7", tc.TraceID)
+	assert.Equal("e457b5a2e4d86bd1", tc.SpanID)
+	assert.True(tc.Sampled)
+
+	_, ok = ExtractHeaders(http.Header{})
+	assert.False(ok)
+}
+
+func TestTraceContextFromSpanContext(t *testing.T) {
+	assert := assert.New(t)
+
+	tc, ok := TraceContextFromSpanContext(mockSpanContext{traceID: 1234, spanID: 5678, priority: PriorityAutoReject})
+	assert.True(ok)
+	assert.Equal("000000000000000000000000000004d2", tc.TraceID)
+	assert.Equal("000000000000162e", tc.SpanID)
+	assert.False(tc.Sampled)
+
+	_, ok = TraceContextFromSpanContext(mockSpanContext{})
+	assert.False(ok)
+}
+
+func TestTraceContextInjectRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	tc := TraceContext{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7", Sampled: true}
+	header := http.Header{}
+	tc.Inject(header)
+	assert.Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", header.Get(HeaderTraceParent))
+	assert.Equal("1", header.Get(HeaderB3Sampled))
+
+	header.Del(HeaderTraceParent)
+	extracted, ok := ExtractHeaders(header)
+	assert.True(ok)
+	assert.Equal(tc, extracted)
+}
@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	// TagKeySamplingPriority is the sampling priority tag key; it is honored by the datadog tracer.
+	TagKeySamplingPriority = "sampling.priority"
+)
+
+// Sampler decides if a trace should be kept, given the operation name and resource of its root span.
+type Sampler interface {
+	Sample(operationName, resource string) bool
+}
+
+// SamplerFunc is a function that implements sampler.
+type SamplerFunc func(operationName, resource string) bool
+
+// Sample implements sampler.
+func (sf SamplerFunc) Sample(operationName, resource string) bool {
+	return sf(operationName, resource)
+}
+
+// NewRateSampler returns a sampler that keeps a given fraction of traces, from 0 to 1.
+func NewRateSampler(rate float64) *RateSampler {
+	return &RateSampler{
+		rate:   rate,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// RateSampler keeps a fixed fraction of traces.
+type RateSampler struct {
+	sync.Mutex
+	rate   float64
+	random *rand.Rand
+}
+
+// Rate returns the fraction of traces that are kept.
+func (rs *RateSampler) Rate() float64 {
+	return rs.rate
+}
+
+// Sample implements sampler.
+func (rs *RateSampler) Sample(operationName, resource string) bool {
+	if rs.rate >= 1 {
+		return true
+	}
+	if rs.rate <= 0 {
+		return false
+	}
+	rs.Lock()
+	defer rs.Unlock()
+	return rs.random.Float64() < rs.rate
+}
+
+// SamplingRule is a sampling rate for traces whose root span matches an operation name and resource.
+// An empty operation name or resource matches any value; a trailing `*` matches by prefix.
+type SamplingRule struct {
+	OperationName string  `json:"operationName,omitempty" yaml:"operationName,omitempty"`
+	Resource      string  `json:"resource,omitempty" yaml:"resource,omitempty"`
+	Rate          float64 `json:"rate" yaml:"rate"`
+}
+
+// Matches returns if the rule matches an operation name and resource.
+func (sr SamplingRule) Matches(operationName, resource string) bool {
+	return matchPattern(sr.OperationName, operationName) && matchPattern(sr.Resource, resource)
+}
+
+// NewRuleSampler returns a sampler that applies the rate of the first matching rule,
+// and a default rate if no rule matches.
+func NewRuleSampler(defaultRate float64, rules ...SamplingRule) *RuleSampler {
+	rs := &RuleSampler{
+		defaultSampler: NewRateSampler(defaultRate),
+	}
+	for _, rule := range rules {
+		rs.rules = append(rs.rules, rule)
+		rs.samplers = append(rs.samplers, NewRateSampler(rule.Rate))
+	}
+	return rs
+}
+
+// RuleSampler samples traces with per operation and resource rates.
+type RuleSampler struct {
+	rules          []SamplingRule
+	samplers       []*RateSampler
+	defaultSampler *RateSampler
+}
+
+// Rules returns the sampling rules.
+func (rs *RuleSampler) Rules() []SamplingRule {
+	return rs.rules
+}
+
+// DefaultRate returns the rate used if no rule matches.
+func (rs *RuleSampler) DefaultRate() float64 {
+	return rs.defaultSampler.Rate()
+}
+
+// Sample implements sampler.
+func (rs *RuleSampler) Sample(operationName, resource string) bool {
+	for index, rule := range rs.rules {
+		if rule.Matches(operationName, resource) {
+			return rs.samplers[index].Sample(operationName, resource)
+		}
+	}
+	return rs.defaultSampler.Sample(operationName, resource)
+}
+
+// Assert the sampling tracer is a tracer.
+var (
+	_ opentracing.Tracer = (*SamplingTracer)(nil)
+)
+
+// NewSamplingTracer returns a tracer that applies a sampler to root spans started by another tracer.
+func NewSamplingTracer(tracer opentracing.Tracer, sampler Sampler) *SamplingTracer {
+	return &SamplingTracer{
+		Tracer:  tracer,
+		sampler: sampler,
+	}
+}
+
+// SamplingTracer sets the sampling priority of root spans to `PriorityAutoKeep` or `PriorityAutoReject`
+// with a sampler, unless the priority is already set. Child spans inherit the decision of their trace.
+type SamplingTracer struct {
+	opentracing.Tracer
+	sampler Sampler
+}
+
+// Sampler returns the sampler.
+func (st *SamplingTracer) Sampler() Sampler {
+	return st.sampler
+}
+
+// StartSpan implements opentracing.Tracer.
+func (st *SamplingTracer) StartSpan(operationName string, opts ...opentracing.StartSpanOption) opentracing.Span {
+	var options opentracing.StartSpanOptions
+	for _, opt := range opts {
+		opt.Apply(&options)
+	}
+	if _, hasPriority := options.Tags[TagKeySamplingPriority]; len(options.References) == 0 && !hasPriority {
+		resource, _ := options.Tags[TagKeyResourceName].(string)
+		priority := PriorityAutoReject
+		if st.sampler.Sample(operationName, resource) {
+			priority = PriorityAutoKeep
+		}
+		opts = append(opts, opentracing.Tag{Key: TagKeySamplingPriority, Value: priority})
+	}
+	return st.Tracer.StartSpan(operationName, opts...)
+}
+
+func matchPattern(pattern, value string) bool {
+	if len(pattern) == 0 || pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(value, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == value
+}
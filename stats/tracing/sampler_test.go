@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestRateSampler(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(NewRateSampler(1).Sample("op", "resource"))
+	assert.False(NewRateSampler(0).Sample("op", "resource"))
+
+	sampler := NewRateSampler(0.5)
+	var kept int
+	for x := 0; x < 1000; x++ {
+		if sampler.Sample("op", "resource") {
+			kept++
+		}
+	}
+	assert.True(kept > 350 && kept < 650, kept)
+}
+
+func TestRuleSampler(t *testing.T) {
+	assert := assert.New(t)
+
+	sampler := NewRuleSampler(1,
+		SamplingRule{OperationName: OperationHTTPRequest, Resource: "/healthz", Rate: 0},
+		SamplingRule{OperationName: "sql.*", Rate: 0},
+	)
+	assert.Len(sampler.Rules(), 2)
+	assert.Equal(1, sampler.DefaultRate())
+	assert.False(sampler.Sample(OperationHTTPRequest, "/healthz"))
+	assert.True(sampler.Sample(OperationHTTPRequest, "/foo"))
+	assert.False(sampler.Sample(OperationSQLQuery, "select 1"))
+	assert.True(sampler.Sample(OperationJob, ""))
+}
+
+func TestSamplingTracer(t *testing.T) {
+	assert := assert.New(t)
+
+	mock := mocktracer.New()
+	tracer := NewSamplingTracer(mock, NewRuleSampler(1, SamplingRule{Resource: "/healthz", Rate: 0}))
+
+	root := tracer.StartSpan(OperationHTTPRequest, opentracing.Tag{Key: TagKeyResourceName, Value: "/healthz"})
+	child := tracer.StartSpan(OperationSQLQuery, opentracing.ChildOf(root.Context()))
+	kept := tracer.StartSpan(OperationHTTPRequest, opentracing.Tag{Key: TagKeyResourceName, Value: "/foo"})
+	explicit := tracer.StartSpan(OperationHTTPRequest,
+		opentracing.Tag{Key: TagKeyResourceName, Value: "/healthz"},
+		opentracing.Tag{Key: TagKeySamplingPriority, Value: PriorityUserKeep},
+	)
+
+	assert.Equal(PriorityAutoReject, root.(*mocktracer.MockSpan).Tag(TagKeySamplingPriority))
+	assert.Nil(child.(*mocktracer.MockSpan).Tag(TagKeySamplingPriority))
+	assert.Equal(PriorityAutoKeep, kept.(*mocktracer.MockSpan).Tag(TagKeySamplingPriority))
+	assert.Equal(PriorityUserKeep, explicit.(*mocktracer.MockSpan).Tag(TagKeySamplingPriority))
+}
+
+func TestNewSamplerFromConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultSampleRate, NewSamplerFromConfig(&SamplerConfig{}).DefaultRate())
+
+	rate := 0.0
+	sampler := NewSamplerFromConfig(&SamplerConfig{
+		SampleRate: &rate,
+		Rules:      []SamplingRule{{OperationName: OperationJob, Rate: 1}},
+	})
+	assert.Zero(sampler.DefaultRate())
+	assert.True(sampler.Sample(OperationJob, "reports"))
+	assert.False(sampler.Sample(OperationHTTPRequest, "/"))
+}
@@ -3,6 +3,7 @@ package tracing
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/blend/go-sdk/exception"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -33,6 +34,8 @@ const (
 	TagKeyHTTPCode = "http.status_code"
 	// TagKeyHTTPURL is the url of the request (typically the raw path).
 	TagKeyHTTPURL = "http.url"
+	// TagKeyHTTPRequestID is the request id, e.g. from the `X-Request-ID` header.
+	TagKeyHTTPRequestID = "http.request_id"
 
 	// TagKeyDBApplication is the application that uses a database.
 	TagKeyDBApplication = "db.application"
@@ -40,9 +43,13 @@ const (
 	TagKeyDBName = "db.name"
 	// TagKeyDBUser is the user on the database connection.
 	TagKeyDBUser = "db.user"
+	// TagKeyDBQuery is the normalized statement.
+	TagKeyDBQuery = "db.query"
 
 	// TagKeyJobName is the job name.
 	TagKeyJobName = "job.name"
+	// TagKeyJobElapsed is the job elapsed time in milliseconds.
+	TagKeyJobElapsed = "job.elapsed"
 )
 
 // Operations are actions represented by spans.
@@ -52,6 +59,8 @@ const (
 	OperationHTTPRequest = "http.request"
 	// OperationHTTPRender is the operation name for rendering a server side view.
 	OperationHTTPRender = "http.render"
+	// OperationHTTPWebSocket is the operation name for an upgraded websocket connection.
+	OperationHTTPWebSocket = "http.websocket"
 	// OperationDBPing is the db ping tracing operation.
 	OperationSQLPing = "sql.ping"
 	// OperationDBPrepare is the db prepare tracing operation.
@@ -141,3 +150,37 @@ func SpanError(span opentracing.Span, err error) {
 		}
 	}
 }
+
+// MarkSpanError injects error metadata into a span like `SpanError`, and sets the
+// sampling priority to `PriorityUserKeep` so traces with errors are never dropped by samplers.
+func MarkSpanError(span opentracing.Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	SpanError(span, err)
+	span.SetTag(TagKeySamplingPriority, PriorityUserKeep)
+}
+
+// MarkSpanErrorFromContext marks the active span in a context with an error; see `MarkSpanError`.
+func MarkSpanErrorFromContext(ctx context.Context, err error) {
+	MarkSpanError(opentracing.SpanFromContext(ctx), err)
+}
+
+// GetTraceIDs returns the trace and span ids for a span context as strings.
+// The opentracing api does not expose ids, so this supports tracers whose span contexts
+// implement `TraceID()` and `SpanID()` returning either `uint64` (e.g. datadog) or `string`.
+func GetTraceIDs(spanContext opentracing.SpanContext) (traceID, spanID string) {
+	switch typed := spanContext.(type) {
+	case interface {
+		TraceID() uint64
+		SpanID() uint64
+	}:
+		return strconv.FormatUint(typed.TraceID(), 10), strconv.FormatUint(typed.SpanID(), 10)
+	case interface {
+		TraceID() string
+		SpanID() string
+	}:
+		return typed.TraceID(), typed.SpanID()
+	}
+	return "", ""
+}
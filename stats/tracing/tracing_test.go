@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestMarkSpanError(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("test").(*mocktracer.MockSpan)
+	// the mock tracer applies the sampling priority tag to the span context.
+	span.SetTag(TagKeySamplingPriority, PriorityAutoReject)
+	MarkSpanError(span, nil)
+	assert.False(span.Context().(mocktracer.MockSpanContext).Sampled)
+
+	MarkSpanError(span, fmt.Errorf("only a test"))
+	assert.Equal("only a test", span.Tag(TagKeyError))
+	assert.True(span.Context().(mocktracer.MockSpanContext).Sampled)
+
+	MarkSpanError(nil, fmt.Errorf("only a test"))
+}
+
+func TestMarkSpanErrorFromContext(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	span, ctx := StartSpanFromContext(context.Background(), tracer, "test")
+	span.SetTag(TagKeySamplingPriority, PriorityAutoReject)
+	MarkSpanErrorFromContext(ctx, fmt.Errorf("only a test"))
+	assert.Equal("only a test", span.(*mocktracer.MockSpan).Tag(TagKeyError))
+	assert.True(span.Context().(mocktracer.MockSpanContext).Sampled)
+
+	MarkSpanErrorFromContext(context.Background(), fmt.Errorf("only a test"))
+	assert.Nil(opentracing.SpanFromContext(context.Background()))
+}
@@ -0,0 +1,77 @@
+package tracing
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+// Assert the transport is a round tripper.
+var (
+	_ http.RoundTripper = (*Transport)(nil)
+)
+
+// NewTransport returns a new transport that traces requests sent by a base round tripper.
+// If the base round tripper is nil, `http.DefaultTransport` is used.
+func NewTransport(tracer opentracing.Tracer, base http.RoundTripper) *Transport {
+	return &Transport{
+		tracer: tracer,
+		base:   base,
+	}
+}
+
+// Transport is a round tripper that starts a client span per request.
+// The span is a child of any span in the request context, and its context is
+// injected into the outgoing request headers.
+type Transport struct {
+	tracer opentracing.Tracer
+	base   http.RoundTripper
+}
+
+// Tracer returns the tracer.
+func (t *Transport) Tracer() opentracing.Tracer {
+	return t.tracer
+}
+
+// Base returns the underlying round tripper.
+func (t *Transport) Base() http.RoundTripper {
+	if t.base != nil {
+		return t.base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	startOptions := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: TagKeyResourceName, Value: req.URL.Host},
+		opentracing.Tag{Key: TagKeySpanType, Value: SpanTypeHTTP},
+		opentracing.Tag{Key: TagKeyHTTPMethod, Value: req.Method},
+		opentracing.Tag{Key: TagKeyHTTPURL, Value: req.URL.Path},
+		opentracing.StartTime(time.Now().UTC()),
+	}
+	span, spanCtx := StartSpanFromContext(req.Context(), t.tracer, OperationHTTPRequest, startOptions...)
+	defer span.Finish()
+
+	// round trippers must not modify the request, so inject into a copy of the headers.
+	outgoing := req.WithContext(spanCtx)
+	outgoing.Header = make(http.Header, len(req.Header))
+	for key, values := range req.Header {
+		outgoing.Header[key] = append([]string(nil), values...)
+	}
+	InjectHeaders(spanCtx, outgoing.Header)
+
+	res, err := t.Base().RoundTrip(outgoing)
+	if err != nil {
+		SpanError(span, exception.New(err))
+		return res, err
+	}
+	span.SetTag(TagKeyHTTPCode, strconv.Itoa(res.StatusCode))
+	if res.StatusCode >= http.StatusInternalServerError {
+		SpanError(span, exception.New(ErrHTTPServerError).WithMessage(http.StatusText(res.StatusCode)))
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+)
+
+func TestTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		headers = req.Header
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	parent := tracer.StartSpan("parent")
+	req, err := http.NewRequest("GET", server.URL+"/foo", nil)
+	assert.Nil(err)
+	req = req.WithContext(opentracing.ContextWithSpan(req.Context(), parent))
+
+	client := &http.Client{Transport: NewTransport(tracer, nil)}
+	res, err := client.Do(req)
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Empty(req.Header, "the original request should not be modified")
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.Equal(SpanTypeHTTP, spans[0].Tag(TagKeySpanType))
+	assert.Equal("/foo", spans[0].Tag(TagKeyHTTPURL))
+	assert.Equal("503", spans[0].Tag(TagKeyHTTPCode))
+	assert.Equal(ErrHTTPServerError, spans[0].Tag(TagKeyError))
+	assert.Equal(parent.Context().(mocktracer.MockSpanContext).SpanID, spans[0].ParentID)
+
+	spanContext, err := tracer.Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(headers))
+	assert.Nil(err)
+	assert.Equal(spans[0].SpanContext.SpanID, spanContext.(mocktracer.MockSpanContext).SpanID)
+}
+
+func TestTransportError(t *testing.T) {
+	assert := assert.New(t)
+
+	tracer := mocktracer.New()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := &http.Client{Transport: NewTransport(tracer, nil)}
+	_, err := client.Get(server.URL)
+	assert.NotNil(err)
+
+	spans := tracer.FinishedSpans()
+	assert.Len(spans, 1)
+	assert.NotNil(spans[0].Tag(TagKeyError))
+	assert.Nil(spans[0].Tag(TagKeyHTTPCode))
+}
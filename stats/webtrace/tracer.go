@@ -16,7 +16,9 @@ const (
 )
 
 var (
-	_ web.Tracer = (*webTracer)(nil)
+	_ web.Tracer          = (*webTracer)(nil)
+	_ web.ViewTracer      = (*webTracer)(nil)
+	_ web.WebSocketTracer = (*webTracer)(nil)
 )
 
 // Tracer returns a web tracer.
@@ -53,8 +55,8 @@ func (wt webTracer) Start(ctx *web.Ctx) web.TraceFinisher {
 
 	// try to extract an incoming span context
 	// this is typically done if we're a service being called in a chain from another (more ancestral)
-	// span context.
-	spanContext, _ := wt.tracer.Extract(opentracing.TextMap, opentracing.HTTPHeadersCarrier(ctx.Request().Header))
+	// span context; w3c `traceparent` and b3 headers are used if the tracer's own headers are missing.
+	spanContext, _ := tracing.ExtractSpanContext(wt.tracer, ctx.Request().Header)
 	if spanContext != nil {
 		startOptions = append(startOptions, opentracing.ChildOf(spanContext))
 	}
@@ -80,6 +82,9 @@ func (wtf webTraceFinisher) Finish(ctx *web.Ctx, err error) {
 	}
 	tracing.SpanError(wtf.span, err)
 	wtf.span.SetTag(tracing.TagKeyHTTPCode, strconv.Itoa(ctx.Response().StatusCode()))
+	if requestID := webutil.GetRequestID(ctx.Context()); len(requestID) > 0 {
+		wtf.span.SetTag(tracing.TagKeyHTTPRequestID, requestID)
+	}
 	wtf.span.Finish()
 }
 
@@ -106,3 +111,31 @@ func (wvtf webViewTraceFinisher) Finish(ctx *web.Ctx, vr *web.ViewResult, err er
 	tracing.SpanError(wvtf.span, err)
 	wvtf.span.Finish()
 }
+
+func (wt webTracer) StartWebSocket(ctx *web.Ctx, wsr *web.WebSocketResult) web.WebSocketTraceFinisher {
+	var resource string
+	if ctx.Route() != nil {
+		resource = ctx.Route().String()
+	} else {
+		resource = ctx.Request().URL.Path
+	}
+	startOptions := []opentracing.StartSpanOption{
+		opentracing.Tag{Key: tracing.TagKeyResourceName, Value: resource},
+		opentracing.Tag{Key: tracing.TagKeySpanType, Value: tracing.SpanTypeWeb},
+		opentracing.StartTime(time.Now().UTC()),
+	}
+	span, _ := tracing.StartSpanFromContext(ctx.Context(), wt.tracer, tracing.OperationHTTPWebSocket, startOptions...)
+	return &webWebSocketTraceFinisher{span: span}
+}
+
+type webWebSocketTraceFinisher struct {
+	span opentracing.Span
+}
+
+func (wwstf webWebSocketTraceFinisher) Finish(ctx *web.Ctx, wsr *web.WebSocketResult, err error) {
+	if wwstf.span == nil {
+		return
+	}
+	tracing.SpanError(wwstf.span, err)
+	wwstf.span.Finish()
+}
@@ -0,0 +1,183 @@
+package web
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	// LetsEncryptDirectoryURL is the directory url of the Let's Encrypt production acme server.
+	LetsEncryptDirectoryURL = autocert.DefaultACMEDirectory
+	// LetsEncryptStagingDirectoryURL is the directory url of the Let's Encrypt staging acme server,
+	// which has higher rate limits but issues untrusted certificates.
+	LetsEncryptStagingDirectoryURL = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+	// ACMEChallengePathPrefix is the path prefix http-01 challenges are served under.
+	ACMEChallengePathPrefix = "/.well-known/acme-challenge/"
+
+	// DefaultACMERenewBefore is the default time before a certificate expires that it is renewed.
+	DefaultACMERenewBefore = 30 * 24 * time.Hour
+)
+
+// NewACMEManager returns a new acme manager that obtains certificates for a given set of hosts
+// from Let's Encrypt.
+func NewACMEManager(hosts ...string) *ACMEManager {
+	manager := &ACMEManager{
+		hosts: map[string]bool{},
+	}
+	for _, host := range hosts {
+		manager.hosts[strings.ToLower(host)] = true
+	}
+	manager.autocert = &autocert.Manager{
+		Prompt:      func(string) bool { return manager.termsAgreed },
+		HostPolicy:  autocert.HostWhitelist(manager.Hosts()...),
+		RenewBefore: DefaultACMERenewBefore,
+		Client:      &acme.Client{DirectoryURL: LetsEncryptDirectoryURL},
+	}
+	return manager
+}
+
+// ACMEManager obtains and renews certificates from an acme server (e.g. Let's Encrypt) on demand
+// with `golang.org/x/crypto/acme/autocert`.
+/*
+Serve the app with the manager's tls config, and the manager's http handler on port 80:
+
+	certs := web.NewACMEManager("example.com", "www.example.com").
+		WithEmail("ops@example.com").
+		WithTermsOfServiceAgreed(true).
+		WithCacheDir("/var/lib/acme")
+	app.WithBindAddr(":443").WithTLSConfig(certs.TLSConfig())
+
+	upgrader := web.NewHTTPSUpgrader().WithBindAddr(":80")
+	challenges := upgrader.Server()
+	challenges.Handler = certs.HTTPHandler(upgrader)
+	go web.New().WithBindAddr(upgrader.BindAddr()).WithServer(challenges).Start()
+
+A certificate is obtained on the first handshake for a host, and renewed in the background `RenewBefore`
+it expires; failed renewals are retried with a backoff while the current certificate is served, and handshakes
+shortly after a failed order fail fast rather than placing another one. Set a cache dir so certificates survive
+restarts; otherwise each restart obtains new certificates, which quickly runs into Let's Encrypt's rate limits.
+*/
+type ACMEManager struct {
+	hosts       map[string]bool
+	termsAgreed bool
+	cacheDir    string
+	autocert    *autocert.Manager
+}
+
+// Hosts returns the hosts certificates are obtained for.
+func (am *ACMEManager) Hosts() []string {
+	var hosts []string
+	for host := range am.hosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// WithDirectoryURL sets the directory url of the acme server, e.g. `LetsEncryptStagingDirectoryURL`.
+func (am *ACMEManager) WithDirectoryURL(directoryURL string) *ACMEManager {
+	am.autocert.Client.DirectoryURL = directoryURL
+	return am
+}
+
+// DirectoryURL returns the directory url of the acme server.
+func (am *ACMEManager) DirectoryURL() string {
+	return am.autocert.Client.DirectoryURL
+}
+
+// WithEmail sets the contact email for the acme account, which is sent expiry notices.
+func (am *ACMEManager) WithEmail(email string) *ACMEManager {
+	am.autocert.Email = email
+	return am
+}
+
+// Email returns the contact email for the acme account.
+func (am *ACMEManager) Email() string {
+	return am.autocert.Email
+}
+
+// WithTermsOfServiceAgreed sets if you agree to the acme server's terms of service; certificates
+// are not obtained without agreeing to them.
+func (am *ACMEManager) WithTermsOfServiceAgreed(agreed bool) *ACMEManager {
+	am.termsAgreed = agreed
+	return am
+}
+
+// TermsOfServiceAgreed returns if you agree to the acme server's terms of service.
+func (am *ACMEManager) TermsOfServiceAgreed() bool {
+	return am.termsAgreed
+}
+
+// WithCacheDir sets the directory the account key and certificates are stored in.
+func (am *ACMEManager) WithCacheDir(cacheDir string) *ACMEManager {
+	am.cacheDir = cacheDir
+	if len(cacheDir) > 0 {
+		am.autocert.Cache = autocert.DirCache(cacheDir)
+	} else {
+		am.autocert.Cache = nil
+	}
+	return am
+}
+
+// CacheDir returns the directory the account key and certificates are stored in.
+func (am *ACMEManager) CacheDir() string {
+	return am.cacheDir
+}
+
+// WithRenewBefore sets the time before a certificate expires that it is renewed;
+// an hour or less uses the default.
+func (am *ACMEManager) WithRenewBefore(renewBefore time.Duration) *ACMEManager {
+	am.autocert.RenewBefore = renewBefore
+	return am
+}
+
+// RenewBefore returns the time before a certificate expires that it is renewed.
+func (am *ACMEManager) RenewBefore() time.Duration {
+	return am.autocert.RenewBefore
+}
+
+// WithClient sets the http client used to make requests to the acme server.
+func (am *ACMEManager) WithClient(client *http.Client) *ACMEManager {
+	am.autocert.Client.HTTPClient = client
+	return am
+}
+
+// Client returns the http client used to make requests to the acme server.
+func (am *ACMEManager) Client() *http.Client {
+	return am.autocert.Client.HTTPClient
+}
+
+// TLSConfig returns a tls config that serves certificates from the manager.
+func (am *ACMEManager) TLSConfig() *tls.Config {
+	config := am.autocert.TLSConfig()
+	config.GetCertificate = am.GetCertificate
+	config.MinVersion = tls.VersionTLS12
+	return config
+}
+
+// GetCertificate returns the certificate for a handshake's server name, obtaining it if required;
+// it can be used as `tls.Config.GetCertificate`.
+func (am *ACMEManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := am.autocert.GetCertificate(hello)
+	if err != nil {
+		return nil, exception.New(ErrACME).WithMessagef("server name: %s", hello.ServerName).WithInner(err)
+	}
+	return cert, nil
+}
+
+// HTTPHandler returns a handler that serves http-01 challenges, passing other requests to a fallback;
+// if the fallback is nil, other requests are redirected to https.
+func (am *ACMEManager) HTTPHandler(fallback http.Handler) http.Handler {
+	if fallback == nil {
+		fallback = NewHTTPSUpgrader()
+	}
+	return am.autocert.HTTPHandler(fallback)
+}
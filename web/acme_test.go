@@ -0,0 +1,481 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/jwt"
+)
+
+// acmeTestHello returns a client hello for a server name from a client that supports ecdsa certificates.
+func acmeTestHello(serverName string) *tls.ClientHelloInfo {
+	return &tls.ClientHelloInfo{
+		ServerName:   serverName,
+		CipherSuites: []uint16{tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256},
+	}
+}
+
+func TestACMEManagerGetCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	cacheDir, err := ioutil.TempDir("", "acme")
+	assert.Nil(err)
+	defer os.RemoveAll(cacheDir)
+
+	acme := newFakeACMEServer(t, 90*24*time.Hour)
+	defer acme.Close()
+	manager := acme.Manager("Example.com").WithCacheDir(cacheDir)
+	assert.Equal([]string{"example.com"}, manager.Hosts())
+
+	// concurrent handshakes share one order.
+	var wg sync.WaitGroup
+	certs := make([]*tls.Certificate, 4)
+	errs := make([]error, 4)
+	for index := range certs {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			certs[index], errs[index] = manager.GetCertificate(acmeTestHello("EXAMPLE.com"))
+		}(index)
+	}
+	wg.Wait()
+	for index := range certs {
+		assert.Nil(errs[index])
+		assert.Equal(certs[0].Certificate, certs[index].Certificate)
+	}
+	assert.Equal([]string{"example.com"}, certs[0].Leaf.DNSNames)
+	assert.Len(certs[0].Certificate, 2)
+	assert.Equal(1, acme.Orders())
+	assert.Equal(1, acme.Issued())
+
+	_, err = certs[0].Leaf.Verify(x509.VerifyOptions{DNSName: "example.com", Roots: acme.Roots()})
+	assert.Nil(err)
+
+	// a new manager loads the cached certificate.
+	cached, err := acme.Manager("example.com").WithCacheDir(cacheDir).GetCertificate(acmeTestHello("example.com"))
+	assert.Nil(err)
+	assert.Equal(certs[0].Certificate, cached.Certificate)
+	assert.Equal(1, acme.Orders())
+}
+
+func TestACMEManagerRenew(t *testing.T) {
+	assert := assert.New(t)
+
+	acme := newFakeACMEServer(t, time.Hour)
+	defer acme.Close()
+	manager := acme.Manager("example.com").WithRenewBefore(2 * time.Hour)
+
+	hello := acmeTestHello("example.com")
+	cert, err := manager.GetCertificate(hello)
+	assert.Nil(err)
+
+	// the certificate is within the renew window, so it is renewed in the background.
+	assert.Eventually(func() bool {
+		current, err := manager.GetCertificate(hello)
+		return err == nil && current.Leaf.SerialNumber.Cmp(cert.Leaf.SerialNumber) != 0
+	}, 5*time.Second, 10*time.Millisecond, "the certificate should be renewed in the background")
+	assert.True(acme.Issued() >= 2)
+}
+
+func TestACMEManagerErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	acme := newFakeACMEServer(t, 90*24*time.Hour)
+	defer acme.Close()
+
+	_, err := acme.Manager("example.com").GetCertificate(acmeTestHello(""))
+	assert.True(exception.Is(err, ErrACME))
+	_, err = acme.Manager("example.com").GetCertificate(acmeTestHello("other.com"))
+	assert.True(exception.Is(err, ErrACME))
+	_, err = acme.Manager("example.com").WithTermsOfServiceAgreed(false).GetCertificate(acmeTestHello("example.com"))
+	assert.True(exception.Is(err, ErrACME))
+	assert.Zero(acme.Orders())
+
+	acme.Lock()
+	acme.rejectChallenges = true
+	acme.Unlock()
+	manager := acme.Manager("example.com")
+	_, err = manager.GetCertificate(acmeTestHello("example.com"))
+	assert.True(exception.Is(err, ErrACME))
+	assert.Zero(acme.Issued())
+
+	// handshakes right after a failed order fail fast rather than placing another one.
+	orders := acme.Orders()
+	assert.NotZero(orders)
+	_, err = manager.GetCertificate(acmeTestHello("example.com"))
+	assert.True(exception.Is(err, ErrACME))
+	assert.Equal(orders, acme.Orders())
+}
+
+func TestACMEManagerHTTPHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := NewACMEManager("example.com").HTTPHandler(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", "http://example.com"+ACMEChallengePathPrefix+"token", nil))
+	assert.Equal(http.StatusNotFound, res.Code)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest("GET", "http://example.com/foo", nil))
+	assert.Equal(http.StatusTeapot, res.Code)
+
+	// other requests are upgraded to https by default.
+	res = httptest.NewRecorder()
+	NewACMEManager("example.com").HTTPHandler(nil).ServeHTTP(res, httptest.NewRequest("GET", "http://example.com/foo", nil))
+	assert.Equal(http.StatusMovedPermanently, res.Code)
+	assert.Equal("https://example.com/foo", res.Header().Get("Location"))
+}
+
+// newFakeACMEServer returns an acme server that issues certificates valid for a given duration,
+// validating http-01 challenges against the handler of the manager that requested them.
+func newFakeACMEServer(t *testing.T, validity time.Duration) *fakeACMEServer {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake acme ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	fake := &fakeACMEServer{
+		t:           t,
+		validity:    validity,
+		caKey:       caKey,
+		caCert:      caCert,
+		nonces:      map[string]bool{},
+		accounts:    map[string]fakeACMEAccount{},
+		orders:      map[string]*fakeACMEOrder{},
+		challengers: map[string]http.Handler{},
+	}
+	fake.Server = httptest.NewServer(http.HandlerFunc(fake.serveHTTP))
+	return fake
+}
+
+type fakeACMEServer struct {
+	*httptest.Server
+	t        *testing.T
+	validity time.Duration
+	caKey    *ecdsa.PrivateKey
+	caCert   *x509.Certificate
+
+	sync.Mutex
+	rejectChallenges bool
+	serial           int
+	nonces           map[string]bool
+	accounts         map[string]fakeACMEAccount
+	orders           map[string]*fakeACMEOrder
+	challengers      map[string]http.Handler
+}
+
+// fakeACMEAccount is the json web key of an account.
+type fakeACMEAccount struct {
+	X, Y string
+}
+
+type fakeACMEOrder struct {
+	host       string
+	account    string
+	authorized bool
+	invalid    bool
+	cert       []byte
+}
+
+// fakeACMEProblem is an acme error response.
+type fakeACMEProblem struct {
+	Type   string `json:"type"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type fakeACMEIdentifier struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type fakeACMEChallenge struct {
+	Type   string           `json:"type"`
+	URL    string           `json:"url"`
+	Token  string           `json:"token"`
+	Status string           `json:"status"`
+	Error  *fakeACMEProblem `json:"error,omitempty"`
+}
+
+// Manager returns a manager for a given host that obtains certificates from the server.
+func (f *fakeACMEServer) Manager(host string) *ACMEManager {
+	manager := NewACMEManager(host).
+		WithDirectoryURL(f.URL + "/directory").
+		WithTermsOfServiceAgreed(true).
+		WithEmail("ops@example.com").
+		WithClient(f.Client())
+	f.Lock()
+	f.challengers[strings.ToLower(host)] = manager.HTTPHandler(nil)
+	f.Unlock()
+	return manager
+}
+
+// Orders returns the number of orders placed.
+func (f *fakeACMEServer) Orders() int {
+	f.Lock()
+	defer f.Unlock()
+	return len(f.orders)
+}
+
+// Issued returns the number of certificates issued.
+func (f *fakeACMEServer) Issued() (issued int) {
+	f.Lock()
+	defer f.Unlock()
+	for _, order := range f.orders {
+		if order.cert != nil {
+			issued++
+		}
+	}
+	return
+}
+
+// Roots returns a pool with the server's ca certificate.
+func (f *fakeACMEServer) Roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(f.caCert)
+	return pool
+}
+
+func (f *fakeACMEServer) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	rw.Header().Set("Replay-Nonce", f.newNonce())
+	if req.URL.Path == "/directory" {
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"newNonce":   f.URL + "/nonce",
+			"newAccount": f.URL + "/account",
+			"newOrder":   f.URL + "/order",
+			"meta":       map[string]string{"termsOfService": f.URL + "/terms"},
+		})
+		return
+	}
+	if req.URL.Path == "/nonce" {
+		return
+	}
+
+	account, payload, problem := f.verify(req)
+	if problem != "" {
+		f.problem(rw, http.StatusBadRequest, problem)
+		return
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var order *fakeACMEOrder
+	if len(parts) == 2 && parts[0] != "account" {
+		if order = f.orders[parts[1]]; order == nil || order.account != account {
+			http.NotFound(rw, req)
+			return
+		}
+	}
+
+	switch parts[0] {
+	case "account":
+		var request struct {
+			TermsOfServiceAgreed bool `json:"termsOfServiceAgreed"`
+		}
+		json.Unmarshal(payload, &request)
+		if !request.TermsOfServiceAgreed {
+			f.problem(rw, http.StatusForbidden, "urn:ietf:params:acme:error:userActionRequired")
+			return
+		}
+		rw.Header().Set("Location", f.URL+"/account/"+account)
+		rw.WriteHeader(http.StatusCreated)
+		json.NewEncoder(rw).Encode(map[string]string{"status": "valid"})
+	case "order":
+		if order == nil {
+			var request struct {
+				Identifiers []fakeACMEIdentifier `json:"identifiers"`
+			}
+			json.Unmarshal(payload, &request)
+			f.serial++
+			id := fmt.Sprint(f.serial)
+			order = &fakeACMEOrder{host: request.Identifiers[0].Value, account: account}
+			f.orders[id] = order
+			rw.Header().Set("Location", f.URL+"/order/"+id)
+			rw.WriteHeader(http.StatusCreated)
+			json.NewEncoder(rw).Encode(f.order(id, order))
+			return
+		}
+		json.NewEncoder(rw).Encode(f.order(parts[1], order))
+	case "authz":
+		status := "pending"
+		challenge := fakeACMEChallenge{Type: "http-01", URL: f.URL + "/challenge/" + parts[1], Token: "token-" + parts[1], Status: "pending"}
+		if order.authorized {
+			status, challenge.Status = "valid", "valid"
+		} else if order.invalid {
+			status, challenge.Status = "invalid", "invalid"
+			challenge.Error = &fakeACMEProblem{Type: "urn:ietf:params:acme:error:unauthorized"}
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"status":     status,
+			"identifier": fakeACMEIdentifier{Type: "dns", Value: order.host},
+			"challenges": []fakeACMEChallenge{
+				{Type: "dns-01", URL: f.URL + "/dns/" + parts[1], Token: "dns-" + parts[1], Status: "pending"},
+				challenge,
+			},
+		})
+	case "challenge":
+		keyAuth := httptest.NewRecorder()
+		f.challengers[order.host].ServeHTTP(keyAuth, httptest.NewRequest("GET", "http://"+order.host+ACMEChallengePathPrefix+"token-"+parts[1], nil))
+		jwk := f.accounts[account]
+		hash := sha256.Sum256([]byte(fmt.Sprintf(`{"crv":"P-256","kty":"EC","x":"%s","y":"%s"}`, jwk.X, jwk.Y)))
+		order.authorized = !f.rejectChallenges && keyAuth.Body.String() == "token-"+parts[1]+"."+jwt.EncodeSegment(hash[:])
+		order.invalid = !order.authorized
+		json.NewEncoder(rw).Encode(fakeACMEChallenge{Type: "http-01", URL: f.URL + req.URL.Path, Token: "token-" + parts[1], Status: "processing"})
+	case "finalize":
+		var request struct {
+			CSR string `json:"csr"`
+		}
+		json.Unmarshal(payload, &request)
+		der, _ := jwt.DecodeSegment(request.CSR)
+		csr, err := x509.ParseCertificateRequest(der)
+		if err != nil || csr.CheckSignature() != nil || !order.authorized || len(csr.DNSNames) != 1 || csr.DNSNames[0] != order.host {
+			f.problem(rw, http.StatusForbidden, "urn:ietf:params:acme:error:badCSR")
+			return
+		}
+		f.serial++
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(f.serial)),
+			Subject:      pkix.Name{CommonName: order.host},
+			DNSNames:     csr.DNSNames,
+			NotBefore:    time.Now().Add(-time.Minute),
+			NotAfter:     time.Now().Add(f.validity),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+		if order.cert, err = x509.CreateCertificate(rand.Reader, template, f.caCert, csr.PublicKey, f.caKey); err != nil {
+			f.problem(rw, http.StatusInternalServerError, "urn:ietf:params:acme:error:serverInternal")
+			return
+		}
+		json.NewEncoder(rw).Encode(f.order(parts[1], order))
+	case "cert":
+		pem.Encode(rw, &pem.Block{Type: "CERTIFICATE", Bytes: order.cert})
+		pem.Encode(rw, &pem.Block{Type: "CERTIFICATE", Bytes: f.caCert.Raw})
+	default:
+		http.NotFound(rw, req)
+	}
+}
+
+func (f *fakeACMEServer) order(id string, order *fakeACMEOrder) map[string]interface{} {
+	output := map[string]interface{}{
+		"status":         "pending",
+		"identifiers":    []fakeACMEIdentifier{{Type: "dns", Value: order.host}},
+		"authorizations": []string{f.URL + "/authz/" + id},
+		"finalize":       f.URL + "/finalize/" + id,
+	}
+	if order.cert != nil {
+		output["status"] = "valid"
+		output["certificate"] = f.URL + "/cert/" + id
+	} else if order.authorized {
+		output["status"] = "ready"
+	} else if order.invalid {
+		output["status"] = "invalid"
+	}
+	return output
+}
+
+func (f *fakeACMEServer) problem(rw http.ResponseWriter, statusCode int, problem string) {
+	rw.Header().Set(HeaderContentType, "application/problem+json")
+	rw.WriteHeader(statusCode)
+	json.NewEncoder(rw).Encode(fakeACMEProblem{Type: problem})
+}
+
+// verify checks a request's jws, returning the account it is signed by and its payload,
+// or the type of the problem with it.
+func (f *fakeACMEServer) verify(req *http.Request) (account string, payload []byte, problem string) {
+	const malformed = "urn:ietf:params:acme:error:malformed"
+	var body struct {
+		Protected string `json:"protected"`
+		Payload   string `json:"payload"`
+		Signature string `json:"signature"`
+	}
+	if req.Method != http.MethodPost || req.Header.Get(HeaderContentType) != "application/jose+json" {
+		return "", nil, malformed
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		return "", nil, malformed
+	}
+	var protected struct {
+		Alg   string            `json:"alg"`
+		Nonce string            `json:"nonce"`
+		URL   string            `json:"url"`
+		KID   string            `json:"kid"`
+		JWK   map[string]string `json:"jwk"`
+	}
+	contents, _ := jwt.DecodeSegment(body.Protected)
+	if err := json.Unmarshal(contents, &protected); err != nil || protected.URL != f.URL+req.URL.Path {
+		return "", nil, malformed
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	if !f.nonces[protected.Nonce] {
+		return "", nil, "urn:ietf:params:acme:error:badNonce"
+	}
+	delete(f.nonces, protected.Nonce)
+
+	var jwk fakeACMEAccount
+	if len(protected.JWK) > 0 {
+		jwk = fakeACMEAccount{X: protected.JWK["x"], Y: protected.JWK["y"]}
+		account = fmt.Sprintf("%x", sha256.Sum256([]byte(jwk.X+jwk.Y)))[:16]
+		f.accounts[account] = jwk
+	} else {
+		account = strings.TrimPrefix(protected.KID, f.URL+"/account/")
+		jwk = f.accounts[account]
+	}
+	x, _ := jwt.DecodeSegment(jwk.X)
+	y, _ := jwt.DecodeSegment(jwk.Y)
+	if len(x) == 0 || len(y) == 0 || protected.Alg != "ES256" {
+		return "", nil, "urn:ietf:params:acme:error:accountDoesNotExist"
+	}
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if err := jwt.SigningMethodES256.Verify(body.Protected+"."+body.Payload, body.Signature, key); err != nil {
+		return "", nil, malformed
+	}
+	payload, _ = jwt.DecodeSegment(body.Payload)
+	return account, payload, ""
+}
+
+func (f *fakeACMEServer) newNonce() string {
+	f.Lock()
+	defer f.Unlock()
+	f.serial++
+	nonce := fmt.Sprintf("nonce-%d", f.serial)
+	f.nonces[nonce] = true
+	return nonce
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 	"github.com/blend/go-sdk/logger"
 	"github.com/blend/go-sdk/util"
 	"github.com/blend/go-sdk/webutil"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 // New returns a new app.
@@ -32,10 +35,14 @@ func New() *App {
 		statics:               map[string]Fileserver{},
 		readTimeout:           DefaultReadTimeout,
 		writeTimeout:          DefaultWriteTimeout,
+		tcpKeepAlivePeriod:    DefaultTCPKeepAliveListenerPeriod,
+		http2:                 DefaultHTTP2,
 		redirectTrailingSlash: true,
 		recoverPanics:         true,
 		defaultHeaders:        DefaultHeaders,
 		shutdownGracePeriod:   DefaultShutdownGracePeriod,
+		compressor:            NewCompressor(),
+		serializers:           NewSerializers(),
 		views:                 views,
 		defaultResultProvider: views,
 	}
@@ -78,6 +85,7 @@ type App struct {
 	listener *net.TCPListener
 
 	shutdownGracePeriod time.Duration
+	backgroundWorkers   []BackgroundWorker
 
 	// statics serve files at various routes
 	statics map[string]Fileserver
@@ -91,7 +99,10 @@ type App struct {
 	handleMethodNotAllowed  bool
 
 	defaultMiddleware []Middleware
+	compressor        *Compressor
+	serializers       *Serializers
 	tracer            Tracer
+	logHeaders        []string
 
 	defaultResultProvider ResultProvider
 
@@ -101,6 +112,11 @@ type App struct {
 	writeTimeout      time.Duration
 	idleTimeout       time.Duration
 
+	tcpKeepAlivePeriod        time.Duration
+	http2                     bool
+	h2c                       bool
+	http2MaxConcurrentStreams int
+
 	state State
 
 	recoverPanics bool
@@ -136,15 +152,32 @@ func (a *App) WithConfig(cfg *Config) *App {
 	a.WithReadTimeout(cfg.GetReadTimeout())
 	a.WithWriteTimeout(cfg.GetWriteTimeout())
 	a.WithIdleTimeout(cfg.GetIdleTimeout())
+	a.WithTCPKeepAlivePeriod(cfg.GetTCPKeepAlivePeriod())
+	a.WithHTTP2(cfg.GetHTTP2())
+	a.WithH2C(cfg.GetH2C())
+	a.WithHTTP2MaxConcurrentStreams(cfg.GetHTTP2MaxConcurrentStreams())
 
 	a.WithAuth(NewAuthManagerFromConfig(cfg))
 	a.WithViews(NewViewCacheFromConfig(&cfg.Views))
 	a.WithDefaultResultProvider(a.Views())
 	a.WithBaseURL(webutil.MustParseURL(cfg.GetBaseURL()))
 	a.WithShutdownGracePeriod(cfg.GetShutdownGracePeriod())
+	a.WithLogHeaders(cfg.GetLogHeaders()...)
 	return a
 }
 
+// WithLogHeaders sets the allow-list of request headers included in request and response log events.
+// Headers not in the list (e.g. `Authorization` or `Cookie`) are never logged.
+func (a *App) WithLogHeaders(headers ...string) *App {
+	a.logHeaders = headers
+	return a
+}
+
+// LogHeaders returns the allow-list of request headers included in log events.
+func (a *App) LogHeaders() []string {
+	return a.logHeaders
+}
+
 // WithShutdownGracePeriod sets the shutdown grace period.
 func (a *App) WithShutdownGracePeriod(gracePeriod time.Duration) *App {
 	a.shutdownGracePeriod = gracePeriod
@@ -156,6 +189,29 @@ func (a *App) ShutdownGracePeriod() time.Duration {
 	return a.shutdownGracePeriod
 }
 
+// WithCompressor sets the compressor used for responses to requests that accept gzip.
+// Set it to nil to disable compression, e.g. to only compress some routes with `Compressor.Middleware`.
+func (a *App) WithCompressor(compressor *Compressor) *App {
+	a.compressor = compressor
+	return a
+}
+
+// Compressor returns the compressor used for responses.
+func (a *App) Compressor() *Compressor {
+	return a.compressor
+}
+
+// WithBackgroundWorkers adds background workers, e.g. a `cron.JobManager`, that are started and stopped with the app by `StartWithShutdown`.
+func (a *App) WithBackgroundWorkers(workers ...BackgroundWorker) *App {
+	a.backgroundWorkers = append(a.backgroundWorkers, workers...)
+	return a
+}
+
+// BackgroundWorkers returns the background workers.
+func (a *App) BackgroundWorkers() []BackgroundWorker {
+	return a.backgroundWorkers
+}
+
 // WithDefaultHeaders sets the default headers
 func (a *App) WithDefaultHeaders(headers map[string]string) *App {
 	a.defaultHeaders = headers
@@ -322,6 +378,52 @@ func (a *App) WithWriteTimeout(timeout time.Duration) *App {
 	return a
 }
 
+// TCPKeepAlivePeriod returns the keep alive period for accepted connections.
+func (a *App) TCPKeepAlivePeriod() time.Duration {
+	return a.tcpKeepAlivePeriod
+}
+
+// WithTCPKeepAlivePeriod sets the keep alive period for accepted connections; a negative period disables keep alives.
+func (a *App) WithTCPKeepAlivePeriod(period time.Duration) *App {
+	a.tcpKeepAlivePeriod = period
+	return a
+}
+
+// HTTP2 returns if the server negotiates http/2 with tls clients.
+func (a *App) HTTP2() bool {
+	return a.http2
+}
+
+// WithHTTP2 sets if the server negotiates http/2 with tls clients; it is enabled by default.
+func (a *App) WithHTTP2(enabled bool) *App {
+	a.http2 = enabled
+	return a
+}
+
+// H2C returns if the server accepts http/2 without tls.
+func (a *App) H2C() bool {
+	return a.h2c
+}
+
+// WithH2C sets if the server accepts http/2 without tls ("h2c"), for apps behind a proxy that terminates tls
+// and speaks http/2 to its upstreams, e.g. grpc-web or envoy. It has no effect if http/2 is disabled.
+func (a *App) WithH2C(enabled bool) *App {
+	a.h2c = enabled
+	return a
+}
+
+// HTTP2MaxConcurrentStreams returns the number of concurrent streams each http/2 client can open.
+func (a *App) HTTP2MaxConcurrentStreams() int {
+	return a.http2MaxConcurrentStreams
+}
+
+// WithHTTP2MaxConcurrentStreams sets the number of concurrent streams each http/2 client can open;
+// zero uses the http2 package's default.
+func (a *App) WithHTTP2MaxConcurrentStreams(streams int) *App {
+	a.http2MaxConcurrentStreams = streams
+	return a
+}
+
 // WithHSTS enables or disables issuing the strict transport security header.
 func (a *App) WithHSTS(enabled bool) *App {
 	a.hsts = enabled
@@ -523,6 +625,13 @@ func (a *App) WithDefaultMiddleware(middleware ...Middleware) *App {
 	return a
 }
 
+// Use adds application wide default middleware, nested outside any default middleware already added.
+// Default middleware is applied when routes are registered, so it must be added before the routes it applies to.
+func (a *App) Use(middleware ...Middleware) *App {
+	a.defaultMiddleware = append(a.defaultMiddleware, middleware...)
+	return a
+}
+
 // DefaultMiddleware returns the default middleware.
 func (a *App) DefaultMiddleware() []Middleware {
 	return a.defaultMiddleware
@@ -539,9 +648,9 @@ func (a *App) Tracer() Tracer {
 	return a.tracer
 }
 
-// CreateServer returns the basic http.Server for the app.
+// CreateServer returns the basic http.Server for the app, configured for http/2 as set on the app.
 func (a *App) CreateServer() *http.Server {
-	return &http.Server{
+	server := &http.Server{
 		Addr:              a.BindAddr(),
 		Handler:           a,
 		MaxHeaderBytes:    a.maxHeaderBytes,
@@ -551,6 +660,26 @@ func (a *App) CreateServer() *http.Server {
 		IdleTimeout:       a.idleTimeout,
 		TLSConfig:         a.tlsConfig,
 	}
+	if !a.http2 {
+		// a non-nil, empty map disables the server's automatic http/2 support.
+		server.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		return server
+	}
+
+	h2s := &http2.Server{
+		MaxConcurrentStreams: uint32(a.http2MaxConcurrentStreams),
+		IdleTimeout:          a.idleTimeout,
+	}
+	// the http2 package creates a tls config if there isn't one, which would make the server serve tls.
+	if server.TLSConfig != nil {
+		if err := http2.ConfigureServer(server, h2s); err != nil {
+			a.syncInfof("http/2 is disabled; %v", err)
+		}
+	}
+	if a.h2c {
+		server.Handler = h2c.NewHandler(a, h2s)
+	}
+	return server
 }
 
 // WithServer sets the server.
@@ -631,7 +760,7 @@ func (a *App) Start() (err error) {
 		a.log.SyncTrigger(NewAppEvent(AppStartComplete).WithApp(a).WithElapsed(time.Since(start)))
 	}
 
-	keepAliveListener := TCPKeepAliveListener{a.listener}
+	keepAliveListener := TCPKeepAliveListener{TCPListener: a.listener, Period: a.tcpKeepAlivePeriod}
 	var shutdownErr error
 	a.latch.Started()
 	if a.server.TLSConfig != nil {
@@ -647,7 +776,8 @@ func (a *App) Start() (err error) {
 	return
 }
 
-// Shutdown stops the server.
+// Shutdown stops the server, draining in flight requests for up to the shutdown grace period
+// before closing any remaining connections.
 func (a *App) Shutdown() error {
 	if !a.Latch().IsRunning() {
 		return nil
@@ -659,6 +789,10 @@ func (a *App) Shutdown() error {
 	a.syncInfof("server shutting down")
 	a.server.SetKeepAlivesEnabled(false)
 	if err := a.server.Shutdown(ctx); err != nil {
+		if err == context.DeadlineExceeded {
+			a.syncInfof("server shutdown grace period elapsed, closing remaining connections")
+			a.server.Close()
+		}
 		return exception.New(err)
 	}
 
@@ -723,6 +857,17 @@ func (a *App) Views() *ViewCache {
 	return a.views
 }
 
+// WithSerializers sets the serializers used to negotiate the media type of results.
+func (a *App) WithSerializers(serializers *Serializers) *App {
+	a.serializers = serializers
+	return a
+}
+
+// Serializers returns the serializers used to negotiate the media type of results.
+func (a *App) Serializers() *Serializers {
+	return a.serializers
+}
+
 // --------------------------------------------------------------------------------
 // Static Result Methods
 // --------------------------------------------------------------------------------
@@ -758,24 +903,40 @@ func (a *App) SetStaticMiddleware(route string, middlewares ...Middleware) error
 	return exception.New("no static fileserver mounted at route").WithMessagef("route: %s", mountedRoute)
 }
 
+// SetStaticPrecompressed sets if pre-compressed files, e.g. `foo.css.br` or `foo.css.gz`, are served for the given static path.
+func (a *App) SetStaticPrecompressed(route string, precompressed bool) error {
+	mountedRoute := a.createStaticMountRoute(route)
+	if static, hasRoute := a.statics[mountedRoute]; hasRoute {
+		typed, ok := static.(*StaticFileServer)
+		if !ok {
+			return exception.New("static fileserver does not support pre-compressed files").WithMessagef("route: %s", mountedRoute)
+		}
+		typed.WithPrecompressed(precompressed)
+		return nil
+	}
+	return exception.New("no static fileserver mounted at route").WithMessagef("route: %s", mountedRoute)
+}
+
 // ServeStatic serves files from the given file system root.
 // If the path does not end with "/*filepath" that suffix will be added for you internally.
 // For example if root is "/etc" and *filepath is "passwd", the local file
 // "/etc/passwd" would be served.
 func (a *App) ServeStatic(route, filepath string) {
-	sfs := NewStaticFileServer(http.Dir(filepath))
-	mountedRoute := a.createStaticMountRoute(route)
-	a.statics[mountedRoute] = sfs
-	a.Handle("GET", mountedRoute, a.renderAction(a.middlewarePipeline(sfs.Action)))
+	a.ServeFileserver(route, NewStaticFileServer(http.Dir(filepath)))
 }
 
 // ServeStaticCached serves files from the given file system root.
 // If the path does not end with "/*filepath" that suffix will be added for you internally.
 func (a *App) ServeStaticCached(route, filepath string) {
-	sfs := NewCachedStaticFileServer(http.Dir(filepath))
+	a.ServeFileserver(route, NewCachedStaticFileServer(http.Dir(filepath)))
+}
+
+// ServeFileserver serves files from a fileserver, e.g. a `StaticFileServer` configured with caching headers.
+// If the path does not end with "/*filepath" that suffix will be added for you internally.
+func (a *App) ServeFileserver(route string, fileserver Fileserver) {
 	mountedRoute := a.createStaticMountRoute(route)
-	a.statics[mountedRoute] = sfs
-	a.Handle("GET", mountedRoute, a.renderAction(a.middlewarePipeline(sfs.Action)))
+	a.statics[mountedRoute] = fileserver
+	a.Handle("GET", mountedRoute, a.renderAction(a.middlewarePipeline(fileserver.Action)))
 }
 
 func (a *App) createStaticMountRoute(route string) string {
@@ -921,6 +1082,23 @@ func (a *App) Lookup(method, path string) (route *Route, params RouteParameters,
 	return nil, nil, false
 }
 
+// Routes returns the registered routes, sorted by path and then method.
+func (a *App) Routes() []Route {
+	var routes []Route
+	for _, root := range a.routes {
+		root.walk(func(route *Route) {
+			routes = append(routes, *route)
+		})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+	return routes
+}
+
 // --------------------------------------------------------------------------------
 // Request Pipeline
 // --------------------------------------------------------------------------------
@@ -993,9 +1171,8 @@ func (a *App) renderAction(action Action) Handler {
 		var tf TraceFinisher
 
 		var response ResponseWriter
-		if strings.Contains(r.Header.Get(HeaderAcceptEncoding), ContentEncodingGZIP) {
-			w.Header().Set(HeaderContentEncoding, ContentEncodingGZIP)
-			response = NewCompressedResponseWriter(w)
+		if a.compressor != nil && AcceptsEncoding(r, ContentEncodingGZIP) {
+			response = a.compressor.NewResponseWriter(w)
 		} else {
 			w.Header().Set(HeaderContentEncoding, ContentEncodingIdentity)
 			response = NewRawResponseWriter(w)
@@ -1034,6 +1211,10 @@ func (a *App) renderAction(action Action) Handler {
 			a.logError(result.Render(ctx))
 		}
 
+		// close the response if middleware replaced it, e.g. to compress it.
+		if ctx.Response() != response {
+			a.logError(ctx.Response().Close())
+		}
 		ctx.setStatusCode(response.StatusCode())
 		ctx.setContentLength(response.ContentLength())
 		ctx.onRequestFinish()
@@ -1057,6 +1238,7 @@ func (a *App) createCtx(w ResponseWriter, r *http.Request, route *Route, p Route
 		request:               r,
 		app:                   a,
 		views:                 a.views,
+		serializers:           a.serializers,
 		route:                 route,
 		routeParameters:       p,
 		state:                 s,
@@ -1147,6 +1329,7 @@ func (a *App) addHSTSHeader(w http.ResponseWriter) {
 
 func (a *App) httpRequestEvent(ctx *Ctx) *logger.HTTPRequestEvent {
 	event := logger.NewHTTPRequestEvent(ctx.Request()).
+		WithHeaderAllowList(a.logHeaders...).
 		WithState(ctx.state)
 	event.SetEntity(ctx.ID())
 	if ctx.Route() != nil {
@@ -1160,8 +1343,12 @@ func (a *App) httpResponseEvent(ctx *Ctx) *logger.HTTPResponseEvent {
 		WithStatusCode(ctx.statusCode).
 		WithElapsed(ctx.Elapsed()).
 		WithContentLength(ctx.contentLength).
+		WithHeaderAllowList(a.logHeaders...).
 		WithState(ctx.state)
 	event.SetEntity(ctx.ID())
+	if requestID := ctx.RequestID(); len(requestID) > 0 {
+		event = event.WithLabel(LabelRequestID, requestID)
+	}
 
 	if ctx.Route() != nil {
 		event = event.WithRoute(ctx.Route().String())
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"crypto/tls"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 	"github.com/blend/go-sdk/env"
 	"github.com/blend/go-sdk/logger"
 	"github.com/blend/go-sdk/util"
+	"golang.org/x/net/http2"
 )
 
 func controllerNoOp(_ *Ctx) Result { return nil }
@@ -75,6 +78,11 @@ func TestAppNewFromConfig(t *testing.T) {
 		ReadTimeout:            6 * time.Second,
 		IdleTimeout:            7 * time.Second,
 		WriteTimeout:           8 * time.Second,
+		TCPKeepAlivePeriod:     9 * time.Second,
+		HTTP2:                  util.OptionalBool(false),
+		H2C:                    util.OptionalBool(true),
+
+		HTTP2MaxConcurrentStreams: 64,
 
 		CookieName: "A GOOD ONE",
 
@@ -92,6 +100,10 @@ func TestAppNewFromConfig(t *testing.T) {
 	assert.Equal(6*time.Second, app.ReadTimeout())
 	assert.Equal(7*time.Second, app.IdleTimeout())
 	assert.Equal(8*time.Second, app.WriteTimeout())
+	assert.Equal(9*time.Second, app.TCPKeepAlivePeriod())
+	assert.False(app.HTTP2())
+	assert.True(app.H2C())
+	assert.Equal(64, app.HTTP2MaxConcurrentStreams())
 	assert.Equal("A GOOD ONE", app.Auth().CookieName(), "we should use the auth config for the auth manager")
 	assert.True(app.Views().Cached(), "we should use the view cache config for the view cache")
 
@@ -318,6 +330,30 @@ func TestAppDefaultResultProvider(t *testing.T) {
 	assert.NotNil(rc.defaultResultProvider)
 }
 
+func TestAppUse(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	step := func(name string) Middleware {
+		return func(action Action) Action {
+			return func(ctx *Ctx) Result {
+				calls = append(calls, name)
+				return action(ctx)
+			}
+		}
+	}
+
+	app := New().Use(step("first")).Use(step("second"))
+	assert.Len(app.DefaultMiddleware(), 2)
+	app.GET("/", func(ctx *Ctx) Result {
+		calls = append(calls, "action")
+		return ctx.Text().Result("ok")
+	}, step("route"))
+
+	assert.Nil(app.Mock().WithPathf("/").Execute())
+	assert.Equal([]string{"second", "first", "route", "action"}, calls)
+}
+
 func TestAppDefaultResultProviderWithDefault(t *testing.T) {
 	assert := assert.New(t)
 	app := New().WithDefaultMiddleware(ViewProviderAsDefault)
@@ -425,6 +461,30 @@ func TestAppNotFound(t *testing.T) {
 	wg.Wait()
 }
 
+func TestAppLogHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := bytes.NewBuffer(nil)
+	agent := logger.New().WithFlags(logger.AllFlags()).WithWriter(logger.NewJSONWriter(buffer))
+	app := New().WithLogger(agent).WithLogHeaders("X-Request-Id")
+	app.GET("/users/:id", func(r *Ctx) Result {
+		return r.Raw([]byte("ok!"))
+	})
+
+	err := app.Mock().Get("/users/123").
+		WithHeader("X-Request-Id", "request-id").
+		WithHeader("Authorization", "Bearer secret").
+		WithHeader("User-Agent", "test-agent").
+		Execute()
+	assert.Nil(err)
+	assert.Nil(agent.Drain())
+
+	assert.Contains(buffer.String(), `"route":"/users/:id"`)
+	assert.Contains(buffer.String(), `"userAgent":"test-agent"`)
+	assert.Contains(buffer.String(), `"X-Request-Id":"request-id"`)
+	assert.NotContains(buffer.String(), "secret")
+}
+
 func TestAppDefaultHeaders(t *testing.T) {
 	assert := assert.New(t)
 	app := New().WithDefaultHeader("foo", "bar").WithDefaultHeader("baz", "buzz")
@@ -547,6 +607,57 @@ func TestAppHandlesPanics(t *testing.T) {
 	assert.False(didRecover)
 }
 
+func TestAppH2C(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New().WithBindAddr(DefaultIntegrationBindAddr).WithH2C(true)
+	app.GET("/", func(r *Ctx) Result {
+		return r.Text().Result(r.Request().Proto)
+	})
+
+	go app.Start()
+	defer app.Shutdown()
+	<-app.NotifyStarted()
+
+	client := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.Dial(network, addr)
+			},
+		},
+	}
+	res, err := client.Get("http://" + app.Listener().Addr().String() + "/")
+	assert.Nil(err)
+	defer res.Body.Close()
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal("HTTP/2.0", string(contents))
+
+	res, err = http.Get("http://" + app.Listener().Addr().String() + "/")
+	assert.Nil(err)
+	defer res.Body.Close()
+	contents, err = ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal("HTTP/1.1", string(contents), "http/1.1 clients should still be served")
+}
+
+func TestAppCreateServerHTTP2(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New().WithTLSConfig(&tls.Config{})
+	server := app.CreateServer()
+	assert.Contains(strings.Join(server.TLSConfig.NextProtos, ","), "h2")
+
+	server = New().CreateServer()
+	assert.Nil(server.TLSConfig, "http/2 should not force tls")
+
+	server = New().WithTLSConfig(&tls.Config{}).WithHTTP2(false).CreateServer()
+	assert.NotNil(server.TLSNextProto)
+	assert.Empty(server.TLSNextProto)
+	assert.NotContains(strings.Join(server.TLSConfig.NextProtos, ","), "h2")
+}
+
 var (
 	_ Tracer     = (*mockTracer)(nil)
 	_ ViewTracer = (*mockTracer)(nil)
@@ -2,9 +2,12 @@ package web
 
 import (
 	"context"
+	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/blend/go-sdk/exception"
 	"github.com/blend/go-sdk/webutil"
 )
 
@@ -17,6 +20,29 @@ const (
 	AuthManagerModeLocal = "cached"
 )
 
+const (
+	// CookieSameSiteLax is the lax cookie same site policy.
+	CookieSameSiteLax = "lax"
+	// CookieSameSiteStrict is the strict cookie same site policy.
+	CookieSameSiteStrict = "strict"
+	// CookieSameSiteNone is the none cookie same site policy; cookies must also be https only.
+	CookieSameSiteNone = "none"
+)
+
+// ParseCookieSameSite parses a cookie same site policy, returning the default mode if it is unrecognized.
+func ParseCookieSameSite(sameSite string) http.SameSite {
+	switch strings.ToLower(sameSite) {
+	case CookieSameSiteLax:
+		return http.SameSiteLaxMode
+	case CookieSameSiteStrict:
+		return http.SameSiteStrictMode
+	case CookieSameSiteNone:
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteDefaultMode
+	}
+}
+
 // NewAuthManagerFromConfig returns a new auth manager from a given config.
 func NewAuthManagerFromConfig(cfg *Config) (manager *AuthManager) {
 	switch cfg.GetAuthManagerMode() {
@@ -33,9 +59,17 @@ func NewAuthManagerFromConfig(cfg *Config) (manager *AuthManager) {
 	return manager.WithCookieHTTPSOnly(cfg.GetCookieHTTPSOnly()).
 		WithCookieName(cfg.GetCookieName()).
 		WithCookiePath(cfg.GetCookiePath()).
+		WithCookieSameSite(ParseCookieSameSite(cfg.GetCookieSameSite())).
+		WithSessionRotationInterval(cfg.GetSessionRotationInterval()).
+		WithSessionRotationGracePeriod(cfg.GetSessionRotationGracePeriod()).
 		WithSessionTimeoutProvider(SessionTimeoutProvider(cfg.GetSessionTimeoutIsAbsolute(), cfg.GetSessionTimeout()))
 }
 
+// NewSessionStoreAuthManager returns a new server auth manager that saves sessions to a given store.
+func NewSessionStoreAuthManager(store SessionStore) *AuthManager {
+	return NewServerAuthManager().WithSessionStore(store)
+}
+
 // NewLocalAuthManager returns a new locally cached session manager.
 // It saves sessions to a local store.
 func NewLocalAuthManager() *AuthManager {
@@ -46,6 +80,9 @@ func NewLocalAuthManager() *AuthManager {
 		removeHandler:  cache.RemoveHandler,
 		cookieName:     DefaultCookieName,
 		cookiePath:     DefaultCookiePath,
+		cookieSameSite: ParseCookieSameSite(DefaultCookieSameSite),
+
+		sessionRotationGracePeriod: DefaultSessionRotationGracePeriod,
 	}
 }
 
@@ -58,7 +95,10 @@ func NewJWTAuthManager(key []byte) *AuthManager {
 		parseSessionValueHandler:     jwtm.ParseSessionValueHandler,
 		cookieName:                   DefaultCookieName,
 		cookiePath:                   DefaultCookiePath,
+		cookieSameSite:               ParseCookieSameSite(DefaultCookieSameSite),
 		sessionTimeoutProvider:       SessionTimeoutProviderAbsolute(DefaultSessionTimeout),
+
+		sessionRotationGracePeriod: DefaultSessionRotationGracePeriod,
 	}
 }
 
@@ -66,8 +106,11 @@ func NewJWTAuthManager(key []byte) *AuthManager {
 // You should set the `FetchHandler`, the `PersistHandler` and the `RemoveHandler`.
 func NewServerAuthManager() *AuthManager {
 	return &AuthManager{
-		cookieName: DefaultCookieName,
-		cookiePath: DefaultCookiePath,
+		cookieName:     DefaultCookieName,
+		cookiePath:     DefaultCookiePath,
+		cookieSameSite: ParseCookieSameSite(DefaultCookieSameSite),
+
+		sessionRotationGracePeriod: DefaultSessionRotationGracePeriod,
 	}
 }
 
@@ -114,6 +157,10 @@ type AuthManager struct {
 	cookieName      string
 	cookiePath      string
 	cookieHTTPSOnly bool
+	cookieSameSite  http.SameSite
+
+	sessionRotationInterval    time.Duration
+	sessionRotationGracePeriod time.Duration
 }
 
 // --------------------------------------------------------------------------------
@@ -210,6 +257,16 @@ func (am *AuthManager) VerifySession(ctx *Ctx) (session *Session, err error) {
 		}
 	}
 
+	// a session id that was rotated is valid until its grace period ends, but isn't rotated or extended again.
+	if len(session.ReplacedBy) > 0 {
+		return
+	}
+
+	// rotate the session id if it is due; this also updates the expiry.
+	if am.shouldRotate(session) {
+		return am.rotate(ctx, session)
+	}
+
 	if am.sessionTimeoutProvider != nil {
 		session.ExpiresUTC = am.sessionTimeoutProvider(session)
 		if am.persistHandler != nil {
@@ -223,6 +280,22 @@ func (am *AuthManager) VerifySession(ctx *Ctx) (session *Session, err error) {
 	return
 }
 
+// RotateSession issues a new session id for the current session, e.g. after a user's privileges change;
+// the previous session id is removed once the `SessionRotationGracePeriod` passes.
+// The session must have been verified with `VerifySession`.
+func (am *AuthManager) RotateSession(ctx *Ctx) (*Session, error) {
+	session := ctx.Session()
+	if session.IsZero() {
+		return nil, exception.New(ErrSessionIDEmpty)
+	}
+	rotated, err := am.rotate(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	ctx.WithSession(rotated)
+	return rotated, nil
+}
+
 // LoginRedirect returns a redirect result for when auth fails and you need to
 // send the user to a login page.
 func (am *AuthManager) LoginRedirect(ctx *Ctx) Result {
@@ -274,6 +347,54 @@ func (am *AuthManager) CookiesHTTPSOnly() bool {
 	return am.cookieHTTPSOnly
 }
 
+// WithCookieSameSite sets the same site policy for issued cookies.
+func (am *AuthManager) WithCookieSameSite(sameSite http.SameSite) *AuthManager {
+	am.cookieSameSite = sameSite
+	return am
+}
+
+// CookieSameSite returns the same site policy for issued cookies.
+func (am *AuthManager) CookieSameSite() http.SameSite {
+	return am.cookieSameSite
+}
+
+// WithSessionRotationInterval sets how often session ids are rotated by `VerifySession`; zero disables rotation.
+func (am *AuthManager) WithSessionRotationInterval(interval time.Duration) *AuthManager {
+	am.sessionRotationInterval = interval
+	return am
+}
+
+// SessionRotationInterval returns how often session ids are rotated.
+func (am *AuthManager) SessionRotationInterval() time.Duration {
+	return am.sessionRotationInterval
+}
+
+// WithSessionRotationGracePeriod sets how long a session id is still valid for after it's rotated,
+// so concurrent requests with the previous id aren't logged out; zero removes the previous id immediately.
+func (am *AuthManager) WithSessionRotationGracePeriod(gracePeriod time.Duration) *AuthManager {
+	am.sessionRotationGracePeriod = gracePeriod
+	return am
+}
+
+// SessionRotationGracePeriod returns how long a session id is still valid for after it's rotated.
+func (am *AuthManager) SessionRotationGracePeriod() time.Duration {
+	return am.sessionRotationGracePeriod
+}
+
+// WithSessionStore sets the persist, fetch and remove handlers to use a given session store.
+func (am *AuthManager) WithSessionStore(store SessionStore) *AuthManager {
+	am.persistHandler = func(ctx context.Context, session *Session, _ State) error {
+		return store.PersistSession(ctx, session)
+	}
+	am.fetchHandler = func(ctx context.Context, sessionID string, _ State) (*Session, error) {
+		return store.FetchSession(ctx, sessionID)
+	}
+	am.removeHandler = func(ctx context.Context, sessionID string, _ State) error {
+		return store.RemoveSession(ctx, sessionID)
+	}
+	return am
+}
+
 // WithCookieName sets the cookie name.
 func (am *AuthManager) WithCookieName(paramName string) *AuthManager {
 	am.cookieName = paramName
@@ -409,9 +530,72 @@ func (am AuthManager) shouldUpdateSessionExpiry() bool {
 	return am.sessionTimeoutProvider != nil
 }
 
+// shouldRotate returns if the session id is due to be rotated.
+func (am AuthManager) shouldRotate(session *Session) bool {
+	if am.sessionRotationInterval <= 0 {
+		return false
+	}
+	last := session.RotatedUTC
+	if last.IsZero() {
+		last = session.CreatedUTC
+	}
+	return time.Now().UTC().Sub(last) >= am.sessionRotationInterval
+}
+
+// rotate replaces a session's id, persisting the new session and either expiring the previous id
+// after the grace period or removing it.
+func (am *AuthManager) rotate(ctx *Ctx, session *Session) (*Session, error) {
+	previous := *session
+	session.SessionID = NewSessionID()
+	session.RotatedUTC = time.Now().UTC()
+	if am.sessionTimeoutProvider != nil {
+		session.ExpiresUTC = am.sessionTimeoutProvider(session)
+	}
+
+	if am.persistHandler != nil {
+		if err := am.persistHandler(ctx.Context(), session, ctx.state); err != nil {
+			return nil, err
+		}
+	}
+	if am.sessionRotationGracePeriod > 0 && am.persistHandler != nil {
+		previous.ReplacedBy = session.SessionID
+		previous.RotatedUTC = session.RotatedUTC
+		if graceExpires := session.RotatedUTC.Add(am.sessionRotationGracePeriod); previous.ExpiresUTC.IsZero() || graceExpires.Before(previous.ExpiresUTC) {
+			previous.ExpiresUTC = graceExpires
+		}
+		if err := am.persistHandler(ctx.Context(), &previous, ctx.state); err != nil {
+			return nil, err
+		}
+	} else if am.removeHandler != nil {
+		if err := am.removeHandler(ctx.Context(), previous.SessionID, ctx.state); err != nil {
+			return nil, err
+		}
+	}
+
+	sessionValue := session.SessionID
+	if am.serializeSessionValueHandler != nil {
+		var err error
+		sessionValue, err = am.serializeSessionValueHandler(ctx.Context(), session, ctx.state)
+		if err != nil {
+			return nil, err
+		}
+	}
+	am.injectCookie(ctx, am.CookieName(), sessionValue, session.ExpiresUTC)
+	return session, nil
+}
+
 // InjectCookie injects a session cookie into the context.
 func (am *AuthManager) injectCookie(ctx *Ctx, name, value string, expire time.Time) {
-	ctx.WriteNewCookie(name, value, expire, am.CookiePath(), am.CookiesHTTPSOnly())
+	ctx.WriteCookie(&http.Cookie{
+		Name:     name,
+		HttpOnly: true,
+		Value:    value,
+		Path:     am.CookiePath(),
+		Secure:   am.CookiesHTTPSOnly(),
+		SameSite: am.cookieSameSite,
+		Domain:   ctx.getCookieDomain(),
+		Expires:  expire,
+	})
 }
 
 // readParam reads a param from a given request context from either the cookies or headers.
@@ -0,0 +1,340 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/util"
+	"github.com/blend/go-sdk/uuid"
+)
+
+const (
+	// FieldTagQuery is the struct tag that maps a field to a query string value.
+	FieldTagQuery = "query"
+	// FieldTagForm is the struct tag that maps a field to a posted form value.
+	FieldTagForm = "form"
+	// FieldTagValidate is the struct tag that lists a field's validation rules, e.g. `validate:"required,max=64"`.
+	FieldTagValidate = "validate"
+
+	// ContentTypeApplicationFormEncoded is the content type for url encoded forms.
+	ContentTypeApplicationFormEncoded = "application/x-www-form-urlencoded"
+	// ContentTypeMultipartFormData is the content type for multipart forms.
+	ContentTypeMultipartFormData = "multipart/form-data"
+
+	// DefaultBindMaxMemory is the maximum memory used to parse multipart forms; the rest is stored on disk.
+	DefaultBindMaxMemory = 32 << 20
+)
+
+// Validation rules.
+const (
+	// ValidationRuleRequired requires a field be set to a non-zero value.
+	ValidationRuleRequired = "required"
+	// ValidationRuleMin is a minimum value for numbers, or a minimum length for strings, slices and maps.
+	ValidationRuleMin = "min"
+	// ValidationRuleMax is a maximum value for numbers, or a maximum length for strings, slices and maps.
+	ValidationRuleMax = "max"
+	// ValidationRuleFormat requires a string be in a given format, e.g. `format=email`.
+	ValidationRuleFormat = "format"
+	// ValidationRuleType is the rule reported when a value could not be decoded into a field's type.
+	ValidationRuleType = "type"
+)
+
+// Validation formats.
+const (
+	// ValidationFormatEmail is an email address, without a display name.
+	ValidationFormatEmail = "email"
+	// ValidationFormatURL is an absolute url.
+	ValidationFormatURL = "url"
+	// ValidationFormatUUID is a uuid, with or without dashes.
+	ValidationFormatUUID = "uuid"
+)
+
+// FieldError is a validation error for a single field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (fe FieldError) Error() string {
+	return fmt.Sprintf("%s %s", fe.Field, fe.Message)
+}
+
+// BindError is returned by `Ctx.Bind` if the request could not be decoded or is invalid.
+// It marshals to json as a structured response, so it can be passed directly to `BadRequest(err)`.
+type BindError struct {
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// Error implements error.
+func (be *BindError) Error() string {
+	if len(be.Fields) == 0 {
+		return be.Message
+	}
+	fields := make([]string, len(be.Fields))
+	for index, field := range be.Fields {
+		fields[index] = field.Error()
+	}
+	return be.Message + ": " + strings.Join(fields, ", ")
+}
+
+// IsBindError returns if an error is a bind error, i.e. the request was bad,
+// as opposed to a problem with the bind target or its validation rules.
+func IsBindError(err error) bool {
+	_, ok := err.(*BindError)
+	return ok
+}
+
+// Bind decodes the request into the target, which must be a pointer to a struct, and validates it.
+// Query string values are bound to fields with a `query` tag, then the body is decoded based on its
+// content type; json bodies use `json` tags and form bodies use `form` tags.
+// Bad requests return a `*BindError`, which should be rendered with `BadRequest(err)`.
+func (rc *Ctx) Bind(target interface{}) error {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Struct {
+		return exception.New(ErrBindTarget).WithMessagef("bind target must be a pointer to a struct, got %T", target)
+	}
+	if rc.request == nil {
+		return exception.New(ErrBindTarget).WithMessage("request is unset")
+	}
+
+	if rc.request.URL != nil && len(rc.request.URL.RawQuery) > 0 {
+		if err := util.Reflection.PatchStrings(FieldTagQuery, flattenValues(rc.request.URL.Query()), target); err != nil {
+			return &BindError{Message: "invalid query string; " + err.Error()}
+		}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(rc.request.Header.Get(HeaderContentType))
+	switch mediaType {
+	case "application/json":
+		body, err := rc.PostBody()
+		if err != nil {
+			return err
+		}
+		if len(body) == 0 {
+			break
+		}
+		if err := json.Unmarshal(body, target); err != nil {
+			if typeErr, ok := err.(*json.UnmarshalTypeError); ok && len(typeErr.Field) > 0 {
+				return &BindError{Message: "invalid json body", Fields: []FieldError{{
+					Field:   typeErr.Field,
+					Rule:    ValidationRuleType,
+					Message: "must be a " + typeErr.Type.String(),
+				}}}
+			}
+			return &BindError{Message: "invalid json body; " + err.Error()}
+		}
+	case ContentTypeApplicationFormEncoded, ContentTypeMultipartFormData:
+		var err error
+		if mediaType == ContentTypeMultipartFormData {
+			err = rc.request.ParseMultipartForm(DefaultBindMaxMemory)
+		} else {
+			err = rc.request.ParseForm()
+		}
+		if err != nil {
+			return &BindError{Message: "invalid form body; " + err.Error()}
+		}
+		if err := util.Reflection.PatchStrings(FieldTagForm, flattenValues(rc.request.PostForm), target); err != nil {
+			return &BindError{Message: "invalid form body; " + err.Error()}
+		}
+	}
+
+	return Validate(target)
+}
+
+// Validate checks the `validate` tags of a struct's fields, recursing into nested structs.
+// Rules are comma separated, e.g. `validate:"required,min=1,max=10"` or `validate:"format=email"`.
+// Unset optional fields (nil pointers, empty strings, slices and maps) are not checked against other rules.
+// It returns a `*BindError` listing each invalid field by its json name, or an exception if a rule is malformed.
+func Validate(target interface{}) error {
+	value := util.Reflection.Value(target)
+	if value.Kind() != reflect.Struct {
+		return exception.New(ErrBindTarget).WithMessagef("validate target must be a struct, got %T", target)
+	}
+	var fields []FieldError
+	if err := validateStruct(value, "", &fields); err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return &BindError{Message: "invalid request", Fields: fields}
+	}
+	return nil
+}
+
+func validateStruct(value reflect.Value, prefix string, fieldErrors *[]FieldError) error {
+	valueType := value.Type()
+	for x := 0; x < valueType.NumField(); x++ {
+		field := valueType.Field(x)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := prefix + fieldName(field)
+		fieldValue := value.Field(x)
+
+		if rules := field.Tag.Get(FieldTagValidate); len(rules) > 0 {
+			fieldError, err := validateField(fieldValue, name, rules)
+			if err != nil {
+				return err
+			}
+			if fieldError != nil {
+				*fieldErrors = append(*fieldErrors, *fieldError)
+				continue
+			}
+		}
+
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+		if fieldValue.Kind() == reflect.Struct {
+			nestedPrefix := name + "."
+			if field.Anonymous {
+				nestedPrefix = prefix
+			}
+			if err := validateStruct(fieldValue, nestedPrefix, fieldErrors); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateField returns the first rule the field fails, if any.
+func validateField(value reflect.Value, name, rules string) (*FieldError, error) {
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	var empty bool
+	switch value.Kind() {
+	case reflect.Ptr:
+		empty = true
+	case reflect.String, reflect.Slice, reflect.Map:
+		empty = value.Len() == 0
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		ruleName, ruleArg := rule, ""
+		if equals := strings.Index(rule, "="); equals >= 0 {
+			ruleName, ruleArg = rule[:equals], rule[equals+1:]
+		}
+
+		if ruleName == ValidationRuleRequired {
+			if empty || util.Reflection.IsZero(value) {
+				return &FieldError{Field: name, Rule: ruleName, Message: "is required"}, nil
+			}
+			continue
+		}
+		if empty {
+			continue
+		}
+
+		var message string
+		var err error
+		switch ruleName {
+		case ValidationRuleMin, ValidationRuleMax:
+			message, err = validateBound(value, ruleName, ruleArg)
+		case ValidationRuleFormat:
+			message, err = validateFormat(value, ruleArg)
+		default:
+			err = exception.New(ErrValidationRule).WithMessagef("field %s: unknown rule %q", name, ruleName)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(message) > 0 {
+			return &FieldError{Field: name, Rule: ruleName, Message: message}, nil
+		}
+	}
+	return nil, nil
+}
+
+func validateBound(value reflect.Value, rule, arg string) (string, error) {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return "", exception.New(ErrValidationRule).WithMessagef("invalid %s %q", rule, arg)
+	}
+
+	var actual float64
+	var unit string
+	switch kind := value.Kind(); {
+	case kind == reflect.String:
+		actual, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+	case kind == reflect.Slice, kind == reflect.Map, kind == reflect.Array:
+		actual, unit = float64(value.Len()), " items"
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		actual = float64(value.Int())
+	case kind >= reflect.Uint && kind <= reflect.Uintptr:
+		actual = float64(value.Uint())
+	case kind == reflect.Float32, kind == reflect.Float64:
+		actual = value.Float()
+	default:
+		return "", exception.New(ErrValidationRule).WithMessagef("%s is not supported for %s", rule, value.Type())
+	}
+
+	if rule == ValidationRuleMin && actual < bound {
+		if len(unit) > 0 {
+			return fmt.Sprintf("must have at least %s%s", arg, unit), nil
+		}
+		return fmt.Sprintf("must be at least %s", arg), nil
+	}
+	if rule == ValidationRuleMax && actual > bound {
+		if len(unit) > 0 {
+			return fmt.Sprintf("must have at most %s%s", arg, unit), nil
+		}
+		return fmt.Sprintf("must be at most %s", arg), nil
+	}
+	return "", nil
+}
+
+func validateFormat(value reflect.Value, format string) (string, error) {
+	if value.Kind() != reflect.String {
+		return "", exception.New(ErrValidationRule).WithMessagef("format is not supported for %s", value.Type())
+	}
+	corpus := value.String()
+	switch format {
+	case ValidationFormatEmail:
+		if address, err := mail.ParseAddress(corpus); err != nil || address.Address != corpus {
+			return "must be a valid email address", nil
+		}
+	case ValidationFormatURL:
+		if parsed, err := url.ParseRequestURI(corpus); err != nil || len(parsed.Scheme) == 0 || len(parsed.Host) == 0 {
+			return "must be a valid url", nil
+		}
+	case ValidationFormatUUID:
+		if _, err := uuid.Parse(corpus); err != nil {
+			return "must be a valid uuid", nil
+		}
+	default:
+		return "", exception.New(ErrValidationRule).WithMessagef("unknown format %q", format)
+	}
+	return "", nil
+}
+
+// fieldName returns the name a field is reported as; its json name, form name, query name, or go name.
+func fieldName(field reflect.StructField) string {
+	for _, tagName := range []string{"json", FieldTagForm, FieldTagQuery} {
+		if name := strings.Split(field.Tag.Get(tagName), ",")[0]; len(name) > 0 && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// flattenValues joins the values for each key with commas, so repeated keys bind to `csv` fields.
+func flattenValues(values url.Values) map[string]string {
+	output := make(map[string]string, len(values))
+	for key, value := range values {
+		output[key] = strings.Join(value, ",")
+	}
+	return output
+}
@@ -0,0 +1,182 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+type bindTestAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type bindTestInput struct {
+	Name     string           `json:"name" form:"name" validate:"required,min=2,max=8"`
+	Email    string           `json:"email" form:"email" validate:"format=email"`
+	Age      int              `json:"age" form:"age" validate:"min=18"`
+	Page     int              `query:"page" validate:"max=100"`
+	Tags     []string         `json:"tags" validate:"max=2"`
+	Nickname *string          `json:"nickname" validate:"min=3"`
+	Address  *bindTestAddress `json:"address"`
+}
+
+func TestCtxBindJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, err := NewMockRequestBuilder(nil).
+		WithVerb("POST").
+		WithQueryString("page", "3").
+		WithHeader(HeaderContentType, ContentTypeApplicationJSON).
+		WithPostBodyAsJSON(map[string]interface{}{"name": "bailey", "email": "bailey@example.com", "age": 30, "tags": []string{"a"}}).
+		CreateCtx(nil)
+	assert.Nil(err)
+
+	var input bindTestInput
+	assert.Nil(ctx.Bind(&input))
+	assert.Equal("bailey", input.Name)
+	assert.Equal("bailey@example.com", input.Email)
+	assert.Equal(30, input.Age)
+	assert.Equal(3, input.Page)
+	assert.Equal([]string{"a"}, input.Tags)
+	assert.Nil(input.Nickname)
+}
+
+func TestCtxBindForm(t *testing.T) {
+	assert := assert.New(t)
+
+	form := url.Values{"name": []string{"bailey"}, "age": []string{"21"}}
+	ctx, err := NewMockRequestBuilder(nil).
+		WithVerb("POST").
+		WithHeader(HeaderContentType, ContentTypeApplicationFormEncoded).
+		WithPostBody([]byte(form.Encode())).
+		CreateCtx(nil)
+	assert.Nil(err)
+	ctx.Request().Form = nil
+
+	var input bindTestInput
+	assert.Nil(ctx.Bind(&input))
+	assert.Equal("bailey", input.Name)
+	assert.Equal(21, input.Age)
+}
+
+func TestCtxBindValidationErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, err := NewMockRequestBuilder(nil).
+		WithVerb("POST").
+		WithQueryString("page", "500").
+		WithHeader(HeaderContentType, ContentTypeApplicationJSON).
+		WithPostBody([]byte(`{"email":"not an email","age":12,"tags":["a","b","c"],"nickname":"ab","address":{}}`)).
+		CreateCtx(nil)
+	assert.Nil(err)
+
+	var input bindTestInput
+	err = ctx.Bind(&input)
+	assert.True(IsBindError(err))
+
+	fields := err.(*BindError).Fields
+	assert.Len(fields, 7)
+	assert.Equal(FieldError{Field: "name", Rule: ValidationRuleRequired, Message: "is required"}, fields[0])
+	assert.Equal(FieldError{Field: "email", Rule: ValidationRuleFormat, Message: "must be a valid email address"}, fields[1])
+	assert.Equal(FieldError{Field: "age", Rule: ValidationRuleMin, Message: "must be at least 18"}, fields[2])
+	assert.Equal(FieldError{Field: "page", Rule: ValidationRuleMax, Message: "must be at most 100"}, fields[3])
+	assert.Equal(FieldError{Field: "tags", Rule: ValidationRuleMax, Message: "must have at most 2 items"}, fields[4])
+	assert.Equal(FieldError{Field: "nickname", Rule: ValidationRuleMin, Message: "must have at least 3 characters"}, fields[5])
+	assert.Equal(FieldError{Field: "address.city", Rule: ValidationRuleRequired, Message: "is required"}, fields[6])
+}
+
+func TestCtxBindInvalidBody(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, err := NewMockRequestBuilder(nil).
+		WithVerb("POST").
+		WithHeader(HeaderContentType, ContentTypeApplicationJSON).
+		WithPostBody([]byte(`{"name":1}`)).
+		CreateCtx(nil)
+	assert.Nil(err)
+
+	var input bindTestInput
+	err = ctx.Bind(&input)
+	assert.True(IsBindError(err))
+	assert.Equal([]FieldError{{Field: "name", Rule: ValidationRuleType, Message: "must be a string"}}, err.(*BindError).Fields)
+
+	ctx, err = NewMockRequestBuilder(nil).
+		WithVerb("POST").
+		WithHeader(HeaderContentType, ContentTypeApplicationJSON).
+		WithPostBody([]byte(`{"name":`)).
+		CreateCtx(nil)
+	assert.Nil(err)
+	err = ctx.Bind(&input)
+	assert.True(IsBindError(err))
+	assert.Empty(err.(*BindError).Fields)
+}
+
+func TestCtxBindTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, err := NewMockRequestBuilder(nil).CreateCtx(nil)
+	assert.Nil(err)
+
+	var input bindTestInput
+	assert.True(exception.Is(ctx.Bind(input), ErrBindTarget))
+	assert.True(exception.Is(ctx.Bind(new(string)), ErrBindTarget))
+}
+
+func TestValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	type formats struct {
+		Website string `validate:"format=url"`
+		ID      string `validate:"format=uuid"`
+	}
+	assert.Nil(Validate(formats{Website: "https://example.com/foo", ID: "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"}))
+	err := Validate(formats{Website: "/foo", ID: "nope"})
+	assert.True(IsBindError(err))
+	assert.Len(err.(*BindError).Fields, 2)
+
+	type unknownRule struct {
+		Name string `validate:"shiny"`
+	}
+	assert.True(exception.Is(Validate(unknownRule{Name: "foo"}), ErrValidationRule))
+
+	type unknownFormat struct {
+		Name string `validate:"format=shiny"`
+	}
+	assert.True(exception.Is(Validate(unknownFormat{Name: "foo"}), ErrValidationRule))
+}
+
+func TestCtxBindBadRequestResult(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	app.POST("/", func(r *Ctx) Result {
+		var input bindTestInput
+		if err := r.Bind(&input); err != nil {
+			return r.JSON().BadRequest(err)
+		}
+		return r.JSON().OK()
+	})
+
+	res, err := app.Mock().WithVerb("POST").WithPathf("/").
+		WithHeader(HeaderContentType, ContentTypeApplicationJSON).
+		WithPostBody([]byte(`{"name":"bailey","email":"nope"}`)).
+		Response()
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusBadRequest, res.StatusCode)
+
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	var body BindError
+	assert.Nil(json.Unmarshal(contents, &body))
+	assert.Equal("invalid request", body.Message)
+	assert.Equal([]FieldError{
+		{Field: "email", Rule: ValidationRuleFormat, Message: "must be a valid email address"},
+		{Field: "age", Rule: ValidationRuleMin, Message: "must be at least 18"},
+	}, body.Fields)
+}
@@ -0,0 +1,218 @@
+package web
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/logger"
+)
+
+const (
+	// DefaultCertReloaderPollInterval is the default interval cert files are checked for changes on.
+	DefaultCertReloaderPollInterval = 10 * time.Second
+)
+
+// NewCertReloader returns a new cert reloader for a key pair on disk, loading it immediately.
+func NewCertReloader(certPath, keyPath string) (*CertReloader, error) {
+	cr := &CertReloader{
+		certPath:     certPath,
+		keyPath:      keyPath,
+		pollInterval: DefaultCertReloaderPollInterval,
+		latch:        async.NewLatch(),
+	}
+	if err := cr.Reload(); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// CertReloader serves a tls key pair from disk, reloading it when the files change
+// or the process receives `SIGHUP`, so certs can be rotated without a restart.
+/*
+Use its tls config for the app, and start it to watch for changes:
+
+	reloader, err := web.NewCertReloader("/etc/tls/tls.crt", "/etc/tls/tls.key")
+	if err != nil {
+		return err
+	}
+	reloader.Start()
+	defer reloader.Stop()
+	app.WithTLSConfig(reloader.TLSConfig())
+
+If a reload fails, e.g. the files are mid-write or don't match, the current key pair is kept
+and the reload is retried on the next change or poll.
+*/
+type CertReloader struct {
+	certPath     string
+	keyPath      string
+	pollInterval time.Duration
+	log          *logger.Logger
+	latch        *async.Latch
+
+	certLock    sync.RWMutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+// CertPath returns the path of the cert file.
+func (cr *CertReloader) CertPath() string {
+	return cr.certPath
+}
+
+// KeyPath returns the path of the key file.
+func (cr *CertReloader) KeyPath() string {
+	return cr.keyPath
+}
+
+// WithPollInterval sets the interval the files are checked for changes on; zero disables polling,
+// leaving reloads to `SIGHUP`. It must be set before `Start` is called.
+func (cr *CertReloader) WithPollInterval(interval time.Duration) *CertReloader {
+	cr.pollInterval = interval
+	return cr
+}
+
+// PollInterval returns the interval the files are checked for changes on.
+func (cr *CertReloader) PollInterval() time.Duration {
+	return cr.pollInterval
+}
+
+// WithLogger sets the logger reloads and reload errors are logged to.
+func (cr *CertReloader) WithLogger(log *logger.Logger) *CertReloader {
+	cr.log = log
+	return cr
+}
+
+// Logger returns the logger.
+func (cr *CertReloader) Logger() *logger.Logger {
+	return cr.log
+}
+
+// Certificate returns the current key pair.
+func (cr *CertReloader) Certificate() *tls.Certificate {
+	cr.certLock.RLock()
+	defer cr.certLock.RUnlock()
+	return cr.cert
+}
+
+// GetCertificate returns the current key pair; it can be used as `tls.Config.GetCertificate`.
+func (cr *CertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return cr.Certificate(), nil
+}
+
+// TLSConfig returns a tls config that serves the current key pair.
+func (cr *CertReloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		GetCertificate: cr.GetCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+}
+
+// Reload loads the key pair from disk, keeping the current key pair if it fails.
+func (cr *CertReloader) Reload() error {
+	certInfo, err := os.Stat(cr.certPath)
+	if err != nil {
+		return exception.New(err)
+	}
+	keyInfo, err := os.Stat(cr.keyPath)
+	if err != nil {
+		return exception.New(err)
+	}
+	cert, err := tls.LoadX509KeyPair(cr.certPath, cr.keyPath)
+	if err != nil {
+		return exception.New(err).WithMessagef("cert: %s, key: %s", cr.certPath, cr.keyPath)
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return exception.New(err)
+	}
+
+	cr.certLock.Lock()
+	cr.cert = &cert
+	cr.certModTime = certInfo.ModTime()
+	cr.keyModTime = keyInfo.ModTime()
+	cr.certLock.Unlock()
+	return nil
+}
+
+// Start starts watching for changes in the background.
+func (cr *CertReloader) Start() {
+	if !cr.latch.CanStart() {
+		return
+	}
+	cr.latch.Starting()
+
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hangups)
+		var poll <-chan time.Time
+		if cr.pollInterval > 0 {
+			ticker := time.NewTicker(cr.pollInterval)
+			defer ticker.Stop()
+			poll = ticker.C
+		}
+		cr.latch.Started()
+		for {
+			select {
+			case <-hangups:
+				cr.reload("sighup")
+			case <-poll:
+				if cr.hasChanged() {
+					cr.reload("file change")
+				}
+			case <-cr.latch.NotifyStopping():
+				cr.latch.Stopped()
+				return
+			}
+		}
+	}()
+	<-cr.latch.NotifyStarted()
+}
+
+// Stop stops watching for changes.
+func (cr *CertReloader) Stop() {
+	if !cr.latch.CanStop() {
+		return
+	}
+	cr.latch.Stopping()
+	<-cr.latch.NotifyStopped()
+}
+
+// IsRunning returns if the reloader is watching for changes.
+func (cr *CertReloader) IsRunning() bool {
+	return cr.latch.IsRunning()
+}
+
+// hasChanged returns if either file's modification time differs from when it was last loaded.
+func (cr *CertReloader) hasChanged() bool {
+	certInfo, err := os.Stat(cr.certPath)
+	if err != nil {
+		return false
+	}
+	keyInfo, err := os.Stat(cr.keyPath)
+	if err != nil {
+		return false
+	}
+	cr.certLock.RLock()
+	defer cr.certLock.RUnlock()
+	return !certInfo.ModTime().Equal(cr.certModTime) || !keyInfo.ModTime().Equal(cr.keyModTime)
+}
+
+func (cr *CertReloader) reload(reason string) {
+	if err := cr.Reload(); err != nil {
+		if cr.log != nil {
+			cr.log.Error(err)
+		}
+		return
+	}
+	if cr.log != nil {
+		cr.log.Infof("tls cert reloaded (%s), expires %v", reason, cr.Certificate().Leaf.NotAfter)
+	}
+}
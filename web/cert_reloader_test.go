@@ -0,0 +1,116 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCertReloaderReload(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cert_reloader")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	certPath, keyPath := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	_, err = NewCertReloader(certPath, keyPath)
+	assert.NotNil(err)
+
+	writeTestKeyPair(t, certPath, keyPath, 1)
+	reloader, err := NewCertReloader(certPath, keyPath)
+	assert.Nil(err)
+	cert, err := reloader.GetCertificate(nil)
+	assert.Nil(err)
+	assert.Equal(int64(1), cert.Leaf.SerialNumber.Int64())
+
+	writeTestKeyPair(t, certPath, keyPath, 2)
+	assert.Nil(reloader.Reload())
+	assert.Equal(int64(2), reloader.Certificate().Leaf.SerialNumber.Int64())
+
+	// a key that doesn't match the cert is rejected, keeping the current key pair.
+	assert.Nil(ioutil.WriteFile(keyPath, testKeyPEM(t), 0600))
+	assert.NotNil(reloader.Reload())
+	assert.Equal(int64(2), reloader.Certificate().Leaf.SerialNumber.Int64())
+}
+
+func TestCertReloaderPoll(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "cert_reloader")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	certPath, keyPath := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	writeTestKeyPair(t, certPath, keyPath, 1)
+	reloader, err := NewCertReloader(certPath, keyPath)
+	assert.Nil(err)
+	reloader.WithPollInterval(5 * time.Millisecond).Start()
+	defer reloader.Stop()
+	assert.True(reloader.IsRunning())
+
+	writeTestKeyPair(t, certPath, keyPath, 2)
+	// make sure the modification time changes on filesystems with coarse timestamps.
+	future := time.Now().Add(time.Minute)
+	assert.Nil(os.Chtimes(certPath, future, future))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reloader.Certificate().Leaf.SerialNumber.Int64() != 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.Equal(int64(2), reloader.Certificate().Leaf.SerialNumber.Int64())
+
+	reloader.Stop()
+	assert.False(reloader.IsRunning())
+}
+
+func writeTestKeyPair(t *testing.T, certPath, keyPath string, serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testKeyPEM(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
@@ -1,8 +1,12 @@
 package web
 
 import (
+	"bufio"
 	"compress/gzip"
+	"net"
 	"net/http"
+
+	"github.com/blend/go-sdk/exception"
 )
 
 // NewCompressedResponseWriter returns a new gzipped response writer.
@@ -50,6 +54,21 @@ func (crw *CompressedResponseWriter) InnerResponse() http.ResponseWriter {
 	return crw.innerResponse
 }
 
+// Hijack takes over the underlying connection, e.g. to upgrade it to a websocket.
+// The response is recorded with the switching protocols status code.
+func (crw *CompressedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := crw.innerResponse.(http.Hijacker)
+	if !ok {
+		return nil, nil, exception.New(ErrWebSocketHandshake).WithMessage("response does not support hijacking")
+	}
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	crw.statusCode = http.StatusSwitchingProtocols
+	return conn, buffer, nil
+}
+
 // StatusCode returns the status code for the request.
 func (crw *CompressedResponseWriter) StatusCode() int {
 	return crw.statusCode
@@ -0,0 +1,323 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ContentEncodingBrotli is the brotli (compressed) content encoding.
+	// It is only served for pre-compressed static files, as there is no brotli encoder in the standard library.
+	ContentEncodingBrotli = "br"
+
+	// DefaultCompressionMinSize is the default minimum response size, in bytes, that will be compressed.
+	DefaultCompressionMinSize = 1024
+)
+
+// DefaultCompressionContentTypes are the default content types that are compressed.
+// Entries ending in `/` match any subtype, e.g. `text/` matches `text/html`.
+var DefaultCompressionContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+	"application/xml",
+	"application/xhtml+xml",
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/wasm",
+	"image/svg+xml",
+}
+
+// NewCompressor returns a new gzip compressor with the default content types and minimum size.
+func NewCompressor() *Compressor {
+	return &Compressor{
+		contentTypes: DefaultCompressionContentTypes,
+		minSize:      DefaultCompressionMinSize,
+		level:        gzip.DefaultCompression,
+	}
+}
+
+// Compress is a middleware that compresses responses with the default compressor.
+func Compress(action Action) Action {
+	return NewCompressor().Middleware(action)
+}
+
+// Compressor gzips responses if the client accepts it, the content type is in an allow-list,
+// and the response is at least a minimum size.
+// Responses that already have a content encoding, e.g. pre-compressed static files, are not compressed again.
+type Compressor struct {
+	contentTypes []string
+	minSize      int
+	level        int
+}
+
+// WithContentTypes sets the content types that are compressed; entries ending in `/` match any subtype.
+func (c *Compressor) WithContentTypes(contentTypes ...string) *Compressor {
+	c.contentTypes = contentTypes
+	return c
+}
+
+// ContentTypes returns the content types that are compressed.
+func (c *Compressor) ContentTypes() []string {
+	return c.contentTypes
+}
+
+// WithMinSize sets the minimum response size, in bytes, that will be compressed.
+func (c *Compressor) WithMinSize(minSize int) *Compressor {
+	c.minSize = minSize
+	return c
+}
+
+// MinSize returns the minimum response size, in bytes, that will be compressed.
+func (c *Compressor) MinSize() int {
+	return c.minSize
+}
+
+// WithLevel sets the gzip compression level.
+func (c *Compressor) WithLevel(level int) *Compressor {
+	c.level = level
+	return c
+}
+
+// Level returns the gzip compression level.
+func (c *Compressor) Level() int {
+	return c.level
+}
+
+// Middleware returns the action wrapped so its response is compressed.
+// It is a no-op if the response is already being compressed, e.g. by the app's compressor.
+func (c *Compressor) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		if _, isCompressed := ctx.Response().(*CompressorResponseWriter); isCompressed {
+			return action(ctx)
+		}
+		if !AcceptsEncoding(ctx.Request(), ContentEncodingGZIP) {
+			return action(ctx)
+		}
+		ctx.WithResponse(c.NewResponseWriter(ctx.Response()))
+		return action(ctx)
+	}
+}
+
+// IsCompressible returns if a content type is in the allow-list.
+func (c *Compressor) IsCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range c.contentTypes {
+		if strings.HasSuffix(allowed, "/") {
+			if strings.HasPrefix(mediaType, allowed) {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// NewResponseWriter returns a response writer that compresses output written to a given writer.
+func (c *Compressor) NewResponseWriter(w http.ResponseWriter) *CompressorResponseWriter {
+	return &CompressorResponseWriter{
+		compressor:    c,
+		innerResponse: w,
+	}
+}
+
+// AcceptsEncoding returns if a request accepts a content encoding, i.e. it is listed in
+// the `Accept-Encoding` header, or `*` is, with a non-zero quality.
+func AcceptsEncoding(r *http.Request, encoding string) bool {
+	for _, value := range r.Header[HeaderAcceptEncoding] {
+		for _, part := range strings.Split(value, ",") {
+			name, quality := part, ""
+			if semicolon := strings.Index(part, ";"); semicolon >= 0 {
+				name, quality = part[:semicolon], strings.TrimSpace(part[semicolon+1:])
+			}
+			name = strings.TrimSpace(name)
+			if !strings.EqualFold(name, encoding) && name != "*" {
+				continue
+			}
+			if strings.HasPrefix(quality, "q=") {
+				if parsed, err := strconv.ParseFloat(strings.TrimPrefix(quality, "q="), 64); err == nil && parsed == 0 {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// CompressorResponseWriter is a response writer that buffers output until it reaches the compressor's
+// minimum size, and then compresses it if the content type is compressible.
+type CompressorResponseWriter struct {
+	compressor    *Compressor
+	innerResponse http.ResponseWriter
+
+	buffer     bytes.Buffer
+	gzipWriter *gzip.Writer
+	decided    bool
+
+	statusCode    int
+	contentLength int
+}
+
+// Header returns the headers for the response.
+func (crw *CompressorResponseWriter) Header() http.Header {
+	return crw.innerResponse.Header()
+}
+
+// WriteHeader records the status code; it is written once it is known if the response will be compressed.
+func (crw *CompressorResponseWriter) WriteHeader(code int) {
+	if crw.statusCode != 0 {
+		return
+	}
+	crw.statusCode = code
+	if !bodyAllowedForStatus(code) {
+		crw.decide()
+	}
+}
+
+// Write writes the bytes to the response, buffering them until the compressor's minimum size is reached.
+func (crw *CompressorResponseWriter) Write(b []byte) (int, error) {
+	crw.contentLength += len(b)
+	if !crw.decided {
+		crw.buffer.Write(b)
+		if crw.buffer.Len() < crw.compressor.minSize {
+			return len(b), nil
+		}
+		return len(b), crw.decide()
+	}
+	if crw.gzipWriter != nil {
+		_, err := crw.gzipWriter.Write(b)
+		return len(b), err
+	}
+	return crw.innerResponse.Write(b)
+}
+
+// InnerResponse returns the backing http response.
+func (crw *CompressorResponseWriter) InnerResponse() http.ResponseWriter {
+	return crw.innerResponse
+}
+
+// StatusCode returns the status code for the request.
+func (crw *CompressorResponseWriter) StatusCode() int {
+	return crw.statusCode
+}
+
+// ContentLength returns the uncompressed content length for the request.
+func (crw *CompressorResponseWriter) ContentLength() int {
+	return crw.contentLength
+}
+
+// IsCompressed returns if the response is being compressed.
+func (crw *CompressorResponseWriter) IsCompressed() bool {
+	return crw.gzipWriter != nil
+}
+
+// Flush writes any buffered output, deciding if the response is compressed if it has not been already.
+func (crw *CompressorResponseWriter) Flush() error {
+	if err := crw.decide(); err != nil {
+		return err
+	}
+	if crw.gzipWriter != nil {
+		if err := crw.gzipWriter.Flush(); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := crw.innerResponse.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// Hijack takes over the underlying connection, e.g. to upgrade it to a websocket.
+func (crw *CompressorResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := crw.innerResponse.(http.Hijacker)
+	if !ok {
+		return nil, nil, exception.New(ErrWebSocketHandshake).WithMessage("response does not support hijacking")
+	}
+	crw.decided = true
+	crw.statusCode = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// Close writes any buffered output and closes the compressed stream.
+func (crw *CompressorResponseWriter) Close() error {
+	if err := crw.decide(); err != nil {
+		return err
+	}
+	if crw.gzipWriter != nil {
+		err := crw.gzipWriter.Close()
+		crw.gzipWriter = nil
+		return err
+	}
+	return nil
+}
+
+// decide writes the headers and buffered output, compressing it if the response qualifies.
+func (crw *CompressorResponseWriter) decide() error {
+	if crw.decided {
+		return nil
+	}
+	crw.decided = true
+
+	header := crw.innerResponse.Header()
+	if crw.statusCode == 0 {
+		crw.statusCode = http.StatusOK
+	}
+	if len(header.Get(HeaderContentType)) == 0 && crw.buffer.Len() > 0 {
+		header.Set(HeaderContentType, http.DetectContentType(crw.buffer.Bytes()))
+	}
+
+	encoding := header.Get(HeaderContentEncoding)
+	if (len(encoding) == 0 || encoding == ContentEncodingIdentity) &&
+		bodyAllowedForStatus(crw.statusCode) &&
+		crw.buffer.Len() >= crw.compressor.minSize &&
+		crw.compressor.IsCompressible(header.Get(HeaderContentType)) {
+		header.Set(HeaderContentEncoding, ContentEncodingGZIP)
+		header.Add(HeaderVary, HeaderAcceptEncoding)
+		header.Del(HeaderContentLength)
+		gzipWriter, err := gzip.NewWriterLevel(crw.innerResponse, crw.compressor.level)
+		if err != nil {
+			return exception.New(err)
+		}
+		crw.gzipWriter = gzipWriter
+	} else if len(encoding) == 0 {
+		header.Set(HeaderContentEncoding, ContentEncodingIdentity)
+	}
+
+	crw.innerResponse.WriteHeader(crw.statusCode)
+	if crw.buffer.Len() == 0 {
+		return nil
+	}
+	var err error
+	if crw.gzipWriter != nil {
+		_, err = crw.gzipWriter.Write(crw.buffer.Bytes())
+	} else {
+		_, err = crw.innerResponse.Write(crw.buffer.Bytes())
+	}
+	crw.buffer.Reset()
+	return err
+}
+
+// bodyAllowedForStatus returns if a status code permits a response body.
+func bodyAllowedForStatus(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
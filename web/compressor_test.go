@@ -0,0 +1,142 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	assert := assert.New(t)
+
+	req := NewMockRequest("GET", "/")
+	assert.False(AcceptsEncoding(req, ContentEncodingGZIP))
+
+	req.Header.Set(HeaderAcceptEncoding, "deflate, gzip;q=1.0, br")
+	assert.True(AcceptsEncoding(req, ContentEncodingGZIP))
+	assert.True(AcceptsEncoding(req, ContentEncodingBrotli))
+
+	req.Header.Set(HeaderAcceptEncoding, "gzip;q=0, *")
+	assert.False(AcceptsEncoding(req, ContentEncodingGZIP))
+	assert.True(AcceptsEncoding(req, ContentEncodingBrotli))
+}
+
+func TestCompressorIsCompressible(t *testing.T) {
+	assert := assert.New(t)
+
+	compressor := NewCompressor()
+	assert.True(compressor.IsCompressible(ContentTypeHTML))
+	assert.True(compressor.IsCompressible(ContentTypeApplicationJSON))
+	assert.True(compressor.IsCompressible("image/svg+xml"))
+	assert.False(compressor.IsCompressible("image/png"))
+	assert.False(compressor.IsCompressible(""))
+
+	compressor.WithContentTypes("application/")
+	assert.True(compressor.IsCompressible("application/octet-stream"))
+	assert.False(compressor.IsCompressible(ContentTypeHTML))
+}
+
+func TestAppCompression(t *testing.T) {
+	assert := assert.New(t)
+
+	large := strings.Repeat("compress me ", 200)
+	app := New()
+	app.GET("/large", func(r *Ctx) Result {
+		return r.Text().Result(large)
+	})
+	app.GET("/small", func(r *Ctx) Result {
+		return r.Text().Result("small")
+	})
+	app.GET("/image", func(r *Ctx) Result {
+		return r.RawWithContentType("image/png", []byte(large))
+	})
+
+	res, err := app.Mock().Get("/large").WithHeader(HeaderAcceptEncoding, "gzip").Response()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(ContentEncodingGZIP, res.Header.Get(HeaderContentEncoding))
+	assert.Equal(HeaderAcceptEncoding, res.Header.Get(HeaderVary))
+	gzipReader, err := gzip.NewReader(res.Body)
+	assert.Nil(err)
+	contents, err := ioutil.ReadAll(gzipReader)
+	assert.Nil(err)
+	assert.Equal(large, string(contents))
+
+	res, err = app.Mock().Get("/small").WithHeader(HeaderAcceptEncoding, "gzip").Response()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingIdentity, res.Header.Get(HeaderContentEncoding))
+	contents, err = ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal("small", string(contents))
+
+	res, err = app.Mock().Get("/image").WithHeader(HeaderAcceptEncoding, "gzip").Response()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingIdentity, res.Header.Get(HeaderContentEncoding))
+
+	res, err = app.Mock().Get("/large").Response()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingIdentity, res.Header.Get(HeaderContentEncoding))
+	contents, err = ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal(large, string(contents))
+}
+
+func TestCompressorMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New().WithCompressor(nil)
+	app.GET("/compressed", func(r *Ctx) Result {
+		return r.Text().Result("compressed")
+	}, NewCompressor().WithMinSize(0).Middleware)
+	app.GET("/uncompressed", func(r *Ctx) Result {
+		return r.Text().Result("uncompressed")
+	})
+
+	res, err := app.Mock().Get("/compressed").WithHeader(HeaderAcceptEncoding, "gzip").Response()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingGZIP, res.Header.Get(HeaderContentEncoding))
+	gzipReader, err := gzip.NewReader(res.Body)
+	assert.Nil(err)
+	contents, err := ioutil.ReadAll(gzipReader)
+	assert.Nil(err)
+	assert.Equal("compressed", string(contents))
+
+	res, err = app.Mock().Get("/uncompressed").WithHeader(HeaderAcceptEncoding, "gzip").Response()
+	assert.Nil(err)
+	assert.Equal(ContentEncodingIdentity, res.Header.Get(HeaderContentEncoding))
+}
+
+func TestStaticFileserverPrecompressed(t *testing.T) {
+	assert := assert.New(t)
+
+	original, err := ioutil.ReadFile("testdata/test_file.html")
+	assert.Nil(err)
+
+	app := New()
+	app.ServeStatic("/static", "testdata")
+	assert.Nil(app.SetStaticPrecompressed("/static", true))
+
+	res, err := app.Mock().Get("/static/test_file.html").WithHeader(HeaderAcceptEncoding, "br, gzip").Response()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(ContentEncodingGZIP, res.Header.Get(HeaderContentEncoding))
+	assert.True(strings.HasPrefix(res.Header.Get(HeaderContentType), "text/html"))
+	body, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	gzipReader, err := gzip.NewReader(bytes.NewReader(body))
+	assert.Nil(err)
+	contents, err := ioutil.ReadAll(gzipReader)
+	assert.Nil(err)
+	assert.Equal(original, contents)
+
+	res, err = app.Mock().Get("/static/test_file.html").Response()
+	assert.Nil(err)
+	body, err = ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+	assert.Equal(original, body)
+}
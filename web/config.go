@@ -51,6 +51,12 @@ type Config struct {
 	CookieName string `json:"cookieName,omitempty" yaml:"cookieName,omitempty" env:"COOKIE_NAME"`
 	// CookiePath is the path on the cookie to issue with sessions.
 	CookiePath string `json:"cookiePath,omitempty" yaml:"cookiePath,omitempty" env:"COOKIE_PATH"`
+	// CookieSameSite is the same site policy of the cookie to issue with sessions, one of `lax`, `strict` or `none`.
+	CookieSameSite string `json:"cookieSameSite,omitempty" yaml:"cookieSameSite,omitempty" env:"COOKIE_SAME_SITE"`
+	// SessionRotationInterval is how often session ids are rotated; zero disables rotation.
+	SessionRotationInterval time.Duration `json:"sessionRotationInterval,omitempty" yaml:"sessionRotationInterval,omitempty" env:"SESSION_ROTATION_INTERVAL"`
+	// SessionRotationGracePeriod is how long a session id is still valid for after it's rotated.
+	SessionRotationGracePeriod time.Duration `json:"sessionRotationGracePeriod,omitempty" yaml:"sessionRotationGracePeriod,omitempty" env:"SESSION_ROTATION_GRACE_PERIOD"`
 
 	// DefaultHeaders are included on any responses. The app ships with a set of default headers, which you can augment with this property.
 	DefaultHeaders map[string]string `json:"defaultHeaders,omitempty" yaml:"defaultHeaders,omitempty"`
@@ -61,8 +67,20 @@ type Config struct {
 	WriteTimeout      time.Duration `json:"writeTimeout,omitempty" yaml:"writeTimeout,omitempty" env:"WRITE_TIMEOUT"`
 	IdleTimeout       time.Duration `json:"idleTimeout,omitempty" yaml:"idleTimeout,omitempty" env:"IDLE_TIMEOUT"`
 
+	// TCPKeepAlivePeriod is the keep alive period for accepted connections; a negative period disables keep alives.
+	TCPKeepAlivePeriod time.Duration `json:"tcpKeepAlivePeriod,omitempty" yaml:"tcpKeepAlivePeriod,omitempty" env:"TCP_KEEP_ALIVE_PERIOD"`
+	// HTTP2 determines if the server negotiates http/2 with tls clients.
+	HTTP2 *bool `json:"http2,omitempty" yaml:"http2,omitempty" env:"HTTP2"`
+	// H2C determines if the server accepts http/2 without tls ("h2c"), e.g. from a proxy that terminates tls.
+	H2C *bool `json:"h2c,omitempty" yaml:"h2c,omitempty" env:"H2C"`
+	// HTTP2MaxConcurrentStreams is the number of concurrent streams each http/2 client can open.
+	HTTP2MaxConcurrentStreams int `json:"http2MaxConcurrentStreams,omitempty" yaml:"http2MaxConcurrentStreams,omitempty" env:"HTTP2_MAX_CONCURRENT_STREAMS"`
+
 	ShutdownGracePeriod time.Duration `json:"shutdownGracePeriod" yaml:"shutdownGracePeriod" env:"SHUTDOWN_GRACE_PERIOD"`
 
+	// LogHeaders is an allow-list of request headers included in request and response log events.
+	LogHeaders []string `json:"logHeaders,omitempty" yaml:"logHeaders,omitempty" env:"LOG_HEADERS,csv"`
+
 	TLS   TLSConfig       `json:"tls,omitempty" yaml:"tls,omitempty"`
 	Views ViewCacheConfig `json:"views,omitempty" yaml:"views,omitempty"`
 }
@@ -217,6 +235,21 @@ func (c Config) GetCookiePath(defaults ...string) string {
 	return util.Coalesce.String(c.CookiePath, DefaultCookiePath, defaults...)
 }
 
+// GetCookieSameSite returns a property or a default.
+func (c Config) GetCookieSameSite(defaults ...string) string {
+	return util.Coalesce.String(c.CookieSameSite, DefaultCookieSameSite, defaults...)
+}
+
+// GetSessionRotationInterval returns a property or a default.
+func (c Config) GetSessionRotationInterval(defaults ...time.Duration) time.Duration {
+	return util.Coalesce.Duration(c.SessionRotationInterval, 0, defaults...)
+}
+
+// GetSessionRotationGracePeriod returns a property or a default.
+func (c Config) GetSessionRotationGracePeriod(defaults ...time.Duration) time.Duration {
+	return util.Coalesce.Duration(c.SessionRotationGracePeriod, DefaultSessionRotationGracePeriod, defaults...)
+}
+
 // GetMaxHeaderBytes returns the maximum header size in bytes or a default.
 func (c Config) GetMaxHeaderBytes(defaults ...int) int {
 	return util.Coalesce.Int(c.MaxHeaderBytes, DefaultMaxHeaderBytes, defaults...)
@@ -242,7 +275,38 @@ func (c Config) GetIdleTimeout(defaults ...time.Duration) time.Duration {
 	return util.Coalesce.Duration(c.IdleTimeout, DefaultIdleTimeout, defaults...)
 }
 
+// GetTCPKeepAlivePeriod gets a property.
+func (c Config) GetTCPKeepAlivePeriod(defaults ...time.Duration) time.Duration {
+	return util.Coalesce.Duration(c.TCPKeepAlivePeriod, DefaultTCPKeepAliveListenerPeriod, defaults...)
+}
+
+// GetHTTP2 returns if the server negotiates http/2 with tls clients.
+func (c Config) GetHTTP2(defaults ...bool) bool {
+	return util.Coalesce.Bool(c.HTTP2, DefaultHTTP2, defaults...)
+}
+
+// GetH2C returns if the server accepts http/2 without tls.
+func (c Config) GetH2C(defaults ...bool) bool {
+	return util.Coalesce.Bool(c.H2C, DefaultH2C, defaults...)
+}
+
+// GetHTTP2MaxConcurrentStreams gets a property.
+func (c Config) GetHTTP2MaxConcurrentStreams(defaults ...int) int {
+	return util.Coalesce.Int(c.HTTP2MaxConcurrentStreams, DefaultHTTP2MaxConcurrentStreams, defaults...)
+}
+
 // GetShutdownGracePeriod gets the shutdown grace period.
 func (c Config) GetShutdownGracePeriod(defaults ...time.Duration) time.Duration {
 	return util.Coalesce.Duration(c.ShutdownGracePeriod, DefaultShutdownGracePeriod, defaults...)
 }
+
+// GetLogHeaders gets the request headers included in log events.
+func (c Config) GetLogHeaders(defaults ...[]string) []string {
+	if len(c.LogHeaders) > 0 {
+		return c.LogHeaders
+	}
+	if len(defaults) > 0 {
+		return defaults[0]
+	}
+	return nil
+}
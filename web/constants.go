@@ -15,6 +15,10 @@ const (
 	// RegexpAssetCacheFiles is a common regex for parsing css, js, and html file routes.
 	RegexpAssetCacheFiles = `^(.*)\.([0-9]+)\.(css|js|html|htm)$`
 
+	// HeaderAccept is the "Accept" header.
+	// It indicates what media types the request will accept responses as, and is used for content negotiation.
+	HeaderAccept = "Accept"
+
 	// HeaderAcceptEncoding is the "Accept-Encoding" header.
 	// It indicates what types of encodings the request will accept responses as.
 	// It typically enables or disables compressed (gzipped) responses.
@@ -36,6 +40,10 @@ const (
 	// Typical values for this include "no-cache", "max-age", "min-fresh", and "max-stale" variants.
 	HeaderCacheControl = "Cache-Control"
 
+	// HeaderETag is the "ETag" header.
+	// It identifies a version of a resource, so clients can revalidate it with "If-None-Match".
+	HeaderETag = "ETag"
+
 	// HeaderConnection is the "Connection" header.
 	// It is used to indicate if the connection should remain open by the server
 	// after the final response bytes are sent.
@@ -102,6 +110,15 @@ const (
 	// We specify chartset=utf-8 so that clients know to use the UTF-8 string encoding.
 	ContentTypeText = "text/plain; charset=utf-8"
 
+	// ContentTypeApplicationXML is a content type for XML responses to clients that ask for "application/xml".
+	ContentTypeApplicationXML = "application/xml; charset=utf-8"
+
+	// ContentTypeApplicationMsgPack is a content type for MessagePack responses.
+	ContentTypeApplicationMsgPack = "application/msgpack"
+
+	// ContentTypeCSV is a content type for CSV responses.
+	ContentTypeCSV = "text/csv; charset=utf-8"
+
 	// ConnectionKeepAlive is a value for the "Connection" header and
 	// indicates the server should keep the tcp connection open
 	// after the last byte of the response is sent.
@@ -223,12 +240,23 @@ const (
 	DefaultWriteTimeout time.Duration = 0
 	// DefaultIdleTimeout is a default.
 	DefaultIdleTimeout time.Duration = 0
+	// DefaultHTTP2 is the default for if the server negotiates http/2 with tls clients.
+	DefaultHTTP2 = true
+	// DefaultH2C is the default for if the server accepts http/2 without tls.
+	DefaultH2C = false
+	// DefaultHTTP2MaxConcurrentStreams is a default that is unset, i.e. the http2 package's default of 250.
+	DefaultHTTP2MaxConcurrentStreams = 0
 	// DefaultCookieName is the default name of the field that contains the session id.
 	DefaultCookieName = "SID"
 	// DefaultSecureCookieName is the default name of the field that contains the secure session id.
 	DefaultSecureCookieName = "SSID"
 	// DefaultCookiePath is the default cookie path.
 	DefaultCookiePath = "/"
+	// DefaultCookieSameSite is the default cookie same site policy.
+	DefaultCookieSameSite = CookieSameSiteLax
+	// DefaultSessionRotationGracePeriod is the default time a session id is still valid for after it's rotated,
+	// so requests already in flight with it (e.g. from other tabs) aren't logged out.
+	DefaultSessionRotationGracePeriod time.Duration = 30 * time.Second
 	// DefaultSessionTimeout is the default absolute timeout for a session (24 hours as a sane default).
 	DefaultSessionTimeout time.Duration = 24 * time.Hour
 	// DefaultUseSessionCache is the default if we should use the auth manager session cache.
@@ -0,0 +1,289 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"net/http"
+)
+
+const (
+	// DefaultCSRFCookieName is the default cookie the csrf secret is stored in.
+	DefaultCSRFCookieName = "_csrf"
+	// DefaultCSRFHeaderName is the default header the csrf token is read from.
+	DefaultCSRFHeaderName = "X-CSRF-Token"
+	// DefaultCSRFFieldName is the default form field the csrf token is read from.
+	DefaultCSRFFieldName = "csrf_token"
+
+	// StateKeyCSRF is the state key for the csrf secret of the request.
+	StateKeyCSRF = "csrf"
+
+	// csrfSecretLength is the length of csrf secrets, and of the pad tokens are masked with.
+	csrfSecretLength = 32
+)
+
+// NewCSRF returns a new csrf protection middleware that uses double submit cookies.
+func NewCSRF() *CSRF {
+	return &CSRF{
+		cookieName:     DefaultCSRFCookieName,
+		cookiePath:     "/",
+		cookieSameSite: http.SameSiteLaxMode,
+		headerName:     DefaultCSRFHeaderName,
+		fieldName:      DefaultCSRFFieldName,
+	}
+}
+
+// CSRF is a middleware that rejects unsafe requests (i.e. not `GET`, `HEAD`, `OPTIONS` or `TRACE`)
+// that don't echo the request's csrf token in a header or form field.
+//
+// By default the secret tokens are checked against is a random value in a cookie (double submit cookies).
+// With a session key, requests that have a session (i.e. `SessionAware` runs before the middleware)
+// use a secret derived from the session id instead (synchronizer tokens), so tokens are invalidated on logout.
+//
+// Tokens are masked with a random pad each time they're rendered, so they don't leak the secret
+// through compressed responses. Render them with `CSRFToken(ctx)`, or in views with
+// `{{ .CSRFField }}` in forms and `{{ .CSRFToken }}` for scripts.
+type CSRF struct {
+	cookieName     string
+	cookiePath     string
+	cookieSecure   bool
+	cookieSameSite http.SameSite
+	headerName     string
+	fieldName      string
+	sessionKey     []byte
+	exemptRoutes   map[string]bool
+	exemptFunc     func(*Ctx) bool
+}
+
+// WithCookieName sets the cookie the secret is stored in.
+func (c *CSRF) WithCookieName(name string) *CSRF {
+	c.cookieName = name
+	return c
+}
+
+// CookieName returns the cookie the secret is stored in.
+func (c *CSRF) CookieName() string {
+	return c.cookieName
+}
+
+// WithCookiePath sets the path of the secret cookie.
+func (c *CSRF) WithCookiePath(path string) *CSRF {
+	c.cookiePath = path
+	return c
+}
+
+// CookiePath returns the path of the secret cookie.
+func (c *CSRF) CookiePath() string {
+	return c.cookiePath
+}
+
+// WithCookieHTTPSOnly sets if the secret cookie is only sent over https.
+func (c *CSRF) WithCookieHTTPSOnly(isHTTPSOnly bool) *CSRF {
+	c.cookieSecure = isHTTPSOnly
+	return c
+}
+
+// CookieHTTPSOnly returns if the secret cookie is only sent over https.
+func (c *CSRF) CookieHTTPSOnly() bool {
+	return c.cookieSecure
+}
+
+// WithCookieSameSite sets the same site policy of the secret cookie.
+func (c *CSRF) WithCookieSameSite(sameSite http.SameSite) *CSRF {
+	c.cookieSameSite = sameSite
+	return c
+}
+
+// CookieSameSite returns the same site policy of the secret cookie.
+func (c *CSRF) CookieSameSite() http.SameSite {
+	return c.cookieSameSite
+}
+
+// WithHeaderName sets the header the token is read from.
+func (c *CSRF) WithHeaderName(name string) *CSRF {
+	c.headerName = name
+	return c
+}
+
+// HeaderName returns the header the token is read from.
+func (c *CSRF) HeaderName() string {
+	return c.headerName
+}
+
+// WithFieldName sets the form field the token is read from.
+func (c *CSRF) WithFieldName(name string) *CSRF {
+	c.fieldName = name
+	return c
+}
+
+// FieldName returns the form field the token is read from.
+func (c *CSRF) FieldName() string {
+	return c.fieldName
+}
+
+// WithSessionKey sets the key secrets are derived from session ids with, enabling synchronizer tokens.
+func (c *CSRF) WithSessionKey(key []byte) *CSRF {
+	c.sessionKey = key
+	return c
+}
+
+// SessionKey returns the key secrets are derived from session ids with.
+func (c *CSRF) SessionKey() []byte {
+	return c.sessionKey
+}
+
+// WithExemptRoutes sets the routes, as registered (e.g. `/webhooks/:id`), that are not checked.
+func (c *CSRF) WithExemptRoutes(routes ...string) *CSRF {
+	c.exemptRoutes = map[string]bool{}
+	for _, route := range routes {
+		c.exemptRoutes[route] = true
+	}
+	return c
+}
+
+// ExemptRoutes returns the routes that are not checked.
+func (c *CSRF) ExemptRoutes() []string {
+	var routes []string
+	for route := range c.exemptRoutes {
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// WithExemptFunc sets a function that returns if a request is not checked,
+// e.g. requests authenticated with a bearer token rather than cookies.
+func (c *CSRF) WithExemptFunc(exemptFunc func(*Ctx) bool) *CSRF {
+	c.exemptFunc = exemptFunc
+	return c
+}
+
+// ExemptFunc returns the function that returns if a request is not checked.
+func (c *CSRF) ExemptFunc() func(*Ctx) bool {
+	return c.exemptFunc
+}
+
+// Middleware returns the action wrapped so unsafe requests require a valid token.
+// Rejected requests get the default result provider's not authorized result.
+func (c *CSRF) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		secret, err := c.secret(ctx)
+		if err != nil {
+			return ctx.DefaultResultProvider().InternalError(err)
+		}
+		ctx.WithStateValue(StateKeyCSRF, &csrfState{secret: secret, fieldName: c.fieldName})
+
+		if isSafeMethod(ctx.Request().Method) || c.isExempt(ctx) {
+			return action(ctx)
+		}
+		if !csrfTokenMatches(secret, c.readToken(ctx)) {
+			return ctx.DefaultResultProvider().NotAuthorized()
+		}
+		return action(ctx)
+	}
+}
+
+// secret returns the secret for the request, issuing a new secret cookie if required.
+func (c *CSRF) secret(ctx *Ctx) ([]byte, error) {
+	if len(c.sessionKey) > 0 && ctx.Session() != nil {
+		mac := hmac.New(sha256.New, c.sessionKey)
+		mac.Write([]byte(ctx.Session().SessionID))
+		return mac.Sum(nil), nil
+	}
+
+	if cookie := ctx.GetCookie(c.cookieName); cookie != nil {
+		secret, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+		if err == nil && len(secret) == csrfSecretLength {
+			return secret, nil
+		}
+	}
+	secret := make([]byte, csrfSecretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	ctx.WriteCookie(&http.Cookie{
+		Name:     c.cookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(secret),
+		Path:     c.cookiePath,
+		HttpOnly: true,
+		Secure:   c.cookieSecure,
+		SameSite: c.cookieSameSite,
+		Domain:   ctx.getCookieDomain(),
+	})
+	return secret, nil
+}
+
+func (c *CSRF) isExempt(ctx *Ctx) bool {
+	if ctx.Route() != nil && c.exemptRoutes[ctx.Route().Path] {
+		return true
+	}
+	return c.exemptFunc != nil && c.exemptFunc(ctx)
+}
+
+func (c *CSRF) readToken(ctx *Ctx) string {
+	if token := ctx.Request().Header.Get(c.headerName); len(token) > 0 {
+		return token
+	}
+	return ctx.Request().PostFormValue(c.fieldName)
+}
+
+// CSRFToken returns a csrf token for the request, or an empty string if the `CSRF` middleware
+// did not run for the request. Each call returns a different token for the same secret.
+func CSRFToken(ctx *Ctx) string {
+	state, ok := ctx.StateValue(StateKeyCSRF).(*csrfState)
+	if !ok {
+		return ""
+	}
+	return maskCSRFSecret(state.secret)
+}
+
+// CSRFField returns a hidden form input with a csrf token for the request.
+func CSRFField(ctx *Ctx) template.HTML {
+	state, ok := ctx.StateValue(StateKeyCSRF).(*csrfState)
+	if !ok {
+		return ""
+	}
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(state.fieldName), maskCSRFSecret(state.secret)))
+}
+
+type csrfState struct {
+	secret    []byte
+	fieldName string
+}
+
+// maskCSRFSecret returns a random pad followed by the secret xor the pad, base64 encoded.
+func maskCSRFSecret(secret []byte) string {
+	token := make([]byte, 2*len(secret))
+	pad := token[:len(secret)]
+	if _, err := rand.Read(pad); err != nil {
+		return ""
+	}
+	for index := range secret {
+		token[len(secret)+index] = secret[index] ^ pad[index]
+	}
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func csrfTokenMatches(secret []byte, token string) bool {
+	contents, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(contents) != 2*len(secret) {
+		return false
+	}
+	unmasked := make([]byte, len(secret))
+	for index := range secret {
+		unmasked[index] = contents[index] ^ contents[len(secret)+index]
+	}
+	return subtle.ConstantTimeCompare(unmasked, secret) == 1
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
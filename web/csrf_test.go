@@ -0,0 +1,128 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func csrfTestApp(csrf *CSRF) *App {
+	app := New()
+	app.Use(csrf.Middleware)
+	app.GET("/form", func(r *Ctx) Result {
+		return r.Text().Result(string(CSRFField(r)))
+	})
+	app.POST("/form", func(r *Ctx) Result {
+		return r.Text().Result("ok!")
+	})
+	app.POST("/webhooks/:id", func(r *Ctx) Result {
+		return r.Text().Result("ok!")
+	})
+	return app
+}
+
+func TestCSRFDoubleSubmitCookie(t *testing.T) {
+	assert := assert.New(t)
+
+	app := csrfTestApp(NewCSRF().WithExemptRoutes("/webhooks/:id"))
+
+	res, err := app.Mock().WithPathf("/form").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	var cookie *http.Cookie
+	for _, c := range res.Cookies() {
+		if c.Name == DefaultCSRFCookieName {
+			cookie = c
+		}
+	}
+	assert.NotNil(cookie)
+	assert.True(cookie.HttpOnly)
+
+	contents, err := app.Mock().WithPathf("/form").WithCookie(cookie).Bytes()
+	assert.Nil(err)
+	matches := regexp.MustCompile(`name="csrf_token" value="([^"]+)"`).FindStringSubmatch(string(contents))
+	assert.Len(matches, 2)
+	token := matches[1]
+
+	_, meta, err := app.Mock().WithVerb("POST").WithPathf("/form").WithCookie(cookie).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+
+	_, meta, err = app.Mock().WithVerb("POST").WithPathf("/form").WithCookie(cookie).
+		WithHeader(DefaultCSRFHeaderName, token).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	req := httptest.NewRequest("POST", "/form", strings.NewReader(url.Values{DefaultCSRFFieldName: []string{token}}.Encode()))
+	req.Header.Set(HeaderContentType, ContentTypeApplicationFormEncoded)
+	req.AddCookie(cookie)
+	rw := httptest.NewRecorder()
+	app.ServeHTTP(rw, req)
+	assert.Equal(http.StatusOK, rw.Code)
+
+	// a token for one cookie is not valid for another.
+	_, meta, err = app.Mock().WithVerb("POST").WithPathf("/form").
+		WithHeader(DefaultCSRFHeaderName, token).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusForbidden, meta.StatusCode)
+
+	_, meta, err = app.Mock().WithVerb("POST").WithPathf("/webhooks/stripe").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+}
+
+func TestCSRFSessionTokens(t *testing.T) {
+	assert := assert.New(t)
+
+	csrf := NewCSRF().WithSessionKey([]byte("a super secret key"))
+	session := NewSession("bailey", "session-1")
+
+	ctx, err := NewMockRequestBuilder(nil).CreateCtx(nil)
+	assert.Nil(err)
+	ctx.WithSession(session)
+	var token string
+	csrf.Middleware(func(r *Ctx) Result {
+		token = CSRFToken(r)
+		return nil
+	})(ctx)
+	assert.NotEmpty(token)
+	assert.Empty(ctx.Response().Header().Get("Set-Cookie"))
+
+	check := func(session *Session) bool {
+		ctx, err := NewMockRequestBuilder(nil).WithVerb("POST").WithHeader(DefaultCSRFHeaderName, token).CreateCtx(nil)
+		assert.Nil(err)
+		ctx.WithSession(session)
+		var called bool
+		csrf.Middleware(func(r *Ctx) Result {
+			called = true
+			return nil
+		})(ctx)
+		return called
+	}
+	assert.True(check(session))
+	assert.False(check(NewSession("bailey", "session-2")))
+}
+
+func TestCSRFTokenMasking(t *testing.T) {
+	assert := assert.New(t)
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	first, second := maskCSRFSecret(secret), maskCSRFSecret(secret)
+	assert.NotEqual(first, second)
+	assert.True(csrfTokenMatches(secret, first))
+	assert.True(csrfTokenMatches(secret, second))
+	assert.False(csrfTokenMatches(secret, ""))
+	assert.False(csrfTokenMatches(secret, "not a token"))
+	assert.False(csrfTokenMatches([]byte("fedcba9876543210fedcba9876543210"), first))
+
+	ctx, err := NewMockRequestBuilder(nil).CreateCtx(nil)
+	assert.Nil(err)
+	assert.Empty(CSRFToken(ctx))
+	assert.Empty(CSRFField(ctx))
+}
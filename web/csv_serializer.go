@@ -0,0 +1,179 @@
+package web
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+var (
+	_ Serializer = (*CSVSerializer)(nil)
+)
+
+// CSVSerializer serializes objects as csv.
+/*
+Slices of structs are written with a header row of the field names, named by their `csv` tag or their
+`json` tag, and a row per element; a single struct is written as a header and one row. Slices of maps are
+written with a header of the sorted keys. `[][]string` is written as is, and anything else, e.g. an error
+from a result provider, is written as a single cell.
+*/
+type CSVSerializer struct{}
+
+// Serialize implements Serializer.
+func (CSVSerializer) Serialize(w io.Writer, object interface{}) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(csvRecords(object)); err != nil {
+		return exception.New(err)
+	}
+	return nil
+}
+
+func csvRecords(object interface{}) [][]string {
+	switch typed := object.(type) {
+	case [][]string:
+		return typed
+	case []string:
+		return [][]string{typed}
+	case error:
+		return [][]string{{typed.Error()}}
+	}
+
+	v := indirectValue(reflect.ValueOf(object))
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		if csvCell(v) != nil {
+			return [][]string{{*csvCell(v)}}
+		}
+		return csvRows(reflect.ValueOf([]interface{}{v.Interface()}))
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			return [][]string{{string(v.Bytes())}}
+		}
+		return csvRows(v)
+	}
+	return [][]string{{csvValue(v)}}
+}
+
+// csvRows returns the header and rows for a slice of structs or maps, or a row per element otherwise.
+func csvRows(v reflect.Value) [][]string {
+	var elements []reflect.Value
+	for i := 0; i < v.Len(); i++ {
+		elements = append(elements, indirectValue(v.Index(i)))
+	}
+	if len(elements) == 0 {
+		return nil
+	}
+
+	first := elements[0]
+	if first.IsValid() && first.Kind() == reflect.Struct && csvCell(first) == nil {
+		fields := serializedFields(first.Type(), "csv", "json")
+		records := [][]string{make([]string, len(fields))}
+		for index, field := range fields {
+			records[0][index] = field.Name
+		}
+		for _, element := range elements {
+			row := make([]string, len(fields))
+			if element.IsValid() && element.Type() == first.Type() {
+				for index, field := range fields {
+					if value, ok := fieldByIndex(element, field.Index); ok {
+						row[index] = csvValue(value)
+					}
+				}
+			}
+			records = append(records, row)
+		}
+		return records
+	}
+
+	if first.IsValid() && first.Kind() == reflect.Map {
+		columns := map[string]bool{}
+		for _, element := range elements {
+			if element.IsValid() && element.Kind() == reflect.Map {
+				for _, key := range element.MapKeys() {
+					columns[fmt.Sprint(key.Interface())] = true
+				}
+			}
+		}
+		header := make([]string, 0, len(columns))
+		for column := range columns {
+			header = append(header, column)
+		}
+		sort.Strings(header)
+		records := [][]string{header}
+		for _, element := range elements {
+			values := map[string]string{}
+			if element.IsValid() && element.Kind() == reflect.Map {
+				for _, key := range element.MapKeys() {
+					values[fmt.Sprint(key.Interface())] = csvValue(element.MapIndex(key))
+				}
+			}
+			row := make([]string, len(header))
+			for index, column := range header {
+				row[index] = values[column]
+			}
+			records = append(records, row)
+		}
+		return records
+	}
+
+	var records [][]string
+	for _, element := range elements {
+		if element.IsValid() && (element.Kind() == reflect.Slice || element.Kind() == reflect.Array) && csvCell(element) == nil {
+			row := make([]string, element.Len())
+			for index := range row {
+				row[index] = csvValue(element.Index(index))
+			}
+			records = append(records, row)
+			continue
+		}
+		records = append(records, []string{csvValue(element)})
+	}
+	return records
+}
+
+// csvCell returns the text of a value that marshals itself as text, e.g. a time.Time, or nil.
+func csvCell(v reflect.Value) *string {
+	if !v.IsValid() || !v.Type().Implements(textMarshalerType) {
+		return nil
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return nil
+	}
+	value := string(text)
+	return &value
+}
+
+// csvValue formats a value as a csv cell; nil values are empty.
+func csvValue(v reflect.Value) string {
+	v = indirectValue(v)
+	if !v.IsValid() {
+		return ""
+	}
+	if text := csvCell(v); text != nil {
+		return *text
+	}
+	if err, ok := v.Interface().(error); ok {
+		return err.Error()
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// indirectValue dereferences pointers and interfaces, returning an invalid value for nil.
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
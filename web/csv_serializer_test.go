@@ -0,0 +1,42 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCSVSerializer(t *testing.T) {
+	assert := assert.New(t)
+
+	serialize := func(object interface{}) string {
+		var buffer bytes.Buffer
+		assert.Nil(CSVSerializer{}.Serialize(&buffer, object))
+		return buffer.String()
+	}
+
+	type row struct {
+		ID      int        `csv:"id"`
+		Name    string     `json:"name"`
+		Created time.Time  `csv:"created"`
+		Deleted *time.Time `csv:"deleted"`
+		Skipped string     `csv:"-"`
+	}
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := []row{
+		{ID: 1, Name: "bailey, jr.", Created: created},
+		{ID: 2, Name: "riley", Created: created, Deleted: &created, Skipped: "skipped"},
+	}
+	assert.Equal("id,name,created,deleted\n1,\"bailey, jr.\",2020-01-02T03:04:05Z,\n2,riley,2020-01-02T03:04:05Z,2020-01-02T03:04:05Z\n", serialize(rows))
+	assert.Equal("id,name,created,deleted\n1,\"bailey, jr.\",2020-01-02T03:04:05Z,\n", serialize(&rows[0]))
+	assert.Equal("a,b\n1,\n,x\n", serialize([]map[string]interface{}{{"a": 1}, {"b": "x"}}))
+	assert.Equal("a,b\nc,d\n", serialize([][]string{{"a", "b"}, {"c", "d"}}))
+	assert.Equal("1,2\n3,4\n", serialize([][]int{{1, 2}, {3, 4}}))
+	assert.Equal("1\n2\n", serialize([]int{1, 2}))
+	assert.Equal("Bad Request\n", serialize("Bad Request"))
+	assert.Equal("oops\n", serialize(fmt.Errorf("oops")))
+	assert.Equal("", serialize(nil))
+}
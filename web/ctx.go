@@ -23,6 +23,7 @@ func NewCtx(w ResponseWriter, r *http.Request, p RouteParameters, s State) *Ctx
 		routeParameters:       p,
 		state:                 s,
 		defaultResultProvider: Text,
+		serializers:           NewSerializers(),
 	}
 	if ctx.state == nil {
 		ctx.state = State{}
@@ -44,6 +45,8 @@ type Ctx struct {
 
 	tracer Tracer
 
+	serializers *Serializers
+
 	postBody              []byte
 	defaultResultProvider ResultProvider
 
@@ -119,8 +122,12 @@ func (rc *Ctx) Auth() *AuthManager {
 }
 
 // WithSession sets the session for the request.
+// The session is also set on the request context, see `GetSessionFromContext`.
 func (rc *Ctx) WithSession(session *Session) *Ctx {
 	rc.session = session
+	if rc.request != nil {
+		rc.request = rc.request.WithContext(WithSessionContext(rc.request.Context(), session))
+	}
 	return rc
 }
 
@@ -181,6 +188,59 @@ func (rc *Ctx) Text() TextResultProvider {
 	return Text
 }
 
+// Negotiated returns the result provider that serializes results in the media type the request accepts.
+/*
+The available media types are the app's serializers; by default json, xml, MessagePack and csv.
+*/
+func (rc *Ctx) Negotiated() NegotiatedResultProvider {
+	return Negotiated
+}
+
+// Serializers returns the serializers used to negotiate the media type of results.
+func (rc *Ctx) Serializers() *Serializers {
+	if rc.serializers == nil {
+		rc.serializers = NewSerializers()
+	}
+	return rc.serializers
+}
+
+// WithSerializers sets the serializers used to negotiate the media type of results.
+func (rc *Ctx) WithSerializers(serializers *Serializers) *Ctx {
+	rc.serializers = serializers
+	return rc
+}
+
+// Serialized returns a result serialized in a given media type, e.g. `web.ContentTypeCSV`, regardless of
+// the request's "Accept" header.
+func (rc *Ctx) Serialized(mediaType string, response interface{}) *SerializedResult {
+	return &SerializedResult{
+		StatusCode: http.StatusOK,
+		MediaType:  mediaType,
+		Response:   response,
+	}
+}
+
+// Error returns a result for an error with the default result provider, using the error response
+// and status code registered for the error's class; see `NewErrorResponse`.
+/*
+Handlers can return errors without switching on them:
+
+	user, err := store.User(id)
+	if err != nil {
+		return r.Error(err) // e.g. {"statusCode":404,"code":"user_not_found","message":"..."}
+	}
+
+Internal errors, i.e. errors with unregistered classes, are logged.
+*/
+func (rc *Ctx) Error(err error) Result {
+	response := NewErrorResponse(err)
+	result := rc.DefaultResultProvider().Status(response.StatusCode, response)
+	if response.StatusCode >= http.StatusInternalServerError {
+		return resultWithLoggedError(result, err)
+	}
+	return result
+}
+
 // DefaultResultProvider returns the current result provider for the context. This is
 // set by calling SetDefaultResultProvider or using one of the pre-built middleware
 // steps that set it for you.
@@ -316,14 +376,14 @@ func (rc *Ctx) HeaderValue(key string) (value string, err error) {
 
 // PostBody returns the bytes in a post body.
 func (rc *Ctx) PostBody() ([]byte, error) {
-	var err error
 	if len(rc.postBody) == 0 {
 		if rc.request != nil && rc.request.Body != nil {
 			defer rc.request.Body.Close()
-			rc.postBody, err = ioutil.ReadAll(rc.request.Body)
-		}
-		if err != nil {
-			return nil, exception.New(err)
+			body, err := ioutil.ReadAll(rc.request.Body)
+			if err != nil {
+				return nil, exception.New(err)
+			}
+			rc.postBody = body
 		}
 	}
 	return rc.postBody, nil
@@ -488,6 +548,21 @@ func (rc *Ctx) NoContent() *NoContentResult {
 	return &NoContentResult{}
 }
 
+// WebSocket returns a result that upgrades the request to a websocket and runs a given handler on it.
+func (rc *Ctx) WebSocket(handler WebSocketHandler) *WebSocketResult {
+	return NewWebSocketResult(handler)
+}
+
+// SSE returns a result that streams server sent events from a given handler.
+func (rc *Ctx) SSE(handler SSEHandler) *SSEResult {
+	return NewSSEResult(handler)
+}
+
+// Proxy returns a result that forwards the request to a reverse proxy's upstream.
+func (rc *Ctx) Proxy(proxy *ReverseProxy) *ProxyResult {
+	return &ProxyResult{Proxy: proxy}
+}
+
 // Static returns a static result.
 func (rc *Ctx) Static(filePath string) *StaticResult {
 	return NewStaticResultForFile(filePath)
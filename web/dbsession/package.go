@@ -0,0 +1,2 @@
+// Package dbsession provides a `web.SessionStore` that saves sessions to a database table with the `db` package.
+package dbsession
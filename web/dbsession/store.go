@@ -0,0 +1,142 @@
+package dbsession
+
+import (
+	"context"
+	"time"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/web"
+)
+
+const (
+	// DefaultTableName is the default sessions table name.
+	DefaultTableName = "web_session"
+)
+
+// Schema is the postgres schema for the sessions table.
+const Schema = `CREATE TABLE IF NOT EXISTS web_session (
+	session_id varchar(255) not null primary key,
+	user_id varchar(255) not null,
+	base_url varchar(1024),
+	created_utc timestamp not null,
+	expires_utc timestamp,
+	rotated_utc timestamp,
+	replaced_by varchar(255),
+	user_agent varchar(1024),
+	remote_addr varchar(255),
+	state json
+);
+CREATE INDEX IF NOT EXISTS ix_web_session_expires_utc ON web_session(expires_utc);`
+
+var (
+	_ web.SessionStore = (*Store)(nil)
+)
+
+// New returns a new session store for a given connection.
+// The sessions table must exist, see `Schema`.
+func New(conn *db.Connection) *Store {
+	return &Store{conn: conn}
+}
+
+// Store is a session store that saves sessions to a database table.
+type Store struct {
+	conn *db.Connection
+}
+
+// Conn returns the underlying connection.
+func (s *Store) Conn() *db.Connection {
+	return s.conn
+}
+
+// FetchSession implements web.SessionStore; expired sessions are not returned.
+func (s *Store) FetchSession(ctx context.Context, sessionID string) (*web.Session, error) {
+	var row session
+	if err := s.conn.Invoke(ctx).Get(&row, sessionID); err != nil {
+		return nil, err
+	}
+	if len(row.SessionID) == 0 {
+		return nil, nil
+	}
+	output := row.Session()
+	if output.IsExpired() {
+		return nil, nil
+	}
+	return output, nil
+}
+
+// PersistSession implements web.SessionStore.
+func (s *Store) PersistSession(ctx context.Context, value *web.Session) error {
+	return s.conn.Invoke(ctx).Upsert(newSession(value))
+}
+
+// RemoveSession implements web.SessionStore.
+func (s *Store) RemoveSession(ctx context.Context, sessionID string) error {
+	return s.conn.Invoke(ctx).Delete(&session{SessionID: sessionID})
+}
+
+// RemoveExpired removes expired sessions, and should be called periodically, e.g. from a cron job.
+func (s *Store) RemoveExpired(ctx context.Context) error {
+	return s.conn.ExecContext(ctx, "DELETE FROM "+DefaultTableName+" WHERE expires_utc < $1", time.Now().UTC())
+}
+
+func newSession(value *web.Session) *session {
+	return &session{
+		SessionID:  value.SessionID,
+		UserID:     value.UserID,
+		BaseURL:    value.BaseURL,
+		CreatedUTC: value.CreatedUTC,
+		ExpiresUTC: optionalTime(value.ExpiresUTC),
+		RotatedUTC: optionalTime(value.RotatedUTC),
+		ReplacedBy: value.ReplacedBy,
+		UserAgent:  value.UserAgent,
+		RemoteAddr: value.RemoteAddr,
+		State:      value.State,
+	}
+}
+
+// session is the database mapped session row.
+type session struct {
+	SessionID  string                 `db:"session_id,pk"`
+	UserID     string                 `db:"user_id"`
+	BaseURL    string                 `db:"base_url"`
+	CreatedUTC time.Time              `db:"created_utc"`
+	ExpiresUTC *time.Time             `db:"expires_utc,nullable"`
+	RotatedUTC *time.Time             `db:"rotated_utc,nullable"`
+	ReplacedBy string                 `db:"replaced_by"`
+	UserAgent  string                 `db:"user_agent"`
+	RemoteAddr string                 `db:"remote_addr"`
+	State      map[string]interface{} `db:"state,json"`
+}
+
+// TableName returns the table name.
+func (s session) TableName() string {
+	return DefaultTableName
+}
+
+// Session returns the row as a web session.
+func (s session) Session() *web.Session {
+	output := &web.Session{
+		SessionID:  s.SessionID,
+		UserID:     s.UserID,
+		BaseURL:    s.BaseURL,
+		CreatedUTC: s.CreatedUTC.UTC(),
+		ReplacedBy: s.ReplacedBy,
+		UserAgent:  s.UserAgent,
+		RemoteAddr: s.RemoteAddr,
+		State:      s.State,
+	}
+	if s.ExpiresUTC != nil {
+		output.ExpiresUTC = s.ExpiresUTC.UTC()
+	}
+	if s.RotatedUTC != nil {
+		output.RotatedUTC = s.RotatedUTC.UTC()
+	}
+	return output
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,72 @@
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/blend/go-sdk/web"
+)
+
+// Mount mounts debug endpoints on an app under a prefix, behind the given middleware, and returns the group
+// they're registered on, so more can be added.
+/*
+The endpoints are:
+
+	{prefix}/pprof/         the pprof index, and profiles at {prefix}/pprof/{profile}
+	{prefix}/vars           expvar variables, including memstats and the command line
+	{prefix}/routes         the app's routes, as json
+
+They are served by the app's listener, so profiling a production app doesn't need a second port; guard them
+with auth middleware, e.g.:
+
+	debug.Mount(app, "/debug", web.SessionRequired, adminOnly)
+
+CPU profiles and traces are limited by the app's write timeout, so set it longer than the profiles you take.
+*/
+func Mount(app *web.App, prefix string, middleware ...web.Middleware) *web.Group {
+	group := app.Group(prefix, middleware...)
+	group.GET("/pprof/", debugHandler(http.HandlerFunc(pprof.Index)))
+	group.GET("/pprof/:name", debugPprof)
+	group.POST("/pprof/:name", debugPprof)
+	group.GET("/vars", debugHandler(expvar.Handler()))
+	group.GET("/routes", func(r *web.Ctx) web.Result {
+		type debugRoute struct {
+			Method string   `json:"method"`
+			Path   string   `json:"path"`
+			Params []string `json:"params,omitempty"`
+		}
+		var routes []debugRoute
+		for _, route := range app.Routes() {
+			routes = append(routes, debugRoute{Method: route.Method, Path: route.Path, Params: route.Params})
+		}
+		return r.JSON().Result(routes)
+	})
+	return group
+}
+
+// debugPprof serves a pprof endpoint; they share a route because the route tree doesn't allow
+// static segments next to a route parameter.
+func debugPprof(r *web.Ctx) web.Result {
+	name, _ := r.RouteParam("name")
+	switch name {
+	case "cmdline":
+		pprof.Cmdline(r.Response(), r.Request())
+	case "profile":
+		pprof.Profile(r.Response(), r.Request())
+	case "symbol":
+		pprof.Symbol(r.Response(), r.Request())
+	case "trace":
+		pprof.Trace(r.Response(), r.Request())
+	default:
+		pprof.Handler(name).ServeHTTP(r.Response(), r.Request())
+	}
+	return nil
+}
+
+func debugHandler(handler http.Handler) web.Action {
+	return func(r *web.Ctx) web.Result {
+		handler.ServeHTTP(r.Response(), r.Request())
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestMount(t *testing.T) {
+	assert := assert.New(t)
+
+	app := web.New()
+	app.GET("/users/:id", func(_ *web.Ctx) web.Result { return nil })
+	Mount(app, "/admin/debug", func(action web.Action) web.Action {
+		return func(r *web.Ctx) web.Result {
+			if r.Request().Header.Get("X-Admin") != "true" {
+				return r.Text().NotAuthorized()
+			}
+			return action(r)
+		}
+	})
+
+	get := func(path string, admin bool) (*http.Response, string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if admin {
+			req.Header.Set("X-Admin", "true")
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		contents, err := ioutil.ReadAll(w.Result().Body)
+		assert.Nil(err)
+		return w.Result(), string(contents)
+	}
+
+	for _, path := range []string{"/admin/debug/pprof/", "/admin/debug/pprof/heap", "/admin/debug/vars", "/admin/debug/routes"} {
+		res, _ := get(path, false)
+		assert.Equal(http.StatusForbidden, res.StatusCode, path)
+	}
+
+	res, body := get("/admin/debug/pprof/", true)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Contains(body, "goroutine?debug=1")
+
+	res, body = get("/admin/debug/pprof/goroutine?debug=1", true)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Contains(body, "goroutine profile:")
+
+	res, body = get("/admin/debug/pprof/cmdline", true)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.NotEmpty(body)
+
+	res, _ = get("/admin/debug/pprof/nope", true)
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+
+	res, body = get("/admin/debug/vars", true)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Contains(body, "\"memstats\"")
+
+	res, body = get("/admin/debug/routes", true)
+	assert.Equal(http.StatusOK, res.StatusCode)
+	var routes []map[string]interface{}
+	assert.Nil(json.NewDecoder(strings.NewReader(body)).Decode(&routes))
+	assert.Contains(body, "\"path\":\"/users/:id\"")
+	assert.Contains(body, "\"path\":\"/admin/debug/pprof/:name\"")
+}
@@ -0,0 +1,3 @@
+// Package debug mounts pprof, expvar and route dump endpoints on a `web.App`. It is separate from `web` because
+// importing `net/http/pprof` and `expvar` registers their handlers on `http.DefaultServeMux`.
+package debug
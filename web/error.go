@@ -17,6 +17,46 @@ const (
 
 	// ErrParameterMissing is an error on request validation.
 	ErrParameterMissing exception.Class = "parameter is missing"
+
+	// ErrWebSocketHandshake is an error returned if a websocket upgrade request is invalid.
+	ErrWebSocketHandshake exception.Class = "websocket handshake failed"
+	// ErrWebSocketProtocol is an error returned if a websocket peer violates the protocol.
+	ErrWebSocketProtocol exception.Class = "websocket protocol error"
+	// ErrWebSocketClosed is an error returned if a websocket is used after it is closed.
+	ErrWebSocketClosed exception.Class = "websocket is closed"
+	// ErrSSEClosed is an error returned if an event stream is written to after the client disconnects.
+	ErrSSEClosed exception.Class = "event stream is closed"
+
+	// ErrBindTarget is an error returned if a bind or validate target is not a struct.
+	ErrBindTarget exception.Class = "invalid bind target"
+	// ErrValidationRule is an error returned if a field's validation rules are malformed.
+	ErrValidationRule exception.Class = "invalid validation rule"
+
+	// ErrRateLimitStore is an error returned if a rate limit store returns an unexpected value.
+	ErrRateLimitStore exception.Class = "rate limit store error"
+
+	// ErrJWTAudience is an error returned if a jwt was not issued for the expected audience.
+	ErrJWTAudience exception.Class = "jwt audience is invalid"
+	// ErrJWTIssuer is an error returned if a jwt was not issued by the expected issuer.
+	ErrJWTIssuer exception.Class = "jwt issuer is invalid"
+	// ErrJWKSFetch is an error returned if keys can't be fetched from a jwks endpoint.
+	ErrJWKSFetch exception.Class = "jwks fetch failed"
+	// ErrJWKSKeyNotFound is an error returned if a jwks endpoint has no key for a token's key id.
+	ErrJWKSKeyNotFound exception.Class = "jwks key not found"
+	// ErrJWKSInvalidKey is an error returned if a json web key is malformed or unsupported.
+	ErrJWKSInvalidKey exception.Class = "jwks key is invalid"
+
+	// ErrReverseProxy is an error returned if a request can't be proxied to an upstream.
+	ErrReverseProxy exception.Class = "reverse proxy error"
+
+	// ErrACME is an error returned if a certificate can't be obtained from an acme server.
+	ErrACME exception.Class = "acme error"
+
+	// ErrRequestBodyTooLarge is an error returned when reading a request body past its size limit.
+	ErrRequestBodyTooLarge exception.Class = "request body too large"
+
+	// ErrSerialize is an error returned if a response can't be serialized in a media type.
+	ErrSerialize exception.Class = "serialize error"
 )
 
 func newParameterMissingError(paramName string) error {
@@ -0,0 +1,73 @@
+package web
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+func init() {
+	exception.Register(ErrParameterMissing, "parameter_missing", http.StatusBadRequest)
+	exception.Register(ErrRequestBodyTooLarge, "request_body_too_large", http.StatusRequestEntityTooLarge)
+	exception.Register(ErrSessionIDEmpty, "session_invalid", http.StatusUnauthorized)
+	exception.Register(ErrSecureSessionIDEmpty, "session_invalid", http.StatusUnauthorized)
+	exception.Register(ErrJWTAudience, "token_invalid", http.StatusUnauthorized)
+	exception.Register(ErrJWTIssuer, "token_invalid", http.StatusUnauthorized)
+	exception.RegisterFunc(IsBindError, "invalid_request", http.StatusBadRequest)
+	exception.RegisterFunc(IsParamError, "invalid_parameter", http.StatusBadRequest)
+}
+
+// NewErrorResponse returns the error response for an error, with the code and status code registered for
+// its class with `exception.Register`.
+/*
+The message is taken from the error that matched the registered class rather than any errors wrapping it,
+so context added while the error was returned up the stack isn't sent to clients. Errors whose class isn't
+registered are internal errors; their message isn't included, so internal details aren't leaked to clients.
+*/
+func NewErrorResponse(err error) ErrorResponse {
+	code, matched, ok := exception.DefaultRegistry.Match(err)
+	if !ok {
+		code = exception.InternalErrorCode
+	}
+	response := ErrorResponse{
+		StatusCode: code.StatusCode,
+		Code:       code.Code,
+	}
+	if code.StatusCode >= http.StatusInternalServerError {
+		response.Message = http.StatusText(code.StatusCode)
+		return response
+	}
+	if typed, isTyped := matched.(exception.Exception); isTyped {
+		response.Message = typed.Message()
+		if len(response.Message) == 0 && typed.Class() != nil {
+			response.Message = typed.Class().Error()
+		}
+	} else if matched != nil {
+		response.Message = matched.Error()
+	}
+	var bindErr *BindError
+	if errors.As(matched, &bindErr) {
+		response.Message = bindErr.Message
+		response.Fields = bindErr.Fields
+	}
+	return response
+}
+
+// ErrorResponse is a consistent envelope for error responses; see `ctx.Error(err)`.
+type ErrorResponse struct {
+	XMLName    xml.Name     `json:"-" xml:"error"`
+	StatusCode int          `json:"statusCode" xml:"statusCode"`
+	Code       string       `json:"code" xml:"code"`
+	Message    string       `json:"message,omitempty" xml:"message,omitempty"`
+	Fields     []FieldError `json:"fields,omitempty" xml:"field,omitempty"`
+}
+
+// String returns the code and message, for text results.
+func (er ErrorResponse) String() string {
+	if len(er.Message) == 0 {
+		return er.Code
+	}
+	return er.Code + ": " + er.Message
+}
@@ -0,0 +1,75 @@
+package web
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestNewErrorResponse(t *testing.T) {
+	assert := assert.New(t)
+
+	errNotFound := exception.Class("widget not found")
+	exception.Register(errNotFound, "widget_not_found", http.StatusNotFound)
+
+	assert.Equal(ErrorResponse{StatusCode: http.StatusNotFound, Code: "widget_not_found", Message: "widget not found"}, NewErrorResponse(exception.New(errNotFound)))
+	assert.Equal(ErrorResponse{StatusCode: http.StatusNotFound, Code: "widget_not_found", Message: "widget 1234"}, NewErrorResponse(exception.New(errNotFound).WithMessage("widget 1234")))
+	assert.Equal(ErrorResponse{StatusCode: http.StatusBadRequest, Code: "parameter_missing", Message: "parameter is missing"}, NewErrorResponse(exception.New(ErrParameterMissing)))
+	assert.Equal(ErrorResponse{StatusCode: http.StatusNotFound, Code: "widget_not_found", Message: "widget 1234"}, NewErrorResponse(fmt.Errorf("select from widgets on 10.0.0.1: %w", exception.New(errNotFound).WithMessage("widget 1234"))), "messages of errors wrapping the registered one shouldn't be leaked")
+	assert.Equal(ErrorResponse{StatusCode: http.StatusNotFound, Code: "widget_not_found", Message: "widget not found"}, NewErrorResponse(exception.New("query failed: password authentication failed").WithInner(exception.New(errNotFound))))
+	assert.Equal(ErrorResponse{StatusCode: http.StatusBadRequest, Code: "invalid_parameter", Message: "`id` parameter must be an integer"}, NewErrorResponse(&ParamError{Param: "id", Message: "must be an integer"}))
+
+	bindErr := &BindError{Message: "invalid request", Fields: []FieldError{{Field: "name", Rule: "required", Message: "is required"}}}
+	response := NewErrorResponse(fmt.Errorf("binding: %w", bindErr))
+	assert.Equal(http.StatusBadRequest, response.StatusCode)
+	assert.Equal("invalid_request", response.Code)
+	assert.Equal("invalid request", response.Message)
+	assert.Equal(bindErr.Fields, response.Fields)
+
+	response = NewErrorResponse(exception.New("connection refused to 10.0.0.1"))
+	assert.Equal(ErrorResponse{StatusCode: http.StatusInternalServerError, Code: "internal_error", Message: "Internal Server Error"}, response, "internal errors shouldn't be leaked")
+	assert.Equal("internal_error: Internal Server Error", response.String())
+}
+
+func TestCtxError(t *testing.T) {
+	assert := assert.New(t)
+
+	errNotFound := exception.Class("gadget not found")
+	exception.Register(errNotFound, "gadget_not_found", http.StatusNotFound)
+
+	app := New()
+	app.GET("/json", func(r *Ctx) Result {
+		return r.Error(exception.New(errNotFound))
+	}, JSONProviderAsDefault)
+	app.GET("/text", func(r *Ctx) Result {
+		return r.Error(exception.New(errNotFound))
+	}, TextProviderAsDefault)
+	app.GET("/xml", func(r *Ctx) Result {
+		return r.Error(exception.New("internal"))
+	}, XMLProviderAsDefault)
+
+	get := func(path string) (int, string) {
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		contents, err := ioutil.ReadAll(w.Result().Body)
+		assert.Nil(err)
+		return w.Result().StatusCode, string(contents)
+	}
+
+	statusCode, body := get("/json")
+	assert.Equal(http.StatusNotFound, statusCode)
+	assert.Equal("{\"statusCode\":404,\"code\":\"gadget_not_found\",\"message\":\"gadget not found\"}\n", body)
+
+	statusCode, body = get("/text")
+	assert.Equal(http.StatusNotFound, statusCode)
+	assert.Equal("gadget_not_found: gadget not found", body)
+
+	statusCode, body = get("/xml")
+	assert.Equal(http.StatusInternalServerError, statusCode)
+	assert.Equal("<error><statusCode>500</statusCode><code>internal_error</code><message>Internal Server Error</message></error>", body)
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,6 +13,12 @@ type Shutdowner interface {
 	Shutdown() error
 }
 
+// BackgroundWorker is a process that runs alongside an app, e.g. a `cron.JobManager`.
+type BackgroundWorker interface {
+	Start()
+	Stop()
+}
+
 // GracefulShutdown is an alias to StartWithGracefulShutdown.
 var GracefulShutdown = StartWithGracefulShutdown
 
@@ -66,3 +73,55 @@ func startWithGracefulShutdownBySignal(app Shutdowner, terminateSignal chan os.S
 	}
 	return nil
 }
+
+// StartWithShutdown starts the app and its background workers, and shuts them down when the context
+// is cancelled or on SIGINT or SIGTERM. The server stops accepting new connections and drains in
+// flight requests for up to the shutdown grace period, then the background workers are stopped
+// before it returns. It will return any errors from starting or draining the server.
+func (a *App) StartWithShutdown(ctx context.Context) error {
+	terminateSignal := make(chan os.Signal, 1)
+	signal.Notify(terminateSignal, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(terminateSignal)
+	return a.startWithShutdown(ctx, terminateSignal)
+}
+
+func (a *App) startWithShutdown(ctx context.Context, terminateSignal chan os.Signal) error {
+	server := make(chan error, 1)
+	go func() {
+		server <- a.Start()
+	}()
+
+	select {
+	case <-a.NotifyStarted():
+	case err := <-server:
+		return err
+	}
+
+	for _, worker := range a.backgroundWorkers {
+		worker.Start()
+	}
+	defer a.stopBackgroundWorkers()
+
+	select {
+	case <-ctx.Done():
+	case <-terminateSignal:
+	case err := <-server: // if the server exited on its own
+		return err
+	}
+
+	shutdownErr := a.Shutdown()
+	if err := <-server; err != nil {
+		return err
+	}
+	return shutdownErr
+}
+
+func (a *App) stopBackgroundWorkers() {
+	if len(a.backgroundWorkers) == 0 {
+		return
+	}
+	a.syncInfof("stopping background workers")
+	for _, worker := range a.backgroundWorkers {
+		worker.Stop()
+	}
+}
@@ -1,8 +1,13 @@
 package web
 
 import (
+	"context"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
 )
@@ -24,3 +29,99 @@ func TestGracefulShutdown(t *testing.T) {
 	<-done
 	assert.Nil(err)
 }
+
+type mockBackgroundWorker struct {
+	started int32
+	stopped int32
+}
+
+func (mbw *mockBackgroundWorker) Start() { atomic.AddInt32(&mbw.started, 1) }
+func (mbw *mockBackgroundWorker) Stop()  { atomic.AddInt32(&mbw.stopped, 1) }
+
+func TestAppStartWithShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	inFlight := make(chan struct{})
+	worker := &mockBackgroundWorker{}
+	app := New().WithBindAddr(DefaultIntegrationBindAddr).WithBackgroundWorkers(worker)
+	app.GET("/", func(r *Ctx) Result {
+		close(inFlight)
+		time.Sleep(50 * time.Millisecond)
+		return r.Text().Result("OK!")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = app.startWithShutdown(ctx, make(chan os.Signal))
+		close(done)
+	}()
+	<-app.NotifyStarted()
+
+	var res *http.Response
+	var resErr error
+	requestDone := make(chan struct{})
+	go func() {
+		res, resErr = http.Get("http://" + app.Listener().Addr().String() + "/")
+		close(requestDone)
+	}()
+
+	<-inFlight
+	cancel()
+	<-done
+	assert.Nil(err)
+	assert.Equal(1, atomic.LoadInt32(&worker.started))
+	assert.Equal(1, atomic.LoadInt32(&worker.stopped))
+
+	<-requestDone
+	assert.Nil(resErr)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	contents, readErr := ioutil.ReadAll(res.Body)
+	assert.Nil(readErr)
+	assert.Equal("OK!", string(contents))
+}
+
+func TestAppStartWithShutdownGracePeriodElapsed(t *testing.T) {
+	assert := assert.New(t)
+
+	inFlight := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	worker := &mockBackgroundWorker{}
+	app := New().WithBindAddr(DefaultIntegrationBindAddr).WithShutdownGracePeriod(10 * time.Millisecond).WithBackgroundWorkers(worker)
+	app.GET("/", func(r *Ctx) Result {
+		close(inFlight)
+		<-release
+		return r.Text().Result("OK!")
+	})
+
+	terminateSignal := make(chan os.Signal)
+	var err error
+	done := make(chan struct{})
+	go func() {
+		err = app.startWithShutdown(context.Background(), terminateSignal)
+		close(done)
+	}()
+	<-app.NotifyStarted()
+
+	go http.Get("http://" + app.Listener().Addr().String() + "/")
+	<-inFlight
+	close(terminateSignal)
+	<-done
+	assert.NotNil(err)
+	assert.Equal(1, atomic.LoadInt32(&worker.stopped))
+}
+
+func TestAppStartWithShutdownStartError(t *testing.T) {
+	assert := assert.New(t)
+
+	worker := &mockBackgroundWorker{}
+	app := New().WithBindAddr("not-an-address").WithBackgroundWorkers(worker)
+	assert.NotNil(app.startWithShutdown(context.Background(), make(chan os.Signal)))
+	assert.Zero(atomic.LoadInt32(&worker.started))
+}
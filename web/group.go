@@ -0,0 +1,122 @@
+package web
+
+import "strings"
+
+// Group returns a route group; routes registered on the group are prefixed with the
+// group's prefix, and run the group's middleware before their own.
+/*
+Groups are useful for sections of an app that share configuration, e.g. versioned apis:
+
+	v1 := app.Group("/api/v1", web.JSONProviderAsDefault, authMiddleware)
+	v1.GET("/users", listUsers)        // registered at /api/v1/users
+	v1.GET("/users/:id", getUser)      // registered at /api/v1/users/:id
+
+	admin := v1.Group("/admin", adminOnly)
+	admin.DELETE("/users/:id", deleteUser) // registered at /api/v1/admin/users/:id
+
+As with `app.Use`, middleware is applied when routes are registered.
+*/
+func (a *App) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		app:        a,
+		prefix:     strings.TrimSuffix(prefix, "/"),
+		middleware: middleware,
+	}
+}
+
+// Group is a set of routes that share a path prefix and middleware.
+type Group struct {
+	app        *App
+	parent     *Group
+	prefix     string
+	middleware []Middleware
+}
+
+// App returns the app the group registers routes with.
+func (g *Group) App() *App {
+	return g.app
+}
+
+// Prefix returns the path prefix for the group's routes.
+func (g *Group) Prefix() string {
+	return g.prefix
+}
+
+// Middleware returns the group's middleware, not including the middleware of parent groups.
+func (g *Group) Middleware() []Middleware {
+	return g.middleware
+}
+
+// Use adds middleware to the group, nested outside any middleware already added.
+// It only applies to routes registered after it is added.
+func (g *Group) Use(middleware ...Middleware) *Group {
+	g.middleware = append(g.middleware, middleware...)
+	return g
+}
+
+// Group returns a nested group; its prefix is appended to this group's prefix
+// and its middleware runs after this group's middleware.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	return &Group{
+		app:        g.app,
+		parent:     g,
+		prefix:     g.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: middleware,
+	}
+}
+
+// Path returns the full path for a route in the group.
+// An empty path is the group prefix itself.
+func (g *Group) Path(path string) string {
+	if len(path) == 0 && len(g.prefix) == 0 {
+		return "/"
+	}
+	return g.prefix + path
+}
+
+// GET registers a GET request handler.
+func (g *Group) GET(path string, action Action, middleware ...Middleware) {
+	g.app.GET(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// OPTIONS registers a OPTIONS request handler.
+func (g *Group) OPTIONS(path string, action Action, middleware ...Middleware) {
+	g.app.OPTIONS(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// HEAD registers a HEAD request handler.
+func (g *Group) HEAD(path string, action Action, middleware ...Middleware) {
+	g.app.HEAD(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// PUT registers a PUT request handler.
+func (g *Group) PUT(path string, action Action, middleware ...Middleware) {
+	g.app.PUT(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// PATCH registers a PATCH request handler.
+func (g *Group) PATCH(path string, action Action, middleware ...Middleware) {
+	g.app.PATCH(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// POST registers a POST request handler.
+func (g *Group) POST(path string, action Action, middleware ...Middleware) {
+	g.app.POST(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// DELETE registers a DELETE request handler.
+func (g *Group) DELETE(path string, action Action, middleware ...Middleware) {
+	g.app.DELETE(g.Path(path), action, g.routeMiddleware(middleware)...)
+}
+
+// routeMiddleware returns the middleware for a route, with the middleware of the group
+// and its parents nested outside it.
+func (g *Group) routeMiddleware(middleware []Middleware) []Middleware {
+	output := make([]Middleware, 0, len(middleware)+len(g.middleware))
+	output = append(output, middleware...)
+	output = append(output, g.middleware...)
+	if g.parent != nil {
+		return g.parent.routeMiddleware(output)
+	}
+	return output
+}
@@ -0,0 +1,71 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func groupTestMiddleware(name string, calls *[]string) Middleware {
+	return func(action Action) Action {
+		return func(ctx *Ctx) Result {
+			*calls = append(*calls, name)
+			return action(ctx)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	app := New()
+	app.Use(groupTestMiddleware("app", &calls))
+
+	v1 := app.Group("/api/v1/", groupTestMiddleware("v1", &calls))
+	assert.Equal("/api/v1", v1.Prefix())
+	v1.GET("", func(r *Ctx) Result {
+		return r.Text().Result("index")
+	})
+	v1.GET("/users/:id", func(r *Ctx) Result {
+		return r.Text().Result("user " + r.routeParameters.Get("id"))
+	}, groupTestMiddleware("route", &calls))
+
+	admin := v1.Group("/admin", groupTestMiddleware("admin", &calls))
+	admin.Use(groupTestMiddleware("admin2", &calls))
+	admin.DELETE("/users/:id", func(r *Ctx) Result {
+		return r.NoContent()
+	})
+
+	contents, err := app.Mock().WithPathf("/api/v1").Bytes()
+	assert.Nil(err)
+	assert.Equal("index", string(contents))
+	assert.Equal([]string{"app", "v1"}, calls)
+
+	calls = nil
+	contents, err = app.Mock().WithPathf("/api/v1/users/1").Bytes()
+	assert.Nil(err)
+	assert.Equal("user 1", string(contents))
+	assert.Equal([]string{"app", "v1", "route"}, calls)
+
+	calls = nil
+	_, meta, err := app.Mock().WithVerb("DELETE").WithPathf("/api/v1/admin/users/1").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusNoContent, meta.StatusCode)
+	assert.Equal("app,v1,admin2,admin", strings.Join(calls, ","))
+
+	route, _, _ := app.Lookup("DELETE", "/admin/users/1")
+	assert.Nil(route)
+}
+
+func TestGroupPath(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	assert.Equal("/", app.Group("/").Path(""))
+	assert.Equal("/foo", app.Group("").Path("/foo"))
+	assert.Equal("/api/foo/", app.Group("/api").Path("/foo/"))
+	assert.Equal("/api/v2", app.Group("/api").Group("/v2/").Path(""))
+}
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/blend/go-sdk/env"
 	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/healthz"
 	"github.com/blend/go-sdk/logger"
 )
 
@@ -37,12 +40,25 @@ const (
 
 	// ErrHealthzAppUnset is a common error.
 	ErrHealthzAppUnset exception.Class = "healthz app unset"
+	// ErrHealthzAppNotRunning is the error reported by the app check if the monitored app is not running.
+	ErrHealthzAppNotRunning exception.Class = "healthz app not running"
+
+	// HealthzCheckApp is the name of the built-in check that the monitored app is running.
+	HealthzCheckApp = "app"
 )
 
 // NewHealthz returns a new healthz.
 func NewHealthz(monitored *App) *Healthz {
+	appCheck := healthz.CheckerFunc(func(_ context.Context) error {
+		if !monitored.Latch().IsRunning() {
+			return exception.New(ErrHealthzAppNotRunning)
+		}
+		return nil
+	})
 	return &Healthz{
 		monitored:      monitored,
+		liveness:       healthz.New().Register(HealthzCheckApp, appCheck),
+		readiness:      healthz.New().Register(HealthzCheckApp, appCheck),
 		defaultHeaders: map[string]string{},
 		state:          State{},
 		vars: State{
@@ -78,7 +94,8 @@ func NewHealthzFromConfig(monitored *App, cfg *HealthzConfig) *Healthz {
 // Healthz is a sentinel / healthcheck sidecar that can run on a different
 // port to the main app.
 // It typically implements the following routes:
-// 	/healthz - overall health endpoint, 200 on healthy, 5xx on not.
+// 	/healthz - liveness endpoint, 200 if the liveness checks pass, 503 if not.
+// 	/readyz  - readiness endpoint, 200 if the readiness checks pass, 503 if not.
 // 	/varz    - basic stats and metrics since start
 //	/debug/vars - `pkg/expvar` output.
 type Healthz struct {
@@ -99,6 +116,9 @@ type Healthz struct {
 
 	state State
 
+	liveness  *healthz.Checks
+	readiness *healthz.Checks
+
 	varsLock sync.Mutex
 	vars     State
 
@@ -111,6 +131,30 @@ func (hz *Healthz) Monitored() *App {
 	return hz.monitored
 }
 
+// WithLivenessCheck registers a check run by `/healthz`, e.g. for in process subsystems like a job manager.
+// A failing liveness check typically gets the process restarted, so it should not check external dependencies.
+func (hz *Healthz) WithLivenessCheck(name string, checker healthz.Checker) *Healthz {
+	hz.liveness.Register(name, checker)
+	return hz
+}
+
+// LivenessChecks returns the checks run by `/healthz`; they include a check that the monitored app is running.
+func (hz *Healthz) LivenessChecks() *healthz.Checks {
+	return hz.liveness
+}
+
+// WithReadinessCheck registers a check run by `/readyz`, e.g. for a database connection or an external dependency.
+// A failing readiness check typically takes the process out of load balancing until it passes.
+func (hz *Healthz) WithReadinessCheck(name string, checker healthz.Checker) *Healthz {
+	hz.readiness.Register(name, checker)
+	return hz
+}
+
+// ReadinessChecks returns the checks run by `/readyz`; they include a check that the monitored app is running.
+func (hz *Healthz) ReadinessChecks() *healthz.Checks {
+	return hz.readiness
+}
+
 // Vars returns the underlying vars collection.
 func (hz *Healthz) Vars() State {
 	return hz.vars
@@ -292,7 +336,9 @@ func (hz *Healthz) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch route {
 	case "/healthz":
-		hz.healthzHandler(res, r)
+		hz.checksHandler(res, r, hz.liveness)
+	case "/readyz":
+		hz.checksHandler(res, r, hz.readiness)
 	case "/varz":
 		hz.varzHandler(res, r)
 	default:
@@ -315,18 +361,30 @@ func (hz *Healthz) recover(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (hz *Healthz) healthzHandler(w ResponseWriter, r *http.Request) {
-	if hz.monitored.Latch().IsRunning() {
-		w.WriteHeader(http.StatusOK)
-		w.Header().Set(HeaderContentType, ContentTypeText)
-		fmt.Fprintf(w, "OK!\n")
+// /healthz and /readyz
+// runs the checks and writes out the report, as json if the client accepts it.
+func (hz *Healthz) checksHandler(w ResponseWriter, r *http.Request, checks *healthz.Checks) {
+	report := checks.Run(r.Context())
+	statusCode := http.StatusOK
+	if !report.OK() {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		w.Header().Set(HeaderContentType, ContentTypeApplicationJSON)
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(report)
 		return
 	}
 
-	w.WriteHeader(http.StatusInternalServerError)
 	w.Header().Set(HeaderContentType, ContentTypeText)
-	fmt.Fprintf(w, "Failure!\n")
-	return
+	w.WriteHeader(statusCode)
+	if report.OK() {
+		fmt.Fprintf(w, "OK!\n")
+	} else {
+		fmt.Fprintf(w, "Failure!\n")
+	}
+	report.WriteText(w)
 }
 
 // /varz
@@ -1,10 +1,16 @@
 package web
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/healthz"
 	"github.com/blend/go-sdk/logger"
 )
 
@@ -47,3 +53,85 @@ func TestHealthz(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(http.StatusOK, healthzRes.StatusCode)
 }
+
+func TestHealthzShutdown(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New().WithBindAddr("127.0.0.1:0")
+	defer app.Shutdown()
+
+	hzApp := New().WithBindAddr("127.0.0.1:0").WithServer(NewHealthz(app).Server())
+
+	go app.Start()
+	go hzApp.Start()
+
+	<-app.NotifyStarted()
+	<-hzApp.NotifyStarted()
+
+	healthzRes, err := http.Get("http://" + hzApp.Listener().Addr().String() + "/healthz")
+	assert.Nil(err)
+	healthzRes.Body.Close()
+	assert.Equal(http.StatusOK, healthzRes.StatusCode)
+
+	assert.Nil(hzApp.Shutdown())
+	assert.False(hzApp.Latch().IsRunning())
+}
+
+func TestHealthzChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New().WithBindAddr("127.0.0.1:0")
+	defer app.Shutdown()
+
+	var dbErr error
+	hz := NewHealthz(app).
+		WithLivenessCheck("cron", healthz.CheckerFunc(func(_ context.Context) error { return nil })).
+		WithReadinessCheck("db", healthz.CheckerFunc(func(_ context.Context) error { return dbErr }))
+	assert.Equal([]string{HealthzCheckApp, "cron"}, hz.LivenessChecks().Names())
+	assert.Equal([]string{HealthzCheckApp, "db"}, hz.ReadinessChecks().Names())
+
+	// the app is not started yet, so every endpoint fails.
+	statusCode, body := hzGet(hz, "/healthz")
+	assert.Equal(http.StatusServiceUnavailable, statusCode)
+	assert.True(strings.HasPrefix(body, "Failure!\n[-] app failed"))
+
+	go app.Start()
+	<-app.NotifyStarted()
+
+	statusCode, body = hzGet(hz, "/healthz")
+	assert.Equal(http.StatusOK, statusCode)
+	assert.True(strings.HasPrefix(body, "OK!\n[+] app ok"))
+	assert.Contains(body, "[+] cron ok")
+
+	dbErr = fmt.Errorf("connection refused")
+	statusCode, body = hzGet(hz, "/readyz", "Accept", "application/json")
+	assert.Equal(http.StatusServiceUnavailable, statusCode)
+	var report struct {
+		Status string `json:"status"`
+		Checks []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Error  string `json:"error"`
+		} `json:"checks"`
+	}
+	assert.Nil(json.Unmarshal([]byte(body), &report))
+	assert.Equal(healthz.StatusFailed, report.Status)
+	assert.Len(report.Checks, 2)
+	assert.Equal(healthz.StatusOK, report.Checks[0].Status)
+	assert.Equal("db", report.Checks[1].Name)
+	assert.Equal("connection refused", report.Checks[1].Error)
+
+	// liveness does not depend on readiness checks.
+	statusCode, _ = hzGet(hz, "/healthz")
+	assert.Equal(http.StatusOK, statusCode)
+}
+
+func hzGet(hz *Healthz, path string, headers ...string) (int, string) {
+	recorder := httptest.NewRecorder()
+	req := NewMockRequest("GET", path)
+	for index := 0; index+1 < len(headers); index += 2 {
+		req.Header.Set(headers[index], headers[index+1])
+	}
+	hz.ServeHTTP(recorder, req)
+	return recorder.Code, recorder.Body.String()
+}
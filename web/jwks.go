@@ -0,0 +1,288 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/jwt"
+)
+
+const (
+	// DefaultJWKSCacheTTL is the default time keys fetched from a jwks endpoint are cached for.
+	DefaultJWKSCacheTTL = time.Hour
+	// DefaultJWKSRefreshInterval is the default minimum time between fetches for keys not in the cache.
+	DefaultJWKSRefreshInterval = time.Minute
+	// DefaultJWKSTimeout is the default timeout for fetching keys.
+	DefaultJWKSTimeout = 10 * time.Second
+)
+
+// JWK is a json web key, as in https://tools.ietf.org/html/rfc7517.
+// Only the fields needed for rsa and elliptic curve public keys are read.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// PublicKey returns the key as an `*rsa.PublicKey` or an `*ecdsa.PublicKey`.
+func (jwk JWK) PublicKey() (interface{}, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, exception.New(ErrJWKSInvalidKey).WithMessagef("kid: %s; exponent is too large", jwk.KeyID)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, exception.New(ErrJWKSInvalidKey).WithMessagef("kid: %s; unsupported curve: %s", jwk.KeyID, jwk.Curve)
+		}
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, exception.New(ErrJWKSInvalidKey).WithMessagef("kid: %s; point is not on curve", jwk.KeyID)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, exception.New(ErrJWKSInvalidKey).WithMessagef("kid: %s; unsupported key type: %s", jwk.KeyID, jwk.KeyType)
+	}
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	if len(value) == 0 {
+		return nil, exception.New(ErrJWKSInvalidKey).WithMessagef("key parameter is empty")
+	}
+	contents, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, exception.New(ErrJWKSInvalidKey).WithInner(err)
+	}
+	return new(big.Int).SetBytes(contents), nil
+}
+
+// NewJWKS returns a new key source for a jwks endpoint, e.g.
+// `https://example.auth0.com/.well-known/jwks.json`.
+// Use `NewJWTAuth(jwks.KeyFunc)` to validate tokens with its keys.
+func NewJWKS(url string) *JWKS {
+	return &JWKS{
+		url:             url,
+		client:          &http.Client{Timeout: DefaultJWKSTimeout},
+		cacheTTL:        DefaultJWKSCacheTTL,
+		refreshInterval: DefaultJWKSRefreshInterval,
+		now:             time.Now,
+	}
+}
+
+// JWKS fetches and caches public keys by key id from a jwks endpoint.
+// Keys are fetched when the cache is older than the cache ttl, or when a token has a key id
+// that is not in the cache (to pick up rotated keys), at most once per refresh interval whether
+// or not the fetch succeeds. Fetches don't block requests that can be served from the cache, and
+// if a fetch fails the cached keys are used until the next fetch.
+type JWKS struct {
+	sync.Mutex
+	url             string
+	client          *http.Client
+	cacheTTL        time.Duration
+	refreshInterval time.Duration
+
+	keys        map[string]interface{}
+	lastFetch   time.Time
+	lastAttempt time.Time
+	lastErr     error
+	fetching    chan struct{}
+	now         func() time.Time
+}
+
+// URL returns the jwks endpoint url.
+func (j *JWKS) URL() string {
+	return j.url
+}
+
+// WithClient sets the http client used to fetch keys.
+func (j *JWKS) WithClient(client *http.Client) *JWKS {
+	j.client = client
+	return j
+}
+
+// Client returns the http client used to fetch keys.
+func (j *JWKS) Client() *http.Client {
+	return j.client
+}
+
+// WithCacheTTL sets the time keys are cached for.
+func (j *JWKS) WithCacheTTL(ttl time.Duration) *JWKS {
+	j.cacheTTL = ttl
+	return j
+}
+
+// CacheTTL returns the time keys are cached for.
+func (j *JWKS) CacheTTL() time.Duration {
+	return j.cacheTTL
+}
+
+// WithRefreshInterval sets the minimum time between fetches.
+func (j *JWKS) WithRefreshInterval(interval time.Duration) *JWKS {
+	j.refreshInterval = interval
+	return j
+}
+
+// RefreshInterval returns the minimum time between fetches.
+func (j *JWKS) RefreshInterval() time.Duration {
+	return j.refreshInterval
+}
+
+// KeyFunc implements `jwt.Keyfunc`, returning the key for the token's `kid` header.
+// If the token has no `kid` and the endpoint has a single key, that key is used.
+func (j *JWKS) KeyFunc(token *jwt.Token) (interface{}, error) {
+	keyID, _ := token.Header["kid"].(string)
+	return j.Key(keyID)
+}
+
+// Key returns a key by key id, fetching keys if required.
+func (j *JWKS) Key(keyID string) (interface{}, error) {
+	now := j.now()
+
+	j.Lock()
+	stale := j.keys == nil || now.Sub(j.lastFetch) >= j.cacheTTL
+	j.Unlock()
+	if stale {
+		if err := j.refresh(now); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := j.lookup(keyID); ok {
+		return key, nil
+	}
+	if err := j.refresh(now); err != nil {
+		return nil, err
+	}
+	if key, ok := j.lookup(keyID); ok {
+		return key, nil
+	}
+	return nil, exception.New(ErrJWKSKeyNotFound).WithMessagef("kid: %s", keyID)
+}
+
+func (j *JWKS) lookup(keyID string) (interface{}, bool) {
+	j.Lock()
+	defer j.Unlock()
+
+	if len(keyID) == 0 && len(j.keys) == 1 {
+		for _, key := range j.keys {
+			return key, true
+		}
+	}
+	key, ok := j.keys[keyID]
+	return key, ok
+}
+
+// refresh fetches the keys, unless they were fetched (or a fetch failed) within the refresh interval.
+// Only one fetch runs at a time, and callers only wait for it if there are no cached keys.
+// It returns an error only if there are no cached keys to use instead.
+func (j *JWKS) refresh(now time.Time) error {
+	j.Lock()
+	if fetching := j.fetching; fetching != nil {
+		if j.keys != nil {
+			j.Unlock()
+			return nil
+		}
+		j.Unlock()
+		<-fetching
+		j.Lock()
+		defer j.Unlock()
+		return j.errIfNoKeys()
+	}
+	if !j.lastAttempt.IsZero() && now.Sub(j.lastAttempt) < j.refreshInterval {
+		defer j.Unlock()
+		return j.errIfNoKeys()
+	}
+	j.lastAttempt = now
+	fetching := make(chan struct{})
+	j.fetching = fetching
+	j.Unlock()
+
+	keys, err := j.fetch()
+
+	j.Lock()
+	defer j.Unlock()
+	if err == nil {
+		j.keys = keys
+		j.lastFetch = now
+	}
+	j.lastErr = err
+	j.fetching = nil
+	close(fetching)
+	return j.errIfNoKeys()
+}
+
+func (j *JWKS) errIfNoKeys() error {
+	if j.keys == nil {
+		return j.lastErr
+	}
+	return nil
+}
+
+// fetch fetches the keys; keys that are not signing keys or can't be parsed are skipped.
+func (j *JWKS) fetch() (map[string]interface{}, error) {
+	res, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, exception.New(ErrJWKSFetch).WithInner(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, exception.New(ErrJWKSFetch).WithMessagef("url: %s; status: %d", j.url, res.StatusCode)
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, exception.New(ErrJWKSFetch).WithInner(err)
+	}
+
+	keys := map[string]interface{}{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,197 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/jwt"
+)
+
+func jwksTestRSAKey(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		KeyType: "RSA",
+		KeyID:   kid,
+		Use:     "sig",
+		N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func jwksTestECKey(kid string, key *ecdsa.PublicKey) JWK {
+	return JWK{
+		KeyType: "EC",
+		KeyID:   kid,
+		Curve:   "P-256",
+		X:       base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:       base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+}
+
+func jwksTestSign(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	output, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return output
+}
+
+func TestJWKSKeyFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(err)
+	rotatedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+
+	keys := []JWK{
+		jwksTestRSAKey("rsa-1", &rsaKey.PublicKey),
+		jwksTestECKey("ec-1", &ecKey.PublicKey),
+		{KeyType: "RSA", KeyID: "enc-1", Use: "enc"},
+	}
+	var fetches, rotated, failing int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if atomic.LoadInt32(&rotated) == 1 {
+			json.NewEncoder(rw).Encode(map[string]interface{}{"keys": append(keys, jwksTestRSAKey("rsa-2", &rotatedKey.PublicKey))})
+			return
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	jwks := NewJWKS(server.URL)
+	jwks.now = func() time.Time { return now }
+	auth := NewJWTAuth(jwks.KeyFunc).WithAlgorithms(jwt.SigningMethodNameRS256, jwt.SigningMethodNameES256)
+
+	claims, err := auth.Validate(jwksTestSign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, jwt.MapClaims{"sub": "user-1"}))
+	assert.Nil(err)
+	assert.Equal("user-1", claims.Subject())
+
+	claims, err = auth.Validate(jwksTestSign(t, jwt.SigningMethodES256, "ec-1", ecKey, jwt.MapClaims{"sub": "user-2"}))
+	assert.Nil(err)
+	assert.Equal("user-2", claims.Subject())
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+
+	_, err = jwks.Key("enc-1")
+	assert.True(exception.Is(err, ErrJWKSKeyNotFound))
+
+	// unknown key ids are only refetched once per refresh interval.
+	atomic.StoreInt32(&rotated, 1)
+	rotatedToken := jwksTestSign(t, jwt.SigningMethodRS256, "rsa-2", rotatedKey, jwt.MapClaims{"sub": "user-3"})
+	_, err = auth.Validate(rotatedToken)
+	assert.True(exception.Is(err, ErrJWKSKeyNotFound))
+	assert.Equal(1, atomic.LoadInt32(&fetches))
+
+	now = now.Add(DefaultJWKSRefreshInterval)
+	claims, err = auth.Validate(rotatedToken)
+	assert.Nil(err)
+	assert.Equal("user-3", claims.Subject())
+	assert.Equal(2, atomic.LoadInt32(&fetches))
+
+	// cached keys are used if a refresh fails.
+	atomic.StoreInt32(&failing, 1)
+	now = now.Add(DefaultJWKSCacheTTL)
+	_, err = auth.Validate(rotatedToken)
+	assert.Nil(err)
+	assert.Equal(3, atomic.LoadInt32(&fetches))
+
+	// failed fetches are only retried once per refresh interval.
+	_, err = auth.Validate(rotatedToken)
+	assert.Nil(err)
+	_, err = jwks.Key("unknown")
+	assert.True(exception.Is(err, ErrJWKSKeyNotFound))
+	assert.Equal(3, atomic.LoadInt32(&fetches))
+
+	now = now.Add(DefaultJWKSRefreshInterval)
+	_, err = auth.Validate(rotatedToken)
+	assert.Nil(err)
+	assert.Equal(4, atomic.LoadInt32(&fetches))
+}
+
+func TestJWKSServesCachedKeysWhileFetching(t *testing.T) {
+	assert := assert.New(t)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(err)
+
+	var fetches int32
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			<-unblock
+		}
+		json.NewEncoder(rw).Encode(map[string]interface{}{"keys": []JWK{jwksTestRSAKey("rsa-1", &rsaKey.PublicKey)}})
+	}))
+	defer server.Close()
+
+	var now atomic.Value
+	now.Store(time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC))
+	jwks := NewJWKS(server.URL)
+	jwks.now = func() time.Time { return now.Load().(time.Time) }
+
+	_, err = jwks.Key("rsa-1")
+	assert.Nil(err)
+
+	now.Store(now.Load().(time.Time).Add(DefaultJWKSCacheTTL))
+	refreshed := make(chan error)
+	go func() {
+		_, err := jwks.Key("rsa-1")
+		refreshed <- err
+	}()
+	assert.Eventually(func() bool { return atomic.LoadInt32(&fetches) == 2 }, time.Second, time.Millisecond)
+
+	key, err := jwks.Key("rsa-1")
+	assert.Nil(err, "cached keys should be served while a fetch is in flight")
+	assert.NotNil(key)
+	assert.Equal(2, atomic.LoadInt32(&fetches), "only one fetch should run at a time")
+
+	close(unblock)
+	assert.Nil(<-refreshed)
+}
+
+func TestJWKSFetchError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	jwks := NewJWKS(server.URL)
+	_, err := jwks.Key("rsa-1")
+	assert.True(exception.Is(err, ErrJWKSFetch))
+	_, err = jwks.Key("rsa-1")
+	assert.True(exception.Is(err, ErrJWKSFetch), "the last error should be returned until the next fetch")
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := JWK{KeyType: "oct", KeyID: "hmac-1"}.PublicKey()
+	assert.True(exception.Is(err, ErrJWKSInvalidKey))
+	_, err = JWK{KeyType: "EC", Curve: "P-256", X: "AQ", Y: "AQ"}.PublicKey()
+	assert.True(exception.Is(err, ErrJWKSInvalidKey))
+	_, err = JWK{KeyType: "RSA", N: "not base64!", E: "AQAB"}.PublicKey()
+	assert.True(exception.Is(err, ErrJWKSInvalidKey))
+}
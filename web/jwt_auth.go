@@ -0,0 +1,166 @@
+package web
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/jwt"
+)
+
+const (
+	// HeaderAuthorization is the "Authorization" header.
+	HeaderAuthorization = "Authorization"
+	// HeaderWWWAuthenticate is the "WWW-Authenticate" header.
+	HeaderWWWAuthenticate = "WWW-Authenticate"
+
+	// DefaultJWTClockSkew is the default tolerance for time based claims.
+	DefaultJWTClockSkew = time.Minute
+)
+
+// NewJWTAuth returns a new jwt auth middleware that validates tokens with keys from a key func,
+// e.g. `NewJWKS(url).KeyFunc`, or `func(*jwt.Token) (interface{}, error) { return secret, nil }`.
+func NewJWTAuth(keyFunc jwt.Keyfunc) *JWTAuth {
+	return &JWTAuth{
+		keyFunc:   keyFunc,
+		clockSkew: DefaultJWTClockSkew,
+		now:       time.Now,
+	}
+}
+
+// JWTAuth is a middleware that validates `Authorization: Bearer <token>` jwts
+// and sets their claims on the request context, see `Ctx.JWTClaims`.
+// Requests without a valid token get a 401 with a `WWW-Authenticate` challenge.
+type JWTAuth struct {
+	keyFunc    jwt.Keyfunc
+	audience   string
+	issuer     string
+	clockSkew  time.Duration
+	algorithms []string
+	now        func() time.Time
+}
+
+// KeyFunc returns the key func.
+func (ja *JWTAuth) KeyFunc() jwt.Keyfunc {
+	return ja.keyFunc
+}
+
+// WithAudience sets the audience tokens must be issued for.
+func (ja *JWTAuth) WithAudience(audience string) *JWTAuth {
+	ja.audience = audience
+	return ja
+}
+
+// Audience returns the audience tokens must be issued for.
+func (ja *JWTAuth) Audience() string {
+	return ja.audience
+}
+
+// WithIssuer sets the issuer tokens must be issued by.
+func (ja *JWTAuth) WithIssuer(issuer string) *JWTAuth {
+	ja.issuer = issuer
+	return ja
+}
+
+// Issuer returns the issuer tokens must be issued by.
+func (ja *JWTAuth) Issuer() string {
+	return ja.issuer
+}
+
+// WithClockSkew sets the tolerance for the `exp`, `nbf` and `iat` claims.
+func (ja *JWTAuth) WithClockSkew(skew time.Duration) *JWTAuth {
+	ja.clockSkew = skew
+	return ja
+}
+
+// ClockSkew returns the tolerance for time based claims.
+func (ja *JWTAuth) ClockSkew() time.Duration {
+	return ja.clockSkew
+}
+
+// WithAlgorithms sets the signing methods tokens can use, e.g. `jwt.SigningMethodNameRS256`.
+// If unset, any signing method the key supports is allowed.
+func (ja *JWTAuth) WithAlgorithms(algorithms ...string) *JWTAuth {
+	ja.algorithms = algorithms
+	return ja
+}
+
+// Algorithms returns the signing methods tokens can use.
+func (ja *JWTAuth) Algorithms() []string {
+	return ja.algorithms
+}
+
+// Middleware returns the action wrapped so it requires a valid token.
+func (ja *JWTAuth) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		tokenString := BearerToken(ctx)
+		if len(tokenString) == 0 {
+			ctx.Response().Header().Set(HeaderWWWAuthenticate, "Bearer")
+			return ctx.DefaultResultProvider().Status(http.StatusUnauthorized)
+		}
+		claims, err := ja.Validate(tokenString)
+		if err != nil {
+			if exception.Is(err, ErrJWKSFetch) && ctx.log != nil {
+				ctx.log.Error(err)
+			}
+			ctx.Response().Header().Set(HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return ctx.DefaultResultProvider().Status(http.StatusUnauthorized)
+		}
+		ctx.WithContext(WithJWTClaimsContext(ctx.Context(), claims))
+		return action(ctx)
+	}
+}
+
+// Validate parses a token, verifies its signature and validates its claims.
+// Validation failures are `jwt.ErrValidation` errors; errors from the key func are returned as is.
+func (ja *JWTAuth) Validate(tokenString string) (JWTClaims, error) {
+	parser := jwt.Parser{
+		ValidMethods:         ja.algorithms,
+		SkipClaimsValidation: true,
+	}
+	token, err := parser.ParseWithClaims(tokenString, jwt.MapClaims{}, ja.keyFunc)
+	if err != nil {
+		return nil, err
+	}
+	claims := JWTClaims(token.Claims.(jwt.MapClaims))
+	if err := ja.validateClaims(claims); err != nil {
+		return nil, exception.New(jwt.ErrValidation).WithInner(err)
+	}
+	return claims, nil
+}
+
+func (ja *JWTAuth) validateClaims(claims JWTClaims) error {
+	now := ja.now()
+	if claims.Has("exp") && now.Add(-ja.clockSkew).After(claims.ExpiresAt()) {
+		return exception.New(jwt.ErrValidationExpired).WithMessagef("token is expired by %v", now.Sub(claims.ExpiresAt()))
+	}
+	if claims.Has("nbf") && now.Add(ja.clockSkew).Before(claims.NotBefore()) {
+		return exception.New(jwt.ErrValidationNotBefore)
+	}
+	if claims.Has("iat") && now.Add(ja.clockSkew).Before(claims.IssuedAt()) {
+		return exception.New(jwt.ErrValidationIssued)
+	}
+	if len(ja.issuer) > 0 && subtle.ConstantTimeCompare([]byte(claims.Issuer()), []byte(ja.issuer)) != 1 {
+		return exception.New(ErrJWTIssuer).WithMessagef("issuer: %s", claims.Issuer())
+	}
+	if len(ja.audience) > 0 {
+		for _, audience := range claims.Audience() {
+			if subtle.ConstantTimeCompare([]byte(audience), []byte(ja.audience)) == 1 {
+				return nil
+			}
+		}
+		return exception.New(ErrJWTAudience).WithMessagef("audience: %s", strings.Join(claims.Audience(), ", "))
+	}
+	return nil
+}
+
+// BearerToken returns the token from an `Authorization: Bearer <token>` header, or an empty string.
+func BearerToken(ctx *Ctx) string {
+	header := ctx.Request().Header.Get(HeaderAuthorization)
+	if len(header) < len("bearer ") || !strings.EqualFold(header[:len("bearer ")], "bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(header[len("bearer "):])
+}
@@ -0,0 +1,146 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/jwt"
+)
+
+var jwtAuthTestKey = []byte("a super secret key")
+
+func jwtAuthTestKeyFunc(_ *jwt.Token) (interface{}, error) {
+	return jwtAuthTestKey, nil
+}
+
+func jwtAuthTestToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHMAC256, claims).SignedString(jwtAuthTestKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTAuthValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	auth := NewJWTAuth(jwtAuthTestKeyFunc).
+		WithAudience("api").
+		WithIssuer("https://auth.example.com/").
+		WithClockSkew(30 * time.Second)
+	auth.now = func() time.Time { return now }
+
+	claims, err := auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "https://auth.example.com/",
+		"aud": []string{"web", "api"},
+		"exp": now.Add(-20 * time.Second).Unix(),
+		"iat": now.Add(20 * time.Second).Unix(),
+	}))
+	assert.Nil(err)
+	assert.Equal("user-1", claims.Subject())
+	assert.Equal([]string{"web", "api"}, claims.Audience())
+	assert.Equal(now.Add(-20*time.Second), claims.ExpiresAt())
+
+	_, err = auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://auth.example.com/",
+		"aud": "api",
+		"exp": now.Add(-time.Minute).Unix(),
+	}))
+	assert.True(jwt.IsValidation(err))
+	assert.True(exception.Is(exception.Inner(err), jwt.ErrValidationExpired))
+
+	_, err = auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://auth.example.com/",
+		"aud": "api",
+		"nbf": now.Add(time.Minute).Unix(),
+	}))
+	assert.True(exception.Is(exception.Inner(err), jwt.ErrValidationNotBefore))
+
+	_, err = auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://evil.example.com/",
+		"aud": "api",
+	}))
+	assert.True(exception.Is(exception.Inner(err), ErrJWTIssuer))
+
+	_, err = auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://auth.example.com/",
+		"aud": "web",
+	}))
+	assert.True(exception.Is(exception.Inner(err), ErrJWTAudience))
+
+	_, err = auth.Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://auth.example.com/",
+	}))
+	assert.True(exception.Is(exception.Inner(err), ErrJWTAudience))
+
+	// only the allowed algorithms are accepted.
+	_, err = auth.WithAlgorithms(jwt.SigningMethodNameRS256).Validate(jwtAuthTestToken(t, jwt.MapClaims{
+		"iss": "https://auth.example.com/",
+		"aud": "api",
+	}))
+	assert.True(jwt.IsValidation(err))
+}
+
+func TestJWTAuthMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	auth := NewJWTAuth(jwtAuthTestKeyFunc)
+	app := New()
+	app.GET("/", func(r *Ctx) Result {
+		claims := r.JWTClaims()
+		return r.Text().Result(claims.Subject() + " " + claims.String("role"))
+	}, auth.Middleware)
+
+	contents, meta, err := app.Mock().WithPathf("/").
+		WithHeader(HeaderAuthorization, "Bearer "+jwtAuthTestToken(t, jwt.MapClaims{"sub": "user-1", "role": "admin"})).
+		BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("user-1 admin", string(contents))
+
+	_, meta, err = app.Mock().WithPathf("/").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Equal("Bearer", meta.Headers.Get(HeaderWWWAuthenticate))
+
+	_, meta, err = app.Mock().WithPathf("/").WithHeader(HeaderAuthorization, "Bearer not.a.token").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+	assert.Equal(`Bearer error="invalid_token"`, meta.Headers.Get(HeaderWWWAuthenticate))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHMAC256, jwt.MapClaims{"sub": "user-1"}).SignedString([]byte("wrong key"))
+	assert.Nil(err)
+	_, meta, err = app.Mock().WithPathf("/").WithHeader(HeaderAuthorization, "Bearer "+token).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusUnauthorized, meta.StatusCode)
+}
+
+func TestJWTClaims(t *testing.T) {
+	assert := assert.New(t)
+
+	claims := JWTClaims{
+		"sub":    "user-1",
+		"aud":    "api",
+		"scopes": []interface{}{"read", "write"},
+		"admin":  true,
+		"level":  float64(3),
+		"org":    map[string]interface{}{"id": "org-1"},
+	}
+	assert.Equal("user-1", claims.Subject())
+	assert.Equal([]string{"api"}, claims.Audience())
+	assert.Equal([]string{"read", "write"}, claims.Strings("scopes"))
+	assert.True(claims.Bool("admin"))
+	assert.Equal(3, claims.Int64("level"))
+	assert.Equal("org-1", claims.Map("org")["id"])
+	assert.Empty(claims.String("admin"))
+	assert.True(claims.ExpiresAt().IsZero())
+
+	var empty JWTClaims
+	assert.Empty(empty.Subject())
+	assert.Nil(GetJWTClaimsFromContext(nil))
+}
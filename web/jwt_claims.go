@@ -0,0 +1,146 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/blend/go-sdk/jwt"
+)
+
+type jwtClaimsContextKey struct{}
+
+// WithJWTClaimsContext returns a context with a given set of jwt claims.
+func WithJWTClaimsContext(ctx context.Context, claims JWTClaims) context.Context {
+	return context.WithValue(ctx, jwtClaimsContextKey{}, claims)
+}
+
+// GetJWTClaimsFromContext returns the jwt claims from a context, if they are set.
+// Claims validated by `JWTAuth` are set on the request context.
+func GetJWTClaimsFromContext(ctx context.Context) JWTClaims {
+	if ctx == nil {
+		return nil
+	}
+	if claims, ok := ctx.Value(jwtClaimsContextKey{}).(JWTClaims); ok {
+		return claims
+	}
+	return nil
+}
+
+// JWTClaims returns the jwt claims for the request, if they were set by `JWTAuth`.
+func (rc *Ctx) JWTClaims() JWTClaims {
+	return GetJWTClaimsFromContext(rc.Context())
+}
+
+// JWTClaims are the claims of a validated jwt with typed accessors.
+// Accessors return the zero value if a claim is unset or is of a different type.
+type JWTClaims jwt.MapClaims
+
+// Subject returns the `sub` claim.
+func (jc JWTClaims) Subject() string {
+	return jc.String("sub")
+}
+
+// Issuer returns the `iss` claim.
+func (jc JWTClaims) Issuer() string {
+	return jc.String("iss")
+}
+
+// ID returns the `jti` claim.
+func (jc JWTClaims) ID() string {
+	return jc.String("jti")
+}
+
+// Audience returns the `aud` claim, which can be a single value or a list.
+func (jc JWTClaims) Audience() []string {
+	if audience := jc.String("aud"); audience != "" {
+		return []string{audience}
+	}
+	return jc.Strings("aud")
+}
+
+// ExpiresAt returns the `exp` claim.
+func (jc JWTClaims) ExpiresAt() time.Time {
+	return jc.Time("exp")
+}
+
+// IssuedAt returns the `iat` claim.
+func (jc JWTClaims) IssuedAt() time.Time {
+	return jc.Time("iat")
+}
+
+// NotBefore returns the `nbf` claim.
+func (jc JWTClaims) NotBefore() time.Time {
+	return jc.Time("nbf")
+}
+
+// Has returns if a claim is set.
+func (jc JWTClaims) Has(key string) bool {
+	_, ok := jc[key]
+	return ok
+}
+
+// String returns a string claim.
+func (jc JWTClaims) String(key string) string {
+	value, _ := jc[key].(string)
+	return value
+}
+
+// Strings returns a list of strings claim, e.g. `scope` as a list.
+func (jc JWTClaims) Strings(key string) []string {
+	values, ok := jc[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	var output []string
+	for _, value := range values {
+		if typed, ok := value.(string); ok {
+			output = append(output, typed)
+		}
+	}
+	return output
+}
+
+// Int64 returns an integer claim.
+func (jc JWTClaims) Int64(key string) int64 {
+	switch typed := jc[key].(type) {
+	case float64:
+		return int64(typed)
+	case json.Number:
+		value, _ := typed.Int64()
+		return value
+	}
+	return 0
+}
+
+// Float64 returns a number claim.
+func (jc JWTClaims) Float64(key string) float64 {
+	switch typed := jc[key].(type) {
+	case float64:
+		return typed
+	case json.Number:
+		value, _ := typed.Float64()
+		return value
+	}
+	return 0
+}
+
+// Bool returns a boolean claim.
+func (jc JWTClaims) Bool(key string) bool {
+	value, _ := jc[key].(bool)
+	return value
+}
+
+// Time returns a claim in seconds since the epoch as a time in utc.
+func (jc JWTClaims) Time(key string) time.Time {
+	if !jc.Has(key) {
+		return time.Time{}
+	}
+	return time.Unix(jc.Int64(key), 0).UTC()
+}
+
+// Map returns a nested object claim.
+func (jc JWTClaims) Map(key string) map[string]interface{} {
+	value, _ := jc[key].(map[string]interface{})
+	return value
+}
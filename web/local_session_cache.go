@@ -14,7 +14,7 @@ func NewLocalSessionCache() *LocalSessionCache {
 }
 
 // LocalSessionCache is a memory cache of sessions.
-// It is meant to be used in tests.
+// It implements `SessionStore`, and is meant to be used in tests or single instance apps.
 type LocalSessionCache struct {
 	SessionLock *sync.Mutex
 	Sessions    map[string]*Session
@@ -37,6 +37,28 @@ func (lsc *LocalSessionCache) RemoveHandler(_ context.Context, sessionID string,
 	return nil
 }
 
+// FetchSession implements SessionStore; expired sessions are removed and not returned.
+func (lsc *LocalSessionCache) FetchSession(_ context.Context, sessionID string) (*Session, error) {
+	session := lsc.Get(sessionID)
+	if session.IsExpired() {
+		lsc.Remove(sessionID)
+		return nil, nil
+	}
+	return session, nil
+}
+
+// PersistSession implements SessionStore.
+func (lsc *LocalSessionCache) PersistSession(_ context.Context, session *Session) error {
+	lsc.Upsert(session)
+	return nil
+}
+
+// RemoveSession implements SessionStore.
+func (lsc *LocalSessionCache) RemoveSession(_ context.Context, sessionID string) error {
+	lsc.Remove(sessionID)
+	return nil
+}
+
 // Upsert adds or updates a session to the cache.
 func (lsc *LocalSessionCache) Upsert(session *Session) {
 	lsc.SessionLock.Lock()
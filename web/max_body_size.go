@@ -0,0 +1,91 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// WithMaxBodySize returns middleware that limits request bodies to a given number of bytes,
+// responding with a 413 if the action reads past the limit.
+/*
+It can be set for every route with `app.Use`, and raised for the routes that need it:
+
+	app.Use(web.WithMaxBodySize(1 << 20))
+	uploads := app.Group("/uploads", web.WithMaxBodySize(100 << 20))
+
+Unlike timeouts, the limit closest to the action applies, so routes and groups can raise or lower a limit set
+outside them. Requests whose `Content-Length` is over the limit fail on the first read, without reading the body.
+Reads past the limit return an error `IsRequestBodyTooLarge` is true for, and the 413 replaces the action's result.
+*/
+func WithMaxBodySize(limit int64) Middleware {
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			if r.request.Body == nil || r.request.Body == http.NoBody {
+				return action(r)
+			}
+			body, ok := r.request.Body.(*maxBodySizeReader)
+			if ok {
+				body.limit = limit
+			} else {
+				body = &maxBodySizeReader{body: r.request.Body, limit: limit, contentLength: r.request.ContentLength}
+				r.request.Body = body
+			}
+
+			result := action(r)
+			if body.Exceeded() {
+				r.Response().Header().Set(HeaderConnection, "close")
+				return r.DefaultResultProvider().Status(http.StatusRequestEntityTooLarge)
+			}
+			return result
+		}
+	}
+}
+
+// IsRequestBodyTooLarge returns if an error is from reading a request body past its size limit.
+func IsRequestBodyTooLarge(err error) bool {
+	return exception.Is(err, ErrRequestBodyTooLarge)
+}
+
+// maxBodySizeReader is a request body that returns an error once more than a limit is read.
+type maxBodySizeReader struct {
+	body          io.ReadCloser
+	limit         int64
+	contentLength int64
+	read          int64
+	exceeded      int32
+}
+
+// Read implements io.Reader.
+func (mr *maxBodySizeReader) Read(p []byte) (int, error) {
+	if mr.Exceeded() || mr.contentLength > mr.limit {
+		return 0, mr.tooLarge()
+	}
+	// read at most one byte past the limit, to tell if the body is over it.
+	if remaining := mr.limit - mr.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := mr.body.Read(p)
+	mr.read += int64(n)
+	if mr.read > mr.limit {
+		return n - int(mr.read-mr.limit), mr.tooLarge()
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (mr *maxBodySizeReader) Close() error {
+	return mr.body.Close()
+}
+
+// Exceeded returns if the body was read past the limit.
+func (mr *maxBodySizeReader) Exceeded() bool {
+	return atomic.LoadInt32(&mr.exceeded) == 1
+}
+
+func (mr *maxBodySizeReader) tooLarge() error {
+	atomic.StoreInt32(&mr.exceeded, 1)
+	return exception.New(ErrRequestBodyTooLarge).WithMessagef("limit: %d bytes", mr.limit)
+}
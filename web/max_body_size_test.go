@@ -0,0 +1,96 @@
+package web
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestWithMaxBodySize(t *testing.T) {
+	assert := assert.New(t)
+
+	var readErr error
+	app := New()
+	app.Use(WithMaxBodySize(8))
+	echo := func(r *Ctx) Result {
+		body, err := r.PostBody()
+		if err != nil {
+			readErr = err
+			return r.Text().BadRequest(err)
+		}
+		return r.Text().Result(string(body))
+	}
+	app.POST("/small", echo)
+	app.POST("/large", echo, WithMaxBodySize(16))
+
+	contents, meta, err := app.Mock().Post("/small").WithPostBody([]byte("12345678")).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("12345678", string(contents))
+
+	_, meta, err = app.Mock().Post("/small").WithPostBody([]byte("123456789")).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, meta.StatusCode)
+	assert.Equal("close", meta.Headers.Get(HeaderConnection))
+	assert.True(IsRequestBodyTooLarge(readErr))
+
+	// the route limit replaces the limit set with `app.Use`.
+	contents, meta, err = app.Mock().Post("/large").WithPostBody([]byte("0123456789abcdef")).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("0123456789abcdef", string(contents))
+
+	_, meta, err = app.Mock().Post("/large").WithPostBody([]byte("0123456789abcdefg")).BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusRequestEntityTooLarge, meta.StatusCode)
+}
+
+func TestWithMaxBodySizeContentLength(t *testing.T) {
+	assert := assert.New(t)
+
+	body := &countingReader{Reader: strings.NewReader(strings.Repeat("a", 1024))}
+	app := New()
+	app.POST("/", func(r *Ctx) Result {
+		_, err := r.PostBody()
+		return r.Text().BadRequest(err)
+	}, WithMaxBodySize(512))
+
+	req := httptest.NewRequest("POST", "/", body)
+	req.ContentLength = 1024
+	res := httptest.NewRecorder()
+	app.ServeHTTP(res, req)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.Code)
+	assert.Zero(body.read, "the body should not be read when the content length is over the limit")
+}
+
+func TestMaxBodySizeReader(t *testing.T) {
+	assert := assert.New(t)
+
+	reader := &maxBodySizeReader{body: ioutil.NopCloser(bytes.NewBufferString("0123456789")), limit: 4, contentLength: -1}
+	buffer := make([]byte, 3)
+	n, err := reader.Read(buffer)
+	assert.Nil(err)
+	assert.Equal(3, n)
+	n, err = reader.Read(buffer)
+	assert.True(IsRequestBodyTooLarge(err))
+	assert.Equal(1, n)
+	assert.True(reader.Exceeded())
+	_, err = reader.Read(buffer)
+	assert.True(IsRequestBodyTooLarge(err))
+}
+
+type countingReader struct {
+	*strings.Reader
+	read int
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.Reader.Read(p)
+	cr.read += n
+	return n, err
+}
@@ -21,6 +21,13 @@ func XMLProviderAsDefault(action Action) Action {
 	}
 }
 
+// NegotiatedProviderAsDefault sets the context.DefaultResultProvider() equal to context.Negotiated().
+func NegotiatedProviderAsDefault(action Action) Action {
+	return func(ctx *Ctx) Result {
+		return action(ctx.WithDefaultResultProvider(ctx.Negotiated()))
+	}
+}
+
 // TextProviderAsDefault sets the context.DefaultResultProvider() equal to context.Text().
 func TextProviderAsDefault(action Action) Action {
 	return func(ctx *Ctx) Result {
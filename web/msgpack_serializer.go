@@ -0,0 +1,254 @@
+package web
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+var (
+	_ Serializer = (*MsgPackSerializer)(nil)
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+// MsgPackSerializer serializes objects as MessagePack.
+/*
+Objects are written the way the json package would write them: struct fields are named by their `msgpack`
+tag, or their `json` tag, types that marshal themselves to json or text are written as that json or text,
+and byte slices are written as binary.
+*/
+type MsgPackSerializer struct{}
+
+// Serialize implements Serializer.
+func (MsgPackSerializer) Serialize(w io.Writer, object interface{}) error {
+	var buffer bytes.Buffer
+	if err := writeMsgPack(&buffer, reflect.ValueOf(object)); err != nil {
+		return err
+	}
+	_, err := w.Write(buffer.Bytes())
+	return exception.New(err)
+}
+
+func writeMsgPack(buffer *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buffer.WriteByte(0xc0)
+		return nil
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		buffer.WriteByte(0xc0)
+		return nil
+	}
+	if v.Type() == jsonNumberType {
+		return writeMsgPackJSONNumber(buffer, json.Number(v.String()))
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		contents, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return exception.New(err)
+		}
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+		var decoded interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return exception.New(err)
+		}
+		return writeMsgPack(buffer, reflect.ValueOf(decoded))
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return exception.New(err)
+		}
+		writeMsgPackString(buffer, string(text))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return writeMsgPack(buffer, v.Elem())
+	case reflect.Bool:
+		if v.Bool() {
+			buffer.WriteByte(0xc3)
+		} else {
+			buffer.WriteByte(0xc2)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeMsgPackInt(buffer, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeMsgPackUint(buffer, v.Uint())
+	case reflect.Float32:
+		buffer.WriteByte(0xca)
+		binary.Write(buffer, binary.BigEndian, math.Float32bits(float32(v.Float())))
+	case reflect.Float64:
+		buffer.WriteByte(0xcb)
+		binary.Write(buffer, binary.BigEndian, math.Float64bits(v.Float()))
+	case reflect.String:
+		writeMsgPackString(buffer, v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			buffer.WriteByte(0xc0)
+			return nil
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			writeMsgPackBinary(buffer, v.Bytes())
+			return nil
+		}
+		writeMsgPackLength(buffer, v.Len(), 0x90, 16, 0xdc, 0xdd)
+		for i := 0; i < v.Len(); i++ {
+			if err := writeMsgPack(buffer, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			buffer.WriteByte(0xc0)
+			return nil
+		}
+		// keys are sorted so the output is stable, like the json package.
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		writeMsgPackLength(buffer, len(keys), 0x80, 16, 0xde, 0xdf)
+		for _, key := range keys {
+			if err := writeMsgPack(buffer, key); err != nil {
+				return err
+			}
+			if err := writeMsgPack(buffer, v.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		var values []reflect.Value
+		var names []string
+		for _, field := range serializedFields(v.Type(), "msgpack", "json") {
+			value, ok := fieldByIndex(v, field.Index)
+			if !ok || (field.OmitEmpty && isEmptyValue(value)) {
+				continue
+			}
+			names = append(names, field.Name)
+			values = append(values, value)
+		}
+		writeMsgPackLength(buffer, len(values), 0x80, 16, 0xde, 0xdf)
+		for index, value := range values {
+			writeMsgPackString(buffer, names[index])
+			if err := writeMsgPack(buffer, value); err != nil {
+				return err
+			}
+		}
+	default:
+		return exception.New(ErrSerialize).WithMessagef("msgpack: unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+func writeMsgPackJSONNumber(buffer *bytes.Buffer, number json.Number) error {
+	if value, err := number.Int64(); err == nil {
+		writeMsgPackInt(buffer, value)
+		return nil
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return exception.New(err)
+	}
+	buffer.WriteByte(0xcb)
+	binary.Write(buffer, binary.BigEndian, math.Float64bits(value))
+	return nil
+}
+
+func writeMsgPackInt(buffer *bytes.Buffer, value int64) {
+	switch {
+	case value >= 0:
+		writeMsgPackUint(buffer, uint64(value))
+	case value >= -32:
+		buffer.WriteByte(byte(value))
+	case value >= math.MinInt8:
+		buffer.WriteByte(0xd0)
+		buffer.WriteByte(byte(value))
+	case value >= math.MinInt16:
+		buffer.WriteByte(0xd1)
+		binary.Write(buffer, binary.BigEndian, int16(value))
+	case value >= math.MinInt32:
+		buffer.WriteByte(0xd2)
+		binary.Write(buffer, binary.BigEndian, int32(value))
+	default:
+		buffer.WriteByte(0xd3)
+		binary.Write(buffer, binary.BigEndian, value)
+	}
+}
+
+func writeMsgPackUint(buffer *bytes.Buffer, value uint64) {
+	switch {
+	case value < 128:
+		buffer.WriteByte(byte(value))
+	case value <= math.MaxUint8:
+		buffer.WriteByte(0xcc)
+		buffer.WriteByte(byte(value))
+	case value <= math.MaxUint16:
+		buffer.WriteByte(0xcd)
+		binary.Write(buffer, binary.BigEndian, uint16(value))
+	case value <= math.MaxUint32:
+		buffer.WriteByte(0xce)
+		binary.Write(buffer, binary.BigEndian, uint32(value))
+	default:
+		buffer.WriteByte(0xcf)
+		binary.Write(buffer, binary.BigEndian, value)
+	}
+}
+
+func writeMsgPackString(buffer *bytes.Buffer, value string) {
+	switch length := len(value); {
+	case length < 32:
+		buffer.WriteByte(0xa0 | byte(length))
+	case length <= math.MaxUint8:
+		buffer.WriteByte(0xd9)
+		buffer.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buffer.WriteByte(0xda)
+		binary.Write(buffer, binary.BigEndian, uint16(length))
+	default:
+		buffer.WriteByte(0xdb)
+		binary.Write(buffer, binary.BigEndian, uint32(length))
+	}
+	buffer.WriteString(value)
+}
+
+func writeMsgPackBinary(buffer *bytes.Buffer, value []byte) {
+	switch length := len(value); {
+	case length <= math.MaxUint8:
+		buffer.WriteByte(0xc4)
+		buffer.WriteByte(byte(length))
+	case length <= math.MaxUint16:
+		buffer.WriteByte(0xc5)
+		binary.Write(buffer, binary.BigEndian, uint16(length))
+	default:
+		buffer.WriteByte(0xc6)
+		binary.Write(buffer, binary.BigEndian, uint32(length))
+	}
+	buffer.Write(value)
+}
+
+// writeMsgPackLength writes the header of an array or map, which is a "fix" type for small lengths.
+func writeMsgPackLength(buffer *bytes.Buffer, length int, fix byte, fixMax int, header16, header32 byte) {
+	switch {
+	case length < fixMax:
+		buffer.WriteByte(fix | byte(length))
+	case length <= math.MaxUint16:
+		buffer.WriteByte(header16)
+		binary.Write(buffer, binary.BigEndian, uint16(length))
+	default:
+		buffer.WriteByte(header32)
+		binary.Write(buffer, binary.BigEndian, uint32(length))
+	}
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestMsgPackSerializer(t *testing.T) {
+	assert := assert.New(t)
+
+	serialize := func(object interface{}) string {
+		var buffer bytes.Buffer
+		assert.Nil(MsgPackSerializer{}.Serialize(&buffer, object))
+		return buffer.String()
+	}
+
+	assert.Equal("\xc0", serialize(nil))
+	assert.Equal("\xc3", serialize(true))
+	assert.Equal("\x7f", serialize(127))
+	assert.Equal("\xcc\x80", serialize(128))
+	assert.Equal("\xcd\x01\x00", serialize(uint16(256)))
+	assert.Equal("\xe0", serialize(-32))
+	assert.Equal("\xd0\xdf", serialize(-33))
+	assert.Equal("\xd1\x80\x00", serialize(-32768))
+	assert.Equal("\xce\x00\x01\x00\x00", serialize(65536))
+	assert.Equal("\xd3\x80\x00\x00\x00\x00\x00\x00\x00", serialize(int64(-1<<63)))
+	assert.Equal("\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00", serialize(1.5))
+	assert.Equal("\xca\x3f\xc0\x00\x00", serialize(float32(1.5)))
+	assert.Equal("\xa3foo", serialize("foo"))
+	assert.Equal("\xd9\x20"+strings.Repeat("a", 32), serialize(strings.Repeat("a", 32)))
+	assert.Equal("\xc4\x02\x01\x02", serialize([]byte{1, 2}))
+	assert.Equal("\x93\x01\x02\x03", serialize([]int{1, 2, 3}))
+	assert.Equal("\xc0", serialize([]int(nil)))
+	assert.Equal("\x82\xa1a\x01\xa1b\x02", serialize(map[string]int{"b": 2, "a": 1}))
+	assert.Equal("\xb42020-01-02T03:04:05Z", serialize(time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)))
+	assert.Equal("\x82\xa1a\x01\xa1b\xcb\x3f\xf8\x00\x00\x00\x00\x00\x00", serialize(json.RawMessage(`{"a":1,"b":1.5}`)))
+
+	type base struct {
+		ID int `json:"id"`
+	}
+	type object struct {
+		base
+		Name     string `msgpack:"n" json:"name"`
+		Nickname string `json:"nickname,omitempty"`
+		Secret   string `json:"-"`
+		Tags     []string
+		hidden   string
+	}
+	assert.Equal("\x83\xa1n\xa6bailey\xa4Tags\x91\xa1x\xa2id\x01", serialize(&object{base: base{ID: 1}, Name: "bailey", Secret: "s", Tags: []string{"x"}, hidden: "h"}))
+
+	var buffer bytes.Buffer
+	assert.NotNil(MsgPackSerializer{}.Serialize(&buffer, make(chan int)))
+}
@@ -0,0 +1,78 @@
+package web
+
+import (
+	"net/http"
+)
+
+var (
+	// Negotiated is a static singleton result provider that negotiates the media type of results.
+	Negotiated NegotiatedResultProvider
+
+	_ ResultProvider = (*NegotiatedResultProvider)(nil)
+)
+
+// NegotiatedResultProvider are context results for api methods that serve more than one media type,
+// serialized in the media type the request accepts.
+type NegotiatedResultProvider struct{}
+
+// NotFound returns a service response.
+func (nrp NegotiatedResultProvider) NotFound() Result {
+	return &NegotiatedResult{
+		StatusCode: http.StatusNotFound,
+		Response:   "Not Found",
+	}
+}
+
+// NotAuthorized returns a service response.
+func (nrp NegotiatedResultProvider) NotAuthorized() Result {
+	return &NegotiatedResult{
+		StatusCode: http.StatusForbidden,
+		Response:   "Not Authorized",
+	}
+}
+
+// InternalError returns a service response.
+func (nrp NegotiatedResultProvider) InternalError(err error) Result {
+	return resultWithLoggedError(&NegotiatedResult{
+		StatusCode: http.StatusInternalServerError,
+		Response:   err,
+	}, err)
+}
+
+// BadRequest returns a service response.
+func (nrp NegotiatedResultProvider) BadRequest(err error) Result {
+	if err != nil {
+		return &NegotiatedResult{
+			StatusCode: http.StatusBadRequest,
+			Response:   err,
+		}
+	}
+	return &NegotiatedResult{
+		StatusCode: http.StatusBadRequest,
+		Response:   "Bad Request",
+	}
+}
+
+// OK returns a service response.
+func (nrp NegotiatedResultProvider) OK() Result {
+	return &NegotiatedResult{
+		StatusCode: http.StatusOK,
+		Response:   "OK!",
+	}
+}
+
+// Status returns a plaintext result.
+func (nrp NegotiatedResultProvider) Status(statusCode int, response ...interface{}) Result {
+	return &NegotiatedResult{
+		StatusCode: statusCode,
+		Response:   ResultOrDefault(http.StatusText(statusCode), response...),
+	}
+}
+
+// Result returns a negotiated response.
+func (nrp NegotiatedResultProvider) Result(response interface{}) Result {
+	return &NegotiatedResult{
+		StatusCode: http.StatusOK,
+		Response:   response,
+	}
+}
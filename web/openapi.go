@@ -0,0 +1,580 @@
+package web
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOpenAPIPath is the default path the openapi document is served at.
+	DefaultOpenAPIPath = "/openapi.json"
+	// OpenAPIVersion is the openapi specification version documents are generated for.
+	OpenAPIVersion = "3.0.3"
+
+	// FieldTagPath is the struct tag that documents a field as a route parameter.
+	FieldTagPath = "path"
+	// FieldTagHeader is the struct tag that documents a field as a header.
+	FieldTagHeader = "header"
+)
+
+// NewOpenAPI returns a new openapi document generator.
+func NewOpenAPI(title, version string) *OpenAPI {
+	return &OpenAPI{
+		path:       DefaultOpenAPIPath,
+		info:       OpenAPIInfo{Title: title, Version: version},
+		operations: map[string]*OpenAPIOperation{},
+	}
+}
+
+// OpenAPI generates an openapi 3 document from an app's routes.
+/*
+Every registered route is documented with its route parameters; describe operations further
+with their parameter, request and response types:
+
+	spec := web.NewOpenAPI("Users", "1.0.0")
+	spec.Operation("GET", "/users/:id").WithSummary("Get a user").WithParams(getUserParams{}).WithResponse(http.StatusOK, User{})
+	spec.Operation("POST", "/users").WithRequest(CreateUser{}).WithResponse(http.StatusCreated, User{})
+	app.ServeOpenAPI(spec)
+
+Parameter types use `path`, `query` and `header` struct tags, and request and response types use
+`json` tags. `validate` rules, as used by `Ctx.Bind`, are documented as schema constraints.
+*/
+type OpenAPI struct {
+	path       string
+	info       OpenAPIInfo
+	servers    []OpenAPIServer
+	operations map[string]*OpenAPIOperation
+}
+
+// WithPath sets the path the document is served at.
+func (o *OpenAPI) WithPath(path string) *OpenAPI {
+	o.path = path
+	return o
+}
+
+// Path returns the path the document is served at.
+func (o *OpenAPI) Path() string {
+	return o.path
+}
+
+// WithDescription sets the api description.
+func (o *OpenAPI) WithDescription(description string) *OpenAPI {
+	o.info.Description = description
+	return o
+}
+
+// Info returns the api info.
+func (o *OpenAPI) Info() OpenAPIInfo {
+	return o.info
+}
+
+// WithServer adds a server url the api is available at.
+func (o *OpenAPI) WithServer(url, description string) *OpenAPI {
+	o.servers = append(o.servers, OpenAPIServer{URL: url, Description: description})
+	return o
+}
+
+// Servers returns the server urls the api is available at.
+func (o *OpenAPI) Servers() []OpenAPIServer {
+	return o.servers
+}
+
+// Operation returns the operation for a route as it is registered, e.g. `/users/:id`, creating it if required.
+func (o *OpenAPI) Operation(method, path string) *OpenAPIOperation {
+	key := method + " " + path
+	if operation, ok := o.operations[key]; ok {
+		return operation
+	}
+	operation := &OpenAPIOperation{}
+	o.operations[key] = operation
+	return operation
+}
+
+// Document returns the openapi document for an app's routes.
+func (o *OpenAPI) Document(app *App) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    o.info,
+		Servers: o.servers,
+		Paths:   map[string]map[string]*OpenAPIOperation{},
+	}
+	schemas := &openAPISchemas{schemas: map[reflect.Type]*OpenAPISchema{}, refs: map[reflect.Type][]*OpenAPISchema{}}
+
+	for _, route := range app.Routes() {
+		if route.Path == o.path {
+			continue
+		}
+		operation, ok := o.operations[route.Method+" "+route.Path]
+		if !ok {
+			operation = &OpenAPIOperation{}
+		}
+		if operation.hidden {
+			continue
+		}
+		path, pathParams := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*OpenAPIOperation{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation.document(pathParams, schemas)
+	}
+	if len(schemas.schemas) > 0 {
+		doc.Components = &OpenAPIComponents{Schemas: schemas.components()}
+	}
+	return doc
+}
+
+// ServeOpenAPI serves the openapi document for the app's routes at the document's path.
+func (a *App) ServeOpenAPI(spec *OpenAPI) {
+	a.GET(spec.Path(), func(ctx *Ctx) Result {
+		return ctx.JSON().Result(spec.Document(a))
+	})
+}
+
+// OpenAPIOperation documents a route.
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Parameters  []OpenAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+
+	params    interface{}
+	request   interface{}
+	responses map[int]interface{}
+	hidden    bool
+}
+
+// WithOperationID sets the operation id, which client generators use as the method name.
+func (oo *OpenAPIOperation) WithOperationID(operationID string) *OpenAPIOperation {
+	oo.OperationID = operationID
+	return oo
+}
+
+// WithSummary sets the summary.
+func (oo *OpenAPIOperation) WithSummary(summary string) *OpenAPIOperation {
+	oo.Summary = summary
+	return oo
+}
+
+// WithDescription sets the description.
+func (oo *OpenAPIOperation) WithDescription(description string) *OpenAPIOperation {
+	oo.Description = description
+	return oo
+}
+
+// WithTags sets the tags the operation is grouped by.
+func (oo *OpenAPIOperation) WithTags(tags ...string) *OpenAPIOperation {
+	oo.Tags = tags
+	return oo
+}
+
+// WithDeprecated sets if the operation is deprecated.
+func (oo *OpenAPIOperation) WithDeprecated(deprecated bool) *OpenAPIOperation {
+	oo.Deprecated = deprecated
+	return oo
+}
+
+// WithHidden sets if the operation is left out of the document.
+func (oo *OpenAPIOperation) WithHidden(hidden bool) *OpenAPIOperation {
+	oo.hidden = hidden
+	return oo
+}
+
+// WithParams sets a struct whose fields with `path`, `query` or `header` tags document the parameters.
+func (oo *OpenAPIOperation) WithParams(params interface{}) *OpenAPIOperation {
+	oo.params = params
+	return oo
+}
+
+// WithRequest sets the type of the json request body.
+func (oo *OpenAPIOperation) WithRequest(request interface{}) *OpenAPIOperation {
+	oo.request = request
+	return oo
+}
+
+// WithResponse sets the type of the json response body for a status code; use nil for no body.
+func (oo *OpenAPIOperation) WithResponse(statusCode int, response interface{}) *OpenAPIOperation {
+	if oo.responses == nil {
+		oo.responses = map[int]interface{}{}
+	}
+	oo.responses[statusCode] = response
+	return oo
+}
+
+// document returns a copy of the operation with its parameters, request body and responses filled in.
+func (oo *OpenAPIOperation) document(pathParams []string, schemas *openAPISchemas) *OpenAPIOperation {
+	output := &OpenAPIOperation{
+		OperationID: oo.OperationID,
+		Summary:     oo.Summary,
+		Description: oo.Description,
+		Tags:        oo.Tags,
+		Deprecated:  oo.Deprecated,
+		Responses:   map[string]*OpenAPIResponse{},
+	}
+
+	documented := map[string]OpenAPIParameter{}
+	var others []OpenAPIParameter
+	if oo.params != nil {
+		for _, param := range schemas.parameters(reflect.TypeOf(oo.params)) {
+			if param.In == FieldTagPath {
+				documented[param.Name] = param
+				continue
+			}
+			others = append(others, param)
+		}
+	}
+	for _, name := range pathParams {
+		param, ok := documented[name]
+		if !ok {
+			param = OpenAPIParameter{Name: name, In: FieldTagPath, Schema: &OpenAPISchema{Type: "string"}}
+		}
+		param.Required = true
+		output.Parameters = append(output.Parameters, param)
+	}
+	output.Parameters = append(output.Parameters, others...)
+
+	if oo.request != nil {
+		output.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]OpenAPIMediaType{
+				ContentTypeApplicationJSON: {Schema: schemas.schema(reflect.TypeOf(oo.request))},
+			},
+		}
+	}
+
+	for statusCode, response := range oo.responses {
+		documentedResponse := &OpenAPIResponse{Description: http.StatusText(statusCode)}
+		if response != nil {
+			documentedResponse.Content = map[string]OpenAPIMediaType{
+				ContentTypeApplicationJSON: {Schema: schemas.schema(reflect.TypeOf(response))},
+			}
+		}
+		output.Responses[strconv.Itoa(statusCode)] = documentedResponse
+	}
+	if len(output.Responses) == 0 {
+		output.Responses["default"] = &OpenAPIResponse{Description: "Response"}
+	}
+	return output
+}
+
+// OpenAPIDocument is an openapi document.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+}
+
+// OpenAPIInfo describes an api.
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIServer is a url an api is available at.
+type OpenAPIServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIParameter is a path, query or header parameter.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIRequestBody is a request body.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse is a response for a status code.
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType is the schema of a body for a content type.
+type OpenAPIMediaType struct {
+	Schema *OpenAPISchema `json:"schema,omitempty"`
+}
+
+// OpenAPIComponents are the named schemas referenced by a document.
+type OpenAPIComponents struct {
+	Schemas map[string]*OpenAPISchema `json:"schemas,omitempty"`
+}
+
+// OpenAPISchema is a json schema, as used by openapi.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+}
+
+// openAPIPath converts a route path to an openapi path, e.g. `/users/:id` to `/users/{id}`,
+// returning the names of its parameters.
+func openAPIPath(routePath string) (string, []string) {
+	var params []string
+	segments := strings.Split(routePath, "/")
+	for index, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			params = append(params, segment[1:])
+			segments[index] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// openAPIComponentName replaces the characters in a package path that aren't allowed in component names
+// (letters, digits, `.`, `-` and `_`) with `.`, e.g. `github.com/blend/go-sdk/web` to `github.com.blend.go-sdk.web`.
+func openAPIComponentName(pkgPath string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '.'
+	}, pkgPath)
+}
+
+var (
+	openAPITimeType     = reflect.TypeOf(time.Time{})
+	openAPIDurationType = reflect.TypeOf(time.Duration(0))
+)
+
+// openAPISchemas generates schemas, collecting named struct types as components.
+// Refs to components are named once all the types are collected, see `components`.
+type openAPISchemas struct {
+	schemas map[reflect.Type]*OpenAPISchema
+	refs    map[reflect.Type][]*OpenAPISchema
+}
+
+// components returns the collected component schemas by name, and names the refs to them.
+// Components are named by their type name, qualified with their package path if types from
+// different packages share a name, e.g. `github.com.blend.go-sdk.web.Session`.
+func (oas *openAPISchemas) components() map[string]*OpenAPISchema {
+	names := map[string]int{}
+	for schemaType := range oas.schemas {
+		names[schemaType.Name()]++
+	}
+	components := map[string]*OpenAPISchema{}
+	for schemaType, schema := range oas.schemas {
+		name := schemaType.Name()
+		if names[name] > 1 {
+			name = openAPIComponentName(schemaType.PkgPath()) + "." + name
+		}
+		components[name] = schema
+		for _, ref := range oas.refs[schemaType] {
+			ref.Ref = "#/components/schemas/" + name
+		}
+	}
+	return components
+}
+
+// parameters returns the parameters documented by a struct's `path`, `query` and `header` tags.
+func (oas *openAPISchemas) parameters(paramsType reflect.Type) []OpenAPIParameter {
+	for paramsType.Kind() == reflect.Ptr {
+		paramsType = paramsType.Elem()
+	}
+	if paramsType.Kind() != reflect.Struct {
+		return nil
+	}
+	var params []OpenAPIParameter
+	for x := 0; x < paramsType.NumField(); x++ {
+		field := paramsType.Field(x)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		for _, in := range []string{FieldTagPath, FieldTagQuery, FieldTagHeader} {
+			name := strings.Split(field.Tag.Get(in), ",")[0]
+			if len(name) == 0 || name == "-" {
+				continue
+			}
+			schema := oas.schema(field.Type)
+			rules := openAPIApplyRules(schema, field.Tag.Get(FieldTagValidate))
+			params = append(params, OpenAPIParameter{
+				Name:     name,
+				In:       in,
+				Required: rules.required,
+				Schema:   schema,
+			})
+		}
+	}
+	return params
+}
+
+// schema returns the schema for a type; named structs are references to components.
+func (oas *openAPISchemas) schema(valueType reflect.Type) *OpenAPISchema {
+	nullable := false
+	for valueType.Kind() == reflect.Ptr {
+		valueType = valueType.Elem()
+		nullable = true
+	}
+
+	switch valueType {
+	case openAPITimeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time", Nullable: nullable}
+	case openAPIDurationType:
+		return &OpenAPISchema{Type: "integer", Format: "int64", Nullable: nullable}
+	}
+
+	switch valueType.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean", Nullable: nullable}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return &OpenAPISchema{Type: "integer", Format: "int32", Nullable: nullable}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64", Nullable: nullable}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float", Nullable: nullable}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double", Nullable: nullable}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if valueType.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte", Nullable: nullable}
+		}
+		return &OpenAPISchema{Type: "array", Items: oas.schema(valueType.Elem()), Nullable: nullable}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: oas.schema(valueType.Elem()), Nullable: nullable}
+	case reflect.Struct:
+		if len(valueType.Name()) == 0 {
+			return oas.structSchema(valueType)
+		}
+		if _, ok := oas.schemas[valueType]; !ok {
+			// reserve the type first so recursive types terminate.
+			oas.schemas[valueType] = &OpenAPISchema{}
+			*oas.schemas[valueType] = *oas.structSchema(valueType)
+		}
+		ref := &OpenAPISchema{}
+		oas.refs[valueType] = append(oas.refs[valueType], ref)
+		return ref
+	}
+	return &OpenAPISchema{}
+}
+
+func (oas *openAPISchemas) structSchema(structType reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	oas.addProperties(schema, structType)
+	return schema
+}
+
+func (oas *openAPISchemas) addProperties(schema *OpenAPISchema, structType reflect.Type) {
+	for x := 0; x < structType.NumField(); x++ {
+		field := structType.Field(x)
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+		if field.Anonymous && len(tag[0]) == 0 {
+			embeddedType := field.Type
+			for embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				oas.addProperties(schema, embeddedType)
+				continue
+			}
+		}
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := field.Name
+		if len(tag[0]) > 0 {
+			name = tag[0]
+		}
+		property := oas.schema(field.Type)
+		if openAPIApplyRules(property, field.Tag.Get(FieldTagValidate)).required {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = property
+	}
+}
+
+type openAPIRules struct {
+	required bool
+}
+
+// openAPIApplyRules documents `validate` rules as schema constraints.
+// Constraints aren't set on references, as siblings of `$ref` are ignored.
+func openAPIApplyRules(schema *OpenAPISchema, rules string) (output openAPIRules) {
+	if len(rules) == 0 {
+		return
+	}
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		ruleName, ruleArg := rule, ""
+		if equals := strings.Index(rule, "="); equals >= 0 {
+			ruleName, ruleArg = rule[:equals], rule[equals+1:]
+		}
+		switch ruleName {
+		case ValidationRuleRequired:
+			output.required = true
+		case ValidationRuleMin, ValidationRuleMax:
+			openAPIApplyBound(schema, ruleName == ValidationRuleMin, ruleArg)
+		case ValidationRuleFormat:
+			if schema.Type != "string" {
+				continue
+			}
+			switch ruleArg {
+			case ValidationFormatEmail:
+				schema.Format = "email"
+			case ValidationFormatURL:
+				schema.Format = "uri"
+			case ValidationFormatUUID:
+				schema.Format = "uuid"
+			}
+		}
+	}
+	return
+}
+
+func openAPIApplyBound(schema *OpenAPISchema, isMin bool, arg string) {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return
+	}
+	length := int(bound)
+	switch schema.Type {
+	case "integer", "number":
+		if isMin {
+			schema.Minimum = &bound
+		} else {
+			schema.Maximum = &bound
+		}
+	case "string":
+		if isMin {
+			schema.MinLength = &length
+		} else {
+			schema.MaxLength = &length
+		}
+	case "array":
+		if isMin {
+			schema.MinItems = &length
+		} else {
+			schema.MaxItems = &length
+		}
+	}
+}
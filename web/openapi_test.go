@@ -0,0 +1,144 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type openAPITestUser struct {
+	ID        string            `json:"id"`
+	Email     string            `json:"email" validate:"required,format=email"`
+	Name      string            `json:"name" validate:"min=2,max=64"`
+	Age       int               `json:"age,omitempty" validate:"min=18"`
+	Tags      []string          `json:"tags" validate:"max=5"`
+	Manager   *openAPITestUser  `json:"manager,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Labels    map[string]string `json:"labels"`
+	Secret    string            `json:"-"`
+}
+
+type openAPITestUserParams struct {
+	ID      int    `path:"id" validate:"min=1"`
+	Expand  bool   `query:"expand"`
+	TraceID string `header:"X-Trace-ID" validate:"required"`
+}
+
+func newOpenAPITestApp() *App {
+	app := New()
+	app.GET("/users/:id", func(r *Ctx) Result { return r.NoContent() })
+	app.POST("/users", func(r *Ctx) Result { return r.NoContent() })
+	app.GET("/files/*filepath", func(r *Ctx) Result { return r.NoContent() })
+	app.DELETE("/internal/cache", func(r *Ctx) Result { return r.NoContent() })
+
+	spec := NewOpenAPI("Users", "1.0.0").WithServer("https://api.example.com", "production")
+	spec.Operation("GET", "/users/:id").
+		WithOperationID("getUser").
+		WithTags("users").
+		WithParams(openAPITestUserParams{}).
+		WithResponse(http.StatusOK, openAPITestUser{}).
+		WithResponse(http.StatusNotFound, nil)
+	spec.Operation("POST", "/users").WithRequest(&openAPITestUser{}).WithResponse(http.StatusBadRequest, BindError{})
+	spec.Operation("DELETE", "/internal/cache").WithHidden(true)
+	app.ServeOpenAPI(spec)
+	return app
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	assert := assert.New(t)
+
+	app := newOpenAPITestApp()
+	var doc OpenAPIDocument
+	assert.Nil(app.Mock().WithPathf(DefaultOpenAPIPath).JSON(&doc))
+	assert.Equal(OpenAPIVersion, doc.OpenAPI)
+	assert.Equal("Users", doc.Info.Title)
+	assert.Len(doc.Servers, 1)
+	assert.Len(doc.Paths, 3)
+	assert.Nil(doc.Paths[DefaultOpenAPIPath])
+	assert.Nil(doc.Paths["/internal/cache"])
+
+	getUser := doc.Paths["/users/{id}"]["get"]
+	assert.NotNil(getUser)
+	assert.Equal("getUser", getUser.OperationID)
+	assert.Len(getUser.Parameters, 3)
+	assert.Equal(OpenAPIParameter{Name: "id", In: "path", Required: true, Schema: &OpenAPISchema{Type: "integer", Format: "int64", Minimum: &[]float64{1}[0]}}, getUser.Parameters[0])
+	assert.Equal("expand", getUser.Parameters[1].Name)
+	assert.Equal("query", getUser.Parameters[1].In)
+	assert.False(getUser.Parameters[1].Required)
+	assert.Equal("header", getUser.Parameters[2].In)
+	assert.True(getUser.Parameters[2].Required)
+	assert.Equal("#/components/schemas/openAPITestUser", getUser.Responses["200"].Content[ContentTypeApplicationJSON].Schema.Ref)
+	assert.Equal("Not Found", getUser.Responses["404"].Description)
+	assert.Empty(getUser.Responses["404"].Content)
+
+	createUser := doc.Paths["/users"]["post"]
+	assert.NotNil(createUser.RequestBody)
+	assert.Equal("#/components/schemas/openAPITestUser", createUser.RequestBody.Content[ContentTypeApplicationJSON].Schema.Ref)
+	assert.Equal("#/components/schemas/BindError", createUser.Responses["400"].Content[ContentTypeApplicationJSON].Schema.Ref)
+
+	files := doc.Paths["/files/{filepath}"]["get"]
+	assert.Equal([]OpenAPIParameter{{Name: "filepath", In: "path", Required: true, Schema: &OpenAPISchema{Type: "string"}}}, files.Parameters)
+	assert.NotNil(files.Responses["default"])
+
+	user := doc.Components.Schemas["openAPITestUser"]
+	assert.NotNil(user)
+	assert.Equal([]string{"email"}, user.Required)
+	assert.Equal("email", user.Properties["email"].Format)
+	assert.Equal(2, *user.Properties["name"].MinLength)
+	assert.Equal(64, *user.Properties["name"].MaxLength)
+	assert.Equal(18, *user.Properties["age"].Minimum)
+	assert.Equal(5, *user.Properties["tags"].MaxItems)
+	assert.Equal("#/components/schemas/openAPITestUser", user.Properties["manager"].Ref)
+	assert.Equal("date-time", user.Properties["createdAt"].Format)
+	assert.Equal("string", user.Properties["labels"].AdditionalProperties.Type)
+	assert.Nil(user.Properties["Secret"])
+	assert.NotNil(doc.Components.Schemas["FieldError"])
+}
+
+func TestOpenAPIDocumentSchemaNames(t *testing.T) {
+	assert := assert.New(t)
+
+	// Cookie has the same name as `http.Cookie`, from another package.
+	type Cookie struct {
+		ID string `json:"id"`
+	}
+
+	app := New()
+	app.GET("/cookies", func(r *Ctx) Result { return r.NoContent() })
+	app.POST("/cookies", func(r *Ctx) Result { return r.NoContent() })
+	spec := NewOpenAPI("Cookies", "1.0.0")
+	spec.Operation("GET", "/cookies").WithResponse(http.StatusOK, []Cookie{})
+	spec.Operation("POST", "/cookies").WithRequest(http.Cookie{}).WithResponse(http.StatusOK, Cookie{})
+
+	doc := spec.Document(app)
+	assert.Len(doc.Components.Schemas, 2)
+	assert.NotNil(doc.Components.Schemas["github.com.blend.go-sdk.web.Cookie"])
+	assert.NotNil(doc.Components.Schemas["net.http.Cookie"])
+	assert.Equal("#/components/schemas/github.com.blend.go-sdk.web.Cookie", doc.Paths["/cookies"]["get"].Responses["200"].Content[ContentTypeApplicationJSON].Schema.Items.Ref)
+	assert.Equal("#/components/schemas/github.com.blend.go-sdk.web.Cookie", doc.Paths["/cookies"]["post"].Responses["200"].Content[ContentTypeApplicationJSON].Schema.Ref)
+	assert.Equal("#/components/schemas/net.http.Cookie", doc.Paths["/cookies"]["post"].RequestBody.Content[ContentTypeApplicationJSON].Schema.Ref)
+	assert.NotNil(doc.Components.Schemas["net.http.Cookie"].Properties["Name"])
+}
+
+func TestOpenAPIDocumentJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	app.GET("/", func(r *Ctx) Result { return r.NoContent() })
+	contents, err := json.Marshal(NewOpenAPI("Empty", "0.1.0").Document(app))
+	assert.Nil(err)
+	assert.Equal(`{"openapi":"3.0.3","info":{"title":"Empty","version":"0.1.0"},"paths":{"/":{"get":{"responses":{"default":{"description":"Response"}}}}}}`, string(contents))
+}
+
+func TestOpenAPIDocumentGolden(t *testing.T) {
+	assert := assert.New(t)
+
+	contents, err := newOpenAPITestApp().Mock().WithPathf(DefaultOpenAPIPath).Bytes()
+	assert.Nil(err)
+	var doc OpenAPIDocument
+	assert.Nil(json.Unmarshal(contents, &doc))
+	assert.MatchesGolden("openapi", doc)
+}
@@ -0,0 +1,297 @@
+package web
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/uuid"
+)
+
+// ParamError is returned by the typed param accessors if a parameter is missing or can't be parsed.
+// It marshals to json as a structured response, so it can be passed directly to `BadRequest(err)`.
+type ParamError struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (pe *ParamError) Error() string {
+	return fmt.Sprintf("`%s` parameter %s", pe.Param, pe.Message)
+}
+
+// IsParamError returns if an error is a param error, i.e. a parameter was missing or malformed.
+func IsParamError(err error) bool {
+	_, ok := err.(*ParamError)
+	return ok
+}
+
+// ParamInt returns a parameter, from any of the sources `Param` reads, as an int.
+func (rc *Ctx) ParamInt(name string) (output int, err error) {
+	err = rc.parseParam(name, "must be an integer", func(value string) (parseErr error) {
+		output, parseErr = strconv.Atoi(value)
+		return
+	})
+	return
+}
+
+// ParamInt64 returns a parameter, from any of the sources `Param` reads, as an int64.
+func (rc *Ctx) ParamInt64(name string) (output int64, err error) {
+	err = rc.parseParam(name, "must be an integer", func(value string) (parseErr error) {
+		output, parseErr = strconv.ParseInt(value, 10, 64)
+		return
+	})
+	return
+}
+
+// ParamFloat64 returns a parameter, from any of the sources `Param` reads, as a float64.
+func (rc *Ctx) ParamFloat64(name string) (output float64, err error) {
+	err = rc.parseParam(name, "must be a number", func(value string) (parseErr error) {
+		output, parseErr = strconv.ParseFloat(value, 64)
+		return
+	})
+	return
+}
+
+// ParamBool returns a parameter, from any of the sources `Param` reads, as a bool.
+// It accepts the same values as `BoolValue`.
+func (rc *Ctx) ParamBool(name string) (output bool, err error) {
+	err = rc.parseParam(name, "must be a boolean", func(value string) (parseErr error) {
+		output, parseErr = BoolValue(value, nil)
+		return
+	})
+	return
+}
+
+// ParamUUID returns a parameter, from any of the sources `Param` reads, as a uuid.
+func (rc *Ctx) ParamUUID(name string) (output uuid.UUID, err error) {
+	err = rc.parseParam(name, "must be a valid uuid", func(value string) (parseErr error) {
+		output, parseErr = uuid.Parse(value)
+		return
+	})
+	return
+}
+
+// ParamTime returns a parameter, from any of the sources `Param` reads, as a time in a given layout, e.g. `time.RFC3339`.
+func (rc *Ctx) ParamTime(name, layout string) (output time.Time, err error) {
+	err = rc.parseParam(name, "must be a time formatted as "+layout, func(value string) (parseErr error) {
+		output, parseErr = time.Parse(layout, value)
+		return
+	})
+	return
+}
+
+// ParamDuration returns a parameter, from any of the sources `Param` reads, as a duration, e.g. "1h30m".
+func (rc *Ctx) ParamDuration(name string) (output time.Duration, err error) {
+	err = rc.parseParam(name, "must be a duration", func(value string) (parseErr error) {
+		output, parseErr = time.ParseDuration(value)
+		return
+	})
+	return
+}
+
+// parseParam reads a parameter and parses it, returning a `*ParamError` if it is missing or can't be parsed.
+func (rc *Ctx) parseParam(name, message string, parse func(string) error) error {
+	value, err := rc.Param(name)
+	if err != nil {
+		return &ParamError{Param: name, Message: "is missing"}
+	}
+	if err := parse(value); err != nil {
+		return &ParamError{Param: name, Message: message}
+	}
+	return nil
+}
+
+// TypedParam is a declared parameter that `ValidateParams` checks before an action runs.
+/*
+Declaring parameters once, as typed values, lets the compiler check their names and types in every action
+that uses them:
+
+	var (
+		userID = web.Int64Param("userID")
+		since  = web.TimeParam("since")
+	)
+
+	app.GET("/users/:userID/events", func(r *web.Ctx) web.Result {
+		events, err := store.Events(userID.Value(r), since.Value(r))
+		...
+	}, web.ValidateParams(userID, since))
+
+Requests with a missing or malformed parameter get a 400 with a `*ParamError`, so `Value` can
+ignore errors.
+*/
+type TypedParam interface {
+	// ParamName returns the parameter name.
+	ParamName() string
+	// Validate returns a `*ParamError` if the parameter is missing or can't be parsed.
+	Validate(*Ctx) error
+}
+
+// ValidateParams returns middleware that responds with a 400 if any of the given parameters are missing or
+// can't be parsed, using the default result provider.
+func ValidateParams(params ...TypedParam) Middleware {
+	return func(action Action) Action {
+		return func(r *Ctx) Result {
+			for _, param := range params {
+				if err := param.Validate(r); err != nil {
+					return r.DefaultResultProvider().BadRequest(err)
+				}
+			}
+			return action(r)
+		}
+	}
+}
+
+var (
+	_ TypedParam = (*StringParam)(nil)
+	_ TypedParam = (*IntParam)(nil)
+	_ TypedParam = (*Int64Param)(nil)
+	_ TypedParam = (*Float64Param)(nil)
+	_ TypedParam = (*BoolParam)(nil)
+	_ TypedParam = (*UUIDParam)(nil)
+	_ TypedParam = (*TimeParam)(nil)
+	_ TypedParam = (*DurationParam)(nil)
+)
+
+// StringParam is a declared string parameter; it is only required to be present.
+type StringParam string
+
+// ParamName implements TypedParam.
+func (p StringParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p StringParam) Validate(r *Ctx) error {
+	return r.parseParam(string(p), "", func(string) error { return nil })
+}
+
+// Value returns the parameter value, or an empty string if it is missing.
+func (p StringParam) Value(r *Ctx) string {
+	value, _ := r.Param(string(p))
+	return value
+}
+
+// IntParam is a declared int parameter.
+type IntParam string
+
+// ParamName implements TypedParam.
+func (p IntParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p IntParam) Validate(r *Ctx) error {
+	_, err := r.ParamInt(string(p))
+	return err
+}
+
+// Value returns the parameter value, or zero if it is missing or malformed.
+func (p IntParam) Value(r *Ctx) int {
+	value, _ := r.ParamInt(string(p))
+	return value
+}
+
+// Int64Param is a declared int64 parameter.
+type Int64Param string
+
+// ParamName implements TypedParam.
+func (p Int64Param) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p Int64Param) Validate(r *Ctx) error {
+	_, err := r.ParamInt64(string(p))
+	return err
+}
+
+// Value returns the parameter value, or zero if it is missing or malformed.
+func (p Int64Param) Value(r *Ctx) int64 {
+	value, _ := r.ParamInt64(string(p))
+	return value
+}
+
+// Float64Param is a declared float64 parameter.
+type Float64Param string
+
+// ParamName implements TypedParam.
+func (p Float64Param) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p Float64Param) Validate(r *Ctx) error {
+	_, err := r.ParamFloat64(string(p))
+	return err
+}
+
+// Value returns the parameter value, or zero if it is missing or malformed.
+func (p Float64Param) Value(r *Ctx) float64 {
+	value, _ := r.ParamFloat64(string(p))
+	return value
+}
+
+// BoolParam is a declared bool parameter.
+type BoolParam string
+
+// ParamName implements TypedParam.
+func (p BoolParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p BoolParam) Validate(r *Ctx) error {
+	_, err := r.ParamBool(string(p))
+	return err
+}
+
+// Value returns the parameter value, or false if it is missing or malformed.
+func (p BoolParam) Value(r *Ctx) bool {
+	value, _ := r.ParamBool(string(p))
+	return value
+}
+
+// UUIDParam is a declared uuid parameter.
+type UUIDParam string
+
+// ParamName implements TypedParam.
+func (p UUIDParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p UUIDParam) Validate(r *Ctx) error {
+	_, err := r.ParamUUID(string(p))
+	return err
+}
+
+// Value returns the parameter value, or nil if it is missing or malformed.
+func (p UUIDParam) Value(r *Ctx) uuid.UUID {
+	value, _ := r.ParamUUID(string(p))
+	return value
+}
+
+// TimeParam is a declared time parameter, formatted as `time.RFC3339`.
+type TimeParam string
+
+// ParamName implements TypedParam.
+func (p TimeParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p TimeParam) Validate(r *Ctx) error {
+	_, err := r.ParamTime(string(p), time.RFC3339)
+	return err
+}
+
+// Value returns the parameter value, or the zero time if it is missing or malformed.
+func (p TimeParam) Value(r *Ctx) time.Time {
+	value, _ := r.ParamTime(string(p), time.RFC3339)
+	return value
+}
+
+// DurationParam is a declared duration parameter.
+type DurationParam string
+
+// ParamName implements TypedParam.
+func (p DurationParam) ParamName() string { return string(p) }
+
+// Validate implements TypedParam.
+func (p DurationParam) Validate(r *Ctx) error {
+	_, err := r.ParamDuration(string(p))
+	return err
+}
+
+// Value returns the parameter value, or zero if it is missing or malformed.
+func (p DurationParam) Value(r *Ctx) time.Duration {
+	value, _ := r.ParamDuration(string(p))
+	return value
+}
@@ -0,0 +1,96 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestCtxTypedParams(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := New().Mock().
+		WithQueryString("id", "1234").
+		WithQueryString("ratio", "0.5").
+		WithQueryString("enabled", "yes").
+		WithQueryString("uuid", "4f6d3ba2-2bd7-4b8d-8b2c-1e0f6c2b5e7a").
+		WithQueryString("since", "2018-06-01T12:00:00Z").
+		WithQueryString("window", "1h30m").
+		WithQueryString("bad", "nope").
+		CreateCtx(nil)
+	assert.Nil(err)
+
+	id, err := r.ParamInt64("id")
+	assert.Nil(err)
+	assert.Equal(int64(1234), id)
+	intID, err := r.ParamInt("id")
+	assert.Nil(err)
+	assert.Equal(1234, intID)
+	ratio, err := r.ParamFloat64("ratio")
+	assert.Nil(err)
+	assert.Equal(0.5, ratio)
+	enabled, err := r.ParamBool("enabled")
+	assert.Nil(err)
+	assert.True(enabled)
+	parsed, err := r.ParamUUID("uuid")
+	assert.Nil(err)
+	assert.Equal("4f6d3ba22bd74b8d8b2c1e0f6c2b5e7a", parsed.String())
+	since, err := r.ParamTime("since", time.RFC3339)
+	assert.Nil(err)
+	assert.Equal(2018, since.Year())
+	window, err := r.ParamDuration("window")
+	assert.Nil(err)
+	assert.Equal(90*time.Minute, window)
+
+	_, err = r.ParamInt64("bad")
+	assert.True(IsParamError(err))
+	assert.Equal("`bad` parameter must be an integer", err.Error())
+	_, err = r.ParamUUID("missing")
+	assert.True(IsParamError(err))
+	assert.Equal("`missing` parameter is missing", err.Error())
+}
+
+func TestValidateParams(t *testing.T) {
+	assert := assert.New(t)
+
+	userID := Int64Param("userID")
+	since := TimeParam("since")
+	name := StringParam("name")
+
+	app := New()
+	app.GET("/users/:userID", func(r *Ctx) Result {
+		return r.JSON().Result(map[string]interface{}{
+			"userID": userID.Value(r),
+			"since":  since.Value(r).Year(),
+			"name":   name.Value(r),
+		})
+	}, ValidateParams(userID, since, name), JSONProviderAsDefault)
+
+	var output map[string]interface{}
+	meta, err := app.Mock().Get("/users/1234").
+		WithQueryString("since", "2018-06-01T12:00:00Z").
+		WithQueryString("name", "bailey").
+		JSONWithMeta(&output)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal(1234, output["userID"])
+	assert.Equal(2018, output["since"])
+	assert.Equal("bailey", output["name"])
+
+	contents, meta, err := app.Mock().Get("/users/bailey").
+		WithQueryString("since", "2018-06-01T12:00:00Z").
+		WithQueryString("name", "bailey").
+		BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+	var paramErr ParamError
+	assert.Nil(json.Unmarshal(contents, &paramErr))
+	assert.Equal(ParamError{Param: "userID", Message: "must be an integer"}, paramErr)
+
+	meta, err = app.Mock().Get("/users/1234").WithQueryString("since", "2018-06-01T12:00:00Z").ExecuteWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+}
@@ -0,0 +1,312 @@
+package web
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderRetryAfter is the "Retry-After" header.
+	HeaderRetryAfter = "Retry-After"
+	// HeaderXRateLimitLimit is the header for the number of requests allowed in a burst.
+	HeaderXRateLimitLimit = "X-RateLimit-Limit"
+	// HeaderXRateLimitRemaining is the header for the number of requests remaining in the current burst.
+	HeaderXRateLimitRemaining = "X-RateLimit-Remaining"
+
+	// DefaultRateLimitKeyPrefix is the default prefix for rate limit keys.
+	DefaultRateLimitKeyPrefix = "ratelimit:"
+	// DefaultRateLimitSweepInterval is the interval the memory store removes idle buckets on.
+	DefaultRateLimitSweepInterval = time.Minute
+)
+
+// RateLimitKeyFunc returns the key a request is rate limited by.
+// Requests with an empty key are not rate limited.
+type RateLimitKeyFunc func(*Ctx) string
+
+// RateLimitByIP rate limits requests by the address of the connection they were made on.
+// Forwarded headers are ignored, as clients can set them to anything; use `RateLimitByForwardedIP`
+// if the app is behind a trusted proxy.
+func RateLimitByIP(ctx *Ctx) string {
+	return rateLimitConnectionIP(ctx.Request())
+}
+
+// RateLimitByForwardedIP returns a key func that rate limits requests by the client address
+// in `X-Forwarded-For`, for apps behind trusted proxies.
+// Trusted proxies are ips or cidr ranges; the header is only honored for connections from a trusted proxy,
+// and the client address is the rightmost address in the header that isn't a trusted proxy.
+// Invalid trusted proxies are ignored.
+/*
+	limiter := web.NewRateLimiter(100, time.Minute).WithKeyFunc(web.RateLimitByForwardedIP("10.0.0.0/8"))
+*/
+func RateLimitByForwardedIP(trustedProxies ...string) RateLimitKeyFunc {
+	var trusted []*net.IPNet
+	for _, proxy := range trustedProxies {
+		if _, network, err := net.ParseCIDR(proxy); err == nil {
+			trusted = append(trusted, network)
+			continue
+		}
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		}
+	}
+	isTrusted := func(addr string) bool {
+		ip := net.ParseIP(strings.TrimSpace(addr))
+		if ip == nil {
+			return false
+		}
+		for _, network := range trusted {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(ctx *Ctx) string {
+		remoteAddr := rateLimitConnectionIP(ctx.Request())
+		if !isTrusted(remoteAddr) {
+			return remoteAddr
+		}
+		forwarded := strings.Split(strings.Join(ctx.Request().Header[HeaderXForwardedFor], ","), ",")
+		for index := len(forwarded) - 1; index >= 0; index-- {
+			addr := strings.TrimSpace(forwarded[index])
+			if len(addr) == 0 {
+				continue
+			}
+			if !isTrusted(addr) {
+				return addr
+			}
+			remoteAddr = addr
+		}
+		return remoteAddr
+	}
+}
+
+// rateLimitConnectionIP returns the ip of the connection a request was made on.
+func rateLimitConnectionIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// RateLimitByHeader returns a key func that rate limits requests by a header value, e.g. an api key.
+func RateLimitByHeader(header string) RateLimitKeyFunc {
+	return func(ctx *Ctx) string {
+		return ctx.Request().Header.Get(header)
+	}
+}
+
+// RateLimit is a token bucket; it holds up to `Burst` tokens and refills at `Rate` tokens per second.
+// Each request takes a token.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// RateLimitStore stores token buckets by key.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+}
+
+// NewRateLimiter returns a new rate limiter that allows a number of requests per interval per ip,
+// in bursts of up to the same number, with an in-memory store.
+func NewRateLimiter(requests int, per time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit: RateLimit{
+			Rate:  float64(requests) / per.Seconds(),
+			Burst: requests,
+		},
+		keyFunc:   RateLimitByIP,
+		keyPrefix: DefaultRateLimitKeyPrefix,
+		store:     NewMemoryRateLimitStore(),
+	}
+}
+
+// RateLimiter is a middleware that responds with a 429 and a `Retry-After` header
+// when a key runs out of tokens.
+// Use `app.Use(limiter.Middleware)` to limit every route, or pass it as route middleware.
+type RateLimiter struct {
+	limit     RateLimit
+	keyFunc   RateLimitKeyFunc
+	keyPrefix string
+	store     RateLimitStore
+}
+
+// WithBurst sets the number of requests allowed in a burst.
+func (rl *RateLimiter) WithBurst(burst int) *RateLimiter {
+	rl.limit.Burst = burst
+	return rl
+}
+
+// Limit returns the rate limit.
+func (rl *RateLimiter) Limit() RateLimit {
+	return rl.limit
+}
+
+// WithKeyFunc sets the function requests are keyed by.
+func (rl *RateLimiter) WithKeyFunc(keyFunc RateLimitKeyFunc) *RateLimiter {
+	rl.keyFunc = keyFunc
+	return rl
+}
+
+// KeyFunc returns the function requests are keyed by.
+func (rl *RateLimiter) KeyFunc() RateLimitKeyFunc {
+	return rl.keyFunc
+}
+
+// WithKeyPrefix sets the prefix for keys; limiters that share a store should use different prefixes.
+func (rl *RateLimiter) WithKeyPrefix(keyPrefix string) *RateLimiter {
+	rl.keyPrefix = keyPrefix
+	return rl
+}
+
+// KeyPrefix returns the prefix for keys.
+func (rl *RateLimiter) KeyPrefix() string {
+	return rl.keyPrefix
+}
+
+// WithStore sets the store for token buckets.
+func (rl *RateLimiter) WithStore(store RateLimitStore) *RateLimiter {
+	rl.store = store
+	return rl
+}
+
+// Store returns the store for token buckets.
+func (rl *RateLimiter) Store() RateLimitStore {
+	return rl.store
+}
+
+// Middleware returns the action wrapped so it is rate limited.
+// If the store returns an error the error is logged and the request is allowed.
+func (rl *RateLimiter) Middleware(action Action) Action {
+	return func(ctx *Ctx) Result {
+		key := rl.keyFunc(ctx)
+		if len(key) == 0 {
+			return action(ctx)
+		}
+		result, err := rl.store.Take(ctx.Context(), rl.keyPrefix+key, rl.limit)
+		if err != nil {
+			if ctx.log != nil {
+				ctx.log.Error(err)
+			}
+			return action(ctx)
+		}
+
+		header := ctx.Response().Header()
+		header.Set(HeaderXRateLimitLimit, strconv.Itoa(rl.limit.Burst))
+		header.Set(HeaderXRateLimitRemaining, strconv.Itoa(result.Remaining))
+		if !result.Allowed {
+			header.Set(HeaderRetryAfter, strconv.Itoa(int(math.Ceil(result.RetryAfter.Seconds()))))
+			return ctx.DefaultResultProvider().Status(http.StatusTooManyRequests)
+		}
+		return action(ctx)
+	}
+}
+
+// NewMemoryRateLimitStore returns a new in-memory rate limit store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:       map[string]*tokenBucket{},
+		sweepInterval: DefaultRateLimitSweepInterval,
+		now:           time.Now,
+	}
+}
+
+// MemoryRateLimitStore is a rate limit store local to the process.
+// Buckets that have refilled are removed periodically.
+type MemoryRateLimitStore struct {
+	sync.Mutex
+	buckets       map[string]*tokenBucket
+	sweepInterval time.Duration
+	lastSweep     time.Time
+	now           func() time.Time
+}
+
+// Take implements RateLimitStore.
+func (mrs *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	mrs.Lock()
+	defer mrs.Unlock()
+
+	now := mrs.now()
+	if now.Sub(mrs.lastSweep) >= mrs.sweepInterval {
+		mrs.sweep(now)
+		mrs.lastSweep = now
+	}
+
+	bucket, ok := mrs.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		mrs.buckets[key] = bucket
+	}
+	return bucket.take(now, limit), nil
+}
+
+// Len returns the number of buckets held.
+func (mrs *MemoryRateLimitStore) Len() int {
+	mrs.Lock()
+	defer mrs.Unlock()
+	return len(mrs.buckets)
+}
+
+// sweep removes buckets that would be full by now, as they are equivalent to no bucket.
+func (mrs *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, bucket := range mrs.buckets {
+		if bucket.refill(now) >= float64(bucket.limit.Burst) {
+			delete(mrs.buckets, key)
+		}
+	}
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   RateLimit
+}
+
+func (tb *tokenBucket) refill(now time.Time) float64 {
+	elapsed := now.Sub(tb.updated).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(tb.limit.Burst), tb.tokens+elapsed*tb.limit.Rate)
+}
+
+func (tb *tokenBucket) take(now time.Time, limit RateLimit) RateLimitResult {
+	tb.limit = limit
+	tb.tokens = tb.refill(now)
+	tb.updated = now
+	if tb.tokens < 1 {
+		return RateLimitResult{
+			RetryAfter: rateLimitRetryAfter(tb.tokens, limit),
+		}
+	}
+	tb.tokens--
+	return RateLimitResult{Allowed: true, Remaining: int(tb.tokens)}
+}
+
+// rateLimitRetryAfter returns how long until a bucket has a whole token.
+func rateLimitRetryAfter(tokens float64, limit RateLimit) time.Duration {
+	if limit.Rate <= 0 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
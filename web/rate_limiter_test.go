@@ -0,0 +1,237 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+func TestMemoryRateLimitStoreTake(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+	limit := RateLimit{Rate: 1, Burst: 2}
+
+	result, err := store.Take(context.Background(), "foo", limit)
+	assert.Nil(err)
+	assert.True(result.Allowed)
+	assert.Equal(1, result.Remaining)
+
+	result, err = store.Take(context.Background(), "foo", limit)
+	assert.Nil(err)
+	assert.True(result.Allowed)
+	assert.Equal(0, result.Remaining)
+
+	result, err = store.Take(context.Background(), "foo", limit)
+	assert.Nil(err)
+	assert.False(result.Allowed)
+	assert.Equal(time.Second, result.RetryAfter)
+
+	// other keys have their own bucket.
+	result, err = store.Take(context.Background(), "bar", limit)
+	assert.Nil(err)
+	assert.True(result.Allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Take(context.Background(), "foo", limit)
+	assert.Nil(err)
+	assert.False(result.Allowed)
+	assert.Equal(500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = store.Take(context.Background(), "foo", limit)
+	assert.Nil(err)
+	assert.True(result.Allowed)
+}
+
+func TestMemoryRateLimitStoreSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryRateLimitStore()
+	store.now = func() time.Time { return now }
+
+	_, err := store.Take(context.Background(), "foo", RateLimit{Rate: 1, Burst: 10})
+	assert.Nil(err)
+	_, err = store.Take(context.Background(), "bar", RateLimit{Rate: 0.01, Burst: 10})
+	assert.Nil(err)
+	assert.Equal(2, store.Len())
+
+	now = now.Add(DefaultRateLimitSweepInterval)
+	_, err = store.Take(context.Background(), "baz", RateLimit{Rate: 1, Burst: 10})
+	assert.Nil(err)
+	assert.Equal(2, store.Len())
+}
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(2, time.Minute).WithKeyFunc(RateLimitByHeader("X-Api-Key"))
+	app := New()
+	app.GET("/", func(r *Ctx) Result {
+		return r.Text().Result("ok!")
+	}, limiter.Middleware)
+
+	for x := 0; x < 2; x++ {
+		res, err := app.Mock().WithPathf("/").WithHeader("X-Api-Key", "foo").Response()
+		assert.Nil(err)
+		res.Body.Close()
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Equal("2", res.Header.Get(HeaderXRateLimitLimit))
+		assert.Equal(fmt.Sprint(1-x), res.Header.Get(HeaderXRateLimitRemaining))
+	}
+
+	res, err := app.Mock().WithPathf("/").WithHeader("X-Api-Key", "foo").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal("30", res.Header.Get(HeaderRetryAfter))
+
+	res, err = app.Mock().WithPathf("/").WithHeader("X-Api-Key", "bar").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+
+	// requests without a key are not limited.
+	for x := 0; x < 3; x++ {
+		res, err = app.Mock().WithPathf("/").Response()
+		assert.Nil(err)
+		res.Body.Close()
+		assert.Equal(http.StatusOK, res.StatusCode)
+		assert.Empty(res.Header.Get(HeaderXRateLimitLimit))
+	}
+}
+
+type mockRateLimitStore struct {
+	err error
+}
+
+func (mrs mockRateLimitStore) Take(_ context.Context, _ string, _ RateLimit) (RateLimitResult, error) {
+	return RateLimitResult{}, mrs.err
+}
+
+func TestRateLimiterMiddlewareStoreError(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(1, time.Minute).WithStore(mockRateLimitStore{err: fmt.Errorf("connection refused")})
+	app := New()
+	app.Use(limiter.Middleware)
+	app.GET("/", func(r *Ctx) Result {
+		return r.Text().Result("ok!")
+	})
+
+	res, err := app.Mock().WithPathf("/").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
+
+type mockRedisScripter struct {
+	keys  []string
+	args  []interface{}
+	reply interface{}
+}
+
+func (mrs *mockRedisScripter) Eval(_ context.Context, _ string, keys []string, args ...interface{}) (interface{}, error) {
+	mrs.keys = keys
+	mrs.args = args
+	return mrs.reply, nil
+}
+
+func TestRedisRateLimitStoreTake(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &mockRedisScripter{reply: []interface{}{int64(1), "3.5"}}
+	store := NewRedisRateLimitStore(client)
+	store.now = func() time.Time { return time.Unix(10, 0) }
+
+	result, err := store.Take(context.Background(), "ratelimit:foo", RateLimit{Rate: 0.5, Burst: 5})
+	assert.Nil(err)
+	assert.True(result.Allowed)
+	assert.Equal(3, result.Remaining)
+	assert.Equal([]string{"ratelimit:foo"}, client.keys)
+	assert.Equal([]interface{}{"0.5", "5", "10000"}, client.args)
+
+	client.reply = []interface{}{int64(0), []byte("0.5")}
+	result, err = store.Take(context.Background(), "ratelimit:foo", RateLimit{Rate: 0.5, Burst: 5})
+	assert.Nil(err)
+	assert.False(result.Allowed)
+	assert.Equal(time.Second, result.RetryAfter)
+
+	client.reply = "OK"
+	_, err = store.Take(context.Background(), "ratelimit:foo", RateLimit{Rate: 0.5, Burst: 5})
+	assert.True(exception.Is(err, ErrRateLimitStore))
+}
+
+func TestRateLimitByIP(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{
+		RemoteAddr: "192.168.1.10:51234",
+		Header: http.Header{
+			HeaderXForwardedFor: []string{"1.2.3.4"},
+			"X-Real-Ip":         []string{"1.2.3.4"},
+		},
+	}
+	// forwarded headers set by the client are ignored.
+	assert.Equal("192.168.1.10", RateLimitByIP(NewCtx(nil, req, nil, nil)))
+}
+
+func TestRateLimitByForwardedIP(t *testing.T) {
+	assert := assert.New(t)
+
+	keyFunc := RateLimitByForwardedIP("10.0.0.0/8", "192.168.1.1", "not an ip")
+	key := func(remoteAddr string, forwarded ...string) string {
+		req := &http.Request{RemoteAddr: remoteAddr, Header: http.Header{}}
+		for _, value := range forwarded {
+			req.Header.Add(HeaderXForwardedFor, value)
+		}
+		return keyFunc(NewCtx(nil, req, nil, nil))
+	}
+
+	// the header is ignored for connections that aren't from a trusted proxy.
+	assert.Equal("192.168.1.10", key("192.168.1.10:51234", "1.2.3.4"))
+	// the client address is the rightmost untrusted address.
+	assert.Equal("5.6.7.8", key("10.0.0.1:51234", "5.6.7.8"))
+	assert.Equal("5.6.7.8", key("192.168.1.1:51234", "1.2.3.4, 5.6.7.8, 10.0.0.2"))
+	assert.Equal("5.6.7.8", key("10.0.0.1:51234", "1.2.3.4", "5.6.7.8"))
+	// a spoofed leftmost address doesn't change the key.
+	assert.Equal(key("10.0.0.1:51234", "5.6.7.8"), key("10.0.0.1:51234", "9.9.9.9, 5.6.7.8"))
+	// if every address is trusted, the leftmost one is used.
+	assert.Equal("10.0.0.2", key("10.0.0.1:51234", "10.0.0.2"))
+	assert.Equal("10.0.0.1", key("10.0.0.1:51234"))
+}
+
+func TestRateLimiterMiddlewareSpoofedForwardedFor(t *testing.T) {
+	assert := assert.New(t)
+
+	limiter := NewRateLimiter(1, time.Minute)
+	var allowed bool
+	action := limiter.Middleware(func(r *Ctx) Result {
+		allowed = true
+		return nil
+	})
+
+	serve := func(forwardedFor string) bool {
+		allowed = false
+		req := &http.Request{
+			RemoteAddr: "192.168.1.10:51234",
+			Header:     http.Header{HeaderXForwardedFor: []string{forwardedFor}},
+		}
+		ctx := NewCtx(NewMockResponseWriter(ioutil.Discard), req, nil, nil).WithDefaultResultProvider(Text)
+		action(ctx)
+		return allowed
+	}
+
+	assert.True(serve("1.1.1.1"))
+	// a different spoofed address doesn't get a new bucket.
+	assert.False(serve("2.2.2.2"))
+}
@@ -1,7 +1,11 @@
 package web
 
 import (
+	"bufio"
+	"net"
 	"net/http"
+
+	"github.com/blend/go-sdk/exception"
 )
 
 // NewRawResponseWriter creates a new uncompressed response writer.
@@ -41,6 +45,21 @@ func (rw *RawResponseWriter) InnerResponse() http.ResponseWriter {
 	return rw.innerResponse
 }
 
+// Hijack takes over the underlying connection, e.g. to upgrade it to a websocket.
+// The response is recorded with the switching protocols status code.
+func (rw *RawResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.innerResponse.(http.Hijacker)
+	if !ok {
+		return nil, nil, exception.New(ErrWebSocketHandshake).WithMessage("response does not support hijacking")
+	}
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	rw.statusCode = http.StatusSwitchingProtocols
+	return conn, buffer, nil
+}
+
 // StatusCode returns the status code.
 func (rw *RawResponseWriter) StatusCode() int {
 	return rw.statusCode
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// redisTokenBucketScript takes a token from a bucket stored as a hash of its tokens and the time
+// it was last updated in milliseconds, so buckets are shared and updated atomically across servers.
+// The key expires once the bucket would be full again.
+const redisTokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+local elapsed = math.max(0, now - updated) / 1000
+tokens = math.min(burst, tokens + elapsed * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(now))
+if rate > 0 then
+	redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1)
+end
+return {allowed, tostring(tokens)}
+`
+
+// RedisScripter is the subset of a redis client the redis rate limit store uses.
+// Wrap the redis client of your choice to satisfy it.
+type RedisScripter interface {
+	// Eval runs a lua script, returning its result, e.g. a lua table as an `[]interface{}`.
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+var (
+	_ RateLimitStore = (*RedisRateLimitStore)(nil)
+	_ RateLimitStore = (*MemoryRateLimitStore)(nil)
+)
+
+// NewRedisRateLimitStore returns a new redis rate limit store.
+func NewRedisRateLimitStore(client RedisScripter) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// RedisRateLimitStore is a rate limit store shared between servers through redis.
+type RedisRateLimitStore struct {
+	client RedisScripter
+	now    func() time.Time
+}
+
+// Client returns the redis client.
+func (rrs *RedisRateLimitStore) Client() RedisScripter {
+	return rrs.client
+}
+
+// Take implements RateLimitStore.
+func (rrs *RedisRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	nowMillis := rrs.now().UnixNano() / int64(time.Millisecond)
+	reply, err := rrs.client.Eval(ctx, redisTokenBucketScript, []string{key},
+		strconv.FormatFloat(limit.Rate, 'f', -1, 64), strconv.Itoa(limit.Burst), strconv.FormatInt(nowMillis, 10))
+	if err != nil {
+		return RateLimitResult{}, exception.New(err)
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return RateLimitResult{}, exception.New(ErrRateLimitStore).WithMessagef("unexpected reply %v", reply)
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return RateLimitResult{}, exception.New(ErrRateLimitStore).WithMessagef("unexpected reply %v", reply)
+	}
+	var tokens float64
+	switch typed := values[1].(type) {
+	case string:
+		tokens, err = strconv.ParseFloat(typed, 64)
+	case []byte:
+		tokens, err = strconv.ParseFloat(string(typed), 64)
+	default:
+		err = exception.New(ErrRateLimitStore).WithMessagef("unexpected reply %v", reply)
+	}
+	if err != nil {
+		return RateLimitResult{}, exception.New(err)
+	}
+
+	if allowed == 0 {
+		return RateLimitResult{RetryAfter: rateLimitRetryAfter(tokens, limit)}, nil
+	}
+	return RateLimitResult{Allowed: true, Remaining: int(tokens)}, nil
+}
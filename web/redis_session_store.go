@@ -0,0 +1,98 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// DefaultRedisSessionKeyPrefix is the default prefix for redis session keys.
+	DefaultRedisSessionKeyPrefix = "session:"
+)
+
+// RedisClient is the subset of a redis client the redis session store uses.
+// Wrap the redis client of your choice to satisfy it.
+type RedisClient interface {
+	// Get returns the value of a key, and if the key was found.
+	Get(ctx context.Context, key string) (value string, found bool, err error)
+	// Set sets the value of a key, expiring it after the expiration if it is non-zero.
+	Set(ctx context.Context, key, value string, expiration time.Duration) error
+	// Del deletes a key.
+	Del(ctx context.Context, key string) error
+}
+
+var (
+	_ SessionStore = (*RedisSessionStore)(nil)
+	_ SessionStore = (*LocalSessionCache)(nil)
+)
+
+// NewRedisSessionStore returns a new redis session store.
+func NewRedisSessionStore(client RedisClient) *RedisSessionStore {
+	return &RedisSessionStore{
+		client:    client,
+		keyPrefix: DefaultRedisSessionKeyPrefix,
+	}
+}
+
+// RedisSessionStore is a session store that saves sessions as json in redis.
+// Keys expire with the session, so expired sessions are cleaned up by redis.
+type RedisSessionStore struct {
+	client    RedisClient
+	keyPrefix string
+}
+
+// Client returns the redis client.
+func (rss *RedisSessionStore) Client() RedisClient {
+	return rss.client
+}
+
+// WithKeyPrefix sets the prefix for session keys.
+func (rss *RedisSessionStore) WithKeyPrefix(keyPrefix string) *RedisSessionStore {
+	rss.keyPrefix = keyPrefix
+	return rss
+}
+
+// KeyPrefix returns the prefix for session keys.
+func (rss *RedisSessionStore) KeyPrefix() string {
+	return rss.keyPrefix
+}
+
+// FetchSession implements SessionStore.
+func (rss *RedisSessionStore) FetchSession(ctx context.Context, sessionID string) (*Session, error) {
+	value, found, err := rss.client.Get(ctx, rss.keyPrefix+sessionID)
+	if err != nil {
+		return nil, exception.New(err)
+	}
+	if !found {
+		return nil, nil
+	}
+	var session Session
+	if err := json.Unmarshal([]byte(value), &session); err != nil {
+		return nil, exception.New(err)
+	}
+	return &session, nil
+}
+
+// PersistSession implements SessionStore.
+func (rss *RedisSessionStore) PersistSession(ctx context.Context, session *Session) error {
+	contents, err := json.Marshal(session)
+	if err != nil {
+		return exception.New(err)
+	}
+	var expiration time.Duration
+	if !session.ExpiresUTC.IsZero() {
+		expiration = session.ExpiresUTC.Sub(time.Now().UTC())
+		if expiration <= 0 {
+			return rss.RemoveSession(ctx, session.SessionID)
+		}
+	}
+	return exception.New(rss.client.Set(ctx, rss.keyPrefix+session.SessionID, string(contents), expiration))
+}
+
+// RemoveSession implements SessionStore.
+func (rss *RedisSessionStore) RemoveSession(ctx context.Context, sessionID string) error {
+	return exception.New(rss.client.Del(ctx, rss.keyPrefix+sessionID))
+}
@@ -0,0 +1,55 @@
+package web
+
+import (
+	"github.com/blend/go-sdk/webutil"
+)
+
+const (
+	// HeaderXRequestID is the "X-Request-ID" header.
+	HeaderXRequestID = webutil.HeaderXRequestID
+
+	// LabelRequestID is the label the request id is set on for response log events.
+	LabelRequestID = "request_id"
+
+	// MaxRequestIDLength is the maximum length of a request id accepted from a client.
+	MaxRequestIDLength = 128
+)
+
+// RequestID is a middleware that assigns the request an id, using the `X-Request-ID` header
+// if the client sent a valid one and the ctx id otherwise.
+//
+// The id is returned in the `X-Request-ID` response header, becomes the ctx id, and is set on
+// the request context, where the response log event labels itself with it, the trace finisher
+// tags spans with it, and the `request` package sends it on outbound requests.
+// Use it with `app.Use` so it runs before other middleware.
+func RequestID(action Action) Action {
+	return func(ctx *Ctx) Result {
+		requestID := ctx.Request().Header.Get(HeaderXRequestID)
+		if !isValidRequestID(requestID) {
+			requestID = ctx.ID()
+		}
+		ctx.id = requestID
+		ctx.WithContext(webutil.WithRequestID(ctx.Context(), requestID))
+		ctx.Response().Header().Set(HeaderXRequestID, requestID)
+		return action(ctx)
+	}
+}
+
+// RequestID returns the request id set by the `RequestID` middleware, or an empty string if it did not run.
+func (rc *Ctx) RequestID() string {
+	return webutil.GetRequestID(rc.Context())
+}
+
+// isValidRequestID returns if a request id is non-empty, not too long, and only contains
+// printable ascii characters other than spaces, so it's safe to log and forward.
+func isValidRequestID(requestID string) bool {
+	if len(requestID) == 0 || len(requestID) > MaxRequestIDLength {
+		return false
+	}
+	for index := 0; index < len(requestID); index++ {
+		if requestID[index] <= ' ' || requestID[index] > '~' {
+			return false
+		}
+	}
+	return true
+}
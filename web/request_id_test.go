@@ -0,0 +1,52 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	app.Use(RequestID)
+	app.GET("/", func(r *Ctx) Result {
+		return r.Text().Result(r.ID() + " " + r.RequestID())
+	})
+
+	contents, meta, err := app.Mock().WithPathf("/").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	generated := meta.Headers.Get(HeaderXRequestID)
+	assert.NotEmpty(generated)
+	assert.Equal(generated+" "+generated, string(contents))
+
+	contents, meta, err = app.Mock().WithPathf("/").WithHeader(HeaderXRequestID, "upstream-1234").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal("upstream-1234", meta.Headers.Get(HeaderXRequestID))
+	assert.Equal("upstream-1234 upstream-1234", string(contents))
+
+	for _, invalid := range []string{"has space", "new\nline", strings.Repeat("a", MaxRequestIDLength+1)} {
+		_, meta, err = app.Mock().WithPathf("/").WithHeader(HeaderXRequestID, invalid).BytesWithMeta()
+		assert.Nil(err)
+		assert.NotEmpty(meta.Headers.Get(HeaderXRequestID))
+		assert.NotEqual(invalid, meta.Headers.Get(HeaderXRequestID))
+	}
+}
+
+func TestRequestIDResponseEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	ctx, err := app.Mock().WithHeader(HeaderXRequestID, "upstream-1234").CreateCtx(nil)
+	assert.Nil(err)
+	assert.Empty(app.httpResponseEvent(ctx).Labels()[LabelRequestID])
+
+	RequestID(func(r *Ctx) Result { return nil })(ctx)
+	event := app.httpResponseEvent(ctx)
+	assert.Equal("upstream-1234", event.Entity())
+	assert.Equal("upstream-1234", event.Labels()[LabelRequestID])
+}
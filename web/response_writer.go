@@ -13,3 +13,18 @@ type ResponseWriter interface {
 	ContentLength() int
 	Close() error
 }
+
+// flushResponse writes any buffered output of a response to the client, if the response supports it.
+func flushResponse(rw ResponseWriter) error {
+	switch typed := rw.(type) {
+	case interface{ Flush() error }:
+		return typed.Flush()
+	case http.Flusher:
+		typed.Flush()
+		return nil
+	}
+	if flusher, ok := rw.InnerResponse().(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
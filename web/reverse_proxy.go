@@ -0,0 +1,289 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// HeaderXForwardedFor is the "X-Forwarded-For" header.
+	HeaderXForwardedFor = "X-Forwarded-For"
+	// HeaderXForwardedHost is the "X-Forwarded-Host" header.
+	HeaderXForwardedHost = "X-Forwarded-Host"
+	// HeaderXForwardedProto is the "X-Forwarded-Proto" header.
+	HeaderXForwardedProto = "X-Forwarded-Proto"
+
+	// DefaultReverseProxyFlushInterval is the default interval responses are flushed to the client on.
+	DefaultReverseProxyFlushInterval = 100 * time.Millisecond
+)
+
+// NewReverseProxy returns a new reverse proxy to an upstream, e.g. `http://localhost:8081/api`.
+// Request paths are appended to the upstream path.
+func NewReverseProxy(upstream *url.URL) *ReverseProxy {
+	return &ReverseProxy{
+		upstream:      upstream,
+		flushInterval: DefaultReverseProxyFlushInterval,
+	}
+}
+
+// ReverseProxy forwards requests to an upstream. Bodies are streamed in both directions,
+// and websocket (and other protocol) upgrades are passed through.
+/*
+Register it as an action, e.g. to forward everything under a path:
+
+	users := web.NewReverseProxy(usersURL).WithStripPrefix("/users")
+	app.GET("/users/*path", users.Action)
+	app.POST("/users/*path", users.Action)
+
+Or return `ctx.Proxy(users)` from an action to decide per request.
+*/
+type ReverseProxy struct {
+	upstream        *url.URL
+	transport       http.RoundTripper
+	flushInterval   time.Duration
+	stripPrefix     string
+	preserveHost    bool
+	trustForwarded  bool
+	requestHeaders  http.Header
+	responseHeaders http.Header
+	modifyResponse  func(*http.Response) error
+}
+
+// Upstream returns the upstream url.
+func (rp *ReverseProxy) Upstream() *url.URL {
+	return rp.upstream
+}
+
+// WithTransport sets the transport used to make upstream requests; if unset `http.DefaultTransport` is used.
+func (rp *ReverseProxy) WithTransport(transport http.RoundTripper) *ReverseProxy {
+	rp.transport = transport
+	return rp
+}
+
+// Transport returns the transport used to make upstream requests.
+func (rp *ReverseProxy) Transport() http.RoundTripper {
+	return rp.transport
+}
+
+// WithFlushInterval sets the interval responses are flushed to the client on; a negative interval flushes
+// after every write. Streamed responses (e.g. server sent events) are always flushed after every write.
+func (rp *ReverseProxy) WithFlushInterval(interval time.Duration) *ReverseProxy {
+	rp.flushInterval = interval
+	return rp
+}
+
+// FlushInterval returns the interval responses are flushed to the client on.
+func (rp *ReverseProxy) FlushInterval() time.Duration {
+	return rp.flushInterval
+}
+
+// WithStripPrefix sets a prefix removed from request paths before they're appended to the upstream path.
+func (rp *ReverseProxy) WithStripPrefix(prefix string) *ReverseProxy {
+	rp.stripPrefix = prefix
+	return rp
+}
+
+// StripPrefix returns the prefix removed from request paths.
+func (rp *ReverseProxy) StripPrefix() string {
+	return rp.stripPrefix
+}
+
+// WithPreserveHost sets if the request's `Host` is sent upstream, instead of the upstream's host.
+func (rp *ReverseProxy) WithPreserveHost(preserveHost bool) *ReverseProxy {
+	rp.preserveHost = preserveHost
+	return rp
+}
+
+// PreserveHost returns if the request's `Host` is sent upstream.
+func (rp *ReverseProxy) PreserveHost() bool {
+	return rp.preserveHost
+}
+
+// WithTrustForwardedHeaders sets if `X-Forwarded-*` headers on incoming requests are kept,
+// i.e. if the app itself is behind a trusted proxy. Otherwise they are replaced.
+func (rp *ReverseProxy) WithTrustForwardedHeaders(trustForwarded bool) *ReverseProxy {
+	rp.trustForwarded = trustForwarded
+	return rp
+}
+
+// TrustForwardedHeaders returns if `X-Forwarded-*` headers on incoming requests are kept.
+func (rp *ReverseProxy) TrustForwardedHeaders() bool {
+	return rp.trustForwarded
+}
+
+// WithRequestHeader sets a header on upstream requests; an empty value removes the header.
+func (rp *ReverseProxy) WithRequestHeader(key, value string) *ReverseProxy {
+	if rp.requestHeaders == nil {
+		rp.requestHeaders = http.Header{}
+	}
+	rp.requestHeaders.Set(key, value)
+	return rp
+}
+
+// RequestHeaders returns the headers set on upstream requests.
+func (rp *ReverseProxy) RequestHeaders() http.Header {
+	return rp.requestHeaders
+}
+
+// WithResponseHeader sets a header on responses; an empty value removes the header.
+func (rp *ReverseProxy) WithResponseHeader(key, value string) *ReverseProxy {
+	if rp.responseHeaders == nil {
+		rp.responseHeaders = http.Header{}
+	}
+	rp.responseHeaders.Set(key, value)
+	return rp
+}
+
+// ResponseHeaders returns the headers set on responses.
+func (rp *ReverseProxy) ResponseHeaders() http.Header {
+	return rp.responseHeaders
+}
+
+// WithModifyResponse sets a function that can change upstream responses before they are sent to the client.
+func (rp *ReverseProxy) WithModifyResponse(modifyResponse func(*http.Response) error) *ReverseProxy {
+	rp.modifyResponse = modifyResponse
+	return rp
+}
+
+// ModifyResponse returns the function that can change upstream responses.
+func (rp *ReverseProxy) ModifyResponse() func(*http.Response) error {
+	return rp.modifyResponse
+}
+
+// Action is an action that proxies the request.
+func (rp *ReverseProxy) Action(ctx *Ctx) Result {
+	return ctx.Proxy(rp)
+}
+
+// director rewrites an outgoing request for the upstream.
+func (rp *ReverseProxy) director(req *http.Request) {
+	requestPath := req.URL.Path
+	if len(rp.stripPrefix) > 0 {
+		requestPath = strings.TrimPrefix(requestPath, rp.stripPrefix)
+		if !strings.HasPrefix(requestPath, "/") {
+			requestPath = "/" + requestPath
+		}
+	}
+	req.URL.Scheme = rp.upstream.Scheme
+	req.URL.Host = rp.upstream.Host
+	req.URL.Path = strings.TrimSuffix(rp.upstream.Path, "/") + requestPath
+	req.URL.RawPath = ""
+	if len(rp.upstream.RawQuery) > 0 && len(req.URL.RawQuery) > 0 {
+		req.URL.RawQuery = rp.upstream.RawQuery + "&" + req.URL.RawQuery
+	} else if len(rp.upstream.RawQuery) > 0 {
+		req.URL.RawQuery = rp.upstream.RawQuery
+	}
+
+	if !rp.trustForwarded || len(req.Header.Get(HeaderXForwardedHost)) == 0 {
+		req.Header.Set(HeaderXForwardedHost, req.Host)
+	}
+	if !rp.trustForwarded || len(req.Header.Get(HeaderXForwardedProto)) == 0 {
+		if req.TLS != nil {
+			req.Header.Set(HeaderXForwardedProto, "https")
+		} else {
+			req.Header.Set(HeaderXForwardedProto, "http")
+		}
+	}
+	if !rp.trustForwarded {
+		// the proxy appends the client address to what is left.
+		req.Header.Del(HeaderXForwardedFor)
+	}
+	if !rp.preserveHost {
+		req.Host = rp.upstream.Host
+	}
+
+	for key, values := range rp.requestHeaders {
+		if len(values) == 0 || len(values[0]) == 0 {
+			req.Header.Del(key)
+			continue
+		}
+		req.Header[key] = values
+	}
+	if _, ok := req.Header[HeaderUserAgent]; !ok {
+		// keep the transport from setting a default user agent.
+		req.Header.Set(HeaderUserAgent, "")
+	}
+}
+
+func (rp *ReverseProxy) modify(res *http.Response) error {
+	for key, values := range rp.responseHeaders {
+		if len(values) == 0 || len(values[0]) == 0 {
+			res.Header.Del(key)
+			continue
+		}
+		res.Header[key] = values
+	}
+	if rp.modifyResponse != nil {
+		return rp.modifyResponse(res)
+	}
+	return nil
+}
+
+// ProxyResult is a result that forwards the request to an upstream.
+type ProxyResult struct {
+	Proxy *ReverseProxy
+}
+
+// Render proxies the request, responding with a 502 if the upstream can't be reached.
+func (pr *ProxyResult) Render(ctx *Ctx) error {
+	if pr.Proxy == nil || pr.Proxy.upstream == nil {
+		return exception.New(ErrReverseProxy).WithMessage("upstream is unset")
+	}
+
+	// the upstream's encoding is used, rather than the identity encoding the app defaults to.
+	ctx.Response().Header().Del(HeaderContentEncoding)
+
+	var proxyErr error
+	proxy := &httputil.ReverseProxy{
+		Director:      pr.Proxy.director,
+		Transport:     pr.Proxy.transport,
+		FlushInterval: pr.Proxy.flushInterval,
+		ModifyResponse: func(res *http.Response) error {
+			if err := pr.Proxy.modify(res); err != nil {
+				return err
+			}
+			// the upstream's headers replace headers the app set, e.g. its default headers,
+			// and removed headers are removed from both.
+			for key := range pr.Proxy.responseHeaders {
+				ctx.Response().Header().Del(key)
+			}
+			for key := range res.Header {
+				ctx.Response().Header().Del(key)
+			}
+			return nil
+		},
+		ErrorHandler: func(rw http.ResponseWriter, _ *http.Request, err error) {
+			if err != context.Canceled {
+				proxyErr = exception.New(ErrReverseProxy).WithInner(err)
+			}
+			rw.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	proxy.ServeHTTP(proxyResponseWriter{ctx.Response()}, ctx.Request())
+	return proxyErr
+}
+
+// proxyResponseWriter lets the proxy flush streamed responses and hijack upgraded connections.
+type proxyResponseWriter struct {
+	ResponseWriter
+}
+
+func (prw proxyResponseWriter) Flush() {
+	flushResponse(prw.ResponseWriter)
+}
+
+func (prw proxyResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := prw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, exception.New(ErrReverseProxy).WithMessage("response does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
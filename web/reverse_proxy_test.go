@@ -0,0 +1,162 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestReverseProxy(t *testing.T) {
+	assert := assert.New(t)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		rw.Header().Set("X-Upstream", "true")
+		rw.Header().Set("Server", "upstream")
+		rw.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(rw, "%s %s?%s host=%s fwd-host=%s fwd-proto=%s fwd-for=%s gateway=%s cookie=%s body=%s",
+			req.Method, req.URL.Path, req.URL.RawQuery, req.Host,
+			req.Header.Get(HeaderXForwardedHost), req.Header.Get(HeaderXForwardedProto), req.Header.Get(HeaderXForwardedFor),
+			req.Header.Get("X-Gateway"), req.Header.Get("Cookie"), body)
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL + "/api?version=2")
+	assert.Nil(err)
+	proxy := NewReverseProxy(upstreamURL).
+		WithStripPrefix("/users").
+		WithRequestHeader("X-Gateway", "edge").
+		WithRequestHeader("Cookie", "").
+		WithResponseHeader("Server", "")
+
+	app := New()
+	app.POST("/users/*path", proxy.Action)
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL+"/users/bailey?fields=name", strings.NewReader("hello"))
+	assert.Nil(err)
+	req.Header.Set(HeaderXForwardedFor, "10.0.0.1")
+	req.Header.Set(HeaderXForwardedHost, "spoofed.example.com")
+	req.Header.Set("Cookie", "session=secret")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(err)
+	defer res.Body.Close()
+	contents, err := ioutil.ReadAll(res.Body)
+	assert.Nil(err)
+
+	serverURL, _ := url.Parse(server.URL)
+	assert.Equal(http.StatusCreated, res.StatusCode)
+	assert.Equal("true", res.Header.Get("X-Upstream"))
+	assert.Empty(res.Header.Get("Server"))
+	assert.Equal(
+		fmt.Sprintf("POST /api/bailey?version=2&fields=name host=%s fwd-host=%s fwd-proto=http fwd-for=127.0.0.1 gateway=edge cookie= body=hello",
+			upstreamURL.Host, serverURL.Host),
+		string(contents),
+	)
+}
+
+func TestReverseProxyTrustForwardedHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	var forwarded http.Header
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		forwarded = req.Header
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	assert.Nil(err)
+	proxy := NewReverseProxy(upstreamURL).WithTrustForwardedHeaders(true).WithPreserveHost(true)
+
+	app := New()
+	app.GET("/", proxy.Action)
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	assert.Nil(err)
+	req.Host = "app.example.com"
+	req.Header.Set(HeaderXForwardedFor, "10.0.0.1")
+	req.Header.Set(HeaderXForwardedProto, "https")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(err)
+	res.Body.Close()
+
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("10.0.0.1, 127.0.0.1", forwarded.Get(HeaderXForwardedFor))
+	assert.Equal("https", forwarded.Get(HeaderXForwardedProto))
+	assert.Equal("app.example.com", forwarded.Get(HeaderXForwardedHost))
+}
+
+func TestReverseProxyBadGateway(t *testing.T) {
+	assert := assert.New(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(err)
+	upstreamURL := &url.URL{Scheme: "http", Host: listener.Addr().String()}
+	listener.Close()
+
+	app := New()
+	app.GET("/", NewReverseProxy(upstreamURL).Action)
+	_, meta, err := app.Mock().WithPathf("/").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadGateway, meta.StatusCode)
+}
+
+func TestReverseProxyUpgrade(t *testing.T) {
+	assert := assert.New(t)
+
+	// the upstream upgrades to a line echo protocol.
+	upstream := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get(HeaderUpgrade) != "echo" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, buffer, err := rw.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buffer.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		buffer.Flush()
+		line, err := buffer.ReadString('\n')
+		if err != nil {
+			return
+		}
+		buffer.WriteString("echo: " + line)
+		buffer.Flush()
+	}))
+	defer upstream.Close()
+
+	upstreamURL, err := url.Parse(upstream.URL)
+	assert.Nil(err)
+	app := New()
+	app.GET("/echo", NewReverseProxy(upstreamURL).Action)
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+	assert.Nil(err)
+	defer conn.Close()
+	fmt.Fprintf(conn, "GET /echo HTTP/1.1\r\nHost: localhost\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, nil)
+	assert.Nil(err)
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal("echo", res.Header.Get(HeaderUpgrade))
+
+	fmt.Fprintf(conn, "hello\n")
+	line, err := reader.ReadString('\n')
+	assert.Nil(err)
+	assert.Equal("echo: hello\n", line)
+}
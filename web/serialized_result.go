@@ -0,0 +1,64 @@
+package web
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// SerializedResult is a result serialized in a given media type by the app's serializers.
+type SerializedResult struct {
+	StatusCode int
+	MediaType  string
+	Response   interface{}
+}
+
+// Render renders the result.
+func (sr *SerializedResult) Render(ctx *Ctx) error {
+	contentType, serializer, ok := ctx.Serializers().Get(sr.MediaType)
+	if !ok {
+		return exception.New(ErrSerialize).WithMessagef("no serializer is registered for %q", sr.MediaType)
+	}
+	// serialize to a buffer first, so a serialization error doesn't leave a partial response.
+	var buffer bytes.Buffer
+	if err := serializer.Serialize(&buffer, sr.Response); err != nil {
+		return exception.New(err)
+	}
+	ctx.Response().Header().Set(HeaderContentType, contentType)
+	statusCode := sr.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	ctx.Response().WriteHeader(statusCode)
+	_, err := ctx.Response().Write(buffer.Bytes())
+	return exception.New(err)
+}
+
+// NegotiatedResult is a result serialized in the media type the request's "Accept" header
+// prefers, of the app's serializers.
+/*
+If the request doesn't accept any of them, it is rendered as a 406 with the media types that are available.
+*/
+type NegotiatedResult struct {
+	StatusCode int
+	Response   interface{}
+}
+
+// Render renders the result.
+func (nr *NegotiatedResult) Render(ctx *Ctx) error {
+	ctx.Response().Header().Add(HeaderVary, HeaderAccept)
+	mediaType, ok := ctx.Serializers().Negotiate(ctx.Request())
+	if !ok {
+		ctx.Response().Header().Set(HeaderContentType, ContentTypeText)
+		ctx.Response().WriteHeader(http.StatusNotAcceptable)
+		_, err := ctx.Response().Write([]byte("Not Acceptable; available media types: " + strings.Join(ctx.Serializers().MediaTypes(), ", ")))
+		return exception.New(err)
+	}
+	return (&SerializedResult{
+		StatusCode: nr.StatusCode,
+		MediaType:  mediaType,
+		Response:   nr.Response,
+	}).Render(ctx)
+}
@@ -0,0 +1,152 @@
+package web
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+var (
+	_ Serializer = (*SerializerFunc)(nil)
+	_ Serializer = (*JSONSerializer)(nil)
+	_ Serializer = (*XMLSerializer)(nil)
+)
+
+// Serializer writes objects to responses in a media type.
+type Serializer interface {
+	Serialize(w io.Writer, object interface{}) error
+}
+
+// SerializerFunc is a function that implements Serializer.
+type SerializerFunc func(w io.Writer, object interface{}) error
+
+// Serialize implements Serializer.
+func (sf SerializerFunc) Serialize(w io.Writer, object interface{}) error {
+	return sf(w, object)
+}
+
+// JSONSerializer serializes objects as json.
+type JSONSerializer struct{}
+
+// Serialize implements Serializer.
+func (JSONSerializer) Serialize(w io.Writer, object interface{}) error {
+	return exception.New(json.NewEncoder(w).Encode(object))
+}
+
+// XMLSerializer serializes objects as xml.
+type XMLSerializer struct{}
+
+// Serialize implements Serializer.
+func (XMLSerializer) Serialize(w io.Writer, object interface{}) error {
+	return exception.New(xml.NewEncoder(w).Encode(object))
+}
+
+// serializedField is a struct field written by a serializer.
+type serializedField struct {
+	Name      string
+	Index     []int
+	OmitEmpty bool
+}
+
+// serializedFields returns the fields of a struct type a serializer writes, named by the first of the given
+// tags that is set, like the json package; embedded structs without a name have their fields promoted.
+func serializedFields(t reflect.Type, tags ...string) []serializedField {
+	var fields []serializedField
+	seen := map[string]bool{}
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		var embedded []reflect.StructField
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, omitEmpty := field.Name, false
+			var named bool
+			for _, tag := range tags {
+				value, ok := field.Tag.Lookup(tag)
+				if !ok {
+					continue
+				}
+				if value == "-" {
+					name = ""
+					break
+				}
+				parts := strings.Split(value, ",")
+				if parts[0] != "" {
+					name, named = parts[0], true
+				}
+				for _, option := range parts[1:] {
+					omitEmpty = omitEmpty || option == "omitempty"
+				}
+				break
+			}
+			if name == "" {
+				continue
+			}
+			if field.Anonymous && !named {
+				fieldType := field.Type
+				if fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+				if fieldType.Kind() == reflect.Struct {
+					embedded = append(embedded, field)
+					continue
+				}
+			}
+			if field.PkgPath != "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			fields = append(fields, serializedField{
+				Name:      name,
+				Index:     append(append([]int(nil), index...), i),
+				OmitEmpty: omitEmpty,
+			})
+		}
+		// promoted fields are shadowed by fields of the outer struct.
+		for _, field := range embedded {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			walk(fieldType, append(append([]int(nil), index...), field.Index...))
+		}
+	}
+	walk(t, nil)
+	return fields
+}
+
+// fieldByIndex returns a field of a struct value, or false if it is in a nil embedded struct.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, fieldIndex := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(fieldIndex)
+	}
+	return v, true
+}
+
+// isEmptyValue returns if a value is omitted by "omitempty".
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
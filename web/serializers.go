@@ -0,0 +1,192 @@
+package web
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NewSerializers returns a serializer registry with the built-in serializers registered,
+// for json (the default), xml, MessagePack and csv.
+func NewSerializers() *Serializers {
+	return new(Serializers).
+		Register(ContentTypeApplicationJSON, JSONSerializer{}).
+		Register(ContentTypeApplicationXML, XMLSerializer{}).
+		Register(ContentTypeXML, XMLSerializer{}).
+		Register(ContentTypeApplicationMsgPack, MsgPackSerializer{}).
+		Register("application/x-msgpack", MsgPackSerializer{}).
+		Register(ContentTypeCSV, CSVSerializer{})
+}
+
+// Serializers is a registry of serializers by media type, used to negotiate the media type of responses.
+type Serializers struct {
+	lock          sync.RWMutex
+	registrations []serializerRegistration
+}
+
+type serializerRegistration struct {
+	MediaType   string
+	ContentType string
+	Serializer  Serializer
+}
+
+// Register registers a serializer for a content type, e.g. "application/vnd.api+json" or
+// "text/csv; charset=utf-8", replacing any serializer already registered for its media type.
+// The content type is sent as the "Content-Type" header of responses it serializes.
+// Serializers registered first are preferred when a request accepts more than one equally.
+func (s *Serializers) Register(contentType string, serializer Serializer) *Serializers {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	registration := serializerRegistration{
+		MediaType:   mediaTypeOf(contentType),
+		ContentType: contentType,
+		Serializer:  serializer,
+	}
+	for index := range s.registrations {
+		if s.registrations[index].MediaType == registration.MediaType {
+			s.registrations[index] = registration
+			return s
+		}
+	}
+	s.registrations = append(s.registrations, registration)
+	return s
+}
+
+// Remove removes the serializer for a media type.
+func (s *Serializers) Remove(mediaType string) *Serializers {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	mediaType = mediaTypeOf(mediaType)
+	for index := range s.registrations {
+		if s.registrations[index].MediaType == mediaType {
+			s.registrations = append(s.registrations[:index], s.registrations[index+1:]...)
+			break
+		}
+	}
+	return s
+}
+
+// MediaTypes returns the registered media types, in order of preference.
+func (s *Serializers) MediaTypes() []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	mediaTypes := make([]string, len(s.registrations))
+	for index, registration := range s.registrations {
+		mediaTypes[index] = registration.MediaType
+	}
+	return mediaTypes
+}
+
+// Get returns the content type and serializer for a media type, or false if none is registered.
+func (s *Serializers) Get(mediaType string) (contentType string, serializer Serializer, ok bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	mediaType = mediaTypeOf(mediaType)
+	for _, registration := range s.registrations {
+		if registration.MediaType == mediaType {
+			return registration.ContentType, registration.Serializer, true
+		}
+	}
+	return "", nil, false
+}
+
+// Negotiate returns the media type of the serializer that best matches a request's "Accept" header,
+// or false if the request doesn't accept any of them.
+func (s *Serializers) Negotiate(r *http.Request) (mediaType string, ok bool) {
+	return NegotiateMediaType(r, s.MediaTypes()...)
+}
+
+// NegotiateMediaType returns the offered media type a request's "Accept" header gives the highest quality,
+// preferring the first offered on a tie, or false if none are acceptable.
+// Requests without an "Accept" header accept the first offer.
+func NegotiateMediaType(r *http.Request, offers ...string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+	ranges := parseAccept(r.Header[HeaderAccept])
+	if len(ranges) == 0 {
+		return offers[0], true
+	}
+
+	var best string
+	var bestQuality float64
+	for _, offer := range offers {
+		mediaType := mediaTypeOf(offer)
+		quality, specificity := 0.0, -1
+		// the most specific range that matches an offer sets its quality.
+		for _, acceptRange := range ranges {
+			if rangeSpecificity := acceptRange.Match(mediaType); rangeSpecificity > specificity {
+				quality, specificity = acceptRange.Quality, rangeSpecificity
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best, best != ""
+}
+
+type acceptRange struct {
+	Type    string
+	Subtype string
+	Quality float64
+}
+
+// Match returns how specifically the range matches a media type; 2 for an exact match, 1 for "type/*",
+// 0 for "*/*", and -1 if it doesn't.
+func (ar acceptRange) Match(mediaType string) int {
+	mediaTypeType, mediaTypeSubtype := mediaType, ""
+	if slash := strings.Index(mediaType, "/"); slash >= 0 {
+		mediaTypeType, mediaTypeSubtype = mediaType[:slash], mediaType[slash+1:]
+	}
+	switch {
+	case ar.Type == "*" && ar.Subtype == "*":
+		return 0
+	case ar.Type == mediaTypeType && ar.Subtype == "*":
+		return 1
+	case ar.Type == mediaTypeType && ar.Subtype == mediaTypeSubtype:
+		return 2
+	}
+	return -1
+}
+
+func parseAccept(values []string) []acceptRange {
+	var ranges []acceptRange
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			params := strings.Split(part, ";")
+			mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+			if mediaType == "" {
+				continue
+			}
+			parsed := acceptRange{Type: mediaType, Subtype: "*", Quality: 1}
+			if slash := strings.Index(mediaType, "/"); slash >= 0 {
+				parsed.Type, parsed.Subtype = mediaType[:slash], mediaType[slash+1:]
+			}
+			for _, param := range params[1:] {
+				param = strings.TrimSpace(param)
+				if strings.HasPrefix(param, "q=") {
+					if quality, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+						parsed.Quality = quality
+					}
+				}
+			}
+			ranges = append(ranges, parsed)
+		}
+	}
+	return ranges
+}
+
+// mediaTypeOf returns the media type of a content type, without parameters like the charset.
+func mediaTypeOf(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	assert := assert.New(t)
+
+	offers := []string{"application/json", "application/xml", "text/csv"}
+	testCases := []struct {
+		Accept   string
+		Expected string
+	}{
+		{Accept: "", Expected: "application/json"},
+		{Accept: "*/*", Expected: "application/json"},
+		{Accept: "text/csv", Expected: "text/csv"},
+		{Accept: "Application/XML", Expected: "application/xml"},
+		{Accept: "text/*", Expected: "text/csv"},
+		{Accept: "text/html, application/xml;q=0.9, */*;q=0.8", Expected: "application/xml"},
+		{Accept: "application/json;q=0.5, text/csv", Expected: "text/csv"},
+		{Accept: "application/json;q=0.5, application/xml;q=0.5", Expected: "application/json"},
+		{Accept: "*/*, application/json;q=0", Expected: "application/xml"},
+		{Accept: "text/html", Expected: ""},
+	}
+	for _, testCase := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if testCase.Accept != "" {
+			req.Header.Set(HeaderAccept, testCase.Accept)
+		}
+		mediaType, ok := NegotiateMediaType(req, offers...)
+		assert.Equal(testCase.Expected, mediaType, testCase.Accept)
+		assert.Equal(testCase.Expected != "", ok, testCase.Accept)
+	}
+}
+
+func TestSerializers(t *testing.T) {
+	assert := assert.New(t)
+
+	serializers := NewSerializers()
+	assert.Equal([]string{"application/json", "application/xml", "text/xml", "application/msgpack", "application/x-msgpack", "text/csv"}, serializers.MediaTypes())
+
+	contentType, serializer, ok := serializers.Get("text/csv; charset=utf-8")
+	assert.True(ok)
+	assert.Equal(ContentTypeCSV, contentType)
+	assert.Equal(CSVSerializer{}, serializer)
+
+	upper := SerializerFunc(func(w io.Writer, object interface{}) error {
+		_, err := io.WriteString(w, strings.ToUpper(object.(string)))
+		return err
+	})
+	serializers.Register("text/x-upper", upper).Register("text/csv", upper).Remove("text/xml")
+	assert.Equal([]string{"application/json", "application/xml", "application/msgpack", "application/x-msgpack", "text/csv", "text/x-upper"}, serializers.MediaTypes())
+	contentType, _, ok = serializers.Get("text/csv")
+	assert.True(ok)
+	assert.Equal("text/csv", contentType)
+
+	_, _, ok = serializers.Get("text/html")
+	assert.False(ok)
+}
+
+func TestAppNegotiated(t *testing.T) {
+	assert := assert.New(t)
+
+	type user struct {
+		ID   int    `json:"id" xml:"id"`
+		Name string `json:"name" xml:"name"`
+	}
+	app := New()
+	app.Serializers().Register("text/x-upper", SerializerFunc(func(w io.Writer, object interface{}) error {
+		_, err := io.WriteString(w, strings.ToUpper(object.(user).Name))
+		return err
+	}))
+	app.GET("/", func(r *Ctx) Result {
+		return r.DefaultResultProvider().Status(http.StatusCreated, user{ID: 1, Name: "bailey"})
+	}, NegotiatedProviderAsDefault)
+	app.GET("/export", func(r *Ctx) Result {
+		return r.Serialized(ContentTypeCSV, []user{{ID: 1, Name: "bailey"}, {ID: 2, Name: "riley"}})
+	})
+
+	get := func(path, accept string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if accept != "" {
+			req.Header.Set(HeaderAccept, accept)
+		}
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, req)
+		return w.Result()
+	}
+	body := func(res *http.Response) string {
+		contents, err := ioutil.ReadAll(res.Body)
+		assert.Nil(err)
+		return string(contents)
+	}
+
+	res := get("/", "")
+	assert.Equal(http.StatusCreated, res.StatusCode)
+	assert.Equal(ContentTypeApplicationJSON, res.Header.Get(HeaderContentType))
+	assert.Equal(HeaderAccept, res.Header.Get(HeaderVary))
+	assert.Equal("{\"id\":1,\"name\":\"bailey\"}\n", body(res))
+
+	res = get("/", "application/xml")
+	assert.Equal(ContentTypeApplicationXML, res.Header.Get(HeaderContentType))
+	assert.Equal("<user><id>1</id><name>bailey</name></user>", body(res))
+
+	res = get("/", "text/csv")
+	assert.Equal(ContentTypeCSV, res.Header.Get(HeaderContentType))
+	assert.Equal("id,name\n1,bailey\n", body(res))
+
+	res = get("/", "application/msgpack")
+	assert.Equal(ContentTypeApplicationMsgPack, res.Header.Get(HeaderContentType))
+	assert.Equal("\x82\xa2id\x01\xa4name\xa6bailey", body(res))
+
+	res = get("/", "text/x-upper")
+	assert.Equal(http.StatusCreated, res.StatusCode)
+	assert.Equal("BAILEY", body(res))
+
+	res = get("/", "text/html")
+	assert.Equal(http.StatusNotAcceptable, res.StatusCode)
+	assert.Contains(body(res), "application/json, application/xml")
+
+	res = get("/export", "application/json")
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal("id,name\n1,bailey\n2,riley\n", body(res))
+}
@@ -21,6 +21,8 @@ type Session struct {
 	SessionID  string                 `json:"sessionID" yaml:"sessionID"`
 	CreatedUTC time.Time              `json:"createdUTC" yaml:"createdUTC"`
 	ExpiresUTC time.Time              `json:"expiresUTC" yaml:"expiresUTC"`
+	RotatedUTC time.Time              `json:"rotatedUTC,omitempty" yaml:"rotatedUTC,omitempty"`
+	ReplacedBy string                 `json:"replacedBy,omitempty" yaml:"replacedBy,omitempty"`
 	UserAgent  string                 `json:"userAgent" yaml:"userAgent"`
 	RemoteAddr string                 `json:"remoteAddr" yaml:"remoteAddr"`
 	State      map[string]interface{} `json:"state,omitempty" yaml:"state,omitempty"`
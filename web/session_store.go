@@ -0,0 +1,30 @@
+package web
+
+import "context"
+
+// SessionStore is a backing store for server tracked sessions, see `AuthManager.WithSessionStore`.
+// `FetchSession` should return nil, and no error, if the session is not found.
+type SessionStore interface {
+	FetchSession(ctx context.Context, sessionID string) (*Session, error)
+	PersistSession(ctx context.Context, session *Session) error
+	RemoveSession(ctx context.Context, sessionID string) error
+}
+
+type sessionContextKey struct{}
+
+// WithSessionContext returns a context with a given session.
+func WithSessionContext(ctx context.Context, session *Session) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, session)
+}
+
+// GetSessionFromContext returns the session from a context, if one is set.
+// Sessions set on a `Ctx` with `WithSession`, e.g. by `SessionAware`, are also set on the request context.
+func GetSessionFromContext(ctx context.Context) *Session {
+	if ctx == nil {
+		return nil
+	}
+	if session, ok := ctx.Value(sessionContextKey{}).(*Session); ok {
+		return session
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+type mockRedisClient struct {
+	sync.Mutex
+	values      map[string]string
+	expirations map[string]time.Duration
+}
+
+func (mrc *mockRedisClient) Get(_ context.Context, key string) (string, bool, error) {
+	mrc.Lock()
+	defer mrc.Unlock()
+	value, found := mrc.values[key]
+	return value, found, nil
+}
+
+func (mrc *mockRedisClient) Set(_ context.Context, key, value string, expiration time.Duration) error {
+	mrc.Lock()
+	defer mrc.Unlock()
+	mrc.values[key] = value
+	mrc.expirations[key] = expiration
+	return nil
+}
+
+func (mrc *mockRedisClient) Del(_ context.Context, key string) error {
+	mrc.Lock()
+	defer mrc.Unlock()
+	delete(mrc.values, key)
+	return nil
+}
+
+func TestSessionContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(GetSessionFromContext(context.Background()))
+
+	session := NewSession("bailey@blend.com", NewSessionID())
+	r := NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequest("GET", "/"), nil, nil)
+	r.WithSession(session)
+	assert.Equal(session, GetSessionFromContext(r.Context()))
+	assert.Equal(session, GetSessionFromContext(r.Request().Context()))
+}
+
+func TestRedisSessionStore(t *testing.T) {
+	assert := assert.New(t)
+
+	client := &mockRedisClient{values: map[string]string{}, expirations: map[string]time.Duration{}}
+	store := NewRedisSessionStore(client).WithKeyPrefix("test:")
+
+	session := NewSession("bailey@blend.com", NewSessionID())
+	session.ExpiresUTC = time.Now().UTC().Add(time.Hour)
+	session.State["foo"] = "bar"
+	assert.Nil(store.PersistSession(context.Background(), session))
+	assert.NotEmpty(client.values["test:"+session.SessionID])
+	assert.True(client.expirations["test:"+session.SessionID] > 59*time.Minute)
+
+	fetched, err := store.FetchSession(context.Background(), session.SessionID)
+	assert.Nil(err)
+	assert.NotNil(fetched)
+	assert.Equal(session.UserID, fetched.UserID)
+	assert.Equal("bar", fetched.State["foo"])
+
+	assert.Nil(store.RemoveSession(context.Background(), session.SessionID))
+	fetched, err = store.FetchSession(context.Background(), session.SessionID)
+	assert.Nil(err)
+	assert.Nil(fetched)
+}
+
+func TestAuthManagerSessionStore(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalSessionCache()
+	am := NewSessionStoreAuthManager(store).WithCookieSameSite(http.SameSiteStrictMode)
+
+	res := NewMockResponseWriter(new(bytes.Buffer))
+	session, err := am.Login("bailey@blend.com", NewCtx(res, NewMockRequest("GET", "/"), nil, nil))
+	assert.Nil(err)
+	assert.NotNil(store.Get(session.SessionID))
+	assert.True(strings.Contains(res.Header().Get(HeaderSetCookie), "SameSite=Strict"))
+	assert.True(strings.Contains(res.Header().Get(HeaderSetCookie), "HttpOnly"))
+
+	r := NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequestWithCookie("GET", "/", am.CookieName(), session.SessionID), nil, nil)
+	verified, err := am.VerifySession(r)
+	assert.Nil(err)
+	assert.NotNil(verified)
+
+	assert.Nil(am.Logout(r))
+	assert.Nil(store.Get(session.SessionID))
+}
+
+func TestAuthManagerRotateSession(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalSessionCache()
+	am := NewSessionStoreAuthManager(store)
+
+	session, err := am.Login("bailey@blend.com", NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequest("GET", "/"), nil, nil))
+	assert.Nil(err)
+	previousSessionID := session.SessionID
+
+	r := NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequestWithCookie("GET", "/", am.CookieName(), session.SessionID), nil, nil)
+	_, err = am.RotateSession(r)
+	assert.NotNil(err, "the session must be verified first")
+
+	verified, err := am.VerifySession(r)
+	assert.Nil(err)
+	r.WithSession(verified)
+
+	rotated, err := am.RotateSession(r)
+	assert.Nil(err)
+	assert.NotEqual(previousSessionID, rotated.SessionID)
+	assert.False(rotated.RotatedUTC.IsZero())
+	assert.NotNil(store.Get(rotated.SessionID))
+	assert.Equal(rotated, GetSessionFromContext(r.Context()))
+	assert.True(strings.HasPrefix(r.Response().Header().Get(HeaderSetCookie), am.CookieName()+"="+rotated.SessionID))
+
+	// the previous id is still valid for the grace period, but isn't rotated or extended again.
+	previous := store.Get(previousSessionID)
+	assert.NotNil(previous)
+	assert.Equal(rotated.SessionID, previous.ReplacedBy)
+	assert.True(previous.ExpiresUTC.Before(rotated.RotatedUTC.Add(DefaultSessionRotationGracePeriod + time.Second)))
+
+	r = NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequestWithCookie("GET", "/", am.CookieName(), previousSessionID), nil, nil)
+	verified, err = am.VerifySession(r)
+	assert.Nil(err)
+	assert.NotNil(verified)
+	assert.Equal(previousSessionID, verified.SessionID)
+	assert.Empty(r.Response().Header().Get(HeaderSetCookie))
+
+	previous.ExpiresUTC = time.Now().UTC().Add(-time.Second)
+	verified, err = am.VerifySession(r)
+	assert.Nil(err)
+	assert.Nil(verified)
+	assert.Nil(store.Get(previousSessionID))
+}
+
+func TestAuthManagerRotateSessionWithoutGracePeriod(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalSessionCache()
+	am := NewSessionStoreAuthManager(store).WithSessionRotationGracePeriod(0)
+
+	session, err := am.Login("bailey@blend.com", NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequest("GET", "/"), nil, nil))
+	assert.Nil(err)
+	previousSessionID := session.SessionID
+
+	r := NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequestWithCookie("GET", "/", am.CookieName(), session.SessionID), nil, nil)
+	verified, err := am.VerifySession(r)
+	assert.Nil(err)
+	r.WithSession(verified)
+
+	rotated, err := am.RotateSession(r)
+	assert.Nil(err)
+	assert.Nil(store.Get(previousSessionID))
+	assert.NotNil(store.Get(rotated.SessionID))
+}
+
+func TestAuthManagerDefaultCookieSameSite(t *testing.T) {
+	assert := assert.New(t)
+
+	managers := []*AuthManager{
+		NewLocalAuthManager(),
+		NewServerAuthManager(),
+		NewSessionStoreAuthManager(NewLocalSessionCache()),
+		NewJWTAuthManager([]byte("test-key")),
+		NewAuthManagerFromConfig(&Config{}),
+	}
+	for _, am := range managers {
+		assert.Equal(http.SameSiteLaxMode, am.CookieSameSite())
+		assert.Equal(DefaultSessionRotationGracePeriod, am.SessionRotationGracePeriod())
+	}
+}
+
+func TestAuthManagerSessionRotationInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	store := NewLocalSessionCache()
+	am := NewSessionStoreAuthManager(store).WithSessionRotationInterval(time.Hour)
+
+	session, err := am.Login("bailey@blend.com", NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequest("GET", "/"), nil, nil))
+	assert.Nil(err)
+	sessionID := session.SessionID
+
+	r := NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequestWithCookie("GET", "/", am.CookieName(), sessionID), nil, nil)
+	verified, err := am.VerifySession(r)
+	assert.Nil(err)
+	assert.Equal(sessionID, verified.SessionID)
+
+	verified.CreatedUTC = time.Now().UTC().Add(-2 * time.Hour)
+	verified, err = am.VerifySession(r)
+	assert.Nil(err)
+	assert.NotEqual(sessionID, verified.SessionID)
+	assert.Equal(verified.SessionID, store.Get(sessionID).ReplacedBy)
+}
@@ -0,0 +1,160 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// ContentTypeEventStream is the content type for server sent event streams.
+	ContentTypeEventStream = "text/event-stream"
+	// HeaderLastEventID is the header clients send the id of the last event they received in when they reconnect.
+	HeaderLastEventID = "Last-Event-ID"
+	// HeaderXAccelBuffering is the "X-Accel-Buffering" header; it disables response buffering in nginx.
+	HeaderXAccelBuffering = "X-Accel-Buffering"
+
+	// DefaultSSEKeepAliveInterval is the default interval keep alive comments are sent on.
+	DefaultSSEKeepAliveInterval = 15 * time.Second
+)
+
+// SSEEvent is a server sent event.
+type SSEEvent struct {
+	// ID is the event id; clients send the last id they received in the `Last-Event-ID` header when they reconnect.
+	ID string
+	// Event is the event type; if unset clients dispatch a "message" event.
+	Event string
+	// Data is the event data; it is sent as one `data` field per line.
+	Data string
+	// Retry is the time clients should wait before reconnecting; zero leaves it unchanged.
+	Retry time.Duration
+}
+
+// WriteTo writes the event, framed for an event stream, to a given writer.
+func (e SSEEvent) WriteTo(w io.Writer) (int64, error) {
+	buffer := new(bytes.Buffer)
+	if len(e.ID) > 0 {
+		writeSSEField(buffer, "id", e.ID)
+	}
+	if len(e.Event) > 0 {
+		writeSSEField(buffer, "event", e.Event)
+	}
+	if e.Retry > 0 {
+		writeSSEField(buffer, "retry", strconv.FormatInt(int64(e.Retry/time.Millisecond), 10))
+	}
+	for _, line := range strings.Split(strings.Replace(e.Data, "\r\n", "\n", -1), "\n") {
+		writeSSEField(buffer, "data", line)
+	}
+	buffer.WriteString("\n")
+	return buffer.WriteTo(w)
+}
+
+// writeSSEField writes a field line; carriage returns and newlines are removed from the value,
+// as they would end the field.
+func writeSSEField(buffer *bytes.Buffer, name, value string) {
+	buffer.WriteString(name)
+	buffer.WriteString(": ")
+	buffer.WriteString(strings.NewReplacer("\r", "", "\n", "").Replace(value))
+	buffer.WriteString("\n")
+}
+
+// SSEHandler is a handler for a server sent event stream; the stream ends when it returns.
+type SSEHandler func(*SSEWriter) error
+
+// newSSEWriter returns a new event stream writer for a request.
+func newSSEWriter(ctx *Ctx) *SSEWriter {
+	return &SSEWriter{ctx: ctx}
+}
+
+// SSEWriter writes server sent events to a client, flushing each one.
+// It is safe to use from multiple goroutines.
+type SSEWriter struct {
+	ctx *Ctx
+
+	writeLock sync.Mutex
+	closed    bool
+}
+
+// Ctx returns the request context for the stream.
+func (sw *SSEWriter) Ctx() *Ctx {
+	return sw.ctx
+}
+
+// LastEventID returns the id of the last event the client received, if it is reconnecting.
+func (sw *SSEWriter) LastEventID() string {
+	return sw.ctx.Request().Header.Get(HeaderLastEventID)
+}
+
+// Done returns a channel that is closed when the client disconnects.
+func (sw *SSEWriter) Done() <-chan struct{} {
+	return sw.ctx.Context().Done()
+}
+
+// Send writes an event.
+func (sw *SSEWriter) Send(event SSEEvent) error {
+	return sw.write(event)
+}
+
+// SendData writes an event with only data.
+func (sw *SSEWriter) SendData(data string) error {
+	return sw.write(SSEEvent{Data: data})
+}
+
+// SendJSON writes an event of a given type with a value serialized as json as its data.
+func (sw *SSEWriter) SendJSON(event string, v interface{}) error {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return exception.New(err)
+	}
+	return sw.write(SSEEvent{Event: event, Data: string(contents)})
+}
+
+// Comment writes a comment, which clients ignore; it is used to keep idle connections open.
+func (sw *SSEWriter) Comment(text string) error {
+	buffer := new(bytes.Buffer)
+	for _, line := range strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n") {
+		buffer.WriteString(": ")
+		buffer.WriteString(strings.Replace(line, "\r", "", -1))
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("\n")
+	return sw.write(buffer)
+}
+
+// write writes to the response and flushes it, returning `ErrSSEClosed` if the client has disconnected
+// or the handler has returned.
+func (sw *SSEWriter) write(output io.WriterTo) error {
+	sw.writeLock.Lock()
+	defer sw.writeLock.Unlock()
+	if sw.closed {
+		return exception.New(ErrSSEClosed)
+	}
+	if err := sw.ctx.Context().Err(); err != nil {
+		return exception.New(ErrSSEClosed).WithInner(err)
+	}
+	if _, err := output.WriteTo(sw.ctx.Response()); err != nil {
+		return exception.New(ErrSSEClosed).WithInner(err)
+	}
+	if err := flushResponse(sw.ctx.Response()); err != nil {
+		return exception.New(ErrSSEClosed).WithInner(err)
+	}
+	return nil
+}
+
+// close stops further writes, e.g. from goroutines the handler started, once the handler returns.
+func (sw *SSEWriter) close() {
+	sw.writeLock.Lock()
+	sw.closed = true
+	sw.writeLock.Unlock()
+}
+
+// IsSSEClosed returns if an error is from writing to an event stream after the client disconnected.
+func IsSSEClosed(err error) bool {
+	return exception.Is(err, ErrSSEClosed)
+}
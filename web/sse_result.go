@@ -0,0 +1,77 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// NewSSEResult returns a new server sent event result with the default keep alive interval.
+func NewSSEResult(handler SSEHandler) *SSEResult {
+	return &SSEResult{
+		Handler:           handler,
+		KeepAliveInterval: DefaultSSEKeepAliveInterval,
+	}
+}
+
+// SSEResult is a result that streams server sent events from a handler until it returns.
+// Handlers should return when `Done()` is closed, i.e. the client disconnected; streams are subject
+// to the server's write timeout, so apps serving long lived streams should leave it unset.
+type SSEResult struct {
+	Handler SSEHandler
+	// KeepAliveInterval is the interval comments are sent on to keep idle connections open; zero disables them.
+	KeepAliveInterval time.Duration
+}
+
+// Render writes the stream headers and runs the handler.
+func (sr *SSEResult) Render(ctx *Ctx) error {
+	if sr.Handler == nil {
+		return exception.New(ErrSSEClosed).WithMessage("sse handler is unset")
+	}
+
+	header := ctx.Response().Header()
+	header.Set(HeaderContentType, ContentTypeEventStream)
+	header.Set(HeaderCacheControl, "no-cache")
+	header.Set(HeaderXAccelBuffering, "no")
+	header.Del(HeaderContentLength)
+	ctx.Response().WriteHeader(http.StatusOK)
+	if err := flushResponse(ctx.Response()); err != nil {
+		return exception.New(err)
+	}
+
+	sw := newSSEWriter(ctx)
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		sw.close()
+	}()
+	if sr.KeepAliveInterval > 0 {
+		go sr.keepAlive(sw, done)
+	}
+
+	err := sr.Handler(sw)
+	if err != nil && (IsSSEClosed(err) || ctx.Context().Err() != nil) {
+		// the client disconnecting is how streams normally end.
+		return nil
+	}
+	return err
+}
+
+// keepAlive sends comments on the keep alive interval until the handler returns or the client disconnects.
+func (sr *SSEResult) keepAlive(sw *SSEWriter, done <-chan struct{}) {
+	ticker := time.NewTicker(sr.KeepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-sw.Done():
+			return
+		case <-ticker.C:
+			if err := sw.Comment("keep-alive"); err != nil {
+				return
+			}
+		}
+	}
+}
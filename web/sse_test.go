@@ -0,0 +1,106 @@
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSSEEventWriteTo(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	_, err := SSEEvent{ID: "1\n2", Event: "update", Data: "line one\r\nline two", Retry: 3 * time.Second}.WriteTo(buffer)
+	assert.Nil(err)
+	assert.Equal("id: 12\nevent: update\nretry: 3000\ndata: line one\ndata: line two\n\n", buffer.String())
+
+	buffer.Reset()
+	_, err = SSEEvent{}.WriteTo(buffer)
+	assert.Nil(err)
+	assert.Equal("data: \n\n", buffer.String())
+}
+
+func TestSSEResult(t *testing.T) {
+	assert := assert.New(t)
+
+	next := make(chan struct{})
+	app := New()
+	app.GET("/events", func(r *Ctx) Result {
+		return r.SSE(func(sw *SSEWriter) error {
+			if err := sw.Send(SSEEvent{ID: "1", Data: "resumed from " + sw.LastEventID()}); err != nil {
+				return err
+			}
+			<-next
+			return sw.SendJSON("user", map[string]string{"name": "bailey"})
+		})
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	req, err := http.NewRequest("GET", server.URL+"/events", nil)
+	assert.Nil(err)
+	req.Header.Set(HeaderLastEventID, "0")
+	res, err := http.DefaultClient.Do(req)
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	assert.Equal(ContentTypeEventStream, res.Header.Get(HeaderContentType))
+	assert.Equal("no-cache", res.Header.Get(HeaderCacheControl))
+
+	// the first event is flushed before the handler sends the second.
+	reader := bufio.NewReader(res.Body)
+	assert.Equal("id: 1\ndata: resumed from 0\n\n", readSSEEvent(reader))
+	close(next)
+	assert.Equal("event: user\ndata: {\"name\":\"bailey\"}\n\n", readSSEEvent(reader))
+}
+
+func TestSSEResultKeepAliveDisconnect(t *testing.T) {
+	assert := assert.New(t)
+
+	returned := make(chan error, 1)
+	app := New()
+	app.GET("/events", func(r *Ctx) Result {
+		result := r.SSE(func(sw *SSEWriter) error {
+			<-sw.Done()
+			err := sw.SendData("too late")
+			returned <- err
+			return err
+		})
+		result.KeepAliveInterval = 10 * time.Millisecond
+		return result
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/events")
+	assert.Nil(err)
+	assert.Equal(": keep-alive\n\n", readSSEEvent(bufio.NewReader(res.Body)))
+	res.Body.Close()
+
+	select {
+	case err := <-returned:
+		assert.True(IsSSEClosed(err))
+	case <-time.After(5 * time.Second):
+		assert.FailNow("handler should return when the client disconnects")
+	}
+}
+
+func readSSEEvent(reader *bufio.Reader) string {
+	var event []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return strings.Join(event, "")
+		}
+		event = append(event, line)
+		if line == "\n" {
+			return strings.Join(event, "")
+		}
+	}
+}
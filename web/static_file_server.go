@@ -1,17 +1,43 @@
 package web
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/blend/go-sdk/exception"
 	"github.com/blend/go-sdk/logger"
 )
 
+const (
+	// DefaultStaticIndexFile is the default file served for directories.
+	DefaultStaticIndexFile = "index.html"
+
+	// CacheControlNoCache is a cache control policy that has clients revalidate files before using them.
+	CacheControlNoCache = "no-cache"
+	// CacheControlImmutable is a cache control policy for files that never change, e.g. fingerprinted assets.
+	CacheControlImmutable = "public, max-age=31536000, immutable"
+)
+
 // NewStaticFileServer returns a new static file cache.
 func NewStaticFileServer(fs http.FileSystem) *StaticFileServer {
 	return &StaticFileServer{
 		fileSystem: fs,
+		indexFile:  DefaultStaticIndexFile,
+		modTime:    time.Now().UTC(),
+		etagCache:  map[string]staticETag{},
 	}
 }
 
@@ -22,6 +48,18 @@ type StaticFileServer struct {
 	rewriteRules []RewriteRule
 	middleware   Action
 	headers      http.Header
+
+	precompressed bool
+
+	etags             bool
+	etagLock          sync.Mutex
+	etagCache         map[string]staticETag
+	cacheControl      string
+	cacheControlRules []cacheControlRule
+	indexFile         string
+	directoryListing  bool
+	fallback          string
+	modTime           time.Time
 }
 
 // Log returns a logger reference.
@@ -35,6 +73,102 @@ func (sc *StaticFileServer) WithLogger(log *logger.Logger) *StaticFileServer {
 	return sc
 }
 
+// WithPrecompressed sets if pre-compressed files, i.e. `foo.css.br` or `foo.css.gz` for `foo.css`,
+// are served in place of the file when the client accepts their encoding.
+func (sc *StaticFileServer) WithPrecompressed(precompressed bool) *StaticFileServer {
+	sc.precompressed = precompressed
+	return sc
+}
+
+// Precompressed returns if pre-compressed files are served.
+func (sc *StaticFileServer) Precompressed() bool {
+	return sc.precompressed
+}
+
+// WithETags sets if responses have an `ETag` header, computed from a hash of the file contents,
+// so clients can revalidate with `If-None-Match`.
+func (sc *StaticFileServer) WithETags(etags bool) *StaticFileServer {
+	sc.etags = etags
+	return sc
+}
+
+// ETags returns if responses have an `ETag` header.
+func (sc *StaticFileServer) ETags() bool {
+	return sc.etags
+}
+
+// WithCacheControl sets the default `Cache-Control` header value, e.g. `CacheControlNoCache`.
+func (sc *StaticFileServer) WithCacheControl(cacheControl string) *StaticFileServer {
+	sc.cacheControl = cacheControl
+	return sc
+}
+
+// CacheControl returns the default `Cache-Control` header value.
+func (sc *StaticFileServer) CacheControl() string {
+	return sc.cacheControl
+}
+
+// AddCacheControlRule sets the `Cache-Control` header value for requested paths that match an expression,
+// e.g. `CacheControlImmutable` for fingerprinted assets. The first matching rule is used.
+// Paths are matched without a leading slash, i.e. `^assets/` matches `/static/assets/app.js` served from `/static`.
+func (sc *StaticFileServer) AddCacheControlRule(match, cacheControl string) error {
+	expr, err := regexp.Compile(match)
+	if err != nil {
+		return err
+	}
+	sc.cacheControlRules = append(sc.cacheControlRules, cacheControlRule{
+		expr:         expr,
+		cacheControl: cacheControl,
+	})
+	return nil
+}
+
+// WithIndexFile sets the file served for directories, `index.html` by default; empty disables index files.
+func (sc *StaticFileServer) WithIndexFile(indexFile string) *StaticFileServer {
+	sc.indexFile = indexFile
+	return sc
+}
+
+// IndexFile returns the file served for directories.
+func (sc *StaticFileServer) IndexFile() string {
+	return sc.indexFile
+}
+
+// WithDirectoryListing sets if directories without an index file are listed; if not they are not found.
+func (sc *StaticFileServer) WithDirectoryListing(directoryListing bool) *StaticFileServer {
+	sc.directoryListing = directoryListing
+	return sc
+}
+
+// DirectoryListing returns if directories without an index file are listed.
+func (sc *StaticFileServer) DirectoryListing() bool {
+	return sc.directoryListing
+}
+
+// WithFallback sets a file served in place of files that are not found,
+// e.g. `index.html` for single page apps that route on the client.
+func (sc *StaticFileServer) WithFallback(fallback string) *StaticFileServer {
+	sc.fallback = fallback
+	return sc
+}
+
+// Fallback returns the file served in place of files that are not found.
+func (sc *StaticFileServer) Fallback() string {
+	return sc.fallback
+}
+
+// WithModTime sets the `Last-Modified` time for files that do not have one, e.g. files in an `embed.FS`.
+// It defaults to when the server was created.
+func (sc *StaticFileServer) WithModTime(modTime time.Time) *StaticFileServer {
+	sc.modTime = modTime
+	return sc
+}
+
+// ModTime returns the `Last-Modified` time for files that do not have one.
+func (sc *StaticFileServer) ModTime() time.Time {
+	return sc.modTime
+}
+
 // AddHeader adds a header to the static cache results.
 func (sc *StaticFileServer) AddHeader(key, value string) {
 	if sc.headers == nil {
@@ -93,6 +227,7 @@ func (sc *StaticFileServer) ServeFile(r *Ctx) Result {
 	if err != nil {
 		return r.DefaultResultProvider().InternalError(err)
 	}
+	requestPath := filePath
 
 	for _, rule := range sc.rewriteRules {
 		if matched, newFilePath := rule.Apply(filePath); matched {
@@ -100,21 +235,193 @@ func (sc *StaticFileServer) ServeFile(r *Ctx) Result {
 		}
 	}
 
+	sc.setCacheControl(r, requestPath)
+	result, served := sc.serve(r, filePath)
+	if !served && len(sc.fallback) > 0 {
+		sc.setCacheControl(r, sc.fallback)
+		result, served = sc.serve(r, sc.fallback)
+	}
+	if !served {
+		r.Response().Header().Del(HeaderCacheControl)
+		return r.DefaultResultProvider().NotFound()
+	}
+	return result
+}
+
+// serve serves a file or directory, returning false if it was not found.
+func (sc *StaticFileServer) serve(r *Ctx, filePath string) (Result, bool) {
+	filePath = path.Join("/", filePath)
+	if sc.precompressed {
+		if result, served := sc.servePrecompressed(r, filePath); served {
+			return result, true
+		}
+	}
+
 	f, err := sc.fileSystem.Open(filePath)
 	if f == nil || os.IsNotExist(err) {
-		return r.DefaultResultProvider().NotFound()
+		return nil, false
 	}
 	if err != nil {
-		return r.DefaultResultProvider().InternalError(err)
+		return r.DefaultResultProvider().InternalError(err), true
 	}
 	defer f.Close()
 
 	d, err := f.Stat()
 	if err != nil {
-		return r.DefaultResultProvider().InternalError(err)
+		return r.DefaultResultProvider().InternalError(err), true
+	}
+
+	if d.IsDir() {
+		hasIndex := len(sc.indexFile) > 0 && sc.exists(path.Join(filePath, sc.indexFile))
+		if !hasIndex && !sc.directoryListing {
+			return nil, false
+		}
+		// redirect to the path with a trailing slash so relative links resolve within the directory.
+		if requestPath := r.Request().URL.Path; !strings.HasSuffix(requestPath, "/") {
+			redirect := &url.URL{Path: requestPath + "/", RawQuery: r.Request().URL.RawQuery}
+			return r.Redirect(redirect.String()), true
+		}
+		if hasIndex {
+			return sc.serve(r, path.Join(filePath, sc.indexFile))
+		}
+		return sc.serveDirectory(r, f), true
+	}
+
+	return sc.serveContent(r, filePath, filePath, d, f), true
+}
+
+// exists returns if a file exists and is not a directory.
+func (sc *StaticFileServer) exists(filePath string) bool {
+	f, err := sc.fileSystem.Open(filePath)
+	if err != nil {
+		return false
 	}
+	defer f.Close()
+	d, err := f.Stat()
+	return err == nil && !d.IsDir()
+}
 
-	http.ServeContent(r.Response(), r.Request(), filePath, d.ModTime(), f)
+// serveContent writes a file, setting its `ETag` and `Last-Modified` headers, and handling conditional requests.
+func (sc *StaticFileServer) serveContent(r *Ctx, name, filePath string, d os.FileInfo, f http.File) Result {
+	if sc.etags {
+		etag, err := sc.etag(filePath, d, f)
+		if err != nil {
+			return r.DefaultResultProvider().InternalError(err)
+		}
+		r.Response().Header().Set(HeaderETag, etag)
+	}
+	modTime := d.ModTime()
+	if modTime.IsZero() || modTime.Unix() == 0 {
+		modTime = sc.modTime
+	}
+	http.ServeContent(r.Response(), r.Request(), name, modTime, f)
 	return nil
+}
+
+// etag returns the etag for a file, hashing its contents if they have changed since it was last hashed.
+func (sc *StaticFileServer) etag(filePath string, d os.FileInfo, f http.File) (string, error) {
+	sc.etagLock.Lock()
+	cached, ok := sc.etagCache[filePath]
+	sc.etagLock.Unlock()
+	if ok && cached.size == d.Size() && cached.modTime.Equal(d.ModTime()) {
+		return cached.etag, nil
+	}
 
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", exception.New(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", exception.New(err)
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+
+	sc.etagLock.Lock()
+	sc.etagCache[filePath] = staticETag{etag: etag, size: d.Size(), modTime: d.ModTime()}
+	sc.etagLock.Unlock()
+	return etag, nil
+}
+
+// setCacheControl sets the `Cache-Control` header from the first rule that matches the path, or the default.
+func (sc *StaticFileServer) setCacheControl(r *Ctx, filePath string) {
+	filePath = strings.TrimPrefix(path.Join("/", filePath), "/")
+	cacheControl := sc.cacheControl
+	for _, rule := range sc.cacheControlRules {
+		if rule.expr.MatchString(filePath) {
+			cacheControl = rule.cacheControl
+			break
+		}
+	}
+	if len(cacheControl) > 0 {
+		r.Response().Header().Set(HeaderCacheControl, cacheControl)
+	}
+}
+
+// serveDirectory writes an html list of the files in a directory.
+func (sc *StaticFileServer) serveDirectory(r *Ctx, f http.File) Result {
+	entries, err := f.Readdir(-1)
+	if err != nil {
+		return r.DefaultResultProvider().InternalError(err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	buffer := new(bytes.Buffer)
+	buffer.WriteString("<html><body><pre>\n")
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		fmt.Fprintf(buffer, "<a href=\"%s\">%s</a>\n", (&url.URL{Path: name}).String(), html.EscapeString(name))
+	}
+	buffer.WriteString("</pre></body></html>\n")
+	return r.RawWithContentType(ContentTypeHTML, buffer.Bytes())
+}
+
+type cacheControlRule struct {
+	expr         *regexp.Regexp
+	cacheControl string
+}
+
+type staticETag struct {
+	etag    string
+	size    int64
+	modTime time.Time
+}
+
+// precompressedEncodings are the encodings of pre-compressed files and their extensions, in order of preference.
+var precompressedEncodings = []struct {
+	Encoding  string
+	Extension string
+}{
+	{Encoding: ContentEncodingBrotli, Extension: ".br"},
+	{Encoding: ContentEncodingGZIP, Extension: ".gz"},
+}
+
+// servePrecompressed serves a pre-compressed version of a file if one exists in an encoding the client accepts.
+func (sc *StaticFileServer) servePrecompressed(r *Ctx, filePath string) (Result, bool) {
+	for _, precompressed := range precompressedEncodings {
+		if !AcceptsEncoding(r.Request(), precompressed.Encoding) {
+			continue
+		}
+		f, err := sc.fileSystem.Open(filePath + precompressed.Extension)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+
+		d, err := f.Stat()
+		if err != nil {
+			return r.DefaultResultProvider().InternalError(err), true
+		}
+		if d.IsDir() {
+			continue
+		}
+
+		r.Response().Header().Set(HeaderContentEncoding, precompressed.Encoding)
+		r.Response().Header().Add(HeaderVary, HeaderAcceptEncoding)
+		// serve with the original name so the content type is from the original extension.
+		return sc.serveContent(r, filePath, filePath+precompressed.Extension, d, f), true
+	}
+	return nil, false
 }
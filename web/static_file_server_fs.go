@@ -0,0 +1,21 @@
+//go:build go1.16
+// +build go1.16
+
+package web
+
+import (
+	"io/fs"
+	"net/http"
+)
+
+// NewStaticFileServerFS returns a new static file server for a file system, e.g. an `embed.FS`.
+// Use `fs.Sub` to serve a subdirectory of the file system.
+func NewStaticFileServerFS(fsys fs.FS) *StaticFileServer {
+	return NewStaticFileServer(http.FS(fsys))
+}
+
+// ServeStaticFS serves files from a file system, e.g. an `embed.FS`.
+// If the path does not end with "/*filepath" that suffix will be added for you internally.
+func (a *App) ServeStaticFS(route string, fsys fs.FS) {
+	a.ServeFileserver(route, NewStaticFileServerFS(fsys))
+}
@@ -0,0 +1,59 @@
+//go:build go1.16
+// +build go1.16
+
+package web
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+//go:embed testdata/spa
+var testStaticFS embed.FS
+
+func TestStaticFileServerFS(t *testing.T) {
+	assert := assert.New(t)
+
+	spa, err := fs.Sub(testStaticFS, "testdata/spa")
+	assert.Nil(err)
+
+	modTime := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	app := New()
+	app.ServeFileserver("/", NewStaticFileServerFS(spa).WithETags(true).WithFallback("index.html").WithModTime(modTime))
+
+	contents, meta, err := app.Mock().WithPathf("/assets/app.3f2a1b.js").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Equal("console.log(\"app\");\n", string(contents))
+	assert.Equal(modTime.Format(http.TimeFormat), meta.Headers.Get("Last-Modified"))
+	assert.NotEmpty(meta.Headers.Get(HeaderETag))
+
+	res, err := app.Mock().WithPathf("/assets/app.3f2a1b.js").WithHeader("If-Modified-Since", modTime.Format(http.TimeFormat)).Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+
+	contents, err = app.Mock().WithPathf("/").Bytes()
+	assert.Nil(err)
+	assert.Contains(string(contents), "app")
+
+	contents, err = app.Mock().WithPathf("/settings").Bytes()
+	assert.Nil(err)
+	assert.Contains(string(contents), "app")
+}
+
+func TestAppServeStaticFS(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	app.ServeStaticFS("/static", testStaticFS)
+
+	contents, err := app.Mock().WithPathf("/static/testdata/spa/docs/readme.txt").Bytes()
+	assert.Nil(err)
+	assert.Equal("docs\n", string(contents))
+}
@@ -107,3 +107,108 @@ func TestStaticFileserverMiddleware(t *testing.T) {
 	assert.True(didCallMiddleware)
 	assert.NotEmpty(buffer.Bytes())
 }
+
+func TestStaticFileserverETag(t *testing.T) {
+	assert := assert.New(t)
+
+	app := New()
+	app.ServeFileserver("/static", NewStaticFileServer(http.Dir("testdata/spa")).WithETags(true))
+
+	res, err := app.Mock().WithPathf("/static/index.html").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+	etag := res.Header.Get(HeaderETag)
+	assert.NotEmpty(etag)
+	assert.NotEmpty(res.Header.Get("Last-Modified"))
+
+	res, err = app.Mock().WithPathf("/static/index.html").WithHeader("If-None-Match", etag).Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusNotModified, res.StatusCode)
+
+	res, err = app.Mock().WithPathf("/static/index.html").WithHeader("If-None-Match", `"stale"`).Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusOK, res.StatusCode)
+}
+
+func TestStaticFileserverCacheControl(t *testing.T) {
+	assert := assert.New(t)
+
+	sfs := NewStaticFileServer(http.Dir("testdata/spa")).WithCacheControl(CacheControlNoCache)
+	assert.Nil(sfs.AddCacheControlRule(`^assets/`, CacheControlImmutable))
+	assert.NotNil(sfs.AddCacheControlRule(`(`, CacheControlImmutable))
+	app := New()
+	app.ServeFileserver("/static", sfs)
+
+	res, err := app.Mock().WithPathf("/static/assets/app.3f2a1b.js").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(CacheControlImmutable, res.Header.Get(HeaderCacheControl))
+
+	res, err = app.Mock().WithPathf("/static/index.html").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(CacheControlNoCache, res.Header.Get(HeaderCacheControl))
+
+	res, err = app.Mock().WithPathf("/static/assets/missing.js").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+	assert.Empty(res.Header.Get(HeaderCacheControl))
+}
+
+func TestStaticFileserverDirectories(t *testing.T) {
+	assert := assert.New(t)
+
+	sfs := NewStaticFileServer(http.Dir("testdata/spa"))
+	app := New()
+	app.ServeFileserver("/static", sfs)
+
+	contents, meta, err := app.Mock().WithPathf("/static/").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "app")
+
+	res, err := app.Mock().WithPathf("/static/docs/").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusNotFound, res.StatusCode)
+
+	sfs.WithDirectoryListing(true)
+	res, err = app.Mock().WithPathf("/static/docs").Response()
+	assert.Nil(err)
+	res.Body.Close()
+	assert.Equal(http.StatusTemporaryRedirect, res.StatusCode)
+	assert.Equal("/static/docs/", res.Header.Get("Location"))
+
+	contents, meta, err = app.Mock().WithPathf("/static/docs/").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), `<a href="readme.txt">readme.txt</a>`)
+
+	sfs.WithIndexFile("")
+	contents, err = app.Mock().WithPathf("/static/").Bytes()
+	assert.Nil(err)
+	assert.Contains(string(contents), `<a href="assets/">assets/</a>`)
+}
+
+func TestStaticFileserverFallback(t *testing.T) {
+	assert := assert.New(t)
+
+	sfs := NewStaticFileServer(http.Dir("testdata/spa")).WithFallback("index.html")
+	assert.Nil(sfs.AddCacheControlRule(`^index\.html$`, CacheControlNoCache))
+	app := New()
+	app.ServeFileserver("/", sfs)
+
+	contents, meta, err := app.Mock().WithPathf("/users/1234").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "app")
+	assert.Equal(CacheControlNoCache, meta.Headers.Get(HeaderCacheControl))
+
+	contents, err = app.Mock().WithPathf("/docs/readme.txt").Bytes()
+	assert.Nil(err)
+	assert.Equal("docs\n", string(contents))
+}
@@ -16,6 +16,8 @@ const (
 // go away.
 type TCPKeepAliveListener struct {
 	*net.TCPListener
+	// Period is the keep alive period; zero uses the default, and a negative period disables keep alives.
+	Period time.Duration
 }
 
 // Accept accepts the connection.
@@ -24,7 +26,15 @@ func (ln TCPKeepAliveListener) Accept() (net.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
+	if ln.Period < 0 {
+		tc.SetKeepAlive(false)
+		return tc, nil
+	}
+	period := ln.Period
+	if period == 0 {
+		period = DefaultTCPKeepAliveListenerPeriod
+	}
 	tc.SetKeepAlive(true)
-	tc.SetKeepAlivePeriod(DefaultTCPKeepAliveListenerPeriod)
+	tc.SetKeepAlivePeriod(period)
 	return tc, nil
 }
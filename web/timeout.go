@@ -6,11 +6,23 @@ import (
 	"time"
 )
 
-// WithTimeout injects the context for a given action with a timeout context.
+// WithTimeout returns middleware that cancels the request context after a given duration,
+// responding with a 503 if the action hasn't returned by then.
+/*
+It can be passed as route middleware, or to a group to apply to all of its routes:
+
+	api := app.Group("/api", web.JSONProviderAsDefault, web.WithTimeout(10*time.Second))
+	api.POST("/reports", createReport, web.WithTimeout(time.Minute))
+
+Timeouts nest like contexts; the shortest deadline applies, so a route can't extend its group's timeout.
+The action runs in its own goroutine and its result is discarded once it times out, so long running
+actions should watch `r.Context().Done()` and return.
+*/
 func WithTimeout(d time.Duration) Middleware {
 	return func(action Action) Action {
 		return func(r *Ctx) Result {
-			ctx, cancel := context.WithTimeout(r.Context(), d)
+			parent := r.Context()
+			ctx, cancel := context.WithTimeout(parent, d)
 			defer func() { cancel() }()
 
 			r.request = r.request.WithContext(ctx)
@@ -33,7 +45,8 @@ func WithTimeout(d time.Duration) Middleware {
 			case res := <-resultChan:
 				return res
 			case <-ctx.Done():
-				if len(r.Response().InnerResponse().(http.CloseNotifier).CloseNotify()) > 0 {
+				// the client disconnected, so there's no one to respond to.
+				if parent.Err() != nil {
 					return NoContent
 				}
 				return r.DefaultResultProvider().Status(http.StatusServiceUnavailable)
@@ -0,0 +1,39 @@
+package web
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	cancelled := make(chan bool, 1)
+	app := New()
+	api := app.Group("/api", JSONProviderAsDefault, WithTimeout(time.Second))
+	api.GET("/fast", func(r *Ctx) Result {
+		return r.JSON().OK()
+	})
+	api.GET("/slow", func(r *Ctx) Result {
+		select {
+		case <-r.Context().Done():
+			cancelled <- true
+		case <-time.After(5 * time.Second):
+			cancelled <- false
+		}
+		return r.JSON().OK()
+	}, WithTimeout(10*time.Millisecond))
+
+	meta, err := app.Mock().Get("/api/fast").ExecuteWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	contents, meta, err := app.Mock().Get("/api/slow").BytesWithMeta()
+	assert.Nil(err)
+	assert.Equal(http.StatusServiceUnavailable, meta.StatusCode)
+	assert.Contains(string(contents), http.StatusText(http.StatusServiceUnavailable))
+	assert.True(<-cancelled, "the action context should be cancelled")
+}
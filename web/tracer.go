@@ -19,3 +19,13 @@ type ViewTracer interface {
 type ViewTraceFinisher interface {
 	Finish(*Ctx, *ViewResult, error)
 }
+
+// WebSocketTracer is a type that can listen for websocket connection traces.
+type WebSocketTracer interface {
+	StartWebSocket(*Ctx, *WebSocketResult) WebSocketTraceFinisher
+}
+
+// WebSocketTraceFinisher is a finisher for websocket traces.
+type WebSocketTraceFinisher interface {
+	Finish(*Ctx, *WebSocketResult, error)
+}
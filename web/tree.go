@@ -769,3 +769,13 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// walk calls a function for each route in the tree.
+func (n *node) walk(handler func(*Route)) {
+	if n.route != nil {
+		handler(n.route)
+	}
+	for _, child := range n.children {
+		child.walk(handler)
+	}
+}
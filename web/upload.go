@@ -0,0 +1,391 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+const (
+	// DefaultUploadMaxFileSize is the default maximum size of an uploaded file.
+	DefaultUploadMaxFileSize = int64(32 << 20) // 32mb
+	// DefaultUploadMaxFiles is the default maximum number of files in an upload.
+	DefaultUploadMaxFiles = 16
+	// DefaultUploadMaxValuesSize is the default maximum total size of the non-file values in an upload.
+	DefaultUploadMaxValuesSize = int64(1 << 20) // 1mb
+
+	// uploadSniffLen is the number of bytes content types are detected from.
+	uploadSniffLen = 512
+	// uploadCopyBufferSize is the size of the buffer files are copied to storage with.
+	uploadCopyBufferSize = 32 << 10
+)
+
+// UploadFile is a file in a multipart upload.
+type UploadFile struct {
+	// Key is the form field the file was posted in.
+	Key string
+	// FileName is the client's name for the file, without any directories.
+	FileName string
+	// ContentType is the content type detected from the file's contents; the type the client sent is ignored.
+	ContentType string
+	// Size is the number of bytes stored.
+	Size int64
+	// Location is where the storage put the file, e.g. its path; storage sets it in `Create`.
+	Location string
+}
+
+// Upload is a stored multipart upload.
+type Upload struct {
+	Files  []UploadFile
+	Values url.Values
+}
+
+// UploadStorage stores uploaded files as they are read from the request.
+type UploadStorage interface {
+	// Create returns a writer for a file; it is closed once the file is written.
+	// If the writer implements `UploadAborter` it is aborted instead if the file is rejected part way through.
+	Create(ctx context.Context, file *UploadFile) (io.WriteCloser, error)
+}
+
+// UploadStorageFunc is a function that implements `UploadStorage`.
+type UploadStorageFunc func(ctx context.Context, file *UploadFile) (io.WriteCloser, error)
+
+// Create implements UploadStorage.
+func (usf UploadStorageFunc) Create(ctx context.Context, file *UploadFile) (io.WriteCloser, error) {
+	return usf(ctx, file)
+}
+
+// UploadAborter is a storage writer that can discard a partially written file.
+type UploadAborter interface {
+	Abort() error
+}
+
+// UploadProgressFunc is called as each file is written, with the number of bytes written so far.
+type UploadProgressFunc func(file UploadFile, written int64)
+
+// UploadError is returned by `Uploader.Upload` if an upload is rejected.
+// `StatusCode` is the status to respond with, and it marshals to json as a structured response.
+type UploadError struct {
+	StatusCode int    `json:"-"`
+	Message    string `json:"message"`
+	Key        string `json:"key,omitempty"`
+	FileName   string `json:"fileName,omitempty"`
+}
+
+// Error implements error.
+func (ue *UploadError) Error() string {
+	if len(ue.FileName) > 0 {
+		return ue.Message + ": " + ue.FileName
+	}
+	return ue.Message
+}
+
+// IsUploadError returns if an error is an upload error, i.e. the upload was rejected,
+// as opposed to a problem reading the request or storing a file.
+func IsUploadError(err error) bool {
+	_, ok := err.(*UploadError)
+	return ok
+}
+
+// NewUploader returns a new uploader that streams files to a given storage, with the default limits.
+func NewUploader(storage UploadStorage) *Uploader {
+	return &Uploader{
+		storage:       storage,
+		maxFileSize:   DefaultUploadMaxFileSize,
+		maxFiles:      DefaultUploadMaxFiles,
+		maxValuesSize: DefaultUploadMaxValuesSize,
+	}
+}
+
+// Uploader reads multipart uploads, streaming each file to storage as it is read instead of
+// buffering the request in memory or temp files like `Ctx.PostedFiles`.
+/*
+Files are checked against the limits as they are read:
+
+	uploader := web.NewUploader(web.NewDirUploadStorage("/var/uploads")).
+		WithMaxFileSize(10 << 20).
+		WithAllowedContentTypes("image/png", "image/jpeg", "application/pdf")
+
+	app.POST("/documents", func(r *web.Ctx) web.Result {
+		upload, err := uploader.Upload(r)
+		if uploadErr, ok := err.(*web.UploadError); ok {
+			return r.JSON().Status(uploadErr.StatusCode, uploadErr)
+		} else if err != nil {
+			return r.JSON().InternalError(err)
+		}
+		...
+	})
+
+If an upload fails part way through, the files stored before the failure are returned with the error,
+so they can be cleaned up.
+*/
+type Uploader struct {
+	storage       UploadStorage
+	maxFileSize   int64
+	maxFiles      int
+	maxValuesSize int64
+	contentTypes  []string
+	keys          []string
+	progress      UploadProgressFunc
+}
+
+// Storage returns the storage files are written to.
+func (u *Uploader) Storage() UploadStorage {
+	return u.storage
+}
+
+// WithMaxFileSize sets the maximum size of each file; larger files are rejected with a 413.
+func (u *Uploader) WithMaxFileSize(maxFileSize int64) *Uploader {
+	u.maxFileSize = maxFileSize
+	return u
+}
+
+// MaxFileSize returns the maximum size of each file.
+func (u *Uploader) MaxFileSize() int64 {
+	return u.maxFileSize
+}
+
+// WithMaxFiles sets the maximum number of files in an upload; uploads with more are rejected with a 413.
+func (u *Uploader) WithMaxFiles(maxFiles int) *Uploader {
+	u.maxFiles = maxFiles
+	return u
+}
+
+// MaxFiles returns the maximum number of files in an upload.
+func (u *Uploader) MaxFiles() int {
+	return u.maxFiles
+}
+
+// WithMaxValuesSize sets the maximum total size of the non-file values in an upload, which are read into memory.
+func (u *Uploader) WithMaxValuesSize(maxValuesSize int64) *Uploader {
+	u.maxValuesSize = maxValuesSize
+	return u
+}
+
+// MaxValuesSize returns the maximum total size of the non-file values in an upload.
+func (u *Uploader) MaxValuesSize() int64 {
+	return u.maxValuesSize
+}
+
+// WithAllowedContentTypes sets the content types files can have, e.g. "application/pdf", or "image/*"
+// for any image; files with other types are rejected with a 415. If unset any type is allowed.
+func (u *Uploader) WithAllowedContentTypes(contentTypes ...string) *Uploader {
+	u.contentTypes = contentTypes
+	return u
+}
+
+// AllowedContentTypes returns the content types files can have.
+func (u *Uploader) AllowedContentTypes() []string {
+	return u.contentTypes
+}
+
+// WithKeys sets the form fields files can be posted in; files in other fields are rejected with a 400.
+// If unset files can be posted in any field.
+func (u *Uploader) WithKeys(keys ...string) *Uploader {
+	u.keys = keys
+	return u
+}
+
+// Keys returns the form fields files can be posted in.
+func (u *Uploader) Keys() []string {
+	return u.keys
+}
+
+// WithProgress sets a function that is called as each file is written.
+func (u *Uploader) WithProgress(progress UploadProgressFunc) *Uploader {
+	u.progress = progress
+	return u
+}
+
+// Progress returns the function that is called as each file is written.
+func (u *Uploader) Progress() UploadProgressFunc {
+	return u.progress
+}
+
+// Upload reads a multipart upload from a request, streaming each file to storage.
+// A rejected upload returns an `*UploadError`.
+func (u *Uploader) Upload(ctx *Ctx) (*Upload, error) {
+	reader, err := ctx.Request().MultipartReader()
+	if err != nil {
+		return nil, &UploadError{StatusCode: http.StatusBadRequest, Message: "request is not a multipart form"}
+	}
+
+	upload := &Upload{Values: url.Values{}}
+	var valuesSize int64
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return upload, nil
+		}
+		if err != nil {
+			return upload, u.readError(err)
+		}
+
+		key := part.FormName()
+		fileName := part.FileName()
+		if len(fileName) == 0 {
+			value, err := ioutil.ReadAll(io.LimitReader(part, u.maxValuesSize-valuesSize+1))
+			part.Close()
+			if err != nil {
+				return upload, u.readError(err)
+			}
+			if valuesSize += int64(len(value)); valuesSize > u.maxValuesSize {
+				return upload, &UploadError{StatusCode: http.StatusRequestEntityTooLarge, Message: "form values are too large", Key: key}
+			}
+			upload.Values.Add(key, string(value))
+			continue
+		}
+
+		fileName = filepath.Base(strings.Replace(fileName, "\\", "/", -1))
+		if len(u.keys) > 0 && !u.isAllowedKey(key) {
+			part.Close()
+			return upload, &UploadError{StatusCode: http.StatusBadRequest, Message: "files are not allowed in this field", Key: key, FileName: fileName}
+		}
+		if u.maxFiles > 0 && len(upload.Files) >= u.maxFiles {
+			part.Close()
+			return upload, &UploadError{StatusCode: http.StatusRequestEntityTooLarge, Message: "too many files", Key: key, FileName: fileName}
+		}
+		file, err := u.store(ctx.Context(), part, &UploadFile{Key: key, FileName: fileName})
+		part.Close()
+		if err != nil {
+			return upload, err
+		}
+		upload.Files = append(upload.Files, *file)
+	}
+}
+
+// store streams a file part to storage.
+func (u *Uploader) store(ctx context.Context, part io.Reader, file *UploadFile) (*UploadFile, error) {
+	buffered := bufio.NewReaderSize(part, uploadSniffLen)
+	head, err := buffered.Peek(uploadSniffLen)
+	if err != nil && err != io.EOF {
+		return nil, u.readError(err)
+	}
+	file.ContentType = http.DetectContentType(head)
+	if !u.isAllowedContentType(file.ContentType) {
+		return nil, &UploadError{StatusCode: http.StatusUnsupportedMediaType, Message: "file type is not allowed", Key: file.Key, FileName: file.FileName}
+	}
+
+	writer, err := u.storage.Create(ctx, file)
+	if err != nil {
+		return nil, exception.New(err)
+	}
+	if err := u.copy(writer, buffered, file); err != nil {
+		if aborter, ok := writer.(UploadAborter); ok {
+			aborter.Abort()
+		} else {
+			writer.Close()
+		}
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, exception.New(err)
+	}
+	return file, nil
+}
+
+// copy copies a file to its writer, enforcing the max file size and reporting progress.
+func (u *Uploader) copy(writer io.Writer, reader io.Reader, file *UploadFile) error {
+	buffer := make([]byte, uploadCopyBufferSize)
+	for {
+		n, err := reader.Read(buffer)
+		if n > 0 {
+			if u.maxFileSize > 0 && file.Size+int64(n) > u.maxFileSize {
+				return &UploadError{StatusCode: http.StatusRequestEntityTooLarge, Message: "file is too large", Key: file.Key, FileName: file.FileName}
+			}
+			if _, err := writer.Write(buffer[:n]); err != nil {
+				return exception.New(err)
+			}
+			file.Size += int64(n)
+			if u.progress != nil {
+				u.progress(*file, file.Size)
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return u.readError(err)
+		}
+	}
+}
+
+// readError returns an error for a failed read; a body over the `WithMaxBodySize` limit is passed through
+// so the middleware responds with a 413.
+func (u *Uploader) readError(err error) error {
+	if IsRequestBodyTooLarge(err) {
+		return err
+	}
+	return &UploadError{StatusCode: http.StatusBadRequest, Message: "malformed multipart form; " + err.Error()}
+}
+
+func (u *Uploader) isAllowedKey(key string) bool {
+	for _, allowed := range u.keys {
+		if allowed == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *Uploader) isAllowedContentType(contentType string) bool {
+	if len(u.contentTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	for _, allowed := range u.contentTypes {
+		if allowed == mediaType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDirUploadStorage returns an upload storage that writes files to a directory.
+// Files are given random names, set as their `Location`, as client file names can't be trusted.
+func NewDirUploadStorage(dir string) *DirUploadStorage {
+	return &DirUploadStorage{dir: dir}
+}
+
+// DirUploadStorage is an upload storage that writes files to a directory.
+type DirUploadStorage struct {
+	dir string
+}
+
+// Dir returns the directory files are written to.
+func (ds *DirUploadStorage) Dir() string {
+	return ds.dir
+}
+
+// Create implements UploadStorage.
+func (ds *DirUploadStorage) Create(_ context.Context, file *UploadFile) (io.WriteCloser, error) {
+	if err := os.MkdirAll(ds.dir, 0755); err != nil {
+		return nil, exception.New(err)
+	}
+	output, err := ioutil.TempFile(ds.dir, "upload-")
+	if err != nil {
+		return nil, exception.New(err)
+	}
+	file.Location = output.Name()
+	return &dirUploadFile{File: output}, nil
+}
+
+// dirUploadFile is a file being written by a dir upload storage.
+type dirUploadFile struct {
+	*os.File
+}
+
+// Abort implements UploadAborter.
+func (df *dirUploadFile) Abort() error {
+	df.Close()
+	return os.Remove(df.Name())
+}
@@ -0,0 +1,162 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestUploaderUpload(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "upload")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	var progress []int64
+	uploader := NewUploader(NewDirUploadStorage(dir)).
+		WithAllowedContentTypes("text/*", "image/png").
+		WithProgress(func(file UploadFile, written int64) {
+			progress = append(progress, written)
+		})
+
+	contents := strings.Repeat("hello world\n", 4096)
+	upload, err := uploader.Upload(newUploadCtx(t, map[string]string{"title": "greetings"}, uploadPart{"document", `..\..\etc\hello.txt`, contents}))
+	assert.Nil(err)
+	assert.Equal("greetings", upload.Values.Get("title"))
+	assert.Len(upload.Files, 1)
+
+	file := upload.Files[0]
+	assert.Equal("document", file.Key)
+	assert.Equal("hello.txt", file.FileName)
+	assert.Equal("text/plain; charset=utf-8", file.ContentType)
+	assert.Equal(int64(len(contents)), file.Size)
+	assert.True(strings.HasPrefix(file.Location, dir))
+	stored, err := ioutil.ReadFile(file.Location)
+	assert.Nil(err)
+	assert.Equal(contents, string(stored))
+	assert.NotEmpty(progress)
+	assert.Equal(int64(len(contents)), progress[len(progress)-1])
+}
+
+func TestUploaderRejects(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "upload")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	uploader := NewUploader(NewDirUploadStorage(dir)).
+		WithMaxFileSize(1024).
+		WithMaxFiles(1).
+		WithMaxValuesSize(8).
+		WithKeys("document").
+		WithAllowedContentTypes("text/plain")
+
+	assertUploadError := func(expectedStatus int, err error) {
+		assert.True(IsUploadError(err), err)
+		if uploadErr, ok := err.(*UploadError); ok {
+			assert.Equal(expectedStatus, uploadErr.StatusCode)
+		}
+	}
+
+	_, err = uploader.Upload(newUploadCtx(t, nil, uploadPart{"document", "big.txt", strings.Repeat("a", 1025)}))
+	assertUploadError(http.StatusRequestEntityTooLarge, err)
+	_, err = uploader.Upload(newUploadCtx(t, nil, uploadPart{"document", "image.png", "\x89PNG\r\n\x1a\n"}))
+	assertUploadError(http.StatusUnsupportedMediaType, err)
+	_, err = uploader.Upload(newUploadCtx(t, nil, uploadPart{"avatar", "a.txt", "a"}))
+	assertUploadError(http.StatusBadRequest, err)
+	_, err = uploader.Upload(newUploadCtx(t, map[string]string{"title": "too long a title"}))
+	assertUploadError(http.StatusRequestEntityTooLarge, err)
+	_, err = uploader.Upload(NewCtx(NewMockResponseWriter(new(bytes.Buffer)), NewMockRequest("POST", "/"), nil, nil))
+	assertUploadError(http.StatusBadRequest, err)
+
+	// the files stored before the failure are returned.
+	upload, err := uploader.Upload(newUploadCtx(t, nil, uploadPart{"document", "a.txt", "a"}, uploadPart{"document", "b.txt", "b"}))
+	assertUploadError(http.StatusRequestEntityTooLarge, err)
+	assert.Len(upload.Files, 1)
+
+	// the partially written file was removed.
+	files, err := ioutil.ReadDir(dir)
+	assert.Nil(err)
+	assert.Len(files, 1)
+}
+
+func TestUploaderStorageFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	uploader := NewUploader(UploadStorageFunc(func(_ context.Context, file *UploadFile) (io.WriteCloser, error) {
+		file.Location = "memory://" + file.FileName
+		return nopWriteCloser{buffer}, nil
+	}))
+
+	upload, err := uploader.Upload(newUploadCtx(t, nil, uploadPart{"document", "a.txt", "contents"}))
+	assert.Nil(err)
+	assert.Equal("memory://a.txt", upload.Files[0].Location)
+	assert.Equal("contents", buffer.String())
+}
+
+func TestUploaderMaxBodySize(t *testing.T) {
+	assert := assert.New(t)
+
+	uploader := NewUploader(UploadStorageFunc(func(_ context.Context, _ *UploadFile) (io.WriteCloser, error) {
+		return nopWriteCloser{ioutil.Discard}, nil
+	}))
+	app := New()
+	app.POST("/", func(r *Ctx) Result {
+		if _, err := uploader.Upload(r); err != nil {
+			return r.Text().BadRequest(err)
+		}
+		return NoContent
+	}, WithMaxBodySize(1024))
+
+	req := newUploadCtx(t, nil, uploadPart{"document", "a.txt", strings.Repeat("a", 2048)}).Request()
+	req.ContentLength = -1
+	res := httptest.NewRecorder()
+	app.ServeHTTP(res, req)
+	assert.Equal(http.StatusRequestEntityTooLarge, res.Code)
+}
+
+type uploadPart struct {
+	key, fileName, contents string
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func newUploadCtx(t *testing.T, values map[string]string, parts ...uploadPart) *Ctx {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for key, value := range values {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, part := range parts {
+		fileWriter, err := writer.CreateFormFile(part.key, part.fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fileWriter.Write([]byte(part.contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest("POST", "/", body)
+	req.Header.Set(HeaderContentType, writer.FormDataContentType())
+	return NewCtx(NewMockResponseWriter(new(bytes.Buffer)), req, nil, nil)
+}
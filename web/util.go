@@ -43,6 +43,15 @@ func NestMiddleware(action Action, middleware ...Middleware) Action {
 	return metaAction(action)
 }
 
+// ChainMiddleware composes middleware into a single middleware, so common steps like auth and logging
+// can be reused as one unit across routes. The middleware is nested the same as `NestMiddleware`,
+// i.e. the last middleware runs first.
+func ChainMiddleware(middleware ...Middleware) Middleware {
+	return func(action Action) Action {
+		return NestMiddleware(action, middleware...)
+	}
+}
+
 // NewSessionID returns a new session id.
 // It is not a uuid; session ids are generated using a secure random source.
 // SessionIDs are generally 64 bytes.
@@ -47,6 +47,25 @@ func TestNestMiddleware(t *testing.T) {
 	assert.Equal(0, mw1Called)
 }
 
+func TestChainMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	step := func(name string) Middleware {
+		return func(action Action) Action {
+			return func(ctx *Ctx) Result {
+				calls = append(calls, name)
+				return action(ctx)
+			}
+		}
+	}
+
+	chained := ChainMiddleware(step("inner"), step("outer"))
+	nested := NestMiddleware(func(ctx *Ctx) Result { return nil }, step("route"), chained)
+	nested(nil)
+	assert.Equal([]string{"outer", "inner", "route"}, calls)
+}
+
 func TestPortFromBindAddr(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1,6 +1,8 @@
 package web
 
 import (
+	"html/template"
+
 	"github.com/blend/go-sdk/env"
 	"github.com/blend/go-sdk/uuid"
 )
@@ -26,6 +28,16 @@ func (vm *ViewModel) UUIDv4() string {
 	return uuid.V4().String()
 }
 
+// CSRFToken returns a csrf token for the request, see `CSRF`.
+func (vm *ViewModel) CSRFToken() string {
+	return CSRFToken(vm.Ctx)
+}
+
+// CSRFField returns a hidden form input with a csrf token for the request, see `CSRF`.
+func (vm *ViewModel) CSRFField() template.HTML {
+	return CSRFField(vm.Ctx)
+}
+
 // StatusViewModel returns the status view model.
 type StatusViewModel struct {
 	StatusCode int
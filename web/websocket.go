@@ -0,0 +1,474 @@
+package web
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blend/go-sdk/exception"
+)
+
+// WebSocket message types (frame opcodes) from RFC 6455.
+const (
+	WebSocketMessageContinuation = 0
+	WebSocketMessageText         = 1
+	WebSocketMessageBinary       = 2
+	WebSocketMessageClose        = 8
+	WebSocketMessagePing         = 9
+	WebSocketMessagePong         = 10
+)
+
+// WebSocket close codes from RFC 6455.
+const (
+	WebSocketCloseNormal          = 1000
+	WebSocketCloseGoingAway       = 1001
+	WebSocketCloseProtocolError   = 1002
+	WebSocketCloseUnsupportedData = 1003
+	WebSocketCloseNoStatus        = 1005
+	WebSocketCloseAbnormal        = 1006
+	WebSocketCloseInvalidPayload  = 1007
+	WebSocketClosePolicyViolation = 1008
+	WebSocketCloseMessageTooBig   = 1009
+	WebSocketCloseInternalError   = 1011
+)
+
+const (
+	// DefaultWebSocketReadLimit is the default maximum size of a message read from a websocket.
+	DefaultWebSocketReadLimit = 1 << 20
+	// MaxWebSocketReadLimit is the largest read limit a websocket can have, so a client can't make
+	// the server allocate an unbounded amount of memory for a message.
+	MaxWebSocketReadLimit = 64 << 20
+	// DefaultWebSocketPingInterval is the default interval pings are sent to the client on.
+	DefaultWebSocketPingInterval = 30 * time.Second
+	// DefaultWebSocketPongTimeout is the default time to wait to read a frame, including pongs, before the connection is considered dead.
+	DefaultWebSocketPongTimeout = 60 * time.Second
+	// DefaultWebSocketWriteTimeout is the default timeout for writing a frame.
+	DefaultWebSocketWriteTimeout = 10 * time.Second
+)
+
+// WebSocketMessage is a complete (reassembled) websocket data message.
+type WebSocketMessage struct {
+	Type int
+	Data []byte
+}
+
+// IsText returns if the message is a text message.
+func (wsm WebSocketMessage) IsText() bool {
+	return wsm.Type == WebSocketMessageText
+}
+
+// WebSocketCloseError is returned when the peer closes the websocket.
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements error.
+func (wsce *WebSocketCloseError) Error() string {
+	if len(wsce.Reason) > 0 {
+		return fmt.Sprintf("websocket closed: %d %s", wsce.Code, wsce.Reason)
+	}
+	return fmt.Sprintf("websocket closed: %d", wsce.Code)
+}
+
+// IsWebSocketClosed returns if an error is from the websocket being closed normally,
+// either by the peer with a normal or going away close code, or locally.
+func IsWebSocketClosed(err error) bool {
+	if err == nil {
+		return false
+	}
+	if typed, ok := err.(*WebSocketCloseError); ok {
+		return typed.Code == WebSocketCloseNormal || typed.Code == WebSocketCloseGoingAway || typed.Code == WebSocketCloseNoStatus
+	}
+	return exception.Is(err, ErrWebSocketClosed)
+}
+
+// WebSocketHandler is the handler for an upgraded websocket connection.
+// The connection is closed when the handler returns.
+type WebSocketHandler func(*WebSocket) error
+
+func newWebSocket(ctx *Ctx, conn net.Conn, reader *bufio.Reader, wsr *WebSocketResult) *WebSocket {
+	return &WebSocket{
+		ctx:          ctx,
+		conn:         conn,
+		reader:       reader,
+		readLimit:    webSocketReadLimit(wsr.ReadLimit),
+		pingInterval: wsr.PingInterval,
+		pongTimeout:  wsr.PongTimeout,
+		writeTimeout: wsr.WriteTimeout,
+		done:         make(chan struct{}),
+	}
+}
+
+// webSocketReadLimit returns the read limit to use for a configured limit; unset limits use
+// the default, and limits larger than the max are capped.
+func webSocketReadLimit(limit int64) int64 {
+	if limit <= 0 {
+		return DefaultWebSocketReadLimit
+	}
+	if limit > MaxWebSocketReadLimit {
+		return MaxWebSocketReadLimit
+	}
+	return limit
+}
+
+// WebSocket is an upgraded websocket connection.
+// Reads must come from a single goroutine, writes are safe to call concurrently.
+type WebSocket struct {
+	ctx         *Ctx
+	conn        net.Conn
+	reader      *bufio.Reader
+	subprotocol string
+
+	readLimit    int64
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	writeTimeout time.Duration
+
+	writeLock sync.Mutex
+	closeSent bool
+	closeOnce sync.Once
+	done      chan struct{}
+
+	messagesRead    int32
+	messagesWritten int32
+}
+
+// Ctx returns the request context the websocket was upgraded from.
+func (ws *WebSocket) Ctx() *Ctx {
+	return ws.ctx
+}
+
+// Conn returns the underlying connection.
+func (ws *WebSocket) Conn() net.Conn {
+	return ws.conn
+}
+
+// Subprotocol returns the negotiated subprotocol, if any.
+func (ws *WebSocket) Subprotocol() string {
+	return ws.subprotocol
+}
+
+// Done returns a channel that is closed when the websocket is closed.
+func (ws *WebSocket) Done() <-chan struct{} {
+	return ws.done
+}
+
+// ReadMessage reads the next data message, answering pings and reassembling fragmented messages.
+// It returns a `*WebSocketCloseError` if the peer closes the websocket.
+func (ws *WebSocket) ReadMessage() (message WebSocketMessage, err error) {
+	message.Type = -1
+	for {
+		if ws.pongTimeout > 0 {
+			ws.conn.SetReadDeadline(time.Now().Add(ws.pongTimeout))
+		}
+
+		var fin bool
+		var opcode int
+		var payload []byte
+		fin, opcode, payload, err = ws.readFrame()
+		if err != nil {
+			return
+		}
+
+		switch opcode {
+		case WebSocketMessagePing:
+			if err = ws.writeFrame(WebSocketMessagePong, payload); err != nil {
+				return
+			}
+			continue
+		case WebSocketMessagePong:
+			continue
+		case WebSocketMessageClose:
+			err = ws.handleClose(payload)
+			return
+		case WebSocketMessageContinuation:
+			if message.Type < 0 {
+				err = ws.fail(WebSocketCloseProtocolError, "unexpected continuation frame")
+				return
+			}
+		case WebSocketMessageText, WebSocketMessageBinary:
+			if message.Type >= 0 {
+				err = ws.fail(WebSocketCloseProtocolError, "expected continuation frame")
+				return
+			}
+			message.Type = opcode
+		default:
+			err = ws.fail(WebSocketCloseProtocolError, fmt.Sprintf("unknown opcode %d", opcode))
+			return
+		}
+
+		message.Data = append(message.Data, payload...)
+		if int64(len(message.Data)) > ws.readLimit {
+			err = ws.fail(WebSocketCloseMessageTooBig, "message too big")
+			return
+		}
+		if fin {
+			if message.IsText() && !utf8.Valid(message.Data) {
+				err = ws.fail(WebSocketCloseInvalidPayload, "invalid utf-8 in text message")
+				return
+			}
+			atomic.AddInt32(&ws.messagesRead, 1)
+			return
+		}
+	}
+}
+
+// ReadJSON reads the next message and deserializes it as json into a given object.
+func (ws *WebSocket) ReadJSON(v interface{}) error {
+	message, err := ws.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return exception.New(json.Unmarshal(message.Data, v))
+}
+
+// WriteMessage writes a data message.
+func (ws *WebSocket) WriteMessage(message WebSocketMessage) error {
+	if message.Type != WebSocketMessageText && message.Type != WebSocketMessageBinary {
+		return exception.New(ErrWebSocketProtocol).WithMessagef("invalid message type %d", message.Type)
+	}
+	if err := ws.writeFrame(message.Type, message.Data); err != nil {
+		return err
+	}
+	atomic.AddInt32(&ws.messagesWritten, 1)
+	return nil
+}
+
+// WriteText writes a text message.
+func (ws *WebSocket) WriteText(text string) error {
+	return ws.WriteMessage(WebSocketMessage{Type: WebSocketMessageText, Data: []byte(text)})
+}
+
+// WriteJSON serializes an object as json and writes it as a text message.
+func (ws *WebSocket) WriteJSON(v interface{}) error {
+	contents, err := json.Marshal(v)
+	if err != nil {
+		return exception.New(err)
+	}
+	return ws.WriteMessage(WebSocketMessage{Type: WebSocketMessageText, Data: contents})
+}
+
+// Ping sends a ping; the peer's pong is handled by `ReadMessage`.
+func (ws *WebSocket) Ping(data []byte) error {
+	return ws.writeFrame(WebSocketMessagePing, data)
+}
+
+// ReadPump reads messages and passes them to a handler until the peer closes the websocket,
+// the handler returns an error, or reading fails. It returns nil if the websocket was closed normally.
+func (ws *WebSocket) ReadPump(handler func(WebSocketMessage) error) error {
+	for {
+		message, err := ws.ReadMessage()
+		if err != nil {
+			if IsWebSocketClosed(err) {
+				return nil
+			}
+			return err
+		}
+		if err = handler(message); err != nil {
+			return err
+		}
+	}
+}
+
+// WritePump writes messages from a channel until the channel is closed, at which point
+// the websocket is closed normally, or until the websocket is closed.
+func (ws *WebSocket) WritePump(messages <-chan WebSocketMessage) error {
+	for {
+		select {
+		case message, ok := <-messages:
+			if !ok {
+				return ws.Close()
+			}
+			if err := ws.WriteMessage(message); err != nil {
+				return err
+			}
+		case <-ws.done:
+			return nil
+		}
+	}
+}
+
+// Close sends a normal close frame and closes the connection.
+func (ws *WebSocket) Close() error {
+	return ws.CloseWithReason(WebSocketCloseNormal, "")
+}
+
+// CloseWithReason sends a close frame with a given code and reason and closes the connection.
+func (ws *WebSocket) CloseWithReason(code int, reason string) error {
+	err := ws.writeClose(code, reason)
+	if closeErr := ws.closeConn(); err == nil || exception.Is(err, ErrWebSocketClosed) {
+		err = closeErr
+	}
+	return err
+}
+
+// --------------------------------------------------------------------------------
+// internal methods
+// --------------------------------------------------------------------------------
+
+// pingLoop sends pings on the ping interval until the websocket is closed.
+func (ws *WebSocket) pingLoop() {
+	ticker := time.NewTicker(ws.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := ws.Ping(nil); err != nil {
+				return
+			}
+		case <-ws.done:
+			return
+		}
+	}
+}
+
+// handleClose answers a close frame from the peer and returns it as an error.
+func (ws *WebSocket) handleClose(payload []byte) error {
+	closeErr := &WebSocketCloseError{Code: WebSocketCloseNoStatus}
+	if len(payload) >= 2 {
+		closeErr.Code = int(binary.BigEndian.Uint16(payload))
+		closeErr.Reason = string(payload[2:])
+	}
+	if closeErr.Code == WebSocketCloseNoStatus {
+		ws.writeFrame(WebSocketMessageClose, nil)
+	} else {
+		ws.writeClose(closeErr.Code, "")
+	}
+	return closeErr
+}
+
+// fail closes the websocket with a given code and returns a protocol error.
+func (ws *WebSocket) fail(code int, reason string) error {
+	ws.CloseWithReason(code, reason)
+	return exception.New(ErrWebSocketProtocol).WithMessage(reason)
+}
+
+func (ws *WebSocket) writeClose(code int, reason string) error {
+	payload := make([]byte, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	copy(payload[2:], reason)
+	return ws.writeFrame(WebSocketMessageClose, payload)
+}
+
+func (ws *WebSocket) closeConn() (err error) {
+	ws.closeOnce.Do(func() {
+		close(ws.done)
+		err = ws.conn.Close()
+	})
+	return
+}
+
+// readFrame reads a single frame; frames from clients must be masked.
+func (ws *WebSocket) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(ws.reader, header[:]); err != nil {
+		err = ws.readError(err)
+		return
+	}
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0f)
+	if header[0]&0x70 != 0 {
+		err = ws.fail(WebSocketCloseProtocolError, "unexpected reserved bits")
+		return
+	}
+	if header[1]&0x80 == 0 {
+		err = ws.fail(WebSocketCloseProtocolError, "client frames must be masked")
+		return
+	}
+
+	length := int64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err = io.ReadFull(ws.reader, extended[:]); err != nil {
+			err = ws.readError(err)
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err = io.ReadFull(ws.reader, extended[:]); err != nil {
+			err = ws.readError(err)
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	if opcode >= WebSocketMessageClose && (!fin || length > 125) {
+		err = ws.fail(WebSocketCloseProtocolError, "invalid control frame")
+		return
+	}
+	if length < 0 || length > ws.readLimit {
+		err = ws.fail(WebSocketCloseMessageTooBig, "message too big")
+		return
+	}
+
+	var mask [4]byte
+	if _, err = io.ReadFull(ws.reader, mask[:]); err != nil {
+		err = ws.readError(err)
+		return
+	}
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(ws.reader, payload); err != nil {
+		err = ws.readError(err)
+		return
+	}
+	for index := range payload {
+		payload[index] ^= mask[index%4]
+	}
+	return
+}
+
+func (ws *WebSocket) readError(err error) error {
+	select {
+	case <-ws.done:
+		return exception.New(ErrWebSocketClosed)
+	default:
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return &WebSocketCloseError{Code: WebSocketCloseAbnormal}
+	}
+	return exception.New(err)
+}
+
+// writeFrame writes a single unmasked, unfragmented frame.
+func (ws *WebSocket) writeFrame(opcode int, payload []byte) error {
+	ws.writeLock.Lock()
+	defer ws.writeLock.Unlock()
+	if ws.closeSent {
+		return exception.New(ErrWebSocketClosed)
+	}
+	if opcode == WebSocketMessageClose {
+		ws.closeSent = true
+	}
+
+	frame := make([]byte, 0, 10+len(payload))
+	frame = append(frame, 0x80|byte(opcode))
+	switch length := len(payload); {
+	case length <= 125:
+		frame = append(frame, byte(length))
+	case length <= 0xffff:
+		frame = append(frame, 126, byte(length>>8), byte(length))
+	default:
+		var extended [8]byte
+		binary.BigEndian.PutUint64(extended[:], uint64(length))
+		frame = append(append(frame, 127), extended[:]...)
+	}
+	frame = append(frame, payload...)
+
+	if ws.writeTimeout > 0 {
+		ws.conn.SetWriteDeadline(time.Now().Add(ws.writeTimeout))
+	}
+	if _, err := ws.conn.Write(frame); err != nil {
+		return exception.New(err)
+	}
+	return nil
+}
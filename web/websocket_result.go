@@ -0,0 +1,219 @@
+package web
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/blend/go-sdk/exception"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/webutil"
+)
+
+const (
+	// WebSocketOpen fires when a websocket is upgraded.
+	WebSocketOpen logger.Flag = "web.websocket.open"
+	// WebSocketClose fires when a websocket is closed.
+	WebSocketClose logger.Flag = "web.websocket.close"
+)
+
+const (
+	// HeaderUpgrade is the "Upgrade" header.
+	HeaderUpgrade = "Upgrade"
+	// HeaderOrigin is the "Origin" header.
+	HeaderOrigin = "Origin"
+	// HeaderSecWebSocketKey is the websocket handshake key header.
+	HeaderSecWebSocketKey = "Sec-WebSocket-Key"
+	// HeaderSecWebSocketAccept is the websocket handshake accept header.
+	HeaderSecWebSocketAccept = "Sec-WebSocket-Accept"
+	// HeaderSecWebSocketVersion is the websocket version header.
+	HeaderSecWebSocketVersion = "Sec-WebSocket-Version"
+	// HeaderSecWebSocketProtocol is the websocket subprotocol header.
+	HeaderSecWebSocketProtocol = "Sec-WebSocket-Protocol"
+
+	// webSocketVersion is the only supported websocket version.
+	webSocketVersion = "13"
+	// webSocketAcceptGUID is appended to the handshake key to compute the accept header.
+	webSocketAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// NewWebSocketResult returns a new websocket result with the default limits and timeouts.
+func NewWebSocketResult(handler WebSocketHandler) *WebSocketResult {
+	return &WebSocketResult{
+		Handler:      handler,
+		ReadLimit:    DefaultWebSocketReadLimit,
+		PingInterval: DefaultWebSocketPingInterval,
+		PongTimeout:  DefaultWebSocketPongTimeout,
+		WriteTimeout: DefaultWebSocketWriteTimeout,
+	}
+}
+
+// WebSocketResult is a result that upgrades the request to a websocket and runs a handler on the connection.
+// Upgraded connections are hijacked, and are not drained by `App.Shutdown`; handlers should watch
+// `ctx.App().Latch()` or their own signal if they need to close on shutdown.
+type WebSocketResult struct {
+	Handler WebSocketHandler
+	// Subprotocols are the subprotocols the server supports; the first one offered by the client is used.
+	Subprotocols []string
+	// CheckOrigin validates the request origin; if unset requests with an `Origin` header must match the host.
+	CheckOrigin func(*http.Request) bool
+	// ReadLimit is the maximum size of a message read; zero uses `DefaultWebSocketReadLimit`,
+	// and it is capped at `MaxWebSocketReadLimit`.
+	ReadLimit int64
+	// PingInterval is the interval pings are sent on; zero disables pings.
+	PingInterval time.Duration
+	// PongTimeout is how long reads wait for a frame, including pongs; zero disables the timeout.
+	PongTimeout time.Duration
+	// WriteTimeout is the timeout for writing a frame; zero disables the timeout.
+	WriteTimeout time.Duration
+}
+
+// Render upgrades the connection and runs the handler.
+func (wsr *WebSocketResult) Render(ctx *Ctx) (err error) {
+	if wsr.Handler == nil {
+		err = exception.New(ErrWebSocketHandshake).WithMessage("websocket handler is unset")
+		return
+	}
+	if ctx.tracer != nil {
+		if typed, ok := ctx.tracer.(WebSocketTracer); ok {
+			tf := typed.StartWebSocket(ctx, wsr)
+			defer func() { tf.Finish(ctx, wsr, err) }()
+		}
+	}
+
+	if statusCode, handshakeErr := wsr.checkHandshake(ctx.Request()); handshakeErr != nil {
+		ctx.Response().Header().Set(HeaderSecWebSocketVersion, webSocketVersion)
+		ctx.Response().Header().Set(HeaderContentType, ContentTypeText)
+		ctx.Response().WriteHeader(statusCode)
+		ctx.Response().Write([]byte(http.StatusText(statusCode)))
+		err = handshakeErr
+		return
+	}
+
+	hijacker, ok := ctx.Response().(http.Hijacker)
+	if !ok {
+		hijacker, ok = ctx.Response().InnerResponse().(http.Hijacker)
+	}
+	if !ok {
+		err = exception.New(ErrWebSocketHandshake).WithMessage("response does not support hijacking")
+		return
+	}
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		err = exception.New(err)
+		return
+	}
+	// clear any deadlines set by the server's read and write timeouts.
+	conn.SetDeadline(time.Time{})
+
+	ws := newWebSocket(ctx, conn, buffer.Reader, wsr)
+	ws.subprotocol = wsr.selectSubprotocol(ctx.Request())
+
+	handshake := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		HeaderSecWebSocketAccept + ": " + webSocketAccept(ctx.Request().Header.Get(HeaderSecWebSocketKey)) + "\r\n"
+	if len(ws.subprotocol) > 0 {
+		handshake += HeaderSecWebSocketProtocol + ": " + ws.subprotocol + "\r\n"
+	}
+	if _, err = conn.Write([]byte(handshake + "\r\n")); err != nil {
+		conn.Close()
+		err = exception.New(err)
+		return
+	}
+
+	start := time.Now()
+	if ctx.log != nil {
+		ctx.log.TriggerContext(ctx.Context(), logger.Messagef(WebSocketOpen, "%s %s", ctx.Request().URL.Path, webutil.GetRemoteAddr(ctx.Request())))
+	}
+	if wsr.PingInterval > 0 {
+		go ws.pingLoop()
+	}
+
+	err = wsr.Handler(ws)
+	closeCode := WebSocketCloseNormal
+	if err != nil && !IsWebSocketClosed(err) {
+		closeCode = WebSocketCloseInternalError
+	} else {
+		err = nil
+	}
+	ws.CloseWithReason(closeCode, "")
+
+	if ctx.log != nil {
+		ctx.log.TriggerContext(ctx.Context(), logger.Messagef(WebSocketClose, "%s %s code=%d read=%d written=%d elapsed=%v",
+			ctx.Request().URL.Path, webutil.GetRemoteAddr(ctx.Request()), closeCode, atomic.LoadInt32(&ws.messagesRead), atomic.LoadInt32(&ws.messagesWritten), time.Since(start)))
+	}
+	return
+}
+
+// checkHandshake validates the client handshake, returning the status code to respond with if it is invalid.
+func (wsr *WebSocketResult) checkHandshake(r *http.Request) (int, error) {
+	if r.Method != MethodGet {
+		return http.StatusMethodNotAllowed, exception.New(ErrWebSocketHandshake).WithMessagef("invalid method %s", r.Method)
+	}
+	if !headerContainsToken(r.Header, HeaderConnection, "upgrade") || !headerContainsToken(r.Header, HeaderUpgrade, "websocket") {
+		return http.StatusBadRequest, exception.New(ErrWebSocketHandshake).WithMessage("missing upgrade headers")
+	}
+	if r.Header.Get(HeaderSecWebSocketVersion) != webSocketVersion {
+		return http.StatusBadRequest, exception.New(ErrWebSocketHandshake).WithMessagef("unsupported version %q", r.Header.Get(HeaderSecWebSocketVersion))
+	}
+	if key, err := base64.StdEncoding.DecodeString(r.Header.Get(HeaderSecWebSocketKey)); err != nil || len(key) != 16 {
+		return http.StatusBadRequest, exception.New(ErrWebSocketHandshake).WithMessage("invalid key")
+	}
+	checkOrigin := wsr.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = webSocketSameOrigin
+	}
+	if !checkOrigin(r) {
+		return http.StatusForbidden, exception.New(ErrWebSocketHandshake).WithMessagef("origin not allowed %q", r.Header.Get(HeaderOrigin))
+	}
+	return 0, nil
+}
+
+// selectSubprotocol returns the first subprotocol offered by the client the server supports.
+func (wsr *WebSocketResult) selectSubprotocol(r *http.Request) string {
+	for _, offered := range r.Header[http.CanonicalHeaderKey(HeaderSecWebSocketProtocol)] {
+		for _, subprotocol := range strings.Split(offered, ",") {
+			subprotocol = strings.TrimSpace(subprotocol)
+			for _, supported := range wsr.Subprotocols {
+				if subprotocol == supported {
+					return supported
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// webSocketSameOrigin returns if the request has no origin or the origin matches the host.
+func webSocketSameOrigin(r *http.Request) bool {
+	origin := r.Header.Get(HeaderOrigin)
+	if len(origin) == 0 {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(parsed.Host, r.Host)
+}
+
+func webSocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + webSocketAcceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func headerContainsToken(header http.Header, key, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(key)] {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package web
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/exception"
+)
+
+const testWebSocketKey = "dGhlIHNhbXBsZSBub25jZQ=="
+
+type testWebSocketClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialTestWebSocket(assert *assert.Assertions, app *App, headers ...string) (*testWebSocketClient, *http.Response) {
+	conn, err := net.Dial("tcp", app.Listener().Addr().String())
+	assert.Nil(err)
+	assert.Nil(conn.SetDeadline(time.Now().Add(5 * time.Second)))
+
+	request := "GET /ws HTTP/1.1\r\n" +
+		"Host: " + app.Listener().Addr().String() + "\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Key: " + testWebSocketKey + "\r\n"
+	for _, header := range headers {
+		request += header + "\r\n"
+	}
+	_, err = conn.Write([]byte(request + "\r\n"))
+	assert.Nil(err)
+
+	client := &testWebSocketClient{conn: conn, reader: bufio.NewReader(conn)}
+	res, err := http.ReadResponse(client.reader, nil)
+	assert.Nil(err)
+	return client, res
+}
+
+func (c *testWebSocketClient) writeFrame(fin bool, opcode int, payload []byte) error {
+	first := byte(opcode)
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	switch {
+	case len(payload) <= 125:
+		frame = append(frame, 0x80|byte(len(payload)))
+	default:
+		frame = append(frame, 0x80|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+	mask := []byte{1, 2, 3, 4}
+	frame = append(frame, mask...)
+	for index, b := range payload {
+		frame = append(frame, b^mask[index%4])
+	}
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+func (c *testWebSocketClient) readFrame() (opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.reader, header[:]); err != nil {
+		return
+	}
+	opcode = int(header[0] & 0x0f)
+	length := int(header[1] & 0x7f)
+	if length == 126 {
+		var extended [2]byte
+		if _, err = io.ReadFull(c.reader, extended[:]); err != nil {
+			return
+		}
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload = make([]byte, length)
+	_, err = io.ReadFull(c.reader, payload)
+	return
+}
+
+func startWebSocketApp(action func(*Ctx) Result) *App {
+	app := New().WithBindAddr(DefaultIntegrationBindAddr)
+	app.GET("/ws", action)
+	go app.Start()
+	<-app.NotifyStarted()
+	return app
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	assert := assert.New(t)
+	// from RFC 6455 section 1.3
+	assert.Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", webSocketAccept(testWebSocketKey))
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	assert := assert.New(t)
+
+	handlerErr := make(chan error, 1)
+	app := startWebSocketApp(func(r *Ctx) Result {
+		result := r.WebSocket(func(ws *WebSocket) error {
+			if ws.Subprotocol() != "echo" {
+				return fmt.Errorf("unexpected subprotocol %q", ws.Subprotocol())
+			}
+			err := ws.ReadPump(func(message WebSocketMessage) error {
+				return ws.WriteMessage(message)
+			})
+			handlerErr <- err
+			return err
+		})
+		result.Subprotocols = []string{"echo"}
+		return result
+	})
+	defer app.Shutdown()
+
+	client, res := dialTestWebSocket(assert, app, "Sec-WebSocket-Protocol: chat, echo", "Accept-Encoding: gzip")
+	defer client.conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+	assert.Equal("s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", res.Header.Get(HeaderSecWebSocketAccept))
+	assert.Equal("echo", res.Header.Get(HeaderSecWebSocketProtocol))
+
+	assert.Nil(client.writeFrame(true, WebSocketMessageText, []byte("hello")))
+	opcode, payload, err := client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageText, opcode)
+	assert.Equal("hello", string(payload))
+
+	// fragmented messages are reassembled, and pings are answered in between.
+	assert.Nil(client.writeFrame(false, WebSocketMessageBinary, []byte("foo")))
+	assert.Nil(client.writeFrame(true, WebSocketMessagePing, []byte("ping")))
+	assert.Nil(client.writeFrame(true, WebSocketMessageContinuation, []byte(strings.Repeat("bar", 100))))
+
+	opcode, payload, err = client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessagePong, opcode)
+	assert.Equal("ping", string(payload))
+
+	opcode, payload, err = client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageBinary, opcode)
+	assert.Equal("foo"+strings.Repeat("bar", 100), string(payload))
+
+	assert.Nil(client.writeFrame(true, WebSocketMessageClose, []byte{0x03, 0xe8}))
+	opcode, payload, err = client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseNormal, int(binary.BigEndian.Uint16(payload)))
+	assert.Nil(<-handlerErr)
+}
+
+func TestWebSocketPingAndWritePump(t *testing.T) {
+	assert := assert.New(t)
+
+	app := startWebSocketApp(func(r *Ctx) Result {
+		result := r.WebSocket(func(ws *WebSocket) error {
+			messages := make(chan WebSocketMessage, 1)
+			messages <- WebSocketMessage{Type: WebSocketMessageText, Data: []byte("hello")}
+			go func() {
+				<-time.After(50 * time.Millisecond)
+				close(messages)
+			}()
+			return ws.WritePump(messages)
+		})
+		result.PingInterval = 10 * time.Millisecond
+		return result
+	})
+	defer app.Shutdown()
+
+	client, res := dialTestWebSocket(assert, app)
+	defer client.conn.Close()
+	assert.Equal(http.StatusSwitchingProtocols, res.StatusCode)
+
+	var opcodes []int
+	for {
+		opcode, _, err := client.readFrame()
+		assert.Nil(err)
+		opcodes = append(opcodes, opcode)
+		if opcode == WebSocketMessageClose {
+			break
+		}
+	}
+	assert.Equal(WebSocketMessageText, opcodes[0])
+	assert.Any(opcodes, func(v interface{}) bool { return v.(int) == WebSocketMessagePing })
+}
+
+func TestWebSocketReadLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	handlerErr := make(chan error, 1)
+	app := startWebSocketApp(func(r *Ctx) Result {
+		result := r.WebSocket(func(ws *WebSocket) error {
+			_, err := ws.ReadMessage()
+			handlerErr <- err
+			return err
+		})
+		result.ReadLimit = 8
+		return result
+	})
+	defer app.Shutdown()
+
+	client, _ := dialTestWebSocket(assert, app)
+	defer client.conn.Close()
+
+	assert.Nil(client.writeFrame(false, WebSocketMessageText, []byte("12345")))
+	assert.Nil(client.writeFrame(true, WebSocketMessageContinuation, []byte("67890")))
+	opcode, payload, err := client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseMessageTooBig, int(binary.BigEndian.Uint16(payload)))
+	assert.True(exception.Is(<-handlerErr, ErrWebSocketProtocol))
+}
+
+func TestWebSocketReadLimitDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultWebSocketReadLimit, webSocketReadLimit(0))
+	assert.Equal(8, webSocketReadLimit(8))
+	assert.Equal(MaxWebSocketReadLimit, webSocketReadLimit(1<<40))
+
+	handlerErr := make(chan error, 1)
+	app := startWebSocketApp(func(r *Ctx) Result {
+		return &WebSocketResult{Handler: func(ws *WebSocket) error {
+			_, err := ws.ReadMessage()
+			handlerErr <- err
+			return err
+		}}
+	})
+	defer app.Shutdown()
+
+	client, _ := dialTestWebSocket(assert, app)
+	defer client.conn.Close()
+
+	// a frame that declares a huge length shouldn't be allocated.
+	frame := []byte{0x80 | byte(WebSocketMessageBinary), 0x80 | 127}
+	frame = append(frame, make([]byte, 8)...)
+	binary.BigEndian.PutUint64(frame[2:], 1<<40)
+	_, err := client.conn.Write(append(frame, 1, 2, 3, 4))
+	assert.Nil(err)
+
+	opcode, payload, err := client.readFrame()
+	assert.Nil(err)
+	assert.Equal(WebSocketMessageClose, opcode)
+	assert.Equal(WebSocketCloseMessageTooBig, int(binary.BigEndian.Uint16(payload)))
+	assert.True(exception.Is(<-handlerErr, ErrWebSocketProtocol))
+}
+
+func TestWebSocketHandshakeErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	app := startWebSocketApp(func(r *Ctx) Result {
+		return r.WebSocket(func(ws *WebSocket) error {
+			return nil
+		})
+	})
+	defer app.Shutdown()
+
+	res, err := http.Get("http://" + app.Listener().Addr().String() + "/ws")
+	assert.Nil(err)
+	defer res.Body.Close()
+	assert.Equal(http.StatusBadRequest, res.StatusCode)
+	assert.Equal("13", res.Header.Get(HeaderSecWebSocketVersion))
+
+	client, res := dialTestWebSocket(assert, app, "Origin: https://example.com")
+	defer client.conn.Close()
+	assert.Equal(http.StatusForbidden, res.StatusCode)
+}
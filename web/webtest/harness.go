@@ -0,0 +1,164 @@
+package webtest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+const (
+	// InMemoryURL is the base url of requests to apps served in memory.
+	InMemoryURL = "http://example.com"
+)
+
+// New returns a harness that serves requests with an app in memory, without a listener.
+// Requests go through the app's full handler, including global middleware, logging and tracing.
+func New(t *testing.T, app *web.App) *Harness {
+	baseURL, _ := url.Parse(InMemoryURL)
+	return newHarness(t, app, baseURL)
+}
+
+// NewServer returns a harness that serves an app on an ephemeral port; close the harness when the test is done.
+// Use it to test things that need a real connection, like websockets or streaming responses.
+func NewServer(t *testing.T, app *web.App) *Harness {
+	server := httptest.NewServer(app)
+	baseURL, _ := url.Parse(server.URL)
+	harness := newHarness(t, app, baseURL)
+	harness.server = server
+	harness.client = server.Client()
+	harness.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return harness
+}
+
+func newHarness(t *testing.T, app *web.App, baseURL *url.URL) *Harness {
+	jar, _ := cookiejar.New(nil)
+	return &Harness{
+		assert:  assert.New(t),
+		app:     app,
+		baseURL: baseURL,
+		headers: http.Header{},
+		jar:     jar,
+	}
+}
+
+// Harness sends requests to an app and asserts on the responses.
+/*
+Build requests fluently, then assert on the response:
+
+	func TestGetUser(t *testing.T) {
+		h := webtest.New(t, app).WithHeader("X-Api-Key", testKey)
+
+		var user User
+		h.Get("/users/%d", 1234).Do().
+			AssertStatus(http.StatusOK).
+			AssertHeader(web.HeaderContentType, web.ContentTypeApplicationJSON).
+			JSON(&user)
+
+		h.Post("/users").WithJSON(User{Name: "bailey"}).Do().
+			AssertStatus(http.StatusCreated).
+			AssertJSON(map[string]interface{}{"id": 1235, "name": "bailey"})
+	}
+
+Cookies set by responses are sent with later requests, like a browser, so login flows can be tested end to end.
+Redirects are not followed. A failed assertion fails the test immediately.
+*/
+type Harness struct {
+	assert  *assert.Assertions
+	app     *web.App
+	server  *httptest.Server
+	client  *http.Client
+	baseURL *url.URL
+	headers http.Header
+	jar     *cookiejar.Jar
+}
+
+// App returns the app under test.
+func (h *Harness) App() *web.App {
+	return h.app
+}
+
+// URL returns the base url requests are sent to.
+func (h *Harness) URL() string {
+	return h.baseURL.String()
+}
+
+// WithHeader sets a header sent with every request.
+func (h *Harness) WithHeader(key, value string) *Harness {
+	h.headers.Set(key, value)
+	return h
+}
+
+// Headers returns the headers sent with every request.
+func (h *Harness) Headers() http.Header {
+	return h.headers
+}
+
+// Cookies returns the cookies that will be sent with requests.
+func (h *Harness) Cookies() []*http.Cookie {
+	return h.jar.Cookies(h.baseURL)
+}
+
+// ClearCookies removes the cookies set by previous responses.
+func (h *Harness) ClearCookies() *Harness {
+	h.jar, _ = cookiejar.New(nil)
+	return h
+}
+
+// Close stops the server, if the app is served on a port.
+func (h *Harness) Close() {
+	if h.server != nil {
+		h.server.Close()
+	}
+}
+
+// Get returns a GET request for a path, formatted with the given args.
+func (h *Harness) Get(pathFormat string, args ...interface{}) *Request {
+	return h.Request(http.MethodGet, pathFormat, args...)
+}
+
+// Post returns a POST request for a path, formatted with the given args.
+func (h *Harness) Post(pathFormat string, args ...interface{}) *Request {
+	return h.Request(http.MethodPost, pathFormat, args...)
+}
+
+// Put returns a PUT request for a path, formatted with the given args.
+func (h *Harness) Put(pathFormat string, args ...interface{}) *Request {
+	return h.Request(http.MethodPut, pathFormat, args...)
+}
+
+// Patch returns a PATCH request for a path, formatted with the given args.
+func (h *Harness) Patch(pathFormat string, args ...interface{}) *Request {
+	return h.Request(http.MethodPatch, pathFormat, args...)
+}
+
+// Delete returns a DELETE request for a path, formatted with the given args.
+func (h *Harness) Delete(pathFormat string, args ...interface{}) *Request {
+	return h.Request(http.MethodDelete, pathFormat, args...)
+}
+
+// Request returns a request with a given method for a path, formatted with the given args.
+func (h *Harness) Request(method, pathFormat string, args ...interface{}) *Request {
+	path := pathFormat
+	if len(args) > 0 {
+		path = fmt.Sprintf(pathFormat, args...)
+	}
+	headers := http.Header{}
+	for key, values := range h.headers {
+		headers[key] = append([]string(nil), values...)
+	}
+	return &Request{
+		harness: h,
+		method:  method,
+		path:    path,
+		query:   url.Values{},
+		headers: headers,
+	}
+}
@@ -0,0 +1,91 @@
+package webtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+type testUser struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func testApp() *web.App {
+	app := web.New()
+	app.GET("/users/:id", func(r *web.Ctx) web.Result {
+		id, err := r.ParamInt("id")
+		if err != nil {
+			return r.JSON().BadRequest(err)
+		}
+		return r.JSON().Result(testUser{ID: id, Name: r.Request().URL.Query().Get("name")})
+	})
+	app.POST("/users", func(r *web.Ctx) web.Result {
+		var user testUser
+		if err := r.PostBodyAsJSON(&user); err != nil {
+			return r.JSON().BadRequest(err)
+		}
+		user.ID = 2
+		return r.JSON().Status(http.StatusCreated, user)
+	})
+	app.POST("/login", func(r *web.Ctx) web.Result {
+		username, _ := r.FormValue("username")
+		r.WriteCookie(&http.Cookie{Name: "user", Value: username, Path: "/"})
+		return r.Redirect("/whoami")
+	})
+	app.GET("/whoami", func(r *web.Ctx) web.Result {
+		if cookie := r.GetCookie("user"); cookie != nil {
+			return r.Text().Result("hello " + cookie.Value + " with " + r.Request().Header.Get("X-Api-Key"))
+		}
+		return r.Text().NotAuthorized()
+	})
+	return app
+}
+
+func TestHarness(t *testing.T) {
+	assert := assert.New(t)
+
+	h := New(t, testApp()).WithHeader("X-Api-Key", "test-key")
+	assert.Equal(InMemoryURL, h.URL())
+
+	var user testUser
+	h.Get("/users/%d", 1).WithQuery("name", "bailey").Do().
+		AssertStatus(http.StatusOK).
+		AssertContentType("application/json").
+		AssertJSON(map[string]interface{}{"id": 1, "name": "bailey"}).
+		JSON(&user)
+	assert.Equal(testUser{ID: 1, Name: "bailey"}, user)
+
+	h.Get("/users/bailey").Do().
+		AssertStatus(http.StatusBadRequest).
+		AssertBodyContains("must be an integer")
+
+	h.Post("/users").WithJSON(testUser{Name: "riley"}).Do().
+		AssertStatus(http.StatusCreated).
+		AssertJSON(testUser{ID: 2, Name: "riley"})
+
+	h.Get("/whoami").Do().AssertStatus(http.StatusForbidden)
+	h.Post("/login").WithFormValue("username", "bailey").Do().
+		AssertStatus(http.StatusTemporaryRedirect).
+		AssertHeader("Location", "/whoami").
+		AssertCookie("user", "bailey")
+	h.Get("/whoami").Do().AssertStatus(http.StatusOK).AssertBody("hello bailey with test-key")
+	assert.Len(h.Cookies(), 1)
+
+	h.ClearCookies()
+	h.Get("/whoami").Do().AssertStatus(http.StatusForbidden)
+	h.Get("/whoami").WithCookie(&http.Cookie{Name: "user", Value: "riley"}).Do().AssertBody("hello riley with test-key")
+}
+
+func TestHarnessServer(t *testing.T) {
+	assert := assert.New(t)
+
+	h := NewServer(t, testApp())
+	defer h.Close()
+	assert.NotEqual(InMemoryURL, h.URL())
+
+	h.Post("/login").WithFormValue("username", "bailey").Do().AssertStatus(http.StatusTemporaryRedirect)
+	h.Get("/whoami").Do().AssertStatus(http.StatusOK).AssertBody("hello bailey with ")
+}
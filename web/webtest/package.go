@@ -0,0 +1,3 @@
+// Package webtest provides a harness for testing web apps and controllers, with a fluent request builder
+// and assertions on responses.
+package webtest
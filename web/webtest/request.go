@@ -0,0 +1,133 @@
+package webtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
+	"github.com/blend/go-sdk/web"
+)
+
+// Request is a request being built by a harness.
+type Request struct {
+	harness *Harness
+	method  string
+	path    string
+	query   url.Values
+	headers http.Header
+	cookies []*http.Cookie
+	form    url.Values
+	body    []byte
+}
+
+// WithQuery adds a query string value.
+func (r *Request) WithQuery(key, value string) *Request {
+	r.query.Add(key, value)
+	return r
+}
+
+// WithHeader sets a header.
+func (r *Request) WithHeader(key, value string) *Request {
+	r.headers.Set(key, value)
+	return r
+}
+
+// WithCookie adds a cookie, in addition to any set by previous responses.
+func (r *Request) WithCookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+// WithBasicAuth sets the basic auth header.
+func (r *Request) WithBasicAuth(username, password string) *Request {
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+	return r.WithHeader(web.HeaderAuthorization, req.Header.Get(web.HeaderAuthorization))
+}
+
+// WithBearerToken sets a bearer token in the authorization header.
+func (r *Request) WithBearerToken(token string) *Request {
+	return r.WithHeader(web.HeaderAuthorization, "Bearer "+token)
+}
+
+// WithFormValue adds a form value; the form is sent url encoded as the body.
+func (r *Request) WithFormValue(key, value string) *Request {
+	if r.form == nil {
+		r.form = url.Values{}
+	}
+	r.form.Add(key, value)
+	return r.WithHeader(web.HeaderContentType, web.ContentTypeApplicationFormEncoded)
+}
+
+// WithJSON sets the body to an object serialized as json.
+func (r *Request) WithJSON(object interface{}) *Request {
+	contents, err := json.Marshal(object)
+	r.harness.assert.Nil(err, "webtest: marshalling json body")
+	return r.WithBody(web.ContentTypeApplicationJSON, contents)
+}
+
+// WithBody sets the body and its content type.
+func (r *Request) WithBody(contentType string, body []byte) *Request {
+	r.body = body
+	return r.WithHeader(web.HeaderContentType, contentType)
+}
+
+// URL returns the url the request is sent to.
+func (r *Request) URL() string {
+	requestURL := *r.harness.baseURL
+	parsed, err := url.Parse(r.path)
+	r.harness.assert.Nil(err, "webtest: parsing path", r.path)
+	requestURL.Path = parsed.Path
+	query := parsed.Query()
+	for key, values := range r.query {
+		query[key] = append(query[key], values...)
+	}
+	requestURL.RawQuery = query.Encode()
+	return requestURL.String()
+}
+
+// Do sends the request, failing the test if it can't be sent.
+func (r *Request) Do() *Response {
+	var body io.Reader
+	if r.form != nil {
+		body = bytes.NewBufferString(r.form.Encode())
+	} else if r.body != nil {
+		body = bytes.NewReader(r.body)
+	}
+
+	req, err := http.NewRequest(r.method, r.URL(), body)
+	r.harness.assert.Nil(err, "webtest: creating request")
+	req.Header = r.headers
+	for _, cookie := range r.harness.jar.Cookies(req.URL) {
+		req.AddCookie(cookie)
+	}
+	for _, cookie := range r.cookies {
+		req.AddCookie(cookie)
+	}
+
+	var res *http.Response
+	if r.harness.client != nil {
+		res, err = r.harness.client.Do(req)
+		r.harness.assert.Nil(err, "webtest: sending request", r.method, r.path)
+	} else {
+		req.RemoteAddr = "192.0.2.1:1234"
+		recorder := httptest.NewRecorder()
+		r.harness.app.ServeHTTP(recorder, req)
+		res = recorder.Result()
+	}
+	defer res.Body.Close()
+	contents, err := ioutil.ReadAll(res.Body)
+	r.harness.assert.Nil(err, "webtest: reading response", r.method, r.path)
+
+	r.harness.jar.SetCookies(req.URL, res.Cookies())
+	return &Response{
+		Response: res,
+		Body:     contents,
+		assert:   r.harness.assert,
+		name:     r.method + " " + r.path,
+	}
+}
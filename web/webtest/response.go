@@ -0,0 +1,92 @@
+package webtest
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/web"
+)
+
+// Response is a response from a harness request, with its body read.
+// The assertions fail the test immediately, and return the response so they can be chained.
+type Response struct {
+	*http.Response
+	Body []byte
+
+	assert *assert.Assertions
+	name   string
+}
+
+// String returns the body as a string.
+func (r *Response) String() string {
+	return string(r.Body)
+}
+
+// JSON decodes the body as json into a given object, failing the test if it can't be decoded.
+func (r *Response) JSON(object interface{}) *Response {
+	r.assert.Nil(json.Unmarshal(r.Body, object), r.name, "decoding json body:", r.String())
+	return r
+}
+
+// Cookie returns a cookie set by the response, or nil.
+func (r *Response) Cookie(name string) *http.Cookie {
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+// AssertStatus asserts the response has a given status code.
+func (r *Response) AssertStatus(statusCode int) *Response {
+	r.assert.Equal(statusCode, r.StatusCode, r.name, "status code; body:", r.String())
+	return r
+}
+
+// AssertHeader asserts a header has a given value.
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.assert.Equal(value, r.Header.Get(key), r.name, "header", key)
+	return r
+}
+
+// AssertContentType asserts the response has a given media type, ignoring parameters like the charset.
+func (r *Response) AssertContentType(mediaType string) *Response {
+	actual, _, _ := mime.ParseMediaType(r.Header.Get(web.HeaderContentType))
+	r.assert.Equal(mediaType, actual, r.name, "content type")
+	return r
+}
+
+// AssertCookie asserts the response sets a cookie with a given value.
+func (r *Response) AssertCookie(name, value string) *Response {
+	cookie := r.Cookie(name)
+	r.assert.NotNil(cookie, r.name, "cookie", name, "is not set")
+	r.assert.Equal(value, cookie.Value, r.name, "cookie", name)
+	return r
+}
+
+// AssertBody asserts the body is a given string.
+func (r *Response) AssertBody(body string) *Response {
+	r.assert.Equal(body, r.String(), r.name, "body")
+	return r
+}
+
+// AssertBodyContains asserts the body contains a given string.
+func (r *Response) AssertBodyContains(substring string) *Response {
+	r.assert.Contains(r.String(), substring, r.name, "body")
+	return r
+}
+
+// AssertJSON asserts the body is json equivalent to a given object, e.g. a struct or a map.
+// Both are compared as decoded json, so field order and whitespace don't matter.
+func (r *Response) AssertJSON(expected interface{}) *Response {
+	contents, err := json.Marshal(expected)
+	r.assert.Nil(err, r.name, "marshalling expected json")
+	var expectedValue, actualValue interface{}
+	r.assert.Nil(json.Unmarshal(contents, &expectedValue), r.name, "decoding expected json")
+	r.assert.Nil(json.Unmarshal(r.Body, &actualValue), r.name, "decoding json body:", r.String())
+	r.assert.Equal(expectedValue, actualValue, r.name, "json body")
+	return r
+}
@@ -0,0 +1,26 @@
+package webutil
+
+import "context"
+
+// HeaderXRequestID is the "X-Request-ID" header.
+// It carries an id for a request that is logged by each service that handles it.
+const HeaderXRequestID = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a context with a given request id.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// GetRequestID returns the request id from a context, if one is set.
+// Requests handled by the web package's `RequestID` middleware have it set on the request context.
+func GetRequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if requestID, ok := ctx.Value(requestIDContextKey{}).(string); ok {
+		return requestID
+	}
+	return ""
+}
@@ -0,0 +1,15 @@
+package webutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(GetRequestID(context.Background()))
+	assert.Equal("abc123", GetRequestID(WithRequestID(context.Background(), "abc123")))
+}